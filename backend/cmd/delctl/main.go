@@ -0,0 +1,123 @@
+// Command delctl runs operational tasks against the DelPresence database
+// without going through the HTTP API.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/database"
+	"github.com/joho/godotenv"
+	"gorm.io/gorm/logger"
+)
+
+// command is one delctl subcommand, e.g. "user list"
+type command struct {
+	usage       string
+	description string
+	run         func(args []string) error
+}
+
+// commands maps "<group> <name>" to the subcommand
+var commands = map[string]command{}
+
+// register adds a subcommand
+func register(name, usage, description string, run func(args []string) error) {
+	commands[name] = command{usage: usage, description: description, run: run}
+}
+
+var (
+	// errUsage is returned when a subcommand is called with invalid arguments
+	errUsage = errors.New("invalid usage")
+
+	// errProblemsFound is returned by checks that found problems, after printing them
+	errProblemsFound = errors.New("problems found")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("delctl: ")
+
+	global := flag.NewFlagSet("delctl", flag.ExitOnError)
+	global.BoolVar(&jsonOutput, "json", false, "print results as JSON")
+	verbose := global.Bool("verbose", false, "log SQL statements")
+	global.Usage = printUsage
+	global.Parse(os.Args[1:])
+
+	args := global.Args()
+	if len(args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	name := args[0] + " " + args[1]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using environment variables")
+	}
+
+	// delctl uses the same configuration as the server, from CONFIG_FILE and the environment
+	if _, err := config.Load(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	database.Initialize()
+	defer database.Close()
+	if !*verbose {
+		database.SetLogLevel(logger.Silent)
+	}
+
+	// Only the migrate commands may run against an outdated schema
+	if args[0] != "migrate" {
+		if err := database.CheckSchema(); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+
+	if err := cmd.run(args[2:]); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(os.Stderr, "usage: delctl %s %s\n", name, cmd.usage)
+			os.Exit(2)
+		}
+		database.Close()
+		if errors.Is(err, errProblemsFound) {
+			os.Exit(1)
+		}
+		log.Fatalf("%s: %v", name, err)
+	}
+}
+
+// printUsage lists all subcommands
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: delctl [--json] [--verbose] <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd := commands[name]
+		fmt.Fprintf(os.Stderr, "  %s\n      %s\n", strings.TrimSpace(name+" "+cmd.usage), cmd.description)
+	}
+}
+
+// newFlagSet creates the flags of a subcommand, which also accept --json
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.BoolVar(&jsonOutput, "json", jsonOutput, "print results as JSON")
+	return flags
+}
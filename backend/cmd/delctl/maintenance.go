@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/delpresence/backend/internal/services"
+)
+
+func init() {
+	register("session close-stale", "[--older-than 12h] [--dry-run]", "close attendance sessions left active", sessionCloseStale)
+	register("schedule recompute-enrolled", "[--dry-run]", "recompute the enrolled count of course schedules", scheduleRecomputeEnrolled)
+	register("check integrity", "", "run the data integrity checks, exits with 1 when problems are found", checkIntegrity)
+}
+
+func sessionCloseStale(args []string) error {
+	flags := newFlagSet("session close-stale")
+	olderThan := flags.Duration("older-than", 12*time.Hour, "close sessions that started longer ago than this")
+	dryRun := flags.Bool("dry-run", false, "only list the sessions")
+	if err := flags.Parse(args); err != nil || *olderThan <= 0 {
+		return errUsage
+	}
+
+	sessions, err := services.NewMaintenanceService().CloseStaleSessions(*olderThan, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	return output(sessions, func() {
+		rows := [][]string{{"SESSION", "SCHEDULE", "LECTURER", "STARTED", "OPEN (HOURS)"}}
+		for _, session := range sessions {
+			startTime := session.StartTime
+			rows = append(rows, []string{
+				strconv.FormatUint(uint64(session.ID), 10), strconv.FormatUint(uint64(session.CourseScheduleID), 10),
+				strconv.FormatUint(uint64(session.LecturerID), 10), formatTime(&startTime),
+				strconv.FormatFloat(session.OpenHours, 'f', 1, 64),
+			})
+		}
+		table(rows)
+		fmt.Println(actionSummary(len(sessions), "stale sessions", "closed", *dryRun))
+	})
+}
+
+func scheduleRecomputeEnrolled(args []string) error {
+	flags := newFlagSet("schedule recompute-enrolled")
+	dryRun := flags.Bool("dry-run", false, "only list the schedules whose count is wrong")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	fixes, err := services.NewMaintenanceService().RecomputeEnrolled(*dryRun)
+	if err != nil {
+		return err
+	}
+
+	return output(fixes, func() {
+		rows := [][]string{{"SCHEDULE", "STUDENT GROUP", "STORED", "ACTUAL"}}
+		for _, fix := range fixes {
+			rows = append(rows, []string{
+				strconv.FormatUint(uint64(fix.CourseScheduleID), 10), strconv.FormatUint(uint64(fix.StudentGroupID), 10),
+				strconv.Itoa(fix.Stored), strconv.Itoa(fix.Actual),
+			})
+		}
+		table(rows)
+		fmt.Println(actionSummary(len(fixes), "course schedules", "corrected", *dryRun))
+	})
+}
+
+func checkIntegrity(args []string) error {
+	if err := newFlagSet("check integrity").Parse(args); err != nil {
+		return errUsage
+	}
+
+	issues, err := services.NewMaintenanceService().CheckIntegrity()
+	if err != nil {
+		return err
+	}
+
+	err = output(issues, func() {
+		if len(issues) == 0 {
+			fmt.Println("No integrity problems found")
+			return
+		}
+		for _, issue := range issues {
+			ids := make([]string, 0, len(issue.SampleIDs))
+			for _, id := range issue.SampleIDs {
+				ids = append(ids, strconv.FormatUint(uint64(id), 10))
+			}
+			fmt.Printf("%s: %d %s (e.g. %s)\n", issue.Check, issue.Count, issue.Description, strings.Join(ids, ", "))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(issues) > 0 {
+		return errProblemsFound
+	}
+	return nil
+}
+
+// actionSummary describes how many records were changed, or would be with --dry-run
+func actionSummary(count int, what, action string, dryRun bool) string {
+	if dryRun {
+		return fmt.Sprintf("%d %s would be %s (dry run)", count, what, action)
+	}
+	return fmt.Sprintf("%d %s %s", count, what, action)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/database"
+)
+
+func init() {
+	register("migrate status", "", "list schema migrations and whether they are applied", migrateStatus)
+	register("migrate up", "", "apply all pending migrations", migrateUp)
+	register("migrate down", "[steps]", "revert the last applied migrations (default 1)", migrateDown)
+	register("migrate to", "<version>", "apply or revert migrations until the schema is at version", migrateTo)
+}
+
+func migrateStatus(args []string) error {
+	if err := newFlagSet("migrate status").Parse(args); err != nil {
+		return errUsage
+	}
+	return printMigrations()
+}
+
+func migrateUp(args []string) error {
+	if err := newFlagSet("migrate up").Parse(args); err != nil {
+		return errUsage
+	}
+	if err := database.MigrateUp(); err != nil {
+		return err
+	}
+	return printMigrations()
+}
+
+func migrateDown(args []string) error {
+	flags := newFlagSet("migrate down")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 1 {
+		return errUsage
+	}
+
+	steps := 1
+	if flags.NArg() == 1 {
+		var err error
+		if steps, err = strconv.Atoi(flags.Arg(0)); err != nil {
+			return errUsage
+		}
+	}
+	if err := database.MigrateDown(steps); err != nil {
+		return err
+	}
+	return printMigrations()
+}
+
+func migrateTo(args []string) error {
+	flags := newFlagSet("migrate to")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+	version, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return errUsage
+	}
+
+	if err := database.MigrateTo(version); err != nil {
+		return err
+	}
+	return printMigrations()
+}
+
+// printMigrations prints every known migration and when it was applied
+func printMigrations() error {
+	states, err := database.GetMigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	return output(states, func() {
+		rows := [][]string{{"VERSION", "NAME", "APPLIED"}}
+		for _, state := range states {
+			rows = append(rows, []string{strconv.Itoa(state.Version), state.Name, formatTime(state.AppliedAt)})
+		}
+		table(rows)
+		fmt.Printf("Latest version: %d\n", database.LatestVersion())
+	})
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+)
+
+func init() {
+	register("report attendance", "--academic-year ID [--study-program ID] --out FILE [--format csv|json]",
+		"write the attendance summary per course schedule to a file", reportAttendance)
+}
+
+func reportAttendance(args []string) error {
+	flags := newFlagSet("report attendance")
+	academicYearID := flags.Uint("academic-year", 0, "academic year to report on")
+	studyProgramID := flags.Uint("study-program", 0, "only schedules of this study program")
+	out := flags.String("out", "", "file to write the report to")
+	format := flags.String("format", "", "csv or json, by default taken from the file extension")
+	if err := flags.Parse(args); err != nil || *academicYearID == 0 || *out == "" {
+		return errUsage
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*out)), ".")
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unsupported report format %q, use csv or json", *format)
+	}
+
+	rows, err := services.NewIntegrationService().GetAttendanceReport(uint(*academicYearID), uint(*studyProgramID), nil)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if *format == "json" {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(rows)
+	} else {
+		err = writeAttendanceCSV(file, rows)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	result := map[string]interface{}{"file": *out, "format": *format, "rows": len(rows)}
+	return output(result, func() {
+		fmt.Printf("Wrote %d course schedules to %s\n", len(rows), *out)
+	})
+}
+
+// writeAttendanceCSV writes one line per course schedule with its attendance statistics
+func writeAttendanceCSV(file *os.File, rows []models.AttendanceReportRow) error {
+	w := csv.NewWriter(file)
+	header := []string{
+		"course_schedule_id", "course_code", "course_name", "student_group", "study_program_id", "day",
+		"start_time", "end_time", "total_sessions", "total_students", "total_attendance", "total_late", "total_absent",
+		"total_excused", "average_attendance",
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		stats := row.Statistics
+		record := []string{
+			strconv.FormatUint(uint64(row.CourseScheduleID), 10), row.CourseCode, row.CourseName, row.StudentGroup,
+			strconv.FormatUint(uint64(row.StudyProgramID), 10), row.Day, row.StartTime, row.EndTime,
+			strconv.Itoa(stats.TotalSessions), strconv.Itoa(stats.TotalStudents),
+			strconv.Itoa(stats.TotalAttendance), strconv.Itoa(stats.TotalLate), strconv.Itoa(stats.TotalAbsent),
+			strconv.Itoa(stats.TotalExcused), strconv.Itoa(stats.AverageAttendance),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+)
+
+func init() {
+	register("sync status", "", "show the schedule and latest run of every entity", syncStatus)
+	register("sync run", "<students|lecturers|employees>", "sync an entity from CIS now", syncRun)
+	register("sync runs", "[--entity E] [--status S] [--limit N]", "list recent sync runs", syncRuns)
+	register("sync show", "<run-id>", "show a sync run with its record errors", syncShow)
+	register("sync freshness", "", "show when every entity was last synced", syncFreshness)
+}
+
+func syncStatus(args []string) error {
+	if err := newFlagSet("sync status").Parse(args); err != nil {
+		return errUsage
+	}
+
+	statuses, err := services.GetSyncScheduler().Status()
+	if err != nil {
+		return err
+	}
+
+	return output(statuses, func() {
+		rows := [][]string{{"ENTITY", "SCHEDULE", "LAST RUN", "STATUS", "LAST SUCCESS", "FAILURES"}}
+		for _, status := range statuses {
+			schedule, lastRun, lastStatus := "off", "-", "-"
+			if status.Schedule != "" {
+				schedule = status.Schedule
+			}
+			if status.LastRun != nil {
+				lastRun = formatTime(&status.LastRun.StartedAt)
+				lastStatus = status.LastRun.Status
+			}
+			rows = append(rows, []string{status.Entity, schedule, lastRun, lastStatus, formatTime(status.LastSuccessAt), strconv.Itoa(status.ConsecutiveFailures)})
+		}
+		table(rows)
+	})
+}
+
+func syncRun(args []string) error {
+	flags := newFlagSet("sync run")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	// Runs started from the command line are recorded with the operating system user
+	actor := &services.SyncActor{Username: "delctl"}
+	if current, err := user.Current(); err == nil {
+		actor.Username = "delctl:" + current.Username
+	}
+
+	run, err := services.GetSyncScheduler().Run(flags.Arg(0), models.SyncTriggerManual, actor)
+	if run == nil {
+		return err
+	}
+	if outputErr := output(run, func() { printSyncRun(run) }); outputErr != nil {
+		return outputErr
+	}
+	return err
+}
+
+func syncRuns(args []string) error {
+	flags := newFlagSet("sync runs")
+	entity := flags.String("entity", "", "only runs of this entity")
+	status := flags.String("status", "", "only runs with this status")
+	limit := flags.Int("limit", 20, "maximum number of runs")
+	if err := flags.Parse(args); err != nil || *limit < 1 {
+		return errUsage
+	}
+
+	runs, err := services.GetSyncScheduler().Runs(*entity, *status, *limit)
+	if err != nil {
+		return err
+	}
+
+	return output(runs, func() {
+		rows := [][]string{{"ID", "ENTITY", "TRIGGER", "ACTOR", "STATUS", "STARTED", "DURATION", "RECEIVED", "INSERTED", "UPDATED", "DEACTIVATED", "FAILED"}}
+		for _, run := range runs {
+			actor := run.ActorUsername
+			if actor == "" {
+				actor = "-"
+			}
+			rows = append(rows, []string{
+				strconv.FormatUint(uint64(run.ID), 10), run.Entity, run.Trigger, actor, run.Status,
+				formatTime(&run.StartedAt), fmt.Sprintf("%dms", run.DurationMs),
+				strconv.Itoa(run.RecordCount), strconv.Itoa(run.InsertedCount), strconv.Itoa(run.UpdatedCount),
+				strconv.Itoa(run.DeactivatedCount), strconv.Itoa(run.FailedCount),
+			})
+		}
+		table(rows)
+	})
+}
+
+func syncShow(args []string) error {
+	flags := newFlagSet("sync show")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+	id, err := strconv.ParseUint(flags.Arg(0), 10, 32)
+	if err != nil {
+		return errUsage
+	}
+
+	run, err := services.GetSyncScheduler().GetRun(uint(id))
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("sync run %d not found", id)
+	}
+
+	return output(run, func() { printSyncRun(run) })
+}
+
+func syncFreshness(args []string) error {
+	if err := newFlagSet("sync freshness").Parse(args); err != nil {
+		return errUsage
+	}
+
+	freshness, err := services.GetSyncScheduler().Freshness()
+	if err != nil {
+		return err
+	}
+
+	return output(freshness, func() {
+		rows := [][]string{{"ENTITY", "LAST SUCCESS", "AGE (HOURS)", "STALE"}}
+		for _, entry := range freshness {
+			age := "-"
+			if entry.AgeHours != nil {
+				age = strconv.FormatFloat(*entry.AgeHours, 'f', 1, 64)
+			}
+			rows = append(rows, []string{entry.Entity, formatTime(entry.LastSuccessAt), age, strconv.FormatBool(entry.Stale)})
+		}
+		table(rows)
+	})
+}
+
+// printSyncRun prints the details and record errors of a run
+func printSyncRun(run *models.SyncRun) {
+	fmt.Printf("Run %d: %s %s sync, %s\n", run.ID, run.Trigger, run.Entity, run.Status)
+	fmt.Printf("Started %s, finished %s (%dms)\n", formatTime(&run.StartedAt), formatTime(run.FinishedAt), run.DurationMs)
+	fmt.Printf("Received %d, inserted %d, updated %d, deactivated %d, failed %d\n",
+		run.RecordCount, run.InsertedCount, run.UpdatedCount, run.DeactivatedCount, run.FailedCount)
+	if run.Error != "" {
+		fmt.Fprintf(os.Stdout, "Error: %s\n", run.Error)
+	}
+	for _, recordErr := range run.Errors {
+		fmt.Printf("  %s %s: %s\n", recordErr.RecordKey, recordErr.Label, recordErr.Message)
+	}
+}
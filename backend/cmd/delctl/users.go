@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+func init() {
+	register("user list", "[--role ROLE]", "list users", userList)
+	register("user create", "--username NAME [--role Admin] [--password-stdin]", "create a local user, e.g. an admin", userCreate)
+	register("user set-password", "--username NAME [--password-stdin]", "set the password of a local user", userSetPassword)
+	register("user delete", "--username NAME", "delete a user", userDelete)
+}
+
+func userList(args []string) error {
+	flags := newFlagSet("user list")
+	role := flags.String("role", "", "only list users with this role")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	users, err := repositories.NewUserRepository().FindAll(*role)
+	if err != nil {
+		return err
+	}
+
+	return output(users, func() {
+		rows := [][]string{{"ID", "USERNAME", "ROLE", "EXTERNAL ID", "CREATED"}}
+		for _, user := range users {
+			externalID := "-"
+			if user.ExternalUserID != nil {
+				externalID = strconv.Itoa(*user.ExternalUserID)
+			}
+			createdAt := user.CreatedAt
+			rows = append(rows, []string{strconv.FormatUint(uint64(user.ID), 10), user.Username, user.Role, externalID, formatTime(&createdAt)})
+		}
+		table(rows)
+	})
+}
+
+func userCreate(args []string) error {
+	flags := newFlagSet("user create")
+	username := flags.String("username", "", "username of the new user")
+	role := flags.String("role", "Admin", "role of the new user")
+	passwordStdin := flags.Bool("password-stdin", false, "read the password from standard input")
+	if err := flags.Parse(args); err != nil || *username == "" {
+		return errUsage
+	}
+
+	repo := repositories.NewUserRepository()
+	existing, err := repo.FindByUsername(*username)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("user %q already exists", *username)
+	}
+
+	password, err := readPassword(*passwordStdin)
+	if err != nil {
+		return err
+	}
+
+	// The password is hashed by the BeforeSave hook
+	user := models.User{Username: *username, Password: password, Role: *role}
+	if err := repo.Create(&user); err != nil {
+		return err
+	}
+
+	return output(user, func() {
+		fmt.Printf("Created %s user %s (ID %d)\n", user.Role, user.Username, user.ID)
+	})
+}
+
+func userSetPassword(args []string) error {
+	flags := newFlagSet("user set-password")
+	username := flags.String("username", "", "username of the user")
+	passwordStdin := flags.Bool("password-stdin", false, "read the password from standard input")
+	if err := flags.Parse(args); err != nil || *username == "" {
+		return errUsage
+	}
+
+	repo := repositories.NewUserRepository()
+	user, err := repo.FindByUsername(*username)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("user %q not found", *username)
+	}
+	if user.ExternalUserID != nil {
+		return fmt.Errorf("user %q is a campus user, its password is managed by CIS", *username)
+	}
+
+	password, err := readPassword(*passwordStdin)
+	if err != nil {
+		return err
+	}
+
+	user.Password = password
+	if err := repo.Update(user); err != nil {
+		return err
+	}
+
+	return output(user, func() {
+		fmt.Printf("Password of %s updated\n", user.Username)
+	})
+}
+
+func userDelete(args []string) error {
+	flags := newFlagSet("user delete")
+	username := flags.String("username", "", "username of the user")
+	if err := flags.Parse(args); err != nil || *username == "" {
+		return errUsage
+	}
+
+	repo := repositories.NewUserRepository()
+	user, err := repo.FindByUsername(*username)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("user %q not found", *username)
+	}
+	if err := repo.Delete(user.ID); err != nil {
+		return err
+	}
+
+	return output(user, func() {
+		fmt.Printf("Deleted user %s (ID %d)\n", user.Username, user.ID)
+	})
+}
+
+// readPassword reads a password from standard input, or from DELCTL_PASSWORD when it is set,
+// so passwords never appear in the process list
+func readPassword(fromStdin bool) (string, error) {
+	password := os.Getenv("DELCTL_PASSWORD")
+	if fromStdin {
+		if !jsonOutput {
+			fmt.Fprint(os.Stderr, "Password: ")
+		}
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	if password == "" {
+		return "", fmt.Errorf("no password given, use --password-stdin or set DELCTL_PASSWORD")
+	}
+	if len(password) < 8 {
+		return "", fmt.Errorf("password must be at least 8 characters")
+	}
+	return password, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/database"
+)
+
+const migrateUsage = `usage: delpresence-server migrate <command>
+
+commands:
+  up            apply all pending migrations
+  down [steps]  revert the last applied migration, or the given number of migrations
+  status        list migrations and whether they are applied
+  to <version>  apply or revert migrations until the schema is at version`
+
+// runMigrate runs the migrate subcommand with the arguments following "migrate"
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n%s", migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		if err := database.MigrateUp(); err != nil {
+			return err
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		if err := database.MigrateDown(steps); err != nil {
+			return err
+		}
+	case "to":
+		if len(args) < 2 {
+			return fmt.Errorf("missing version\n%s", migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err := database.MigrateTo(version); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], migrateUsage)
+	}
+
+	return printMigrationStatus()
+}
+
+// printMigrationStatus prints every known migration and when it was applied
+func printMigrationStatus() error {
+	states, err := database.GetMigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	current := 0
+	for _, state := range states {
+		applied := "pending"
+		if state.Applied {
+			applied = "applied " + state.AppliedAt.Format("2006-01-02 15:04:05")
+			current = state.Version
+		}
+		fmt.Printf("%4d  %-45s %s\n", state.Version, state.Name, applied)
+	}
+	slog.Info("Schema version", "current", current, "latest", database.LatestVersion())
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+const openapiUsage = `usage: delpresence-server openapi [command]
+
+commands:
+  write <file>  write the OpenAPI document to file, the log goes to stdout
+  check         fail when the registered routes and the document differ, also run by go test`
+
+// runOpenAPI runs the openapi subcommand with the arguments following "openapi"
+// It needs no database, so it can run in CI and in the image build
+func runOpenAPI(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing openapi command\n%s", openapiUsage)
+	}
+
+	switch args[0] {
+	case "write":
+		if len(args) < 2 {
+			return fmt.Errorf("missing file\n%s", openapiUsage)
+		}
+		document, err := json.MarshalIndent(openapi.Get().Document, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(args[1], append(document, '\n'), 0o644)
+	case "check":
+		// Without the route listing of debug mode
+		gin.SetMode(gin.ReleaseMode)
+		routes := newRouter(cfg).Routes()
+		if problems := openapi.Get().Check(routes); len(problems) > 0 {
+			return fmt.Errorf("routes and OpenAPI document differ:\n  %s", strings.Join(problems, "\n  "))
+		}
+		fmt.Printf("%d routes match the OpenAPI document\n", len(routes))
+		return nil
+	default:
+		return fmt.Errorf("unknown openapi command %q\n%s", args[0], openapiUsage)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// TestRoutesMatchOpenAPIDocument fails when a route is registered without being documented,
+// or documented without being registered
+func TestRoutesMatchOpenAPIDocument(t *testing.T) {
+	cfg, err := config.Load()
+	if err != nil {
+		t.Logf("config: %v", err)
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	routes := newRouter(cfg).Routes()
+	if len(routes) == 0 {
+		t.Fatal("router has no routes")
+	}
+	if problems := openapi.Get().Check(routes); len(problems) > 0 {
+		t.Errorf("routes and OpenAPI document differ:\n  %s", strings.Join(problems, "\n  "))
+	}
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"github.com/delpresence/backend/internal/apierror"
+	"github.com/delpresence/backend/internal/auth/campus"
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/handlers"
+	"github.com/delpresence/backend/internal/metrics"
+	"github.com/delpresence/backend/internal/middleware"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// newRouter registers the middleware and routes of the API
+// Handlers are created without touching the database, so the router can be built by "openapi check"
+func newRouter(cfg *config.Config) *gin.Engine {
+	// Create a new Gin router, requests are logged by the access log middleware and timed for /metrics
+	// Panics and unknown routes are answered with the error envelope
+	router := gin.New()
+	router.NoRoute(apierror.NoRoute)
+	router.Use(gin.CustomRecovery(apierror.Recovered), middleware.RequestIDMiddleware(), middleware.AccessLogMiddleware(), middleware.MetricsMiddleware())
+
+	// Configure CORS
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOrigins = cfg.Server.CORSAllowedOrigins
+	corsConfig.AllowCredentials = true
+	corsConfig.AllowHeaders = append(corsConfig.AllowHeaders, "Authorization", "Content-Type", "X-API-Key", middleware.RequestIDHeader, middleware.IdempotencyKeyHeader)
+	corsConfig.ExposeHeaders = append(corsConfig.ExposeHeaders, middleware.RequestIDHeader, middleware.IdempotentReplayedHeader)
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	router.Use(cors.New(corsConfig))
+
+	// Reject requests that do not match the OpenAPI document before they reach the handlers
+	router.Use(middleware.RequestValidationMiddleware())
+
+	// Liveness and readiness probes
+	healthHandler := handlers.NewHealthHandler()
+	router.GET("/healthz", healthHandler.Healthz)
+	router.GET("/readyz", healthHandler.Readyz)
+
+	// Prometheus metrics, protected by METRICS_TOKEN when it is set
+	router.GET("/metrics", middleware.MetricsTokenMiddleware(cfg.Server.MetricsToken), gin.WrapH(metrics.Handler()))
+
+	// Register authentication routes
+	router.POST("/api/auth/login", handlers.Login)
+	router.POST("/api/auth/refresh", handlers.RefreshToken)
+
+	// Register the second login step for accounts with two-factor authentication
+	router.POST("/api/auth/login/2fa", handlers.LoginTwoFactor)
+	router.POST("/api/auth/login/2fa/setup", handlers.LoginTwoFactorSetup)
+	router.POST("/api/auth/login/2fa/enable", handlers.LoginTwoFactorEnable)
+
+	// Register campus authentication route (works for all role types)
+	router.POST("/api/auth/campus/login", handlers.CampusLogin)
+
+	// Create handlers
+	campusAuthHandler := handlers.NewCampusAuthHandler()
+	lecturerHandler := handlers.NewLecturerHandler()
+	studentHandler := handlers.NewStudentHandler()
+	employeeHandler := handlers.NewEmployeeHandler()
+	facultyHandler := handlers.NewFacultyHandler()
+	studyProgramHandler := handlers.NewStudyProgramHandler()
+	buildingHandler := handlers.NewBuildingHandler()
+	roomHandler := handlers.NewRoomHandler()
+	academicYearHandler := handlers.NewAcademicYearHandler()
+	courseHandler := handlers.NewCourseHandler()
+	studentGroupHandler := handlers.NewStudentGroupHandler()
+	lecturerAssignmentHandler := handlers.NewLecturerAssignmentHandler()
+	teachingAssistantAssignmentHandler := handlers.NewTeachingAssistantAssignmentHandler()
+	courseScheduleHandler := handlers.NewCourseScheduleHandler()
+	scheduleConflictHandler := handlers.NewScheduleConflictHandler()
+	scheduleSlotHandler := handlers.NewScheduleSlotHandler()
+	lecturerAvailabilityHandler := handlers.NewLecturerAvailabilityHandler()
+	timetableHandler := handlers.NewTimetableHandler()
+	attendanceHandler := handlers.NewAttendanceHandler()
+	loginLockoutHandler := handlers.NewLoginLockoutHandler()
+	auditLogHandler := handlers.NewAuditLogHandler()
+	apiKeyHandler := handlers.NewAPIKeyHandler()
+	integrationHandler := handlers.NewIntegrationHandler()
+	syncHandler := handlers.NewSyncHandler()
+	configHandler := handlers.NewConfigHandler()
+	calendarFeedHandler := handlers.NewCalendarFeedHandler()
+	holidayHandler := handlers.NewHolidayHandler()
+	scheduleExceptionHandler := handlers.NewScheduleExceptionHandler()
+
+	// Integration routes accept an API key or an admin bearer token
+	integrationRoutes := router.Group("/api/integrations")
+	integrationRoutes.Use(middleware.APIKeyMiddleware(campus.CampusAuthMiddleware()))
+	{
+		integrationRoutes.GET("/rooms/:room_id/active-session", middleware.PermissionMiddleware(models.APIKeyPermissionKioskQR), integrationHandler.GetRoomActiveSession)
+		integrationRoutes.GET("/rooms/:room_id/schedules", middleware.PermissionMiddleware(models.APIKeyPermissionSchedulesRead), integrationHandler.GetRoomSchedules)
+		integrationRoutes.GET("/reports/attendance", middleware.PermissionMiddleware(models.APIKeyPermissionReportsRead), integrationHandler.GetAttendanceReport)
+	}
+
+	// iCalendar feeds are authenticated by the token in their URL, calendar apps cannot send headers
+	router.GET("/api/calendar/:token", calendarFeedHandler.GetFeed)
+
+	// Protected routes
+	authRequired := router.Group("/api")
+	authRequired.Use(campus.CampusAuthMiddleware(), middleware.ImpersonationMiddleware())
+	{
+		// Current user
+		authRequired.GET("/auth/me", handlers.GetCurrentUser)
+
+		// Admin routes
+		adminRoutes := authRequired.Group("/admin")
+		adminRoutes.Use(middleware.RoleMiddleware("Admin"))
+		{
+			// Campus API token management (admin only)
+			adminRoutes.GET("/campus/token", campusAuthHandler.GetToken)
+			adminRoutes.POST("/campus/token/refresh", campusAuthHandler.RefreshToken)
+
+			// Login lockout management (admin only)
+			adminRoutes.GET("/auth/lockouts", loginLockoutHandler.GetLockedAccounts)
+			adminRoutes.POST("/auth/lockouts/unlock", loginLockoutHandler.UnlockAccount)
+
+			// Two-factor authentication for the admin account
+			adminRoutes.GET("/2fa", handlers.GetTwoFactorStatus)
+			adminRoutes.POST("/2fa/setup", handlers.SetupTwoFactor)
+			adminRoutes.POST("/2fa/enable", handlers.EnableTwoFactor)
+			adminRoutes.POST("/2fa/disable", handlers.DisableTwoFactor)
+			adminRoutes.POST("/2fa/recovery-codes", handlers.RegenerateRecoveryCodes)
+
+			// Audit log of security-relevant events (offline logins, impersonation, ...)
+			adminRoutes.GET("/audit-logs", auditLogHandler.GetAuditLogs)
+
+			// Impersonation ("view as user") for support staff
+			adminRoutes.POST("/impersonate", handlers.StartImpersonation)
+
+			// API keys for classroom kiosks and service integrations
+			adminRoutes.GET("/api-keys", apiKeyHandler.GetAllAPIKeys)
+			adminRoutes.GET("/api-keys/:id", apiKeyHandler.GetAPIKeyByID)
+			adminRoutes.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+			adminRoutes.POST("/api-keys/:id/rotate", apiKeyHandler.RotateAPIKey)
+			adminRoutes.DELETE("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
+
+			// Effective configuration, with secrets redacted
+			adminRoutes.GET("/config", configHandler.GetConfig)
+
+			// Schedule and latest run of the campus syncs
+			adminRoutes.GET("/sync/status", syncHandler.GetSyncStatus)
+			adminRoutes.GET("/sync/freshness", syncHandler.GetSyncFreshness)
+
+			// History of sync runs with their per-record errors
+			adminRoutes.GET("/sync/runs", syncHandler.GetSyncRuns)
+			adminRoutes.GET("/sync/runs/:id", syncHandler.GetSyncRunByID)
+
+			// Dry-run syncs that are reviewed before they are applied
+			adminRoutes.POST("/sync/:entity/preview", syncHandler.PreviewSync)
+			adminRoutes.GET("/sync/previews/:id", syncHandler.GetSyncPreview)
+			adminRoutes.POST("/sync/previews/:id/apply", syncHandler.ApplySyncPreview)
+
+			// Admin access to lecturer data
+			adminRoutes.GET("/lecturers", lecturerHandler.GetAllLecturers)
+			adminRoutes.GET("/lecturers/search", lecturerHandler.SearchLecturers)
+			adminRoutes.GET("/lecturers/:id", lecturerHandler.GetLecturerByID)
+			adminRoutes.POST("/lecturers/sync", lecturerHandler.SyncLecturers)
+
+			// Admin access to employee data (replacing assistant lecturer)
+			adminRoutes.GET("/employees", employeeHandler.GetAllEmployees)
+			adminRoutes.GET("/employees/:id", employeeHandler.GetEmployeeByID)
+			adminRoutes.POST("/employees/sync", employeeHandler.SyncEmployees)
+
+			// Admin access to student data
+			adminRoutes.GET("/students", studentHandler.GetAllStudents)
+			adminRoutes.GET("/students/:id", studentHandler.GetStudentByID)
+			adminRoutes.GET("/students/by-user-id/:user_id", studentHandler.GetStudentByUserID)
+			adminRoutes.POST("/students/sync", studentHandler.SyncStudents)
+
+			// Admin access to faculty data
+			adminRoutes.GET("/faculties", facultyHandler.GetAllFaculties)
+			adminRoutes.GET("/faculties/:id", facultyHandler.GetFacultyByID)
+			adminRoutes.POST("/faculties", facultyHandler.CreateFaculty)
+			adminRoutes.PUT("/faculties/:id", facultyHandler.UpdateFaculty)
+			adminRoutes.DELETE("/faculties/:id", facultyHandler.DeleteFaculty)
+
+			// Admin access to study program data
+			adminRoutes.GET("/study-programs", studyProgramHandler.GetAllStudyPrograms)
+			adminRoutes.GET("/study-programs/:id", studyProgramHandler.GetStudyProgramByID)
+			adminRoutes.POST("/study-programs", studyProgramHandler.CreateStudyProgram)
+			adminRoutes.PUT("/study-programs/:id", studyProgramHandler.UpdateStudyProgram)
+			adminRoutes.DELETE("/study-programs/:id", studyProgramHandler.DeleteStudyProgram)
+
+			// Admin access to building data
+			adminRoutes.GET("/buildings", buildingHandler.GetAllBuildings)
+			adminRoutes.GET("/buildings/:id", buildingHandler.GetBuildingByID)
+			adminRoutes.POST("/buildings", buildingHandler.CreateBuilding)
+			adminRoutes.PUT("/buildings/:id", buildingHandler.UpdateBuilding)
+			adminRoutes.DELETE("/buildings/:id", buildingHandler.DeleteBuilding)
+
+			// Admin access to room data
+			adminRoutes.GET("/rooms", roomHandler.GetAllRooms)
+			adminRoutes.GET("/rooms/:id", roomHandler.GetRoomByID)
+			adminRoutes.POST("/rooms", roomHandler.CreateRoom)
+			adminRoutes.PUT("/rooms/:id", roomHandler.UpdateRoom)
+			adminRoutes.DELETE("/rooms/:id", roomHandler.DeleteRoom)
+
+			// Calendar feeds of rooms, such as for building managers
+			adminRoutes.GET("/rooms/:id/calendar-feeds", calendarFeedHandler.GetRoomFeeds)
+			adminRoutes.POST("/rooms/:id/calendar-feeds", calendarFeedHandler.CreateRoomFeed)
+			adminRoutes.DELETE("/calendar-feeds/:id", calendarFeedHandler.RevokeFeed)
+
+			// Admin access to academic year data
+			adminRoutes.GET("/academic-years", academicYearHandler.GetAllAcademicYears)
+			adminRoutes.GET("/academic-years/:id", academicYearHandler.GetAcademicYearByID)
+			adminRoutes.POST("/academic-years", academicYearHandler.CreateAcademicYear)
+			adminRoutes.PUT("/academic-years/:id", academicYearHandler.UpdateAcademicYear)
+			adminRoutes.DELETE("/academic-years/:id", academicYearHandler.DeleteAcademicYear)
+
+			// Admin access to course data
+			adminRoutes.GET("/courses", courseHandler.GetAllCourses)
+			adminRoutes.GET("/courses/:id", courseHandler.GetCourseByID)
+			adminRoutes.POST("/courses", courseHandler.CreateCourse)
+			adminRoutes.PUT("/courses/:id", courseHandler.UpdateCourse)
+			adminRoutes.DELETE("/courses/:id", courseHandler.DeleteCourse)
+
+			// Admin access to student group data
+			adminRoutes.GET("/student-groups", studentGroupHandler.GetAllStudentGroups)
+			adminRoutes.GET("/student-groups/:id", studentGroupHandler.GetStudentGroupByID)
+			adminRoutes.POST("/student-groups", studentGroupHandler.CreateStudentGroup)
+			adminRoutes.PUT("/student-groups/:id", studentGroupHandler.UpdateStudentGroup)
+			adminRoutes.DELETE("/student-groups/:id", studentGroupHandler.DeleteStudentGroup)
+			adminRoutes.GET("/student-groups/:id/members", studentGroupHandler.GetGroupMembers)
+			adminRoutes.GET("/student-groups/:id/available-students", studentGroupHandler.GetAvailableStudents)
+			adminRoutes.POST("/student-groups/:id/members", studentGroupHandler.AddStudentToGroup)
+			adminRoutes.POST("/student-groups/:id/members/batch", studentGroupHandler.AddMultipleStudentsToGroup)
+			adminRoutes.DELETE("/student-groups/:id/members/:student_id", studentGroupHandler.RemoveStudentFromGroup)
+			adminRoutes.POST("/student-groups/:id/members/remove-batch", studentGroupHandler.RemoveMultipleStudentsFromGroup)
+
+			// Admin access to course schedules
+			adminRoutes.GET("/schedules", courseScheduleHandler.GetAllSchedules)
+			adminRoutes.GET("/schedules/:id", courseScheduleHandler.GetScheduleByID)
+			adminRoutes.POST("/schedules", courseScheduleHandler.CreateSchedule)
+			adminRoutes.PUT("/schedules/:id", courseScheduleHandler.UpdateSchedule)
+			adminRoutes.DELETE("/schedules/:id", courseScheduleHandler.DeleteSchedule)
+
+			// Room, lecturer and student group conflicts between the schedules of an academic year
+			adminRoutes.GET("/schedules/conflicts", scheduleConflictHandler.GetConflictReport)
+			adminRoutes.PUT("/schedules/conflicts/status", scheduleConflictHandler.SetConflictStatus)
+			adminRoutes.POST("/schedules/conflicts/check", scheduleConflictHandler.CheckScheduleConflicts)
+
+			// Free slots and rooms for a new schedule
+			adminRoutes.POST("/schedules/free-slots", scheduleSlotHandler.FindFreeSlots)
+
+			// Windows in which lecturers cannot or would rather not teach
+			adminRoutes.GET("/lecturer-availability", lecturerAvailabilityHandler.ListAvailability)
+			adminRoutes.POST("/lecturer-availability", lecturerAvailabilityHandler.CreateAvailability)
+			adminRoutes.DELETE("/lecturer-availability/:id", lecturerAvailabilityHandler.DeleteAvailability)
+
+			// Holidays and cancelled or rescheduled classes, applied to calendar feeds
+			adminRoutes.GET("/holidays", holidayHandler.GetAllHolidays)
+			adminRoutes.POST("/holidays", holidayHandler.CreateHoliday)
+			adminRoutes.DELETE("/holidays/:id", holidayHandler.DeleteHoliday)
+			adminRoutes.GET("/schedules/:id/exceptions", scheduleExceptionHandler.GetScheduleExceptions)
+			adminRoutes.POST("/schedules/:id/exceptions", scheduleExceptionHandler.SaveScheduleException)
+			adminRoutes.DELETE("/schedules/exceptions/:id", scheduleExceptionHandler.DeleteScheduleException)
+
+			// Generated timetable drafts, reviewed and then committed as course schedules
+			adminRoutes.POST("/timetable/drafts", timetableHandler.GenerateDraft)
+			adminRoutes.GET("/timetable/drafts", timetableHandler.ListDrafts)
+			adminRoutes.GET("/timetable/drafts/:id", timetableHandler.GetDraft)
+			adminRoutes.POST("/timetable/drafts/:id/commit", timetableHandler.CommitDraft)
+			adminRoutes.DELETE("/timetable/drafts/:id", timetableHandler.DeleteDraft)
+
+			// Admin access to lecturer assignments
+			adminRoutes.GET("/courses/assignments", lecturerAssignmentHandler.GetAllLecturerAssignments)
+			adminRoutes.GET("/courses/assignments/:id", lecturerAssignmentHandler.GetLecturerAssignmentByID)
+			adminRoutes.POST("/courses/assignments", lecturerAssignmentHandler.CreateLecturerAssignment)
+			adminRoutes.PUT("/courses/assignments/:id", lecturerAssignmentHandler.UpdateLecturerAssignment)
+			adminRoutes.DELETE("/courses/assignments/:id", lecturerAssignmentHandler.DeleteLecturerAssignment)
+			adminRoutes.GET("/courses/:id/lecturers", lecturerAssignmentHandler.GetAssignmentsByCourse)
+			adminRoutes.GET("/lecturers/:id/courses", lecturerAssignmentHandler.GetAssignmentsByLecturer)
+			adminRoutes.GET("/courses/:id/available-lecturers", lecturerAssignmentHandler.GetAvailableLecturers)
+
+			// Admin access to teaching assistant assignments
+			adminRoutes.GET("/courses/ta-assignments", teachingAssistantAssignmentHandler.GetAllTeachingAssistantAssignments)
+			adminRoutes.GET("/courses/ta-assignments/:id", teachingAssistantAssignmentHandler.GetTeachingAssistantAssignmentByID)
+			adminRoutes.POST("/courses/ta-assignments", teachingAssistantAssignmentHandler.CreateTeachingAssistantAssignment)
+			adminRoutes.DELETE("/courses/ta-assignments/:id", teachingAssistantAssignmentHandler.DeleteTeachingAssistantAssignment)
+			adminRoutes.GET("/courses/:id/teaching-assistants", teachingAssistantAssignmentHandler.GetAssignmentsByCourse)
+			adminRoutes.GET("/employees/:id/assigned-courses", teachingAssistantAssignmentHandler.GetAssignmentsByTeachingAssistant)
+			adminRoutes.GET("/courses/:id/available-teaching-assistants", teachingAssistantAssignmentHandler.GetAvailableTeachingAssistants)
+
+			// New endpoint to get lecturer for a course - use a more specific path to avoid conflict
+			adminRoutes.GET("/course-lecturers/course/:course_id", courseScheduleHandler.GetLecturerForCourse)
+		}
+
+		// Lecturer routes - add lecturer-specific endpoints
+		lecturerRoutes := authRequired.Group("/lecturer")
+		lecturerRoutes.Use(middleware.RoleMiddleware("Dosen", "dosen"))
+		{
+			// Get lecturer's own assignments
+			lecturerRoutes.GET("/assignments", lecturerAssignmentHandler.GetMyAssignments)
+
+			// Get lecturer's course schedules
+			lecturerRoutes.GET("/schedules", courseScheduleHandler.GetMySchedules)
+
+			// Calendar feed URL of the lecturer's schedules
+			lecturerRoutes.POST("/calendar-feed", calendarFeedHandler.CreateLecturerFeed)
+			lecturerRoutes.DELETE("/calendar-feed", calendarFeedHandler.RevokeLecturerFeed)
+
+			// Get lecturer's courses (alias for assignments, more intuitive API endpoint)
+			lecturerRoutes.GET("/courses", lecturerAssignmentHandler.GetMyAssignments)
+
+			// Get academic years (needed for filtering courses and schedules)
+			lecturerRoutes.GET("/academic-years", academicYearHandler.GetAllAcademicYears)
+
+			// Attendance management routes for lecturers
+			lecturerRoutes.POST("/attendance/sessions", attendanceHandler.CreateAttendanceSession)
+			lecturerRoutes.GET("/attendance/sessions/active", attendanceHandler.GetActiveAttendanceSessions)
+			lecturerRoutes.GET("/attendance/sessions", attendanceHandler.GetAttendanceSessions)
+			lecturerRoutes.GET("/attendance/sessions/:id", attendanceHandler.GetAttendanceSessionDetails)
+			lecturerRoutes.PUT("/attendance/sessions/:id/close", attendanceHandler.CloseAttendanceSession)
+			lecturerRoutes.PUT("/attendance/sessions/:id/cancel", attendanceHandler.CancelAttendanceSession)
+			lecturerRoutes.GET("/attendance/sessions/:id/students", attendanceHandler.GetStudentAttendances)
+			lecturerRoutes.PUT("/attendance/sessions/:id/students/:studentId", attendanceHandler.MarkStudentAttendance)
+			lecturerRoutes.GET("/attendance/statistics/course/:courseScheduleId", attendanceHandler.GetAttendanceStatistics)
+			lecturerRoutes.GET("/attendance/qrcode/:id", attendanceHandler.GetQRCode)
+			lecturerRoutes.GET("/attendance/sessions/:id/report", attendanceHandler.DownloadAttendanceReport)
+
+			// Teaching assistant management endpoints for lecturers
+			lecturerRoutes.GET("/ta-assignments", teachingAssistantAssignmentHandler.GetMyTeachingAssistantAssignments)
+			lecturerRoutes.POST("/ta-assignments", teachingAssistantAssignmentHandler.CreateTeachingAssistantAssignment)
+			lecturerRoutes.DELETE("/ta-assignments/:id", teachingAssistantAssignmentHandler.DeleteTeachingAssistantAssignment)
+			lecturerRoutes.GET("/courses/:id/available-teaching-assistants", teachingAssistantAssignmentHandler.GetAvailableTeachingAssistants)
+		}
+
+		// Employee routes (replacing assistant routes)
+		employeeRoutes := authRequired.Group("/employee")
+		employeeRoutes.Use(middleware.RoleMiddleware("Pegawai"))
+		{
+			// Employee routes go here
+			// Teaching assistant can view their assigned courses
+			employeeRoutes.GET("/assigned-courses", teachingAssistantAssignmentHandler.GetAssignmentsByTeachingAssistant)
+
+			// Other employee-specific routes can be added here
+		}
+
+		// Assistant routes
+		assistantRoutes := authRequired.Group("/assistant")
+		assistantRoutes.Use(middleware.RoleMiddleware("Asisten Dosen", "asisten dosen"))
+		{
+			// Assistant can view their assigned schedules
+			assistantRoutes.GET("/schedules", teachingAssistantAssignmentHandler.GetMyAssignedSchedules)
+
+			// Get academic years (needed for filtering courses and schedules)
+			assistantRoutes.GET("/academic-years", academicYearHandler.GetAllAcademicYears)
+
+			// Register teaching assistant attendance handler
+			teachingAssistantAttendanceHandler := handlers.NewTeachingAssistantAttendanceHandler()
+
+			// Attendance management routes for assistants - full capabilities like lecturers
+			assistantRoutes.POST("/attendance/sessions", teachingAssistantAttendanceHandler.CreateAttendanceSession)
+			assistantRoutes.GET("/attendance/sessions/active", teachingAssistantAttendanceHandler.GetActiveAttendanceSessions)
+			assistantRoutes.GET("/attendance/sessions", teachingAssistantAttendanceHandler.GetAttendanceSessions)
+			assistantRoutes.GET("/attendance/sessions/:id", teachingAssistantAttendanceHandler.GetAttendanceSessionDetails)
+			assistantRoutes.PUT("/attendance/sessions/:id/close", teachingAssistantAttendanceHandler.CloseAttendanceSession)
+			assistantRoutes.GET("/attendance/sessions/:id/students", teachingAssistantAttendanceHandler.GetStudentAttendances)
+			assistantRoutes.PUT("/attendance/sessions/:id/students/:studentId", teachingAssistantAttendanceHandler.MarkStudentAttendance)
+			assistantRoutes.GET("/attendance/qrcode/:id", teachingAssistantAttendanceHandler.GetQRCode)
+			assistantRoutes.GET("/attendance/sessions/:id/report", teachingAssistantAttendanceHandler.DownloadAttendanceReport)
+		}
+
+		// Student routes
+		studentRoutes := authRequired.Group("/student")
+		studentRoutes.Use(middleware.RoleMiddleware("Mahasiswa"))
+		{
+			// Student routes go here
+			studentRoutes.GET("/schedules", courseScheduleHandler.GetStudentSchedules)
+			studentRoutes.GET("/academic-years", academicYearHandler.GetAllAcademicYears)
+
+			// Calendar feed URL of the student's schedules
+			studentRoutes.POST("/calendar-feed", calendarFeedHandler.CreateStudentFeed)
+			studentRoutes.DELETE("/calendar-feed", calendarFeedHandler.RevokeStudentFeed)
+
+			// Add new endpoint for student courses
+			studentCourseHandler := handlers.NewStudentCourseHandler()
+			studentRoutes.GET("/courses", studentCourseHandler.GetStudentCourses)
+
+			// Add new endpoint for students to check active attendance sessions
+			studentAttendanceHandler := handlers.NewStudentAttendanceHandler()
+			studentRoutes.GET("/attendance/active-sessions", studentAttendanceHandler.GetActiveAttendanceSessions)
+
+			// Add new endpoint for QR code attendance submission, retries with the same Idempotency-Key get the first response
+			studentRoutes.POST("/attendance/qr-submit", middleware.IdempotencyMiddleware(), studentAttendanceHandler.SubmitQRAttendance)
+
+			// Add new endpoint for attendance history
+			studentRoutes.GET("/attendance/history", studentAttendanceHandler.GetAttendanceHistory)
+		}
+	}
+
+	// Add public endpoints
+	router.GET("/api/students/by-user-id/:user_id", studentHandler.GetStudentByUserID)
+
+	// The OpenAPI document of these routes
+	router.GET("/api/openapi.json", handlers.NewOpenAPIHandler().GetDocument)
+
+	return router
+}
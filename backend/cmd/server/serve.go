@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/services"
+)
+
+// serve runs the HTTP server until SIGINT or SIGTERM and then shuts down gracefully:
+// the readiness probe fails for the drain delay so load balancers stop sending requests,
+// in-flight requests such as QR submissions are allowed to finish, the background workers
+// are stopped and the database is closed
+func serve(handler http.Handler, cfg config.ServerConfig, stopWorkers context.CancelFunc) error {
+	port := cfg.Port
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+	slog.Info("Server running", "port", port)
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-signals.Done():
+	}
+	// A second signal kills the process without waiting
+	stopSignals()
+
+	// Keep serving while the failing readiness probe takes the instance out of the load balancer
+	slog.Info("Shutting down, failing readiness probe", "drain_delay", cfg.DrainDelay.String())
+	services.GetHealthService().StartDraining()
+	time.Sleep(cfg.DrainDelay)
+
+	slog.Info("Draining connections", "timeout", cfg.ShutdownTimeout.String())
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelShutdown()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error draining connections", "error", err)
+	}
+	if err := <-serverErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Server stopped with an error", "error", err)
+	}
+
+	// A sync that is still running when the timeout expires is marked as interrupted on the next start
+	stopWorkers()
+	workerCtx, cancelWorkers := context.WithTimeout(context.Background(), cfg.WorkerStopTimeout)
+	defer cancelWorkers()
+	if err := services.GetSyncScheduler().Wait(workerCtx); err != nil {
+		slog.Warn("Scheduled sync still running at shutdown", "error", err)
+	}
+
+	database.Close()
+	slog.Info("Server stopped")
+	return nil
+}
@@ -0,0 +1,138 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// internalDetail is the detail of 5xx errors that wrap an internal error
+const internalDetail = "the error was logged on the server"
+
+// Error is an error returned to API clients
+// Clients switch on Code, Detail is a specific explanation in English for developers and logs
+type Error struct {
+	Code   Code
+	Detail string
+	// Extra holds additional fields of the response, such as retry_after
+	Extra map[string]interface{}
+	Err   error
+}
+
+// New creates an error with a code and a detail
+func New(code Code, detail string) *Error {
+	return &Error{Code: code, Detail: detail}
+}
+
+// Newf creates an error with a code and a formatted detail
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Wrap gives err a code, the detail is the text of err
+// Respond hides the text of errors with a 5xx code from clients
+func Wrap(code Code, err error) *Error {
+	return &Error{Code: code, Detail: err.Error(), Err: err}
+}
+
+// With adds a field to the response of the error
+func (e *Error) With(key string, value interface{}) *Error {
+	if e.Extra == nil {
+		e.Extra = make(map[string]interface{})
+	}
+	e.Extra[key] = value
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return string(e.Code)
+	}
+	return string(e.Code) + ": " + e.Detail
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Status returns the HTTP status of the error
+func (e *Error) Status() int {
+	return e.Code.Status()
+}
+
+// Respond writes err in the error envelope and aborts the request:
+//
+//	{"status": "error", "code": "not_enrolled", "message": "<localized>", "detail": "<specific, English>"}
+//
+// The message is in the language of the Accept-Language header, errors without a code are internal errors.
+// Wrapped errors with a 5xx status are logged and their text, which may hold SQL or driver messages,
+// is not sent to the client.
+func Respond(c *gin.Context, err error) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		apiErr = Wrap(CodeInternal, err)
+	}
+
+	detail := apiErr.Detail
+	if apiErr.Err != nil && apiErr.Status() >= http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "Request failed", "code", apiErr.Code, "error", apiErr.Err)
+		detail = internalDetail
+		if requestID := c.GetString("requestID"); requestID != "" {
+			detail += ", request ID " + requestID
+		}
+	}
+
+	lang := Language(c.GetHeader("Accept-Language"))
+	body := gin.H{
+		"status":  "error",
+		"code":    apiErr.Code,
+		"message": apiErr.Code.Message(lang),
+	}
+	if detail != "" {
+		body["detail"] = detail
+	}
+	for key, value := range apiErr.Extra {
+		body[key] = value
+	}
+
+	c.Header("Content-Language", lang)
+	c.AbortWithStatusJSON(apiErr.Status(), body)
+}
+
+// NoRoute answers requests to unknown routes with the error envelope
+func NoRoute(c *gin.Context) {
+	Respond(c, Newf(CodeNotFound, "no route for %s %s", c.Request.Method, c.Request.URL.Path))
+}
+
+// Recovered answers requests whose handler panicked, for gin.CustomRecovery
+func Recovered(c *gin.Context, recovered any) {
+	Respond(c, New(CodeInternal, ""))
+}
+
+// CodeForStatus returns the generic code of an HTTP status
+func CodeForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusBadGateway:
+		return CodeUpstreamError
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeUpstreamTimeout
+	}
+	return CodeInternal
+}
@@ -0,0 +1,48 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respond runs Respond for err and returns the status and the decoded body
+func respond(t *testing.T, err error) (int, map[string]interface{}) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	Respond(c, err)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	return rec.Code, body
+}
+
+func TestRespondHidesInternalErrorText(t *testing.T) {
+	dbErr := errors.New(`pq: relation "attendances" does not exist`)
+	for _, err := range []error{dbErr, Wrap(CodeInternal, dbErr)} {
+		status, body := respond(t, err)
+		if status != http.StatusInternalServerError {
+			t.Errorf("got status %d, want %d", status, http.StatusInternalServerError)
+		}
+		if detail, _ := body["detail"].(string); strings.Contains(detail, "attendances") {
+			t.Errorf("detail %q exposes the internal error", detail)
+		}
+	}
+}
+
+func TestRespondKeepsClientErrorText(t *testing.T) {
+	_, body := respond(t, Wrap(CodeInvalidRequest, errors.New("start_time must be before end_time")))
+	if body["detail"] != "start_time must be before end_time" {
+		t.Errorf("got detail %v, want the text of the error", body["detail"])
+	}
+}
@@ -0,0 +1,224 @@
+package apierror
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Code is the stable, machine-readable code of an API error
+// Codes are part of the API, clients switch on them instead of the message
+type Code string
+
+// Generic codes, used when there is no more specific one
+const (
+	CodeInvalidRequest  Code = "invalid_request"
+	CodeUnauthorized    Code = "unauthorized"
+	CodeForbidden       Code = "forbidden"
+	CodeNotFound        Code = "not_found"
+	CodeConflict        Code = "conflict"
+	CodeTooManyRequests Code = "too_many_requests"
+	CodeInternal        Code = "internal_error"
+	CodeUpstreamError   Code = "upstream_error"
+	CodeUnavailable     Code = "service_unavailable"
+	CodeUpstreamTimeout Code = "upstream_timeout"
+)
+
+// Authentication codes
+const (
+	CodeInvalidCredentials      Code = "invalid_credentials"
+	CodeInvalidToken            Code = "invalid_token"
+	CodeUserNotFound            Code = "user_not_found"
+	CodeAccountLocked           Code = "account_locked"
+	CodeAccountInactive         Code = "account_inactive"
+	CodeTooManyAttempts         Code = "too_many_attempts"
+	CodeInvalidTwoFactorCode    Code = "invalid_two_factor_code"
+	CodeTwoFactorRequired       Code = "two_factor_required"
+	CodeTwoFactorNotEnabled     Code = "two_factor_not_enabled"
+	CodeTwoFactorAlreadyEnabled Code = "two_factor_already_enabled"
+	CodeTwoFactorNotStarted     Code = "two_factor_not_started"
+	CodeCampusAuthFailed        Code = "campus_auth_failed"
+	CodeCampusUnavailable       Code = "campus_unavailable"
+	CodeImpersonationNotAllowed Code = "impersonation_not_allowed"
+	CodeImpersonationReadOnly   Code = "impersonation_read_only"
+	CodeInvalidAPIKey           Code = "invalid_api_key"
+	CodeInsufficientPermissions Code = "insufficient_permissions"
+)
+
+// Attendance codes
+const (
+	CodeSessionNotFound          Code = "session_not_found"
+	CodeSessionNotActive         Code = "session_not_active"
+	CodeNotEnrolled              Code = "not_enrolled"
+	CodeInvalidQRCode            Code = "invalid_qr_code"
+	CodeQRScheduleMismatch       Code = "qr_schedule_mismatch"
+	CodeVerificationNotSupported Code = "verification_not_supported"
+	CodeStudentNotFound          Code = "student_not_found"
+	CodeLecturerNotFound         Code = "lecturer_not_found"
+)
+
+// Idempotency codes, for requests sent with an Idempotency-Key header
+const (
+	CodeIdempotencyKeyReused     Code = "idempotency_key_reused"
+	CodeIdempotencyKeyInProgress Code = "idempotency_key_in_progress"
+)
+
+// Academic data codes
+const (
+	CodeAcademicYearNotFound    Code = "academic_year_not_found"
+	CodeAcademicYearInUse       Code = "academic_year_in_use"
+	CodeFacultyNotFound         Code = "faculty_not_found"
+	CodeFacultyCodeExists       Code = "faculty_code_exists"
+	CodeFacultyHasStudyPrograms Code = "faculty_has_study_programs"
+	CodeStudyProgramNotFound    Code = "study_program_not_found"
+	CodeStudyProgramCodeExists  Code = "study_program_code_exists"
+	CodeBuildingNotFound        Code = "building_not_found"
+	CodeBuildingCodeExists      Code = "building_code_exists"
+	CodeBuildingHasRooms        Code = "building_has_rooms"
+	CodeRoomNotFound            Code = "room_not_found"
+	CodeRoomCodeExists          Code = "room_code_exists"
+	CodeCourseNotFound          Code = "course_not_found"
+	CodeInvalidListQuery        Code = "invalid_list_query"
+)
+
+// Scheduling codes
+const (
+	CodeScheduleConflictNotFound     Code = "schedule_conflict_not_found"
+	CodeInvalidScheduleSlot          Code = "invalid_schedule_slot"
+	CodeInvalidSlotSearch            Code = "invalid_slot_search"
+	CodeLecturerAvailabilityNotFound Code = "lecturer_availability_not_found"
+	CodeInvalidLecturerAvailability  Code = "invalid_lecturer_availability"
+	CodeTimetableDraftNotFound       Code = "timetable_draft_not_found"
+	CodeTimetableDraftCommitted      Code = "timetable_draft_committed"
+	CodeTimetableDraftStale          Code = "timetable_draft_stale"
+	CodeInvalidTimetableRequest      Code = "invalid_timetable_request"
+	CodeHolidayNotFound              Code = "holiday_not_found"
+	CodeInvalidHoliday               Code = "invalid_holiday"
+	CodeScheduleExceptionNotFound    Code = "schedule_exception_not_found"
+	CodeInvalidScheduleException     Code = "invalid_schedule_exception"
+	CodeScheduleExceptionConflict    Code = "schedule_exception_conflict"
+	CodeCalendarFeedNotFound         Code = "calendar_feed_not_found"
+)
+
+// Campus sync codes
+const (
+	CodeSyncInProgress      Code = "sync_in_progress"
+	CodeSyncBlocked         Code = "sync_blocked"
+	CodeUnknownSyncEntity   Code = "unknown_sync_entity"
+	CodeSyncPreviewNotFound Code = "sync_preview_not_found"
+)
+
+// entry is the HTTP status and the messages of a code
+type entry struct {
+	status int
+	en     string
+	id     string
+}
+
+var catalog = map[Code]entry{
+	CodeInvalidRequest:  {http.StatusBadRequest, "The request is invalid.", "Permintaan tidak valid."},
+	CodeUnauthorized:    {http.StatusUnauthorized, "You need to sign in.", "Anda perlu masuk terlebih dahulu."},
+	CodeForbidden:       {http.StatusForbidden, "You don't have permission to do this.", "Anda tidak memiliki izin untuk melakukan ini."},
+	CodeNotFound:        {http.StatusNotFound, "The requested data was not found.", "Data yang diminta tidak ditemukan."},
+	CodeConflict:        {http.StatusConflict, "The request conflicts with existing data.", "Permintaan bertentangan dengan data yang sudah ada."},
+	CodeTooManyRequests: {http.StatusTooManyRequests, "Too many requests, please try again later.", "Terlalu banyak permintaan, silakan coba lagi nanti."},
+	CodeInternal:        {http.StatusInternalServerError, "Something went wrong on our side.", "Terjadi kesalahan pada server."},
+	CodeUpstreamError:   {http.StatusBadGateway, "The campus system returned an error.", "Sistem kampus mengembalikan kesalahan."},
+	CodeUnavailable:     {http.StatusServiceUnavailable, "The service is temporarily unavailable.", "Layanan sedang tidak tersedia."},
+	CodeUpstreamTimeout: {http.StatusGatewayTimeout, "The campus system did not respond in time.", "Sistem kampus tidak merespons tepat waktu."},
+
+	CodeInvalidCredentials:      {http.StatusUnauthorized, "Invalid username or password.", "Nama pengguna atau kata sandi salah."},
+	CodeInvalidToken:            {http.StatusUnauthorized, "Your session is invalid or has expired, please sign in again.", "Sesi Anda tidak valid atau telah berakhir, silakan masuk kembali."},
+	CodeUserNotFound:            {http.StatusNotFound, "User not found.", "Pengguna tidak ditemukan."},
+	CodeAccountLocked:           {http.StatusTooManyRequests, "Your account is temporarily locked after repeated failed logins.", "Akun Anda dikunci sementara karena gagal masuk berulang kali."},
+	CodeAccountInactive:         {http.StatusForbidden, "Your account is no longer active.", "Akun Anda sudah tidak aktif."},
+	CodeTooManyAttempts:         {http.StatusTooManyRequests, "Too many login attempts, please try again later.", "Terlalu banyak percobaan masuk, silakan coba lagi nanti."},
+	CodeInvalidTwoFactorCode:    {http.StatusUnauthorized, "The two-factor code is invalid.", "Kode verifikasi dua langkah tidak valid."},
+	CodeTwoFactorRequired:       {http.StatusForbidden, "Two-factor authentication is required for your role.", "Verifikasi dua langkah wajib untuk peran Anda."},
+	CodeTwoFactorNotEnabled:     {http.StatusBadRequest, "Two-factor authentication is not enabled.", "Verifikasi dua langkah belum diaktifkan."},
+	CodeTwoFactorAlreadyEnabled: {http.StatusConflict, "Two-factor authentication is already enabled.", "Verifikasi dua langkah sudah aktif."},
+	CodeTwoFactorNotStarted:     {http.StatusBadRequest, "Two-factor setup has not been started.", "Pengaturan verifikasi dua langkah belum dimulai."},
+	CodeCampusAuthFailed:        {http.StatusUnauthorized, "Campus authentication failed.", "Autentikasi kampus gagal."},
+	CodeCampusUnavailable:       {http.StatusServiceUnavailable, "Campus authentication is unavailable, please try again later.", "Autentikasi kampus sedang tidak tersedia, silakan coba lagi nanti."},
+	CodeImpersonationNotAllowed: {http.StatusForbidden, "This user cannot be impersonated.", "Pengguna ini tidak dapat ditiru."},
+	CodeImpersonationReadOnly:   {http.StatusForbidden, "This session is read-only.", "Sesi ini hanya dapat dibaca."},
+	CodeInvalidAPIKey:           {http.StatusUnauthorized, "The API key is invalid or has expired.", "Kunci API tidak valid atau telah kedaluwarsa."},
+	CodeInsufficientPermissions: {http.StatusForbidden, "The API key does not grant this permission.", "Kunci API tidak memiliki izin ini."},
+
+	CodeSessionNotFound:          {http.StatusNotFound, "Attendance session not found.", "Sesi presensi tidak ditemukan."},
+	CodeSessionNotActive:         {http.StatusConflict, "The attendance session is not active.", "Sesi presensi tidak aktif."},
+	CodeNotEnrolled:              {http.StatusForbidden, "You are not enrolled in this course.", "Anda tidak terdaftar di mata kuliah ini."},
+	CodeInvalidQRCode:            {http.StatusBadRequest, "The QR code is invalid for this session.", "QR code tidak valid untuk sesi ini."},
+	CodeQRScheduleMismatch:       {http.StatusBadRequest, "The QR code does not match the selected schedule.", "QR code tidak sesuai dengan jadwal yang dipilih."},
+	CodeVerificationNotSupported: {http.StatusBadRequest, "This attendance session does not support this verification method.", "Sesi presensi ini tidak mendukung metode verifikasi tersebut."},
+	CodeStudentNotFound:          {http.StatusNotFound, "Student record not found.", "Data mahasiswa tidak ditemukan."},
+	CodeLecturerNotFound:         {http.StatusNotFound, "Lecturer record not found.", "Data dosen tidak ditemukan."},
+
+	CodeIdempotencyKeyReused:     {http.StatusUnprocessableEntity, "This request key was already used for a different request.", "Kunci permintaan ini sudah digunakan untuk permintaan lain."},
+	CodeIdempotencyKeyInProgress: {http.StatusConflict, "The request is still being processed, please try again shortly.", "Permintaan masih diproses, silakan coba lagi sebentar lagi."},
+
+	CodeAcademicYearNotFound:    {http.StatusNotFound, "Academic year not found.", "Tahun akademik tidak ditemukan."},
+	CodeAcademicYearInUse:       {http.StatusConflict, "An academic year that is in use cannot be deleted.", "Tahun akademik yang sedang digunakan tidak dapat dihapus."},
+	CodeFacultyNotFound:         {http.StatusNotFound, "Faculty not found.", "Fakultas tidak ditemukan."},
+	CodeFacultyCodeExists:       {http.StatusConflict, "A faculty with this code already exists.", "Fakultas dengan kode ini sudah ada."},
+	CodeFacultyHasStudyPrograms: {http.StatusConflict, "A faculty with study programs cannot be deleted.", "Tidak dapat menghapus fakultas yang memiliki program studi."},
+	CodeStudyProgramNotFound:    {http.StatusNotFound, "Study program not found.", "Program studi tidak ditemukan."},
+	CodeStudyProgramCodeExists:  {http.StatusConflict, "A study program with this code already exists.", "Program studi dengan kode ini sudah ada."},
+	CodeBuildingNotFound:        {http.StatusNotFound, "Building not found.", "Gedung tidak ditemukan."},
+	CodeBuildingCodeExists:      {http.StatusConflict, "This building code is already in use.", "Kode gedung sudah digunakan."},
+	CodeBuildingHasRooms:        {http.StatusConflict, "A building with rooms cannot be deleted.", "Tidak dapat menghapus gedung yang memiliki ruangan."},
+	CodeRoomNotFound:            {http.StatusNotFound, "Room not found.", "Ruangan tidak ditemukan."},
+	CodeRoomCodeExists:          {http.StatusConflict, "This room code is already in use.", "Kode ruangan sudah digunakan."},
+	CodeCourseNotFound:          {http.StatusNotFound, "Course not found.", "Mata kuliah tidak ditemukan."},
+	CodeInvalidListQuery:        {http.StatusBadRequest, "The pagination, filter or sort parameters are invalid.", "Parameter halaman, filter atau urutan tidak valid."},
+
+	CodeScheduleConflictNotFound:     {http.StatusNotFound, "These schedules do not conflict.", "Jadwal-jadwal ini tidak bentrok."},
+	CodeInvalidScheduleSlot:          {http.StatusBadRequest, "The schedule times must be HH:MM and end after they start.", "Waktu jadwal harus berformat HH:MM dan berakhir setelah dimulai."},
+	CodeInvalidSlotSearch:            {http.StatusBadRequest, "The free slot search constraints are invalid.", "Batasan pencarian slot kosong tidak valid."},
+	CodeLecturerAvailabilityNotFound: {http.StatusNotFound, "Lecturer availability not found.", "Ketersediaan dosen tidak ditemukan."},
+	CodeInvalidLecturerAvailability:  {http.StatusBadRequest, "The availability needs a known day and HH:MM times that end after they start.", "Ketersediaan memerlukan hari yang dikenal dan waktu HH:MM yang berakhir setelah dimulai."},
+	CodeTimetableDraftNotFound:       {http.StatusNotFound, "Timetable draft not found.", "Draf jadwal tidak ditemukan."},
+	CodeTimetableDraftCommitted:      {http.StatusConflict, "This timetable draft is already committed.", "Draf jadwal ini sudah diterapkan."},
+	CodeTimetableDraftStale:          {http.StatusConflict, "The timetable draft conflicts with schedules added since it was generated, generate a new draft.", "Draf jadwal bentrok dengan jadwal yang ditambahkan setelah draf dibuat, buat draf baru."},
+	CodeInvalidTimetableRequest:      {http.StatusBadRequest, "The timetable constraints are invalid.", "Batasan penyusunan jadwal tidak valid."},
+	CodeHolidayNotFound:              {http.StatusNotFound, "Holiday not found.", "Hari libur tidak ditemukan."},
+	CodeInvalidHoliday:               {http.StatusBadRequest, "The holiday needs a name and YYYY-MM-DD dates that do not end before they start.", "Hari libur memerlukan nama dan tanggal YYYY-MM-DD yang tidak berakhir sebelum dimulai."},
+	CodeScheduleExceptionNotFound:    {http.StatusNotFound, "Schedule exception not found.", "Perubahan jadwal tidak ditemukan."},
+	CodeInvalidScheduleException:     {http.StatusBadRequest, "The class cannot be cancelled or moved on this date.", "Kelas tidak dapat dibatalkan atau dipindahkan pada tanggal ini."},
+	CodeScheduleExceptionConflict:    {http.StatusConflict, "The replacement session conflicts with another class.", "Kuliah pengganti bentrok dengan kelas lain."},
+	CodeCalendarFeedNotFound:         {http.StatusNotFound, "Calendar feed not found.", "Kalender tidak ditemukan."},
+
+	CodeSyncInProgress:      {http.StatusConflict, "A sync of this data is already running.", "Sinkronisasi data ini sedang berjalan."},
+	CodeSyncBlocked:         {http.StatusConflict, "The sync was blocked by its safety checks.", "Sinkronisasi diblokir oleh pemeriksaan keamanan."},
+	CodeUnknownSyncEntity:   {http.StatusNotFound, "This data cannot be synced.", "Data ini tidak dapat disinkronkan."},
+	CodeSyncPreviewNotFound: {http.StatusNotFound, "Sync preview not found or expired.", "Pratinjau sinkronisasi tidak ditemukan atau sudah kedaluwarsa."},
+}
+
+// Status returns the HTTP status of the code, 500 for unknown codes
+func (c Code) Status() int {
+	if e, ok := catalog[c]; ok {
+		return e.status
+	}
+	return http.StatusInternalServerError
+}
+
+// Message returns the human message of the code in a language returned by Language
+func (c Code) Message(lang string) string {
+	e, ok := catalog[c]
+	if !ok {
+		e = catalog[CodeInternal]
+	}
+	if lang == LangIndonesian {
+		return e.id
+	}
+	return e.en
+}
+
+// Codes returns all codes, sorted
+func Codes() []Code {
+	codes := make([]Code, 0, len(catalog))
+	for code := range catalog {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
@@ -0,0 +1,48 @@
+package apierror
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Languages of error messages
+const (
+	LangEnglish    = "en"
+	LangIndonesian = "id"
+)
+
+// DefaultLanguage is used when the client accepts none of the supported languages
+const DefaultLanguage = LangEnglish
+
+// Language picks the supported language the client prefers from an Accept-Language header,
+// such as "id-ID,id;q=0.9,en;q=0.8"
+func Language(acceptLanguage string) string {
+	best, bestQ := DefaultLanguage, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		// Only the primary subtag matters, "in" is the old code of Indonesian
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		var lang string
+		switch primary {
+		case "id", "in":
+			lang = LangIndonesian
+		case "en":
+			lang = LangEnglish
+		default:
+			continue
+		}
+		if q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+// ErrAccountInactive is returned when a campus user left CIS and was deactivated by the sync
+var ErrAccountInactive = errors.New("account is no longer active")
+
+// ensureCampusAccountActive refuses campus users whose student, lecturer and employee records
+// have all been deactivated by the sync. Users without any synced record are allowed, so new
+// accounts can log in before the next sync picks them up.
+func ensureCampusAccountActive(externalUserID int) error {
+	found, active := 0, 0
+
+	student, err := repositories.NewStudentRepository().FindByUserID(externalUserID)
+	if err != nil {
+		return err
+	}
+	if student != nil {
+		found++
+		if student.LifecycleStatus != models.LifecycleInactive {
+			active++
+		}
+	}
+
+	lecturer, err := repositories.NewLecturerRepository().GetByUserID(externalUserID)
+	if err != nil {
+		return err
+	}
+	if lecturer.ID != 0 {
+		found++
+		if lecturer.LifecycleStatus != models.LifecycleInactive {
+			active++
+		}
+	}
+
+	employee, err := repositories.NewEmployeeRepository().FindByUserID(externalUserID)
+	if err != nil {
+		return err
+	}
+	if employee != nil {
+		found++
+		if employee.LifecycleStatus != models.LifecycleInactive {
+			active++
+		}
+	}
+
+	if found > 0 && active == 0 {
+		// Offline logins must not keep working for accounts that left
+		if err := campusCredentialRepository().DeleteByExternalUserID(externalUserID); err != nil {
+			slog.Error("Error deleting campus credential of inactive user", "external_user_id", externalUserID, "error", err)
+		}
+		return ErrAccountInactive
+	}
+	return nil
+}
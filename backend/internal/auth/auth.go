@@ -3,10 +3,10 @@ package auth
 import (
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"time"
 
+	"github.com/delpresence/backend/internal/config"
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/repositories"
 	"github.com/dgrijalva/jwt-go"
@@ -29,6 +29,10 @@ var UserRepository *repositories.UserRepository
 // Initialize initializes the auth service
 func Initialize() {
 	UserRepository = repositories.NewUserRepository()
+	TwoFactorRepository = repositories.NewTwoFactorRepository()
+	CampusCredentialRepository = repositories.NewCampusCredentialRepository()
+	AuditLogRepository = repositories.NewAuditLogRepository()
+	initializeLimiter()
 }
 
 // Claims represents the JWT claims
@@ -36,13 +40,30 @@ type Claims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	Purpose  string `json:"purpose,omitempty"` // Set on short-lived challenge tokens, empty on access and refresh tokens
+	Offline  bool   `json:"offline,omitempty"` // Set on tokens issued by an offline campus login, which cannot be refreshed
+
+	// Impersonation claims, set when an admin acts as another user
+	ImpersonatorID       uint   `json:"impersonator_id,omitempty"`
+	ImpersonatorUsername string `json:"impersonator_username,omitempty"`
+	ImpersonatorRole     string `json:"impersonator_role,omitempty"`
+	ReadOnly             bool   `json:"read_only,omitempty"`
 	jwt.StandardClaims
 }
 
+// IsImpersonation reports whether the claims belong to an impersonation token
+func (c *Claims) IsImpersonation() bool {
+	return c.ImpersonatorID != 0
+}
+
+// signingKey returns the key that signs and verifies tokens, JWT_SECRET of the configuration
+func signingKey() []byte {
+	return []byte(config.Get().Auth.JWTSecret)
+}
+
 // GenerateTokens generates a JWT token and refresh token
 func GenerateTokens(user models.User) (string, string, error) {
-	// Get JWT secret key from environment
-	jwtKey := []byte(os.Getenv("JWT_SECRET"))
+	jwtKey := signingKey()
 
 	// Create token expiration time (12 hours)
 	tokenExpirationTime := time.Now().Add(12 * time.Hour)
@@ -88,22 +109,40 @@ func GenerateTokens(user models.User) (string, string, error) {
 }
 
 // ValidateToken validates a JWT token
+// Challenge tokens issued during the second login step are rejected
 func ValidateToken(tokenString string) (*Claims, error) {
-	// Get JWT secret key from environment
-	jwtKey := []byte(os.Getenv("JWT_SECRET"))
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
 
+// IsSignedToken reports whether a token carries a valid signature of the JWT secret,
+// whatever its expiry and purpose. Such tokens were issued by this server and must never
+// be read as campus tokens when ValidateToken rejects them.
+func IsSignedToken(tokenString string) bool {
+	parser := jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.ParseWithClaims(tokenString, &Claims{}, keyFunc)
+	return err == nil && token.Valid
+}
+
+// keyFunc returns the JWT secret after checking the signing method
+func keyFunc(token *jwt.Token) (interface{}, error) {
+	// Validate the signing method
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return signingKey(), nil
+}
+
+// parseToken parses and verifies a JWT token signed with the JWT secret
+func parseToken(tokenString string) (*Claims, error) {
 	// Parse the JWT token
-	token, err := jwt.ParseWithClaims(
-		tokenString,
-		&Claims{},
-		func(token *jwt.Token) (interface{}, error) {
-			// Validate the signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return jwtKey, nil
-		},
-	)
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keyFunc)
 
 	if err != nil {
 		return nil, err
@@ -133,6 +172,27 @@ func Login(username, password string) (*models.LoginResponse, error) {
 		return nil, ErrInvalidCredentials
 	}
 
+	// If 2FA is enabled or required for the role, return a challenge instead of tokens
+	step, err := twoFactorStep(user)
+	if err != nil {
+		return nil, err
+	}
+	if step != "" {
+		challenge, err := newTwoFactorChallenge(user, step)
+		if err != nil {
+			return nil, err
+		}
+		return &models.LoginResponse{
+			User:      *user,
+			TwoFactor: challenge,
+		}, nil
+	}
+
+	return newLoginResponse(user)
+}
+
+// newLoginResponse generates the JWT tokens for a user and wraps them in a login response
+func newLoginResponse(user *models.User) (*models.LoginResponse, error) {
 	// Generate JWT tokens
 	token, refreshToken, err := GenerateTokens(*user)
 	if err != nil {
@@ -163,7 +223,7 @@ func CreateAdminUser() error {
 	// Create admin user
 	adminUser := models.User{
 		Username: "admin",
-		Password: "delpresence", // Will be hashed by BeforeSave hook
+		Password: config.Get().Auth.DefaultAdminPassword, // Will be hashed by BeforeSave hook
 		Role:     "Admin",
 	}
 
@@ -173,7 +233,7 @@ func CreateAdminUser() error {
 		return err
 	}
 
-	log.Println("Admin user created successfully")
+	slog.Info("Admin user created")
 	return nil
 }
 
@@ -184,6 +244,9 @@ func RefreshToken(refreshTokenString string) (*models.LoginResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	if claims.Offline || claims.IsImpersonation() {
+		return nil, ErrInvalidToken
+	}
 
 	// Get user from database
 	user, err := UserRepository.FindByID(claims.UserID)
@@ -5,10 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
-	"net/http"
+	"log/slog"
 	"strings"
 
+	"github.com/delpresence/backend/internal/apierror"
 	"github.com/delpresence/backend/internal/auth"
 	"github.com/delpresence/backend/internal/repositories"
 	"github.com/gin-gonic/gin"
@@ -53,8 +53,18 @@ func ValidateCampusToken(token string) (*CampusTokenClaims, error) {
 		return nil, fmt.Errorf("failed to decode payload: %w", err)
 	}
 
-	// Log the full payload for debugging
-	log.Printf("Decoded JWT payload: %s", string(jsonPayload))
+	// Challenge and impersonation claims only exist on tokens issued by this server,
+	// an unsigned payload carrying them is never a campus token
+	var internalClaims struct {
+		Purpose        string      `json:"purpose"`
+		ImpersonatorID interface{} `json:"impersonator_id"`
+	}
+	if err := json.Unmarshal(jsonPayload, &internalClaims); err != nil {
+		return nil, fmt.Errorf("failed to parse payload: %w", err)
+	}
+	if internalClaims.Purpose != "" || internalClaims.ImpersonatorID != nil {
+		return nil, ErrInvalidToken
+	}
 
 	// Try multiple possible formats to find user information
 
@@ -143,9 +153,6 @@ func ValidateCampusToken(token string) (*CampusTokenClaims, error) {
 	// Generic approach as last resort
 	var genericMap map[string]interface{}
 	if err := json.Unmarshal(jsonPayload, &genericMap); err == nil {
-		// Log the full map structure
-		log.Printf("Token payload as map: %+v", genericMap)
-
 		userID := uint(0)
 		username := ""
 		role := "" // Don't default to Dosen, we'll fetch from the database
@@ -234,13 +241,9 @@ func CampusAuthMiddleware() gin.HandlerFunc {
 		authHeader := c.GetHeader("Authorization")
 		path := c.Request.URL.Path
 
-		// Add debug log
-		log.Printf("Processing request for path: %s", path)
-
 		// Check if the header exists and has the correct format
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required and must be a Bearer token"})
-			c.Abort()
+			apierror.Respond(c, apierror.New(apierror.CodeUnauthorized, "Authorization header is required and must be a Bearer token"))
 			return
 		}
 
@@ -255,29 +258,39 @@ func CampusAuthMiddleware() gin.HandlerFunc {
 			c.Set("username", internalClaims.Username)
 			c.Set("role", internalClaims.Role)
 
-			// Add debug log
-			log.Printf("Internal token validation successful for user ID: %v, username: %s, role: %s",
-				internalClaims.UserID, internalClaims.Username, internalClaims.Role)
+			// Impersonation tokens also carry the real identity of the admin
+			if internalClaims.IsImpersonation() {
+				c.Set("impersonatorID", internalClaims.ImpersonatorID)
+				c.Set("impersonatorUsername", internalClaims.ImpersonatorUsername)
+				c.Set("impersonatorRole", internalClaims.ImpersonatorRole)
+				c.Set("impersonationReadOnly", internalClaims.ReadOnly)
+			}
 
 			c.Next()
 			return
 		}
 
+		// A token signed by this server that failed validation is expired, a 2FA challenge or
+		// otherwise not an access token. Reading it as a campus token would skip those checks.
+		if auth.IsSignedToken(tokenString) {
+			slog.InfoContext(c.Request.Context(), "Internal token rejected", "error", err)
+			apierror.Respond(c, apierror.New(apierror.CodeInvalidToken, "Invalid or expired token"))
+			return
+		}
+
 		// If internal validation failed, try campus token validation
-		log.Printf("Internal token validation failed: %v, trying campus token validation", err)
+		slog.DebugContext(c.Request.Context(), "Not an internal token, trying campus token validation", "error", err)
 		campusClaims, err := ValidateCampusToken(tokenString)
 		if err != nil {
-			log.Printf("Campus token validation failed: %v", err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+			slog.InfoContext(c.Request.Context(), "Campus token validation failed", "error", err)
+			apierror.Respond(c, apierror.New(apierror.CodeInvalidToken, "Invalid or expired token"))
 			return
 		}
 
 		// Make sure we have a valid userID
 		if campusClaims.UserID == 0 {
-			log.Printf("Could not extract userID from campus token")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: could not extract user ID"})
-			c.Abort()
+			slog.InfoContext(c.Request.Context(), "Could not extract user ID from campus token")
+			apierror.Respond(c, apierror.New(apierror.CodeInvalidToken, "Invalid token: could not extract user ID"))
 			return
 		}
 
@@ -294,35 +307,25 @@ func CampusAuthMiddleware() gin.HandlerFunc {
 		// Determine role from path and token
 		role := campusClaims.Role
 
-		// Add debug log for role detection
-		log.Printf("Initial role from token: %s", role)
-
 		// If role is empty, infer from the URL path
 		if role == "" {
 			if strings.Contains(path, "/api/lecturer/") {
 				role = "Dosen"
-				log.Printf("Setting role to Dosen based on path: %s", path)
 			} else if strings.Contains(path, "/api/employee/") {
 				role = "Pegawai"
-				log.Printf("Setting role to Pegawai based on path: %s", path)
 			} else if strings.Contains(path, "/api/student/") {
 				role = "Mahasiswa"
-				log.Printf("Setting role to Mahasiswa based on path: %s", path)
 			} else if strings.Contains(path, "/api/assistant/") {
 				role = "Asisten Dosen"
-				log.Printf("Setting role to Asisten Dosen based on path: %s", path)
 			} else {
 				// Default to "Guest" for unknown paths
 				role = "Guest"
-				log.Printf("Setting role to Guest for unknown path: %s", path)
 			}
 		}
 
 		c.Set("role", role)
 
-		// Log the context info
-		log.Printf("Set context for request: userID=%v, username=%s, role=%s, path=%s",
-			campusClaims.UserID, username, role, path)
+		slog.DebugContext(c.Request.Context(), "Campus token accepted", "user_id", campusClaims.UserID, "username", username, "role", role)
 
 		c.Next()
 	}
@@ -336,15 +339,14 @@ func fetchUserRoleFromDatabase(userID uint) string {
 	// Try to find user by external ID
 	user, err := userRepo.FindByExternalUserID(int(userID))
 	if err != nil {
-		log.Printf("Error finding user with ID %d: %v", userID, err)
+		slog.Error("Error finding user by external ID", "external_user_id", userID, "error", err)
 		return ""
 	}
 
 	if user == nil {
-		log.Printf("User with ID %d not found in database", userID)
+		slog.Debug("User not found", "external_user_id", userID)
 		return ""
 	}
 
-	log.Printf("Found user with role: %s for user ID: %d", user.Role, userID)
 	return user.Role
 }
@@ -0,0 +1,166 @@
+package campus
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/delpresence/backend/internal/auth"
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/middleware"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+const testSecret = "test-secret-with-at-least-32-characters"
+
+// newTestRouter mirrors the protected route group of the server with one admin and one student route
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	t.Setenv("JWT_SECRET", testSecret)
+	if _, err := config.Load(); err != nil {
+		t.Logf("config: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authRequired := router.Group("/api")
+	authRequired.Use(CampusAuthMiddleware(), middleware.ImpersonationMiddleware())
+	authRequired.GET("/admin/users", middleware.RoleMiddleware("Admin"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	authRequired.POST("/student/calendar-feed", middleware.RoleMiddleware("Mahasiswa"), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	return router
+}
+
+// signTestToken signs claims with the test secret, as the server does for its own tokens
+func signTestToken(t *testing.T, claims *auth.Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+// serve sends a request with the bearer token and returns the status code
+func serve(router *gin.Engine, method, path, token string) int {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestCampusAuthMiddlewareRejectsTwoFactorChallengeToken(t *testing.T) {
+	router := newTestRouter(t)
+
+	for _, purpose := range []string{"2fa_verify", "2fa_setup"} {
+		token := signTestToken(t, &auth.Claims{
+			UserID:   1,
+			Username: "admin",
+			Role:     "Admin",
+			Purpose:  purpose,
+			StandardClaims: jwt.StandardClaims{
+				ExpiresAt: time.Now().Add(5 * time.Minute).Unix(),
+			},
+		})
+		if code := serve(router, http.MethodGet, "/api/admin/users", token); code != http.StatusUnauthorized {
+			t.Errorf("%s challenge token on admin route: got status %d, want %d", purpose, code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestCampusAuthMiddlewareAcceptsAccessToken(t *testing.T) {
+	router := newTestRouter(t)
+
+	token := signTestToken(t, &auth.Claims{
+		UserID:   1,
+		Username: "admin",
+		Role:     "Admin",
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	})
+	if code := serve(router, http.MethodGet, "/api/admin/users", token); code != http.StatusOK {
+		t.Errorf("access token on admin route: got status %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestValidateCampusTokenRejectsChallengePayload(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"user_id":1,"username":"admin","role":"Admin","purpose":"2fa_verify"}`))
+	if _, err := ValidateCampusToken("e30." + payload + ".c2ln"); err == nil {
+		t.Error("unsigned payload with a purpose was accepted as a campus token")
+	}
+}
+
+func TestCampusAuthMiddlewareRejectsExpiredImpersonationToken(t *testing.T) {
+	router := newTestRouter(t)
+
+	token := signTestToken(t, &auth.Claims{
+		UserID:               42,
+		Username:             "student",
+		Role:                 "Mahasiswa",
+		ImpersonatorID:       1,
+		ImpersonatorUsername: "admin",
+		ReadOnly:             true,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(-time.Minute).Unix(),
+		},
+	})
+	if code := serve(router, http.MethodPost, "/api/student/calendar-feed", token); code != http.StatusUnauthorized {
+		t.Errorf("expired impersonation token on POST: got status %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestValidateCampusTokenRejectsImpersonationPayload(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"user_id":42,"username":"student","role":"Mahasiswa","impersonator_id":1,"read_only":true}`))
+	if _, err := ValidateCampusToken("e30." + payload + ".c2ln"); err == nil {
+		t.Error("unsigned payload with an impersonator was accepted as a campus token")
+	}
+}
+
+func TestImpersonatedRequestAuditsImpersonatorRole(t *testing.T) {
+	t.Setenv("DB_DRIVER", database.DriverSQLite)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "audit.db"))
+	router := newTestRouter(t)
+	database.Initialize()
+	t.Cleanup(database.Close)
+	if err := database.MigrateUp(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	previous := auth.AuditLogRepository
+	auth.AuditLogRepository = repositories.NewAuditLogRepository()
+	t.Cleanup(func() { auth.AuditLogRepository = previous })
+
+	token := signTestToken(t, &auth.Claims{
+		UserID:               42,
+		Username:             "student",
+		Role:                 "Mahasiswa",
+		ImpersonatorID:       7,
+		ImpersonatorUsername: "support",
+		ImpersonatorRole:     "Support",
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		},
+	})
+	if code := serve(router, http.MethodPost, "/api/student/calendar-feed", token); code != http.StatusCreated {
+		t.Fatalf("impersonated POST: got status %d, want %d", code, http.StatusCreated)
+	}
+
+	logs, err := auth.AuditLogRepository.List(models.ListQuery{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("list audit logs: %v", err)
+	}
+	if len(logs.Items) != 1 || logs.Items[0].ActorUsername != "support" || logs.Items[0].ActorRole != "Support" {
+		t.Errorf("got audit logs %+v, want one request by support with the Support role", logs.Items)
+	}
+}
@@ -4,27 +4,28 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 
+	"github.com/delpresence/backend/internal/config"
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/repositories"
 )
 
-const (
-	CampusAuthURL = "https://cis.del.ac.id/api/jwt-api/do-auth"
-)
-
 var (
 	// ErrCampusAuthFailed is returned when campus authentication fails
 	ErrCampusAuthFailed = errors.New("campus authentication failed")
+
+	// ErrCampusUnavailable is returned when CIS cannot be reached or fails with a server error
+	ErrCampusUnavailable = errors.New("campus authentication service unavailable")
 )
 
 // CampusLogin handles authentication with the campus API for all roles
 // This includes students, lecturers, and employees
 func CampusLogin(username, password string) (*models.CampusLoginResponse, error) {
-	log.Printf("Attempting campus login for username: %s", username)
+	slog.Info("Attempting campus login", "username", username)
 
 	// Create a buffer to write form data
 	var requestBody bytes.Buffer
@@ -33,58 +34,62 @@ func CampusLogin(username, password string) (*models.CampusLoginResponse, error)
 	// Add username field
 	usernameField, err := writer.CreateFormField("username")
 	if err != nil {
-		log.Printf("Error creating username field: %v", err)
+		slog.Error("Error creating campus login form", "error", err)
 		return nil, err
 	}
 	_, err = usernameField.Write([]byte(username))
 	if err != nil {
-		log.Printf("Error writing username: %v", err)
+		slog.Error("Error creating campus login form", "error", err)
 		return nil, err
 	}
 
 	// Add password field
 	passwordField, err := writer.CreateFormField("password")
 	if err != nil {
-		log.Printf("Error creating password field: %v", err)
+		slog.Error("Error creating campus login form", "error", err)
 		return nil, err
 	}
 	_, err = passwordField.Write([]byte(password))
 	if err != nil {
-		log.Printf("Error writing password: %v", err)
+		slog.Error("Error creating campus login form", "error", err)
 		return nil, err
 	}
 
 	// Close the multipart writer
 	err = writer.Close()
 	if err != nil {
-		log.Printf("Error closing multipart writer: %v", err)
+		slog.Error("Error creating campus login form", "error", err)
 		return nil, err
 	}
 
 	// Create HTTP request
-	request, err := http.NewRequest("POST", CampusAuthURL, &requestBody)
+	request, err := http.NewRequest("POST", config.Get().Campus.AuthURL, &requestBody)
 	if err != nil {
-		log.Printf("Error creating HTTP request: %v", err)
+		slog.Error("Error creating campus login request", "error", err)
 		return nil, err
 	}
 
 	// Set content type
 	request.Header.Set("Content-Type", writer.FormDataContentType())
-	log.Printf("Making request to URL: %s", CampusAuthURL)
+	slog.Debug("Sending campus login request", "url", request.URL.String())
 
 	// Send request
-	client := &http.Client{}
+	client := &http.Client{Timeout: config.Get().Campus.LoginTimeout}
 	response, err := client.Do(request)
 	if err != nil {
-		log.Printf("Error sending request: %v", err)
-		return nil, err
+		slog.Warn("Error sending campus login request", "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrCampusUnavailable, err)
 	}
 	defer response.Body.Close()
 
 	// Check response status code
-	log.Printf("Received response with status code: %d", response.StatusCode)
+	slog.Debug("Received campus login response", "status", response.StatusCode)
+	if response.StatusCode >= http.StatusInternalServerError {
+		slog.Warn("Campus API returned server error", "status", response.StatusCode)
+		return nil, fmt.Errorf("%w: status code %d", ErrCampusUnavailable, response.StatusCode)
+	}
 	if response.StatusCode != http.StatusOK {
-		log.Printf("Status code is not OK: %d", response.StatusCode)
+		slog.Info("Campus login rejected", "status", response.StatusCode)
 		return nil, ErrCampusAuthFailed
 	}
 
@@ -92,25 +97,29 @@ func CampusLogin(username, password string) (*models.CampusLoginResponse, error)
 	var loginResponse models.CampusLoginResponse
 	err = json.NewDecoder(response.Body).Decode(&loginResponse)
 	if err != nil {
-		log.Printf("Error decoding response: %v", err)
-		return nil, err
+		// CIS answers with an HTML maintenance page when it is down
+		slog.Warn("Error decoding campus login response", "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrCampusUnavailable, err)
 	}
 
 	// Check if login was successful
-	log.Printf("Login result: %v, Role: %s", loginResponse.Result, loginResponse.User.Role)
+	slog.Debug("Campus login result", "result", loginResponse.Result, "role", loginResponse.User.Role)
 	if !loginResponse.Result {
-		log.Printf("Login failed with error: %s", loginResponse.Error)
-		return nil, errors.New(loginResponse.Error)
+		slog.Info("Campus login failed", "username", username, "error", loginResponse.Error)
+		return nil, fmt.Errorf("%w: %s", ErrCampusAuthFailed, loginResponse.Error)
 	}
 
 	// Save or update user in our database
 	err = SaveCampusUserToDatabase(&loginResponse, password)
 	if err != nil {
-		log.Printf("Error saving user to database: %v", err)
+		slog.Error("Error saving campus user", "username", username, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Campus login successful for user: %s, role: %s", loginResponse.User.Username, loginResponse.User.Role)
+	// Remember a verifier of the password for logins while CIS is down
+	storeCampusCredential(&loginResponse.User, password)
+
+	slog.Info("Campus login successful", "username", loginResponse.User.Username, "role", loginResponse.User.Role)
 	return &loginResponse, nil
 }
 
@@ -118,29 +127,26 @@ func CampusLogin(username, password string) (*models.CampusLoginResponse, error)
 func SaveCampusUserToDatabase(campusResponse *models.CampusLoginResponse, password string) error {
 	// Initialize user repository if needed
 	if UserRepository == nil {
-		log.Printf("Initializing UserRepository")
 		UserRepository = repositories.NewUserRepository()
 	}
 
 	// Check if a user with this external ID already exists
 	externalUserID := campusResponse.User.UserID
-	log.Printf("Checking if user with external ID %d exists", externalUserID)
 	existingUser, err := UserRepository.FindByExternalUserID(externalUserID)
 	if err != nil {
-		log.Printf("Error finding user by external ID: %v", err)
+		slog.Error("Error finding user by external ID", "external_user_id", externalUserID, "error", err)
 		return err
 	}
 
 	if existingUser != nil {
-		log.Printf("User already exists in database, no need to create")
 		return nil
 	}
 
 	// Create a new user - password will be hashed by the BeforeSave hook
-	log.Printf("Creating new user record for %s with role %s", campusResponse.User.Username, campusResponse.User.Role)
+	slog.Info("Creating user for campus account", "username", campusResponse.User.Username, "role", campusResponse.User.Role)
 	hashedPassword, err := models.HashPassword(password)
 	if err != nil {
-		log.Printf("Error hashing password: %v", err)
+		slog.Error("Error hashing password", "error", err)
 		return err
 	}
 
@@ -152,14 +158,12 @@ func SaveCampusUserToDatabase(campusResponse *models.CampusLoginResponse, passwo
 	}
 
 	// Save the user
-	log.Printf("Saving new user to database")
 	err = UserRepository.Create(&newUser)
 	if err != nil {
-		log.Printf("Error creating user: %v", err)
+		slog.Error("Error creating user", "username", campusResponse.User.Username, "error", err)
 		return err
 	}
 
-	log.Printf("User created successfully")
 	return nil
 }
 
@@ -167,21 +171,19 @@ func SaveCampusUserToDatabase(campusResponse *models.CampusLoginResponse, passwo
 func ConvertCampusResponseToLoginResponse(campusResponse *models.CampusLoginResponse) *models.LoginResponse {
 	// Initialize user repository if needed
 	if UserRepository == nil {
-		log.Printf("Initializing UserRepository")
 		UserRepository = repositories.NewUserRepository()
 	}
 
 	// Get the user from our database
 	externalUserID := campusResponse.User.UserID
-	log.Printf("Looking up user with external ID %d", externalUserID)
 	user, err := UserRepository.FindByExternalUserID(externalUserID)
 	if err != nil {
-		log.Printf("Error finding user: %v", err)
+		slog.Error("Error finding user by external ID", "external_user_id", externalUserID, "error", err)
 	}
 
 	// If user doesn't exist in our database, use the campus user info
 	if user == nil {
-		log.Printf("User not found in database, creating temporary user object")
+		slog.Debug("Campus user not stored, using the campus account", "external_user_id", externalUserID)
 		// Create default user object from campus user
 		user = &models.User{
 			Username: campusResponse.User.Username,
@@ -189,8 +191,6 @@ func ConvertCampusResponseToLoginResponse(campusResponse *models.CampusLoginResp
 		}
 	}
 
-	log.Printf("Converted campus user to login response with role: %s", user.Role)
-	
 	// Return login response - ensure token and refreshToken are correctly set
 	// This is critical for frontend compatibility
 	return &models.LoginResponse{
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// CampusCredentialRepository is the repository for cached campus credentials
+var CampusCredentialRepository *repositories.CampusCredentialRepository
+
+// AuditLogRepository is the repository for audit log entries
+var AuditLogRepository *repositories.AuditLogRepository
+
+// campusCredentialRepository returns the repository, creating it if Initialize was not called
+func campusCredentialRepository() *repositories.CampusCredentialRepository {
+	if CampusCredentialRepository == nil {
+		CampusCredentialRepository = repositories.NewCampusCredentialRepository()
+	}
+	return CampusCredentialRepository
+}
+
+// auditLogRepository returns the repository, creating it if Initialize was not called
+func auditLogRepository() *repositories.AuditLogRepository {
+	if AuditLogRepository == nil {
+		AuditLogRepository = repositories.NewAuditLogRepository()
+	}
+	return AuditLogRepository
+}
+
+// offlineLoginEnabled reports whether logins may fall back to the credential cache
+func offlineLoginEnabled() bool {
+	return config.Get().Campus.OfflineLoginEnabled
+}
+
+// offlineGracePeriod is how long after the last successful CIS login the cache may be used
+func offlineGracePeriod() time.Duration {
+	return config.Get().Campus.OfflineGracePeriod
+}
+
+// offlineTokenLifetime is the lifetime of tokens issued by an offline login
+// They are kept short and cannot be refreshed, so users go back to CIS once it is up
+func offlineTokenLifetime() time.Duration {
+	return config.Get().Campus.OfflineTokenTTL
+}
+
+// CampusLoginWithFallback authenticates against CIS and, when CIS is unavailable, against the
+// cached credential verifier. The returned flag is true when the login was validated offline.
+// Users deactivated by the sync are refused with ErrAccountInactive.
+func CampusLoginWithFallback(username, password, clientIP string) (*models.CampusLoginResponse, bool, error) {
+	response, offline, err := campusLoginWithFallback(username, password, clientIP)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := ensureCampusAccountActive(response.User.UserID); err != nil {
+		slog.Info("Refusing campus login", "username", username, "error", err)
+		return nil, false, err
+	}
+	return response, offline, nil
+}
+
+// campusLoginWithFallback authenticates against CIS, or the credential cache when CIS is unavailable
+func campusLoginWithFallback(username, password, clientIP string) (*models.CampusLoginResponse, bool, error) {
+	response, err := CampusLogin(username, password)
+	if err == nil {
+		return response, false, nil
+	}
+
+	switch {
+	case errors.Is(err, ErrCampusAuthFailed):
+		invalidateCampusCredential(username, password, clientIP)
+		return nil, false, err
+	case errors.Is(err, ErrCampusUnavailable) && offlineLoginEnabled():
+		slog.Warn("CIS unavailable, trying offline login", "username", username, "error", err)
+		offlineResponse, offlineErr := offlineCampusLogin(username, password, clientIP)
+		if offlineErr != nil {
+			return nil, false, offlineErr
+		}
+		return offlineResponse, true, nil
+	default:
+		return nil, false, err
+	}
+}
+
+// storeCampusCredential caches an Argon2id verifier of a password that CIS accepted
+func storeCampusCredential(user *models.CampusUser, password string) {
+	if !offlineLoginEnabled() {
+		return
+	}
+
+	verifier, err := models.HashPassword(password)
+	if err != nil {
+		slog.Error("Error hashing campus credential", "username", user.Username, "error", err)
+		return
+	}
+
+	credential := &models.CampusCredential{
+		ExternalUserID: user.UserID,
+		Username:       user.Username,
+		Email:          user.Email,
+		Role:           user.Role,
+		Verifier:       verifier,
+		VerifiedAt:     time.Now(),
+	}
+	if err := campusCredentialRepository().Save(credential); err != nil {
+		slog.Error("Error caching campus credential", "username", user.Username, "error", err)
+	}
+}
+
+// invalidateCampusCredential drops the cached verifier when CIS rejects a password that the
+// cache would still accept, which means the password was changed in CIS
+func invalidateCampusCredential(username, password, clientIP string) {
+	credential, err := campusCredentialRepository().FindByUsername(username)
+	if err != nil || credential == nil {
+		return
+	}
+	if !models.CheckPasswordHash(password, credential.Verifier) {
+		return
+	}
+
+	if err := campusCredentialRepository().DeleteByExternalUserID(credential.ExternalUserID); err != nil {
+		slog.Error("Error invalidating campus credential", "username", username, "error", err)
+		return
+	}
+
+	slog.Info("CIS rejected the cached password, campus credential invalidated", "username", username)
+	recordAudit(&models.AuditLog{
+		Action:        models.AuditActionCampusCredentialPurged,
+		ActorUserID:   uint(credential.ExternalUserID),
+		ActorUsername: credential.Username,
+		ActorRole:     credential.Role,
+		IPAddress:     clientIP,
+		Details:       "CIS rejected a password that matched the cached verifier",
+	})
+}
+
+// offlineCampusLogin validates a login against the cached verifier and issues a short-lived token
+func offlineCampusLogin(username, password, clientIP string) (*models.CampusLoginResponse, error) {
+	credential, err := campusCredentialRepository().FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil {
+		return nil, fmt.Errorf("%w: no cached credential for offline login", ErrCampusUnavailable)
+	}
+
+	if time.Since(credential.VerifiedAt) > offlineGracePeriod() {
+		return nil, fmt.Errorf("%w: cached credential expired", ErrCampusUnavailable)
+	}
+
+	if !models.CheckPasswordHash(password, credential.Verifier) {
+		return nil, ErrCampusAuthFailed
+	}
+
+	token, err := generateOfflineToken(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"verified_at": credential.VerifiedAt,
+		"expires_in":  int(offlineTokenLifetime().Seconds()),
+	})
+	recordAudit(&models.AuditLog{
+		Action:        models.AuditActionOfflineLogin,
+		ActorUserID:   uint(credential.ExternalUserID),
+		ActorUsername: credential.Username,
+		ActorRole:     credential.Role,
+		IPAddress:     clientIP,
+		Details:       string(details),
+	})
+	slog.Info("Offline campus login", "username", credential.Username, "verified_at", credential.VerifiedAt.Format(time.RFC3339))
+
+	return &models.CampusLoginResponse{
+		Result:  true,
+		Success: "Offline login",
+		User: models.CampusUser{
+			UserID:   credential.ExternalUserID,
+			Username: credential.Username,
+			Email:    credential.Email,
+			Role:     credential.Role,
+		},
+		Token: token,
+	}, nil
+}
+
+// generateOfflineToken issues an internal access token that carries the campus user ID,
+// so it is handled like a CIS token by the rest of the API
+func generateOfflineToken(credential *models.CampusCredential) (string, error) {
+	claims := &Claims{
+		UserID:   uint(credential.ExternalUserID),
+		Username: credential.Username,
+		Role:     credential.Role,
+		Offline:  true,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(offlineTokenLifetime()).Unix(),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(signingKey())
+}
+
+// recordAudit stores an audit log entry, logging instead of failing the caller on errors
+func recordAudit(entry *models.AuditLog) {
+	if err := auditLogRepository().Create(entry); err != nil {
+		slog.Error("Error writing audit log", "action", entry.Action, "error", err)
+	}
+}
@@ -0,0 +1,378 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+var (
+	// ErrTooManyAttempts is returned when a key must wait before trying again
+	ErrTooManyAttempts = errors.New("too many login attempts")
+
+	// ErrAccountLocked is returned when a key is temporarily locked out
+	ErrAccountLocked = errors.New("account temporarily locked")
+)
+
+// ThrottleError is returned by the limiter when an attempt is not allowed
+// It carries how long the caller has to wait before the next attempt
+type ThrottleError struct {
+	Err        error
+	Key        string
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *ThrottleError) Error() string {
+	return fmt.Sprintf("%v (%s), retry after %s", e.Err, e.Key, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap returns the underlying sentinel error
+func (e *ThrottleError) Unwrap() error {
+	return e.Err
+}
+
+// LimiterStore persists login attempt state
+// The in-memory store is enough for a single instance, the database store shares
+// state between instances. Failures are counted and locks applied by single atomic
+// updates, so concurrent failed logins cannot overwrite each other's counts.
+type LimiterStore interface {
+	Get(key string) (*models.LoginAttempt, error)
+	// IncrementFailures adds one failure to a key at now, restarting the count when the
+	// previous failure is before windowStart, and returns the updated record
+	IncrementFailures(key string, now, windowStart time.Time) (*models.LoginAttempt, error)
+	// Lock locks a key until lockedUntil and resets its failures if it has at least
+	// maxFailures failures. It reports whether the key was locked by this call.
+	Lock(key string, maxFailures int, lockedUntil time.Time) (bool, error)
+	Delete(key string) error
+	FindLocked(now time.Time) ([]models.LoginAttempt, error)
+}
+
+// memorySweepInterval is how often the in-memory store evicts stale keys
+const memorySweepInterval = time.Minute
+
+// MemoryLimiterStore is an in-process LimiterStore
+// Keys whose failures are older than the failure window and that are not locked are
+// evicted, so keys that only ever fail do not grow the map without bound
+type MemoryLimiterStore struct {
+	attempts      map[string]models.LoginAttempt
+	failureWindow time.Duration
+	lastSweep     time.Time
+	mutex         sync.Mutex
+}
+
+// NewMemoryLimiterStore creates a new in-memory limiter store that forgets keys after the failure window
+func NewMemoryLimiterStore(failureWindow time.Duration) *MemoryLimiterStore {
+	return &MemoryLimiterStore{
+		attempts:      make(map[string]models.LoginAttempt),
+		failureWindow: failureWindow,
+	}
+}
+
+// Get returns the attempt record for a key, or nil if there is none
+func (s *MemoryLimiterStore) Get(key string) (*models.LoginAttempt, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	if s.stale(&attempt, time.Now()) {
+		delete(s.attempts, key)
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+// stale reports whether an attempt is neither locked nor within the failure window
+func (s *MemoryLimiterStore) stale(attempt *models.LoginAttempt, now time.Time) bool {
+	return !attempt.IsLocked(now) && now.Sub(attempt.LastFailureAt) > s.failureWindow
+}
+
+// sweep evicts stale keys, at most once per sweep interval. The mutex must be held.
+func (s *MemoryLimiterStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, attempt := range s.attempts {
+		if s.stale(&attempt, now) {
+			delete(s.attempts, key)
+		}
+	}
+}
+
+// IncrementFailures adds one failure to a key, forgetting failures from before windowStart
+func (s *MemoryLimiterStore) IncrementFailures(key string, now, windowStart time.Time) (*models.LoginAttempt, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sweep(now)
+	attempt, ok := s.attempts[key]
+	if !ok {
+		attempt = models.LoginAttempt{Key: key}
+	}
+	if attempt.LastFailureAt.Before(windowStart) {
+		attempt.Failures = 0
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	s.attempts[key] = attempt
+	return &attempt, nil
+}
+
+// Lock locks a key that reached maxFailures and resets its failures
+func (s *MemoryLimiterStore) Lock(key string, maxFailures int, lockedUntil time.Time) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok || attempt.Failures < maxFailures {
+		return false, nil
+	}
+	attempt.Failures = 0
+	attempt.Lockouts++
+	attempt.LockedUntil = &lockedUntil
+	s.attempts[key] = attempt
+	return true, nil
+}
+
+// Delete removes the attempt record for a key
+func (s *MemoryLimiterStore) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+// FindLocked returns all keys locked at the given time
+func (s *MemoryLimiterStore) FindLocked(now time.Time) ([]models.LoginAttempt, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sweep(now)
+	locked := make([]models.LoginAttempt, 0)
+	for _, attempt := range s.attempts {
+		if attempt.IsLocked(now) {
+			locked = append(locked, attempt)
+		}
+	}
+	sort.Slice(locked, func(i, j int) bool {
+		return locked[i].LockedUntil.After(*locked[j].LockedUntil)
+	})
+	return locked, nil
+}
+
+// LimiterConfig holds the thresholds used by the login limiter
+type LimiterConfig struct {
+	// BackoffAfter is the number of failures before progressive backoff starts
+	BackoffAfter int
+	// BaseDelay is the delay after the first failure past BackoffAfter, doubled on each further failure
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay
+	MaxDelay time.Duration
+	// UserMaxFailures is the number of failures for a username before it is locked out
+	UserMaxFailures int
+	// IPMaxFailures is the number of failures for an IP address before it is locked out
+	IPMaxFailures int
+	// LockoutDuration is the first lockout duration, doubled on each further lockout
+	LockoutDuration time.Duration
+	// MaxLockoutDuration caps the lockout duration
+	MaxLockoutDuration time.Duration
+	// FailureWindow is how long failures are remembered without a new failure
+	FailureWindow time.Duration
+}
+
+// LoadLimiterConfig returns the limiter configuration from the login settings
+func LoadLimiterConfig() LimiterConfig {
+	cfg := config.Get().Login
+	return LimiterConfig{
+		BackoffAfter:       cfg.BackoffAfter,
+		BaseDelay:          cfg.BackoffBase,
+		MaxDelay:           cfg.BackoffMax,
+		UserMaxFailures:    cfg.UserMaxFailures,
+		IPMaxFailures:      cfg.IPMaxFailures,
+		LockoutDuration:    cfg.LockoutDuration,
+		MaxLockoutDuration: cfg.MaxLockoutDuration,
+		FailureWindow:      cfg.FailureWindow,
+	}
+}
+
+// LoginLimiter throttles login attempts per IP address and per username
+type LoginLimiter struct {
+	store  LimiterStore
+	config LimiterConfig
+	now    func() time.Time
+}
+
+// NewLoginLimiter creates a new login limiter backed by the given store
+func NewLoginLimiter(store LimiterStore, config LimiterConfig) *LoginLimiter {
+	return &LoginLimiter{
+		store:  store,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Limiter is the login limiter shared by the login handlers
+var Limiter *LoginLimiter
+
+// initializeLimiter creates the shared limiter, using the store selected by LOGIN_LIMITER_STORE
+func initializeLimiter() {
+	var store LimiterStore
+	switch strings.ToLower(config.Get().Login.LimiterStore) {
+	case "database", "db":
+		store = repositories.NewLoginAttemptRepository()
+		slog.Info("Login limiter using database store")
+	default:
+		store = NewMemoryLimiterStore(config.Get().Login.FailureWindow)
+		slog.Info("Login limiter using in-memory store")
+	}
+	Limiter = NewLoginLimiter(store, LoadLimiterConfig())
+}
+
+// IPKey returns the limiter key for an IP address
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// UserKey returns the limiter key for a username
+func UserKey(username string) string {
+	return "user:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+// loginKeys returns the limiter keys for an attempt, skipping empty values
+func loginKeys(ip, username string) []string {
+	keys := make([]string, 0, 2)
+	if ip != "" {
+		keys = append(keys, IPKey(ip))
+	}
+	if strings.TrimSpace(username) != "" {
+		keys = append(keys, UserKey(username))
+	}
+	return keys
+}
+
+// Allow checks whether a login attempt from the IP for the username may proceed
+// The username may be empty for endpoints that are only throttled by IP
+func (l *LoginLimiter) Allow(ip, username string) error {
+	now := l.now()
+	for _, key := range loginKeys(ip, username) {
+		attempt, err := l.store.Get(key)
+		if err != nil {
+			return err
+		}
+		if attempt == nil {
+			continue
+		}
+
+		if attempt.IsLocked(now) {
+			return &ThrottleError{Err: ErrAccountLocked, Key: key, RetryAfter: attempt.LockedUntil.Sub(now)}
+		}
+
+		if l.expired(attempt, now) {
+			continue
+		}
+
+		if attempt.Failures >= l.config.BackoffAfter {
+			nextAllowed := attempt.LastFailureAt.Add(l.backoffDelay(attempt.Failures))
+			if nextAllowed.After(now) {
+				return &ThrottleError{Err: ErrTooManyAttempts, Key: key, RetryAfter: nextAllowed.Sub(now)}
+			}
+		}
+	}
+	return nil
+}
+
+// RecordFailure registers a failed login attempt for the IP and username
+func (l *LoginLimiter) RecordFailure(ip, username string) error {
+	now := l.now()
+	for _, key := range loginKeys(ip, username) {
+		// Forget old failures, but keep the lockout count so repeat offenders are locked longer
+		attempt, err := l.store.IncrementFailures(key, now, now.Add(-l.config.FailureWindow))
+		if err != nil {
+			return err
+		}
+
+		maxFailures := l.maxFailures(key)
+		if attempt.Failures < maxFailures {
+			continue
+		}
+
+		// Concurrent failures may all reach the threshold, only the first one locks the key
+		lockedUntil := now.Add(l.lockoutDuration(attempt.Lockouts + 1))
+		locked, err := l.store.Lock(key, maxFailures, lockedUntil)
+		if err != nil {
+			return err
+		}
+		if locked {
+			slog.Warn("Login limiter locked key after repeated failures", "key", key, "locked_until", lockedUntil.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// RecordSuccess clears the failure history of the username after a successful login
+func (l *LoginLimiter) RecordSuccess(username string) error {
+	if strings.TrimSpace(username) == "" {
+		return nil
+	}
+	return l.store.Delete(UserKey(username))
+}
+
+// LockedKeys returns all currently locked keys
+func (l *LoginLimiter) LockedKeys() ([]models.LoginAttempt, error) {
+	return l.store.FindLocked(l.now())
+}
+
+// Unlock removes the lockout and failure history of a key
+func (l *LoginLimiter) Unlock(key string) error {
+	return l.store.Delete(key)
+}
+
+// expired reports whether the failures of an attempt are older than the failure window
+func (l *LoginLimiter) expired(attempt *models.LoginAttempt, now time.Time) bool {
+	return now.Sub(attempt.LastFailureAt) > l.config.FailureWindow
+}
+
+// maxFailures returns the lockout threshold for a key
+func (l *LoginLimiter) maxFailures(key string) int {
+	if strings.HasPrefix(key, "ip:") {
+		return l.config.IPMaxFailures
+	}
+	return l.config.UserMaxFailures
+}
+
+// backoffDelay returns the delay required after the given number of failures
+func (l *LoginLimiter) backoffDelay(failures int) time.Duration {
+	delay := l.config.BaseDelay
+	for i := l.config.BackoffAfter; i < failures && delay < l.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > l.config.MaxDelay {
+		delay = l.config.MaxDelay
+	}
+	return delay
+}
+
+// lockoutDuration returns the lockout duration for the given lockout count
+func (l *LoginLimiter) lockoutDuration(lockouts int) time.Duration {
+	duration := l.config.LockoutDuration
+	for i := 1; i < lockouts && duration < l.config.MaxLockoutDuration; i++ {
+		duration *= 2
+	}
+	if duration > l.config.MaxLockoutDuration {
+		duration = l.config.MaxLockoutDuration
+	}
+	return duration
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+// testLimiterConfig locks a username after 10 failures, without backoff in the way
+func testLimiterConfig() LimiterConfig {
+	return LimiterConfig{
+		BackoffAfter:       100,
+		BaseDelay:          time.Second,
+		MaxDelay:           time.Minute,
+		UserMaxFailures:    10,
+		IPMaxFailures:      1000,
+		LockoutDuration:    15 * time.Minute,
+		MaxLockoutDuration: 24 * time.Hour,
+		FailureWindow:      15 * time.Minute,
+	}
+}
+
+// newSQLiteLimiterStore returns the database store on a migrated SQLite file
+func newSQLiteLimiterStore(t *testing.T) LimiterStore {
+	t.Helper()
+	t.Setenv("DB_DRIVER", database.DriverSQLite)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "limiter.db"))
+	if _, err := config.Load(); err != nil {
+		t.Logf("config: %v", err)
+	}
+	database.Initialize()
+	t.Cleanup(database.Close)
+	if err := database.MigrateUp(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return repositories.NewLoginAttemptRepository()
+}
+
+func TestRecordFailureLocksUnderConcurrentFailures(t *testing.T) {
+	stores := map[string]func(t *testing.T) LimiterStore{
+		"memory":   func(t *testing.T) LimiterStore { return NewMemoryLimiterStore(testLimiterConfig().FailureWindow) },
+		"database": newSQLiteLimiterStore,
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			cfg := testLimiterConfig()
+			limiter := NewLoginLimiter(newStore(t), cfg)
+
+			// Exactly the threshold of failures, each from its own IP as in a distributed brute force
+			var wg sync.WaitGroup
+			errs := make(chan error, cfg.UserMaxFailures)
+			for i := 0; i < cfg.UserMaxFailures; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					errs <- limiter.RecordFailure(fmt.Sprintf("10.0.0.%d", i), "victim")
+				}(i)
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Fatalf("record failure: %v", err)
+				}
+			}
+
+			err := limiter.Allow("", "victim")
+			if !errors.Is(err, ErrAccountLocked) {
+				t.Fatalf("after %d concurrent failures: got %v, want %v", cfg.UserMaxFailures, err, ErrAccountLocked)
+			}
+
+			locked, err := limiter.LockedKeys()
+			if err != nil {
+				t.Fatalf("locked keys: %v", err)
+			}
+			if len(locked) != 1 || locked[0].Lockouts != 1 {
+				t.Errorf("got locked keys %+v, want one key locked once", locked)
+			}
+		})
+	}
+}
+
+func TestMemoryLimiterStoreEvictsStaleKeys(t *testing.T) {
+	cfg := testLimiterConfig()
+	store := NewMemoryLimiterStore(cfg.FailureWindow)
+	limiter := NewLoginLimiter(store, cfg)
+
+	start := time.Now()
+	limiter.now = func() time.Time { return start }
+	for i := 0; i < 100; i++ {
+		if err := limiter.RecordFailure(fmt.Sprintf("10.0.1.%d", i), fmt.Sprintf("user%d", i)); err != nil {
+			t.Fatalf("record failure: %v", err)
+		}
+	}
+	if n := len(store.attempts); n != 200 {
+		t.Fatalf("got %d keys, want 200", n)
+	}
+
+	// After the failure window a new failure sweeps the keys that only ever failed
+	limiter.now = func() time.Time { return start.Add(cfg.FailureWindow + memorySweepInterval) }
+	if err := limiter.RecordFailure("10.0.2.1", "other"); err != nil {
+		t.Fatalf("record failure: %v", err)
+	}
+	if n := len(store.attempts); n != 2 {
+		t.Errorf("got %d keys after the failure window, want 2", n)
+	}
+}
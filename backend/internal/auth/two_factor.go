@@ -0,0 +1,381 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// challengeTokenLifetime is how long a user has to complete the second login step
+	challengeTokenLifetime = 5 * time.Minute
+
+	// Token purposes for challenge tokens, which are not accepted as access tokens
+	purposeTwoFactorVerify = "2fa_verify"
+	purposeTwoFactorSetup  = "2fa_setup"
+
+	// recoveryCodeCount is the number of recovery codes generated at once
+	recoveryCodeCount = 10
+)
+
+var (
+	// ErrInvalidTwoFactorCode is returned when a TOTP or recovery code is wrong or already used
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+	// ErrTwoFactorNotEnabled is returned when 2FA is not enabled for the user
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+
+	// ErrTwoFactorAlreadyEnabled is returned when the user tries to enroll again
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+
+	// ErrTwoFactorNotStarted is returned when enabling 2FA before requesting a secret
+	ErrTwoFactorNotStarted = errors.New("two-factor setup has not been started")
+
+	// ErrTwoFactorRequired is returned when the role policy forbids disabling 2FA
+	ErrTwoFactorRequired = errors.New("two-factor authentication is required for this role")
+)
+
+// TwoFactorRepository is the repository for TOTP enrollments
+var TwoFactorRepository *repositories.TwoFactorRepository
+
+// twoFactorRepository returns the repository, creating it if Initialize was not called
+func twoFactorRepository() *repositories.TwoFactorRepository {
+	if TwoFactorRepository == nil {
+		TwoFactorRepository = repositories.NewTwoFactorRepository()
+	}
+	return TwoFactorRepository
+}
+
+// IsTwoFactorRequired reports whether the role must use 2FA
+// Roles are listed in TWO_FACTOR_REQUIRED_ROLES, comma-separated (e.g. "Admin")
+func IsTwoFactorRequired(role string) bool {
+	for _, r := range config.Get().Auth.TwoFactorRequiredRoles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// twoFactorStep returns the pending second login step for a user, or "" if none
+func twoFactorStep(user *models.User) (string, error) {
+	twoFactor, err := twoFactorRepository().FindByUserID(user.ID)
+	if err != nil {
+		return "", err
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		return models.TwoFactorStepVerify, nil
+	}
+	if IsTwoFactorRequired(user.Role) {
+		return models.TwoFactorStepSetup, nil
+	}
+	return "", nil
+}
+
+// newTwoFactorChallenge creates the login response for a pending second step
+func newTwoFactorChallenge(user *models.User, step string) (*models.TwoFactorChallengeResponse, error) {
+	purpose := purposeTwoFactorVerify
+	if step == models.TwoFactorStepSetup {
+		purpose = purposeTwoFactorSetup
+	}
+
+	claims := &Claims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		Purpose:  purpose,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(challengeTokenLifetime).Unix(),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(signingKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		TwoFactorStep:     step,
+		ChallengeToken:    token,
+		ExpiresIn:         int(challengeTokenLifetime.Seconds()),
+	}, nil
+}
+
+// validateChallengeToken parses a challenge token and checks its purpose
+func validateChallengeToken(tokenString, purpose string) (*Claims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != purpose {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// ChallengeUsername returns the username of a challenge token, used to throttle the second step
+// It returns an empty string for invalid tokens
+func ChallengeUsername(tokenString string) string {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return ""
+	}
+	return claims.Username
+}
+
+// CompleteTwoFactorLogin verifies the second factor and returns the login tokens
+func CompleteTwoFactorLogin(challengeToken, code, recoveryCode string) (*models.LoginResponse, error) {
+	claims, err := validateChallengeToken(challengeToken, purposeTwoFactorVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := UserRepository.FindByID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := verifySecondFactor(user.ID, code, recoveryCode); err != nil {
+		return nil, err
+	}
+
+	return newLoginResponse(user)
+}
+
+// BeginTwoFactorSetupWithChallenge starts enrollment for a user whose role requires 2FA at login
+func BeginTwoFactorSetupWithChallenge(challengeToken string) (*models.TwoFactorSetupResponse, error) {
+	claims, err := validateChallengeToken(challengeToken, purposeTwoFactorSetup)
+	if err != nil {
+		return nil, err
+	}
+	return BeginTwoFactorSetup(claims.UserID)
+}
+
+// EnableTwoFactorWithChallenge finishes enrollment at login and returns the login tokens
+// together with the recovery codes, which are shown only once
+func EnableTwoFactorWithChallenge(challengeToken, code string) (*models.LoginResponse, []string, error) {
+	claims, err := validateChallengeToken(challengeToken, purposeTwoFactorSetup)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recoveryCodes, err := EnableTwoFactor(claims.UserID, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := UserRepository.FindByID(claims.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, ErrUserNotFound
+	}
+
+	response, err := newLoginResponse(user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, recoveryCodes, nil
+}
+
+// BeginTwoFactorSetup generates a new TOTP secret for the user
+// The secret is only used after it is confirmed with EnableTwoFactor
+func BeginTwoFactorSetup(userID uint) (*models.TwoFactorSetupResponse, error) {
+	user, err := UserRepository.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	twoFactor, err := twoFactorRepository().FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if twoFactor == nil {
+		twoFactor = &models.UserTwoFactor{UserID: userID}
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	twoFactor.Secret = secret
+	twoFactor.LastUsedStep = 0
+
+	if err := twoFactorRepository().Save(twoFactor); err != nil {
+		return nil, err
+	}
+
+	issuer := config.Get().Auth.TwoFactorIssuer
+	return &models.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: TOTPProvisioningURI(issuer, user.Username, secret),
+	}, nil
+}
+
+// EnableTwoFactor confirms the pending secret with a TOTP code and generates recovery codes
+func EnableTwoFactor(userID uint, code string) ([]string, error) {
+	twoFactor, err := twoFactorRepository().FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, ErrTwoFactorNotStarted
+	}
+	if twoFactor.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := ValidateTOTPCode(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	now := time.Now()
+	twoFactor.Enabled = true
+	twoFactor.EnabledAt = &now
+	twoFactor.LastUsedStep = step
+	if err := twoFactorRepository().Save(twoFactor); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Two-factor authentication enabled", "user_id", userID)
+	return replaceRecoveryCodes(userID)
+}
+
+// DisableTwoFactor removes the TOTP enrollment after verifying a TOTP or recovery code
+func DisableTwoFactor(userID uint, role, code string) error {
+	if IsTwoFactorRequired(role) {
+		return ErrTwoFactorRequired
+	}
+	if err := verifySecondFactor(userID, code, code); err != nil {
+		return err
+	}
+
+	slog.Info("Two-factor authentication disabled", "user_id", userID)
+	return twoFactorRepository().DeleteByUserID(userID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after verifying a TOTP code
+func RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	if err := verifySecondFactor(userID, code, ""); err != nil {
+		return nil, err
+	}
+	return replaceRecoveryCodes(userID)
+}
+
+// GetTwoFactorStatus returns the 2FA state of a user
+func GetTwoFactorStatus(userID uint, role string) (*models.TwoFactorStatus, error) {
+	status := &models.TwoFactorStatus{Required: IsTwoFactorRequired(role)}
+
+	twoFactor, err := twoFactorRepository().FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		return status, nil
+	}
+
+	remaining, err := twoFactorRepository().CountUnusedRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status.Enabled = true
+	status.EnabledAt = twoFactor.EnabledAt
+	status.RecoveryCodesRemaining = remaining
+	return status, nil
+}
+
+// verifySecondFactor accepts either a TOTP code or an unused recovery code
+func verifySecondFactor(userID uint, code, recoveryCode string) error {
+	twoFactor, err := twoFactorRepository().FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if code != "" {
+		if step, ok := ValidateTOTPCode(twoFactor.Secret, code, time.Now()); ok {
+			// Each time step can only be used once
+			fresh, err := twoFactorRepository().UpdateLastUsedStep(userID, step)
+			if err != nil {
+				return err
+			}
+			if fresh {
+				return nil
+			}
+		}
+	}
+
+	if recoveryCode != "" {
+		used, err := twoFactorRepository().UseRecoveryCode(userID, hashRecoveryCode(recoveryCode))
+		if err != nil {
+			return err
+		}
+		if used {
+			slog.Info("Recovery code used", "user_id", userID)
+			return nil
+		}
+	}
+
+	return ErrInvalidTwoFactorCode
+}
+
+// replaceRecoveryCodes generates and stores a new set of recovery codes
+func replaceRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err := twoFactorRepository().ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode returns a random code formatted as xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	raw := make([]byte, 7)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	encoded := strings.ToLower(totpEncoding.EncodeToString(raw))[:10]
+	return fmt.Sprintf("%s-%s", encoded[:5], encoded[5:]), nil
+}
+
+// hashRecoveryCode returns the stored digest of a recovery code
+// Recovery codes are random, so a fast hash is enough
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
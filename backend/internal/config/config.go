@@ -0,0 +1,183 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+)
+
+// Supported values of APP_ENV
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// Config is the configuration of the server and delctl
+// Every setting has an environment variable (env tag), a key in the config file (yaml tags of the
+// enclosing structs) and a default. Durations are given in the unit of their tag, or as Go
+// durations such as "90s".
+type Config struct {
+	Environment string `yaml:"environment" env:"APP_ENV" default:"development"`
+
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Login     LoginConfig     `yaml:"login"`
+	Campus    CampusConfig    `yaml:"campus"`
+	Sync      SyncConfig      `yaml:"sync"`
+	Log       LogConfig       `yaml:"log"`
+	Readiness ReadinessConfig `yaml:"readiness"`
+
+	// File is the config file that was loaded, empty when there is none
+	File string `yaml:"-"`
+
+	sources map[string]string
+}
+
+// ServerConfig configures the HTTP server
+type ServerConfig struct {
+	Port               string        `yaml:"port" env:"SERVER_PORT" default:"8080"`
+	GinMode            string        `yaml:"gin_mode" env:"GIN_MODE" default:"debug"`
+	CORSAllowedOrigins []string      `yaml:"cors_allowed_origins" env:"CORS_ALLOWED_ORIGINS" default:"*"`
+	MetricsToken       string        `yaml:"metrics_token" env:"METRICS_TOKEN" secret:"true"`
+	PublicURL          string        `yaml:"public_url" env:"SERVER_PUBLIC_URL"` // Base of links handed out, such as calendar feeds; taken from the request when empty
+	ReadHeaderTimeout  time.Duration `yaml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT_SECONDS" default:"10" unit:"s"`
+	ReadTimeout        time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT_SECONDS" default:"30" unit:"s"`
+	WriteTimeout       time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT_SECONDS" default:"60" unit:"s"`
+	IdleTimeout        time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT_SECONDS" default:"120" unit:"s"`
+	DrainDelay         time.Duration `yaml:"drain_delay" env:"SERVER_DRAIN_DELAY_SECONDS" default:"5" unit:"s"` // Time the readiness probe fails before connections are closed
+	ShutdownTimeout    time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT_SECONDS" default:"30" unit:"s"`
+	WorkerStopTimeout  time.Duration `yaml:"worker_stop_timeout" env:"SERVER_WORKER_STOP_TIMEOUT_SECONDS" default:"10" unit:"s"` // Time for a running scheduled sync to stop
+}
+
+// DatabaseConfig selects and configures the database
+type DatabaseConfig struct {
+	Driver   string `yaml:"driver" env:"DB_DRIVER" default:"postgres"`
+	Host     string `yaml:"host" env:"DB_HOST" default:"localhost"`
+	Port     string `yaml:"port" env:"DB_PORT" default:"5432"`
+	User     string `yaml:"user" env:"DB_USER" default:"postgres"`
+	Password string `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	Name     string `yaml:"name" env:"DB_NAME" default:"delpresence"`
+	SSLMode  string `yaml:"sslmode" env:"DB_SSLMODE" default:"disable"`
+	Path     string `yaml:"path" env:"DB_PATH" default:"delpresence.db"` // SQLite file
+}
+
+// AuthConfig configures tokens, the default admin and two-factor authentication
+type AuthConfig struct {
+	JWTSecret              string        `yaml:"jwt_secret" env:"JWT_SECRET" secret:"true"`
+	CreateDefaultAdmin     bool          `yaml:"create_default_admin" env:"CREATE_DEFAULT_ADMIN" default:"true"`
+	DefaultAdminPassword   string        `yaml:"default_admin_password" env:"DEFAULT_ADMIN_PASSWORD" default:"delpresence" secret:"true"`
+	TwoFactorIssuer        string        `yaml:"two_factor_issuer" env:"TWO_FACTOR_ISSUER" default:"DelPresence"`
+	TwoFactorRequiredRoles []string      `yaml:"two_factor_required_roles" env:"TWO_FACTOR_REQUIRED_ROLES"`
+	ImpersonationTokenTTL  time.Duration `yaml:"impersonation_token_ttl" env:"IMPERSONATION_TOKEN_MINUTES" default:"15" unit:"m"`
+}
+
+// LoginConfig configures the login limiter
+type LoginConfig struct {
+	LimiterStore       string        `yaml:"limiter_store" env:"LOGIN_LIMITER_STORE" default:"memory"`
+	BackoffAfter       int           `yaml:"backoff_after" env:"LOGIN_BACKOFF_AFTER" default:"3"`
+	BackoffBase        time.Duration `yaml:"backoff_base" env:"LOGIN_BACKOFF_BASE_SECONDS" default:"1" unit:"s"`
+	BackoffMax         time.Duration `yaml:"backoff_max" env:"LOGIN_BACKOFF_MAX_SECONDS" default:"60" unit:"s"`
+	UserMaxFailures    int           `yaml:"user_max_failures" env:"LOGIN_USER_MAX_FAILURES" default:"5"`
+	IPMaxFailures      int           `yaml:"ip_max_failures" env:"LOGIN_IP_MAX_FAILURES" default:"20"`
+	LockoutDuration    time.Duration `yaml:"lockout" env:"LOGIN_LOCKOUT_MINUTES" default:"15" unit:"m"`
+	MaxLockoutDuration time.Duration `yaml:"max_lockout" env:"LOGIN_MAX_LOCKOUT_MINUTES" default:"1440" unit:"m"`
+	FailureWindow      time.Duration `yaml:"failure_window" env:"LOGIN_FAILURE_WINDOW_MINUTES" default:"15" unit:"m"`
+}
+
+// CampusConfig configures access to the campus API (CIS)
+type CampusConfig struct {
+	APIUsername         string        `yaml:"api_username" env:"CAMPUS_API_USERNAME"`
+	APIPassword         string        `yaml:"api_password" env:"CAMPUS_API_PASSWORD" secret:"true"`
+	AuthURL             string        `yaml:"auth_url" env:"CAMPUS_AUTH_URL" default:"https://cis.del.ac.id/api/jwt-api/do-auth"`
+	ServiceAuthURL      string        `yaml:"service_auth_url" env:"CAMPUS_SERVICE_AUTH_URL" default:"https://cis-dev.del.ac.id/api/jwt-api/do-auth"`
+	AuthFallbackURL     string        `yaml:"auth_fallback_url" env:"CAMPUS_AUTH_FALLBACK_URL" default:"http://cis.del.ac.id/api/jwt-api/do-auth"`
+	StudentsURL         string        `yaml:"students_url" env:"CAMPUS_STUDENTS_URL" default:"https://cis.del.ac.id/api/library-api/mahasiswa?status=aktif"`
+	LecturersURL        string        `yaml:"lecturers_url" env:"CAMPUS_LECTURERS_URL" default:"https://cis.del.ac.id/api/library-api/dosen"`
+	EmployeesURL        string        `yaml:"employees_url" env:"CAMPUS_EMPLOYEES_URL" default:"https://cis.del.ac.id/api/library-api/pegawai"`
+	LoginTimeout        time.Duration `yaml:"login_timeout" env:"CAMPUS_LOGIN_TIMEOUT_SECONDS" default:"15" unit:"s"`
+	OfflineLoginEnabled bool          `yaml:"offline_login_enabled" env:"CAMPUS_OFFLINE_LOGIN_ENABLED" default:"true"`
+	OfflineGracePeriod  time.Duration `yaml:"offline_grace_period" env:"CAMPUS_OFFLINE_GRACE_HOURS" default:"72" unit:"h"`
+	OfflineTokenTTL     time.Duration `yaml:"offline_token_ttl" env:"CAMPUS_OFFLINE_TOKEN_HOURS" default:"2" unit:"h"`
+}
+
+// SyncConfig configures the scheduled campus syncs
+// Schedules take a cron expression, @hourly/@daily/@weekly, "@every <duration>" or "off"
+// The default schedules are spread out so the campus API is not hit by all syncs at once
+type SyncConfig struct {
+	Enabled           bool          `yaml:"enabled" env:"SYNC_SCHEDULER_ENABLED" default:"true"`
+	Jitter            time.Duration `yaml:"jitter" env:"SYNC_JITTER_SECONDS" default:"120" unit:"s"`
+	FailureAlertAfter int           `yaml:"failure_alert_after" env:"SYNC_FAILURE_ALERT_AFTER" default:"3"`
+	StaleAfter        time.Duration `yaml:"stale_after" env:"SYNC_STALE_AFTER_HOURS" default:"24" unit:"h"`
+	MaxMissingPercent int           `yaml:"max_missing_percent" env:"SYNC_MAX_MISSING_PERCENT" default:"10"`
+	MassChangePercent int           `yaml:"mass_change_percent" env:"SYNC_MASS_CHANGE_PERCENT" default:"30"`
+	PreviewTTL        time.Duration `yaml:"preview_ttl" env:"SYNC_PREVIEW_TTL_MINUTES" default:"30" unit:"m"`
+	StudentsSchedule  string        `yaml:"students_schedule" env:"SYNC_STUDENTS_SCHEDULE" default:"0 */6 * * *"`
+	LecturersSchedule string        `yaml:"lecturers_schedule" env:"SYNC_LECTURERS_SCHEDULE" default:"30 2 * * *"`
+	EmployeesSchedule string        `yaml:"employees_schedule" env:"SYNC_EMPLOYEES_SCHEDULE" default:"45 2 * * *"`
+}
+
+// Schedule returns the schedule of a sync entity
+func (c SyncConfig) Schedule(entity string) string {
+	switch entity {
+	case models.SyncEntityStudents:
+		return c.StudentsSchedule
+	case models.SyncEntityLecturers:
+		return c.LecturersSchedule
+	case models.SyncEntityEmployees:
+		return c.EmployeesSchedule
+	}
+	return "off"
+}
+
+// LogConfig configures logging
+type LogConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" default:"info"`
+	Format string `yaml:"format" env:"LOG_FORMAT" default:"json"`
+}
+
+// ReadinessConfig configures the readiness probe
+type ReadinessConfig struct {
+	CheckCampus bool   `yaml:"check_campus" env:"READINESS_CHECK_CAMPUS" default:"true"`
+	CampusURL   string `yaml:"campus_url" env:"READINESS_CAMPUS_URL" default:"https://cis.del.ac.id"`
+}
+
+// IsProduction reports whether APP_ENV is production
+func (c *Config) IsProduction() bool {
+	return strings.EqualFold(c.Environment, EnvProduction)
+}
+
+var (
+	current   *Config
+	currentMu sync.Mutex
+)
+
+// Load reads the configuration and makes it the one returned by Get
+// The configuration is returned even with an error, with defaults for the invalid settings
+func Load() (*Config, error) {
+	cfg, err := load()
+
+	currentMu.Lock()
+	current = cfg
+	currentMu.Unlock()
+	return cfg, err
+}
+
+// Get returns the loaded configuration, loading it on first use when Load was not called
+func Get() *Config {
+	currentMu.Lock()
+	cfg := current
+	currentMu.Unlock()
+	if cfg != nil {
+		return cfg
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		slog.Warn("Invalid configuration, using defaults for invalid settings", "error", err)
+	}
+	return cfg
+}
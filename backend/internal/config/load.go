@@ -0,0 +1,280 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Sources of a setting's value, in increasing precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// redacted replaces secret values in Settings
+const redacted = "[REDACTED]"
+
+// durationUnits are the units a duration tag can declare
+var durationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// Setting is the effective value of one setting, as shown on the admin config endpoint
+type Setting struct {
+	Key    string `json:"key"`  // Environment variable
+	Path   string `json:"path"` // Key in the config file
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Secret bool   `json:"secret"`
+}
+
+// View is the effective configuration as shown on the admin config endpoint
+type View struct {
+	Environment string    `json:"environment"`
+	File        string    `json:"file"`
+	Settings    []Setting `json:"settings"`
+}
+
+// field is one setting of the Config struct
+type field struct {
+	path   string
+	env    string
+	def    string
+	unit   time.Duration
+	secret bool
+	value  reflect.Value
+}
+
+// load builds the configuration from the defaults, the file named by CONFIG_FILE and the environment
+func load() (*Config, error) {
+	cfg := &Config{sources: make(map[string]string)}
+	fields := cfg.fields()
+	var errs []error
+
+	for _, f := range fields {
+		if err := f.set(f.def); err != nil {
+			errs = append(errs, fmt.Errorf("default of %s: %w", f.env, err))
+		}
+		cfg.sources[f.env] = SourceDefault
+	}
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		cfg.File = path
+		values, err := readFile(path)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		known := make(map[string]bool, len(fields))
+		for _, f := range fields {
+			known[f.path] = true
+			raw, ok := values[f.path]
+			if !ok {
+				continue
+			}
+			if err := f.set(raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s in %s: %w", f.path, path, err))
+				continue
+			}
+			cfg.sources[f.env] = SourceFile
+		}
+		for key := range values {
+			if !known[key] {
+				errs = append(errs, fmt.Errorf("unknown setting %s in %s", key, path))
+			}
+		}
+	}
+
+	for _, f := range fields {
+		raw, ok := os.LookupEnv(f.env)
+		if !ok || raw == "" {
+			continue
+		}
+		if err := f.set(raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+			continue
+		}
+		cfg.sources[f.env] = SourceEnv
+	}
+
+	return cfg, errors.Join(errs...)
+}
+
+// readFile reads a YAML config file into a map from dotted keys ("server.port") to raw values
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var tree map[string]interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flatten("", tree, values)
+	return values, nil
+}
+
+// flatten converts nested YAML maps to dotted keys, lists become comma-separated values
+func flatten(prefix string, tree map[string]interface{}, values map[string]string) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(key, v, values)
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+		case nil:
+			values[key] = ""
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// fields lists the settings of the configuration
+func (c *Config) fields() []field {
+	var fields []field
+	collectFields("", reflect.ValueOf(c).Elem(), &fields)
+	return fields
+}
+
+// collectFields walks a config struct and appends every field with an env tag
+func collectFields(prefix string, value reflect.Value, fields *[]field) {
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		name := strings.Split(structField.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		env := structField.Tag.Get("env")
+		if env == "" {
+			if structField.Type.Kind() == reflect.Struct {
+				collectFields(path, value.Field(i), fields)
+			}
+			continue
+		}
+		*fields = append(*fields, field{
+			path:   path,
+			env:    env,
+			def:    structField.Tag.Get("default"),
+			unit:   durationUnits[structField.Tag.Get("unit")],
+			secret: structField.Tag.Get("secret") == "true",
+			value:  value.Field(i),
+		})
+	}
+}
+
+// set parses a raw value into the field
+func (f field) set(raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	if f.value.Type() == reflect.TypeOf(time.Duration(0)) {
+		if raw == "" {
+			f.value.SetInt(0)
+			return nil
+		}
+		if n, err := strconv.Atoi(raw); err == nil {
+			f.value.SetInt(int64(time.Duration(n) * f.unit))
+			return nil
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		f.value.SetInt(int64(d))
+		return nil
+	}
+
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(raw)
+	case reflect.Int:
+		if raw == "" {
+			f.value.SetInt(0)
+			return nil
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		f.value.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		f.value.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		f.value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", f.value.Type())
+	}
+	return nil
+}
+
+// format returns the value of the field as it would be written in the environment
+func (f field) format() string {
+	switch v := f.value.Interface().(type) {
+	case time.Duration:
+		return v.String()
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Settings lists the effective settings and where they come from, with secrets redacted
+func (c *Config) Settings() []Setting {
+	var settings []Setting
+	for _, f := range c.fields() {
+		value := f.format()
+		if f.secret && value != "" {
+			value = redacted
+		}
+		settings = append(settings, Setting{
+			Key:    f.env,
+			Path:   f.path,
+			Value:  value,
+			Source: c.sources[f.env],
+			Secret: f.secret,
+		})
+	}
+	sort.SliceStable(settings, func(i, j int) bool { return settings[i].Path < settings[j].Path })
+	return settings
+}
+
+// View returns the environment, the config file and the settings, with secrets redacted
+func (c *Config) View() View {
+	return View{Environment: c.Environment, File: c.File, Settings: c.Settings()}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/utils"
+)
+
+// defaultSecrets are secrets from the examples and compose files, refused in production
+var defaultSecrets = map[string]bool{
+	"secret":                 true,
+	"changeme":               true,
+	"your_secret_key":        true,
+	"delpresence_secret_key": true,
+	"postgres":               true,
+	"delpresence":            true,
+}
+
+// Validate checks the configuration before the server starts and returns every problem found
+// An empty JWT secret is always refused; default secrets and credentials only in production
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(strings.EqualFold(c.Environment, EnvDevelopment) || c.IsProduction(),
+		"APP_ENV must be %q or %q, got %q", EnvDevelopment, EnvProduction, c.Environment)
+	check(c.Auth.JWTSecret != "", "JWT_SECRET must be set")
+	check(c.Server.Port != "", "SERVER_PORT must be set")
+	driver := strings.ToLower(c.Database.Driver)
+	check(driver == "postgres" || driver == "sqlite", "DB_DRIVER must be \"postgres\" or \"sqlite\", got %q", c.Database.Driver)
+	store := strings.ToLower(c.Login.LimiterStore)
+	check(store == "memory" || store == "database" || store == "db",
+		"LOGIN_LIMITER_STORE must be \"memory\" or \"database\", got %q", c.Login.LimiterStore)
+	check(c.Server.DrainDelay >= 0, "SERVER_DRAIN_DELAY_SECONDS must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "SERVER_SHUTDOWN_TIMEOUT_SECONDS must be positive")
+	check(c.Server.WorkerStopTimeout > 0, "SERVER_WORKER_STOP_TIMEOUT_SECONDS must be positive")
+	if c.Server.PublicURL != "" {
+		publicURL, err := url.Parse(c.Server.PublicURL)
+		check(err == nil && (publicURL.Scheme == "http" || publicURL.Scheme == "https") && publicURL.Host != "",
+			"SERVER_PUBLIC_URL must be an http or https URL, got %q", c.Server.PublicURL)
+	}
+	check(c.Sync.MaxMissingPercent >= 0 && c.Sync.MaxMissingPercent <= 100, "SYNC_MAX_MISSING_PERCENT must be between 0 and 100")
+	for _, entity := range models.SyncEntities {
+		if spec := c.Sync.Schedule(entity); !strings.EqualFold(spec, "off") {
+			_, err := utils.ParseSchedule(spec)
+			check(err == nil, "SYNC_%s_SCHEDULE is invalid: %v", strings.ToUpper(entity), err)
+		}
+	}
+	check(c.Sync.MassChangePercent >= 0 && c.Sync.MassChangePercent <= 100, "SYNC_MASS_CHANGE_PERCENT must be between 0 and 100")
+
+	if c.IsProduction() {
+		check(!defaultSecrets[c.Auth.JWTSecret], "JWT_SECRET must not be a default value in production")
+		check(len(c.Auth.JWTSecret) >= 32 || c.Auth.JWTSecret == "", "JWT_SECRET must be at least 32 characters in production")
+		check(!c.Auth.CreateDefaultAdmin || !defaultSecrets[c.Auth.DefaultAdminPassword],
+			"DEFAULT_ADMIN_PASSWORD must be changed in production, or CREATE_DEFAULT_ADMIN set to false")
+		if driver == "postgres" {
+			check(c.Database.Password != "" && !defaultSecrets[c.Database.Password], "DB_PASSWORD must not be empty or a default value in production")
+		}
+		check(c.Campus.APIUsername != "" && c.Campus.APIPassword != "", "CAMPUS_API_USERNAME and CAMPUS_API_PASSWORD must be set in production")
+		check(!defaultSecrets[c.Campus.APIPassword], "CAMPUS_API_PASSWORD must not be a default value in production")
+	}
+
+	return errors.Join(errs...)
+}
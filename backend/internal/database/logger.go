@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold is the duration after which a query is logged as slow
+const slowQueryThreshold = time.Second
+
+// sqlLogger writes GORM's logs through slog, so they carry the request ID of the context
+// Queries are logged at debug level without their parameters, slow queries at warn and errors at error
+type sqlLogger struct {
+	level logger.LogLevel
+}
+
+// newSQLLogger returns the GORM logger for the configured log level
+// Every query is only traced when LOG_LEVEL is debug
+func newSQLLogger() logger.Interface {
+	if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		return &sqlLogger{level: logger.Info}
+	}
+	return &sqlLogger{level: logger.Warn}
+}
+
+func (l *sqlLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &sqlLogger{level: level}
+}
+
+func (l *sqlLogger) Info(ctx context.Context, message string, data ...interface{}) {
+	if l.level >= logger.Info {
+		slog.InfoContext(ctx, fmt.Sprintf(message, data...))
+	}
+}
+
+func (l *sqlLogger) Warn(ctx context.Context, message string, data ...interface{}) {
+	if l.level >= logger.Warn {
+		slog.WarnContext(ctx, fmt.Sprintf(message, data...))
+	}
+}
+
+func (l *sqlLogger) Error(ctx context.Context, message string, data ...interface{}) {
+	if l.level >= logger.Error {
+		slog.ErrorContext(ctx, fmt.Sprintf(message, data...))
+	}
+}
+
+func (l *sqlLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		slog.ErrorContext(ctx, "SQL error", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds(), "error", err)
+	case elapsed > slowQueryThreshold && l.level >= logger.Warn:
+		sql, rows := fc()
+		slog.WarnContext(ctx, "Slow SQL", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	case l.level >= logger.Info:
+		sql, rows := fc()
+		slog.DebugContext(ctx, "SQL", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	}
+}
+
+// ParamsFilter drops the parameters of logged queries, they can hold tokens and passwords
+func (l *sqlLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
@@ -0,0 +1,260 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Migration is one versioned schema change
+// Up and Down run inside a transaction together with the bookkeeping in schema_migrations
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// ErrSchemaBehind is returned when the database has migrations that have not been applied yet
+var ErrSchemaBehind = errors.New("database schema is behind")
+
+// migrationLockKey identifies the advisory lock held while migrating, so replicas never migrate concurrently
+const migrationLockKey int64 = 7_310_240_316
+
+// sortedMigrations returns the registered migrations ordered by version
+func sortedMigrations() []Migration {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return sorted
+}
+
+// LatestVersion returns the version of the newest known migration
+func LatestVersion() int {
+	sorted := sortedMigrations()
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[len(sorted)-1].Version
+}
+
+// MigrateUp applies all pending migrations
+func MigrateUp() error {
+	return MigrateTo(LatestVersion())
+}
+
+// MigrateDown reverts the given number of most recently applied migrations
+func MigrateDown(steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("steps must be at least 1")
+	}
+	return withMigrationLock(func() error {
+		applied, err := appliedMigrations()
+		if err != nil {
+			return err
+		}
+
+		sorted := sortedMigrations()
+		for i := len(sorted) - 1; i >= 0 && steps > 0; i-- {
+			if _, ok := applied[sorted[i].Version]; !ok {
+				continue
+			}
+			if err := revertMigration(sorted[i]); err != nil {
+				return err
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// MigrateTo applies or reverts migrations until exactly the migrations up to version are applied
+func MigrateTo(version int) error {
+	if version < 0 || version > LatestVersion() {
+		return fmt.Errorf("unknown schema version %d, latest is %d", version, LatestVersion())
+	}
+	return withMigrationLock(func() error {
+		applied, err := appliedMigrations()
+		if err != nil {
+			return err
+		}
+
+		sorted := sortedMigrations()
+
+		// Revert newer migrations first, newest first
+		for i := len(sorted) - 1; i >= 0; i-- {
+			m := sorted[i]
+			if _, ok := applied[m.Version]; ok && m.Version > version {
+				if err := revertMigration(m); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Then apply missing migrations, oldest first
+		for _, m := range sorted {
+			if _, ok := applied[m.Version]; !ok && m.Version <= version {
+				if err := applyMigration(m); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// GetMigrationStatus lists all known migrations and whether they have been applied
+func GetMigrationStatus() ([]models.MigrationState, error) {
+	applied, err := appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]models.MigrationState, 0, len(migrations))
+	for _, m := range sortedMigrations() {
+		state := models.MigrationState{Version: m.Version, Name: m.Name}
+		if record, ok := applied[m.Version]; ok {
+			appliedAt := record.AppliedAt
+			state.Applied = true
+			state.AppliedAt = &appliedAt
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// CheckSchema returns ErrSchemaBehind when any known migration has not been applied
+func CheckSchema() error {
+	applied, err := appliedMigrations()
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w: %d of %d migrations pending, run \"migrate up\"", ErrSchemaBehind, pending, len(migrations))
+	}
+
+	for version := range applied {
+		if version > LatestVersion() {
+			slog.Warn("Database schema version is newer than this build", "version", version, "latest", LatestVersion())
+		}
+	}
+	return nil
+}
+
+// SchemaVersion returns the version of the newest applied migration, 0 for an empty database
+func SchemaVersion() (int, error) {
+	applied, err := appliedMigrations()
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+// appliedMigrations loads the applied migrations by version
+func appliedMigrations() (map[int]models.SchemaMigration, error) {
+	applied := make(map[int]models.SchemaMigration)
+	if !DB.Migrator().HasTable(&models.SchemaMigration{}) {
+		return applied, nil
+	}
+
+	var records []models.SchemaMigration
+	if err := DB.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema migrations: %w", err)
+	}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// applyMigration runs the up step of a migration and records it
+func applyMigration(m Migration) error {
+	slog.Info("Applying migration", "version", m.Version, "name", m.Name)
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := m.Up(tx); err != nil {
+			return err
+		}
+		return tx.Create(&models.SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+	}
+	return nil
+}
+
+// revertMigration runs the down step of a migration and removes its record
+func revertMigration(m Migration) error {
+	slog.Info("Reverting migration", "version", m.Version, "name", m.Name)
+	if m.Down == nil {
+		return fmt.Errorf("migration %d (%s) cannot be reverted", m.Version, m.Name)
+	}
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := m.Down(tx); err != nil {
+			return err
+		}
+		return tx.Delete(&models.SchemaMigration{}, m.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("reverting migration %d (%s) failed: %w", m.Version, m.Name, err)
+	}
+	return nil
+}
+
+// withMigrationLock runs fn while holding the migration lock
+// On PostgreSQL this is a session advisory lock, so a replica starting at the same time waits
+// and then sees the migrations as applied
+func withMigrationLock(fn func() error) error {
+	if DB.Dialector.Name() != "postgres" {
+		if err := DB.AutoMigrate(&models.SchemaMigration{}); err != nil {
+			return err
+		}
+		return fn()
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+
+	// Advisory locks belong to a session, so the lock is taken on a dedicated connection
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	slog.Info("Acquiring migration lock")
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			slog.Error("Error releasing migration lock", "error", err)
+		}
+	}()
+
+	if err := DB.AutoMigrate(&models.SchemaMigration{}); err != nil {
+		return err
+	}
+	return fn()
+}
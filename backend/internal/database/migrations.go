@@ -0,0 +1,210 @@
+package database
+
+import (
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// migrations lists every schema change in version order
+// Never edit or renumber a migration that has been released, add a new one instead.
+// Later migrations must not fail when the baseline already created their columns from the
+// current models, so prefer the Migrator API (HasColumn, HasIndex) over raw DDL.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "baseline schema",
+		Up: func(tx *gorm.DB) error {
+			// Creates the tables of a new database, and only adds what is missing on
+			// databases created by the old startup AutoMigrate
+			return tx.AutoMigrate(baselineModels()...)
+		},
+		Down: func(tx *gorm.DB) error {
+			tables := baselineModels()
+			for i := len(tables) - 1; i >= 0; i-- {
+				if err := tx.Migrator().DropTable(tables[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 2,
+		Name:    "drop legacy academic year name index",
+		Up: func(tx *gorm.DB) error {
+			// Academic years are unique per name and semester, by the index declared on the model
+			if tx.Migrator().HasIndex(&models.AcademicYear{}, "idx_academic_years_name_deleted_at") {
+				return tx.Migrator().DropIndex(&models.AcademicYear{}, "idx_academic_years_name_deleted_at")
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// The legacy index made names unique across semesters, it is not restored
+			return nil
+		},
+	},
+	{
+		Version: 3,
+		Name:    "unique student attendance per session",
+		Up: func(tx *gorm.DB) error {
+			// Concurrent check-ins could record a student twice in a session. The first check-in is
+			// kept, earlier rows win ties, and the other rows are soft-deleted.
+			err := tx.Exec(`UPDATE student_attendances SET deleted_at = ?
+				WHERE deleted_at IS NULL AND id <> (
+					SELECT keep.id FROM student_attendances keep
+					WHERE keep.deleted_at IS NULL
+						AND keep.attendance_session_id = student_attendances.attendance_session_id
+						AND keep.student_id = student_attendances.student_id
+					ORDER BY CASE WHEN keep.check_in_time IS NULL THEN 1 ELSE 0 END, keep.check_in_time, keep.id
+					LIMIT 1)`, time.Now()).Error
+			if err != nil {
+				return err
+			}
+
+			// Created here and not from a model tag, so the baseline of an old database never
+			// builds it before the duplicates are removed. Partial, so soft-deleted rows do not count.
+			return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_student_attendances_session_student
+				ON student_attendances (attendance_session_id, student_id) WHERE deleted_at IS NULL`).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Exec(`DROP INDEX IF EXISTS idx_student_attendances_session_student`).Error
+		},
+	},
+	{
+		Version: 4,
+		Name:    "idempotency records",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasTable(&models.IdempotencyRecord{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&models.IdempotencyRecord{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.IdempotencyRecord{})
+		},
+	},
+	{
+		Version: 5,
+		Name:    "schedule conflict reviews",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasTable(&models.ScheduleConflictReview{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&models.ScheduleConflictReview{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.ScheduleConflictReview{})
+		},
+	},
+	{
+		Version: 6,
+		Name:    "lecturer availability",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasTable(&models.LecturerAvailability{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&models.LecturerAvailability{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.LecturerAvailability{})
+		},
+	},
+	{
+		Version: 7,
+		Name:    "timetable drafts",
+		Up: func(tx *gorm.DB) error {
+			for _, model := range []interface{}{&models.TimetableDraft{}, &models.TimetableDraftEntry{}} {
+				if tx.Migrator().HasTable(model) {
+					continue
+				}
+				if err := tx.Migrator().CreateTable(model); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.TimetableDraftEntry{}, &models.TimetableDraft{})
+		},
+	},
+	{
+		Version: 8,
+		Name:    "holidays and schedule exceptions",
+		Up: func(tx *gorm.DB) error {
+			for _, model := range []interface{}{&models.Holiday{}, &models.ScheduleException{}} {
+				if tx.Migrator().HasTable(model) {
+					continue
+				}
+				if err := tx.Migrator().CreateTable(model); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.ScheduleException{}, &models.Holiday{})
+		},
+	},
+	{
+		Version: 9,
+		Name:    "calendar feeds",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasTable(&models.CalendarFeed{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&models.CalendarFeed{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.CalendarFeed{})
+		},
+	},
+	{
+		Version: 10,
+		Name:    "sync previews",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasTable(&models.SyncPreviewRecord{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&models.SyncPreviewRecord{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.SyncPreviewRecord{})
+		},
+	},
+}
+
+// baselineModels returns the models of the baseline schema in dependency order
+func baselineModels() []interface{} {
+	return []interface{}{
+		&models.User{},
+		&models.Faculty{},
+		&models.StudyProgram{},
+		&models.Student{},
+		&models.Lecturer{},
+		&models.Employee{},
+		&models.Admin{},
+		&models.Building{},
+		&models.Room{},
+		&models.AcademicYear{},
+		&models.Course{},
+		&models.StudentGroup{},
+		&models.StudentToGroup{},
+		&models.LecturerAssignment{},
+		&models.TeachingAssistantAssignment{},
+		&models.CourseSchedule{},
+		&models.AttendanceSession{},
+		&models.StudentAttendance{},
+		&models.StudentFace{},
+		&models.LoginAttempt{},
+		&models.UserTwoFactor{},
+		&models.RecoveryCode{},
+		&models.CampusCredential{},
+		&models.AuditLog{},
+		&models.APIKey{},
+		&models.SyncRun{},
+		&models.SyncRecordError{},
+		&models.StudentGroupMembershipHistory{},
+	}
+}
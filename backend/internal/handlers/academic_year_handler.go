@@ -3,7 +3,6 @@ package handlers
 import (
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/services"
@@ -22,28 +21,31 @@ func NewAcademicYearHandler() *AcademicYearHandler {
 	}
 }
 
-// GetAllAcademicYears returns all academic years
+// GetAllAcademicYears returns one page of academic years, see parseListQuery for the query parameters
+// With stats=true each record comes with its statistics
 func (h *AcademicYearHandler) GetAllAcademicYears(c *gin.Context) {
-	stats := c.Query("stats")
-	var result interface{}
-	var err error
-
-	if stats == "true" {
-		result, err = h.service.GetAllAcademicYearsWithStats()
-	} else {
-		result, err = h.service.GetAllAcademicYears()
+	q, err := parseListQuery(c, nil)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
 	}
 
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if c.Query("stats") == "true" {
+		page, err := h.service.ListAcademicYearsWithStats(q)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		respondList(c, "Academic years retrieved successfully", q, page)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Academic years retrieved successfully",
-		"data":    result,
-	})
+	page, err := h.service.ListAcademicYears(q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	respondList(c, "Academic years retrieved successfully", q, page)
 }
 
 // GetAcademicYearByID returns an academic year by ID
@@ -51,18 +53,18 @@ func (h *AcademicYearHandler) GetAcademicYearByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	academicYear, err := h.service.GetAcademicYearByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	if academicYear == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Academic year not found"})
+		respondErrorf(c, http.StatusNotFound, "Academic year not found")
 		return
 	}
 
@@ -78,12 +80,12 @@ func (h *AcademicYearHandler) CreateAcademicYear(c *gin.Context) {
 	var academicYear models.AcademicYear
 
 	if err := c.ShouldBindJSON(&academicYear); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	if err := h.service.CreateAcademicYear(&academicYear); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -99,20 +101,20 @@ func (h *AcademicYearHandler) UpdateAcademicYear(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	var academicYear models.AcademicYear
 	if err := c.ShouldBindJSON(&academicYear); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	academicYear.ID = uint(id)
 
 	if err := h.service.UpdateAcademicYear(&academicYear); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -128,17 +130,12 @@ func (h *AcademicYearHandler) DeleteAcademicYear(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	if err := h.service.DeleteAcademicYear(uint(id)); err != nil {
-		// Check if the error is about dependencies (courses, assignments, etc.)
-		if strings.Contains(err.Error(), "cannot delete academic year: it is being used by") {
-			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
-		}
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
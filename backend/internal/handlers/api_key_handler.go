@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles admin requests for managing API keys
+type APIKeyHandler struct {
+	service *services.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler() *APIKeyHandler {
+	return &APIKeyHandler{
+		service: services.NewAPIKeyService(),
+	}
+}
+
+// GetAllAPIKeys returns one page of API keys without their secrets, see parseListQuery for the query parameters
+func (h *APIKeyHandler) GetAllAPIKeys(c *gin.Context) {
+	q, err := parseListQuery(c, nil)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := h.service.ListKeys(q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondList(c, "API keys retrieved successfully", q, page)
+}
+
+// GetAPIKeyByID returns an API key by ID
+func (h *APIKeyHandler) GetAPIKeyByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	key, err := h.service.GetKeyByID(uint(id))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "API key retrieved successfully",
+		"data":    key,
+	})
+}
+
+// CreateAPIKey creates a new API key
+// The plaintext key is only returned in this response
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req models.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	created, err := h.service.CreateKey(req, userID)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "API key created successfully, store it now as it will not be shown again",
+		"data":    created,
+	})
+}
+
+// RotateAPIKey replaces an API key with a new one that has the same scopes
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	var req models.APIKeyRotateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if req.GraceMinutes < 0 {
+		respondErrorf(c, http.StatusBadRequest, "grace_minutes cannot be negative")
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	created, err := h.service.RotateKey(uint(id), req.GraceMinutes, userID)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "API key rotated successfully, store it now as it will not be shown again",
+		"data":    created,
+	})
+}
+
+// RevokeAPIKey revokes an API key immediately
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	if err := h.service.RevokeKey(uint(id)); err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "API key revoked successfully",
+	})
+}
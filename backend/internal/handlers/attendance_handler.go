@@ -32,22 +32,17 @@ func (h *AttendanceHandler) CreateAttendanceSession(c *gin.Context) {
 	userID := c.MustGet("userID").(uint)
 
 	// Parse request
-	var req struct {
-		CourseScheduleID uint                   `json:"course_schedule_id"`
-		Type             string                 `json:"type"`
-		Date             string                 `json:"date"`
-		Settings         map[string]interface{} `json:"settings"`
-	}
+	var req models.AttendanceSessionRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	// Convert date string to time.Time
 	date, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use YYYY-MM-DD"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid date format, use YYYY-MM-DD")
 		return
 	}
 
@@ -63,21 +58,21 @@ func (h *AttendanceHandler) CreateAttendanceSession(c *gin.Context) {
 	case "BOTH":
 		attendanceType = models.AttendanceTypeBoth
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attendance type"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid attendance type")
 		return
 	}
 
 	// Create the session
-	session, err := h.attendanceService.CreateAttendanceSession(userID, req.CourseScheduleID, date, attendanceType, req.Settings)
+	session, err := h.attendanceService.CreateAttendanceSession(c.Request.Context(), userID, req.CourseScheduleID, date, attendanceType, req.Settings)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	// Get the response format
 	response, err := h.attendanceService.GetSessionDetails(session.ID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Session created but error retrieving details"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("session created but error retrieving details: %w", err))
 		return
 	}
 
@@ -91,12 +86,9 @@ func (h *AttendanceHandler) GetActiveAttendanceSessions(c *gin.Context) {
 	userID := c.MustGet("userID").(uint)
 
 	// Get active sessions
-	sessions, err := h.attendanceService.GetActiveSessionsForUser(userID)
+	sessions, err := h.attendanceService.GetActiveSessionsForUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get active sessions",
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get active sessions: %w", err))
 		return
 	}
 
@@ -107,7 +99,8 @@ func (h *AttendanceHandler) GetActiveAttendanceSessions(c *gin.Context) {
 	})
 }
 
-// GetAttendanceSessions gets attendance sessions for the authenticated lecturer within a date range
+// GetAttendanceSessions gets one page of attendance sessions for the authenticated lecturer within a date range
+// See parseListQuery for the pagination, filter and sort parameters
 func (h *AttendanceHandler) GetAttendanceSessions(c *gin.Context) {
 	// Extract lecturer ID from authenticated user
 	userID := c.MustGet("userID").(uint)
@@ -127,28 +120,34 @@ func (h *AttendanceHandler) GetAttendanceSessions(c *gin.Context) {
 	// Parse dates
 	startDate, err := time.Parse("2006-01-02", startDateStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date format, use YYYY-MM-DD"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid start_date format, use YYYY-MM-DD")
 		return
 	}
 
 	endDate, err := time.Parse("2006-01-02", endDateStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date format, use YYYY-MM-DD"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid end_date format, use YYYY-MM-DD")
 		return
 	}
 
 	// Set end time to end of day
 	endDate = endDate.Add(24*time.Hour - time.Second)
 
+	q, err := parseListQuery(c, nil)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
 	// Get sessions
-	sessions, err := h.attendanceService.GetSessionsByDateRange(userID, startDate, endDate)
+	sessions, err := h.attendanceService.ListSessionsByDateRange(c.Request.Context(), userID, startDate, endDate, q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	// Return sessions
-	c.JSON(http.StatusOK, sessions)
+	// Return the page of sessions
+	respondList(c, "Attendance sessions retrieved successfully", q, sessions)
 }
 
 // GetAttendanceSessionDetails gets detailed information for a specific attendance session
@@ -159,14 +158,14 @@ func (h *AttendanceHandler) GetAttendanceSessionDetails(c *gin.Context) {
 	// Extract session ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	// Get session details
 	session, err := h.attendanceService.GetSessionDetails(uint(sessionID), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -182,13 +181,13 @@ func (h *AttendanceHandler) CloseAttendanceSession(c *gin.Context) {
 	// Extract session ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	// Close the session
 	if err := h.attendanceService.CloseAttendanceSession(uint(sessionID), userID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -204,13 +203,13 @@ func (h *AttendanceHandler) CancelAttendanceSession(c *gin.Context) {
 	// Extract session ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	// Cancel the session
 	if err := h.attendanceService.CancelAttendanceSession(uint(sessionID), userID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -226,14 +225,14 @@ func (h *AttendanceHandler) GetStudentAttendances(c *gin.Context) {
 	// Extract session ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	// Get student attendances
 	attendances, err := h.attendanceService.GetStudentAttendances(uint(sessionID), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -249,25 +248,21 @@ func (h *AttendanceHandler) MarkStudentAttendance(c *gin.Context) {
 	// Extract session ID and student ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	studentID, err := strconv.ParseUint(c.Param("studentId"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student ID")
 		return
 	}
 
 	// Parse request
-	var req struct {
-		Status             string `json:"status"`
-		Notes              string `json:"notes"`
-		VerificationMethod string `json:"verification_method"`
-	}
+	var req models.MarkAttendanceRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
@@ -283,13 +278,13 @@ func (h *AttendanceHandler) MarkStudentAttendance(c *gin.Context) {
 	case "EXCUSED":
 		status = models.StudentAttendanceStatusExcused
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attendance status"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid attendance status")
 		return
 	}
 
 	// Mark student attendance
 	if err := h.attendanceService.MarkStudentAttendance(uint(sessionID), uint(studentID), status, req.VerificationMethod, req.Notes, &userID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -305,14 +300,14 @@ func (h *AttendanceHandler) GetAttendanceStatistics(c *gin.Context) {
 	// Extract course schedule ID from URL
 	courseScheduleID, err := strconv.ParseUint(c.Param("courseScheduleId"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course schedule ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid course schedule ID")
 		return
 	}
 
 	// Get attendance statistics
 	stats, err := h.attendanceService.GetAttendanceStatistics(uint(courseScheduleID), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -328,14 +323,14 @@ func (h *AttendanceHandler) GetQRCode(c *gin.Context) {
 	// Extract session ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	// Get session details to verify ownership and retrieve QR code data
 	session, err := h.attendanceService.GetSessionDetails(uint(sessionID), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -354,21 +349,21 @@ func (h *AttendanceHandler) DownloadAttendanceReport(c *gin.Context) {
 	// Extract session ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	// Get session details
 	session, err := h.attendanceService.GetSessionDetails(uint(sessionID), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
 	// Get all student attendances for this session
 	attendances, err := h.attendanceService.GetStudentAttendances(uint(sessionID), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve attendance data"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to retrieve attendance data: %w", err))
 		return
 	}
 
@@ -378,7 +373,7 @@ func (h *AttendanceHandler) DownloadAttendanceReport(c *gin.Context) {
 	// Create main summary sheet
 	summarySheet, err := file.AddSheet("Summary")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create Excel sheet"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to create Excel sheet: %w", err))
 		return
 	}
 
@@ -475,7 +470,7 @@ func (h *AttendanceHandler) DownloadAttendanceReport(c *gin.Context) {
 	// Create detailed attendance sheet
 	detailSheet, err := file.AddSheet("Daftar Hadir")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create detail sheet"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to create detail sheet: %w", err))
 		return
 	}
 
@@ -622,7 +617,7 @@ func (h *AttendanceHandler) DownloadAttendanceReport(c *gin.Context) {
 	// Write file to response
 	err = file.Write(c.Writer)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate Excel file"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to generate Excel file: %w", err))
 		return
 	}
 }
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditLogHandler handles HTTP requests related to the audit log
+type AuditLogHandler struct {
+	service *services.AuditLogService
+}
+
+// NewAuditLogHandler creates a new audit log handler
+func NewAuditLogHandler() *AuditLogHandler {
+	return &AuditLogHandler{
+		service: services.NewAuditLogService(),
+	}
+}
+
+// auditLogFilterAliases are the plain filter parameters of the audit log
+var auditLogFilterAliases = map[string]string{
+	"action":        "action",
+	"actor_user_id": "actor_user_id",
+}
+
+// GetAuditLogs returns one page of audit log entries, see parseListQuery for the query parameters
+func (h *AuditLogHandler) GetAuditLogs(c *gin.Context) {
+	q, err := parseListQuery(c, auditLogFilterAliases)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := h.service.ListLogs(q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondList(c, "Audit logs retrieved successfully", q, page)
+}
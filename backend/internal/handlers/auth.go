@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/delpresence/backend/internal/apierror"
 	"github.com/delpresence/backend/internal/auth"
 	"github.com/delpresence/backend/internal/models"
 	"github.com/gin-gonic/gin"
@@ -19,30 +21,38 @@ func Login(c *gin.Context) {
 
 	// Validate the request body
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Reject the attempt early if the IP or username is throttled
+	if !allowLoginAttempt(c, req.Username) {
 		return
 	}
 
 	// Attempt to login
 	response, err := auth.Login(req.Username, req.Password)
 	if err != nil {
-		var statusCode int
-		var message string
+		recordLoginFailure(c, req.Username)
 
-		// Handle different error types
+		// Handle different error types, unknown usernames look like wrong passwords
 		switch {
 		case errors.Is(err, auth.ErrUserNotFound), errors.Is(err, auth.ErrInvalidCredentials):
-			statusCode = http.StatusUnauthorized
-			message = "Invalid username or password"
+			apierror.Respond(c, apierror.New(apierror.CodeInvalidCredentials, "Invalid username or password"))
 		default:
-			statusCode = http.StatusInternalServerError
-			message = "An error occurred during login"
+			respondError(c, http.StatusInternalServerError, fmt.Errorf("an error occurred during login: %w", err))
 		}
+		return
+	}
 
-		c.JSON(statusCode, gin.H{"error": message})
+	// The password is correct but a second factor is still pending
+	if response.TwoFactor != nil {
+		c.JSON(http.StatusOK, response.TwoFactor)
 		return
 	}
 
+	recordLoginSuccess(req.Username)
+
 	// Use custom response struct to ensure the correct field order
 	orderedResponse := models.OrderedLoginResponse{
 		User:         response.User,
@@ -56,7 +66,7 @@ func Login(c *gin.Context) {
 	// Manually marshal to JSON to ensure field order
 	jsonBytes, err := json.Marshal(orderedResponse)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating response"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("error generating response: %w", err))
 		return
 	}
 	
@@ -71,30 +81,29 @@ func RefreshToken(c *gin.Context) {
 
 	// Validate the request body
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Refresh requests carry no username, so they are only throttled by IP
+	if !allowLoginAttempt(c, "") {
 		return
 	}
 
 	// Attempt to refresh the token
 	response, err := auth.RefreshToken(req.RefreshToken)
 	if err != nil {
-		var statusCode int
-		var message string
+		recordLoginFailure(c, "")
 
-		// Handle different error types
+		// Handle different error types, tokens of deleted users are invalid tokens
 		switch {
 		case errors.Is(err, auth.ErrInvalidToken):
-			statusCode = http.StatusUnauthorized
-			message = "Invalid or expired refresh token"
+			apierror.Respond(c, apierror.New(apierror.CodeInvalidToken, "Invalid or expired refresh token"))
 		case errors.Is(err, auth.ErrUserNotFound):
-			statusCode = http.StatusUnauthorized
-			message = "User not found"
+			apierror.Respond(c, apierror.New(apierror.CodeInvalidToken, "User not found"))
 		default:
-			statusCode = http.StatusInternalServerError
-			message = "An error occurred during token refresh"
+			respondError(c, http.StatusInternalServerError, fmt.Errorf("an error occurred during token refresh: %w", err))
 		}
-
-		c.JSON(statusCode, gin.H{"error": message})
 		return
 	}
 
@@ -111,7 +120,7 @@ func RefreshToken(c *gin.Context) {
 	// Manually marshal to JSON to ensure field order
 	jsonBytes, err := json.Marshal(orderedResponse)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating response"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("error generating response: %w", err))
 		return
 	}
 	
@@ -125,7 +134,7 @@ func GetCurrentUser(c *gin.Context) {
 	// Get the user ID from the context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in token"})
+		respondErrorf(c, http.StatusUnauthorized, "User not found in token")
 		return
 	}
 
@@ -150,7 +159,7 @@ func GetCurrentUser(c *gin.Context) {
 	}
 
 	// Convert userID to proper type if needed
-	var userIDValue interface{} = userID
+	var userIDValue uint
 	switch v := userID.(type) {
 	case float64:
 		userIDValue = uint(v)
@@ -160,13 +169,72 @@ func GetCurrentUser(c *gin.Context) {
 		userIDValue = v
 	}
 
-	// Log the user info for debugging
-	log.Printf("GetCurrentUser: id=%v, username=%v, role=%v", userIDValue, username, role)
+	response := models.CurrentUserResponse{
+		ID:       userIDValue,
+		Username: fmt.Sprint(username),
+		Role:     fmt.Sprint(role),
+	}
+
+	// Let clients show a banner while an admin is viewing as this user
+	if _, ok := c.Get("impersonatorID"); ok {
+		readOnly := c.GetBool("impersonationReadOnly")
+		response.ImpersonatedBy = &models.ImpersonatorResponse{
+			ID:       c.GetUint("impersonatorID"),
+			Username: c.GetString("impersonatorUsername"),
+		}
+		response.ReadOnly = &readOnly
+	}
 
 	// Return the user data
-	c.JSON(http.StatusOK, gin.H{
-		"id":       userIDValue,
-		"username": username,
-		"role":     role,
-	})
-} 
\ No newline at end of file
+	c.JSON(http.StatusOK, response)
+}
+
+// allowLoginAttempt checks the login limiter and writes a 429 response when the attempt is throttled
+func allowLoginAttempt(c *gin.Context, username string) bool {
+	if auth.Limiter == nil {
+		return true
+	}
+
+	err := auth.Limiter.Allow(c.ClientIP(), username)
+	if err == nil {
+		return true
+	}
+
+	var throttleErr *auth.ThrottleError
+	if !errors.As(err, &throttleErr) {
+		// Don't block logins because the limiter store is unavailable
+		slog.ErrorContext(c.Request.Context(), "Login limiter check failed", "error", err)
+		return true
+	}
+
+	apiErr := apierror.New(apierror.CodeTooManyAttempts, "Too many login attempts, please try again later")
+	if errors.Is(err, auth.ErrAccountLocked) {
+		apiErr = apierror.New(apierror.CodeAccountLocked, "Account temporarily locked due to repeated failed login attempts")
+	}
+
+	retryAfter := int(throttleErr.RetryAfter.Seconds()) + 1
+	slog.WarnContext(c.Request.Context(), "Login attempt throttled", "username", username, "error", err)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	apierror.Respond(c, apiErr.With("retry_after", retryAfter))
+	return false
+}
+
+// recordLoginFailure registers a failed login attempt with the login limiter
+func recordLoginFailure(c *gin.Context, username string) {
+	if auth.Limiter == nil {
+		return
+	}
+	if err := auth.Limiter.RecordFailure(c.ClientIP(), username); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error recording failed login attempt", "error", err)
+	}
+}
+
+// recordLoginSuccess clears the failure history of a username after a successful login
+func recordLoginSuccess(username string) {
+	if auth.Limiter == nil {
+		return
+	}
+	if err := auth.Limiter.RecordSuccess(username); err != nil {
+		slog.Error("Error clearing login attempts", "error", err)
+	}
+}
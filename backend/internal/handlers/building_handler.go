@@ -21,28 +21,31 @@ func NewBuildingHandler() *BuildingHandler {
 	}
 }
 
-// GetAllBuildings returns all buildings
+// GetAllBuildings returns one page of buildings, see parseListQuery for the query parameters
+// With stats=true each record comes with its statistics
 func (h *BuildingHandler) GetAllBuildings(c *gin.Context) {
-	stats := c.Query("stats")
-	var result interface{}
-	var err error
+	q, err := parseListQuery(c, nil)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
 
-	if stats == "true" {
-		result, err = h.service.GetAllBuildingsWithStats()
-	} else {
-		result, err = h.service.GetAllBuildings()
+	if c.Query("stats") == "true" {
+		page, err := h.service.ListBuildingsWithStats(q)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		respondList(c, "Buildings retrieved successfully", q, page)
+		return
 	}
 
+	page, err := h.service.ListBuildings(q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Buildings retrieved successfully",
-		"data":    result,
-	})
+	respondList(c, "Buildings retrieved successfully", q, page)
 }
 
 // GetBuildingByID returns a building by ID
@@ -50,7 +53,7 @@ func (h *BuildingHandler) GetBuildingByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
@@ -64,7 +67,7 @@ func (h *BuildingHandler) GetBuildingByID(c *gin.Context) {
 	}
 
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Building not found"})
+		respondError(c, http.StatusNotFound, services.ErrBuildingNotFound)
 		return
 	}
 
@@ -80,12 +83,12 @@ func (h *BuildingHandler) CreateBuilding(c *gin.Context) {
 	var building models.Building
 
 	if err := c.ShouldBindJSON(&building); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	if err := h.service.CreateBuilding(&building); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -101,20 +104,20 @@ func (h *BuildingHandler) UpdateBuilding(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	var building models.Building
 	if err := c.ShouldBindJSON(&building); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	building.ID = uint(id)
 
 	if err := h.service.UpdateBuilding(&building); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -130,12 +133,12 @@ func (h *BuildingHandler) DeleteBuilding(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	if err := h.service.DeleteBuilding(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarFeedHandler handles HTTP requests related to iCalendar feeds of timetables
+type CalendarFeedHandler struct {
+	service *services.CalendarFeedService
+}
+
+// NewCalendarFeedHandler creates a new calendar feed handler
+func NewCalendarFeedHandler() *CalendarFeedHandler {
+	return &CalendarFeedHandler{
+		service: services.NewCalendarFeedService(),
+	}
+}
+
+// GetFeed serves the iCalendar document of a feed token, for calendar apps
+func (h *CalendarFeedHandler) GetFeed(c *gin.Context) {
+	calendar, err := h.service.Calendar(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", `inline; filename="delpresence.ics"`)
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Status(http.StatusOK)
+	if err := calendar.Encode(c.Writer, time.Now()); err != nil {
+		c.Error(err)
+	}
+}
+
+// CreateStudentFeed issues a new feed URL for the logged in student, the previous URL stops working
+func (h *CalendarFeedHandler) CreateStudentFeed(c *gin.Context) {
+	created, err := h.service.CreateStudentFeed(c.MustGet("userID").(uint), publicBaseURL(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Calendar feed created successfully, keep the URL private as anyone with it can read the timetable",
+		"data":    created,
+	})
+}
+
+// RevokeStudentFeed revokes the feed URL of the logged in student
+func (h *CalendarFeedHandler) RevokeStudentFeed(c *gin.Context) {
+	if err := h.service.RevokeStudentFeed(c.MustGet("userID").(uint)); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Calendar feed revoked successfully",
+	})
+}
+
+// CreateLecturerFeed issues a new feed URL for the logged in lecturer, the previous URL stops working
+func (h *CalendarFeedHandler) CreateLecturerFeed(c *gin.Context) {
+	created, err := h.service.CreateLecturerFeed(c.MustGet("userID").(uint), publicBaseURL(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Calendar feed created successfully, keep the URL private as anyone with it can read the timetable",
+		"data":    created,
+	})
+}
+
+// RevokeLecturerFeed revokes the feed URL of the logged in lecturer
+func (h *CalendarFeedHandler) RevokeLecturerFeed(c *gin.Context) {
+	if err := h.service.RevokeLecturerFeed(c.MustGet("userID").(uint)); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Calendar feed revoked successfully",
+	})
+}
+
+// GetRoomFeeds returns the feeds of a room that are not revoked
+func (h *CalendarFeedHandler) GetRoomFeeds(c *gin.Context) {
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	feeds, err := h.service.ListRoomFeeds(uint(roomID))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Calendar feeds retrieved successfully",
+		"data":    feeds,
+	})
+}
+
+// CreateRoomFeed issues a feed URL of the classes in a room, such as for a building manager
+func (h *CalendarFeedHandler) CreateRoomFeed(c *gin.Context) {
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	var req models.CalendarFeedRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	created, err := h.service.CreateRoomFeed(uint(roomID), req.Label, c.MustGet("userID").(uint), publicBaseURL(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Calendar feed created successfully, keep the URL private as anyone with it can read the timetable",
+		"data":    created,
+	})
+}
+
+// RevokeFeed revokes a calendar feed of any student, lecturer or room
+func (h *CalendarFeedHandler) RevokeFeed(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	if err := h.service.RevokeFeed(uint(id)); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Calendar feed revoked successfully",
+	})
+}
+
+// publicBaseURL returns SERVER_PUBLIC_URL, or the scheme and host the request was sent to
+func publicBaseURL(c *gin.Context) string {
+	if publicURL := config.Get().Server.PublicURL; publicURL != "" {
+		return publicURL
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
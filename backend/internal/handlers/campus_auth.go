@@ -3,9 +3,11 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
 
+	"github.com/delpresence/backend/internal/apierror"
 	"github.com/delpresence/backend/internal/auth"
 	"github.com/delpresence/backend/internal/models"
 	"github.com/gin-gonic/gin"
@@ -17,51 +19,52 @@ func CampusLogin(c *gin.Context) {
 
 	// Bind form data
 	if err := c.ShouldBind(&req); err != nil {
-		log.Printf("Error binding request data: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-		})
+		slog.InfoContext(c.Request.Context(), "Invalid campus login request", "error", err)
+		respondErrorf(c, http.StatusBadRequest, "Invalid request format")
 		return
 	}
 
-	log.Printf("Campus login attempt for username: %s", req.Username)
+	// Reject the attempt early so throttled clients never reach CIS
+	if !allowLoginAttempt(c, req.Username) {
+		return
+	}
 
-	// Call campus login service
-	campusResponse, err := auth.CampusLogin(req.Username, req.Password)
+	// Call campus login service, falling back to the credential cache when CIS is down
+	campusResponse, offline, err := auth.CampusLoginWithFallback(req.Username, req.Password, c.ClientIP())
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		message := "Authentication failed"
+		apiErr := apierror.New(apierror.CodeInternal, "Authentication failed")
 
 		// Handle specific error types
-		if errors.Is(err, auth.ErrCampusAuthFailed) {
-			statusCode = http.StatusUnauthorized
-			message = "Campus authentication failed"
+		switch {
+		case errors.Is(err, auth.ErrCampusAuthFailed):
+			apiErr = apierror.New(apierror.CodeCampusAuthFailed, "Campus authentication failed")
+			recordLoginFailure(c, req.Username)
+		case errors.Is(err, auth.ErrCampusUnavailable):
+			apiErr = apierror.New(apierror.CodeCampusUnavailable, "Campus authentication service is unavailable, please try again later")
+		case errors.Is(err, auth.ErrAccountInactive):
+			apiErr = apierror.New(apierror.CodeAccountInactive, "Your account is no longer active")
+		default:
+			recordLoginFailure(c, req.Username)
 		}
 
-		log.Printf("Campus login failed: %v", err)
-
-		// Return a properly formatted error response
-		c.JSON(statusCode, gin.H{
-			"error": message,
-		})
+		slog.InfoContext(c.Request.Context(), "Campus login failed", "username", req.Username, "error", err)
+		apierror.Respond(c, apiErr)
 		return
 	}
 
-	log.Printf("Campus login successful for user: %s with role: %s", campusResponse.User.Username, campusResponse.User.Role)
+	slog.InfoContext(c.Request.Context(), "Campus login successful", "username", campusResponse.User.Username, "role", campusResponse.User.Role, "offline", offline)
+	recordLoginSuccess(req.Username)
 
 	// Convert to standard login response
 	loginResponse := auth.ConvertCampusResponseToLoginResponse(campusResponse)
-	log.Printf("Converted to login response with user role: %s", loginResponse.User.Role)
-	
-	// Debug logging to help diagnose token structure issues
-	log.Printf("Response token length: %d, refresh token length: %d", 
-		len(loginResponse.Token), len(loginResponse.RefreshToken))
+	loginResponse.Offline = offline
 
 	// Use custom response struct to ensure the correct field order
 	orderedResponse := models.OrderedLoginResponse{
 		User:         loginResponse.User,
 		Token:        loginResponse.Token,
 		RefreshToken: loginResponse.RefreshToken,
+		Offline:      loginResponse.Offline,
 	}
 	
 	// Set content type
@@ -70,7 +73,7 @@ func CampusLogin(c *gin.Context) {
 	// Manually marshal to JSON to ensure field order
 	jsonBytes, err := json.Marshal(orderedResponse)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating response"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("error generating response: %w", err))
 		return
 	}
 	
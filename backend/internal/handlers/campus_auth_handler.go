@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/delpresence/backend/internal/services"
@@ -25,14 +26,14 @@ func (h *CampusAuthHandler) GetToken(c *gin.Context) {
 	// This is a simple example, your actual implementation may vary
 	// based on your authentication system
 	if c.GetString("role") != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		respondErrorf(c, http.StatusForbidden, "Admin access required")
 		return
 	}
 
 	// Get token
 	token, err := h.service.GetToken()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get token: " + err.Error()})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get token: %w", err))
 		return
 	}
 
@@ -46,14 +47,14 @@ func (h *CampusAuthHandler) GetToken(c *gin.Context) {
 func (h *CampusAuthHandler) RefreshToken(c *gin.Context) {
 	// Only allow admins to access this endpoint
 	if c.GetString("role") != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		respondErrorf(c, http.StatusForbidden, "Admin access required")
 		return
 	}
 
 	// Refresh token
 	token, err := h.service.RefreshToken()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token: " + err.Error()})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to refresh token: %w", err))
 		return
 	}
 
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigHandler shows the effective configuration to admins
+type ConfigHandler struct{}
+
+// NewConfigHandler creates a new config handler
+func NewConfigHandler() *ConfigHandler {
+	return &ConfigHandler{}
+}
+
+// GetConfig returns every setting with its value and source (default, file or env)
+// Secrets such as JWT_SECRET and DB_PASSWORD are redacted
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Configuration retrieved successfully",
+		"data":    config.Get().View(),
+	})
+}
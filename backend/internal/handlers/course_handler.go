@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -11,74 +12,67 @@ import (
 
 // CourseHandler handles course-related API requests
 type CourseHandler struct {
-	repo *repositories.CourseRepository
+	repo             *repositories.CourseRepository
+	academicYearRepo *repositories.AcademicYearRepository
 }
 
 // NewCourseHandler creates a new instance of CourseHandler
 func NewCourseHandler() *CourseHandler {
 	return &CourseHandler{
-		repo: repositories.NewCourseRepository(),
+		repo:             repositories.NewCourseRepository(),
+		academicYearRepo: repositories.NewAcademicYearRepository(),
 	}
 }
 
-// GetAllCourses returns all courses
+// courseFilterAliases are the plain filter parameters of the course list
+var courseFilterAliases = map[string]string{
+	"department_id":    "department_id",
+	"academic_year_id": "academic_year_id",
+	"semester":         "semester",
+}
+
+// GetAllCourses returns one page of courses, see parseListQuery for the query parameters
 func (h *CourseHandler) GetAllCourses(c *gin.Context) {
-	// Check if we need to filter by department or academic year
-	departmentID := c.Query("department_id")
-	academicYearID := c.Query("academic_year_id")
-	semester := c.Query("semester")
-	activeAcademicYear := c.Query("active_academic_year")
-	
-	var courses []models.Course
-	var err error
-	
-	// Apply filters if provided
-	if departmentID != "" {
-		deptID, convErr := strconv.ParseUint(departmentID, 10, 32)
-		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid department ID"})
-			return
-		}
-		courses, err = h.repo.GetByDepartment(uint(deptID))
-	} else if academicYearID != "" {
-		ayID, convErr := strconv.ParseUint(academicYearID, 10, 32)
-		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid academic year ID"})
+	q, err := parseListQuery(c, courseFilterAliases)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Only the courses of the academic year running today, none when no academic year is running
+	if c.Query("active_academic_year") == "true" {
+		active, err := h.academicYearRepo.GetActiveAcademicYear()
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, err)
 			return
 		}
-		courses, err = h.repo.GetByAcademicYear(uint(ayID))
-	} else if semester != "" {
-		sem, convErr := strconv.Atoi(semester)
-		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid semester"})
-			return
+		var activeID uint
+		if active != nil {
+			activeID = active.ID
 		}
-		courses, err = h.repo.GetBySemester(sem)
-	} else if activeAcademicYear == "true" {
-		courses, err = h.repo.GetByActiveAcademicYear()
-	} else {
-		courses, err = h.repo.GetAll()
+		q.Filters = append(q.Filters, models.ListFilter{Field: "academic_year_id", Op: models.FilterEq, Values: []string{strconv.FormatUint(uint64(activeID), 10)}})
 	}
-	
+
+	page, err := h.repo.List(q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
-	
-	c.JSON(http.StatusOK, gin.H{"status": "success", "data": courses})
+
+	respondList(c, "Courses retrieved successfully", q, page)
 }
 
 // GetCourseByID returns a single course by ID
 func (h *CourseHandler) GetCourseByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid course ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid course ID")
 		return
 	}
 	
 	course, err := h.repo.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Course not found"})
+		respondErrorf(c, http.StatusNotFound, "Course not found")
 		return
 	}
 	
@@ -89,13 +83,13 @@ func (h *CourseHandler) GetCourseByID(c *gin.Context) {
 func (h *CourseHandler) CreateCourse(c *gin.Context) {
 	var course models.Course
 	if err := c.ShouldBindJSON(&course); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 	
 	createdCourse, err := h.repo.Create(course)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	
@@ -106,27 +100,27 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid course ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid course ID")
 		return
 	}
 	
 	// Verify course exists
 	_, err = h.repo.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Course not found"})
+		respondErrorf(c, http.StatusNotFound, "Course not found")
 		return
 	}
 	
 	var course models.Course
 	if err := c.ShouldBindJSON(&course); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 	
 	course.ID = uint(id)
 	updatedCourse, err := h.repo.Update(course)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	
@@ -137,14 +131,14 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 func (h *CourseHandler) DeleteCourse(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid course ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid course ID")
 		return
 	}
 	
 	// Verify course exists
 	_, err = h.repo.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Course not found"})
+		respondErrorf(c, http.StatusNotFound, "Course not found")
 		return
 	}
 	
@@ -152,15 +146,12 @@ func (h *CourseHandler) DeleteCourse(c *gin.Context) {
 	lecturerAssignmentRepo := repositories.NewLecturerAssignmentRepository()
 	assignments, err := lecturerAssignmentRepo.GetByCourseID(uint(id), 0) // 0 means any academic year
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to check course assignments: " + err.Error()})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to check course assignments: %w", err))
 		return
 	}
 	
 	if len(assignments) > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"message": "Cannot delete course: This course is assigned to one or more lecturers. Please remove all lecturer assignments first.",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Cannot delete course: This course is assigned to one or more lecturers. Please remove all lecturer assignments first.")
 		return
 	}
 	
@@ -168,21 +159,18 @@ func (h *CourseHandler) DeleteCourse(c *gin.Context) {
 	scheduleRepo := repositories.NewCourseScheduleRepository()
 	schedules, err := scheduleRepo.GetByCourse(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": "Failed to check course schedules: " + err.Error()})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to check course schedules: %w", err))
 		return
 	}
 	
 	if len(schedules) > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"message": "Cannot delete course: This course has one or more schedules. Please remove all course schedules first.",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Cannot delete course: This course has one or more schedules. Please remove all course schedules first.")
 		return
 	}
 	
 	err = h.repo.Delete(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	
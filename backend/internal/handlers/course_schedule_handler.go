@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"regexp"
 	"sort"
@@ -27,92 +28,46 @@ func NewCourseScheduleHandler() *CourseScheduleHandler {
 	}
 }
 
-// GetAllSchedules returns all course schedules
-func (h *CourseScheduleHandler) GetAllSchedules(c *gin.Context) {
-	// Check for filter parameters
-	academicYearID := c.Query("academic_year_id")
-	lecturerID := c.Query("lecturer_id")
-	studentGroupID := c.Query("student_group_id")
-	day := c.Query("day")
-	roomID := c.Query("room_id")
-	buildingID := c.Query("building_id")
-	courseID := c.Query("course_id")
-
-	var schedules []models.CourseSchedule
-	var err error
+// scheduleFilterAliases are the plain filter parameters of the schedule list
+var scheduleFilterAliases = map[string]string{
+	"academic_year_id": "academic_year_id",
+	"lecturer_id":      "user_id",
+	"student_group_id": "student_group_id",
+	"day":              "day",
+	"room_id":          "room_id",
+	"building_id":      "building_id",
+	"course_id":        "course_id",
+}
 
-	// Apply filters based on query parameters
-	if academicYearID != "" {
-		id, convErr := strconv.ParseUint(academicYearID, 10, 32)
-		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid academic year ID"})
-			return
-		}
-		schedules, err = h.service.GetSchedulesByAcademicYear(uint(id))
-	} else if lecturerID != "" {
-		id, convErr := strconv.ParseUint(lecturerID, 10, 32)
-		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid lecturer ID"})
-			return
-		}
-		schedules, err = h.service.GetSchedulesByLecturer(uint(id))
-	} else if studentGroupID != "" {
-		id, convErr := strconv.ParseUint(studentGroupID, 10, 32)
-		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student group ID"})
-			return
-		}
-		schedules, err = h.service.GetSchedulesByStudentGroup(uint(id))
-	} else if day != "" {
-		schedules, err = h.service.GetSchedulesByDay(day)
-	} else if roomID != "" {
-		id, convErr := strconv.ParseUint(roomID, 10, 32)
-		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid room ID"})
-			return
-		}
-		schedules, err = h.service.GetSchedulesByRoom(uint(id))
-	} else if buildingID != "" {
-		id, convErr := strconv.ParseUint(buildingID, 10, 32)
-		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid building ID"})
-			return
-		}
-		schedules, err = h.service.GetSchedulesByBuilding(uint(id))
-	} else if courseID != "" {
-		id, convErr := strconv.ParseUint(courseID, 10, 32)
-		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid course ID"})
-			return
-		}
-		schedules, err = h.service.GetSchedulesByCourse(uint(id))
-	} else {
-		schedules, err = h.service.GetAllSchedules()
+// GetAllSchedules returns one page of course schedules, see parseListQuery for the query parameters
+func (h *CourseScheduleHandler) GetAllSchedules(c *gin.Context) {
+	// The filter parameters from before list queries are kept, lecturer_id is the schedule's user_id
+	q, err := parseListQuery(c, scheduleFilterAliases)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
 	}
 
+	schedules, err := h.service.ListSchedules(c.Request.Context(), q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	formattedSchedules := h.service.FormatSchedulesForResponse(schedules)
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   formattedSchedules,
-	})
+	respondList(c, "Schedules retrieved successfully", q, schedules)
 }
 
 // GetScheduleByID returns a course schedule by ID
 func (h *CourseScheduleHandler) GetScheduleByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid schedule ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid schedule ID")
 		return
 	}
 
 	schedule, err := h.service.GetScheduleByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Schedule not found"})
+		respondErrorf(c, http.StatusNotFound, "Schedule not found")
 		return
 	}
 
@@ -125,36 +80,19 @@ func (h *CourseScheduleHandler) GetScheduleByID(c *gin.Context) {
 
 // CreateSchedule creates a new course schedule
 func (h *CourseScheduleHandler) CreateSchedule(c *gin.Context) {
-	var request struct {
-		CourseID       uint   `json:"course_id" binding:"required"`
-		RoomID         uint   `json:"room_id" binding:"required"`
-		Day            string `json:"day" binding:"required"`
-		StartTime      string `json:"start_time" binding:"required"`
-		EndTime        string `json:"end_time" binding:"required"`
-		LecturerID     uint   `json:"lecturer_id" binding:"required"`
-		StudentGroupID uint   `json:"student_group_id" binding:"required"`
-		AcademicYearID uint   `json:"academic_year_id" binding:"required"`
-		Capacity       int    `json:"capacity"`
-	}
+	var request models.CourseScheduleRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	// Check for duplicate schedule (same course, day, time)
 	scheduleRepo := repositories.NewCourseScheduleRepository()
-	var existingSchedules []models.CourseSchedule
-	err := scheduleRepo.DB().
-		Where("course_id = ? AND day = ? AND start_time = ? AND end_time = ? AND academic_year_id = ?",
-			request.CourseID, request.Day, request.StartTime, request.EndTime, request.AcademicYearID).
-		Find(&existingSchedules).Error
-
-	if err == nil && len(existingSchedules) > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Duplicate schedule: A schedule with the same course, day, and time already exists",
-		})
+	duplicate, err := scheduleRepo.HasDuplicate(request.CourseID, request.Day, request.StartTime, request.EndTime, request.AcademicYearID, nil)
+
+	if err == nil && duplicate {
+		respondErrorf(c, http.StatusBadRequest, "Duplicate schedule: A schedule with the same course, day, and time already exists")
 		return
 	}
 
@@ -162,7 +100,7 @@ func (h *CourseScheduleHandler) CreateSchedule(c *gin.Context) {
 	academicYearRepo := repositories.NewAcademicYearRepository()
 	academicYear, err := academicYearRepo.FindByID(request.AcademicYearID)
 	if err != nil || academicYear == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid academic year ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 		return
 	}
 
@@ -170,17 +108,14 @@ func (h *CourseScheduleHandler) CreateSchedule(c *gin.Context) {
 	courseRepo := repositories.NewCourseRepository()
 	course, err := courseRepo.GetByID(request.CourseID)
 	if err != nil || course.ID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid course ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid course ID")
 		return
 	}
 
 	// Validate and get the correct assigned lecturer for this course
 	assignedLecturerID, err := h.validateLecturerAssignment(request.CourseID, request.AcademicYearID, request.LecturerID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -188,7 +123,7 @@ func (h *CourseScheduleHandler) CreateSchedule(c *gin.Context) {
 	roomRepo := repositories.NewRoomRepository()
 	room, err := roomRepo.FindByID(request.RoomID)
 	if err != nil || room == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid room ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid room ID")
 		return
 	}
 
@@ -196,7 +131,7 @@ func (h *CourseScheduleHandler) CreateSchedule(c *gin.Context) {
 	studentGroupRepo := repositories.NewStudentGroupRepository()
 	studentGroup, err := studentGroupRepo.GetByID(request.StudentGroupID)
 	if err != nil || studentGroup.ID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student group ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student group ID")
 		return
 	}
 
@@ -207,20 +142,14 @@ func (h *CourseScheduleHandler) CreateSchedule(c *gin.Context) {
 	}
 
 	if !validDays[strings.ToLower(request.Day)] {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid day of week. Must be one of: Senin, Selasa, Rabu, Kamis, Jumat, Sabtu, Minggu",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid day of week. Must be one of: Senin, Selasa, Rabu, Kamis, Jumat, Sabtu, Minggu")
 		return
 	}
 
 	// Validate time format (HH:MM)
 	timeRegex := regexp.MustCompile(`^([01]?[0-9]|2[0-3]):[0-5][0-9]$`)
 	if !timeRegex.MatchString(request.StartTime) || !timeRegex.MatchString(request.EndTime) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid time format. Times must be in HH:MM format",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid time format. Times must be in HH:MM format")
 		return
 	}
 
@@ -239,10 +168,7 @@ func (h *CourseScheduleHandler) CreateSchedule(c *gin.Context) {
 
 	// Verify end time is after start time
 	if endTimeMinutes <= startTimeMinutes {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "End time must be after start time",
-		})
+		respondErrorf(c, http.StatusBadRequest, "End time must be after start time")
 		return
 	}
 
@@ -268,9 +194,9 @@ func (h *CourseScheduleHandler) CreateSchedule(c *gin.Context) {
 		Capacity:       request.Capacity,
 	}
 
-	createdSchedule, err := h.service.CreateSchedule(schedule)
+	createdSchedule, err := h.service.CreateSchedule(c.Request.Context(), schedule)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -282,32 +208,22 @@ func (h *CourseScheduleHandler) UpdateSchedule(c *gin.Context) {
 	// Get schedule ID from the URL
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid schedule ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid schedule ID")
 		return
 	}
 
 	// Fetch existing schedule to ensure it exists
 	existingSchedule, err := h.service.GetScheduleByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Schedule not found"})
+		respondErrorf(c, http.StatusNotFound, "Schedule not found")
 		return
 	}
 
 	// Parse request body
-	var request struct {
-		CourseID       uint   `json:"course_id"`
-		RoomID         uint   `json:"room_id"`
-		Day            string `json:"day"`
-		StartTime      string `json:"start_time"`
-		EndTime        string `json:"end_time"`
-		LecturerID     uint   `json:"lecturer_id"`
-		StudentGroupID uint   `json:"student_group_id"`
-		AcademicYearID uint   `json:"academic_year_id"`
-		Capacity       int    `json:"capacity"`
-	}
+	var request models.CourseScheduleUpdateRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -339,17 +255,11 @@ func (h *CourseScheduleHandler) UpdateSchedule(c *gin.Context) {
 
 	// Check for duplicate schedule (same course, day, time) excluding this schedule
 	scheduleRepo := repositories.NewCourseScheduleRepository()
-	var existingSchedules []models.CourseSchedule
-	err = scheduleRepo.DB().
-		Where("id <> ? AND course_id = ? AND day = ? AND start_time = ? AND end_time = ? AND academic_year_id = ?",
-			id, effectiveCourseID, effectiveDay, effectiveStartTime, effectiveEndTime, effectiveAcademicYearID).
-		Find(&existingSchedules).Error
-
-	if err == nil && len(existingSchedules) > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Duplicate schedule: A schedule with the same course, day, and time already exists",
-		})
+	scheduleID := uint(id)
+	duplicate, err := scheduleRepo.HasDuplicate(effectiveCourseID, effectiveDay, effectiveStartTime, effectiveEndTime, effectiveAcademicYearID, &scheduleID)
+
+	if err == nil && duplicate {
+		respondErrorf(c, http.StatusBadRequest, "Duplicate schedule: A schedule with the same course, day, and time already exists")
 		return
 	}
 
@@ -377,10 +287,7 @@ func (h *CourseScheduleHandler) UpdateSchedule(c *gin.Context) {
 		// Validate and get the correct assigned lecturer for this course
 		assignedLecturerID, err := h.validateLecturerAssignment(effectiveCourseID, effectiveAcademicYearID, request.LecturerID)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": err.Error(),
-			})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
 
@@ -401,7 +308,6 @@ func (h *CourseScheduleHandler) UpdateSchedule(c *gin.Context) {
 
 	if request.StudentGroupID != 0 {
 		schedule.StudentGroupID = request.StudentGroupID
-		fmt.Printf("Updating student group ID to: %d\n", request.StudentGroupID)
 	}
 
 	if request.AcademicYearID != 0 {
@@ -416,10 +322,7 @@ func (h *CourseScheduleHandler) UpdateSchedule(c *gin.Context) {
 		}
 
 		if !validDays[strings.ToLower(request.Day)] {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid day of week. Must be one of: Senin, Selasa, Rabu, Kamis, Jumat, Sabtu, Minggu",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid day of week. Must be one of: Senin, Selasa, Rabu, Kamis, Jumat, Sabtu, Minggu")
 			return
 		}
 
@@ -433,18 +336,12 @@ func (h *CourseScheduleHandler) UpdateSchedule(c *gin.Context) {
 	endTimeProvided := request.EndTime != ""
 
 	if startTimeProvided && !timeRegex.MatchString(request.StartTime) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid start time format. Times must be in HH:MM format",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid start time format. Times must be in HH:MM format")
 		return
 	}
 
 	if endTimeProvided && !timeRegex.MatchString(request.EndTime) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid end time format. Times must be in HH:MM format",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid end time format. Times must be in HH:MM format")
 		return
 	}
 
@@ -464,10 +361,7 @@ func (h *CourseScheduleHandler) UpdateSchedule(c *gin.Context) {
 
 		// Verify end time is after start time
 		if endTimeMinutes <= startTimeMinutes {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "End time must be after start time",
-			})
+			respondErrorf(c, http.StatusBadRequest, "End time must be after start time")
 			return
 		}
 	} else if startTimeProvided && !endTimeProvided {
@@ -486,10 +380,7 @@ func (h *CourseScheduleHandler) UpdateSchedule(c *gin.Context) {
 
 		// Verify end time is after start time
 		if endTimeMinutes <= startTimeMinutes {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "End time must be after start time",
-			})
+			respondErrorf(c, http.StatusBadRequest, "End time must be after start time")
 			return
 		}
 	} else if !startTimeProvided && endTimeProvided {
@@ -508,10 +399,7 @@ func (h *CourseScheduleHandler) UpdateSchedule(c *gin.Context) {
 
 		// Verify end time is after start time
 		if endTimeMinutes <= startTimeMinutes {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "End time must be after start time",
-			})
+			respondErrorf(c, http.StatusBadRequest, "End time must be after start time")
 			return
 		}
 	}
@@ -537,9 +425,9 @@ func (h *CourseScheduleHandler) UpdateSchedule(c *gin.Context) {
 	// This allows resources to be used for multiple classes at the same time
 
 	// Update the schedule
-	updatedSchedule, err := h.service.UpdateSchedule(schedule)
+	updatedSchedule, err := h.service.UpdateSchedule(c.Request.Context(), schedule)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -550,13 +438,13 @@ func (h *CourseScheduleHandler) UpdateSchedule(c *gin.Context) {
 func (h *CourseScheduleHandler) DeleteSchedule(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid schedule ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid schedule ID")
 		return
 	}
 
 	err = h.service.DeleteSchedule(uint(id))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Failed to delete schedule: " + err.Error()})
+		respondErrorf(c, http.StatusBadRequest, "Failed to delete schedule: %v", err)
 		return
 	}
 
@@ -566,62 +454,12 @@ func (h *CourseScheduleHandler) DeleteSchedule(c *gin.Context) {
 	})
 }
 
-// CheckScheduleConflicts checks for various scheduling conflicts
-func (h *CourseScheduleHandler) CheckScheduleConflicts(c *gin.Context) {
-	var request struct {
-		ScheduleID     *uint  `json:"schedule_id"`
-		RoomID         uint   `json:"room_id" binding:"required"`
-		LecturerID     uint   `json:"lecturer_id" binding:"required"`
-		StudentGroupID uint   `json:"student_group_id" binding:"required"`
-		Day            string `json:"day" binding:"required"`
-		StartTime      string `json:"start_time" binding:"required"`
-		EndTime        string `json:"end_time" binding:"required"`
-	}
-
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
-		return
-	}
-
-	conflicts, err := h.service.CheckForScheduleConflicts(
-		request.ScheduleID,
-		request.RoomID,
-		request.LecturerID,
-		request.StudentGroupID,
-		request.Day,
-		request.StartTime,
-		request.EndTime,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
-		return
-	}
-
-	// All conflicts are now just warnings, not blocking errors
-	hasBlockingConflict := false // No blocking conflicts - all are warnings
-
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data": map[string]interface{}{
-			"conflicts":                      conflicts,
-			"has_blocking_conflict":          hasBlockingConflict,
-			"room_conflict_warning":          conflicts["room"],
-			"lecturer_conflict_warning":      conflicts["lecturer"],
-			"student_group_conflict_warning": conflicts["student_group"],
-			"message":                        "Room, lecturer, and student group conflicts are non-blocking and will allow flexible scheduling",
-		},
-	})
-}
-
 // GetMySchedules returns the schedules for the logged in lecturer
 func (h *CourseScheduleHandler) GetMySchedules(c *gin.Context) {
 	// Get the user ID from the JWT token context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"status":  "error",
-			"message": "User not found in token",
-		})
+		respondErrorf(c, http.StatusUnauthorized, "User not found in token")
 		return
 	}
 
@@ -640,24 +478,15 @@ func (h *CourseScheduleHandler) GetMySchedules(c *gin.Context) {
 	case string:
 		id, err := strconv.Atoi(v)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid user ID format",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid user ID format")
 			return
 		}
 		userIDInt = id
 	default:
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Invalid user ID type",
-		})
+		respondError(c, http.StatusInternalServerError, errors.New("invalid user ID type"))
 		return
 	}
 
-	// Debug log to check userID
-	fmt.Printf("Looking up lecturer with userID: %d\n", userIDInt)
-
 	// Get the lecturer by userID from authentication
 	lecturer, err := lecturerRepo.GetByUserID(userIDInt)
 
@@ -667,14 +496,11 @@ func (h *CourseScheduleHandler) GetMySchedules(c *gin.Context) {
 
 	if err != nil {
 		// Try alternative method to find lecturer
-		fmt.Printf("Error finding lecturer by userID %d: %v\n", userIDInt, err)
+		slog.DebugContext(c.Request.Context(), "Lecturer not found by user ID, trying lecturer ID", "user_id", userIDInt, "error", err)
 		// Try to find lecturer by ID directly
 		lecturer, err = lecturerRepo.GetByID(uint(userIDInt))
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{
-				"status":  "error",
-				"message": "Lecturer not found: " + err.Error(),
-			})
+			respondErrorf(c, http.StatusNotFound, "Lecturer not found: %v", err)
 			return
 		}
 		finalUserID = lecturer.ID
@@ -684,9 +510,7 @@ func (h *CourseScheduleHandler) GetMySchedules(c *gin.Context) {
 		idSource = "lecturer.UserID"
 	}
 
-	// Log lecturer found
-	fmt.Printf("Found lecturer: ID=%d, UserID=%d, Name=%s, using %s=%d for queries\n",
-		lecturer.ID, lecturer.UserID, lecturer.FullName, idSource, finalUserID)
+	slog.DebugContext(c.Request.Context(), "Found lecturer", "lecturer_id", lecturer.ID, "user_id", lecturer.UserID, "id_source", idSource, "query_id", finalUserID)
 
 	// Check for academic year filter
 	var academicYearID uint = 0
@@ -695,7 +519,7 @@ func (h *CourseScheduleHandler) GetMySchedules(c *gin.Context) {
 		id, err := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if err != nil {
 			// Instead of returning an error, just log it and continue with default behavior
-			fmt.Printf("Invalid academic year ID: %s, using active year instead\n", academicYearIDStr)
+			slog.DebugContext(c.Request.Context(), "Invalid academic year ID, using the latest one", "academic_year_id", academicYearIDStr)
 		} else {
 			academicYearID = uint(id)
 		}
@@ -712,7 +536,6 @@ func (h *CourseScheduleHandler) GetMySchedules(c *gin.Context) {
 				return academicYears[i].ID > academicYears[j].ID
 			})
 			academicYearID = academicYears[0].ID
-			fmt.Printf("Using academicYearID: %d\n", academicYearID)
 		}
 	}
 
@@ -761,8 +584,6 @@ func (h *CourseScheduleHandler) GetMySchedules(c *gin.Context) {
 				for _, lid := range lecturerIDs {
 					assignments, err := assignmentRepo.GetByLecturerID(lid, academicYearID)
 					if err == nil && len(assignments) > 0 {
-						fmt.Printf("Found %d assignments for lecturer ID %d\n", len(assignments), lid)
-
 						for _, assignment := range assignments {
 							var courseSchedules []models.CourseSchedule
 							if academicYearID > 0 {
@@ -786,7 +607,6 @@ func (h *CourseScheduleHandler) GetMySchedules(c *gin.Context) {
 	// Try each method until we find schedules
 	var methodUsed string
 	for _, method := range tryMethods {
-		fmt.Printf("Trying to get schedules %s\n", method.name)
 		foundSchedules, err := method.tryFn()
 		if err == nil && len(foundSchedules) > 0 {
 			schedules = foundSchedules
@@ -795,13 +615,13 @@ func (h *CourseScheduleHandler) GetMySchedules(c *gin.Context) {
 		}
 	}
 
-	fmt.Printf("Found %d schedules for lecturer using method: %s\n", len(schedules), methodUsed)
+	slog.DebugContext(c.Request.Context(), "Found lecturer schedules", "count", len(schedules), "method", methodUsed)
 
 	// If still no schedules found, return an empty array rather than an error
 	if len(schedules) == 0 {
 		c.JSON(http.StatusOK, gin.H{
 			"status": "success",
-			"data":   []map[string]interface{}{}, // Empty array
+			"data":   []models.CourseScheduleResponse{}, // Empty array
 		})
 		return
 	}
@@ -817,10 +637,7 @@ func (h *CourseScheduleHandler) GetMySchedules(c *gin.Context) {
 func (h *CourseScheduleHandler) GetLecturerForCourse(c *gin.Context) {
 	courseID, err := strconv.ParseUint(c.Param("course_id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid course ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid course ID")
 		return
 	}
 
@@ -833,10 +650,7 @@ func (h *CourseScheduleHandler) GetLecturerForCourse(c *gin.Context) {
 	// First try to get all academic years and use the most recent one
 	academicYears, err := academicYearRepo.FindAll()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get academic years: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get academic years: %w", err))
 		return
 	}
 
@@ -859,29 +673,20 @@ func (h *CourseScheduleHandler) GetLecturerForCourse(c *gin.Context) {
 
 	// If we still have an error after trying
 	if assignmentErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get lecturer assignments: " + assignmentErr.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get lecturer assignments: %w", assignmentErr))
 		return
 	}
 
 	// If no assignments found
 	if len(assignments) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "No lecturer assigned to this course",
-		})
+		respondErrorf(c, http.StatusNotFound, "No lecturer assigned to this course")
 		return
 	}
 
 	// Return the first assigned lecturer (typically there should be only one)
 	lecturer := assignments[0].Lecturer
 	if lecturer == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Lecturer information not found",
-		})
+		respondErrorf(c, http.StatusNotFound, "Lecturer information not found")
 		return
 	}
 
@@ -894,14 +699,14 @@ func (h *CourseScheduleHandler) GetLecturerForCourse(c *gin.Context) {
 		// Use the lecturer info to create a response
 		c.JSON(http.StatusOK, gin.H{
 			"status": "success",
-			"data": gin.H{
-				"lecturer_id":        lecturer.ID,     // Use the Lecturer.ID as fallback for user ID
-				"user_id":            lecturer.ID,     // Include user_id field using Lecturer.ID
-				"external_user_id":   lecturer.UserID, // Include external ID for reference
-				"name":               lecturer.FullName,
-				"email":              lecturer.Email,
-				"academic_year_id":   academicYearID,
-				"academic_year_name": academicYearName,
+			"data": models.CourseLecturerResponse{
+				LecturerID:       lecturer.ID,
+				UserID:           lecturer.ID,
+				ExternalUserID:   lecturer.UserID,
+				Name:             lecturer.FullName,
+				Email:            lecturer.Email,
+				AcademicYearID:   academicYearID,
+				AcademicYearName: academicYearName,
 			},
 		})
 		return
@@ -910,14 +715,14 @@ func (h *CourseScheduleHandler) GetLecturerForCourse(c *gin.Context) {
 	// Normal response when user is found
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
-		"data": gin.H{
-			"lecturer_id":        user.ID,         // Use User.ID as the reference ID for CourseSchedule.UserID
-			"user_id":            user.ID,         // Include user_id field to be explicit
-			"external_user_id":   lecturer.UserID, // Include external ID for reference
-			"name":               lecturer.FullName,
-			"email":              lecturer.Email,
-			"academic_year_id":   academicYearID,
-			"academic_year_name": academicYearName,
+		"data": models.CourseLecturerResponse{
+			LecturerID:       user.ID,
+			UserID:           user.ID,
+			ExternalUserID:   lecturer.UserID,
+			Name:             lecturer.FullName,
+			Email:            lecturer.Email,
+			AcademicYearID:   academicYearID,
+			AcademicYearName: academicYearName,
 		},
 	})
 }
@@ -968,10 +773,7 @@ func (h *CourseScheduleHandler) GetStudentSchedules(c *gin.Context) {
 	// Get the user ID from the JWT token context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"status":  "error",
-			"message": "User not found in token",
-		})
+		respondErrorf(c, http.StatusUnauthorized, "User not found in token")
 		return
 	}
 
@@ -987,18 +789,12 @@ func (h *CourseScheduleHandler) GetStudentSchedules(c *gin.Context) {
 	case string:
 		id, err := strconv.Atoi(v)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid user ID format",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid user ID format")
 			return
 		}
 		userIDInt = id
 	default:
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Invalid user ID type",
-		})
+		respondError(c, http.StatusInternalServerError, errors.New("invalid user ID type"))
 		return
 	}
 
@@ -1009,7 +805,7 @@ func (h *CourseScheduleHandler) GetStudentSchedules(c *gin.Context) {
 		// Return empty schedules list instead of error when student not found
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "success",
-			"data":    []interface{}{},
+			"data":    []models.CourseScheduleResponse{},
 			"message": "No student record found for the current user",
 		})
 		return
@@ -1019,10 +815,7 @@ func (h *CourseScheduleHandler) GetStudentSchedules(c *gin.Context) {
 	studentGroupRepo := repositories.NewStudentGroupRepository()
 	studentGroups, err := studentGroupRepo.GetGroupsByStudentID(student.ID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get student groups",
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get student groups: %w", err))
 		return
 	}
 
@@ -1030,7 +823,7 @@ func (h *CourseScheduleHandler) GetStudentSchedules(c *gin.Context) {
 	if len(studentGroups) == 0 {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "success",
-			"data":    []interface{}{},
+			"data":    []models.CourseScheduleResponse{},
 			"message": "Student is not assigned to any groups",
 		})
 		return
@@ -1045,10 +838,7 @@ func (h *CourseScheduleHandler) GetStudentSchedules(c *gin.Context) {
 	if academicYearIDStr != "" {
 		id, convErr := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(id)
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/delpresence/backend/internal/apierror"
+	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/services"
 	"github.com/gin-gonic/gin"
 )
@@ -21,19 +24,21 @@ func NewEmployeeHandler() *EmployeeHandler {
 	}
 }
 
-// GetAllEmployees returns all employees
+// GetAllEmployees returns one page of employees, see parseListQuery for the query parameters
 func (h *EmployeeHandler) GetAllEmployees(c *gin.Context) {
-	employees, err := h.service.GetAllEmployees()
+	q, err := parseListQuery(c, nil)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Employees retrieved successfully",
-		"data":    employees,
-	})
+	employees, err := h.service.ListEmployees(q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondList(c, "Employees retrieved successfully", q, employees)
 }
 
 // GetEmployeeByID returns an employee by ID
@@ -41,13 +46,13 @@ func (h *EmployeeHandler) GetEmployeeByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	employee, err := h.service.GetEmployeeByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		respondErrorf(c, http.StatusNotFound, "Employee not found")
 		return
 	}
 
@@ -60,35 +65,41 @@ func (h *EmployeeHandler) GetEmployeeByID(c *gin.Context) {
 
 // SyncEmployees syncs employees from the campus API
 func (h *EmployeeHandler) SyncEmployees(c *gin.Context) {
-	// Sync employees using the service
-	count, err := h.service.SyncEmployees()
+	// Sync employees through the scheduler so it never overlaps a scheduled run
+	run, err := services.GetSyncScheduler().Run(models.SyncEntityEmployees, models.SyncTriggerManual, syncActor(c))
+	if errors.Is(err, services.ErrSyncInProgress) {
+		apierror.Respond(c, apierror.New(apierror.CodeSyncInProgress, "Employee sync is already running"))
+		return
+	}
+	if errors.Is(err, services.ErrSyncBlocked) {
+		respondError(c, http.StatusConflict, err)
+		return
+	}
 	if err != nil {
 		errMsg := err.Error()
-		statusCode := http.StatusInternalServerError
+		code := apierror.CodeInternal
 		responseMsg := "Failed to sync employees"
-		
+
 		// Check for specific errors to provide better messages
 		if strings.Contains(errMsg, "timeout") || strings.Contains(errMsg, "deadline exceeded") {
-			statusCode = http.StatusGatewayTimeout
+			code = apierror.CodeUpstreamTimeout
 			responseMsg = "Connection to campus API timed out"
 		} else if strings.Contains(errMsg, "connection refused") {
-			statusCode = http.StatusServiceUnavailable
+			code = apierror.CodeUnavailable
 			responseMsg = "Campus API service unavailable"
 		}
-		
-		c.JSON(statusCode, gin.H{
-			"status":  "error",
-			"message": responseMsg,
-			"error":   errMsg,
-		})
+
+		apierror.Respond(c, apierror.Newf(code, "%s: %s", responseMsg, errMsg))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "Employees synced successfully",
-		"data": gin.H{
-			"count": count,
+		"data": models.SyncResponse{
+			Count:     run.InsertedCount + run.UpdatedCount,
+			Failed:    run.FailedCount,
+			SyncRunID: run.ID,
 		},
 	})
 } 
\ No newline at end of file
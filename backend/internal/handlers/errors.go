@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/delpresence/backend/internal/apierror"
+	"github.com/delpresence/backend/internal/auth"
+	"github.com/delpresence/backend/internal/repositories"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// domainErrors maps the errors of the service layer to their API error code, and so to their HTTP status
+// Errors are matched with errors.Is in this order
+var domainErrors = []struct {
+	err  error
+	code apierror.Code
+}{
+	{services.ErrSessionNotFound, apierror.CodeSessionNotFound},
+	{services.ErrNoActiveSession, apierror.CodeSessionNotFound},
+	{services.ErrSessionNotActive, apierror.CodeSessionNotActive},
+	{services.ErrNotEnrolled, apierror.CodeNotEnrolled},
+	{services.ErrInvalidQRCode, apierror.CodeInvalidQRCode},
+	{services.ErrQRScheduleMismatch, apierror.CodeQRScheduleMismatch},
+	{services.ErrQRCodeNotSupported, apierror.CodeVerificationNotSupported},
+	{services.ErrStudentNotFound, apierror.CodeStudentNotFound},
+	{services.ErrLecturerNotFound, apierror.CodeLecturerNotFound},
+
+	{services.ErrAcademicYearNotFound, apierror.CodeAcademicYearNotFound},
+	{services.ErrAcademicYearInUse, apierror.CodeAcademicYearInUse},
+	{services.ErrFacultyNotFound, apierror.CodeFacultyNotFound},
+	{services.ErrFacultyCodeExists, apierror.CodeFacultyCodeExists},
+	{services.ErrFacultyHasStudyPrograms, apierror.CodeFacultyHasStudyPrograms},
+	{services.ErrStudyProgramNotFound, apierror.CodeStudyProgramNotFound},
+	{services.ErrStudyProgramCodeExists, apierror.CodeStudyProgramCodeExists},
+	{services.ErrBuildingNotFound, apierror.CodeBuildingNotFound},
+	{services.ErrBuildingCodeExists, apierror.CodeBuildingCodeExists},
+	{services.ErrBuildingHasRooms, apierror.CodeBuildingHasRooms},
+	{services.ErrRoomNotFound, apierror.CodeRoomNotFound},
+	{services.ErrRoomCodeExists, apierror.CodeRoomCodeExists},
+	{services.ErrCourseNotFound, apierror.CodeCourseNotFound},
+	{repositories.ErrInvalidListQuery, apierror.CodeInvalidListQuery},
+	{services.ErrScheduleConflictNotFound, apierror.CodeScheduleConflictNotFound},
+	{services.ErrInvalidScheduleSlot, apierror.CodeInvalidScheduleSlot},
+	{services.ErrInvalidSlotSearch, apierror.CodeInvalidSlotSearch},
+	{services.ErrLecturerAvailabilityNotFound, apierror.CodeLecturerAvailabilityNotFound},
+	{services.ErrInvalidLecturerAvailability, apierror.CodeInvalidLecturerAvailability},
+	{services.ErrTimetableDraftNotFound, apierror.CodeTimetableDraftNotFound},
+	{services.ErrTimetableDraftCommitted, apierror.CodeTimetableDraftCommitted},
+	{services.ErrTimetableDraftStale, apierror.CodeTimetableDraftStale},
+	{services.ErrInvalidTimetableRequest, apierror.CodeInvalidTimetableRequest},
+	{services.ErrHolidayNotFound, apierror.CodeHolidayNotFound},
+	{services.ErrInvalidHoliday, apierror.CodeInvalidHoliday},
+	{services.ErrScheduleExceptionNotFound, apierror.CodeScheduleExceptionNotFound},
+	{services.ErrInvalidScheduleException, apierror.CodeInvalidScheduleException},
+	{services.ErrScheduleExceptionConflict, apierror.CodeScheduleExceptionConflict},
+	{services.ErrCalendarFeedNotFound, apierror.CodeCalendarFeedNotFound},
+
+	{services.ErrSyncInProgress, apierror.CodeSyncInProgress},
+	{services.ErrSyncBlocked, apierror.CodeSyncBlocked},
+	{services.ErrUnknownSyncEntity, apierror.CodeUnknownSyncEntity},
+	{services.ErrSyncPreviewNotFound, apierror.CodeSyncPreviewNotFound},
+
+	{auth.ErrInvalidCredentials, apierror.CodeInvalidCredentials},
+	{auth.ErrInvalidToken, apierror.CodeInvalidToken},
+	{auth.ErrUserNotFound, apierror.CodeUserNotFound},
+	{auth.ErrAccountLocked, apierror.CodeAccountLocked},
+	{auth.ErrTooManyAttempts, apierror.CodeTooManyAttempts},
+	{auth.ErrAccountInactive, apierror.CodeAccountInactive},
+	{auth.ErrInvalidTwoFactorCode, apierror.CodeInvalidTwoFactorCode},
+	{auth.ErrTwoFactorRequired, apierror.CodeTwoFactorRequired},
+	{auth.ErrTwoFactorNotEnabled, apierror.CodeTwoFactorNotEnabled},
+	{auth.ErrTwoFactorAlreadyEnabled, apierror.CodeTwoFactorAlreadyEnabled},
+	{auth.ErrTwoFactorNotStarted, apierror.CodeTwoFactorNotStarted},
+	{auth.ErrCampusAuthFailed, apierror.CodeCampusAuthFailed},
+	{auth.ErrCampusUnavailable, apierror.CodeCampusUnavailable},
+	{auth.ErrImpersonationNotAllowed, apierror.CodeImpersonationNotAllowed},
+	{services.ErrInvalidAPIKey, apierror.CodeInvalidAPIKey},
+
+	{gorm.ErrRecordNotFound, apierror.CodeNotFound},
+}
+
+// toAPIError gives err its API error code: its own for *apierror.Error, the one in domainErrors
+// for errors of the service layer and otherwise the generic code of status
+func toAPIError(status int, err error) *apierror.Error {
+	var apiErr *apierror.Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	for _, domain := range domainErrors {
+		if errors.Is(err, domain.err) {
+			return apierror.Wrap(domain.code, err)
+		}
+	}
+	return apierror.Wrap(apierror.CodeForStatus(status), err)
+}
+
+// respondError writes err in the error envelope, see apierror.Respond
+// status is only used for errors without a code of their own
+func respondError(c *gin.Context, status int, err error) {
+	apierror.Respond(c, toAPIError(status, err))
+}
+
+// respondErrorf writes an error with a generic code for status and a formatted detail
+// It is for client errors only: a 5xx status goes through respondError, so the formatted
+// text is logged with the request ID and never reaches the client
+func respondErrorf(c *gin.Context, status int, format string, args ...interface{}) {
+	if status >= http.StatusInternalServerError {
+		respondError(c, status, fmt.Errorf(format, args...))
+		return
+	}
+	apierror.Respond(c, apierror.Newf(apierror.CodeForStatus(status), format, args...))
+}
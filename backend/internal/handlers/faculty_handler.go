@@ -4,7 +4,6 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/services"
@@ -24,28 +23,31 @@ func NewFacultyHandler() *FacultyHandler {
 	}
 }
 
-// GetAllFaculties returns all faculties
+// GetAllFaculties returns one page of faculties, see parseListQuery for the query parameters
+// With stats=true each record comes with its statistics
 func (h *FacultyHandler) GetAllFaculties(c *gin.Context) {
-	stats := c.Query("stats")
-	var result interface{}
-	var err error
+	q, err := parseListQuery(c, nil)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
 
-	if stats == "true" {
-		result, err = h.service.GetAllFacultiesWithStats()
-	} else {
-		result, err = h.service.GetAllFaculties()
+	if c.Query("stats") == "true" {
+		page, err := h.service.ListFacultiesWithStats(q)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		respondList(c, "Fakultas berhasil diambil", q, page)
+		return
 	}
 
+	page, err := h.service.ListFaculties(q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Fakultas berhasil diambil",
-		"data":    result,
-	})
+	respondList(c, "Fakultas berhasil diambil", q, page)
 }
 
 // GetFacultyByID returns a faculty by ID
@@ -53,7 +55,7 @@ func (h *FacultyHandler) GetFacultyByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format ID tidak valid"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
@@ -67,7 +69,7 @@ func (h *FacultyHandler) GetFacultyByID(c *gin.Context) {
 	}
 
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Fakultas tidak ditemukan"})
+		respondError(c, http.StatusNotFound, services.ErrFacultyNotFound)
 		return
 	}
 
@@ -83,7 +85,7 @@ func (h *FacultyHandler) CreateFaculty(c *gin.Context) {
 	var faculty models.Faculty
 
 	if err := c.ShouldBindJSON(&faculty); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format data tidak valid"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid data format")
 		return
 	}
 
@@ -93,7 +95,7 @@ func (h *FacultyHandler) CreateFaculty(c *gin.Context) {
 	}
 
 	if err := h.service.CreateFaculty(&faculty); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -109,13 +111,13 @@ func (h *FacultyHandler) UpdateFaculty(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format ID tidak valid"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	var faculty models.Faculty
 	if err := c.ShouldBindJSON(&faculty); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format data tidak valid"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid data format")
 		return
 	}
 
@@ -126,7 +128,7 @@ func (h *FacultyHandler) UpdateFaculty(c *gin.Context) {
 
 	faculty.ID = uint(id)
 	if err := h.service.UpdateFaculty(&faculty); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -142,35 +144,17 @@ func (h *FacultyHandler) DeleteFaculty(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "Format ID tidak valid",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	err = h.service.DeleteFaculty(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = services.ErrFacultyNotFound
+	}
 	if err != nil {
-		// Handle different types of errors with appropriate status codes
-		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{
-				"status": "error",
-				"error":  "Fakultas tidak ditemukan",
-			})
-			return
-		} else if strings.Contains(err.Error(), "associated study programs") {
-			c.JSON(http.StatusConflict, gin.H{
-				"status": "error",
-				"error":  "Tidak dapat menghapus fakultas yang memiliki program studi. Harap hapus semua program studi terlebih dahulu.",
-			})
-			return
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status": "error",
-				"error":  err.Error(),
-			})
-			return
-		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
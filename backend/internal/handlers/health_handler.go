@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler serves the liveness and readiness probes
+type HealthHandler struct {
+	service *services.HealthService
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{
+		service: services.GetHealthService(),
+	}
+}
+
+// Healthz reports that the process is running and serving HTTP
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether the server can take traffic: the database is reachable, the schema is
+// up to date and the server is not shutting down. CIS reachability is included but not required.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.service.Readiness(c.Request.Context())
+
+	status := http.StatusOK
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// HolidayHandler handles HTTP requests related to holidays
+type HolidayHandler struct {
+	service *services.HolidayService
+}
+
+// NewHolidayHandler creates a new holiday handler
+func NewHolidayHandler() *HolidayHandler {
+	return &HolidayHandler{
+		service: services.NewHolidayService(),
+	}
+}
+
+// GetAllHolidays returns one page of holidays, see parseListQuery for the query parameters
+func (h *HolidayHandler) GetAllHolidays(c *gin.Context) {
+	q, err := parseListQuery(c, nil)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := h.service.List(q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondList(c, "Holidays retrieved successfully", q, page)
+}
+
+// CreateHoliday adds a day or range of days without classes
+func (h *HolidayHandler) CreateHoliday(c *gin.Context) {
+	var request models.HolidayRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	holiday, err := h.service.Create(request)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Holiday created successfully",
+		"data":    holiday,
+	})
+}
+
+// DeleteHoliday removes a holiday
+func (h *HolidayHandler) DeleteHoliday(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Holiday deleted successfully",
+	})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/delpresence/backend/internal/auth"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// StartImpersonation issues a short-lived token that lets the current admin act as another user
+func StartImpersonation(c *gin.Context) {
+	var req models.ImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body, user_id and reason are required")
+		return
+	}
+
+	readOnly := true
+	if req.ReadOnly != nil {
+		readOnly = *req.ReadOnly
+	}
+
+	admin := &auth.Claims{
+		UserID:   c.MustGet("userID").(uint),
+		Username: c.GetString("username"),
+		Role:     c.GetString("role"),
+	}
+
+	response, err := auth.Impersonate(admin, req.UserID, readOnly, req.Reason, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrUserNotFound), errors.Is(err, auth.ErrImpersonationNotAllowed):
+			respondError(c, http.StatusInternalServerError, err)
+		default:
+			respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to start impersonation: %w", err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Impersonation token issued",
+		"data":    response,
+	})
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/middleware"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// IntegrationHandler handles requests from classroom kiosks and service integrations
+// Routes are authenticated with an API key or an admin bearer token
+type IntegrationHandler struct {
+	service         *services.IntegrationService
+	scheduleService *services.CourseScheduleService
+}
+
+// NewIntegrationHandler creates a new integration handler
+func NewIntegrationHandler() *IntegrationHandler {
+	return &IntegrationHandler{
+		service:         services.NewIntegrationService(),
+		scheduleService: services.NewCourseScheduleService(),
+	}
+}
+
+// roomIDParam parses the room ID and checks that the API key, if any, may access the room
+func roomIDParam(c *gin.Context) (uint, bool) {
+	roomID, err := strconv.ParseUint(c.Param("room_id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid room ID format")
+		return 0, false
+	}
+
+	if key := middleware.APIKeyFromContext(c); key != nil && !key.AllowsRoom(uint(roomID)) {
+		respondErrorf(c, http.StatusForbidden, "API key is not allowed to access this room")
+		return 0, false
+	}
+	return uint(roomID), true
+}
+
+// GetRoomActiveSession returns the active attendance session of a room with its QR code data
+// This is what a classroom kiosk polls, it renders the QR code from the data itself
+func (h *IntegrationHandler) GetRoomActiveSession(c *gin.Context) {
+	roomID, ok := roomIDParam(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.service.GetActiveSessionForRoom(roomID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get active session: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Active session retrieved successfully",
+		"data": models.RoomActiveSessionResponse{
+			SessionID:        session.ID,
+			CourseScheduleID: session.CourseScheduleID,
+			CourseCode:       session.CourseSchedule.Course.Code,
+			CourseName:       session.CourseSchedule.Course.Name,
+			Room:             session.CourseSchedule.Room.Name,
+			Type:             session.Type,
+			StartTime:        session.StartTime,
+			Duration:         session.Duration,
+			QRCodeData:       session.QRCodeData,
+		},
+	})
+}
+
+// GetRoomSchedules returns the course schedules of a room
+func (h *IntegrationHandler) GetRoomSchedules(c *gin.Context) {
+	roomID, ok := roomIDParam(c)
+	if !ok {
+		return
+	}
+
+	schedules, err := h.scheduleService.GetSchedulesByRoom(roomID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Room schedules retrieved successfully",
+		"data":    h.scheduleService.FormatSchedulesForResponse(schedules),
+	})
+}
+
+// GetAttendanceReport returns attendance statistics per course schedule of an academic year
+// Supports the required query parameter academic_year_id and the optional study_program_id
+func (h *IntegrationHandler) GetAttendanceReport(c *gin.Context) {
+	academicYearID, err := strconv.ParseUint(c.Query("academic_year_id"), 10, 32)
+	if err != nil || academicYearID == 0 {
+		respondErrorf(c, http.StatusBadRequest, "academic_year_id is required")
+		return
+	}
+
+	var studyProgramID uint64
+	if programStr := c.Query("study_program_id"); programStr != "" {
+		studyProgramID, err = strconv.ParseUint(programStr, 10, 32)
+		if err != nil {
+			respondErrorf(c, http.StatusBadRequest, "Invalid study_program_id format")
+			return
+		}
+	}
+
+	var allowedStudyPrograms []uint
+	if key := middleware.APIKeyFromContext(c); key != nil {
+		if studyProgramID != 0 && !key.AllowsStudyProgram(uint(studyProgramID)) {
+			respondErrorf(c, http.StatusForbidden, "API key is not allowed to access this study program")
+			return
+		}
+		allowedStudyPrograms = key.StudyProgramIDs
+	}
+
+	rows, err := h.service.GetAttendanceReport(uint(academicYearID), uint(studyProgramID), allowedStudyPrograms)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Attendance report retrieved successfully",
+		"data":    rows,
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// failingAttendanceStore is an AttendanceStore whose database is unreachable
+type failingAttendanceStore struct {
+	repositories.AttendanceStore
+}
+
+// ListActiveSessionsByRoom fails like a lost database connection
+func (failingAttendanceStore) ListActiveSessionsByRoom(roomID uint) ([]models.AttendanceSession, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestGetRoomActiveSessionErrors(t *testing.T) {
+	schedules := repositories.NewMemoryCourseScheduleStore()
+	students := repositories.NewMemoryStudentStore()
+
+	cases := []struct {
+		name        string
+		attendances repositories.AttendanceStore
+		status      int
+		code        string
+	}{
+		{"no open session", repositories.NewMemoryAttendanceStore(schedules, students), http.StatusNotFound, "session_not_found"},
+		{"store failure", failingAttendanceStore{}, http.StatusInternalServerError, "internal_error"},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, c := range cases {
+		handler := &IntegrationHandler{service: services.NewIntegrationServiceWithStores(c.attendances, schedules)}
+		router := gin.New()
+		router.GET("/rooms/:room_id/active-session", handler.GetRoomActiveSession)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rooms/1/active-session", nil))
+		if rec.Code != c.status || !strings.Contains(rec.Body.String(), `"`+c.code+`"`) {
+			t.Errorf("%s: got %d %s, want %d %s", c.name, rec.Code, rec.Body.String(), c.status, c.code)
+		}
+		if strings.Contains(rec.Body.String(), "connection refused") {
+			t.Errorf("%s: response exposes the store error: %s", c.name, rec.Body.String())
+		}
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sort"
 	"strconv"
@@ -23,17 +24,10 @@ func NewLecturerAssignmentHandler() *LecturerAssignmentHandler {
 
 // CreateLecturerAssignment creates a new lecturer assignment
 func (h *LecturerAssignmentHandler) CreateLecturerAssignment(c *gin.Context) {
-	var input struct {
-		UserID         int  `json:"user_id" binding:"required"`
-		CourseID       uint `json:"course_id" binding:"required"`
-		AcademicYearID uint `json:"academic_year_id"`
-	}
+	var input models.LecturerAssignmentRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid input: " + err.Error(),
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid input: %v", err)
 		return
 	}
 
@@ -58,36 +52,24 @@ func (h *LecturerAssignmentHandler) CreateLecturerAssignment(c *gin.Context) {
 	// Check if an assignment already exists for this lecturer and course (without academic year constraint)
 	exists, err := h.repo.AssignmentExistsForCourse(input.UserID, input.CourseID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Gagal memeriksa penugasan yang ada: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to check existing assignments: %w", err))
 		return
 	}
 
 	// Check if any lecturer is already assigned to this course
 	existingAssignments, err := h.repo.GetByCourseID(input.CourseID, input.AcademicYearID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Gagal memeriksa penugasan yang ada: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to check existing assignments: %w", err))
 		return
 	}
 
 	if len(existingAssignments) > 0 {
-		c.JSON(http.StatusConflict, gin.H{
-			"status":  "error",
-			"message": "Tidak dapat menugaskan dosen: Satu mata kuliah hanya dapat ditugaskan kepada satu dosen. Hapus penugasan dosen yang ada terlebih dahulu.",
-		})
+		respondErrorf(c, http.StatusConflict, "Tidak dapat menugaskan dosen: Satu mata kuliah hanya dapat ditugaskan kepada satu dosen. Hapus penugasan dosen yang ada terlebih dahulu.")
 		return
 	}
 
 	if exists {
-		c.JSON(http.StatusConflict, gin.H{
-			"status":  "error",
-			"message": "Dosen ini sudah ditugaskan untuk mata kuliah ini",
-		})
+		respondErrorf(c, http.StatusConflict, "This lecturer is already assigned to this course")
 		return
 	}
 
@@ -102,27 +84,20 @@ func (h *LecturerAssignmentHandler) CreateLecturerAssignment(c *gin.Context) {
 		// If not found by user_id, try by lecturer.ID directly
 		lecturer, err = lecturerRepo.GetByID(uint(input.UserID))
 		if err != nil || lecturer.ID == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid lecturer ID - no lecturer found with this ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid lecturer ID - no lecturer found with this ID")
 			return
 		}
 		
 		// If found by ID, use the actual user_id from the lecturer record
 		if lecturer.UserID > 0 {
 			actualUserID = lecturer.UserID
-			fmt.Printf("Using lecturer's user_id %d instead of direct ID %d\n", actualUserID, input.UserID)
 		}
 	}
 
 	courseRepo := repositories.NewCourseRepository()
 	course, err := courseRepo.GetByID(input.CourseID)
 	if err != nil || course.ID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "ID mata kuliah tidak valid",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid course ID")
 		return
 	}
 
@@ -135,10 +110,7 @@ func (h *LecturerAssignmentHandler) CreateLecturerAssignment(c *gin.Context) {
 
 	err = h.repo.Create(&assignment)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to create assignment: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to create assignment: %w", err))
 		return
 	}
 
@@ -160,65 +132,39 @@ func (h *LecturerAssignmentHandler) CreateLecturerAssignment(c *gin.Context) {
 	})
 }
 
-// GetAllLecturerAssignments returns all lecturer assignments
+// GetAllLecturerAssignments returns one page of lecturer assignments with their course, academic year and lecturer, see parseListQuery for the query parameters
 func (h *LecturerAssignmentHandler) GetAllLecturerAssignments(c *gin.Context) {
-	// Get academic year ID from query parameter, if provided
-	academicYearIDStr := c.Query("academic_year_id")
-	var academicYearIDUint uint = 0
-
-	if academicYearIDStr != "" && academicYearIDStr != "all" {
-		academicYearID, err := strconv.ParseUint(academicYearIDStr, 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
-			return
-		}
-		academicYearIDUint = uint(academicYearID)
+	q, err := parseListQuery(c, nil)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
 	}
 
-	// Get assignments with detailed responses
-	responses, err := h.repo.GetLecturerAssignmentResponses(academicYearIDUint)
+	page, err := h.repo.ListResponses(withAcademicYearFilter(c, q))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get lecturer assignments: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   responses,
-	})
+	respondList(c, "Lecturer assignments retrieved successfully", q, page)
 }
 
 // GetLecturerAssignmentByID returns a specific lecturer assignment
 func (h *LecturerAssignmentHandler) GetLecturerAssignmentByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID")
 		return
 	}
 
 	response, err := h.repo.GetLecturerAssignmentResponseByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get lecturer assignment: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get lecturer assignment: %w", err))
 		return
 	}
 
 	if response == nil || response.ID == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Lecturer assignment not found",
-		})
+		respondErrorf(c, http.StatusNotFound, "Lecturer assignment not found")
 		return
 	}
 
@@ -231,33 +177,23 @@ func (h *LecturerAssignmentHandler) GetLecturerAssignmentByID(c *gin.Context) {
 // UpdateLecturerAssignment updates a lecturer assignment
 func (h *LecturerAssignmentHandler) UpdateLecturerAssignment(c *gin.Context) {
 	id := c.Param("id")
-	fmt.Printf("\n==== Starting UpdateLecturerAssignment for ID: %s ====\n", id)
 	
 	// Convert ID to uint
 	assignmentID, err := strconv.ParseUint(id, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID")
 		return
 	}
 
 	// Get existing assignment
 	existingAssignment, err := h.repo.GetByID(uint(assignmentID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to retrieve existing assignment: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to retrieve existing assignment: %w", err))
 		return
 	}
 
 	if existingAssignment.ID == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Lecturer assignment not found",
-		})
+		respondErrorf(c, http.StatusNotFound, "Lecturer assignment not found")
 		return
 	}
 
@@ -267,17 +203,10 @@ func (h *LecturerAssignmentHandler) UpdateLecturerAssignment(c *gin.Context) {
 	// Note: We're storing original values only for fields we need to compare
 
 	// Parse input data
-	var input struct {
-		UserID         int  `json:"user_id"`
-		CourseID       uint `json:"course_id"`
-		AcademicYearID uint `json:"academic_year_id"`
-	}
+	var input models.LecturerAssignmentUpdateRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid input: " + err.Error(),
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid input: %v", err)
 		return
 	}
 
@@ -299,10 +228,7 @@ func (h *LecturerAssignmentHandler) UpdateLecturerAssignment(c *gin.Context) {
 		// Check if another assignment already exists with the new values (ignoring academic year)
 		exists, err := h.repo.AssignmentExistsForCourse(userIDToCheck, courseIDToCheck)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": "Failed to check for conflicts: " + err.Error(),
-			})
+			respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to check for conflicts: %w", err))
 			return
 		}
 		
@@ -313,10 +239,7 @@ func (h *LecturerAssignmentHandler) UpdateLecturerAssignment(c *gin.Context) {
 			if err != nil || !currentExists || 
 			   (input.UserID != 0 && input.UserID != existingAssignment.UserID) ||
 			   (input.CourseID != 0 && input.CourseID != existingAssignment.CourseID) {
-				c.JSON(http.StatusConflict, gin.H{
-					"status":  "error",
-					"message": "Dosen lain sudah ditugaskan untuk mata kuliah ini",
-				})
+				respondErrorf(c, http.StatusConflict, "Another lecturer is already assigned to this course")
 				return
 			}
 		}
@@ -334,17 +257,13 @@ func (h *LecturerAssignmentHandler) UpdateLecturerAssignment(c *gin.Context) {
 			// If not found by user_id, try by lecturer.ID directly
 			lecturer, err = lecturerRepo.GetByID(uint(input.UserID))
 			if err != nil || lecturer.ID == 0 {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"status":  "error",
-					"message": "ID dosen tidak valid - tidak ditemukan dosen dengan ID ini",
-				})
+				respondErrorf(c, http.StatusBadRequest, "Invalid lecturer ID, no lecturer has this ID")
 				return
 			}
 			
 			// If found by ID, use the actual user_id from the lecturer record
 			if lecturer.UserID > 0 {
 				actualUserID = lecturer.UserID
-				fmt.Printf("Using lecturer's user_id %d instead of direct ID %d\n", actualUserID, input.UserID)
 			}
 		}
 		
@@ -356,10 +275,7 @@ func (h *LecturerAssignmentHandler) UpdateLecturerAssignment(c *gin.Context) {
 		courseRepo := repositories.NewCourseRepository()
 		course, err := courseRepo.GetByID(input.CourseID)
 		if err != nil || course.ID == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "ID mata kuliah tidak valid",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid course ID")
 			return
 		}
 		existingAssignment.CourseID = input.CourseID
@@ -371,10 +287,7 @@ func (h *LecturerAssignmentHandler) UpdateLecturerAssignment(c *gin.Context) {
 		academicYearRepo := repositories.NewAcademicYearRepository()
 		academicYear, err := academicYearRepo.FindByID(input.AcademicYearID)
 		if err != nil || academicYear == nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		existingAssignment.AcademicYearID = input.AcademicYearID
@@ -383,10 +296,7 @@ func (h *LecturerAssignmentHandler) UpdateLecturerAssignment(c *gin.Context) {
 	// Update the assignment
 	err = h.repo.Update(&existingAssignment)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to update assignment: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to update assignment: %w", err))
 		return
 	}
 
@@ -401,11 +311,9 @@ func (h *LecturerAssignmentHandler) UpdateLecturerAssignment(c *gin.Context) {
 		
 		if updateErr != nil {
 			// Log the error but continue - don't fail the whole operation
-			fmt.Printf("Warning: Failed to update related course schedules for academic year %d: %v\n", 
-				existingAssignment.AcademicYearID, updateErr)
+			slog.WarnContext(c.Request.Context(), "Failed to update related course schedules", "course_id", existingAssignment.CourseID, "academic_year_id", existingAssignment.AcademicYearID, "error", updateErr)
 		} else {
-			fmt.Printf("Successfully updated related course schedules for course_id=%d, academic_year_id=%d to use lecturer_id=%d\n", 
-				existingAssignment.CourseID, existingAssignment.AcademicYearID, existingAssignment.UserID)
+			slog.InfoContext(c.Request.Context(), "Updated related course schedules", "course_id", existingAssignment.CourseID, "academic_year_id", existingAssignment.AcademicYearID, "lecturer_id", existingAssignment.UserID)
 		}
 	}
 
@@ -434,10 +342,9 @@ func (h *LecturerAssignmentHandler) UpdateLecturerAssignment(c *gin.Context) {
 					uint(otherAssignment.UserID))
 				
 				if updateErr != nil {
-					fmt.Printf("Warning: Failed to update related course schedules for previous course: %v\n", updateErr)
+					slog.WarnContext(c.Request.Context(), "Failed to update course schedules of the previous course", "course_id", originalCourseID, "error", updateErr)
 				} else {
-					fmt.Printf("Successfully updated schedules for previous course_id=%d to use alternative lecturer_id=%d\n", 
-						originalCourseID, otherAssignment.UserID)
+					slog.InfoContext(c.Request.Context(), "Updated course schedules of the previous course", "course_id", originalCourseID, "lecturer_id", otherAssignment.UserID)
 				}
 			}
 		}
@@ -470,36 +377,25 @@ func (h *LecturerAssignmentHandler) UpdateLecturerAssignment(c *gin.Context) {
 		"message": "Penugasan dosen berhasil diperbarui",
 		"data":    formattedResponse,
 	})
-	
-	fmt.Printf("==== End of UpdateLecturerAssignment for ID: %s ====\n\n", id)
 }
 
 // DeleteLecturerAssignment deletes a lecturer assignment
 func (h *LecturerAssignmentHandler) DeleteLecturerAssignment(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID")
 		return
 	}
 
 	// Verify that the assignment exists
 	assignment, err := h.repo.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to retrieve assignment: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to retrieve assignment: %w", err))
 		return
 	}
 
 	if assignment.ID == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Lecturer assignment not found",
-		})
+		respondErrorf(c, http.StatusNotFound, "Lecturer assignment not found")
 		return
 	}
 	
@@ -511,10 +407,7 @@ func (h *LecturerAssignmentHandler) DeleteLecturerAssignment(c *gin.Context) {
 	// Check if there are any other lecturer assignments for this course in this academic year
 	assignments, err := h.repo.GetByCourseID(courseID, academicYearID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to check other assignments: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to check other assignments: %w", err))
 		return
 	}
 	
@@ -533,29 +426,20 @@ func (h *LecturerAssignmentHandler) DeleteLecturerAssignment(c *gin.Context) {
 	scheduleRepo := repositories.NewCourseScheduleRepository()
 	schedules, err := scheduleRepo.GetByCourseAndAcademicYear(courseID, academicYearID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to check for associated schedules: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to check for associated schedules: %w", err))
 		return
 	}
 
 	// If there are schedules and this is the only lecturer assignment, prevent deletion
 	if len(schedules) > 0 && otherAssignmentCount == 0 {
-		c.JSON(http.StatusConflict, gin.H{
-			"status":  "error",
-			"message": "Tidak dapat menghapus penugasan: Masih terdapat jadwal perkuliahan yang terkait dengan dosen ini. Silakan hapus jadwal terlebih dahulu atau tugaskan dosen lain untuk mata kuliah ini.",
-		})
+		respondErrorf(c, http.StatusConflict, "Tidak dapat menghapus penugasan: Masih terdapat jadwal perkuliahan yang terkait dengan dosen ini. Silakan hapus jadwal terlebih dahulu atau tugaskan dosen lain untuk mata kuliah ini.")
 		return
 	}
 
 	// Delete the assignment
 	err = h.repo.Delete(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to delete assignment: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to delete assignment: %w", err))
 		return
 	}
 	
@@ -572,10 +456,9 @@ func (h *LecturerAssignmentHandler) DeleteLecturerAssignment(c *gin.Context) {
 				uint(alternativeLecturerID))
 			
 			if updateErr != nil {
-				fmt.Printf("Warning: Failed to update schedules with alternative lecturer after deletion: %v\n", updateErr)
+				slog.WarnContext(c.Request.Context(), "Failed to move course schedules to the alternative lecturer", "course_id", courseID, "error", updateErr)
 			} else {
-				fmt.Printf("Successfully updated schedules to use alternative lecturer_id=%d after deletion\n", 
-					alternativeLecturerID)
+				slog.InfoContext(c.Request.Context(), "Moved course schedules to the alternative lecturer", "course_id", courseID, "lecturer_id", alternativeLecturerID)
 			}
 		}
 	}
@@ -590,10 +473,7 @@ func (h *LecturerAssignmentHandler) DeleteLecturerAssignment(c *gin.Context) {
 func (h *LecturerAssignmentHandler) GetAssignmentsByLecturer(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid lecturer ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid lecturer ID")
 		return
 	}
 
@@ -604,10 +484,7 @@ func (h *LecturerAssignmentHandler) GetAssignmentsByLecturer(c *gin.Context) {
 	if academicYearIDStr != "" && academicYearIDStr != "all" {
 		academicYearIDUint, err := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(academicYearIDUint)
@@ -616,10 +493,7 @@ func (h *LecturerAssignmentHandler) GetAssignmentsByLecturer(c *gin.Context) {
 	// Get assignments for the lecturer
 	assignments, err := h.repo.GetByLecturerID(int(id), academicYearID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get assignments: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get assignments: %w", err))
 		return
 	}
 
@@ -633,10 +507,7 @@ func (h *LecturerAssignmentHandler) GetAssignmentsByLecturer(c *gin.Context) {
 func (h *LecturerAssignmentHandler) GetAssignmentsByCourse(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid course ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid course ID")
 		return
 	}
 
@@ -647,10 +518,7 @@ func (h *LecturerAssignmentHandler) GetAssignmentsByCourse(c *gin.Context) {
 	if academicYearIDStr != "" && academicYearIDStr != "all" {
 		academicYearIDUint, err := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(academicYearIDUint)
@@ -673,10 +541,7 @@ func (h *LecturerAssignmentHandler) GetAssignmentsByCourse(c *gin.Context) {
 	// Get assignments for the course
 	assignments, err := h.repo.GetByCourseID(uint(id), academicYearID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get assignments: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get assignments: %w", err))
 		return
 	}
 
@@ -690,10 +555,7 @@ func (h *LecturerAssignmentHandler) GetAssignmentsByCourse(c *gin.Context) {
 func (h *LecturerAssignmentHandler) GetAvailableLecturers(c *gin.Context) {
 	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid course ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid course ID")
 		return
 	}
 
@@ -704,10 +566,7 @@ func (h *LecturerAssignmentHandler) GetAvailableLecturers(c *gin.Context) {
 	if academicYearIDStr != "" {
 		academicYearIDUint, err := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(academicYearIDUint)
@@ -730,10 +589,7 @@ func (h *LecturerAssignmentHandler) GetAvailableLecturers(c *gin.Context) {
 	// Get available lecturers
 	lecturers, err := h.repo.GetAvailableLecturers(uint(courseID), academicYearID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get available lecturers: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get available lecturers: %w", err))
 		return
 	}
 
@@ -748,10 +604,7 @@ func (h *LecturerAssignmentHandler) GetMyAssignments(c *gin.Context) {
 	// Get user ID from context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"status":  "error",
-			"message": "User not authenticated",
-		})
+		respondErrorf(c, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
@@ -772,28 +625,19 @@ func (h *LecturerAssignmentHandler) GetMyAssignments(c *gin.Context) {
 	case string:
 		id, err := strconv.Atoi(v)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid user ID format",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid user ID format")
 			return
 		}
 		userIDInt = id
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid user ID type",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid user ID type")
 		return
 	}
 	
 	// Get the lecturer by userID from authentication
 	lecturer, err := lecturerRepo.GetByUserID(userIDInt)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Lecturer not found: " + err.Error(),
-		})
+		respondErrorf(c, http.StatusNotFound, "Lecturer not found: %v", err)
 		return
 	}
 	
@@ -804,10 +648,7 @@ func (h *LecturerAssignmentHandler) GetMyAssignments(c *gin.Context) {
 	if academicYearIDStr != "" && academicYearIDStr != "all" {
 		academicYearIDUint, err := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(academicYearIDUint)
@@ -830,10 +671,7 @@ func (h *LecturerAssignmentHandler) GetMyAssignments(c *gin.Context) {
 	// Use the lecturer's UserID from the database to get assignments
 	assignments, err := h.repo.GetByLecturerID(lecturer.UserID, academicYearID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get assignments: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get assignments: %w", err))
 		return
 	}
 
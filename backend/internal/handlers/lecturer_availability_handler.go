@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// LecturerAvailabilityHandler handles HTTP requests related to lecturer availability windows
+type LecturerAvailabilityHandler struct {
+	service *services.LecturerAvailabilityService
+}
+
+// NewLecturerAvailabilityHandler creates a new lecturer availability handler
+func NewLecturerAvailabilityHandler() *LecturerAvailabilityHandler {
+	return &LecturerAvailabilityHandler{
+		service: services.NewLecturerAvailabilityService(),
+	}
+}
+
+// ListAvailability returns the availability windows of a lecturer, or of every lecturer without lecturer_id
+func (h *LecturerAvailabilityHandler) ListAvailability(c *gin.Context) {
+	var lecturerID uint64
+	if value := c.Query("lecturer_id"); value != "" {
+		var err error
+		lecturerID, err = strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			respondErrorf(c, http.StatusBadRequest, "Invalid lecturer_id format")
+			return
+		}
+	}
+
+	windows, err := h.service.List(uint(lecturerID))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Lecturer availability retrieved successfully",
+		"data":    windows,
+	})
+}
+
+// CreateAvailability adds a window in which a lecturer cannot or would rather not teach
+func (h *LecturerAvailabilityHandler) CreateAvailability(c *gin.Context) {
+	var request models.LecturerAvailabilityRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	window, err := h.service.Create(request)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Lecturer availability created successfully",
+		"data":    window,
+	})
+}
+
+// DeleteAvailability removes a lecturer availability window
+func (h *LecturerAvailabilityHandler) DeleteAvailability(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Lecturer availability deleted successfully",
+	})
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
+	"github.com/delpresence/backend/internal/apierror"
+	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/services"
 	"github.com/gin-gonic/gin"
 )
@@ -22,38 +25,25 @@ func NewLecturerHandler() *LecturerHandler {
 	}
 }
 
-// GetAllLecturers returns all lecturers
+// GetAllLecturers returns one page of lecturers, see parseListQuery for the query parameters
 func (h *LecturerHandler) GetAllLecturers(c *gin.Context) {
-	lecturers, err := h.service.GetAllLecturers()
+	q, err := parseListQuery(c, nil)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get lecturers"})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	// Transform the data to include the study program name as a string
-	type LecturerResponse struct {
-		ID               uint      `json:"id"`
-		EmployeeID       int       `json:"employee_id"`
-		LecturerID       int       `json:"lecturer_id"`
-		NIP              string    `json:"nip"`
-		FullName         string    `json:"full_name"`
-		Email            string    `json:"email"`
-		StudyProgramID   uint      `json:"study_program_id"`
-		StudyProgram     string    `json:"study_program"` // String field for frontend
-		AcademicRank     string    `json:"academic_rank"`
-		AcademicRankDesc string    `json:"academic_rank_desc"`
-		EducationLevel   string    `json:"education_level"`
-		NIDN             string    `json:"nidn"`
-		UserID           int       `json:"user_id"`
-		LastSync         time.Time `json:"last_sync"`
-		CreatedAt        time.Time `json:"created_at"`
-		UpdatedAt        time.Time `json:"updated_at"`
+	page, err := h.service.ListLecturers(q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
 	}
+	lecturers := page.Items
 
 	// Map the lecturers to the response structure
-	response := make([]LecturerResponse, len(lecturers))
+	response := make([]models.LecturerResponse, len(lecturers))
 	for i, lecturer := range lecturers {
-		response[i] = LecturerResponse{
+		response[i] = models.LecturerResponse{
 			ID:               lecturer.ID,
 			EmployeeID:       lecturer.EmployeeID,
 			LecturerID:       lecturer.LecturerID,
@@ -78,7 +68,7 @@ func (h *LecturerHandler) GetAllLecturers(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": response})
+	respondList(c, "Lecturers retrieved successfully", q, models.ListResult[models.LecturerResponse]{Items: response, Page: page.Page})
 }
 
 // GetLecturerByID returns a lecturer by ID
@@ -87,19 +77,19 @@ func (h *LecturerHandler) GetLecturerByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	// Get lecturer
 	lecturer, err := h.service.GetLecturerByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get lecturer"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get lecturer: %w", err))
 		return
 	}
 
 	if lecturer == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Lecturer not found"})
+		respondErrorf(c, http.StatusNotFound, "Lecturer not found")
 		return
 	}
 
@@ -108,33 +98,45 @@ func (h *LecturerHandler) GetLecturerByID(c *gin.Context) {
 
 // SyncLecturers syncs lecturers from the campus API
 func (h *LecturerHandler) SyncLecturers(c *gin.Context) {
-	// Sync lecturers using the service (which now handles authentication internally)
-	count, err := h.service.SyncLecturers()
+	// Sync lecturers through the scheduler so it never overlaps a scheduled run
+	run, err := services.GetSyncScheduler().Run(models.SyncEntityLecturers, models.SyncTriggerManual, syncActor(c))
+	if errors.Is(err, services.ErrSyncInProgress) {
+		apierror.Respond(c, apierror.New(apierror.CodeSyncInProgress, "Lecturer sync is already running"))
+		return
+	}
+	if errors.Is(err, services.ErrSyncBlocked) {
+		respondError(c, http.StatusConflict, err)
+		return
+	}
 	if err != nil {
-		// Determine a more specific error message and status code
-		statusCode := http.StatusInternalServerError
+		// Determine a more specific error message and code, a rejected campus login is not the client's fault
+		code := apierror.CodeInternal
 		errorMsg := err.Error()
-		
+
 		// Check for authentication errors
-		if strings.Contains(errorMsg, "authentication failed") || 
-		   strings.Contains(errorMsg, "token") {
-			statusCode = http.StatusUnauthorized
+		if strings.Contains(errorMsg, "authentication failed") ||
+			strings.Contains(errorMsg, "token") {
+			code = apierror.CodeUpstreamError
 			errorMsg = "Failed to authenticate with campus API: " + errorMsg
-		} else if strings.Contains(errorMsg, "network error") || 
-		          strings.Contains(errorMsg, "timeout") {
+		} else if strings.Contains(errorMsg, "network error") ||
+			strings.Contains(errorMsg, "timeout") {
 			errorMsg = "Network error when connecting to campus API: " + errorMsg
-		} else if strings.Contains(errorMsg, "unmarshal") || 
-		          strings.Contains(errorMsg, "parse") {
+		} else if strings.Contains(errorMsg, "unmarshal") ||
+			strings.Contains(errorMsg, "parse") {
 			errorMsg = "Error processing campus API response: " + errorMsg
 		}
-		
-		c.JSON(statusCode, gin.H{"error": errorMsg})
+
+		apierror.Respond(c, apierror.New(code, errorMsg))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Lecturers synced successfully",
-		"count":   count,
+	c.JSON(http.StatusOK, models.LecturerSyncResponse{
+		Message: "Lecturers synced successfully",
+		SyncResponse: models.SyncResponse{
+			Count:     run.InsertedCount + run.UpdatedCount,
+			Failed:    run.FailedCount,
+			SyncRunID: run.ID,
+		},
 	})
 }
 
@@ -149,37 +151,21 @@ func (h *LecturerHandler) SearchLecturers(c *gin.Context) {
 	}
 	
 	if searchQuery == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error", 
-			"message": "Search query is required",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Search query is required")
 		return
 	}
 
 	// Search lecturers
 	lecturers, err := h.service.SearchLecturers(searchQuery)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status": "error",
-			"message": "Failed to search lecturers: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to search lecturers: %w", err))
 		return
 	}
 
 	// Transform the results to a simplified format for the dropdown
-	type LecturerOption struct {
-		ID       uint   `json:"id"`
-		UserID   int    `json:"user_id"` // Include UserID from lecturer table
-		FullName string `json:"full_name"`
-		NIP      string `json:"nip"`      // Include NIP which is mapped to n_ip in DB
-		NIDN     string `json:"nidn"`     // NIDN is mapped to n_id_n in DB
-		Program  string `json:"program"`
-		Email    string `json:"email,omitempty"` // Include email if available
-	}
-
-	options := make([]LecturerOption, len(lecturers))
+	options := make([]models.LecturerOption, len(lecturers))
 	for i, lecturer := range lecturers {
-		options[i] = LecturerOption{
+		options[i] = models.LecturerOption{
 			ID:       lecturer.ID,
 			UserID:   lecturer.UserID, // Include UserID in the response
 			FullName: lecturer.FullName,
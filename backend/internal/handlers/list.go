@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// filterParam matches filter[field] and filter[field][op]
+var filterParam = regexp.MustCompile(`^filter\[([a-z0-9_]+)\](?:\[([a-z]+)\])?$`)
+
+// parseListQuery reads the query parameters shared by list endpoints:
+//
+//	page, page_size         offset pagination, page_size is at most models.MaxPageSize
+//	cursor                  cursor pagination, empty for the first page and then the next_cursor of the previous one
+//	sort=-date,full_name    sort fields, descending with "-"
+//	fields=id,full_name     sparse field selection
+//	filter[status]=active   filters, or filter[field][op]=value with op eq, ne, gt, gte, lt, lte, in (comma-separated) or like
+//
+// aliases maps the plain query parameters an endpoint supported before, such as lecturer_id, to filter fields
+func parseListQuery(c *gin.Context, aliases map[string]string) (models.ListQuery, error) {
+	q := models.ListQuery{Page: 1, PageSize: models.DefaultPageSize}
+	params := c.Request.URL.Query()
+
+	if raw := params.Get("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return q, fmt.Errorf("%w: page must be a positive number", repositories.ErrInvalidListQuery)
+		}
+		q.Page = page
+	}
+	if raw := params.Get("page_size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 || size > models.MaxPageSize {
+			return q, fmt.Errorf("%w: page_size must be between 1 and %d", repositories.ErrInvalidListQuery, models.MaxPageSize)
+		}
+		q.PageSize = size
+	}
+	if _, ok := params["cursor"]; ok {
+		if params.Has("page") {
+			return q, fmt.Errorf("%w: use either page or cursor, not both", repositories.ErrInvalidListQuery)
+		}
+		q.UseCursor = true
+		q.Cursor = params.Get("cursor")
+	}
+
+	for _, field := range splitList(params.Get("sort")) {
+		sort := models.ListSort{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+		q.Sort = append(q.Sort, sort)
+	}
+	q.Fields = splitList(params.Get("fields"))
+
+	// Sorted so that errors and the generated SQL do not depend on map order
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := params.Get(key)
+		if field, ok := aliases[key]; ok {
+			q.Filters = append(q.Filters, models.ListFilter{Field: field, Op: models.FilterEq, Values: []string{value}})
+			continue
+		}
+
+		match := filterParam.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+		filter := models.ListFilter{Field: match[1], Op: match[2], Values: []string{value}}
+		if filter.Op == "" {
+			filter.Op = models.FilterEq
+		}
+		if filter.Op == models.FilterIn {
+			filter.Values = splitList(value)
+		}
+		q.Filters = append(q.Filters, filter)
+	}
+	return q, nil
+}
+
+// withAcademicYearFilter adds the academic_year_id parameter that assignment lists supported
+// before list queries as a filter, "all" is the same as leaving it out
+func withAcademicYearFilter(c *gin.Context, q models.ListQuery) models.ListQuery {
+	if academicYearID := c.Query("academic_year_id"); academicYearID != "" && academicYearID != "all" {
+		q.Filters = append(q.Filters, models.ListFilter{Field: "academic_year_id", Op: models.FilterEq, Values: []string{academicYearID}})
+	}
+	return q
+}
+
+// splitList splits a comma-separated query parameter
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// respondList writes one page of a list in the envelope shared by list endpoints,
+// {"status", "message", "data", "meta"}, with only the requested fields of each record
+func respondList[T any](c *gin.Context, message string, q models.ListQuery, page models.ListResult[T]) {
+	data, err := selectFields(page.Items, q.Fields)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": message,
+		"data":    data,
+		"meta":    page.Page,
+	})
+}
+
+// selectFields keeps only the given JSON fields of each record, all of them when fields is empty
+func selectFields[T any](items []T, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return items, nil
+	}
+
+	known := make(map[string]bool)
+	selected := make([]map[string]json.RawMessage, len(items))
+	for i, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var record map[string]json.RawMessage
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, err
+		}
+
+		selected[i] = make(map[string]json.RawMessage, len(fields))
+		for key := range record {
+			known[key] = true
+		}
+		for _, field := range fields {
+			if value, ok := record[field]; ok {
+				selected[i][field] = value
+			}
+		}
+	}
+
+	// Records only tell which fields exist when there are some
+	if len(items) > 0 {
+		for _, field := range fields {
+			if !known[field] {
+				return nil, fmt.Errorf("%w: unknown field %q", repositories.ErrInvalidListQuery, field)
+			}
+		}
+	}
+	return selected, nil
+}
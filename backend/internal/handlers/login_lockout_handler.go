@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/delpresence/backend/internal/auth"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// LoginLockoutHandler handles admin requests for locked accounts and IP addresses
+type LoginLockoutHandler struct {
+	limiter *auth.LoginLimiter
+}
+
+// NewLoginLockoutHandler creates a new login lockout handler
+func NewLoginLockoutHandler() *LoginLockoutHandler {
+	return &LoginLockoutHandler{
+		limiter: auth.Limiter,
+	}
+}
+
+// GetLockedAccounts returns all usernames and IP addresses that are currently locked
+func (h *LoginLockoutHandler) GetLockedAccounts(c *gin.Context) {
+	locked, err := h.limiter.LockedKeys()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get locked accounts: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Locked accounts retrieved successfully",
+		"data":    locked,
+	})
+}
+
+// UnlockAccount removes the lockout of a username and/or an IP address
+func (h *LoginLockoutHandler) UnlockAccount(c *gin.Context) {
+	var req models.UnlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	keys := make([]string, 0, 2)
+	if strings.TrimSpace(req.Username) != "" {
+		keys = append(keys, auth.UserKey(req.Username))
+	}
+	if strings.TrimSpace(req.IP) != "" {
+		keys = append(keys, auth.IPKey(strings.TrimSpace(req.IP)))
+	}
+	if len(keys) == 0 {
+		respondErrorf(c, http.StatusBadRequest, "Username or IP is required")
+		return
+	}
+
+	for _, key := range keys {
+		if err := h.limiter.Unlock(key); err != nil {
+			respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to unlock account: %w", err))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Account unlocked successfully",
+		"data":    keys,
+	})
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/delpresence/backend/internal/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIHandler serves the OpenAPI document of the API
+type OpenAPIHandler struct{}
+
+// NewOpenAPIHandler creates a new OpenAPI handler
+func NewOpenAPIHandler() *OpenAPIHandler {
+	return &OpenAPIHandler{}
+}
+
+// GetDocument returns the OpenAPI 3 document describing every route, for the dashboard and the mobile app
+func (h *OpenAPIHandler) GetDocument(c *gin.Context) {
+	c.JSON(http.StatusOK, openapi.Get().Document)
+}
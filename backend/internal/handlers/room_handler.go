@@ -21,39 +21,26 @@ func NewRoomHandler() *RoomHandler {
 	}
 }
 
-// GetAllRooms returns all rooms
+// roomFilterAliases are the plain filter parameters of the room list
+var roomFilterAliases = map[string]string{
+	"building_id": "building_id",
+}
+
+// GetAllRooms returns one page of rooms, see parseListQuery for the query parameters
 func (h *RoomHandler) GetAllRooms(c *gin.Context) {
-	buildingID := c.Query("building_id")
-	
-	var result interface{}
-	var err error
-
-	// Filter by building if provided
-	if buildingID != "" {
-		id, err := strconv.ParseUint(buildingID, 10, 64)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID format"})
-			return
-		}
-		
-		result, err = h.service.GetRoomsByBuildingID(uint(id))
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-	} else {
-		result, err = h.service.GetAllRooms()
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+	q, err := parseListQuery(c, roomFilterAliases)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Rooms retrieved successfully",
-		"data":    result,
-	})
+	page, err := h.service.ListRooms(q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondList(c, "Rooms retrieved successfully", q, page)
 }
 
 // GetRoomByID returns a room by ID
@@ -61,13 +48,13 @@ func (h *RoomHandler) GetRoomByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	room, err := h.service.GetRoomByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		respondError(c, http.StatusNotFound, services.ErrRoomNotFound)
 		return
 	}
 
@@ -83,12 +70,12 @@ func (h *RoomHandler) CreateRoom(c *gin.Context) {
 	var room models.Room
 
 	if err := c.ShouldBindJSON(&room); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	if err := h.service.CreateRoom(&room); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -104,20 +91,20 @@ func (h *RoomHandler) UpdateRoom(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	var room models.Room
 	if err := c.ShouldBindJSON(&room); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	room.ID = uint(id)
 
 	if err := h.service.UpdateRoom(&room); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -133,12 +120,12 @@ func (h *RoomHandler) DeleteRoom(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	if err := h.service.DeleteRoom(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
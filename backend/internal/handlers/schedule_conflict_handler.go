@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleConflictHandler handles HTTP requests related to schedule conflicts
+type ScheduleConflictHandler struct {
+	service *services.ScheduleConflictService
+}
+
+// NewScheduleConflictHandler creates a new schedule conflict handler
+func NewScheduleConflictHandler() *ScheduleConflictHandler {
+	return &ScheduleConflictHandler{
+		service: services.NewScheduleConflictService(),
+	}
+}
+
+// GetConflictReport lists the room, lecturer and student group conflicts between the schedules of an academic year
+func (h *ScheduleConflictHandler) GetConflictReport(c *gin.Context) {
+	academicYearID, err := strconv.ParseUint(c.Query("academic_year_id"), 10, 32)
+	if err != nil || academicYearID == 0 {
+		respondErrorf(c, http.StatusBadRequest, "academic_year_id is required")
+		return
+	}
+
+	report, err := h.service.Report(c.Request.Context(), uint(academicYearID))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Schedule conflicts retrieved successfully",
+		"data":    report,
+	})
+}
+
+// SetConflictStatus marks the conflict between two schedules blocking or acknowledged
+func (h *ScheduleConflictHandler) SetConflictStatus(c *gin.Context) {
+	var request models.ScheduleConflictStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	conflict, err := h.service.SetStatus(c.Request.Context(), request, c.GetString("username"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Schedule conflict status updated successfully",
+		"data":    conflict,
+	})
+}
+
+// CheckScheduleConflicts lists the conflicts a proposed schedule slot would have in its academic year
+func (h *ScheduleConflictHandler) CheckScheduleConflicts(c *gin.Context) {
+	var request models.ScheduleConflictCheckRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	check, err := h.service.Check(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Schedule conflicts checked successfully",
+		"data":    check,
+	})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleExceptionHandler handles HTTP requests related to cancelled and rescheduled classes
+type ScheduleExceptionHandler struct {
+	service *services.ScheduleExceptionService
+}
+
+// NewScheduleExceptionHandler creates a new schedule exception handler
+func NewScheduleExceptionHandler() *ScheduleExceptionHandler {
+	return &ScheduleExceptionHandler{
+		service: services.NewScheduleExceptionService(),
+	}
+}
+
+// GetScheduleExceptions returns the cancelled and rescheduled classes of a schedule
+func (h *ScheduleExceptionHandler) GetScheduleExceptions(c *gin.Context) {
+	scheduleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	exceptions, err := h.service.List(uint(scheduleID))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Schedule exceptions retrieved successfully",
+		"data":    exceptions,
+	})
+}
+
+// SaveScheduleException cancels the class of a schedule on a date or moves it to a replacement session
+func (h *ScheduleExceptionHandler) SaveScheduleException(c *gin.Context) {
+	scheduleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	var request models.ScheduleExceptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	exception, err := h.service.Save(c.Request.Context(), uint(scheduleID), request, c.GetString("username"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Schedule exception saved successfully",
+		"data":    exception,
+	})
+}
+
+// DeleteScheduleException removes an exception, the class then takes place as scheduled
+func (h *ScheduleExceptionHandler) DeleteScheduleException(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Schedule exception deleted successfully",
+	})
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleSlotHandler handles HTTP requests related to finding free schedule slots
+type ScheduleSlotHandler struct {
+	service *services.ScheduleSlotService
+}
+
+// NewScheduleSlotHandler creates a new schedule slot handler
+func NewScheduleSlotHandler() *ScheduleSlotHandler {
+	return &ScheduleSlotHandler{
+		service: services.NewScheduleSlotService(),
+	}
+}
+
+// FindFreeSlots returns ranked slot and room combinations where a new schedule would have no conflicts
+func (h *ScheduleSlotHandler) FindFreeSlots(c *gin.Context) {
+	var request models.ScheduleSlotSearchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.service.FindFreeSlots(c.Request.Context(), request)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Free slots retrieved successfully",
+		"data":    result,
+	})
+}
@@ -2,20 +2,20 @@ package handlers
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 
-	"github.com/delpresence/backend/internal/database"
 	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
 	"github.com/delpresence/backend/internal/services"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 // StudentAttendanceHandler handles attendance-related HTTP requests for students
 type StudentAttendanceHandler struct {
 	attendanceService *services.AttendanceService
 	scheduleService   *services.CourseScheduleService
-	db                *gorm.DB
+	attendanceRepo    *repositories.AttendanceRepository
 }
 
 // NewStudentAttendanceHandler creates a new student attendance handler
@@ -23,7 +23,7 @@ func NewStudentAttendanceHandler() *StudentAttendanceHandler {
 	return &StudentAttendanceHandler{
 		attendanceService: services.NewAttendanceService(),
 		scheduleService:   services.NewCourseScheduleService(),
-		db:                database.GetDB(),
+		attendanceRepo:    repositories.NewAttendanceRepository(),
 	}
 }
 
@@ -32,35 +32,32 @@ func (h *StudentAttendanceHandler) GetActiveAttendanceSessions(c *gin.Context) {
 	// Extract student ID from the authenticated user
 	userID := c.MustGet("userID").(uint)
 
-	// Log the request for debugging
-	fmt.Printf("Getting active attendance sessions for user ID=%d\n", userID)
-
 	// Get student's course schedules
-	schedules, err := h.scheduleService.GetStudentSchedules(userID)
+	schedules, err := h.scheduleService.GetStudentSchedules(c.Request.Context(), userID)
 	if err != nil {
-		fmt.Printf("Error getting student schedules for user ID=%d: %v\n", userID, err)
+		slog.ErrorContext(c.Request.Context(), "Error getting student schedules", "user_id", userID, "error", err)
 		// Return empty list instead of error
 		c.JSON(http.StatusOK, gin.H{
 			"status": "success",
-			"data":   []map[string]interface{}{},
+			"data":   []models.ActiveAttendanceSessionResponse{},
 		})
 		return
 	}
 
 	// Log how many schedules were found
-	fmt.Printf("Found %d schedules for user ID=%d\n", len(schedules), userID)
+	slog.DebugContext(c.Request.Context(), "Found student schedules", "user_id", userID, "count", len(schedules))
 
 	// If no schedules found, return empty list
 	if len(schedules) == 0 {
 		c.JSON(http.StatusOK, gin.H{
 			"status": "success",
-			"data":   []map[string]interface{}{},
+			"data":   []models.ActiveAttendanceSessionResponse{},
 		})
 		return
 	}
 
 	// Get active attendance sessions for these schedules
-	var activeSessions []map[string]interface{}
+	activeSessions := []models.ActiveAttendanceSessionResponse{}
 
 	// Extract schedule IDs
 	var scheduleIDs []uint
@@ -68,44 +65,41 @@ func (h *StudentAttendanceHandler) GetActiveAttendanceSessions(c *gin.Context) {
 		scheduleIDs = append(scheduleIDs, schedule.ID)
 	}
 
-	// Log schedule IDs for debugging
-	fmt.Printf("Checking for active sessions for schedule IDs: %v\n", scheduleIDs)
-
 	// Get active sessions for these schedules
-	sessions, err := h.attendanceService.GetActiveSessionsBySchedules(scheduleIDs)
+	sessions, err := h.attendanceService.GetActiveSessionsBySchedules(c.Request.Context(), scheduleIDs)
 	if err != nil {
-		fmt.Printf("Error getting active sessions for schedules %v: %v\n", scheduleIDs, err)
+		slog.ErrorContext(c.Request.Context(), "Error getting active sessions for schedules", "schedule_ids", scheduleIDs, "error", err)
 		// Return empty list instead of error
 		c.JSON(http.StatusOK, gin.H{
 			"status": "success",
-			"data":   []map[string]interface{}{},
+			"data":   []models.ActiveAttendanceSessionResponse{},
 		})
 		return
 	}
 
 	// Log how many active sessions were found
-	fmt.Printf("Found %d active sessions for schedules %v\n", len(sessions), scheduleIDs)
+	slog.DebugContext(c.Request.Context(), "Found active sessions", "schedule_ids", scheduleIDs, "count", len(sessions))
 
 	// Map sessions to response format
 	for _, session := range sessions {
 		// Check if CourseSchedule and related objects are loaded
 		if session.CourseSchedule.ID == 0 || session.CourseSchedule.Course.ID == 0 || session.CourseSchedule.Room.ID == 0 {
-			fmt.Printf("Warning: Session %d has incomplete related data\n", session.ID)
+			slog.WarnContext(c.Request.Context(), "Session has incomplete related data", "session_id", session.ID)
 			continue
 		}
 
-		activeSessions = append(activeSessions, map[string]interface{}{
-			"id":                 session.ID,
-			"course_schedule_id": session.CourseScheduleID,
-			"lecturer_id":        session.LecturerID,
-			"date":               session.Date.Format("2006-01-02"),
-			"start_time":         session.StartTime.Format("15:04"),
-			"type":               session.Type,
-			"status":             session.Status,
-			"course_code":        session.CourseSchedule.Course.Code,
-			"course_name":        session.CourseSchedule.Course.Name,
-			"room_name":          session.CourseSchedule.Room.Name,
-			"building_name":      session.CourseSchedule.Room.Building.Name,
+		activeSessions = append(activeSessions, models.ActiveAttendanceSessionResponse{
+			ID:               session.ID,
+			CourseScheduleID: session.CourseScheduleID,
+			LecturerID:       session.LecturerID,
+			Date:             session.Date.Format("2006-01-02"),
+			StartTime:        session.StartTime.Format("15:04"),
+			Type:             session.Type,
+			Status:           session.Status,
+			CourseCode:       session.CourseSchedule.Course.Code,
+			CourseName:       session.CourseSchedule.Course.Name,
+			RoomName:         session.CourseSchedule.Room.Name,
+			BuildingName:     session.CourseSchedule.Room.Building.Name,
 		})
 	}
 
@@ -122,108 +116,88 @@ func (h *StudentAttendanceHandler) SubmitQRAttendance(c *gin.Context) {
 	userID := c.MustGet("userID").(uint)
 
 	// Parse request body
-	var req struct {
-		SessionID          uint   `json:"session_id" binding:"required"`
-		ScheduleID         uint   `json:"schedule_id"` // Optional, used for verification
-		VerificationMethod string `json:"verification_method" binding:"required"`
-		QRData             string `json:"qr_data"`
-		Timestamp          string `json:"timestamp"`
-	}
+	var req models.QRAttendanceRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "Invalid request format",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request format")
 		return
 	}
 
 	// Log the request
-	fmt.Printf("QR attendance submission received - User: %d, Session: %d, Schedule: %d, Method: %s\n",
-		userID, req.SessionID, req.ScheduleID, req.VerificationMethod)
+	slog.InfoContext(c.Request.Context(), "QR attendance submission received",
+		"user_id", userID, "session_id", req.SessionID, "schedule_id", req.ScheduleID, "method", req.VerificationMethod)
 
 	// Ensure the verification method is QR_CODE
 	if req.VerificationMethod != "QR_CODE" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "Invalid verification method, expected QR_CODE",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid verification method, expected QR_CODE")
 		return
 	}
 
 	// If schedule ID is provided, verify that the session belongs to this schedule
 	if req.ScheduleID > 0 {
 		// Check if this session belongs to the specified schedule
-		var isValidSession bool
-		err := h.db.Raw(`
-			SELECT EXISTS (
-				SELECT 1 FROM attendance_sessions
-				WHERE id = ? AND course_schedule_id = ?
-			) as is_valid_session`,
-			req.SessionID, req.ScheduleID).Scan(&isValidSession).Error
+		isValidSession, err := h.attendanceRepo.SessionBelongsToSchedule(req.SessionID, req.ScheduleID)
 
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status": "error",
-				"error":  "Failed to validate session",
-			})
+			respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to validate session: %w", err))
 			return
 		}
 
 		if !isValidSession {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status": "error",
-				"error":  "QR code tidak sesuai dengan jadwal yang dipilih",
-			})
+			respondError(c, http.StatusBadRequest, services.ErrQRScheduleMismatch)
 			return
 		}
 	}
 
 	// Call the service to record attendance directly using the external user ID
-	err := h.attendanceService.MarkStudentAttendanceByExternalID(
+	// A repeated check-in keeps the first one and is answered with it
+	result, err := h.attendanceService.MarkStudentAttendanceByExternalID(
+		c.Request.Context(),
 		req.SessionID,
 		userID,
 		models.StudentAttendanceStatusPresent,
 		req.QRData,
 	)
 
+	// Rejected check-ins have a code of their own, anything else is our fault
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	message := "Attendance recorded successfully"
+	if result.AlreadyCheckedIn {
+		message = "Attendance was already recorded"
+	}
+
 	// Return success response
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
-		"message": "Attendance recorded successfully",
+		"message": message,
+		"data":    result,
 	})
 }
 
-// GetAttendanceHistory retrieves the attendance history for a student
+// GetAttendanceHistory retrieves one page of the attendance history for a student, latest first
+// See parseListQuery for the pagination, filter and sort parameters
 func (h *StudentAttendanceHandler) GetAttendanceHistory(c *gin.Context) {
 	// Extract student ID from the authenticated user
 	userID := c.MustGet("userID").(uint)
 
-	// Log the request
-	fmt.Printf("Getting attendance history for user ID=%d\n", userID)
+	q, err := parseListQuery(c, nil)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
 
-	// Get attendance history from service
-	history, err := h.attendanceService.GetStudentAttendanceHistory(userID)
+	// Get the page of attendance history from service
+	history, err := h.attendanceService.ListStudentAttendanceHistory(userID, q)
 	if err != nil {
-		fmt.Printf("Error getting attendance history for user ID=%d: %v\n", userID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status": "error",
-			"error":  fmt.Sprintf("Failed to fetch attendance history: %v", err),
-		})
+		slog.ErrorContext(c.Request.Context(), "Error getting attendance history", "user_id", userID, "error", err)
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch attendance history: %w", err))
 		return
 	}
 
-	// Return the attendance history
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   history,
-	})
+	// Return the page of attendance history
+	respondList(c, "Attendance history retrieved successfully", q, history)
 }
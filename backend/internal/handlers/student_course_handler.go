@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -35,10 +36,7 @@ func (h *StudentCourseHandler) GetStudentCourses(c *gin.Context) {
 	// Get the user ID from the JWT token context
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"status":  "error",
-			"message": "User not found in token",
-		})
+		respondErrorf(c, http.StatusUnauthorized, "User not found in token")
 		return
 	}
 
@@ -54,18 +52,12 @@ func (h *StudentCourseHandler) GetStudentCourses(c *gin.Context) {
 	case string:
 		id, err := strconv.Atoi(v)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid user ID format",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid user ID format")
 			return
 		}
 		userIDInt = id
 	default:
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Invalid user ID type",
-		})
+		respondError(c, http.StatusInternalServerError, errors.New("invalid user ID type"))
 		return
 	}
 
@@ -76,7 +68,7 @@ func (h *StudentCourseHandler) GetStudentCourses(c *gin.Context) {
 		// Return empty courses list instead of error when student not found
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "success",
-			"data":    []interface{}{},
+			"data":    []models.StudentCourseResponse{},
 			"message": "No student record found for the current user",
 		})
 		return
@@ -85,10 +77,7 @@ func (h *StudentCourseHandler) GetStudentCourses(c *gin.Context) {
 	// Get student groups for this student
 	studentGroups, err := h.studentGroupRepo.GetGroupsByStudentID(student.ID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get student groups",
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get student groups: %w", err))
 		return
 	}
 
@@ -96,7 +85,7 @@ func (h *StudentCourseHandler) GetStudentCourses(c *gin.Context) {
 	if len(studentGroups) == 0 {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "success",
-			"data":    []interface{}{},
+			"data":    []models.StudentCourseResponse{},
 			"message": "Student is not assigned to any groups",
 		})
 		return
@@ -108,10 +97,7 @@ func (h *StudentCourseHandler) GetStudentCourses(c *gin.Context) {
 	if academicYearIDStr != "" {
 		id, convErr := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(id)
@@ -126,24 +112,8 @@ func (h *StudentCourseHandler) GetStudentCourses(c *gin.Context) {
 		}
 	}
 
-	type CourseWithDetails struct {
-		ID               uint   `json:"id"`
-		CourseID         uint   `json:"course_id"`
-		CourseCode       string `json:"course_code"`
-		CourseName       string `json:"course_name"`
-		Credits          int    `json:"sks"`
-		Semester         int    `json:"semester"`
-		LecturerID       uint   `json:"lecturer_id"`
-		LecturerName     string `json:"lecturer_name"`
-		StudentGroupID   uint   `json:"student_group_id"`
-		StudentGroupName string `json:"student_group_name"`
-		AcademicYearID   uint   `json:"academic_year_id"`
-		AcademicYearName string `json:"academic_year_name"`
-		Description      string `json:"description,omitempty"`
-	}
-
 	// Get courses for all student groups
-	var coursesList []CourseWithDetails
+	coursesList := []models.StudentCourseResponse{}
 
 	// Process each student group
 	for _, group := range studentGroups {
@@ -184,7 +154,7 @@ func (h *StudentCourseHandler) GetStudentCourses(c *gin.Context) {
 			}
 
 			// Create course with details
-			courseWithDetails := CourseWithDetails{
+			courseWithDetails := models.StudentCourseResponse{
 				ID:               course.ID,
 				CourseID:         course.ID,
 				CourseCode:       course.Code,
@@ -216,13 +186,6 @@ func (h *StudentCourseHandler) GetStudentCourses(c *gin.Context) {
 						courseWithDetails.LecturerName = lecturer.FullName
 					}
 				}
-
-				// Add debug information
-				fmt.Printf("Course %s - Lecturer assignment found: UserID=%d, Name=%s\n",
-					course.Name, assignments[0].UserID, courseWithDetails.LecturerName)
-			} else {
-				// Add debug information
-				fmt.Printf("Course %s - No lecturer assignment found\n", course.Name)
 			}
 
 			coursesList = append(coursesList, courseWithDetails)
@@ -23,45 +23,39 @@ func NewStudentGroupHandler() *StudentGroupHandler {
 	}
 }
 
-// GetAllStudentGroups returns all student groups
+// studentGroupFilterAliases are the plain filter parameters of the student group list
+var studentGroupFilterAliases = map[string]string{
+	"department_id": "department_id",
+}
+
+// GetAllStudentGroups returns one page of student groups with their student count, see parseListQuery for the query parameters
 func (h *StudentGroupHandler) GetAllStudentGroups(c *gin.Context) {
-	// Parse query parameters for filtering
-	departmentID := c.Query("department_id")
-	
-	var groups []models.StudentGroup
-	var err error
-	
-	// Apply filters if provided
-	if departmentID != "" {
-		deptID, convErr := strconv.ParseUint(departmentID, 10, 32)
-		if convErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid department ID"})
-			return
-		}
-		groups, err = h.repo.GetByDepartment(uint(deptID))
-	} else {
-		groups, err = h.repo.GetAll()
+	q, err := parseListQuery(c, studentGroupFilterAliases)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
 	}
-	
+
+	page, err := h.repo.List(q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
-	
-	c.JSON(http.StatusOK, gin.H{"status": "success", "data": groups})
+
+	respondList(c, "Student groups retrieved successfully", q, page)
 }
 
 // GetStudentGroupByID returns a student group by ID
 func (h *StudentGroupHandler) GetStudentGroupByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student group ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student group ID")
 		return
 	}
 	
 	group, err := h.repo.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Student group not found"})
+		respondErrorf(c, http.StatusNotFound, "Student group not found")
 		return
 	}
 	
@@ -70,13 +64,10 @@ func (h *StudentGroupHandler) GetStudentGroupByID(c *gin.Context) {
 
 // CreateStudentGroup creates a new student group
 func (h *StudentGroupHandler) CreateStudentGroup(c *gin.Context) {
-	var request struct {
-		Name           string `json:"name" binding:"required"`
-		DepartmentID   uint   `json:"department_id" binding:"required"`
-	}
+	var request models.StudentGroupRequest
 	
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 	
@@ -89,7 +80,7 @@ func (h *StudentGroupHandler) CreateStudentGroup(c *gin.Context) {
 	
 	createdGroup, err := h.repo.Create(group)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	
@@ -100,24 +91,21 @@ func (h *StudentGroupHandler) CreateStudentGroup(c *gin.Context) {
 func (h *StudentGroupHandler) UpdateStudentGroup(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student group ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student group ID")
 		return
 	}
 	
 	// Verify student group exists
 	existingGroup, err := h.repo.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Student group not found"})
+		respondErrorf(c, http.StatusNotFound, "Student group not found")
 		return
 	}
 	
-	var request struct {
-		Name           string `json:"name" binding:"required"`
-		DepartmentID   uint   `json:"department_id" binding:"required"`
-	}
+	var request models.StudentGroupRequest
 	
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 	
@@ -127,7 +115,7 @@ func (h *StudentGroupHandler) UpdateStudentGroup(c *gin.Context) {
 	
 	updatedGroup, err := h.repo.Update(*existingGroup)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	
@@ -138,21 +126,21 @@ func (h *StudentGroupHandler) UpdateStudentGroup(c *gin.Context) {
 func (h *StudentGroupHandler) DeleteStudentGroup(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student group ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student group ID")
 		return
 	}
 	
 	// Verify student group exists
 	_, err = h.repo.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Student group not found"})
+		respondErrorf(c, http.StatusNotFound, "Student group not found")
 		return
 	}
 	
 	// Delete the group
 	err = h.repo.Delete(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	
@@ -163,21 +151,21 @@ func (h *StudentGroupHandler) DeleteStudentGroup(c *gin.Context) {
 func (h *StudentGroupHandler) GetGroupMembers(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student group ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student group ID")
 		return
 	}
 	
 	// Verify student group exists
 	_, err = h.repo.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Student group not found"})
+		respondErrorf(c, http.StatusNotFound, "Student group not found")
 		return
 	}
 	
 	// Get group members
 	students, err := h.repo.GetGroupMembers(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	
@@ -193,21 +181,21 @@ func (h *StudentGroupHandler) GetGroupMembers(c *gin.Context) {
 func (h *StudentGroupHandler) GetAvailableStudents(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student group ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student group ID")
 		return
 	}
 	
 	// Verify student group exists and get its department
 	group, err := h.repo.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Student group not found"})
+		respondErrorf(c, http.StatusNotFound, "Student group not found")
 		return
 	}
 	
 	// Get available students
 	students, err := h.repo.GetAvailableStudents(uint(id), group.DepartmentID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	
@@ -223,43 +211,41 @@ func (h *StudentGroupHandler) GetAvailableStudents(c *gin.Context) {
 func (h *StudentGroupHandler) AddStudentToGroup(c *gin.Context) {
 	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student group ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student group ID")
 		return
 	}
 	
-	var request struct {
-		StudentID uint `json:"student_id" binding:"required"`
-	}
+	var request models.StudentGroupMemberRequest
 	
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 	
 	// Verify student group exists
 	group, err := h.repo.GetByID(uint(groupID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Student group not found"})
+		respondErrorf(c, http.StatusNotFound, "Student group not found")
 		return
 	}
 	
 	// Verify student exists
 	student, err := h.studentRepo.FindByID(request.StudentID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Student not found"})
+		respondErrorf(c, http.StatusNotFound, "Student not found")
 		return
 	}
 	
 	// Verify student matches the group's department
 	if student.StudyProgramID != int(group.DepartmentID) {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Student's department does not match the group's department"})
+		respondErrorf(c, http.StatusBadRequest, "Student's department does not match the group's department")
 		return
 	}
 	
 	// Add student to group
 	err = h.repo.AddStudentToGroup(uint(groupID), request.StudentID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	
@@ -270,23 +256,21 @@ func (h *StudentGroupHandler) AddStudentToGroup(c *gin.Context) {
 func (h *StudentGroupHandler) AddMultipleStudentsToGroup(c *gin.Context) {
 	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student group ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student group ID")
 		return
 	}
 	
-	var request struct {
-		StudentIDs []uint `json:"student_ids" binding:"required"`
-	}
+	var request models.StudentGroupBatchRequest
 	
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 	
 	// Verify student group exists
 	_, err = h.repo.GetByID(uint(groupID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Student group not found"})
+		respondErrorf(c, http.StatusNotFound, "Student group not found")
 		return
 	}
 	
@@ -306,9 +290,9 @@ func (h *StudentGroupHandler) AddMultipleStudentsToGroup(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"message": "Students added to group",
-		"data": gin.H{
-			"success_count": successCount,
-			"failed_students": failedStudents,
+		"data": models.StudentGroupBatchResult{
+			SuccessCount: successCount,
+			FailedStudents: failedStudents,
 		},
 	})
 }
@@ -317,27 +301,27 @@ func (h *StudentGroupHandler) AddMultipleStudentsToGroup(c *gin.Context) {
 func (h *StudentGroupHandler) RemoveStudentFromGroup(c *gin.Context) {
 	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student group ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student group ID")
 		return
 	}
 	
 	studentID, err := strconv.ParseUint(c.Param("student_id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student ID")
 		return
 	}
 	
 	// Verify student group exists
 	_, err = h.repo.GetByID(uint(groupID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Student group not found"})
+		respondErrorf(c, http.StatusNotFound, "Student group not found")
 		return
 	}
 	
 	// Remove student from group
 	err = h.repo.RemoveStudentFromGroup(uint(groupID), uint(studentID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	
@@ -348,23 +332,21 @@ func (h *StudentGroupHandler) RemoveStudentFromGroup(c *gin.Context) {
 func (h *StudentGroupHandler) RemoveMultipleStudentsFromGroup(c *gin.Context) {
 	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid student group ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student group ID")
 		return
 	}
 	
-	var request struct {
-		StudentIDs []uint `json:"student_ids" binding:"required"`
-	}
+	var request models.StudentGroupBatchRequest
 	
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 	
 	// Verify student group exists
 	_, err = h.repo.GetByID(uint(groupID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Student group not found"})
+		respondErrorf(c, http.StatusNotFound, "Student group not found")
 		return
 	}
 	
@@ -384,9 +366,9 @@ func (h *StudentGroupHandler) RemoveMultipleStudentsFromGroup(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"message": "Students removed from group",
-		"data": gin.H{
-			"success_count": successCount,
-			"failed_students": failedStudents,
+		"data": models.StudentGroupBatchResult{
+			SuccessCount: successCount,
+			FailedStudents: failedStudents,
 		},
 	})
 } 
\ No newline at end of file
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/delpresence/backend/internal/apierror"
+	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/services"
 	"github.com/gin-gonic/gin"
 )
@@ -21,19 +24,21 @@ func NewStudentHandler() *StudentHandler {
 	}
 }
 
-// GetAllStudents returns all students
+// GetAllStudents returns one page of students, see parseListQuery for the query parameters
 func (h *StudentHandler) GetAllStudents(c *gin.Context) {
-	students, err := h.service.GetAllStudents()
+	q, err := parseListQuery(c, nil)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Students retrieved successfully",
-		"data":    students,
-	})
+	students, err := h.service.ListStudents(q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondList(c, "Students retrieved successfully", q, students)
 }
 
 // GetStudentByID returns a student by ID
@@ -41,13 +46,13 @@ func (h *StudentHandler) GetStudentByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	student, err := h.service.GetStudentByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		respondErrorf(c, http.StatusNotFound, "Student not found")
 		return
 	}
 
@@ -63,18 +68,18 @@ func (h *StudentHandler) GetStudentByUserID(c *gin.Context) {
 	userIDStr := c.Param("user_id")
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid user ID format")
 		return
 	}
 
 	student, err := h.service.GetStudentByUserID(userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		respondErrorf(c, http.StatusNotFound, "Student not found")
 		return
 	}
 
 	if student == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		respondErrorf(c, http.StatusNotFound, "Student not found")
 		return
 	}
 
@@ -87,35 +92,41 @@ func (h *StudentHandler) GetStudentByUserID(c *gin.Context) {
 
 // SyncStudents syncs students from the campus API
 func (h *StudentHandler) SyncStudents(c *gin.Context) {
-	// Sync students using the service
-	count, err := h.service.SyncStudents()
+	// Sync students through the scheduler so it never overlaps a scheduled run
+	run, err := services.GetSyncScheduler().Run(models.SyncEntityStudents, models.SyncTriggerManual, syncActor(c))
+	if errors.Is(err, services.ErrSyncInProgress) {
+		apierror.Respond(c, apierror.New(apierror.CodeSyncInProgress, "Student sync is already running"))
+		return
+	}
+	if errors.Is(err, services.ErrSyncBlocked) {
+		respondError(c, http.StatusConflict, err)
+		return
+	}
 	if err != nil {
 		errMsg := err.Error()
-		statusCode := http.StatusInternalServerError
+		code := apierror.CodeInternal
 		responseMsg := "Failed to sync students"
 
 		// Check for specific errors to provide better messages
 		if strings.Contains(errMsg, "timeout") || strings.Contains(errMsg, "deadline exceeded") {
-			statusCode = http.StatusGatewayTimeout
+			code = apierror.CodeUpstreamTimeout
 			responseMsg = "Connection to campus API timed out"
 		} else if strings.Contains(errMsg, "connection refused") {
-			statusCode = http.StatusServiceUnavailable
+			code = apierror.CodeUnavailable
 			responseMsg = "Campus API service unavailable"
 		}
 
-		c.JSON(statusCode, gin.H{
-			"status":  "error",
-			"message": responseMsg,
-			"error":   errMsg,
-		})
+		apierror.Respond(c, apierror.Newf(code, "%s: %s", responseMsg, errMsg))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "Students synced successfully",
-		"data": gin.H{
-			"count": count,
+		"data": models.SyncResponse{
+			Count:     run.InsertedCount + run.UpdatedCount,
+			Failed:    run.FailedCount,
+			SyncRunID: run.ID,
 		},
 	})
 }
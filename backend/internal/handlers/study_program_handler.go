@@ -24,46 +24,36 @@ func NewStudyProgramHandler() *StudyProgramHandler {
 	}
 }
 
-// GetAllStudyPrograms returns all study programs
+// studyProgramFilterAliases are the plain filter parameters of the study program list
+var studyProgramFilterAliases = map[string]string{
+	"faculty_id": "faculty_id",
+}
+
+// GetAllStudyPrograms returns one page of study programs, see parseListQuery for the query parameters
+// With stats=true each record comes with its statistics
 func (h *StudyProgramHandler) GetAllStudyPrograms(c *gin.Context) {
-	stats := c.Query("stats")
-	facultyID := c.Query("faculty_id")
-	
-	var result interface{}
-	var err error
+	q, err := parseListQuery(c, studyProgramFilterAliases)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
 
-	// Filter by faculty if provided
-	if facultyID != "" {
-		id, err := strconv.ParseUint(facultyID, 10, 64)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Format ID fakultas tidak valid"})
-			return
-		}
-		
-		result, err = h.service.GetStudyProgramsByFacultyID(uint(id))
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-	} else if stats == "true" {
-		result, err = h.service.GetAllStudyProgramsWithStats()
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-	} else {
-		result, err = h.service.GetAllStudyPrograms()
+	if c.Query("stats") == "true" {
+		page, err := h.service.ListStudyProgramsWithStats(q)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, http.StatusInternalServerError, err)
 			return
 		}
+		respondList(c, "Program studi berhasil diambil", q, page)
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Program studi berhasil diambil",
-		"data":    result,
-	})
+	page, err := h.service.ListStudyPrograms(q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	respondList(c, "Program studi berhasil diambil", q, page)
 }
 
 // GetStudyProgramByID returns a study program by ID
@@ -71,7 +61,7 @@ func (h *StudyProgramHandler) GetStudyProgramByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format ID tidak valid"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
@@ -85,7 +75,7 @@ func (h *StudyProgramHandler) GetStudyProgramByID(c *gin.Context) {
 	}
 
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Program studi tidak ditemukan"})
+		respondError(c, http.StatusNotFound, services.ErrStudyProgramNotFound)
 		return
 	}
 
@@ -101,7 +91,7 @@ func (h *StudyProgramHandler) CreateStudyProgram(c *gin.Context) {
 	var program models.StudyProgram
 
 	if err := c.ShouldBindJSON(&program); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format data tidak valid"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid data format")
 		return
 	}
 
@@ -114,7 +104,7 @@ func (h *StudyProgramHandler) CreateStudyProgram(c *gin.Context) {
 	}
 
 	if err := h.service.CreateStudyProgram(&program); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -130,13 +120,13 @@ func (h *StudyProgramHandler) UpdateStudyProgram(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format ID tidak valid"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	var program models.StudyProgram
 	if err := c.ShouldBindJSON(&program); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format data tidak valid"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid data format")
 		return
 	}
 
@@ -150,7 +140,7 @@ func (h *StudyProgramHandler) UpdateStudyProgram(c *gin.Context) {
 
 	program.ID = uint(id)
 	if err := h.service.UpdateStudyProgram(&program); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -166,35 +156,21 @@ func (h *StudyProgramHandler) DeleteStudyProgram(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "Format ID tidak valid",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
 		return
 	}
 
 	err = h.service.DeleteStudyProgram(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = services.ErrStudyProgramNotFound
+	}
+	if err != nil && strings.Contains(err.Error(), "foreign key constraint") {
+		respondErrorf(c, http.StatusConflict, "The study program still has related data, delete it first")
+		return
+	}
 	if err != nil {
-		// Handle different types of errors with appropriate status codes
-		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{
-				"status": "error",
-				"error":  "Program studi tidak ditemukan",
-			})
-			return
-		} else if strings.Contains(err.Error(), "foreign key constraint") {
-			c.JSON(http.StatusConflict, gin.H{
-				"status": "error",
-				"error":  "Tidak dapat menghapus program studi yang memiliki data terkait. Harap hapus semua data terkait terlebih dahulu.",
-			})
-			return
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status": "error",
-				"error":  err.Error(),
-			})
-			return
-		}
+		respondError(c, http.StatusInternalServerError, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// SyncHandler handles HTTP requests related to campus sync scheduling
+type SyncHandler struct {
+	scheduler *services.SyncScheduler
+}
+
+// NewSyncHandler creates a new sync handler
+func NewSyncHandler() *SyncHandler {
+	return &SyncHandler{
+		scheduler: services.GetSyncScheduler(),
+	}
+}
+
+// GetSyncStatus returns the schedule, next run and latest run of every synced entity
+func (h *SyncHandler) GetSyncStatus(c *gin.Context) {
+	statuses, err := h.scheduler.Status()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Sync status retrieved successfully",
+		"data":    statuses,
+	})
+}
+
+// GetSyncFreshness returns when every entity was last synced successfully and whether its data is stale
+func (h *SyncHandler) GetSyncFreshness(c *gin.Context) {
+	freshness, err := h.scheduler.Freshness()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Sync freshness retrieved successfully",
+		"data":    freshness,
+	})
+}
+
+// syncRunFilterAliases are the plain filter parameters of the sync run list
+var syncRunFilterAliases = map[string]string{
+	"entity": "entity",
+	"status": "status",
+}
+
+// GetSyncRuns returns one page of sync runs, see parseListQuery for the query parameters
+func (h *SyncHandler) GetSyncRuns(c *gin.Context) {
+	q, err := parseListQuery(c, syncRunFilterAliases)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := h.scheduler.ListRuns(q)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondList(c, "Sync runs retrieved successfully", q, page)
+}
+
+// GetSyncRunByID returns a sync run with its per-record errors
+func (h *SyncHandler) GetSyncRunByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid sync run ID")
+		return
+	}
+
+	run, err := h.scheduler.GetRun(uint(id))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if run == nil {
+		respondErrorf(c, http.StatusNotFound, "Sync run not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Sync run retrieved successfully",
+		"data":    run,
+	})
+}
+
+// PreviewSync fetches an entity from the campus API and returns the diff against the database
+// Nothing is saved until the preview is applied
+func (h *SyncHandler) PreviewSync(c *gin.Context) {
+	preview, err := h.scheduler.Preview(c.Param("entity"), c.GetString("username"))
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownSyncEntity) {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondError(c, http.StatusBadGateway, fmt.Errorf("failed to fetch from campus API: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Sync preview created successfully",
+		"data":    preview,
+	})
+}
+
+// GetSyncPreview returns a sync preview by ID
+func (h *SyncHandler) GetSyncPreview(c *gin.Context) {
+	preview, err := h.scheduler.GetPreview(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Sync preview retrieved successfully",
+		"data":    preview,
+	})
+}
+
+// ApplySyncPreview saves the records of a sync preview
+func (h *SyncHandler) ApplySyncPreview(c *gin.Context) {
+	var req models.SyncApplyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	run, err := h.scheduler.ApplyPreview(c.Param("id"), req.Force, syncActor(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Sync preview applied successfully",
+		"data":    run,
+	})
+}
+
+// syncActor returns the admin making the request, recorded on manual sync runs
+func syncActor(c *gin.Context) *services.SyncActor {
+	return &services.SyncActor{UserID: c.GetUint("userID"), Username: c.GetString("username")}
+}
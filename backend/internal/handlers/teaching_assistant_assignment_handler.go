@@ -2,12 +2,12 @@ package handlers
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sort"
 	"strconv"
 	"strings"
 
-	"log"
 
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/repositories"
@@ -27,33 +27,20 @@ func NewTeachingAssistantAssignmentHandler() *TeachingAssistantAssignmentHandler
 
 // CreateTeachingAssistantAssignment creates a new teaching assistant assignment
 func (h *TeachingAssistantAssignmentHandler) CreateTeachingAssistantAssignment(c *gin.Context) {
-	var input struct {
-		EmployeeID     uint `json:"employee_id" binding:"required"`
-		CourseID       uint `json:"course_id" binding:"required"`
-		AcademicYearID uint `json:"academic_year_id"`
-	}
+	var input models.TeachingAssistantAssignmentRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid input: " + err.Error(),
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid input: %v", err)
 		return
 	}
 
 	// Get the lecturer ID from the token (the logged-in user who is making the assignment)
 	userID, exists := c.Get("userID") // Changed from user_id to userID to match middleware
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"status":  "error",
-			"message": "User ID not found in token",
-		})
+		respondErrorf(c, http.StatusUnauthorized, "User ID not found in token")
 		return
 	}
 
-	// Debug the userID value
-	log.Printf("CreateTeachingAssistantAssignment userID: %v, type: %T", userID, userID)
-
 	// Convert userID to uint regardless of its original type
 	var userIDUint uint
 	switch v := userID.(type) {
@@ -72,18 +59,12 @@ func (h *TeachingAssistantAssignmentHandler) CreateTeachingAssistantAssignment(c
 	case string:
 		uintVal, err := strconv.ParseUint(v, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid user ID format",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid user ID format")
 			return
 		}
 		userIDUint = uint(uintVal)
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid user ID type",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid user ID type")
 		return
 	}
 
@@ -109,28 +90,19 @@ func (h *TeachingAssistantAssignmentHandler) CreateTeachingAssistantAssignment(c
 	employeeRepo := repositories.NewEmployeeRepository()
 	employee, err := employeeRepo.FindByID(input.EmployeeID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Gagal menemukan data employee: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to find employee: %w", err))
 		return
 	}
 
 	// Check if an assignment already exists for this teaching assistant and course
 	exists, err = h.repo.AssignmentExistsForCourse(employee.UserID, input.CourseID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Gagal memeriksa penugasan yang ada: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to check existing assignments: %w", err))
 		return
 	}
 
 	if exists {
-		c.JSON(http.StatusConflict, gin.H{
-			"status":  "error",
-			"message": "Asisten dosen sudah ditugaskan pada mata kuliah ini",
-		})
+		respondErrorf(c, http.StatusConflict, "This teaching assistant is already assigned to this course")
 		return
 	}
 
@@ -144,10 +116,7 @@ func (h *TeachingAssistantAssignmentHandler) CreateTeachingAssistantAssignment(c
 
 	result, err := h.repo.Create(assignment)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Gagal membuat penugasan asisten dosen: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to create teaching assistant assignment: %w", err))
 		return
 	}
 
@@ -158,57 +127,34 @@ func (h *TeachingAssistantAssignmentHandler) CreateTeachingAssistantAssignment(c
 	})
 }
 
-// GetAllTeachingAssistantAssignments returns all teaching assistant assignments
+// GetAllTeachingAssistantAssignments returns one page of teaching assistant assignments with their course, academic year and employee, see parseListQuery for the query parameters
 func (h *TeachingAssistantAssignmentHandler) GetAllTeachingAssistantAssignments(c *gin.Context) {
-	// Get academic year ID from query parameter, if provided
-	academicYearIDStr := c.Query("academic_year_id")
-	var academicYearID uint = 0
-
-	if academicYearIDStr != "" && academicYearIDStr != "all" {
-		academicYearIDUint, err := strconv.ParseUint(academicYearIDStr, 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
-			return
-		}
-		academicYearID = uint(academicYearIDUint)
+	q, err := parseListQuery(c, nil)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
 	}
 
-	// Get detailed assignments
-	assignments, err := h.repo.GetTeachingAssistantAssignmentResponses(academicYearID)
+	page, err := h.repo.ListResponses(withAcademicYearFilter(c, q))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get assignments: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   assignments,
-	})
+	respondList(c, "Teaching assistant assignments retrieved successfully", q, page)
 }
 
 // GetTeachingAssistantAssignmentByID returns a specific teaching assistant assignment
 func (h *TeachingAssistantAssignmentHandler) GetTeachingAssistantAssignmentByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid assignment ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid assignment ID")
 		return
 	}
 
 	assignment, err := h.repo.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Assignment not found",
-		})
+		respondErrorf(c, http.StatusNotFound, "Assignment not found")
 		return
 	}
 
@@ -222,38 +168,26 @@ func (h *TeachingAssistantAssignmentHandler) GetTeachingAssistantAssignmentByID(
 func (h *TeachingAssistantAssignmentHandler) DeleteTeachingAssistantAssignment(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid assignment ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid assignment ID")
 		return
 	}
 
-	log.Printf("Attempting to delete teaching assistant assignment with ID: %d", id)
-
 	// Check if the assignment exists
 	_, err = h.repo.GetByID(uint(id))
 	if err != nil {
-		log.Printf("Error getting teaching assistant assignment: %v", err)
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Assignment not found",
-		})
+		respondErrorf(c, http.StatusNotFound, "Assignment not found")
 		return
 	}
 
 	// Delete the assignment
 	err = h.repo.Delete(uint(id))
 	if err != nil {
-		log.Printf("Error deleting teaching assistant assignment: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to delete assignment: " + err.Error(),
-		})
+		slog.ErrorContext(c.Request.Context(), "Error deleting teaching assistant assignment", "id", id, "error", err)
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to delete assignment: %w", err))
 		return
 	}
 
-	log.Printf("Successfully deleted teaching assistant assignment with ID: %d", id)
+	slog.InfoContext(c.Request.Context(), "Deleted teaching assistant assignment", "id", id)
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
 		"message": "Assignment deleted successfully",
@@ -264,10 +198,7 @@ func (h *TeachingAssistantAssignmentHandler) DeleteTeachingAssistantAssignment(c
 func (h *TeachingAssistantAssignmentHandler) GetAssignmentsByTeachingAssistant(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid teaching assistant ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid teaching assistant ID")
 		return
 	}
 
@@ -278,10 +209,7 @@ func (h *TeachingAssistantAssignmentHandler) GetAssignmentsByTeachingAssistant(c
 	if academicYearIDStr != "" && academicYearIDStr != "all" {
 		academicYearIDUint, err := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(academicYearIDUint)
@@ -290,10 +218,7 @@ func (h *TeachingAssistantAssignmentHandler) GetAssignmentsByTeachingAssistant(c
 	// Get assignments for the teaching assistant
 	assignments, err := h.repo.GetByEmployeeID(uint(id), academicYearID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get assignments: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get assignments: %w", err))
 		return
 	}
 
@@ -307,10 +232,7 @@ func (h *TeachingAssistantAssignmentHandler) GetAssignmentsByTeachingAssistant(c
 func (h *TeachingAssistantAssignmentHandler) GetAssignmentsByCourse(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid course ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid course ID")
 		return
 	}
 
@@ -321,10 +243,7 @@ func (h *TeachingAssistantAssignmentHandler) GetAssignmentsByCourse(c *gin.Conte
 	if academicYearIDStr != "" && academicYearIDStr != "all" {
 		academicYearIDUint, err := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(academicYearIDUint)
@@ -333,10 +252,7 @@ func (h *TeachingAssistantAssignmentHandler) GetAssignmentsByCourse(c *gin.Conte
 	// Get assignments for the course
 	assignments, err := h.repo.GetByCourseID(uint(id), academicYearID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get assignments: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get assignments: %w", err))
 		return
 	}
 
@@ -350,16 +266,10 @@ func (h *TeachingAssistantAssignmentHandler) GetAssignmentsByCourse(c *gin.Conte
 func (h *TeachingAssistantAssignmentHandler) GetAvailableTeachingAssistants(c *gin.Context) {
 	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		log.Printf("Error parsing course ID: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid course ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid course ID")
 		return
 	}
 
-	log.Printf("Getting available teaching assistants for course ID: %d", courseID)
-
 	// Get academic year ID from query parameter, if provided
 	academicYearIDStr := c.Query("academic_year_id")
 	var academicYearID uint = 0
@@ -367,11 +277,7 @@ func (h *TeachingAssistantAssignmentHandler) GetAvailableTeachingAssistants(c *g
 	if academicYearIDStr != "" {
 		academicYearIDUint, err := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if err != nil {
-			log.Printf("Error parsing academic year ID: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(academicYearIDUint)
@@ -388,26 +294,19 @@ func (h *TeachingAssistantAssignmentHandler) GetAvailableTeachingAssistants(c *g
 				return academicYears[i].ID > academicYears[j].ID
 			})
 			academicYearID = academicYears[0].ID
-			log.Printf("Using most recent academic year ID: %d", academicYearID)
 		} else if err != nil {
-			log.Printf("Error fetching academic years: %v", err)
-		} else {
-			log.Printf("No academic years found, continuing without filtering by academic year")
+			slog.ErrorContext(c.Request.Context(), "Error fetching academic years", "error", err)
 		}
 	}
 
 	// Get available teaching assistants
 	employees, err := h.repo.GetAvailableTeachingAssistants(uint(courseID), academicYearID)
 	if err != nil {
-		log.Printf("Error getting available teaching assistants: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get available teaching assistants: " + err.Error(),
-		})
+		slog.ErrorContext(c.Request.Context(), "Error getting available teaching assistants", "course_id", courseID, "error", err)
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get available teaching assistants: %w", err))
 		return
 	}
 
-	log.Printf("Successfully retrieved %d available teaching assistants", len(employees))
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"data":   employees,
@@ -418,10 +317,7 @@ func (h *TeachingAssistantAssignmentHandler) GetAvailableTeachingAssistants(c *g
 func (h *TeachingAssistantAssignmentHandler) GetAssignmentsByLecturer(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid lecturer ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid lecturer ID")
 		return
 	}
 
@@ -432,10 +328,7 @@ func (h *TeachingAssistantAssignmentHandler) GetAssignmentsByLecturer(c *gin.Con
 	if academicYearIDStr != "" && academicYearIDStr != "all" {
 		academicYearIDUint, err := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(academicYearIDUint)
@@ -444,10 +337,7 @@ func (h *TeachingAssistantAssignmentHandler) GetAssignmentsByLecturer(c *gin.Con
 	// Get assignments for the lecturer
 	assignments, err := h.repo.GetByLecturerID(uint(id), academicYearID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get assignments: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get assignments: %w", err))
 		return
 	}
 
@@ -462,10 +352,7 @@ func (h *TeachingAssistantAssignmentHandler) GetMyTeachingAssistantAssignments(c
 	// Get user ID from token - changed from user_id to userID to match middleware
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"status":  "error",
-			"message": "User ID not found in token",
-		})
+		respondErrorf(c, http.StatusUnauthorized, "User ID not found in token")
 		return
 	}
 
@@ -476,18 +363,12 @@ func (h *TeachingAssistantAssignmentHandler) GetMyTeachingAssistantAssignments(c
 	if academicYearIDStr != "" && academicYearIDStr != "all" {
 		academicYearIDUint, err := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(academicYearIDUint)
 	}
 
-	// Debug the userID value
-	log.Printf("GetMyTeachingAssistantAssignments userID: %v, type: %T", userID, userID)
-
 	// Convert userID to uint regardless of its original type
 	var userIDUint uint
 	switch v := userID.(type) {
@@ -506,28 +387,19 @@ func (h *TeachingAssistantAssignmentHandler) GetMyTeachingAssistantAssignments(c
 	case string:
 		uintVal, err := strconv.ParseUint(v, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid user ID format",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid user ID format")
 			return
 		}
 		userIDUint = uint(uintVal)
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid user ID type",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid user ID type")
 		return
 	}
 
 	// Get assignments for the lecturer
 	assignments, err := h.repo.GetByLecturerID(userIDUint, academicYearID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get assignments: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get assignments: %w", err))
 		return
 	}
 
@@ -542,10 +414,7 @@ func (h *TeachingAssistantAssignmentHandler) GetMyAssignedSchedules(c *gin.Conte
 	// Get the user ID from the token (the logged-in teaching assistant)
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"status":  "error",
-			"message": "User ID not found in token",
-		})
+		respondErrorf(c, http.StatusUnauthorized, "User ID not found in token")
 		return
 	}
 
@@ -576,10 +445,7 @@ func (h *TeachingAssistantAssignmentHandler) GetMyAssignedSchedules(c *gin.Conte
 	}
 
 	if userIDUint == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid user ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid user ID")
 		return
 	}
 
@@ -590,10 +456,7 @@ func (h *TeachingAssistantAssignmentHandler) GetMyAssignedSchedules(c *gin.Conte
 	if academicYearIDStr != "" && academicYearIDStr != "all" {
 		academicYearIDUint, err := strconv.ParseUint(academicYearIDStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid academic year ID",
-			})
+			respondErrorf(c, http.StatusBadRequest, "Invalid academic year ID")
 			return
 		}
 		academicYearID = uint(academicYearIDUint)
@@ -614,7 +477,7 @@ func (h *TeachingAssistantAssignmentHandler) GetMyAssignedSchedules(c *gin.Conte
 		employee, err := employeeRepo.FindByUserID(int(userIDUint))
 		if err != nil || employee == nil {
 			// Instead of failing, create a response for new assistants who don't have employee records yet
-			log.Printf("Employee record not found for user ID %d. This may be a new teaching assistant.", userIDUint)
+			slog.DebugContext(c.Request.Context(), "Employee record not found, may be a new teaching assistant", "user_id", userIDUint)
 			c.JSON(http.StatusOK, gin.H{
 				"status":  "success",
 				"data":    []models.CourseSchedule{}, // Return empty schedules array
@@ -628,10 +491,7 @@ func (h *TeachingAssistantAssignmentHandler) GetMyAssignedSchedules(c *gin.Conte
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get assignments: " + err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get assignments: %w", err))
 		return
 	}
 
@@ -32,28 +32,17 @@ func (h *TeachingAssistantAttendanceHandler) CreateAttendanceSession(c *gin.Cont
 	userID := c.MustGet("userID").(uint)
 
 	// Parse request
-	var req struct {
-		CourseScheduleID uint                   `json:"course_schedule_id"`
-		Type             string                 `json:"type"`
-		Date             string                 `json:"date"`
-		Settings         map[string]interface{} `json:"settings"`
-	}
+	var req models.AttendanceSessionRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid request body",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	// Convert date string to time.Time
 	date, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid date format, use YYYY-MM-DD",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid date format, use YYYY-MM-DD")
 		return
 	}
 
@@ -69,30 +58,21 @@ func (h *TeachingAssistantAttendanceHandler) CreateAttendanceSession(c *gin.Cont
 	case "BOTH":
 		attendanceType = models.AttendanceTypeBoth
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid attendance type",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid attendance type")
 		return
 	}
 
 	// Create the session
-	session, err := h.attendanceService.CreateAttendanceSession(userID, req.CourseScheduleID, date, attendanceType, req.Settings)
+	session, err := h.attendanceService.CreateAttendanceSession(c.Request.Context(), userID, req.CourseScheduleID, date, attendanceType, req.Settings)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	// Get the response format
 	response, err := h.attendanceService.GetSessionDetails(session.ID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Session created but error retrieving details",
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("session created but error retrieving details: %w", err))
 		return
 	}
 
@@ -109,12 +89,9 @@ func (h *TeachingAssistantAttendanceHandler) GetActiveAttendanceSessions(c *gin.
 	userID := c.MustGet("userID").(uint)
 
 	// Get active sessions using the new function that supports TAs
-	sessions, err := h.attendanceService.GetActiveSessionsForUser(userID)
+	sessions, err := h.attendanceService.GetActiveSessionsForUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to get active sessions",
-		})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to get active sessions: %w", err))
 		return
 	}
 
@@ -145,40 +122,34 @@ func (h *TeachingAssistantAttendanceHandler) GetAttendanceSessions(c *gin.Contex
 	// Parse dates
 	startDate, err := time.Parse("2006-01-02", startDateStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid start_date format, use YYYY-MM-DD",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid start_date format, use YYYY-MM-DD")
 		return
 	}
 
 	endDate, err := time.Parse("2006-01-02", endDateStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid end_date format, use YYYY-MM-DD",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid end_date format, use YYYY-MM-DD")
 		return
 	}
 
 	// Set end time to end of day
 	endDate = endDate.Add(24*time.Hour - time.Second)
 
+	q, err := parseListQuery(c, nil)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
 	// Get sessions (now supports teaching assistants properly)
-	sessions, err := h.attendanceService.GetSessionsByDateRange(userID, startDate, endDate)
+	sessions, err := h.attendanceService.ListSessionsByDateRange(c.Request.Context(), userID, startDate, endDate, q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	// Return sessions
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   sessions,
-	})
+	// Return the page of sessions
+	respondList(c, "Attendance sessions retrieved successfully", q, sessions)
 }
 
 // GetAttendanceSessionDetails gets detailed information for a specific attendance session
@@ -189,20 +160,14 @@ func (h *TeachingAssistantAttendanceHandler) GetAttendanceSessionDetails(c *gin.
 	// Extract session ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid session ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	// Get session details
 	session, err := h.attendanceService.GetSessionDetails(uint(sessionID), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -221,19 +186,13 @@ func (h *TeachingAssistantAttendanceHandler) CloseAttendanceSession(c *gin.Conte
 	// Extract session ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid session ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	// Close the session
 	if err := h.attendanceService.CloseAttendanceSession(uint(sessionID), userID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -252,20 +211,14 @@ func (h *TeachingAssistantAttendanceHandler) GetStudentAttendances(c *gin.Contex
 	// Extract session ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid session ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	// Get student attendances
 	attendances, err := h.attendanceService.GetStudentAttendances(uint(sessionID), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -284,34 +237,21 @@ func (h *TeachingAssistantAttendanceHandler) MarkStudentAttendance(c *gin.Contex
 	// Extract session ID and student ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid session ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	studentID, err := strconv.ParseUint(c.Param("studentId"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid student ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid student ID")
 		return
 	}
 
 	// Parse request
-	var req struct {
-		Status             string `json:"status"`
-		Notes              string `json:"notes"`
-		VerificationMethod string `json:"verification_method"`
-	}
+	var req models.MarkAttendanceRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid request body",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
@@ -327,19 +267,13 @@ func (h *TeachingAssistantAttendanceHandler) MarkStudentAttendance(c *gin.Contex
 	case "EXCUSED":
 		status = models.StudentAttendanceStatusExcused
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid attendance status",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid attendance status")
 		return
 	}
 
 	// Mark student attendance
 	if err := h.attendanceService.MarkStudentAttendance(uint(sessionID), uint(studentID), status, req.VerificationMethod, req.Notes, &userID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -358,37 +292,28 @@ func (h *TeachingAssistantAttendanceHandler) GetQRCode(c *gin.Context) {
 	// Extract session ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid session ID",
-		})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	// Get session to verify ownership
 	session, err := h.attendanceService.GetSessionDetails(uint(sessionID), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
 	// Check if the session uses QR code
 	if session.Type != "QR Code" && session.Type != "Keduanya" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "This session does not use QR code",
-		})
+		respondErrorf(c, http.StatusBadRequest, "This session does not use QR code")
 		return
 	}
 
 	// Return QR code URL
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
-		"data": map[string]interface{}{
-			"qr_code_url": "/api/attendance/qr-code/" + strconv.FormatUint(sessionID, 10),
+		"data": models.AttendanceQRCodeResponse{
+			QRCodeURL: "/api/attendance/qr-code/" + strconv.FormatUint(sessionID, 10),
 		},
 	})
 }
@@ -401,21 +326,21 @@ func (h *TeachingAssistantAttendanceHandler) DownloadAttendanceReport(c *gin.Con
 	// Extract session ID from URL
 	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		respondErrorf(c, http.StatusBadRequest, "Invalid session ID")
 		return
 	}
 
 	// Get session details
 	session, err := h.attendanceService.GetSessionDetails(uint(sessionID), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
 	// Get all student attendances for this session
 	attendances, err := h.attendanceService.GetStudentAttendances(uint(sessionID), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve attendance data"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to retrieve attendance data: %w", err))
 		return
 	}
 
@@ -425,7 +350,7 @@ func (h *TeachingAssistantAttendanceHandler) DownloadAttendanceReport(c *gin.Con
 	// Create main summary sheet
 	summarySheet, err := file.AddSheet("Summary")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create Excel sheet"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to create Excel sheet: %w", err))
 		return
 	}
 
@@ -522,7 +447,7 @@ func (h *TeachingAssistantAttendanceHandler) DownloadAttendanceReport(c *gin.Con
 	// Create detailed attendance sheet
 	detailSheet, err := file.AddSheet("Daftar Hadir")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create detail sheet"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to create detail sheet: %w", err))
 		return
 	}
 
@@ -669,7 +594,7 @@ func (h *TeachingAssistantAttendanceHandler) DownloadAttendanceReport(c *gin.Con
 	// Write file to response
 	err = file.Write(c.Writer)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate Excel file"})
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("failed to generate Excel file: %w", err))
 		return
 	}
 }
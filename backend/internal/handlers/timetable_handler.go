@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// TimetableHandler handles HTTP requests related to generated timetable drafts
+type TimetableHandler struct {
+	service *services.TimetableService
+}
+
+// NewTimetableHandler creates a new timetable handler
+func NewTimetableHandler() *TimetableHandler {
+	return &TimetableHandler{
+		service: services.NewTimetableService(),
+	}
+}
+
+// GenerateDraft solves the timetable of an academic year and stores it as a draft for review
+func (h *TimetableHandler) GenerateDraft(c *gin.Context) {
+	var request models.TimetableSolveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	draft, err := h.service.Generate(c.Request.Context(), request, c.GetString("username"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Timetable draft generated successfully",
+		"data":    draft,
+	})
+}
+
+// ListDrafts returns the timetable drafts of an academic year
+func (h *TimetableHandler) ListDrafts(c *gin.Context) {
+	academicYearID, err := strconv.ParseUint(c.Query("academic_year_id"), 10, 32)
+	if err != nil || academicYearID == 0 {
+		respondErrorf(c, http.StatusBadRequest, "academic_year_id is required")
+		return
+	}
+
+	drafts, err := h.service.List(uint(academicYearID))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Timetable drafts retrieved successfully",
+		"data":    drafts,
+	})
+}
+
+// GetDraft returns a timetable draft with its classes
+func (h *TimetableHandler) GetDraft(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	draft, err := h.service.Get(uint(id))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Timetable draft retrieved successfully",
+		"data":    draft,
+	})
+}
+
+// CommitDraft creates the course schedules of a timetable draft in one transaction
+func (h *TimetableHandler) CommitDraft(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	draft, err := h.service.Commit(c.Request.Context(), uint(id), c.GetString("username"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Timetable draft committed successfully",
+		"data":    draft,
+	})
+}
+
+// DeleteDraft discards a timetable draft that was not committed
+func (h *TimetableHandler) DeleteDraft(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid ID format")
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Timetable draft deleted successfully",
+	})
+}
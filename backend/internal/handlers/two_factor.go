@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/delpresence/backend/internal/apierror"
+	"github.com/delpresence/backend/internal/auth"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+// twoFactorError maps two-factor errors to an API error
+func twoFactorError(err error) *apierror.Error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrUserNotFound):
+		return apierror.New(apierror.CodeInvalidToken, "Invalid or expired challenge token")
+	case errors.Is(err, auth.ErrInvalidTwoFactorCode):
+		return apierror.New(apierror.CodeInvalidTwoFactorCode, "Invalid two-factor code")
+	case errors.Is(err, auth.ErrTwoFactorNotEnabled):
+		return apierror.New(apierror.CodeTwoFactorNotEnabled, "Two-factor authentication is not enabled")
+	case errors.Is(err, auth.ErrTwoFactorAlreadyEnabled):
+		return apierror.New(apierror.CodeTwoFactorAlreadyEnabled, "Two-factor authentication is already enabled")
+	case errors.Is(err, auth.ErrTwoFactorNotStarted):
+		return apierror.New(apierror.CodeTwoFactorNotStarted, "Two-factor setup has not been started")
+	case errors.Is(err, auth.ErrTwoFactorRequired):
+		return apierror.New(apierror.CodeTwoFactorRequired, "Two-factor authentication is required for your role")
+	default:
+		// Expired or malformed JWTs come back as jwt validation errors
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) {
+			return apierror.New(apierror.CodeInvalidToken, "Invalid or expired challenge token")
+		}
+		return apierror.New(apierror.CodeInternal, "An error occurred during two-factor authentication")
+	}
+}
+
+// recordTwoFactorFailure registers a wrong code of an authenticated user with the login limiter
+// Changing 2FA takes a code, which is throttled like logins so a stolen session cannot guess it
+func recordTwoFactorFailure(c *gin.Context, username string, err error) {
+	if errors.Is(err, auth.ErrInvalidTwoFactorCode) {
+		recordLoginFailure(c, username)
+	}
+}
+
+// LoginTwoFactor completes a login with a TOTP or recovery code
+func LoginTwoFactor(c *gin.Context) {
+	var req models.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Code == "" && req.RecoveryCode == "") {
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Codes are only six digits, so the second step is throttled like the first one
+	username := auth.ChallengeUsername(req.ChallengeToken)
+	if !allowLoginAttempt(c, username) {
+		return
+	}
+
+	response, err := auth.CompleteTwoFactorLogin(req.ChallengeToken, req.Code, req.RecoveryCode)
+	if err != nil {
+		recordLoginFailure(c, username)
+		apiErr := twoFactorError(err)
+		if apiErr.Code == apierror.CodeInternal {
+			slog.ErrorContext(c.Request.Context(), "Two-factor login failed", "error", err)
+		}
+		apierror.Respond(c, apiErr)
+		return
+	}
+
+	recordLoginSuccess(username)
+	c.JSON(http.StatusOK, models.OrderedLoginResponse{
+		User:         response.User,
+		Token:        response.Token,
+		RefreshToken: response.RefreshToken,
+	})
+}
+
+// LoginTwoFactorSetup starts the mandatory enrollment of a user during login
+func LoginTwoFactorSetup(c *gin.Context) {
+	var req models.TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	setup, err := auth.BeginTwoFactorSetupWithChallenge(req.ChallengeToken)
+	if err != nil {
+		apierror.Respond(c, twoFactorError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Scan the provisioning URI with an authenticator app, then confirm with a code",
+		"data":    setup,
+	})
+}
+
+// LoginTwoFactorEnable finishes the mandatory enrollment during login and returns the tokens
+func LoginTwoFactorEnable(c *gin.Context) {
+	var req models.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	username := auth.ChallengeUsername(req.ChallengeToken)
+	if !allowLoginAttempt(c, username) {
+		return
+	}
+
+	response, recoveryCodes, err := auth.EnableTwoFactorWithChallenge(req.ChallengeToken, req.Code)
+	if err != nil {
+		recordLoginFailure(c, username)
+		apierror.Respond(c, twoFactorError(err))
+		return
+	}
+
+	recordLoginSuccess(username)
+	c.JSON(http.StatusOK, models.TwoFactorEnabledLoginResponse{
+		User:          response.User,
+		Token:         response.Token,
+		RefreshToken:  response.RefreshToken,
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// GetTwoFactorStatus returns the 2FA state of the current user
+func GetTwoFactorStatus(c *gin.Context) {
+	userID := c.MustGet("userID").(uint)
+
+	status, err := auth.GetTwoFactorStatus(userID, c.GetString("role"))
+	if err != nil {
+		apierror.Respond(c, twoFactorError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Two-factor status retrieved successfully",
+		"data":    status,
+	})
+}
+
+// SetupTwoFactor generates a new TOTP secret for the current user
+func SetupTwoFactor(c *gin.Context) {
+	userID := c.MustGet("userID").(uint)
+
+	setup, err := auth.BeginTwoFactorSetup(userID)
+	if err != nil {
+		apierror.Respond(c, twoFactorError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Scan the provisioning URI with an authenticator app, then confirm with a code",
+		"data":    setup,
+	})
+}
+
+// EnableTwoFactor confirms the TOTP secret of the current user and returns recovery codes
+func EnableTwoFactor(c *gin.Context) {
+	userID := c.MustGet("userID").(uint)
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	username := c.GetString("username")
+	if !allowLoginAttempt(c, username) {
+		return
+	}
+
+	recoveryCodes, err := auth.EnableTwoFactor(userID, req.Code)
+	if err != nil {
+		recordTwoFactorFailure(c, username, err)
+		apierror.Respond(c, twoFactorError(err))
+		return
+	}
+
+	recordLoginSuccess(username)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Two-factor authentication enabled. Store the recovery codes in a safe place, they are shown only once",
+		"data":    models.RecoveryCodesResponse{RecoveryCodes: recoveryCodes},
+	})
+}
+
+// DisableTwoFactor removes 2FA for the current user after verifying a TOTP or recovery code
+func DisableTwoFactor(c *gin.Context) {
+	userID := c.MustGet("userID").(uint)
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	username := c.GetString("username")
+	if !allowLoginAttempt(c, username) {
+		return
+	}
+
+	if err := auth.DisableTwoFactor(userID, c.GetString("role"), req.Code); err != nil {
+		recordTwoFactorFailure(c, username, err)
+		apierror.Respond(c, twoFactorError(err))
+		return
+	}
+
+	recordLoginSuccess(username)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the current user
+func RegenerateRecoveryCodes(c *gin.Context) {
+	userID := c.MustGet("userID").(uint)
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorf(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	username := c.GetString("username")
+	if !allowLoginAttempt(c, username) {
+		return
+	}
+
+	recoveryCodes, err := auth.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		recordTwoFactorFailure(c, username, err)
+		apierror.Respond(c, twoFactorError(err))
+		return
+	}
+
+	recordLoginSuccess(username)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Recovery codes regenerated. Previous codes no longer work",
+		"data":    models.RecoveryCodesResponse{RecoveryCodes: recoveryCodes},
+	})
+}
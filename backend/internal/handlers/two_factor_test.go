@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/delpresence/backend/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func TestTwoFactorChangesRefusedForLockedUser(t *testing.T) {
+	cfg := auth.LimiterConfig{
+		BackoffAfter:       100,
+		BaseDelay:          time.Second,
+		MaxDelay:           time.Minute,
+		UserMaxFailures:    3,
+		IPMaxFailures:      1000,
+		LockoutDuration:    15 * time.Minute,
+		MaxLockoutDuration: time.Hour,
+		FailureWindow:      15 * time.Minute,
+	}
+	previous := auth.Limiter
+	auth.Limiter = auth.NewLoginLimiter(auth.NewMemoryLimiterStore(cfg.FailureWindow), cfg)
+	t.Cleanup(func() { auth.Limiter = previous })
+
+	// Wrong codes sent to the 2FA endpoints count toward the same lockout as failed logins
+	for i := 0; i < cfg.UserMaxFailures; i++ {
+		if err := auth.Limiter.RecordFailure("10.0.0.1", "admin"); err != nil {
+			t.Fatalf("record failure: %v", err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Set("username", "admin")
+		c.Set("role", "Admin")
+	})
+	router.POST("/2fa/enable", EnableTwoFactor)
+	router.POST("/2fa/disable", DisableTwoFactor)
+	router.POST("/2fa/recovery-codes", RegenerateRecoveryCodes)
+
+	for _, path := range []string{"/2fa/enable", "/2fa/disable", "/2fa/recovery-codes"} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"code":"123456"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusTooManyRequests || !strings.Contains(rec.Body.String(), "account_locked") {
+			t.Errorf("%s: got %d %s, want 429 account_locked", path, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Supported values of LOG_FORMAT
+const (
+	FormatJSON = "json"
+	FormatText = "text" // logfmt
+)
+
+type requestIDKey struct{}
+
+// Setup makes slog the default logger with a level (debug, info, warn, error) and a format
+// (json or text). Output of the standard log package goes through it as well.
+func Setup(levelName, format string) {
+	level, levelErr := ParseLevel(levelName)
+	format = strings.ToLower(format)
+
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	var handler slog.Handler
+	if format == FormatText || format == "logfmt" {
+		handler = slog.NewTextHandler(os.Stdout, options)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, options)
+	}
+	slog.SetDefault(slog.New(contextHandler{handler}))
+
+	if levelErr != nil {
+		slog.Warn("Invalid LOG_LEVEL, using info", "error", levelErr)
+	}
+	if format != FormatJSON && format != FormatText && format != "logfmt" {
+		slog.Warn("Invalid LOG_FORMAT, using json", "format", format)
+	}
+}
+
+// ParseLevel converts a level name to a slog level
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if strings.EqualFold(name, "warning") {
+		name = "warn"
+	}
+	err := level.UnmarshalText([]byte(name))
+	if err != nil {
+		return slog.LevelInfo, err
+	}
+	return level, nil
+}
+
+// WithRequestID returns a context carrying a request ID, added to every log line written with it
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID of a context, or an empty string
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextHandler adds the request ID of the context to every record
+// Messages and attributes are redacted by redactAttr
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
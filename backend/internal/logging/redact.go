@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces secrets in log output
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose values are never logged, compared without "_" and "-"
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"newpassword":   true,
+	"token":         true,
+	"accesstoken":   true,
+	"refreshtoken":  true,
+	"authorization": true,
+	"secret":        true,
+	"apikey":        true,
+	"xapikey":       true,
+	"qrcode":        true,
+	"qrcodedata":    true,
+	"qrdata":        true,
+	"otp":           true,
+	"recoverycode":  true,
+}
+
+var (
+	// JSON Web Tokens, signed or not
+	jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]*)?`)
+	// Authorization header values
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+	// key=value, key: value and "key":"value" pairs of sensitive keys
+	pairPattern = regexp.MustCompile(`(?i)("?\b(?:password|token|access_token|refresh_token|secret|api_key|qr_code_data|qr_code|qr_data|qrData)"?\s*[:=]\s*)("[^"]*"|[^\s,;&}]+)`)
+	// QR payloads of attendance sessions
+	qrPattern = regexp.MustCompile(`delpresence:attendance:\S+`)
+	// Tokens of iCalendar feed URLs
+	calendarPattern = regexp.MustCompile(`(/api/calendar/)[^/\s?#"]+`)
+)
+
+// Redact removes tokens, passwords, QR payloads and feed tokens from a log message
+func Redact(message string) string {
+	if message == "" {
+		return message
+	}
+	message = jwtPattern.ReplaceAllString(message, Redacted)
+	message = bearerPattern.ReplaceAllString(message, "$1 "+Redacted)
+	message = pairPattern.ReplaceAllString(message, "${1}"+Redacted)
+	message = qrPattern.ReplaceAllString(message, Redacted)
+	message = calendarPattern.ReplaceAllString(message, "${1}"+Redacted)
+	return message
+}
+
+// IsSensitiveKey reports whether values logged under a key must be redacted
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	return sensitiveKeys[key]
+}
+
+// redactAttr is the slog ReplaceAttr hook that hides sensitive attributes
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if IsSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+	switch value := attr.Value.Any().(type) {
+	case string:
+		return slog.String(attr.Key, Redact(value))
+	case error:
+		return slog.String(attr.Key, Redact(value.Error()))
+	}
+	return attr
+}
@@ -0,0 +1,20 @@
+package logging
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	cases := []struct {
+		message, want string
+	}{
+		{"password=hunter2 user=admin", "password=[REDACTED] user=admin"},
+		{"Authorization: Bearer abc.def", "Authorization: Bearer [REDACTED]"},
+		{"scanned delpresence:attendance:42", "scanned [REDACTED]"},
+		{"GET /api/calendar/c2VjcmV0LWZlZWQ failed", "GET /api/calendar/[REDACTED] failed"},
+		{"GET /api/calendar/c2VjcmV0LWZlZWQ?download=1", "GET /api/calendar/[REDACTED]?download=1"},
+	}
+	for _, c := range cases {
+		if got := Redact(c.message); got != c.want {
+			t.Errorf("Redact(%q) = %q, want %q", c.message, got, c.want)
+		}
+	}
+}
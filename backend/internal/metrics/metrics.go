@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"database/sql"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "delpresence"
+
+// Check-in methods
+const (
+	CheckInMethodQRCode = "qr_code"
+	CheckInMethodManual = "manual"
+)
+
+// Check-in outcomes
+const (
+	CheckInSuccess       = "success"
+	CheckInDuplicate     = "duplicate"
+	CheckInInvalidQR     = "invalid_qr"
+	CheckInNotEnrolled   = "not_enrolled"
+	CheckInSessionClosed = "session_closed"
+	CheckInError         = "error"
+)
+
+// Registry holds every collector exposed on /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	checkIns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "attendance",
+		Name:      "check_ins_total",
+		Help:      "Student check-ins by method and outcome.",
+	}, []string{"method", "outcome"})
+
+	campusRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "campus",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of requests to the campus API (CIS) by endpoint.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"endpoint"})
+
+	campusRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "campus",
+		Name:      "request_errors_total",
+		Help:      "Failed requests to the campus API (CIS) by endpoint and reason.",
+	}, []string{"endpoint", "reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		checkIns,
+		campusRequestDuration,
+		campusRequestErrors,
+	)
+
+	// Export every check-in series from the start, so rates work before the first failure
+	for _, method := range []string{CheckInMethodQRCode, CheckInMethodManual} {
+		for _, outcome := range []string{CheckInSuccess, CheckInDuplicate, CheckInInvalidQR, CheckInNotEnrolled, CheckInSessionClosed, CheckInError} {
+			checkIns.WithLabelValues(method, outcome)
+		}
+	}
+}
+
+// Handler serves the metrics of the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records a served HTTP request
+// route must be the route pattern, not the request path, to keep the number of series bounded
+func ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(route, method, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// RecordCheckIn counts a check-in attempt
+func RecordCheckIn(method, outcome string) {
+	checkIns.WithLabelValues(method, outcome).Inc()
+}
+
+// ObserveCampusRequest records a request to the campus API
+// reason is empty for successful requests, otherwise it is counted as an error
+func ObserveCampusRequest(endpoint string, duration time.Duration, reason string) {
+	campusRequestDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+	if reason != "" {
+		campusRequestErrors.WithLabelValues(endpoint, reason).Inc()
+	}
+}
+
+// RegisterActiveSessions exports the number of active attendance sessions, counted on every scrape
+func RegisterActiveSessions(count func() (int64, error)) {
+	Registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "attendance",
+		Name:      "active_sessions",
+		Help:      "Attendance sessions that are currently active.",
+	}, func() float64 {
+		active, err := count()
+		if err != nil {
+			slog.Warn("Error counting active attendance sessions", "error", err)
+			return math.NaN()
+		}
+		return float64(active)
+	}))
+}
+
+// RegisterDBStats exports the connection pool statistics of the database
+func RegisterDBStats(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+}
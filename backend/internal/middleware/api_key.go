@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/delpresence/backend/internal/apierror"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyRole is the role set in the context for requests authenticated with an API key
+const APIKeyRole = "ApiKey"
+
+// APIKeyMiddleware authenticates requests carrying an API key in the X-API-Key header or an
+// "Authorization: ApiKey <key>" header. Requests without a key are passed to the bearer
+// token middleware, so both kinds of credentials are accepted on the same routes.
+func APIKeyMiddleware(bearer gin.HandlerFunc) gin.HandlerFunc {
+	service := services.NewAPIKeyService()
+
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader("X-API-Key")
+		if authHeader := c.GetHeader("Authorization"); rawKey == "" && strings.HasPrefix(authHeader, "ApiKey ") {
+			rawKey = strings.TrimPrefix(authHeader, "ApiKey ")
+		}
+
+		if rawKey == "" {
+			bearer(c)
+			return
+		}
+
+		key, err := service.Authenticate(rawKey, c.ClientIP())
+		if err != nil {
+			if !errors.Is(err, services.ErrInvalidAPIKey) {
+				slog.ErrorContext(c.Request.Context(), "Error authenticating API key", "error", err)
+			}
+			apierror.Respond(c, apierror.New(apierror.CodeInvalidAPIKey, "Invalid or expired API key"))
+			return
+		}
+
+		c.Set("apiKey", key)
+		c.Set("username", "apikey:"+key.Name)
+		c.Set("role", APIKeyRole)
+
+		c.Next()
+	}
+}
+
+// APIKeyFromContext returns the API key that authenticated the request, or nil for bearer tokens
+func APIKeyFromContext(c *gin.Context) *models.APIKey {
+	value, exists := c.Get("apiKey")
+	if !exists {
+		return nil
+	}
+	key, _ := value.(*models.APIKey)
+	return key
+}
+
+// PermissionMiddleware ensures an API key grants the permission
+// Requests authenticated with a bearer token must belong to an admin
+func PermissionMiddleware(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := APIKeyFromContext(c); key != nil {
+			if !key.HasPermission(permission) {
+				apierror.Respond(c, apierror.Newf(apierror.CodeInsufficientPermissions, "API key does not grant permission %s", permission))
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if !strings.EqualFold(c.GetString("role"), "Admin") {
+			apierror.Respond(c, apierror.New(apierror.CodeForbidden, "You don't have permission to access this resource"))
+			return
+		}
+		c.Next()
+	}
+}
@@ -2,10 +2,10 @@ package middleware
 
 import (
 	"fmt"
-	"log"
-	"net/http"
+	"log/slog"
 	"strings"
 
+	"github.com/delpresence/backend/internal/apierror"
 	"github.com/delpresence/backend/internal/auth"
 	"github.com/gin-gonic/gin"
 )
@@ -18,8 +18,7 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		// Check if the header exists and has the correct format
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required and must be a Bearer token"})
-			c.Abort()
+			apierror.Respond(c, apierror.New(apierror.CodeUnauthorized, "Authorization header is required and must be a Bearer token"))
 			return
 		}
 
@@ -29,8 +28,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Validate the token
 		claims, err := auth.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+			apierror.Respond(c, apierror.New(apierror.CodeInvalidToken, "Invalid or expired token"))
 			return
 		}
 
@@ -49,17 +47,10 @@ func RoleMiddleware(roles ...string) gin.HandlerFunc {
 		// Get the user role from the context
 		role, exists := c.Get("role")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found in token"})
-			c.Abort()
+			apierror.Respond(c, apierror.New(apierror.CodeInvalidToken, "User role not found in token"))
 			return
 		}
 
-		// Log the request info for debugging
-		path := c.Request.URL.Path
-		method := c.Request.Method
-		userID, _ := c.Get("userID")
-		username, _ := c.Get("username")
-
 		// Convert role to string and normalize
 		userRole := ""
 		if roleStr, ok := role.(string); ok {
@@ -68,16 +59,11 @@ func RoleMiddleware(roles ...string) gin.HandlerFunc {
 			userRole = strings.ToLower(fmt.Sprintf("%v", role))
 		}
 
-		// Log the role information
-		log.Printf("RoleMiddleware check for path: %s %s - user: %v (%s), role: %s, required roles: %v",
-			method, path, userID, username, userRole, roles)
-
 		// Special handling for assistant variations
 		if containsStringVariation(userRole, []string{"asisten", "dosen"}) {
 			for _, r := range roles {
 				// If any required role contains "asisten" or is for teaching assistants
 				if strings.Contains(strings.ToLower(r), "asisten") {
-					log.Printf("Role match (assistant special case): %s matches %s", userRole, r)
 					c.Next()
 					return
 				}
@@ -87,15 +73,13 @@ func RoleMiddleware(roles ...string) gin.HandlerFunc {
 		// Standard role check - case-insensitive comparison
 		for _, r := range roles {
 			if userRole == strings.ToLower(r) {
-				log.Printf("Role match: %s matches %s", userRole, r)
 				c.Next()
 				return
 			}
 		}
 
-		log.Printf("Role check failed: user role %s doesn't match any of %v", userRole, roles)
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this resource"})
-		c.Abort()
+		slog.InfoContext(c.Request.Context(), "Role check failed", "role", userRole, "required_roles", roles)
+		apierror.Respond(c, apierror.New(apierror.CodeForbidden, "You don't have permission to access this resource"))
 	}
 }
 
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"regexp"
+
+	"github.com/delpresence/backend/internal/apierror"
+	"github.com/delpresence/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader carries a client-chosen key that makes retries of a request safe
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set on responses that were stored for an earlier request with the same key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// validIdempotencyKey limits the keys accepted from clients, UUIDs are recommended
+var validIdempotencyKey = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,255}$`)
+
+// idempotencyRecorder keeps a copy of the response body while writing it
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// IdempotencyMiddleware makes requests with an Idempotency-Key header safe to retry: the response
+// to the first request is stored for the signed-in user and route, and a retry with the same key
+// and body gets that response again without running the handler. Requests without the header are
+// handled as usual. Server errors are not stored, so they can be retried.
+// It must run after the auth middleware, which sets the user ID.
+func IdempotencyMiddleware() gin.HandlerFunc {
+	service := services.NewIdempotencyService()
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey.MatchString(key) {
+			apierror.Respond(c, apierror.New(apierror.CodeInvalidRequest, "Idempotency-Key must be 1 to 255 letters, digits or . _ : -").
+				With("field", IdempotencyKeyHeader))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apierror.Respond(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, replay, err := service.Begin(c.GetUint("userID"), c.Request.Method+" "+c.FullPath(), key, body)
+		switch {
+		case errors.Is(err, services.ErrIdempotencyKeyReused):
+			apierror.Respond(c, apierror.Wrap(apierror.CodeIdempotencyKeyReused, err))
+			return
+		case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+			apierror.Respond(c, apierror.Wrap(apierror.CodeIdempotencyKeyInProgress, err))
+			return
+		case err != nil:
+			slog.ErrorContext(c.Request.Context(), "Error claiming idempotency key", "error", err)
+			apierror.Respond(c, apierror.New(apierror.CodeInternal, ""))
+			return
+		}
+
+		if replay {
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(record.StatusCode, gin.MIMEJSON+"; charset=utf-8", []byte(record.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if status := recorder.Status(); status >= 500 {
+			err = service.Release(record)
+		} else {
+			err = service.Complete(record, status, recorder.body.Bytes())
+		}
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "Error storing idempotent response", "error", err)
+		}
+	}
+}
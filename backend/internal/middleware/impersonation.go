@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/delpresence/backend/internal/apierror"
+	"github.com/delpresence/backend/internal/auth"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationMiddleware enforces read-only impersonation tokens and audits every request
+// made under an impersonation token. It must run after the authentication middleware.
+func ImpersonationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		impersonatorID, impersonating := c.Get("impersonatorID")
+		if !impersonating {
+			c.Next()
+			return
+		}
+
+		// Mark every response so clients can show that the session is impersonated
+		c.Header("X-Impersonated-By", c.GetString("impersonatorUsername"))
+
+		readOnly := c.GetBool("impersonationReadOnly")
+		method := c.Request.Method
+		if readOnly && method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions {
+			apierror.Respond(c, apierror.New(apierror.CodeImpersonationReadOnly, "Impersonation token is read-only"))
+		} else {
+			c.Next()
+		}
+
+		userID, _ := c.Get("userID")
+		auth.RecordImpersonatedRequest(&models.AuditLog{
+			ActorUserID:   impersonatorID.(uint),
+			ActorUsername: c.GetString("impersonatorUsername"),
+			ActorRole:     c.GetString("impersonatorRole"),
+			IPAddress:     c.ClientIP(),
+			Method:        method,
+			Path:          c.Request.URL.Path,
+			StatusCode:    c.Writer.Status(),
+			Details:       fmt.Sprintf("effective user %v %s (%s), read-only: %t", userID, c.GetString("username"), c.GetString("role"), readOnly),
+		})
+	}
+}
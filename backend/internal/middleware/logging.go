@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/delpresence/backend/internal/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID in requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limits the request IDs accepted from clients and proxies
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// probeRoutes are polled by Docker and the load balancer, successful probes are only logged at debug level
+var probeRoutes = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// secretPathRoutes carry a credential in their path, only their route template is logged
+var secretPathRoutes = map[string]bool{
+	"/api/calendar/:token": true,
+}
+
+// RequestIDMiddleware assigns every request an ID, taken from the X-Request-ID header when a proxy
+// set one. The ID is returned in the response and stored in the request context, so log lines
+// written with c.Request.Context() carry it.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set("requestID", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
+
+// newRequestID returns a random 16 byte hex ID
+func newRequestID() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return time.Now().UTC().Format("20060102T150405.000000000")
+	}
+	return hex.EncodeToString(bytes)
+}
+
+// AccessLogMiddleware writes one log line per request with the user, route, status and latency
+// It must run after RequestIDMiddleware and before the authentication middleware
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		path := c.Request.URL.Path
+		if secretPathRoutes[route] {
+			path = route
+		}
+		status := c.Writer.Status()
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", route,
+			"path", path,
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"bytes", size,
+			"client_ip", c.ClientIP(),
+		}
+		if userID, exists := c.Get("userID"); exists {
+			attrs = append(attrs, "user_id", userID)
+		}
+		if role := c.GetString("role"); role != "" {
+			attrs = append(attrs, "role", role)
+		}
+		if impersonatorID, exists := c.Get("impersonatorID"); exists {
+			attrs = append(attrs, "impersonator_id", impersonatorID)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "error", c.Errors.String())
+		}
+
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		} else if probeRoutes[route] && status < 400 {
+			level = slog.LevelDebug
+		}
+		slog.Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAccessLogOmitsCalendarFeedToken(t *testing.T) {
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestIDMiddleware(), AccessLogMiddleware())
+	router.GET("/api/calendar/:token", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	const token = "c2VjcmV0LWZlZWQtdG9rZW4"
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/calendar/"+token, nil))
+
+	if strings.Contains(logs.String(), token) {
+		t.Errorf("access log contains the feed token: %s", logs.String())
+	}
+	if !strings.Contains(logs.String(), `"path":"/api/calendar/:token"`) {
+		t.Errorf("access log does not have the route template as path: %s", logs.String())
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/delpresence/backend/internal/apierror"
+	"github.com/delpresence/backend/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records the duration of every request by route pattern, method and status
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTPRequest(route, c.Request.Method, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// MetricsTokenMiddleware protects the metrics endpoint with an "Authorization: Bearer <token>" header
+// The endpoint is open when token is empty
+func MetricsTokenMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			apierror.Respond(c, apierror.New(apierror.CodeUnauthorized, "Invalid metrics token"))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+)
+
+// API key permissions
+const (
+	APIKeyPermissionKioskQR       = "kiosk:qr"       // Display the QR code of the active session in a room
+	APIKeyPermissionSchedulesRead = "schedules:read" // Read room schedules
+	APIKeyPermissionReportsRead   = "reports:read"   // Pull attendance reports
+)
+
+// APIKeyPermissions lists all valid API key permissions
+var APIKeyPermissions = []string{
+	APIKeyPermissionKioskQR,
+	APIKeyPermissionSchedulesRead,
+	APIKeyPermissionReportsRead,
+}
+
+// APIKey represents a scoped key for classroom kiosks and service integrations
+// Only a SHA-256 hash of the key is stored, the plaintext key is shown once at creation
+type APIKey struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Name            string     `json:"name" gorm:"type:varchar(100);not null"`
+	Prefix          string     `json:"prefix" gorm:"type:varchar(16);uniqueIndex;not null"` // Public part of the key, used for lookup
+	KeyHash         string     `json:"-" gorm:"type:varchar(64);not null"`
+	Permissions     []string   `json:"permissions" gorm:"serializer:json;type:text"`
+	RoomIDs         []uint     `json:"room_ids" gorm:"serializer:json;type:text"`          // Empty means all rooms
+	StudyProgramIDs []uint     `json:"study_program_ids" gorm:"serializer:json;type:text"` // Empty means all study programs
+	ExpiresAt       *time.Time `json:"expires_at"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	LastUsedIP      string     `json:"last_used_ip" gorm:"type:varchar(64)"`
+	RevokedAt       *time.Time `json:"revoked_at"`
+	RotatedToID     *uint      `json:"rotated_to_id"` // Key that replaced this one
+	CreatedByID     uint       `json:"created_by_id"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for the APIKey model
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive reports whether the key is neither revoked nor expired at the given time
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || k.ExpiresAt.After(now)
+}
+
+// HasPermission reports whether the key grants a permission
+func (k *APIKey) HasPermission(permission string) bool {
+	for _, p := range k.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsRoom reports whether the key may access a room
+func (k *APIKey) AllowsRoom(roomID uint) bool {
+	return len(k.RoomIDs) == 0 || containsUint(k.RoomIDs, roomID)
+}
+
+// AllowsStudyProgram reports whether the key may access a study program
+func (k *APIKey) AllowsStudyProgram(studyProgramID uint) bool {
+	return len(k.StudyProgramIDs) == 0 || containsUint(k.StudyProgramIDs, studyProgramID)
+}
+
+// containsUint reports whether a slice contains a value
+func containsUint(values []uint, value uint) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyRequest represents the request body for creating an API key
+type APIKeyRequest struct {
+	Name            string     `json:"name" binding:"required"`
+	Permissions     []string   `json:"permissions" binding:"required"`
+	RoomIDs         []uint     `json:"room_ids"`
+	StudyProgramIDs []uint     `json:"study_program_ids"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+}
+
+// APIKeyRotateRequest represents the request body for rotating an API key
+type APIKeyRotateRequest struct {
+	GraceMinutes int `json:"grace_minutes"` // How long the old key keeps working, 0 revokes it immediately
+}
+
+// APIKeyCreatedResponse is returned when a key is created or rotated
+// The plaintext key is only available in this response
+type APIKeyCreatedResponse struct {
+	Key    string `json:"key"`
+	APIKey APIKey `json:"api_key"`
+}
+
+// AttendanceReportRow represents the attendance summary of one course schedule
+type AttendanceReportRow struct {
+	CourseScheduleID uint                 `json:"course_schedule_id"`
+	CourseCode       string               `json:"course_code"`
+	CourseName       string               `json:"course_name"`
+	StudentGroup     string               `json:"student_group"`
+	StudyProgramID   uint                 `json:"study_program_id"`
+	AcademicYearID   uint                 `json:"academic_year_id"`
+	Day              string               `json:"day"`
+	StartTime        string               `json:"start_time"`
+	EndTime          string               `json:"end_time"`
+	Statistics       AttendanceStatistics `json:"statistics"`
+}
@@ -60,6 +60,8 @@ type AttendanceSession struct {
 }
 
 // StudentAttendance represents a student's attendance record for a session
+// A student has at most one record per session, enforced by the unique index
+// idx_student_attendances_session_student created in migration 3
 type StudentAttendance struct {
 	ID                  uint                    `json:"id" gorm:"primaryKey"`
 	AttendanceSessionID uint                    `json:"attendance_session_id" gorm:"not null;index"`
@@ -150,3 +152,71 @@ type AttendanceStatistics struct {
 	TotalExcused      int `json:"total_excused"`
 	AverageAttendance int `json:"average_attendance"` // Percentage
 }
+
+// AttendanceSessionRequest represents the request body for opening an attendance session
+type AttendanceSessionRequest struct {
+	CourseScheduleID uint                   `json:"course_schedule_id" binding:"required"`
+	Type             string                 `json:"type" binding:"required,oneof=QR_CODE FACE_RECOGNITION MANUAL BOTH"`
+	Date             string                 `json:"date" binding:"required"` // YYYY-MM-DD
+	Settings         map[string]interface{} `json:"settings"`                // auto_close, duration, allow_late, late_threshold, notes
+}
+
+// MarkAttendanceRequest represents the request body for marking a student's attendance manually
+type MarkAttendanceRequest struct {
+	Status             string `json:"status" binding:"required,oneof=PRESENT LATE ABSENT EXCUSED"`
+	Notes              string `json:"notes"`
+	VerificationMethod string `json:"verification_method"`
+}
+
+// QRAttendanceRequest represents the request body for a check-in with a scanned QR code
+type QRAttendanceRequest struct {
+	SessionID          uint   `json:"session_id" binding:"required"`
+	ScheduleID         uint   `json:"schedule_id"` // Optional, used for verification
+	VerificationMethod string `json:"verification_method" binding:"required"`
+	QRData             string `json:"qr_data"`
+	Timestamp          string `json:"timestamp"`
+}
+
+// ActiveAttendanceSessionResponse represents an open attendance session of one of a student's courses
+type ActiveAttendanceSessionResponse struct {
+	ID               uint             `json:"id"`
+	CourseScheduleID uint             `json:"course_schedule_id"`
+	LecturerID       uint             `json:"lecturer_id"`
+	Date             string           `json:"date"`
+	StartTime        string           `json:"start_time"`
+	Type             AttendanceType   `json:"type"`
+	Status           AttendanceStatus `json:"status"`
+	CourseCode       string           `json:"course_code"`
+	CourseName       string           `json:"course_name"`
+	RoomName         string           `json:"room_name"`
+	BuildingName     string           `json:"building_name"`
+}
+
+// AttendanceQRCodeResponse represents where the QR code of an attendance session can be fetched
+type AttendanceQRCodeResponse struct {
+	QRCodeURL string `json:"qr_code_url"`
+}
+
+// RoomActiveSessionResponse represents the open attendance session of a room, shown by classroom kiosks
+type RoomActiveSessionResponse struct {
+	SessionID        uint           `json:"session_id"`
+	CourseScheduleID uint           `json:"course_schedule_id"`
+	CourseCode       string         `json:"course_code"`
+	CourseName       string         `json:"course_name"`
+	Room             string         `json:"room"`
+	Type             AttendanceType `json:"type"`
+	StartTime        time.Time      `json:"start_time"`
+	Duration         int            `json:"duration"`     // In minutes
+	QRCodeData       string         `json:"qr_code_data"` // Encode as a QR code for students to scan
+}
+
+// CheckInResponse is the result of a QR check-in
+// AlreadyCheckedIn is set when the student had checked in before, the record is then the first check-in
+type CheckInResponse struct {
+	AttendanceID        uint                    `json:"attendance_id"`
+	AttendanceSessionID uint                    `json:"attendance_session_id"`
+	Status              StudentAttendanceStatus `json:"status"`
+	CheckInTime         *time.Time              `json:"check_in_time"`
+	VerificationMethod  string                  `json:"verification_method"`
+	AlreadyCheckedIn    bool                    `json:"already_checked_in"`
+}
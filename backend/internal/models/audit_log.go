@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// Audit log actions
+const (
+	AuditActionOfflineLogin           = "OFFLINE_LOGIN"
+	AuditActionCampusCredentialPurged = "CAMPUS_CREDENTIAL_PURGED"
+	AuditActionImpersonationStart     = "IMPERSONATION_START"
+	AuditActionImpersonatedRequest    = "IMPERSONATED_REQUEST"
+)
+
+// AuditLog records a security-relevant event
+type AuditLog struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	Action        string    `json:"action" gorm:"type:varchar(50);not null;index"`
+	ActorUserID   uint      `json:"actor_user_id" gorm:"index"` // User who performed the action
+	ActorUsername string    `json:"actor_username" gorm:"type:varchar(100)"`
+	ActorRole     string    `json:"actor_role" gorm:"type:varchar(50)"`
+	TargetUserID  *uint     `json:"target_user_id" gorm:"index"` // User affected by the action, if any
+	IPAddress     string    `json:"ip_address" gorm:"type:varchar(64)"`
+	Method        string    `json:"method" gorm:"type:varchar(10)"`
+	Path          string    `json:"path" gorm:"type:varchar(255)"`
+	StatusCode    int       `json:"status_code"`
+	Details       string    `json:"details" gorm:"type:text"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// TableName returns the table name for the AuditLog model
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// CalendarFeedKind is whose timetable an iCalendar feed serves
+type CalendarFeedKind string
+
+const (
+	CalendarFeedStudent  CalendarFeedKind = "student"
+	CalendarFeedLecturer CalendarFeedKind = "lecturer"
+	CalendarFeedRoom     CalendarFeedKind = "room"
+)
+
+// CalendarFeed is a secret iCalendar feed URL of a student, lecturer or room timetable
+// Calendar apps cannot send an Authorization header, so the token in the URL is the credential.
+// Only its hash is stored.
+type CalendarFeed struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	Kind           CalendarFeedKind `json:"kind" gorm:"type:varchar(20);not null;index:idx_calendar_feeds_subject"`
+	SubjectID      uint             `json:"subject_id" gorm:"not null;index:idx_calendar_feeds_subject"` // Student ID, schedule lecturer_id or room ID
+	Label          string           `json:"label" gorm:"type:varchar(100)"`
+	TokenHash      string           `json:"-" gorm:"type:varchar(64);uniqueIndex;not null"`
+	LastAccessedAt *time.Time       `json:"last_accessed_at"`
+	RevokedAt      *time.Time       `json:"revoked_at"`
+	CreatedByID    uint             `json:"created_by_id"`
+	CreatedAt      time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for the CalendarFeed model
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
+
+// CalendarFeedRequest represents the request body for creating a room calendar feed
+type CalendarFeedRequest struct {
+	Label string `json:"label"` // Who the feed is for, such as the building manager
+}
+
+// CalendarFeedCreatedResponse is returned once when a feed is created, with its URL
+type CalendarFeedCreatedResponse struct {
+	URL  string       `json:"url"`
+	Feed CalendarFeed `json:"feed"`
+}
@@ -2,7 +2,7 @@ package models
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 )
 
 // CampusPosition represents a position/role in the campus system
@@ -28,7 +28,7 @@ func (u *CampusUser) GetJabatanPositions() []CampusPosition {
 	err := json.Unmarshal(u.Jabatan, &positions)
 	if err != nil {
 		// If not an array, it might be a string or something else - just return empty array
-		log.Printf("Jabatan is not an array of positions: %v", err)
+		slog.Debug("Jabatan is not an array of positions", "error", err)
 		return []CampusPosition{}
 	}
 	return positions
@@ -41,7 +41,7 @@ func (u *CampusUser) GetJabatanString() string {
 	err := json.Unmarshal(u.Jabatan, &jabatanString)
 	if err != nil {
 		// If not a string, it might be an array or something else - just return empty string
-		log.Printf("Jabatan is not a string: %v", err)
+		slog.Debug("Jabatan is not a string", "error", err)
 		return ""
 	}
 	return jabatanString
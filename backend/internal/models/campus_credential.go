@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// CampusCredential caches a verifier of the last password accepted by CIS for a campus user
+// It allows logins to be validated locally while CIS is unreachable
+type CampusCredential struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	ExternalUserID int       `json:"external_user_id" gorm:"uniqueIndex;not null"`
+	Username       string    `json:"username" gorm:"type:varchar(100);uniqueIndex;not null"` // Stored lowercase
+	Email          string    `json:"email" gorm:"type:varchar(255)"`
+	Role           string    `json:"role" gorm:"type:varchar(50)"`
+	Verifier       string    `json:"-" gorm:"type:varchar(255);not null"` // Argon2id hash of the password
+	VerifiedAt     time.Time `json:"verified_at" gorm:"not null"`         // Last time CIS accepted the password
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for the CampusCredential model
+func (CampusCredential) TableName() string {
+	return "campus_credentials"
+}
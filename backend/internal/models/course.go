@@ -36,4 +36,20 @@ type Department struct {
 	DeletedAt gorm.DeletedAt `gorm:"index;uniqueIndex:idx_departments_name_deleted_at" json:"deleted_at,omitempty"`
 }
 
-// Faculty struct is defined in faculty.go - removed duplicate declaration 
\ No newline at end of file
+// Faculty struct is defined in faculty.go - removed duplicate declaration 
+// StudentCourseResponse represents a course a student takes through one of their student groups
+type StudentCourseResponse struct {
+	ID               uint   `json:"id"`
+	CourseID         uint   `json:"course_id"`
+	CourseCode       string `json:"course_code"`
+	CourseName       string `json:"course_name"`
+	Credits          int    `json:"sks"`
+	Semester         int    `json:"semester"`
+	LecturerID       uint   `json:"lecturer_id"`
+	LecturerName     string `json:"lecturer_name"`
+	StudentGroupID   uint   `json:"student_group_id"`
+	StudentGroupName string `json:"student_group_name"`
+	AcademicYearID   uint   `json:"academic_year_id"`
+	AcademicYearName string `json:"academic_year_name"`
+	Description      string `json:"description,omitempty"`
+}
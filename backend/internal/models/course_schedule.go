@@ -33,3 +33,65 @@ type CourseSchedule struct {
 func (CourseSchedule) TableName() string {
 	return "course_schedules"
 }
+
+// CourseScheduleRequest represents the request body for creating a course schedule
+type CourseScheduleRequest struct {
+	CourseID       uint   `json:"course_id" binding:"required"`
+	RoomID         uint   `json:"room_id" binding:"required"`
+	Day            string `json:"day" binding:"required"`
+	StartTime      string `json:"start_time" binding:"required"` // HH:MM
+	EndTime        string `json:"end_time" binding:"required"`   // HH:MM
+	LecturerID     uint   `json:"lecturer_id" binding:"required"`
+	StudentGroupID uint   `json:"student_group_id" binding:"required"`
+	AcademicYearID uint   `json:"academic_year_id" binding:"required"`
+	Capacity       int    `json:"capacity"`
+}
+
+// CourseScheduleUpdateRequest represents the request body for updating a course schedule
+// Fields that are left out keep their value
+type CourseScheduleUpdateRequest struct {
+	CourseID       uint   `json:"course_id"`
+	RoomID         uint   `json:"room_id"`
+	Day            string `json:"day"`
+	StartTime      string `json:"start_time"`
+	EndTime        string `json:"end_time"`
+	LecturerID     uint   `json:"lecturer_id"`
+	StudentGroupID uint   `json:"student_group_id"`
+	AcademicYearID uint   `json:"academic_year_id"`
+	Capacity       int    `json:"capacity"`
+}
+
+// CourseScheduleResponse represents a course schedule with the names of its related records
+// The names are left out when the related record cannot be found
+type CourseScheduleResponse struct {
+	ID               uint   `json:"id"`
+	CourseID         uint   `json:"course_id"`
+	RoomID           uint   `json:"room_id"`
+	Day              string `json:"day"`
+	StartTime        string `json:"start_time"`
+	EndTime          string `json:"end_time"`
+	LecturerID       uint   `json:"lecturer_id"`
+	StudentGroupID   uint   `json:"student_group_id"`
+	AcademicYearID   uint   `json:"academic_year_id"`
+	Capacity         int    `json:"capacity"`
+	Enrolled         int    `json:"enrolled"` // Current number of members of the student group
+	CourseName       string `json:"course_name,omitempty"`
+	CourseCode       string `json:"course_code,omitempty"`
+	Semester         int    `json:"semester,omitempty"`
+	RoomName         string `json:"room_name,omitempty"`
+	BuildingName     string `json:"building_name,omitempty"`
+	LecturerName     string `json:"lecturer_name"`
+	StudentGroupName string `json:"student_group_name,omitempty"`
+	AcademicYearName string `json:"academic_year_name,omitempty"`
+}
+
+// CourseLecturerResponse represents the lecturer assigned to a course
+type CourseLecturerResponse struct {
+	LecturerID       uint   `json:"lecturer_id"` // ID to use as the lecturer_id of a course schedule
+	UserID           uint   `json:"user_id"`
+	ExternalUserID   int    `json:"external_user_id"`
+	Name             string `json:"name"`
+	Email            string `json:"email"`
+	AcademicYearID   uint   `json:"academic_year_id"`
+	AcademicYearName string `json:"academic_year_name"`
+}
@@ -20,6 +20,9 @@ type Employee struct {
 	EmploymentType  string         `json:"employment_type" gorm:"type:varchar(50)"`
 	JoinDate        *time.Time     `json:"join_date"`
 	LastSync        time.Time      `json:"last_sync" gorm:"autoCreateTime"`
+	LifecycleStatus string         `json:"lifecycle_status" gorm:"type:varchar(20);default:'active';index"` // active, or inactive once the record left CIS
+	DeactivatedAt   *time.Time     `json:"deactivated_at"`
+	InactiveReason  string         `json:"inactive_reason" gorm:"type:varchar(255)"`
 	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
@@ -0,0 +1,25 @@
+package models
+
+// Health check statuses
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusFailed   = "failed"
+	HealthStatusDisabled = "disabled"
+)
+
+// HealthCheck is the result of one readiness check
+type HealthCheck struct {
+	Status    string `json:"status"`
+	Required  bool   `json:"required"` // A failed required check makes the server not ready
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+	Version   int    `json:"version,omitempty"` // Applied schema version, for the migrations check
+	Latest    int    `json:"latest,omitempty"`  // Newest schema version known to this build
+}
+
+// ReadinessReport is the response of the readiness probe
+type ReadinessReport struct {
+	Ready    bool                   `json:"ready"`
+	Draining bool                   `json:"draining"`
+	Checks   map[string]HealthCheck `json:"checks"`
+}
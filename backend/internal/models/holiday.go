@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// Holiday is a day or range of days without classes, such as a public holiday or a semester break
+type Holiday struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null"`
+	StartDate string    `json:"start_date" gorm:"type:varchar(10);not null;index"` // YYYY-MM-DD
+	EndDate   string    `json:"end_date" gorm:"type:varchar(10);not null"`         // YYYY-MM-DD, inclusive
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for the Holiday model
+func (Holiday) TableName() string {
+	return "holidays"
+}
+
+// HolidayRequest represents the request body for adding a holiday
+type HolidayRequest struct {
+	Name      string `json:"name" binding:"required"`
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string `json:"end_date"`                      // YYYY-MM-DD, defaults to start_date
+}
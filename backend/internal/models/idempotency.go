@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// IdempotencyRecord stores the response to a request sent with an Idempotency-Key header,
+// so a retry of the request gets the same response instead of running it again
+// Keys are scoped to the user and the route
+type IdempotencyRecord struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	UserID      uint   `json:"user_id" gorm:"not null;uniqueIndex:idx_idempotency_records_scope"`
+	Route       string `json:"route" gorm:"type:varchar(150);not null;uniqueIndex:idx_idempotency_records_scope"`
+	Key         string `json:"key" gorm:"column:idempotency_key;type:varchar(255);not null;uniqueIndex:idx_idempotency_records_scope"`
+	RequestHash string `json:"request_hash" gorm:"type:varchar(64);not null"` // SHA-256 of the request body
+	// StatusCode is 0 while the first request is still being handled
+	StatusCode   int       `json:"status_code" gorm:"not null;default:0"`
+	ResponseBody string    `json:"response_body" gorm:"type:text"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for the IdempotencyRecord model
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_records"
+}
+
+// Completed reports whether the response to the request has been stored
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
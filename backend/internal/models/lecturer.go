@@ -24,6 +24,9 @@ type Lecturer struct {
 	EducationLevel      string         `json:"education_level" gorm:"type:varchar(255)"`
 	NIDN                string         `json:"nidn" gorm:"column:n_id_n;type:varchar(20)"`
 	LastSync            time.Time      `json:"last_sync" gorm:"autoCreateTime"`
+	LifecycleStatus     string         `json:"lifecycle_status" gorm:"type:varchar(20);default:'active';index"` // active, or inactive once the record left CIS
+	DeactivatedAt       *time.Time     `json:"deactivated_at"`
+	InactiveReason      string         `json:"inactive_reason" gorm:"type:varchar(255)"`
 	CreatedAt           time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt           time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt           gorm.DeletedAt `json:"-" gorm:"index"`
@@ -56,4 +59,34 @@ type CampusLecturer struct {
 	JenjangPendidikan   string      `json:"jenjang_pendidikan"`
 	NIDN                string      `json:"nidn"`
 	UserID              interface{} `json:"user_id"`
-} 
\ No newline at end of file
+} 
+// LecturerResponse represents a lecturer in the admin lecturer list, with the study program name as a string
+type LecturerResponse struct {
+	ID               uint      `json:"id"`
+	EmployeeID       int       `json:"employee_id"`
+	LecturerID       int       `json:"lecturer_id"`
+	NIP              string    `json:"nip"`
+	FullName         string    `json:"full_name"`
+	Email            string    `json:"email"`
+	StudyProgramID   uint      `json:"study_program_id"`
+	StudyProgram     string    `json:"study_program"` // String field for frontend
+	AcademicRank     string    `json:"academic_rank"`
+	AcademicRankDesc string    `json:"academic_rank_desc"`
+	EducationLevel   string    `json:"education_level"`
+	NIDN             string    `json:"nidn"`
+	UserID           int       `json:"user_id"`
+	LastSync         time.Time `json:"last_sync"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// LecturerOption represents a lecturer in the search results of lecturer dropdowns
+type LecturerOption struct {
+	ID       uint   `json:"id"`
+	UserID   int    `json:"user_id"` // Include UserID from lecturer table
+	FullName string `json:"full_name"`
+	NIP      string `json:"nip"`      // Include NIP which is mapped to n_ip in DB
+	NIDN     string `json:"nidn"`     // NIDN is mapped to n_id_n in DB
+	Program  string `json:"program"`
+	Email    string `json:"email,omitempty"` // Include email if available
+}
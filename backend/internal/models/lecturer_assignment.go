@@ -55,4 +55,18 @@ type LecturerAssignmentResponse struct {
 // TableName specifies the table name for lecturer assignments
 func (LecturerAssignment) TableName() string {
 	return "lecturer_assignments"
-} 
\ No newline at end of file
+} 
+// LecturerAssignmentRequest represents the request body for assigning a lecturer to a course
+type LecturerAssignmentRequest struct {
+	UserID         int  `json:"user_id" binding:"required"` // External user ID of the lecturer
+	CourseID       uint `json:"course_id" binding:"required"`
+	AcademicYearID uint `json:"academic_year_id"` // Defaults to the active academic year
+}
+
+// LecturerAssignmentUpdateRequest represents the request body for updating a lecturer assignment
+// Fields that are left out keep their value
+type LecturerAssignmentUpdateRequest struct {
+	UserID         int  `json:"user_id"`
+	CourseID       uint `json:"course_id"`
+	AcademicYearID uint `json:"academic_year_id"`
+}
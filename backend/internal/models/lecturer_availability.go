@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// LecturerAvailabilityKind tells whether a lecturer cannot or would rather not teach in a window
+type LecturerAvailabilityKind string
+
+const (
+	// LecturerUnavailable windows are never scheduled, a hard constraint of the timetable solver
+	LecturerUnavailable LecturerAvailabilityKind = "unavailable"
+	// LecturerAvoid windows are scheduled only when needed, a soft preference of the timetable solver
+	LecturerAvoid LecturerAvailabilityKind = "avoid"
+)
+
+// LecturerAvailability is a weekly window in which a lecturer cannot or would rather not teach
+type LecturerAvailability struct {
+	ID        uint                     `gorm:"primaryKey" json:"id"`
+	UserID    uint                     `gorm:"not null;index" json:"lecturer_id"` // Same ID as the lecturer_id of course schedules
+	Day       string                   `gorm:"type:varchar(10);not null" json:"day"`
+	StartTime string                   `gorm:"type:varchar(5);not null" json:"start_time"` // HH:MM
+	EndTime   string                   `gorm:"type:varchar(5);not null" json:"end_time"`   // HH:MM
+	Kind      LecturerAvailabilityKind `gorm:"type:varchar(20);not null" json:"kind"`
+	Note      string                   `gorm:"type:text" json:"note"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+// TableName returns the table name for the LecturerAvailability model
+func (LecturerAvailability) TableName() string {
+	return "lecturer_availabilities"
+}
+
+// LecturerAvailabilityRequest represents the request body for adding a lecturer availability window
+type LecturerAvailabilityRequest struct {
+	LecturerID uint                     `json:"lecturer_id" binding:"required"`
+	Day        string                   `json:"day" binding:"required"`
+	StartTime  string                   `json:"start_time" binding:"required"` // HH:MM
+	EndTime    string                   `json:"end_time" binding:"required"`   // HH:MM
+	Kind       LecturerAvailabilityKind `json:"kind" binding:"required,oneof=unavailable avoid"`
+	Note       string                   `json:"note"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Lifecycle statuses of students, lecturers and employees synced from CIS
+const (
+	LifecycleActive   = "active"
+	LifecycleInactive = "inactive" // No longer returned by CIS as active (graduated, dropped out, on leave, left)
+)
+
+// StudentGroupMembershipHistory keeps the group memberships of students that were removed
+// from their groups, so past rosters can still be reconstructed
+type StudentGroupMembershipHistory struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	StudentID      uint      `json:"student_id" gorm:"not null;index"`
+	UserID         int       `json:"user_id" gorm:"not null"`
+	StudentGroupID uint      `json:"student_group_id" gorm:"not null;index"`
+	JoinedAt       time.Time `json:"joined_at"`
+	LeftAt         time.Time `json:"left_at" gorm:"not null"`
+	Reason         string    `json:"reason" gorm:"type:varchar(255)"`
+}
+
+// TableName returns the table name for the StudentGroupMembershipHistory model
+func (StudentGroupMembershipHistory) TableName() string {
+	return "student_group_membership_history"
+}
@@ -0,0 +1,69 @@
+package models
+
+// Filter operators of list queries
+const (
+	FilterEq   = "eq"
+	FilterNe   = "ne"
+	FilterGt   = "gt"
+	FilterGte  = "gte"
+	FilterLt   = "lt"
+	FilterLte  = "lte"
+	FilterIn   = "in"
+	FilterLike = "like"
+)
+
+// Page sizes of list queries
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// ListQuery is the query of a list endpoint: pagination, filters, sorting and sparse fields
+// Field names are the JSON names of the listed records
+type ListQuery struct {
+	Page     int // 1-based, used unless Cursor pagination is requested
+	PageSize int
+	// UseCursor selects cursor pagination, Cursor is empty for the first page
+	UseCursor bool
+	Cursor    string
+	Filters   []ListFilter
+	Sort      []ListSort
+	Fields    []string
+}
+
+// ListFilter restricts a list to records whose field matches a value
+// Values holds several values for the "in" operator
+type ListFilter struct {
+	Field  string
+	Op     string
+	Values []string
+}
+
+// ListSort orders a list by a field
+type ListSort struct {
+	Field string
+	Desc  bool
+}
+
+// Offset returns the number of records skipped by offset pagination
+func (q ListQuery) Offset() int {
+	if q.Page < 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.PageSize
+}
+
+// PageInfo describes the returned page of a list, it is the "meta" of list responses
+type PageInfo struct {
+	Total      int64  `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListResult is one page of records
+type ListResult[T any] struct {
+	Items []T
+	Page  PageInfo
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginAttempt tracks failed login attempts for a single rate limit key
+// A key is either an IP address ("ip:<address>") or a username ("user:<username>")
+type LoginAttempt struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	Key           string     `json:"key" gorm:"column:limit_key;type:varchar(150);uniqueIndex;not null"`
+	Failures      int        `json:"failures" gorm:"not null;default:0"`
+	Lockouts      int        `json:"lockouts" gorm:"not null;default:0"` // Number of lockouts, used for progressive lockout duration
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for the LoginAttempt model
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// IsLocked reports whether the key is locked at the given time
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && a.LockedUntil.After(now)
+}
+
+// UnlockRequest represents the request body for unlocking a locked account or IP
+type UnlockRequest struct {
+	Username string `json:"username"`
+	IP       string `json:"ip"`
+}
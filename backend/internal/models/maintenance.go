@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// StaleSession is an attendance session that is still active long after it started
+type StaleSession struct {
+	ID               uint      `json:"id"`
+	CourseScheduleID uint      `json:"course_schedule_id"`
+	LecturerID       uint      `json:"lecturer_id"`
+	StartTime        time.Time `json:"start_time"`
+	OpenHours        float64   `json:"open_hours"`
+}
+
+// EnrollmentFix is a course schedule whose cached enrolled count differs from its student group
+type EnrollmentFix struct {
+	CourseScheduleID uint `json:"course_schedule_id"`
+	StudentGroupID   uint `json:"student_group_id"`
+	Stored           int  `json:"stored"`
+	Actual           int  `json:"actual"`
+}
+
+// IntegrityIssue is the result of one data integrity check that found problems
+type IntegrityIssue struct {
+	Check       string `json:"check"`
+	Description string `json:"description"`
+	Count       int    `json:"count"`
+	SampleIDs   []uint `json:"sample_ids"` // Up to 10 IDs of the affected records
+}
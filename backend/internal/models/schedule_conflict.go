@@ -0,0 +1,112 @@
+package models
+
+import (
+	"time"
+)
+
+// ScheduleConflictType is the resource two overlapping schedules compete for
+type ScheduleConflictType string
+
+const (
+	ScheduleConflictRoom         ScheduleConflictType = "room"
+	ScheduleConflictLecturer     ScheduleConflictType = "lecturer"
+	ScheduleConflictStudentGroup ScheduleConflictType = "student_group"
+)
+
+// ScheduleConflictStatus is the decision of an admin on a conflict
+type ScheduleConflictStatus string
+
+const (
+	// ScheduleConflictBlocking conflicts must be resolved by moving one of the schedules, conflicts are blocking until reviewed
+	ScheduleConflictBlocking ScheduleConflictStatus = "blocking"
+	// ScheduleConflictAcknowledged conflicts are intended, such as a lecturer teaching two groups together
+	ScheduleConflictAcknowledged ScheduleConflictStatus = "acknowledged"
+)
+
+// ScheduleConflictReview records the status an admin gave to the conflict of two schedules
+// A review only applies while the schedules overlap on the reviewed slot, so moving one of them
+// makes a remaining conflict blocking again
+type ScheduleConflictReview struct {
+	ID              uint                   `gorm:"primaryKey" json:"id"`
+	AcademicYearID  uint                   `gorm:"not null;uniqueIndex:idx_schedule_conflict_reviews_pair" json:"academic_year_id"`
+	Type            ScheduleConflictType   `gorm:"type:varchar(20);not null;uniqueIndex:idx_schedule_conflict_reviews_pair" json:"type"`
+	ScheduleID      uint                   `gorm:"not null;uniqueIndex:idx_schedule_conflict_reviews_pair" json:"schedule_id"`       // Lower ID of the pair
+	OtherScheduleID uint                   `gorm:"not null;uniqueIndex:idx_schedule_conflict_reviews_pair" json:"other_schedule_id"` // Higher ID of the pair
+	Slot            string                 `gorm:"type:varchar(30);not null" json:"slot"`                                            // Overlap when reviewed, such as "Senin 08:00-09:40"
+	Status          ScheduleConflictStatus `gorm:"type:varchar(20);not null" json:"status"`
+	Note            string                 `gorm:"type:text" json:"note"`
+	ReviewedBy      string                 `gorm:"type:varchar(100)" json:"reviewed_by"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+// TableName returns the table name for the ScheduleConflictReview model
+func (ScheduleConflictReview) TableName() string {
+	return "schedule_conflict_reviews"
+}
+
+// ScheduleConflictSchedule is one of the two schedules of a conflict
+type ScheduleConflictSchedule struct {
+	ID         uint   `json:"id"`
+	CourseCode string `json:"course_code,omitempty"`
+	CourseName string `json:"course_name,omitempty"`
+	StartTime  string `json:"start_time"`
+	EndTime    string `json:"end_time"`
+}
+
+// ScheduleConflict is a pair of schedules of an academic year that use the same room, lecturer or
+// student group at overlapping times
+type ScheduleConflict struct {
+	Type           ScheduleConflictType       `json:"type"`
+	ResourceID     uint                       `json:"resource_id"` // ID of the room, lecturer user or student group
+	ResourceName   string                     `json:"resource_name,omitempty"`
+	Day            string                     `json:"day"`
+	ScheduleIDs    []uint                     `json:"schedule_ids"` // The two clashing schedules, lower ID first
+	Schedules      []ScheduleConflictSchedule `json:"schedules"`
+	OverlapStart   string                     `json:"overlap_start"`
+	OverlapEnd     string                     `json:"overlap_end"`
+	OverlapMinutes int                        `json:"overlap_minutes"`
+	Status         ScheduleConflictStatus     `json:"status"`
+	Note           string                     `json:"note,omitempty"`
+	ReviewedBy     string                     `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time                 `json:"reviewed_at,omitempty"`
+}
+
+// ScheduleConflictReport lists the conflicts between the schedules of an academic year
+type ScheduleConflictReport struct {
+	AcademicYearID    uint               `json:"academic_year_id"`
+	AcademicYearName  string             `json:"academic_year_name"`
+	ScheduleCount     int                `json:"schedule_count"`
+	BlockingCount     int                `json:"blocking_count"`
+	AcknowledgedCount int                `json:"acknowledged_count"`
+	Conflicts         []ScheduleConflict `json:"conflicts"`
+}
+
+// ScheduleConflictStatusRequest represents the request body for marking a conflict blocking or acknowledged
+type ScheduleConflictStatusRequest struct {
+	AcademicYearID uint                   `json:"academic_year_id" binding:"required"`
+	Type           ScheduleConflictType   `json:"type" binding:"required,oneof=room lecturer student_group"`
+	ScheduleIDs    []uint                 `json:"schedule_ids" binding:"required,len=2"`
+	Status         ScheduleConflictStatus `json:"status" binding:"required,oneof=blocking acknowledged"`
+	Note           string                 `json:"note"`
+}
+
+// ScheduleConflictCheckRequest represents a proposed schedule slot to check for conflicts
+type ScheduleConflictCheckRequest struct {
+	ScheduleID     *uint  `json:"schedule_id"` // Schedule being moved, left out of the check
+	AcademicYearID uint   `json:"academic_year_id" binding:"required"`
+	RoomID         uint   `json:"room_id" binding:"required"`
+	LecturerID     uint   `json:"lecturer_id" binding:"required"`
+	StudentGroupID uint   `json:"student_group_id" binding:"required"`
+	Day            string `json:"day" binding:"required"`
+	StartTime      string `json:"start_time" binding:"required"` // HH:MM
+	EndTime        string `json:"end_time" binding:"required"`   // HH:MM
+}
+
+// ScheduleConflictCheck is the result of checking a proposed slot
+// A proposed new schedule has no ID yet, so its conflicts list only the existing schedule in ScheduleIDs
+// and the proposal with ID 0 in Schedules
+type ScheduleConflictCheck struct {
+	Conflicts           []ScheduleConflict `json:"conflicts"`
+	HasBlockingConflict bool               `json:"has_blocking_conflict"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// ScheduleExceptionKind tells what happens to one weekly class of a schedule
+type ScheduleExceptionKind string
+
+const (
+	// ScheduleExceptionCancelled classes do not take place
+	ScheduleExceptionCancelled ScheduleExceptionKind = "cancelled"
+	// ScheduleExceptionRescheduled classes take place once at another date, time or room instead
+	ScheduleExceptionRescheduled ScheduleExceptionKind = "rescheduled"
+)
+
+// ScheduleException cancels or moves the class of a course schedule on one date
+type ScheduleException struct {
+	ID               uint                  `json:"id" gorm:"primaryKey"`
+	CourseScheduleID uint                  `json:"course_schedule_id" gorm:"not null;uniqueIndex:idx_schedule_exceptions_date"`
+	Date             string                `json:"date" gorm:"type:varchar(10);not null;uniqueIndex:idx_schedule_exceptions_date"` // YYYY-MM-DD of the regular class
+	Kind             ScheduleExceptionKind `json:"kind" gorm:"type:varchar(20);not null"`
+	NewDate          string                `json:"new_date,omitempty" gorm:"type:varchar(10);index"` // Replacement session, for rescheduled classes
+	NewStartTime     string                `json:"new_start_time,omitempty" gorm:"type:varchar(5)"`
+	NewEndTime       string                `json:"new_end_time,omitempty" gorm:"type:varchar(5)"`
+	NewRoomID        *uint                 `json:"new_room_id,omitempty" gorm:"index"`
+	Note             string                `json:"note" gorm:"type:text"`
+	CreatedBy        string                `json:"created_by" gorm:"type:varchar(100)"`
+	CreatedAt        time.Time             `json:"created_at"`
+	UpdatedAt        time.Time             `json:"updated_at"`
+}
+
+// TableName returns the table name for the ScheduleException model
+func (ScheduleException) TableName() string {
+	return "schedule_exceptions"
+}
+
+// ScheduleExceptionRequest represents the request body for cancelling or moving the class of a schedule on one date
+// The replacement session defaults to the times and room of the schedule.
+type ScheduleExceptionRequest struct {
+	Date         string                `json:"date" binding:"required"` // YYYY-MM-DD of the regular class
+	Kind         ScheduleExceptionKind `json:"kind" binding:"required,oneof=cancelled rescheduled"`
+	NewDate      string                `json:"new_date"`       // YYYY-MM-DD, required for rescheduled classes
+	NewStartTime string                `json:"new_start_time"` // HH:MM
+	NewEndTime   string                `json:"new_end_time"`   // HH:MM
+	NewRoomID    *uint                 `json:"new_room_id"`
+	Note         string                `json:"note"`
+}
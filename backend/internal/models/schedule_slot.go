@@ -0,0 +1,40 @@
+package models
+
+// ScheduleSlotSearchRequest represents the constraints of a search for a free slot and room for a schedule
+type ScheduleSlotSearchRequest struct {
+	AcademicYearID  uint     `json:"academic_year_id" binding:"required"`
+	CourseID        uint     `json:"course_id" binding:"required"`
+	StudentGroupID  uint     `json:"student_group_id" binding:"required"`
+	LecturerID      uint     `json:"lecturer_id" binding:"required"`
+	DurationMinutes int      `json:"duration_minutes"` // Defaults to 50 minutes per credit of the course
+	Days            []string `json:"days"`             // Defaults to Senin to Jumat
+	EarliestStart   string   `json:"earliest_start"`   // HH:MM, defaults to 07:00
+	LatestEnd       string   `json:"latest_end"`       // HH:MM, defaults to 18:00
+	MinCapacity     int      `json:"min_capacity"`     // Defaults to the number of students in the group
+	BuildingID      uint     `json:"building_id"`      // Only rooms of this building when set
+	Limit           int      `json:"limit"`            // Defaults to 20, at most 100
+}
+
+// ScheduleSlotOption is a slot and room where the lecturer, the student group and the room are all free
+type ScheduleSlotOption struct {
+	Rank                   int    `json:"rank"`
+	Day                    string `json:"day"`
+	StartTime              string `json:"start_time"`
+	EndTime                string `json:"end_time"`
+	RoomID                 uint   `json:"room_id"`
+	RoomName               string `json:"room_name"`
+	BuildingID             uint   `json:"building_id"`
+	BuildingName           string `json:"building_name,omitempty"`
+	RoomCapacity           int    `json:"room_capacity"`
+	SpareSeats             int    `json:"spare_seats"`
+	GroupClassesThatDay    int    `json:"group_classes_that_day"`    // Classes the student group already has on the day
+	LecturerClassesThatDay int    `json:"lecturer_classes_that_day"` // Classes the lecturer already has on the day
+}
+
+// ScheduleSlotSearchResult lists the best free slots for a schedule, best first
+type ScheduleSlotSearchResult struct {
+	DurationMinutes int                  `json:"duration_minutes"`
+	MinCapacity     int                  `json:"min_capacity"`
+	TotalFound      int                  `json:"total_found"` // Options found before applying the limit
+	Options         []ScheduleSlotOption `json:"options"`
+}
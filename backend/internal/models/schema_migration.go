@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// SchemaMigration records a schema migration that has been applied to the database
+type SchemaMigration struct {
+	Version   int       `json:"version" gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `json:"applied_at" gorm:"not null"`
+}
+
+// TableName returns the table name for the SchemaMigration model
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationState describes a known migration and whether it has been applied
+type MigrationState struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
@@ -38,12 +38,15 @@ func (e *EmbeddingArray) Scan(value interface{}) error {
 		return nil
 	}
 
-	bytes, ok := value.([]byte)
-	if !ok {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, e)
+	case string:
+		// SQLite may return JSON columns as text
+		return json.Unmarshal([]byte(v), e)
+	default:
 		return errors.New("type assertion to []byte failed")
 	}
-
-	return json.Unmarshal(bytes, e)
 }
 
 // TableName specifies the table name for StudentFace
@@ -36,4 +36,25 @@ type StudentToGroup struct {
 // TableName returns the table name for the StudentToGroup model
 func (StudentToGroup) TableName() string {
 	return "student_to_groups"
-} 
\ No newline at end of file
+} 
+// StudentGroupRequest represents the request body for creating or updating a student group
+type StudentGroupRequest struct {
+	Name         string `json:"name" binding:"required"`
+	DepartmentID uint   `json:"department_id" binding:"required"`
+}
+
+// StudentGroupMemberRequest represents the request body for adding a student to a group
+type StudentGroupMemberRequest struct {
+	StudentID uint `json:"student_id" binding:"required"`
+}
+
+// StudentGroupBatchRequest represents the request body for adding or removing several students at once
+type StudentGroupBatchRequest struct {
+	StudentIDs []uint `json:"student_ids" binding:"required"`
+}
+
+// StudentGroupBatchResult represents the outcome of adding or removing several students at once
+type StudentGroupBatchResult struct {
+	SuccessCount   int    `json:"success_count"`
+	FailedStudents []uint `json:"failed_students"`
+}
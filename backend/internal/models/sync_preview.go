@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// SyncFieldChange describes one field whose value differs between the database and the campus API
+type SyncFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// SyncRecordDiff identifies one record in a sync diff
+type SyncRecordDiff struct {
+	Key     string            `json:"key"`   // External identifier used to match records
+	Label   string            `json:"label"` // Human readable name
+	Changes []SyncFieldChange `json:"changes,omitempty"`
+}
+
+// SyncDiff compares the records returned by the campus API with the database
+type SyncDiff struct {
+	Entity         string           `json:"entity"`
+	CampusCount    int              `json:"campus_count"`
+	LocalCount     int              `json:"local_count"`
+	New            []SyncRecordDiff `json:"new"`
+	Changed        []SyncRecordDiff `json:"changed"`
+	Missing        []SyncRecordDiff `json:"missing"` // Active in the database but no longer returned by the campus API, deactivated on apply
+	UnchangedCount int              `json:"unchanged_count"`
+	MissingPercent float64          `json:"missing_percent"`
+	ChangedPercent float64          `json:"changed_percent"`
+	Warnings       []string         `json:"warnings"`
+	Blocked        bool             `json:"blocked"` // Too many records would disappear, applying requires force
+}
+
+// SyncPreview is a dry-run sync kept for review until an admin applies it
+type SyncPreview struct {
+	ID        string    `json:"id"`
+	Entity    string    `json:"entity"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Diff      SyncDiff  `json:"diff"`
+}
+
+// SyncApplyRequest represents the request body for applying a sync preview
+type SyncApplyRequest struct {
+	Force bool `json:"force"` // Apply even if the safety threshold is exceeded
+}
+
+// SyncPreviewRecord stores a sync preview with the campus records it fetched until it expires,
+// so every instance can show and apply it
+type SyncPreviewRecord struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(32)"`
+	Entity    string    `json:"entity" gorm:"type:varchar(20);not null"`
+	CreatedBy string    `json:"created_by" gorm:"type:varchar(100)"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	Diff      string    `json:"diff" gorm:"type:text;not null"`    // JSON of the SyncDiff
+	Records   string    `json:"records" gorm:"type:text;not null"` // JSON of the fetched campus records
+}
+
+// TableName returns the table name for the SyncPreviewRecord model
+func (SyncPreviewRecord) TableName() string {
+	return "sync_previews"
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+)
+
+// Entities that are synchronized from the campus API
+const (
+	SyncEntityStudents  = "students"
+	SyncEntityLecturers = "lecturers"
+	SyncEntityEmployees = "employees"
+)
+
+// SyncEntities lists all entities that can be synchronized
+var SyncEntities = []string{SyncEntityStudents, SyncEntityLecturers, SyncEntityEmployees}
+
+// What started a sync run
+const (
+	SyncTriggerManual    = "manual"
+	SyncTriggerScheduled = "scheduled"
+)
+
+// Sync run statuses
+const (
+	SyncStatusRunning = "running"
+	SyncStatusSuccess = "success"
+	SyncStatusPartial = "partial" // Finished, but some records failed
+	SyncStatusFailed  = "failed"
+)
+
+// SyncRun records one synchronization of an entity with the campus API
+type SyncRun struct {
+	ID               uint              `json:"id" gorm:"primaryKey"`
+	Entity           string            `json:"entity" gorm:"type:varchar(20);not null;index"`
+	Trigger          string            `json:"trigger" gorm:"type:varchar(20);not null"`
+	ActorUserID      *uint             `json:"actor_user_id"` // Admin who started a manual run
+	ActorUsername    string            `json:"actor_username" gorm:"type:varchar(100)"`
+	Status           string            `json:"status" gorm:"type:varchar(20);not null;index"`
+	StartedAt        time.Time         `json:"started_at" gorm:"not null;index"`
+	FinishedAt       *time.Time        `json:"finished_at"`
+	DurationMs       int64             `json:"duration_ms"`
+	RecordCount      int               `json:"record_count"` // Records returned by the campus API
+	InsertedCount    int               `json:"inserted_count"`
+	UpdatedCount     int               `json:"updated_count"`
+	DeactivatedCount int               `json:"deactivated_count"`
+	FailedCount      int               `json:"failed_count"`
+	Error            string            `json:"error" gorm:"type:text"`
+	Errors           []SyncRecordError `json:"errors,omitempty" gorm:"foreignKey:SyncRunID"`
+}
+
+// TableName returns the table name for the SyncRun model
+func (SyncRun) TableName() string {
+	return "sync_runs"
+}
+
+// SyncRecordError records why a single record could not be synced
+type SyncRecordError struct {
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	SyncRunID uint   `json:"sync_run_id" gorm:"not null;index"`
+	RecordKey string `json:"record_key" gorm:"type:varchar(50)"`
+	Label     string `json:"label" gorm:"type:varchar(255)"`
+	Message   string `json:"message" gorm:"type:text"`
+}
+
+// TableName returns the table name for the SyncRecordError model
+func (SyncRecordError) TableName() string {
+	return "sync_record_errors"
+}
+
+// SyncResult counts what applying a sync changed
+type SyncResult struct {
+	Received    int
+	Inserted    int
+	Updated     int
+	Deactivated int
+	Errors      []SyncRecordError
+}
+
+// AddError records a record that could not be synced
+func (r *SyncResult) AddError(key, label string, err error) {
+	r.Errors = append(r.Errors, SyncRecordError{RecordKey: key, Label: label, Message: err.Error()})
+}
+
+// SyncScheduleStatus describes the schedule and latest run of one entity
+type SyncScheduleStatus struct {
+	Entity              string     `json:"entity"`
+	Schedule            string     `json:"schedule"` // Empty when scheduled sync is disabled for the entity
+	NextRunAt           *time.Time `json:"next_run_at"`
+	Running             bool       `json:"running"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastRun             *SyncRun   `json:"last_run"`
+	LastSuccessAt       *time.Time `json:"last_success_at"`
+}
+
+// SyncFreshness tells how old the data of an entity is
+type SyncFreshness struct {
+	Entity        string     `json:"entity"`
+	LastSuccessAt *time.Time `json:"last_success_at"`
+	AgeHours      *float64   `json:"age_hours"`
+	Stale         bool       `json:"stale"` // Never synced, or older than the staleness threshold
+}
+
+// SyncResponse represents the outcome of a sync started by an admin
+type SyncResponse struct {
+	Count     int  `json:"count"` // Inserted and updated records
+	Failed    int  `json:"failed"`
+	SyncRunID uint `json:"sync_run_id"`
+}
+
+// LecturerSyncResponse represents the response of a lecturer sync, which has no envelope
+type LecturerSyncResponse struct {
+	Message string `json:"message"`
+	SyncResponse
+}
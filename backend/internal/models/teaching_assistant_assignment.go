@@ -67,3 +67,10 @@ type TeachingAssistantAssignmentResponse struct {
 func (TeachingAssistantAssignment) TableName() string {
 	return "teaching_assistant_assignments"
 }
+
+// TeachingAssistantAssignmentRequest represents the request body for assigning a teaching assistant to a course
+type TeachingAssistantAssignmentRequest struct {
+	EmployeeID     uint `json:"employee_id" binding:"required"`
+	CourseID       uint `json:"course_id" binding:"required"`
+	AcademicYearID uint `json:"academic_year_id"` // Defaults to the active academic year
+}
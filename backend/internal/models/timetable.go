@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+)
+
+// TimetableDraftStatus is the state of a generated timetable
+type TimetableDraftStatus string
+
+const (
+	TimetableDraftOpen      TimetableDraftStatus = "draft"
+	TimetableDraftCommitted TimetableDraftStatus = "committed"
+)
+
+// TimetableDraft is a timetable proposed by the solver for an academic year
+// Its entries become course schedules only when an admin commits it
+type TimetableDraft struct {
+	ID              uint                  `gorm:"primaryKey" json:"id"`
+	AcademicYearID  uint                  `gorm:"not null;index" json:"academic_year_id"`
+	Status          TimetableDraftStatus  `gorm:"type:varchar(20);not null" json:"status"`
+	Score           int                   `gorm:"not null;default:0" json:"score"` // Penalty of the soft preferences, lower is better
+	GapMinutes      int                   `gorm:"not null;default:0" json:"gap_minutes"`
+	AvoidedMinutes  int                   `gorm:"not null;default:0" json:"avoided_minutes"` // Minutes placed in windows lecturers would rather avoid
+	BuildingChanges int                   `gorm:"not null;default:0" json:"building_changes"`
+	Unplaced        []TimetableUnplaced   `gorm:"serializer:json;type:text" json:"unplaced"`
+	CreatedBy       string                `gorm:"type:varchar(100)" json:"created_by"`
+	CommittedBy     string                `gorm:"type:varchar(100)" json:"committed_by,omitempty"`
+	CommittedAt     *time.Time            `json:"committed_at,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+	Entries         []TimetableDraftEntry `gorm:"foreignKey:DraftID" json:"entries,omitempty"`
+}
+
+// TableName returns the table name for the TimetableDraft model
+func (TimetableDraft) TableName() string {
+	return "timetable_drafts"
+}
+
+// TimetableDraftEntry is one weekly class of a timetable draft
+type TimetableDraftEntry struct {
+	ID             uint         `gorm:"primaryKey" json:"id"`
+	DraftID        uint         `gorm:"not null;index" json:"draft_id"`
+	CourseID       uint         `gorm:"not null" json:"course_id"`
+	Course         Course       `gorm:"foreignKey:CourseID" json:"course,omitempty"`
+	StudentGroupID uint         `gorm:"not null" json:"student_group_id"`
+	StudentGroup   StudentGroup `gorm:"foreignKey:StudentGroupID" json:"student_group,omitempty"`
+	LecturerID     uint         `gorm:"not null" json:"lecturer_id"` // Becomes the lecturer_id of the course schedule
+	RoomID         uint         `gorm:"not null" json:"room_id"`
+	Room           Room         `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+	Day            string       `gorm:"not null" json:"day"`
+	StartTime      string       `gorm:"not null" json:"start_time"`
+	EndTime        string       `gorm:"not null" json:"end_time"`
+	ScheduleID     *uint        `json:"schedule_id,omitempty"` // Course schedule created when the draft was committed
+}
+
+// TableName returns the table name for the TimetableDraftEntry model
+func (TimetableDraftEntry) TableName() string {
+	return "timetable_draft_entries"
+}
+
+// TimetableUnplaced is a class the solver could not place without breaking a hard constraint
+type TimetableUnplaced struct {
+	CourseID       uint   `json:"course_id"`
+	StudentGroupID uint   `json:"student_group_id"`
+	LecturerID     uint   `json:"lecturer_id"`
+	Minutes        int    `json:"minutes"`
+	Reason         string `json:"reason"`
+}
+
+// TimetableCourseGroups names the student groups that take a course
+type TimetableCourseGroups struct {
+	CourseID        uint   `json:"course_id" binding:"required"`
+	StudentGroupIDs []uint `json:"student_group_ids" binding:"required"`
+}
+
+// TimetableSolveRequest represents the request body for generating a timetable draft
+type TimetableSolveRequest struct {
+	AcademicYearID uint                    `json:"academic_year_id" binding:"required"`
+	Days           []string                `json:"days"`           // Defaults to Senin to Jumat
+	EarliestStart  string                  `json:"earliest_start"` // HH:MM, defaults to 07:00
+	LatestEnd      string                  `json:"latest_end"`     // HH:MM, defaults to 18:00
+	CourseGroups   []TimetableCourseGroups `json:"course_groups"`  // Defaults to the groups of the study program of each course
+}
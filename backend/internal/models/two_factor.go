@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+)
+
+// TwoFactorStep values returned in the login response when a second step is required
+const (
+	TwoFactorStepVerify = "verify" // The user must enter a TOTP or recovery code
+	TwoFactorStepSetup  = "setup"  // The role requires 2FA and the user must enroll first
+)
+
+// UserTwoFactor holds the TOTP enrollment of a user
+type UserTwoFactor struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       uint       `json:"user_id" gorm:"uniqueIndex;not null"`
+	Secret       string     `json:"-" gorm:"type:varchar(64);not null"` // Base32 TOTP secret, never returned in JSON
+	Enabled      bool       `json:"enabled" gorm:"default:false"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep int64      `json:"-" gorm:"default:0"` // Last accepted TOTP time step, prevents code replay
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for the UserTwoFactor model
+func (UserTwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// RecoveryCode is a single-use code that can replace a TOTP code at login
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"index;not null"`
+	CodeHash  string     `json:"-" gorm:"type:varchar(64);not null"` // SHA-256 hex digest of the code
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for the RecoveryCode model
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
+
+// TwoFactorChallengeResponse is returned by login when a second step is pending
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	TwoFactorStep     string `json:"two_factor_step"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int    `json:"expires_in"` // Seconds until the challenge token expires
+}
+
+// TwoFactorLoginRequest represents the second login step request body
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code"`          // TOTP code
+	RecoveryCode   string `json:"recovery_code"` // Alternative to Code
+}
+
+// TwoFactorChallengeRequest represents a request authenticated by a challenge token only
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+// TwoFactorCodeRequest represents a request carrying a TOTP code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TwoFactorSetupResponse is returned when a user starts TOTP enrollment
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// URI to render as a QR code
+}
+
+// TwoFactorStatus describes the 2FA state of a user
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	Required               bool       `json:"required"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// RecoveryCodesResponse represents newly generated recovery codes, they are shown only once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorEnabledLoginResponse represents a login completed by enabling two-factor authentication
+type TwoFactorEnabledLoginResponse struct {
+	User          User     `json:"user"`
+	Token         string   `json:"token"`
+	RefreshToken  string   `json:"refresh_token"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -59,9 +59,11 @@ type LoginRequest struct {
 
 // LoginResponse represents the login response body
 type LoginResponse struct {
-	User         User   `json:"user"`
-	Token        string `json:"token"`         // JWT token for authorization
-	RefreshToken string `json:"refresh_token"` // Field name must match frontend expectations
+	User         User                        `json:"user"`
+	Token        string                      `json:"token"`               // JWT token for authorization
+	RefreshToken string                      `json:"refresh_token"`       // Field name must match frontend expectations
+	TwoFactor    *TwoFactorChallengeResponse `json:"two_factor,omitempty"` // Set instead of tokens when a second step is pending
+	Offline      bool                        `json:"offline,omitempty"`    // Set when the login was validated against the cache while CIS was down
 }
 
 // OrderedLoginResponse represents a login response with controlled field order
@@ -69,9 +71,41 @@ type OrderedLoginResponse struct {
 	User         User   `json:"user"`
 	Token        string `json:"token"`
 	RefreshToken string `json:"refresh_token"`
+	Offline      bool   `json:"offline,omitempty"`
 }
 
 // RefreshRequest represents the refresh token request body
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
-} 
\ No newline at end of file
+}
+
+// ImpersonationRequest represents the request body for starting an impersonation
+type ImpersonationRequest struct {
+	UserID   uint   `json:"user_id" binding:"required"` // Local user ID of the target
+	Reason   string `json:"reason" binding:"required"`
+	ReadOnly *bool  `json:"read_only"` // Defaults to true
+}
+
+// ImpersonationResponse represents the response for a started impersonation
+type ImpersonationResponse struct {
+	Token         string    `json:"token"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	ReadOnly      bool      `json:"read_only"`
+	Impersonating User      `json:"impersonating"`
+}
+
+// CurrentUserResponse represents the signed-in user of a token
+type CurrentUserResponse struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	// Set while an admin is viewing as this user
+	ImpersonatedBy *ImpersonatorResponse `json:"impersonated_by,omitempty"`
+	ReadOnly       *bool                 `json:"read_only,omitempty"`
+}
+
+// ImpersonatorResponse represents the admin behind an impersonation token
+type ImpersonatorResponse struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
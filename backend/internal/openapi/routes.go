@@ -0,0 +1,371 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/services"
+)
+
+// Tags of the operations
+const (
+	tagHealth        = "Health"
+	tagAuth          = "Authentication"
+	tagIntegrations  = "Integrations"
+	tagCalendar      = "Calendar feeds"
+	tagAdminAccounts = "Admin: accounts and security"
+	tagAdminSync     = "Admin: campus sync"
+	tagAdminPeople   = "Admin: lecturers, employees and students"
+	tagAdminCampus   = "Admin: faculties, programs, buildings and rooms"
+	tagAdminCourses  = "Admin: courses and schedules"
+	tagLecturer      = "Lecturer"
+	tagEmployee      = "Employee"
+	tagAssistant     = "Teaching assistant"
+	tagStudent       = "Student"
+)
+
+const (
+	xlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	ics  = "text/calendar"
+)
+
+// healthStatus is the body of /healthz
+type healthStatus struct {
+	Status string `json:"status"`
+}
+
+// tokenResponse is the body of the campus token routes, which have no envelope
+type tokenResponse struct {
+	Message string `json:"message"`
+	Token   string `json:"token"`
+}
+
+// group holds what the routes of a router group share
+type group struct {
+	prefix string
+	tag    string
+	auth   Auth
+	roles  []string
+}
+
+// route documents a route of the group
+func (g group) route(method, path, summary string, body interface{}, reply Reply, query ...*Parameter) Route {
+	return Route{
+		Method:  method,
+		Path:    g.prefix + path,
+		Tag:     g.tag,
+		Summary: summary,
+		Auth:    g.auth,
+		Roles:   g.roles,
+		Query:   query,
+		Body:    body,
+		Reply:   reply,
+	}
+}
+
+// with returns the group with another tag
+func (g group) with(tag string) group {
+	g.tag = tag
+	return g
+}
+
+// queryParam documents an optional query parameter
+func queryParam(name, description string, schema *Schema) *Parameter {
+	return &Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// header documents an optional request header
+func header(name, description string) *Parameter {
+	return &Parameter{Name: name, In: "header", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+func queryString(name, description string) *Parameter {
+	return queryParam(name, description, &Schema{Type: "string"})
+}
+
+func queryID(name, description string) *Parameter {
+	min := 0.0
+	return queryParam(name, description, &Schema{Type: "integer", Minimum: &min})
+}
+
+func queryBool(name, description string) *Parameter {
+	return queryParam(name, description, &Schema{Type: "boolean"})
+}
+
+func queryDate(name, description string) *Parameter {
+	return queryParam(name, description, &Schema{Type: "string", Format: "date"})
+}
+
+// required marks a query parameter as required
+func required(p *Parameter) *Parameter {
+	p.Required = true
+	return p
+}
+
+var (
+	statsQuery        = queryBool("stats", "Include statistics, the data then has the WithStats shape")
+	academicYearQuery = queryParam("academic_year_id", "Academic year ID, or all", &Schema{Type: "string", Pattern: "^([0-9]+|all)$"})
+)
+
+// listQuery documents the query parameters shared by list endpoints, see handlers.parseListQuery
+func listQuery(extra ...*Parameter) []*Parameter {
+	explode := true
+	min, maxSize := 1.0, float64(models.MaxPageSize)
+	params := []*Parameter{
+		queryParam("page", "Page number, starting at 1", &Schema{Type: "integer", Minimum: &min}),
+		queryParam("page_size", "Records per page", &Schema{Type: "integer", Minimum: &min, Maximum: &maxSize}),
+		queryString("cursor", "Cursor pagination, empty for the first page and then the next_cursor of the previous page"),
+		queryString("sort", "Comma-separated sort fields, descending with -, such as -date,full_name"),
+		queryString("fields", "Comma-separated fields to return"),
+		{
+			Name:        "filter",
+			In:          "query",
+			Description: "Filters as filter[field]=value or filter[field][op]=value, op is eq, ne, gt, gte, lt, lte, in or like",
+			Style:       "deepObject",
+			Explode:     &explode,
+			Schema:      &Schema{Type: "object", AdditionalProperties: &Schema{}},
+		},
+	}
+	return append(params, extra...)
+}
+
+// routes documents every route registered in cmd/server, in the same order
+func routes() []Route {
+	get, post, put, del := http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete
+
+	root := group{tag: tagHealth}
+	metrics := group{tag: tagHealth, auth: MetricsToken}
+	public := group{prefix: "/api", tag: tagAuth}
+	integrations := group{prefix: "/api/integrations", tag: tagIntegrations, auth: APIKey}
+	calendar := group{prefix: "/api", tag: tagCalendar}
+	signedIn := group{prefix: "/api", tag: tagAuth, auth: Bearer}
+	admin := group{prefix: "/api/admin", auth: Bearer, roles: []string{"Admin"}}
+	accounts := admin.with(tagAdminAccounts)
+	sync := admin.with(tagAdminSync)
+	people := admin.with(tagAdminPeople)
+	campus := admin.with(tagAdminCampus)
+	courses := admin.with(tagAdminCourses)
+	lecturer := group{prefix: "/api/lecturer", tag: tagLecturer, auth: Bearer, roles: []string{"Dosen"}}
+	employee := group{prefix: "/api/employee", tag: tagEmployee, auth: Bearer, roles: []string{"Pegawai"}}
+	assistant := group{prefix: "/api/assistant", tag: tagAssistant, auth: Bearer, roles: []string{"Asisten Dosen"}}
+	student := group{prefix: "/api/student", tag: tagStudent, auth: Bearer, roles: []string{"Mahasiswa"}}
+
+	loginReply := Raw(OneOf{models.OrderedLoginResponse{}, models.TwoFactorChallengeResponse{}})
+
+	return []Route{
+		root.route(get, "/healthz", "Liveness probe", nil, Raw(healthStatus{})),
+		root.route(get, "/readyz", "Readiness probe, 503 when the server cannot take traffic", nil, Raw(models.ReadinessReport{})),
+		metrics.route(get, "/metrics", "Prometheus metrics", nil, Text()),
+
+		public.route(post, "/auth/login", "Sign in with username and password", models.LoginRequest{}, loginReply),
+		public.route(post, "/auth/refresh", "Exchange a refresh token for new tokens", models.RefreshRequest{}, Raw(models.OrderedLoginResponse{})),
+		public.route(post, "/auth/login/2fa", "Complete a login with a two-factor or recovery code", models.TwoFactorLoginRequest{}, Raw(models.OrderedLoginResponse{})),
+		public.route(post, "/auth/login/2fa/setup", "Start the two-factor setup required to complete a login", models.TwoFactorChallengeRequest{}, Data(models.TwoFactorSetupResponse{})),
+		public.route(post, "/auth/login/2fa/enable", "Enable two-factor authentication and complete the login", models.TwoFactorLoginRequest{}, Raw(models.TwoFactorEnabledLoginResponse{})),
+		public.route(post, "/auth/campus/login", "Sign in with campus (CIS) credentials, as JSON or form", models.CampusLoginRequest{}, loginReply),
+
+		integrations.route(get, "/rooms/:room_id/active-session", "Active attendance session of a room, for kiosks", nil, Data(models.RoomActiveSessionResponse{})),
+		integrations.route(get, "/rooms/:room_id/schedules", "Course schedules of a room", nil, Data([]models.CourseScheduleResponse{})),
+		integrations.route(get, "/reports/attendance", "Attendance statistics per course schedule", nil, Data([]models.AttendanceReportRow{}),
+			required(queryID("academic_year_id", "")), queryID("study_program_id", "")),
+
+		calendar.route(get, "/calendar/:token", "iCalendar feed of a student, lecturer or room timetable, the token in the URL is the credential", nil, File(ics)),
+
+		signedIn.route(get, "/auth/me", "Signed-in user", nil, Raw(models.CurrentUserResponse{})),
+
+		accounts.route(get, "/campus/token", "Current campus API token", nil, Raw(tokenResponse{})),
+		accounts.route(post, "/campus/token/refresh", "Refresh the campus API token", nil, Raw(tokenResponse{})),
+		accounts.route(get, "/auth/lockouts", "Locked accounts", nil, Data([]models.LoginAttempt{})),
+		accounts.route(post, "/auth/lockouts/unlock", "Unlock an account", models.UnlockRequest{}, Data([]string{})),
+		accounts.route(get, "/2fa", "Two-factor status of the admin account", nil, Data(models.TwoFactorStatus{})),
+		accounts.route(post, "/2fa/setup", "Start the two-factor setup", nil, Data(models.TwoFactorSetupResponse{})),
+		accounts.route(post, "/2fa/enable", "Enable two-factor authentication", models.TwoFactorCodeRequest{}, Data(models.RecoveryCodesResponse{})),
+		accounts.route(post, "/2fa/disable", "Disable two-factor authentication", models.TwoFactorCodeRequest{}, Message()),
+		accounts.route(post, "/2fa/recovery-codes", "Regenerate the recovery codes", models.TwoFactorCodeRequest{}, Data(models.RecoveryCodesResponse{})),
+		accounts.route(get, "/audit-logs", "Audit log entries, newest first", nil, List(models.AuditLog{}),
+			listQuery(queryString("action", "Same as filter[action]"), queryID("actor_user_id", "Same as filter[actor_user_id]"))...),
+		accounts.route(post, "/impersonate", "View the app as another user", models.ImpersonationRequest{}, Data(models.ImpersonationResponse{})),
+		accounts.route(get, "/api-keys", "API keys", nil, List(models.APIKey{}), listQuery()...),
+		accounts.route(get, "/api-keys/:id", "API key", nil, Data(models.APIKey{})),
+		accounts.route(post, "/api-keys", "Create an API key, the key is only returned here", models.APIKeyRequest{}, Created(models.APIKeyCreatedResponse{})),
+		optionalBody(accounts.route(post, "/api-keys/:id/rotate", "Rotate an API key", models.APIKeyRotateRequest{}, Data(models.APIKeyCreatedResponse{}))),
+		accounts.route(del, "/api-keys/:id", "Revoke an API key", nil, Message()),
+		accounts.route(get, "/config", "Effective configuration with secrets redacted", nil, Data(config.View{})),
+
+		sync.route(get, "/sync/status", "Schedule and latest run of each sync", nil, Data([]models.SyncScheduleStatus{})),
+		sync.route(get, "/sync/freshness", "Age of the synced data", nil, Data([]models.SyncFreshness{})),
+		sync.route(get, "/sync/runs", "Sync runs, latest first", nil, List(models.SyncRun{}),
+			listQuery(queryString("entity", "Same as filter[entity]"), queryString("status", "Same as filter[status]"))...),
+		sync.route(get, "/sync/runs/:id", "Sync run with its record errors", nil, Data(models.SyncRun{})),
+		sync.route(post, "/sync/:entity/preview", "Preview a sync without applying it", nil, Data(models.SyncPreview{})),
+		sync.route(get, "/sync/previews/:id", "Sync preview", nil, Data(models.SyncPreview{})),
+		optionalBody(sync.route(post, "/sync/previews/:id/apply", "Apply a sync preview", models.SyncApplyRequest{}, Data(models.SyncRun{}))),
+
+		people.route(get, "/lecturers", "Lecturers", nil, List(models.LecturerResponse{}), listQuery()...),
+		people.route(get, "/lecturers/search", "Search lecturers for dropdowns", nil, Data([]models.LecturerOption{}),
+			queryString("query", ""), queryString("q", "Alias of query")),
+		people.route(get, "/lecturers/:id", "Lecturer", nil, Data(models.Lecturer{})),
+		people.route(post, "/lecturers/sync", "Sync lecturers from the campus API", nil, Raw(models.LecturerSyncResponse{})),
+		people.route(get, "/employees", "Employees", nil, List(models.Employee{}), listQuery()...),
+		people.route(get, "/employees/:id", "Employee", nil, Data(models.Employee{})),
+		people.route(post, "/employees/sync", "Sync employees from the campus API", nil, Data(models.SyncResponse{})),
+		people.route(get, "/students", "Students", nil, List(models.Student{}), listQuery()...),
+		people.route(get, "/students/:id", "Student", nil, Data(models.Student{})),
+		people.route(get, "/students/by-user-id/:user_id", "Student by campus user ID", nil, Data(models.Student{})),
+		people.route(post, "/students/sync", "Sync students from the campus API", nil, Data(models.SyncResponse{})),
+
+		campus.route(get, "/faculties", "Faculties", nil, List(OneOf{models.Faculty{}, services.FacultyWithStats{}}), listQuery(statsQuery)...),
+		campus.route(get, "/faculties/:id", "Faculty", nil, Data(OneOf{models.Faculty{}, services.FacultyWithStats{}}), statsQuery),
+		campus.route(post, "/faculties", "Create a faculty", models.Faculty{}, Created(models.Faculty{})),
+		campus.route(put, "/faculties/:id", "Update a faculty", models.Faculty{}, Data(models.Faculty{})),
+		campus.route(del, "/faculties/:id", "Delete a faculty", nil, Message()),
+		campus.route(get, "/study-programs", "Study programs", nil, List(OneOf{models.StudyProgram{}, services.StudyProgramWithStats{}}),
+			listQuery(statsQuery, queryID("faculty_id", "Same as filter[faculty_id]"))...),
+		campus.route(get, "/study-programs/:id", "Study program", nil, Data(OneOf{models.StudyProgram{}, services.StudyProgramWithStats{}}), statsQuery),
+		campus.route(post, "/study-programs", "Create a study program", models.StudyProgram{}, Created(models.StudyProgram{})),
+		campus.route(put, "/study-programs/:id", "Update a study program", models.StudyProgram{}, Data(models.StudyProgram{})),
+		campus.route(del, "/study-programs/:id", "Delete a study program", nil, Message()),
+		campus.route(get, "/buildings", "Buildings", nil, List(OneOf{models.Building{}, services.BuildingWithStats{}}), listQuery(statsQuery)...),
+		campus.route(get, "/buildings/:id", "Building", nil, Data(OneOf{models.Building{}, services.BuildingWithStats{}}), statsQuery),
+		campus.route(post, "/buildings", "Create a building", models.Building{}, Created(models.Building{})),
+		campus.route(put, "/buildings/:id", "Update a building", models.Building{}, Data(models.Building{})),
+		campus.route(del, "/buildings/:id", "Delete a building", nil, Message()),
+		campus.route(get, "/rooms", "Rooms", nil, List(models.Room{}), listQuery(queryID("building_id", "Same as filter[building_id]"))...),
+		campus.route(get, "/rooms/:id", "Room", nil, Data(models.Room{})),
+		campus.route(post, "/rooms", "Create a room", models.Room{}, Created(models.Room{})),
+		campus.route(put, "/rooms/:id", "Update a room", models.Room{}, Data(models.Room{})),
+		campus.route(del, "/rooms/:id", "Delete a room", nil, Message()),
+		campus.route(get, "/rooms/:id/calendar-feeds", "Calendar feeds of a room that are not revoked", nil, Data([]models.CalendarFeed{})),
+		optionalBody(campus.route(post, "/rooms/:id/calendar-feeds", "Create a calendar feed of a room, the URL is only returned here", models.CalendarFeedRequest{}, Created(models.CalendarFeedCreatedResponse{}))),
+		campus.route(del, "/calendar-feeds/:id", "Revoke a calendar feed", nil, Message()),
+
+		courses.route(get, "/academic-years", "Academic years", nil, List(OneOf{models.AcademicYear{}, services.AcademicYearWithStats{}}), listQuery(statsQuery)...),
+		courses.route(get, "/academic-years/:id", "Academic year", nil, Data(models.AcademicYear{})),
+		courses.route(post, "/academic-years", "Create an academic year", models.AcademicYear{}, Created(models.AcademicYear{})),
+		courses.route(put, "/academic-years/:id", "Update an academic year", models.AcademicYear{}, Data(models.AcademicYear{})),
+		courses.route(del, "/academic-years/:id", "Delete an academic year", nil, Message()),
+		courses.route(get, "/courses", "Courses", nil, List(models.Course{}), listQuery(
+			queryID("department_id", "Same as filter[department_id]"), queryID("academic_year_id", "Same as filter[academic_year_id]"),
+			queryParam("semester", "Same as filter[semester]", &Schema{Type: "integer"}),
+			queryBool("active_academic_year", "Only courses of the active academic year"))...),
+		courses.route(get, "/courses/:id", "Course", nil, Data(models.Course{})),
+		courses.route(post, "/courses", "Create a course", models.Course{}, Created(models.Course{})),
+		courses.route(put, "/courses/:id", "Update a course", models.Course{}, Data(models.Course{})),
+		courses.route(del, "/courses/:id", "Delete a course", nil, Message()),
+		courses.route(get, "/student-groups", "Student groups", nil, List(models.StudentGroup{}), listQuery(queryID("department_id", "Same as filter[department_id]"))...),
+		courses.route(get, "/student-groups/:id", "Student group", nil, Data(models.StudentGroup{})),
+		courses.route(post, "/student-groups", "Create a student group", models.StudentGroupRequest{}, Created(models.StudentGroup{})),
+		courses.route(put, "/student-groups/:id", "Update a student group", models.StudentGroupRequest{}, Data(models.StudentGroup{})),
+		courses.route(del, "/student-groups/:id", "Delete a student group", nil, Message()),
+		courses.route(get, "/student-groups/:id/members", "Members of a student group", nil, Data([]models.Student{})),
+		courses.route(get, "/student-groups/:id/available-students", "Students that can join a student group", nil, Data([]models.Student{})),
+		courses.route(post, "/student-groups/:id/members", "Add a student to a group", models.StudentGroupMemberRequest{}, Message()),
+		courses.route(post, "/student-groups/:id/members/batch", "Add several students to a group", models.StudentGroupBatchRequest{}, Data(models.StudentGroupBatchResult{})),
+		courses.route(del, "/student-groups/:id/members/:student_id", "Remove a student from a group", nil, Message()),
+		courses.route(post, "/student-groups/:id/members/remove-batch", "Remove several students from a group", models.StudentGroupBatchRequest{}, Data(models.StudentGroupBatchResult{})),
+		courses.route(get, "/schedules", "Course schedules", nil, List(models.CourseScheduleResponse{}), listQuery(
+			queryID("academic_year_id", "Same as filter[academic_year_id]"), queryID("lecturer_id", "Same as filter[user_id]"),
+			queryID("student_group_id", "Same as filter[student_group_id]"), queryString("day", "Same as filter[day]"),
+			queryID("room_id", "Same as filter[room_id]"), queryID("building_id", "Same as filter[building_id]"),
+			queryID("course_id", "Same as filter[course_id]"))...),
+		courses.route(get, "/schedules/:id", "Course schedule", nil, Data(models.CourseScheduleResponse{})),
+		courses.route(post, "/schedules", "Create a course schedule", models.CourseScheduleRequest{}, Created(models.CourseSchedule{})),
+		courses.route(put, "/schedules/:id", "Update a course schedule", models.CourseScheduleUpdateRequest{}, Data(models.CourseSchedule{})),
+		courses.route(del, "/schedules/:id", "Delete a course schedule", nil, Message()),
+		courses.route(get, "/schedules/conflicts", "Room, lecturer and student group conflicts of an academic year", nil, Data(models.ScheduleConflictReport{}),
+			required(queryID("academic_year_id", "Academic year ID"))),
+		courses.route(put, "/schedules/conflicts/status", "Mark a schedule conflict blocking or acknowledged", models.ScheduleConflictStatusRequest{}, Data(models.ScheduleConflict{})),
+		courses.route(post, "/schedules/conflicts/check", "Check a proposed schedule slot for conflicts", models.ScheduleConflictCheckRequest{}, Data(models.ScheduleConflictCheck{})),
+		courses.route(post, "/schedules/free-slots", "Ranked free slots and rooms for a new schedule", models.ScheduleSlotSearchRequest{}, Data(models.ScheduleSlotSearchResult{})),
+		courses.route(get, "/lecturer-availability", "Windows in which lecturers cannot or would rather not teach", nil, Data([]models.LecturerAvailability{}),
+			queryID("lecturer_id", "All lecturers when omitted")),
+		courses.route(post, "/lecturer-availability", "Add a lecturer availability window", models.LecturerAvailabilityRequest{}, Created(models.LecturerAvailability{})),
+		courses.route(del, "/lecturer-availability/:id", "Delete a lecturer availability window", nil, Message()),
+		courses.route(get, "/holidays", "Holidays, on which calendar feeds cancel classes", nil, List(models.Holiday{}), listQuery()...),
+		courses.route(post, "/holidays", "Add a holiday", models.HolidayRequest{}, Created(models.Holiday{})),
+		courses.route(del, "/holidays/:id", "Delete a holiday", nil, Message()),
+		courses.route(get, "/schedules/:id/exceptions", "Cancelled and rescheduled classes of a schedule", nil, Data([]models.ScheduleException{})),
+		courses.route(post, "/schedules/:id/exceptions", "Cancel the class of a schedule on a date or move it to a replacement session", models.ScheduleExceptionRequest{}, Data(models.ScheduleException{})),
+		courses.route(del, "/schedules/exceptions/:id", "Delete a schedule exception", nil, Message()),
+		courses.route(post, "/timetable/drafts", "Generate a timetable draft for the lecturer assignments of an academic year", models.TimetableSolveRequest{}, Created(models.TimetableDraft{})),
+		courses.route(get, "/timetable/drafts", "Timetable drafts of an academic year, without their classes", nil, Data([]models.TimetableDraft{}),
+			required(queryID("academic_year_id", "Academic year ID"))),
+		courses.route(get, "/timetable/drafts/:id", "Timetable draft with its classes", nil, Data(models.TimetableDraft{})),
+		courses.route(post, "/timetable/drafts/:id/commit", "Create the course schedules of a timetable draft in one transaction", nil, Data(models.TimetableDraft{})),
+		courses.route(del, "/timetable/drafts/:id", "Discard a timetable draft that was not committed", nil, Message()),
+		courses.route(get, "/courses/assignments", "Lecturer assignments", nil, List(models.LecturerAssignmentResponse{}), listQuery(academicYearQuery)...),
+		courses.route(get, "/courses/assignments/:id", "Lecturer assignment", nil, Data(models.LecturerAssignmentResponse{})),
+		courses.route(post, "/courses/assignments", "Assign a lecturer to a course", models.LecturerAssignmentRequest{}, Data(models.LecturerAssignmentResponse{})),
+		courses.route(put, "/courses/assignments/:id", "Update a lecturer assignment", models.LecturerAssignmentUpdateRequest{}, Data(models.LecturerAssignmentResponse{})),
+		courses.route(del, "/courses/assignments/:id", "Delete a lecturer assignment", nil, Message()),
+		courses.route(get, "/courses/:id/lecturers", "Lecturers assigned to a course", nil, Data([]models.LecturerAssignment{}), academicYearQuery),
+		courses.route(get, "/lecturers/:id/courses", "Courses assigned to a lecturer", nil, Data([]models.LecturerAssignment{}), academicYearQuery),
+		courses.route(get, "/courses/:id/available-lecturers", "Lecturers that can be assigned to a course", nil, Data([]models.Lecturer{}), academicYearQuery),
+		courses.route(get, "/courses/ta-assignments", "Teaching assistant assignments", nil, List(models.TeachingAssistantAssignmentResponse{}), listQuery(academicYearQuery)...),
+		courses.route(get, "/courses/ta-assignments/:id", "Teaching assistant assignment", nil, Data(models.TeachingAssistantAssignment{})),
+		courses.route(post, "/courses/ta-assignments", "Assign a teaching assistant to a course", models.TeachingAssistantAssignmentRequest{}, Created(models.TeachingAssistantAssignment{})),
+		courses.route(del, "/courses/ta-assignments/:id", "Delete a teaching assistant assignment", nil, Message()),
+		courses.route(get, "/courses/:id/teaching-assistants", "Teaching assistants of a course", nil, Data([]models.TeachingAssistantAssignment{}), academicYearQuery),
+		courses.route(get, "/employees/:id/assigned-courses", "Courses assigned to a teaching assistant", nil, Data([]models.TeachingAssistantAssignment{}), academicYearQuery),
+		courses.route(get, "/courses/:id/available-teaching-assistants", "Employees that can assist a course", nil, Data([]models.Employee{}), academicYearQuery),
+		courses.route(get, "/course-lecturers/course/:course_id", "Lecturer assigned to a course", nil, Data(models.CourseLecturerResponse{})),
+
+		lecturer.route(get, "/assignments", "Own course assignments", nil, Data([]models.LecturerAssignment{}), academicYearQuery),
+		lecturer.route(get, "/schedules", "Own course schedules", nil, Data([]models.CourseScheduleResponse{}), academicYearQuery),
+		lecturer.route(post, "/calendar-feed", "Create the calendar feed of the own schedules, revoking the previous URL", nil, Created(models.CalendarFeedCreatedResponse{})),
+		lecturer.route(del, "/calendar-feed", "Revoke the calendar feed of the own schedules", nil, Message()),
+		lecturer.route(get, "/courses", "Own course assignments, same as /assignments", nil, Data([]models.LecturerAssignment{}), academicYearQuery),
+		lecturer.route(get, "/academic-years", "Academic years", nil, List(OneOf{models.AcademicYear{}, services.AcademicYearWithStats{}}), listQuery(statsQuery)...),
+		lecturer.route(post, "/attendance/sessions", "Open an attendance session", models.AttendanceSessionRequest{}, Raw(models.AttendanceSessionResponse{})),
+		lecturer.route(get, "/attendance/sessions/active", "Own active attendance sessions", nil, Data([]models.AttendanceSessionResponse{})),
+		lecturer.route(get, "/attendance/sessions", "Own attendance sessions in a date range", nil, List(models.AttendanceSessionResponse{}),
+			listQuery(queryDate("start_date", "Defaults to today"), queryDate("end_date", "Defaults to today"))...),
+		lecturer.route(get, "/attendance/sessions/:id", "Attendance session", nil, Raw(models.AttendanceSessionResponse{})),
+		lecturer.route(put, "/attendance/sessions/:id/close", "Close an attendance session", nil, Message()),
+		lecturer.route(put, "/attendance/sessions/:id/cancel", "Cancel an attendance session", nil, Message()),
+		lecturer.route(get, "/attendance/sessions/:id/students", "Attendance of the students of a session", nil, Raw([]models.StudentAttendanceResponse{})),
+		lecturer.route(put, "/attendance/sessions/:id/students/:studentId", "Mark the attendance of a student", models.MarkAttendanceRequest{}, Message()),
+		lecturer.route(get, "/attendance/statistics/course/:courseScheduleId", "Attendance statistics of a course schedule", nil, Raw(models.AttendanceStatistics{})),
+		lecturer.route(get, "/attendance/qrcode/:id", "QR code of an attendance session", nil, Text()),
+		lecturer.route(get, "/attendance/sessions/:id/report", "Attendance report of a session", nil, File(xlsx)),
+		lecturer.route(get, "/ta-assignments", "Teaching assistants of own courses", nil, Data([]models.TeachingAssistantAssignment{}), academicYearQuery),
+		lecturer.route(post, "/ta-assignments", "Assign a teaching assistant to an own course", models.TeachingAssistantAssignmentRequest{}, Created(models.TeachingAssistantAssignment{})),
+		lecturer.route(del, "/ta-assignments/:id", "Delete a teaching assistant assignment", nil, Message()),
+		lecturer.route(get, "/courses/:id/available-teaching-assistants", "Employees that can assist a course", nil, Data([]models.Employee{}), academicYearQuery),
+
+		employee.route(get, "/assigned-courses", "Courses assigned as teaching assistant", nil, Data([]models.TeachingAssistantAssignment{}), academicYearQuery),
+
+		assistant.route(get, "/schedules", "Schedules of assigned courses", nil, Data([]models.CourseScheduleResponse{}), academicYearQuery),
+		assistant.route(get, "/academic-years", "Academic years", nil, List(OneOf{models.AcademicYear{}, services.AcademicYearWithStats{}}), listQuery(statsQuery)...),
+		assistant.route(post, "/attendance/sessions", "Open an attendance session", models.AttendanceSessionRequest{}, Data(models.AttendanceSessionResponse{})),
+		assistant.route(get, "/attendance/sessions/active", "Own active attendance sessions", nil, Data([]models.AttendanceSessionResponse{})),
+		assistant.route(get, "/attendance/sessions", "Own attendance sessions in a date range", nil, List(models.AttendanceSessionResponse{}),
+			listQuery(queryDate("start_date", "Defaults to today"), queryDate("end_date", "Defaults to today"))...),
+		assistant.route(get, "/attendance/sessions/:id", "Attendance session", nil, Data(models.AttendanceSessionResponse{})),
+		assistant.route(put, "/attendance/sessions/:id/close", "Close an attendance session", nil, Message()),
+		assistant.route(get, "/attendance/sessions/:id/students", "Attendance of the students of a session", nil, Data([]models.StudentAttendanceResponse{})),
+		assistant.route(put, "/attendance/sessions/:id/students/:studentId", "Mark the attendance of a student", models.MarkAttendanceRequest{}, Message()),
+		assistant.route(get, "/attendance/qrcode/:id", "Where to fetch the QR code of an attendance session", nil, Data(models.AttendanceQRCodeResponse{})),
+		assistant.route(get, "/attendance/sessions/:id/report", "Attendance report of a session", nil, File(xlsx)),
+
+		student.route(get, "/schedules", "Own course schedules", nil, Data([]models.CourseScheduleResponse{}), academicYearQuery),
+		student.route(get, "/academic-years", "Academic years", nil, List(OneOf{models.AcademicYear{}, services.AcademicYearWithStats{}}), listQuery(statsQuery)...),
+		student.route(post, "/calendar-feed", "Create the calendar feed of the own schedules, revoking the previous URL", nil, Created(models.CalendarFeedCreatedResponse{})),
+		student.route(del, "/calendar-feed", "Revoke the calendar feed of the own schedules", nil, Message()),
+		student.route(get, "/courses", "Own courses", nil, Data([]models.StudentCourseResponse{}), academicYearQuery),
+		student.route(get, "/attendance/active-sessions", "Active attendance sessions of own courses", nil, Data([]models.ActiveAttendanceSessionResponse{})),
+		student.route(post, "/attendance/qr-submit", "Check in with a scanned QR code, the first check-in of a student wins", models.QRAttendanceRequest{}, Data(models.CheckInResponse{}),
+			header("Idempotency-Key", "Client-chosen key, such as a UUID, a retry with the same key and body gets the first response with Idempotent-Replayed: true")),
+		student.route(get, "/attendance/history", "Own attendance history", nil, List(models.StudentAttendanceHistoryResponse{}), listQuery()...),
+
+		public.with(tagAdminPeople).route(get, "/students/by-user-id/:user_id", "Student by campus user ID", nil, Data(models.Student{})),
+		public.route(get, "/openapi.json", "This OpenAPI document", nil, Raw(Document{})),
+	}
+}
+
+// optionalBody marks the request body of a route as optional
+func optionalBody(route Route) Route {
+	route.BodyOptional = true
+	return route
+}
@@ -0,0 +1,199 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	deletedAtType  = reflect.TypeOf(gorm.DeletedAt{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// enums lists the values of string types that are enumerations
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(models.AttendanceType("")): {
+		string(models.AttendanceTypeQRCode), string(models.AttendanceTypeFaceRecognition),
+		string(models.AttendanceTypeManual), string(models.AttendanceTypeBoth),
+	},
+	reflect.TypeOf(models.AttendanceStatus("")): {
+		string(models.AttendanceStatusActive), string(models.AttendanceStatusClosed), string(models.AttendanceStatusCanceled),
+	},
+	reflect.TypeOf(models.StudentAttendanceStatus("")): {
+		string(models.StudentAttendanceStatusPresent), string(models.StudentAttendanceStatusLate),
+		string(models.StudentAttendanceStatusAbsent), string(models.StudentAttendanceStatusExcused),
+	},
+}
+
+// OneOf documents a value that has one of several types, such as the data of routes with ?stats=true
+type OneOf []interface{}
+
+// registry generates schemas for Go types, named struct types become component schemas
+type registry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newRegistry() *registry {
+	return &registry{schemas: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+// schemaOf returns the schema of the JSON encoding of a value, nil for no value
+func (r *registry) schemaOf(v interface{}) *Schema {
+	if v == nil {
+		return nil
+	}
+	if alternatives, ok := v.(OneOf); ok {
+		s := &Schema{}
+		for _, alternative := range alternatives {
+			s.OneOf = append(s.OneOf, r.schemaOf(alternative))
+		}
+		return s
+	}
+	return r.schema(reflect.TypeOf(v))
+}
+
+// schema returns the schema of the JSON encoding of t
+func (r *registry) schema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case deletedAtType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: true}
+	case rawMessageType:
+		return &Schema{}
+	}
+	if values, ok := enums[t]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := r.schema(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		min := 0.0
+		return &Schema{Type: "integer", Minimum: &min}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.object(t)
+		}
+		return r.ref(t)
+	}
+	// Interfaces can hold any value
+	return &Schema{}
+}
+
+// ref registers a named struct type as a component schema and returns a reference to it
+func (r *registry) ref(t reflect.Type) *Schema {
+	name, ok := r.names[t]
+	if !ok {
+		name = schemaName(t)
+		r.names[t] = name
+		// Registered before its fields so that types referring to each other terminate
+		r.schemas[name] = &Schema{}
+		*r.schemas[name] = *r.object(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// schemaName is the component name of a type, models keep their name and
+// types of other packages are prefixed with the package, such as ConfigView
+func schemaName(t reflect.Type) string {
+	pkg := path.Base(t.PkgPath())
+	if pkg == "models" {
+		return t.Name()
+	}
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + t.Name()
+}
+
+// object returns the schema of a struct with its JSON fields
+func (r *registry) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.addFields(s, t)
+	return s
+}
+
+// addFields adds the JSON fields of a struct to s, fields of embedded structs are promoted like encoding/json does
+func (r *registry) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.addFields(s, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := r.schema(field.Type)
+		if strings.Contains(opts, "string") && prop.Type == "integer" {
+			prop = &Schema{Type: "string"}
+		}
+		required, oneOf := bindingRules(field.Tag.Get("binding"))
+		if required {
+			s.Required = append(s.Required, name)
+		}
+		if len(oneOf) > 0 && prop.Ref == "" {
+			prop.Enum = oneOf
+		}
+		s.Properties[name] = prop
+	}
+}
+
+// bindingRules reads the rules of a binding tag that the schema can express
+func bindingRules(tag string) (required bool, oneOf []string) {
+	for _, rule := range strings.Split(tag, ",") {
+		switch {
+		case rule == "required":
+			required = true
+		case strings.HasPrefix(rule, "oneof="):
+			oneOf = strings.Fields(strings.TrimPrefix(rule, "oneof="))
+		}
+	}
+	return required, oneOf
+}
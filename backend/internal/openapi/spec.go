@@ -0,0 +1,321 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/delpresence/backend/internal/apierror"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Version is the version of the API described by the document
+const Version = "1.0.0"
+
+// Auth is how a route authenticates requests
+type Auth int
+
+const (
+	// Public routes need no credentials
+	Public Auth = iota
+	// Bearer routes need the JWT of a signed-in user
+	Bearer
+	// APIKey routes accept an API key or an admin JWT
+	APIKey
+	// MetricsToken routes need METRICS_TOKEN as bearer token when it is set
+	MetricsToken
+)
+
+// Route documents one route registered on the router
+type Route struct {
+	Method       string
+	Path         string // Gin path, such as /api/admin/rooms/:id
+	Tag          string
+	Summary      string
+	Auth         Auth
+	Roles        []string     // Roles allowed on the route, empty for all signed-in users
+	Query        []*Parameter // Query and header parameters, path parameters are taken from Path
+	Body         interface{}  // Zero value of the JSON request body, nil for none
+	BodyOptional bool         // The body may be left out
+	Reply        Reply
+}
+
+// Reply is the success response of a route
+type Reply struct {
+	status      int
+	contentType string
+	description string
+	schema      func(r *registry) *Schema
+}
+
+// Data is the envelope {"status", "message", "data"} with v as data
+func Data(v interface{}) Reply {
+	return Reply{http.StatusOK, "application/json", "Success", func(r *registry) *Schema {
+		return envelope(r.schemaOf(v))
+	}}
+}
+
+// Created is Data for routes that answer 201 Created
+func Created(v interface{}) Reply {
+	reply := Data(v)
+	reply.status = http.StatusCreated
+	reply.description = "Created"
+	return reply
+}
+
+// List is the envelope of list endpoints, {"status", "message", "data", "meta"}, with a page of v as data
+func List(v interface{}) Reply {
+	return Reply{http.StatusOK, "application/json", "One page of results", func(r *registry) *Schema {
+		s := envelope(&Schema{Type: "array", Items: r.schemaOf(v)})
+		s.Properties["meta"] = r.schemaOf(models.PageInfo{})
+		return s
+	}}
+}
+
+// Message is the envelope without data, {"status", "message"}
+func Message() Reply {
+	return Reply{http.StatusOK, "application/json", "Success", func(r *registry) *Schema {
+		return envelope(nil)
+	}}
+}
+
+// Raw is v without an envelope
+func Raw(v interface{}) Reply {
+	return Reply{http.StatusOK, "application/json", "Success", func(r *registry) *Schema {
+		return r.schemaOf(v)
+	}}
+}
+
+// File is a download of contentType
+func File(contentType string) Reply {
+	return Reply{http.StatusOK, contentType, "File download", func(r *registry) *Schema {
+		return &Schema{Type: "string", Format: "binary"}
+	}}
+}
+
+// Text is a plain text body
+func Text() Reply {
+	return Reply{http.StatusOK, "text/plain", "Plain text", func(r *registry) *Schema {
+		return &Schema{Type: "string"}
+	}}
+}
+
+// envelope is the schema of the success envelope, without data when data is nil
+func envelope(data *Schema) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{
+		"status":  {Type: "string", Enum: []string{"success"}},
+		"message": {Type: "string"},
+	}}
+	if data != nil {
+		s.Properties["data"] = data
+	}
+	return s
+}
+
+// Spec is the OpenAPI document of the routes, with their operations indexed for validation
+type Spec struct {
+	Document   *Document
+	operations map[string]*Operation
+}
+
+var (
+	spec     *Spec
+	specOnce sync.Once
+)
+
+// Get returns the specification of the routes, built on first use
+func Get() *Spec {
+	specOnce.Do(func() {
+		spec = build(routes())
+	})
+	return spec
+}
+
+// build generates the document of a route table
+func build(table []Route) *Spec {
+	r := newRegistry()
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "DelPresence API",
+			Description: "Attendance system of Institut Teknologi Del. Errors use the error envelope, see the Error schema.",
+			Version:     Version,
+		},
+		Paths: make(map[string]*PathItem),
+		Components: Components{
+			Schemas: r.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth":   {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "Access token from /api/auth/login"},
+				"apiKey":       {Type: "apiKey", In: "header", Name: "X-API-Key", Description: "API key of a kiosk or integration, also accepted as \"Authorization: ApiKey <key>\""},
+				"metricsToken": {Type: "http", Scheme: "bearer", Description: "METRICS_TOKEN, only required when it is set"},
+			},
+		},
+	}
+	r.schemas["Error"] = errorSchema()
+
+	s := &Spec{Document: doc, operations: make(map[string]*Operation)}
+	tags := make(map[string]bool)
+	for _, route := range table {
+		op := operation(r, route)
+		path := oasPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = &PathItem{}
+		}
+		(*doc.Paths[path])[strings.ToLower(route.Method)] = op
+		s.operations[route.Method+" "+route.Path] = op
+
+		if !tags[route.Tag] {
+			tags[route.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: route.Tag})
+		}
+	}
+	return s
+}
+
+// operation generates the operation of a route
+func operation(r *registry, route Route) *Operation {
+	op := &Operation{
+		OperationID: operationID(route.Method, route.Path),
+		Summary:     route.Summary,
+		Tags:        []string{route.Tag},
+		Responses: map[string]*Response{
+			fmt.Sprint(route.Reply.status): {
+				Description: route.Reply.description,
+				Content:     map[string]*MediaType{route.Reply.contentType: {Schema: route.Reply.schema(r)}},
+			},
+			"default": {
+				Description: "Error",
+				Content:     map[string]*MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}}},
+			},
+		},
+	}
+	if len(route.Roles) > 0 {
+		op.Description = "Requires the role " + strings.Join(route.Roles, " or ") + "."
+	}
+
+	switch route.Auth {
+	case Bearer:
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+	case APIKey:
+		op.Security = []map[string][]string{{"apiKey": {}}, {"bearerAuth": {}}}
+	case MetricsToken:
+		op.Security = []map[string][]string{{}, {"metricsToken": {}}}
+	}
+
+	for _, name := range pathParams(route.Path) {
+		op.Parameters = append(op.Parameters, pathParam(name))
+	}
+	op.Parameters = append(op.Parameters, route.Query...)
+
+	if route.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: !route.BodyOptional,
+			Content:  map[string]*MediaType{"application/json": {Schema: r.schemaOf(route.Body)}},
+		}
+	}
+	return op
+}
+
+// errorSchema is the schema of the error envelope written by apierror.Respond
+func errorSchema() *Schema {
+	codes := apierror.Codes()
+	values := make([]string, len(codes))
+	for i, code := range codes {
+		values[i] = string(code)
+	}
+	return &Schema{
+		Type:        "object",
+		Description: "Error envelope, clients switch on code. The message is localized by Accept-Language (en, id).",
+		Required:    []string{"status", "code", "message"},
+		Properties: map[string]*Schema{
+			"status":      {Type: "string", Enum: []string{"error"}},
+			"code":        {Type: "string", Enum: values},
+			"message":     {Type: "string"},
+			"detail":      {Type: "string"},
+			"field":       {Type: "string", Description: "Parameter or body field that failed validation, for invalid_request"},
+			"retry_after": {Type: "integer", Description: "Seconds until the next attempt is allowed, for too_many_attempts and account_locked"},
+		},
+	}
+}
+
+// stringParams are path parameters that are not numeric IDs
+var stringParams = map[string]string{
+	"entity": "students, lecturers or employees",
+	"token":  "Calendar feed token, optionally followed by .ics",
+}
+
+// pathParam documents a path parameter, numeric IDs unless listed in stringParams
+func pathParam(name string) *Parameter {
+	if description, ok := stringParams[name]; ok {
+		return &Parameter{Name: name, In: "path", Required: true, Description: description, Schema: &Schema{Type: "string"}}
+	}
+	min := 0.0
+	return &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "integer", Minimum: &min}}
+}
+
+// pathParams returns the names of the parameters of a Gin path
+func pathParams(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") {
+			names = append(names, segment[1:])
+		}
+	}
+	return names
+}
+
+// oasPath converts a Gin path to an OpenAPI path, /rooms/:id becomes /rooms/{id}
+func oasPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID derives an ID from the method and path, GET /api/admin/rooms/:id becomes getAdminRoomsById
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/api"), "/") {
+		if strings.HasPrefix(segment, ":") {
+			b.WriteString("By")
+			segment = segment[1:]
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+// Operation returns the operation of a route by method and Gin path, nil for undocumented routes
+func (s *Spec) Operation(method, path string) *Operation {
+	return s.operations[method+" "+path]
+}
+
+// Check compares the routes of a router with the documented ones
+// It returns one line per route that is registered but not documented or documented but not registered
+func (s *Spec) Check(routes gin.RoutesInfo) []string {
+	registered := make(map[string]bool, len(routes))
+	var problems []string
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		if s.operations[key] == nil {
+			problems = append(problems, "not documented: "+key)
+		}
+	}
+	for key := range s.operations {
+		if !registered[key] {
+			problems = append(problems, "not registered: "+key)
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
@@ -0,0 +1,272 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ValidationError is a request that does not match the document
+type ValidationError struct {
+	Field  string // Parameter name, or path of the body field such as student_ids[2]
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Reason
+	}
+	return e.Field + ": " + e.Reason
+}
+
+// Request holds the parts of a request that are validated
+type Request struct {
+	PathParams map[string]string
+	Query      url.Values
+	// Body is the JSON body, nil when the body is empty or not JSON, such as a form
+	Body []byte
+	// Form is set for form bodies, whose fields are not validated
+	Form bool
+}
+
+// patterns caches the compiled patterns of the document
+var patterns sync.Map
+
+// Validate checks the parameters and JSON body of a request against its operation
+// Unknown query parameters and body fields are allowed, like the handlers do
+func (s *Spec) Validate(op *Operation, req Request) error {
+	for _, param := range op.Parameters {
+		if param.Style == "deepObject" {
+			// Filters are checked by the list handlers against their own fields
+			continue
+		}
+
+		var value string
+		var present bool
+		switch param.In {
+		case "path":
+			value, present = req.PathParams[param.Name]
+		case "query":
+			present = req.Query.Has(param.Name)
+			value = req.Query.Get(param.Name)
+		}
+		if !present || value == "" {
+			if param.Required {
+				return &ValidationError{param.Name, "is required"}
+			}
+			continue
+		}
+		if reason := s.checkParam(param.Schema, value); reason != "" {
+			return &ValidationError{param.Name, reason}
+		}
+	}
+
+	if op.RequestBody == nil || req.Form {
+		return nil
+	}
+	if len(bytes.TrimSpace(req.Body)) == 0 {
+		if op.RequestBody.Required {
+			return &ValidationError{"", "request body is required"}
+		}
+		return nil
+	}
+	media := op.RequestBody.Content["application/json"]
+	if media == nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(req.Body))
+	decoder.UseNumber()
+	var body interface{}
+	if err := decoder.Decode(&body); err != nil {
+		return &ValidationError{"", "request body is not valid JSON"}
+	}
+	if body == nil {
+		return &ValidationError{"", "request body must not be null"}
+	}
+	return s.checkValue(media.Schema, body, "")
+}
+
+// checkParam checks a path or query parameter, it returns why the value does not match or ""
+func (s *Spec) checkParam(schema *Schema, value string) string {
+	switch schema.Type {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "must be an integer"
+		}
+		return checkRange(schema, float64(n))
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "must be true or false"
+		}
+	case "string":
+		return checkString(schema, value)
+	}
+	return ""
+}
+
+// checkValue checks a decoded JSON value against a schema
+func (s *Spec) checkValue(schema *Schema, value interface{}, field string) error {
+	schema = s.resolve(schema)
+
+	if value == nil {
+		// Absent and null fields decode to the zero value, binding rules reject them when required
+		return nil
+	}
+
+	if len(schema.OneOf) > 0 {
+		for _, alternative := range schema.OneOf {
+			if s.checkValue(alternative, value, field) == nil {
+				return nil
+			}
+		}
+		return &ValidationError{field, "does not match any of the allowed shapes"}
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return &ValidationError{field, "must be an object"}
+		}
+		for _, name := range schema.Required {
+			if v, ok := object[name]; !ok || v == nil {
+				return &ValidationError{join(field, name), "is required"}
+			}
+		}
+		for name, v := range object {
+			prop := schema.Properties[name]
+			if prop == nil {
+				prop = schema.AdditionalProperties
+			}
+			if prop == nil {
+				continue
+			}
+			if err := s.checkValue(prop, v, join(field, name)); err != nil {
+				return err
+			}
+		}
+	case "array":
+		if schema.Format == "byte" {
+			break
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			return &ValidationError{field, "must be an array"}
+		}
+		for i, item := range items {
+			if err := s.checkValue(schema.Items, item, fmt.Sprintf("%s[%d]", field, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return &ValidationError{field, "must be a string"}
+		}
+		if reason := checkString(schema, str); reason != "" {
+			return &ValidationError{field, reason}
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return &ValidationError{field, "must be an integer"}
+		}
+		n, err := number.Int64()
+		if err != nil {
+			return &ValidationError{field, "must be an integer"}
+		}
+		if reason := checkRange(schema, float64(n)); reason != "" {
+			return &ValidationError{field, reason}
+		}
+	case "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return &ValidationError{field, "must be a number"}
+		}
+		n, err := number.Float64()
+		if err != nil {
+			return &ValidationError{field, "must be a number"}
+		}
+		if reason := checkRange(schema, n); reason != "" {
+			return &ValidationError{field, reason}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return &ValidationError{field, "must be true or false"}
+		}
+	}
+	return nil
+}
+
+// resolve follows a reference to a component schema
+func (s *Spec) resolve(schema *Schema) *Schema {
+	for schema.Ref != "" {
+		schema = s.Document.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+// checkString checks the enum, format and pattern of a string
+func checkString(schema *Schema, value string) string {
+	if len(schema.Enum) > 0 {
+		allowed := false
+		for _, v := range schema.Enum {
+			if v == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return "must be one of " + strings.Join(schema.Enum, ", ")
+		}
+	}
+
+	switch schema.Format {
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "must be a date as YYYY-MM-DD"
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return "must be a date-time as RFC 3339"
+		}
+	}
+
+	if schema.Pattern != "" {
+		compiled, ok := patterns.Load(schema.Pattern)
+		if !ok {
+			compiled, _ = patterns.LoadOrStore(schema.Pattern, regexp.MustCompile(schema.Pattern))
+		}
+		if !compiled.(*regexp.Regexp).MatchString(value) {
+			return "must match " + schema.Pattern
+		}
+	}
+	return ""
+}
+
+// checkRange checks the minimum and maximum of a number
+func checkRange(schema *Schema, n float64) string {
+	if schema.Minimum != nil && n < *schema.Minimum {
+		return "must be at least " + strconv.FormatFloat(*schema.Minimum, 'f', -1, 64)
+	}
+	if schema.Maximum != nil && n > *schema.Maximum {
+		return "must be at most " + strconv.FormatFloat(*schema.Maximum, 'f', -1, 64)
+	}
+	return ""
+}
+
+// join appends a property name to the path of a body field
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
@@ -20,6 +20,24 @@ func NewAcademicYearRepository() *AcademicYearRepository {
 	}
 }
 
+// academicYearListSpec lists academic years, latest first by default, see ListSpec
+var academicYearListSpec = ListSpec[models.AcademicYear]{
+	Fields: map[string]ListField[models.AcademicYear]{
+		"id":         {Column: "academic_years.id", Value: func(y models.AcademicYear) interface{} { return y.ID }},
+		"name":       {Column: "academic_years.name", Value: func(y models.AcademicYear) interface{} { return y.Name }},
+		"semester":   {Column: "academic_years.semester", Value: func(y models.AcademicYear) interface{} { return y.Semester }},
+		"start_date": {Column: "academic_years.start_date", Value: func(y models.AcademicYear) interface{} { return y.StartDate }},
+		"end_date":   {Column: "academic_years.end_date", Value: func(y models.AcademicYear) interface{} { return y.EndDate }},
+		"created_at": {Column: "academic_years.created_at", Value: func(y models.AcademicYear) interface{} { return y.CreatedAt }},
+	},
+	DefaultSort: []models.ListSort{{Field: "start_date", Desc: true}},
+}
+
+// List returns one page of academic years
+func (r *AcademicYearRepository) List(q models.ListQuery) (models.ListResult[models.AcademicYear], error) {
+	return listRecords(r.db, academicYearListSpec, q)
+}
+
 // Create creates a new academic year
 func (r *AcademicYearRepository) Create(academicYear *models.AcademicYear) error {
 	return r.db.Create(academicYear).Error
@@ -202,4 +220,25 @@ func (r *AcademicYearRepository) CheckNameExists(name string, excludeID uint) (b
 	}
 	
 	return count > 0, nil
-} 
\ No newline at end of file
+}
+
+// CountCourses counts the courses of an academic year
+func (r *AcademicYearRepository) CountCourses(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Course{}).Where("academic_year_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// CountLecturerAssignments counts the lecturer assignments of an academic year
+func (r *AcademicYearRepository) CountLecturerAssignments(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.LecturerAssignment{}).Where("academic_year_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// CountCourseSchedules counts the course schedules of an academic year
+func (r *AcademicYearRepository) CountCourseSchedules(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.CourseSchedule{}).Where("academic_year_id = ?", id).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository handles database operations for API keys
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		db: database.GetDB(),
+	}
+}
+
+// apiKeyListSpec lists API keys, newest first by default, see ListSpec
+var apiKeyListSpec = ListSpec[models.APIKey]{
+	Fields: map[string]ListField[models.APIKey]{
+		"id":            {Column: "api_keys.id", Value: func(k models.APIKey) interface{} { return k.ID }},
+		"name":          {Column: "api_keys.name", Value: func(k models.APIKey) interface{} { return k.Name }},
+		"prefix":        {Column: "api_keys.prefix", Value: func(k models.APIKey) interface{} { return k.Prefix }},
+		"last_used_ip":  {Column: "api_keys.last_used_ip", Value: func(k models.APIKey) interface{} { return k.LastUsedIP }},
+		"created_by_id": {Column: "api_keys.created_by_id", Value: func(k models.APIKey) interface{} { return k.CreatedByID }},
+		"created_at":    {Column: "api_keys.created_at", Value: func(k models.APIKey) interface{} { return k.CreatedAt }},
+	},
+	DefaultSort: []models.ListSort{{Field: "created_at", Desc: true}},
+}
+
+// List returns one page of API keys
+func (r *APIKeyRepository) List(q models.ListQuery) (models.ListResult[models.APIKey], error) {
+	return listRecords(r.db, apiKeyListSpec, q)
+}
+
+// Create creates a new API key
+func (r *APIKeyRepository) Create(key *models.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+// Update updates an existing API key
+func (r *APIKeyRepository) Update(key *models.APIKey) error {
+	return r.db.Save(key).Error
+}
+
+// FindAll returns all API keys, newest first
+func (r *APIKeyRepository) FindAll() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// FindByID returns an API key by ID, or nil if there is none
+func (r *APIKeyRepository) FindByID(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.First(&key, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByPrefix returns an API key by its public prefix, or nil if there is none
+func (r *APIKeyRepository) FindByPrefix(prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// TouchLastUsed records when and from where a key was last used
+func (r *APIKeyRepository) TouchLastUsed(id uint, usedAt time.Time, ip string) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": usedAt, "last_used_ip": ip}).Error
+}
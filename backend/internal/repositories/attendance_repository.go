@@ -1,12 +1,14 @@
 package repositories
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
 	"time"
 
 	"github.com/delpresence/backend/internal/database"
 	"github.com/delpresence/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AttendanceRepository handles database operations for attendance
@@ -21,6 +23,11 @@ func NewAttendanceRepository() *AttendanceRepository {
 	}
 }
 
+// WithContext returns a copy of the repository whose queries carry ctx, so SQL logs show its request ID
+func (r *AttendanceRepository) WithContext(ctx context.Context) AttendanceStore {
+	return &AttendanceRepository{db: r.db.WithContext(ctx)}
+}
+
 // CreateAttendanceSession creates a new attendance session
 func (r *AttendanceRepository) CreateAttendanceSession(session *models.AttendanceSession) error {
 	return r.db.Create(session).Error
@@ -48,14 +55,33 @@ func (r *AttendanceRepository) ListActiveSessions(lecturerID uint) ([]models.Att
 	return sessions, err
 }
 
-// ListSessionsByDateRange lists attendance sessions for a lecturer within a date range
-func (r *AttendanceRepository) ListSessionsByDateRange(lecturerID uint, startDate, endDate time.Time) ([]models.AttendanceSession, error) {
-	var sessions []models.AttendanceSession
-	err := r.db.Preload("CourseSchedule").Preload("CourseSchedule.Course").Preload("CourseSchedule.Room").
-		Where("lecturer_id = ? AND date BETWEEN ? AND ?", lecturerID, startDate, endDate).
-		Order("date DESC, start_time DESC").
-		Find(&sessions).Error
-	return sessions, err
+// attendanceSessionListSpec lists attendance sessions with their course schedule, latest first by default
+var attendanceSessionListSpec = ListSpec[models.AttendanceSession]{
+	Fields: map[string]ListField[models.AttendanceSession]{
+		"id":                 {Column: "attendance_sessions.id", Value: func(s models.AttendanceSession) interface{} { return s.ID }},
+		"course_schedule_id": {Column: "attendance_sessions.course_schedule_id", Value: func(s models.AttendanceSession) interface{} { return s.CourseScheduleID }},
+		"lecturer_id":        {Column: "attendance_sessions.lecturer_id", Value: func(s models.AttendanceSession) interface{} { return s.LecturerID }},
+		"creator_role":       {Column: "attendance_sessions.creator_role", Value: func(s models.AttendanceSession) interface{} { return s.CreatorRole }},
+		"date":               {Column: "attendance_sessions.date", Value: func(s models.AttendanceSession) interface{} { return s.Date }},
+		"start_time":         {Column: "attendance_sessions.start_time", Value: func(s models.AttendanceSession) interface{} { return s.StartTime }},
+		"type":               {Column: "attendance_sessions.type", Value: func(s models.AttendanceSession) interface{} { return string(s.Type) }},
+		"status":             {Column: "attendance_sessions.status", Value: func(s models.AttendanceSession) interface{} { return string(s.Status) }},
+		"created_at":         {Column: "attendance_sessions.created_at", Value: func(s models.AttendanceSession) interface{} { return s.CreatedAt }},
+	},
+	Preloads:    []string{"CourseSchedule", "CourseSchedule.Course", "CourseSchedule.Room"},
+	DefaultSort: []models.ListSort{{Field: "date", Desc: true}, {Field: "start_time", Desc: true}},
+}
+
+// ListSessionsForUser returns one page of the sessions within a date range that a user started as
+// lecturer or that belong to the given course schedules, the ones the user assists in
+func (r *AttendanceRepository) ListSessionsForUser(lecturerID uint, scheduleIDs []uint, startDate, endDate time.Time, q models.ListQuery) (models.ListResult[models.AttendanceSession], error) {
+	query := r.db.Where("attendance_sessions.date BETWEEN ? AND ?", startDate, endDate)
+	if len(scheduleIDs) > 0 {
+		query = query.Where("(attendance_sessions.lecturer_id = ? OR attendance_sessions.course_schedule_id IN ?)", lecturerID, scheduleIDs)
+	} else {
+		query = query.Where("attendance_sessions.lecturer_id = ?", lecturerID)
+	}
+	return listRecords(query, attendanceSessionListSpec, q)
 }
 
 // ListSessionsByCourseSchedule lists attendance sessions for a specific course schedule
@@ -86,6 +112,42 @@ func (r *AttendanceRepository) GetActiveSessionsForSchedule(courseScheduleID uin
 	return sessions, err
 }
 
+// ListActiveSessionsByRoom gets all active attendance sessions held in a room
+func (r *AttendanceRepository) ListActiveSessionsByRoom(roomID uint) ([]models.AttendanceSession, error) {
+	var sessions []models.AttendanceSession
+	err := r.db.Preload("CourseSchedule").Preload("CourseSchedule.Course").Preload("CourseSchedule.Room").
+		Joins("JOIN course_schedules ON course_schedules.id = attendance_sessions.course_schedule_id").
+		Where("course_schedules.room_id = ? AND attendance_sessions.status = ?", roomID, models.AttendanceStatusActive).
+		Order("attendance_sessions.start_time DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// ListActiveSessionsStartedBefore lists active attendance sessions that started before the given time, oldest first
+func (r *AttendanceRepository) ListActiveSessionsStartedBefore(before time.Time) ([]models.AttendanceSession, error) {
+	var sessions []models.AttendanceSession
+	err := r.db.Where("status = ? AND start_time < ?", models.AttendanceStatusActive, before).
+		Order("start_time").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// CountActiveSessions counts the attendance sessions that are currently active
+func (r *AttendanceRepository) CountActiveSessions() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.AttendanceSession{}).
+		Where("status = ?", models.AttendanceStatusActive).
+		Count(&count).Error
+	return count, err
+}
+
+// CloseSessions closes the given attendance sessions if they are still active
+func (r *AttendanceRepository) CloseSessions(ids []uint, endTime time.Time) error {
+	return r.db.Model(&models.AttendanceSession{}).
+		Where("id IN ? AND status = ?", ids, models.AttendanceStatusActive).
+		Updates(map[string]interface{}{"status": models.AttendanceStatusClosed, "end_time": endTime}).Error
+}
+
 // CreateStudentAttendance records a student's attendance
 func (r *AttendanceRepository) CreateStudentAttendance(attendance *models.StudentAttendance) error {
 	return r.db.Create(attendance).Error
@@ -104,6 +166,57 @@ func (r *AttendanceRepository) GetStudentAttendance(sessionID, studentID uint) (
 	return &attendance, err
 }
 
+// CheckInStudent records the check-in of a student in a session, the first check-in wins
+// The record is created when the student has none, or filled in when it has no check-in yet, such
+// as the ABSENT records created with the session. Concurrent check-ins of the same student are
+// serialized by the unique index on the session and student. It returns the stored record and
+// whether this check-in is the one that was recorded.
+func (r *AttendanceRepository) CheckInStudent(attendance *models.StudentAttendance) (*models.StudentAttendance, bool, error) {
+	var stored models.StudentAttendance
+	recorded := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		created := tx.Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "attendance_session_id"}, {Name: "student_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+			DoNothing:   true,
+		}).Create(attendance)
+		if created.Error != nil {
+			return created.Error
+		}
+		recorded = created.RowsAffected == 1
+
+		if !recorded {
+			updated := tx.Model(&models.StudentAttendance{}).
+				Where("attendance_session_id = ? AND student_id = ? AND check_in_time IS NULL", attendance.AttendanceSessionID, attendance.StudentID).
+				Updates(map[string]interface{}{
+					"status":              attendance.Status,
+					"verification_method": attendance.VerificationMethod,
+					"check_in_time":       attendance.CheckInTime,
+					"notes":               attendance.Notes,
+					"verified_by_id":      attendance.VerifiedByID,
+				})
+			if updated.Error != nil {
+				return updated.Error
+			}
+			recorded = updated.RowsAffected == 1
+		}
+
+		return tx.Where("attendance_session_id = ? AND student_id = ?", attendance.AttendanceSessionID, attendance.StudentID).
+			First(&stored).Error
+	})
+	return &stored, recorded, err
+}
+
+// SessionBelongsToSchedule checks if an attendance session was opened for a course schedule
+func (r *AttendanceRepository) SessionBelongsToSchedule(sessionID, courseScheduleID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.AttendanceSession{}).
+		Where("id = ? AND course_schedule_id = ?", sessionID, courseScheduleID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // ListStudentAttendances lists all student attendance records for a session
 func (r *AttendanceRepository) ListStudentAttendances(sessionID uint) ([]models.StudentAttendance, error) {
 	var attendances []models.StudentAttendance
@@ -113,6 +226,49 @@ func (r *AttendanceRepository) ListStudentAttendances(sessionID uint) ([]models.
 	return attendances, err
 }
 
+// ListStudentAttendancesByStudent lists all attendance records of a student with their sessions, latest first
+func (r *AttendanceRepository) ListStudentAttendancesByStudent(studentID uint) ([]models.StudentAttendance, error) {
+	var attendances []models.StudentAttendance
+	err := r.db.Preload("AttendanceSession").
+		Preload("AttendanceSession.CourseSchedule").
+		Preload("AttendanceSession.CourseSchedule.Course").
+		Preload("AttendanceSession.CourseSchedule.Room").
+		Preload("AttendanceSession.CourseSchedule.Room.Building").
+		Preload("Student").
+		Where("student_id = ?", studentID).
+		Order("attendance_session_id DESC").
+		Find(&attendances).Error
+	return attendances, err
+}
+
+// studentAttendanceHistoryListSpec lists the attendance records of a student with their session,
+// latest session first by default. Records of deleted sessions are left out.
+var studentAttendanceHistoryListSpec = ListSpec[models.StudentAttendance]{
+	Fields: map[string]ListField[models.StudentAttendance]{
+		"id":                    {Column: "student_attendances.id", Value: func(a models.StudentAttendance) interface{} { return a.ID }},
+		"attendance_session_id": {Column: "student_attendances.attendance_session_id", Value: func(a models.StudentAttendance) interface{} { return a.AttendanceSessionID }},
+		"course_schedule_id":    {Column: "attendance_sessions.course_schedule_id", Value: func(a models.StudentAttendance) interface{} { return a.AttendanceSession.CourseScheduleID }},
+		"date":                  {Column: "attendance_sessions.date", Value: func(a models.StudentAttendance) interface{} { return a.AttendanceSession.Date }},
+		"status":                {Column: "student_attendances.status", Value: func(a models.StudentAttendance) interface{} { return string(a.Status) }},
+		"verification_method":   {Column: "student_attendances.verification_method", Value: func(a models.StudentAttendance) interface{} { return a.VerificationMethod }},
+		"created_at":            {Column: "student_attendances.created_at", Value: func(a models.StudentAttendance) interface{} { return a.CreatedAt }},
+	},
+	Joins: []string{"JOIN attendance_sessions ON attendance_sessions.id = student_attendances.attendance_session_id AND attendance_sessions.deleted_at IS NULL"},
+	Preloads: []string{
+		"AttendanceSession",
+		"AttendanceSession.CourseSchedule",
+		"AttendanceSession.CourseSchedule.Course",
+		"AttendanceSession.CourseSchedule.Room",
+		"AttendanceSession.CourseSchedule.Room.Building",
+	},
+	DefaultSort: []models.ListSort{{Field: "date", Desc: true}, {Field: "attendance_session_id", Desc: true}},
+}
+
+// ListStudentAttendanceHistory returns one page of the attendance records of a student
+func (r *AttendanceRepository) ListStudentAttendanceHistory(studentID uint, q models.ListQuery) (models.ListResult[models.StudentAttendance], error) {
+	return listRecords(r.db.Where("student_attendances.student_id = ?", studentID), studentAttendanceHistoryListSpec, q)
+}
+
 // ListStudentAttendancesByStatus lists all student attendance records for a session filtered by status
 func (r *AttendanceRepository) ListStudentAttendancesByStatus(sessionID uint, status models.StudentAttendanceStatus) ([]models.StudentAttendance, error) {
 	var attendances []models.StudentAttendance
@@ -200,12 +356,9 @@ func (r *AttendanceRepository) GetAttendanceStats(courseScheduleID uint) (*model
 func (r *AttendanceRepository) ListActiveSessionsBySchedules(scheduleIDs []uint) ([]models.AttendanceSession, error) {
 	// Handle empty schedule IDs
 	if len(scheduleIDs) == 0 {
-		fmt.Printf("No schedule IDs provided for active sessions query\n")
 		return []models.AttendanceSession{}, nil
 	}
 
-	fmt.Printf("Querying active sessions for schedule IDs: %v\n", scheduleIDs)
-
 	var sessions []models.AttendanceSession
 	query := r.db.Preload("CourseSchedule").
 		Preload("CourseSchedule.Course").
@@ -215,21 +368,10 @@ func (r *AttendanceRepository) ListActiveSessionsBySchedules(scheduleIDs []uint)
 
 	// Execute the query
 	err := query.Find(&sessions).Error
-
-	// Log the result
 	if err != nil {
-		fmt.Printf("Error querying active sessions: %v\n", err)
 		return []models.AttendanceSession{}, err
 	}
 
-	fmt.Printf("Found %d active sessions for schedule IDs: %v\n", len(sessions), scheduleIDs)
-
-	// Log details of found sessions for debugging
-	for i, session := range sessions {
-		fmt.Printf("Session %d: ID=%d, ScheduleID=%d, CourseID=%d, Type=%s, Status=%s\n",
-			i+1, session.ID, session.CourseScheduleID,
-			session.CourseSchedule.CourseID, session.Type, session.Status)
-	}
-
+	slog.DebugContext(r.db.Statement.Context, "Found active sessions", "count", len(sessions), "schedule_ids", scheduleIDs)
 	return sessions, nil
 }
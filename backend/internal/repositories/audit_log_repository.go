@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository handles database operations for audit logs
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository() *AuditLogRepository {
+	return &AuditLogRepository{
+		db: database.GetDB(),
+	}
+}
+
+// auditLogListSpec lists audit log entries, newest first by default, see ListSpec
+var auditLogListSpec = ListSpec[models.AuditLog]{
+	Fields: map[string]ListField[models.AuditLog]{
+		"id":             {Column: "audit_logs.id", Value: func(e models.AuditLog) interface{} { return e.ID }},
+		"action":         {Column: "audit_logs.action", Value: func(e models.AuditLog) interface{} { return e.Action }},
+		"actor_user_id":  {Column: "audit_logs.actor_user_id", Value: func(e models.AuditLog) interface{} { return e.ActorUserID }},
+		"actor_username": {Column: "audit_logs.actor_username", Value: func(e models.AuditLog) interface{} { return e.ActorUsername }},
+		"actor_role":     {Column: "audit_logs.actor_role", Value: func(e models.AuditLog) interface{} { return e.ActorRole }},
+		"ip_address":     {Column: "audit_logs.ip_address", Value: func(e models.AuditLog) interface{} { return e.IPAddress }},
+		"method":         {Column: "audit_logs.method", Value: func(e models.AuditLog) interface{} { return e.Method }},
+		"path":           {Column: "audit_logs.path", Value: func(e models.AuditLog) interface{} { return e.Path }},
+		"status_code":    {Column: "audit_logs.status_code", Value: func(e models.AuditLog) interface{} { return e.StatusCode }},
+		"created_at":     {Column: "audit_logs.created_at", Value: func(e models.AuditLog) interface{} { return e.CreatedAt }},
+	},
+	DefaultSort: []models.ListSort{{Field: "created_at", Desc: true}},
+}
+
+// List returns one page of audit log entries
+func (r *AuditLogRepository) List(q models.ListQuery) (models.ListResult[models.AuditLog], error) {
+	return listRecords(r.db, auditLogListSpec, q)
+}
+
+// Create stores a new audit log entry
+func (r *AuditLogRepository) Create(entry *models.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
@@ -18,6 +18,23 @@ func NewBuildingRepository() *BuildingRepository {
 	}
 }
 
+// buildingListSpec lists buildings, see ListSpec
+var buildingListSpec = ListSpec[models.Building]{
+	Fields: map[string]ListField[models.Building]{
+		"id":         {Column: "buildings.id", Value: func(b models.Building) interface{} { return b.ID }},
+		"code":       {Column: "buildings.code", Value: func(b models.Building) interface{} { return b.Code }},
+		"name":       {Column: "buildings.name", Value: func(b models.Building) interface{} { return b.Name }},
+		"floors":     {Column: "buildings.floors", Value: func(b models.Building) interface{} { return b.Floors }},
+		"created_at": {Column: "buildings.created_at", Value: func(b models.Building) interface{} { return b.CreatedAt }},
+	},
+	DefaultSort: []models.ListSort{{Field: "code"}},
+}
+
+// List returns one page of buildings
+func (r *BuildingRepository) List(q models.ListQuery) (models.ListResult[models.Building], error) {
+	return listRecords(r.db, buildingListSpec, q)
+}
+
 // Create creates a new building
 func (r *BuildingRepository) Create(building *models.Building) error {
 	return r.db.Create(building).Error
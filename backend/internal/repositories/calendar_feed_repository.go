@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// CalendarFeedRepository handles database operations for calendar feeds
+type CalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository creates a new calendar feed repository
+func NewCalendarFeedRepository() *CalendarFeedRepository {
+	return &CalendarFeedRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create stores a new calendar feed
+func (r *CalendarFeedRepository) Create(feed *models.CalendarFeed) error {
+	return r.db.Create(feed).Error
+}
+
+// FindByID returns a calendar feed by ID, or nil if there is none
+func (r *CalendarFeedRepository) FindByID(id uint) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.First(&feed, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// FindActiveByTokenHash returns the feed that is not revoked with a token hash, or nil if there is none
+func (r *CalendarFeedRepository) FindActiveByTokenHash(tokenHash string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.Where("token_hash = ? AND revoked_at IS NULL", tokenHash).First(&feed).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// FindActive returns the feeds of a student, lecturer or room that are not revoked, newest first
+func (r *CalendarFeedRepository) FindActive(kind models.CalendarFeedKind, subjectID uint) ([]models.CalendarFeed, error) {
+	var feeds []models.CalendarFeed
+	err := r.db.Where("kind = ? AND subject_id = ? AND revoked_at IS NULL", kind, subjectID).
+		Order("created_at DESC").Find(&feeds).Error
+	return feeds, err
+}
+
+// Revoke revokes a feed
+func (r *CalendarFeedRepository) Revoke(id uint, revokedAt time.Time) error {
+	return r.db.Model(&models.CalendarFeed{}).Where("id = ?", id).Update("revoked_at", revokedAt).Error
+}
+
+// RevokeSubject revokes every feed of a student, lecturer or room
+func (r *CalendarFeedRepository) RevokeSubject(kind models.CalendarFeedKind, subjectID uint, revokedAt time.Time) error {
+	return r.db.Model(&models.CalendarFeed{}).
+		Where("kind = ? AND subject_id = ? AND revoked_at IS NULL", kind, subjectID).
+		Update("revoked_at", revokedAt).Error
+}
+
+// TouchLastAccessed records when a feed was last fetched
+func (r *CalendarFeedRepository) TouchLastAccessed(id uint, accessedAt time.Time) error {
+	return r.db.Model(&models.CalendarFeed{}).Where("id = ?", id).
+		UpdateColumn("last_accessed_at", accessedAt).Error
+}
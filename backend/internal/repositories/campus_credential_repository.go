@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CampusCredentialRepository handles database operations for cached campus credentials
+type CampusCredentialRepository struct {
+	db *gorm.DB
+}
+
+// NewCampusCredentialRepository creates a new campus credential repository
+func NewCampusCredentialRepository() *CampusCredentialRepository {
+	return &CampusCredentialRepository{
+		db: database.GetDB(),
+	}
+}
+
+// FindByUsername returns the cached credential of a username, or nil if there is none
+func (r *CampusCredentialRepository) FindByUsername(username string) (*models.CampusCredential, error) {
+	var credential models.CampusCredential
+	result := r.db.Where("username = ?", strings.ToLower(username)).First(&credential)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &credential, nil
+}
+
+// Save creates or updates the cached credential of a campus user
+func (r *CampusCredentialRepository) Save(credential *models.CampusCredential) error {
+	credential.Username = strings.ToLower(credential.Username)
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "external_user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"username", "email", "role", "verifier", "verified_at", "updated_at"}),
+	}).Create(credential).Error
+}
+
+// DeleteByUsername removes the cached credential of a username
+func (r *CampusCredentialRepository) DeleteByUsername(username string) error {
+	return r.db.Where("username = ?", strings.ToLower(username)).Delete(&models.CampusCredential{}).Error
+}
+
+// DeleteByExternalUserID removes the cached credential of a campus user
+func (r *CampusCredentialRepository) DeleteByExternalUserID(externalUserID int) error {
+	return r.db.Where("external_user_id = ?", externalUserID).Delete(&models.CampusCredential{}).Error
+}
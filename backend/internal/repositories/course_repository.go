@@ -18,6 +18,29 @@ func NewCourseRepository() *CourseRepository {
 	}
 }
 
+// courseListSpec lists courses with their department, faculty and academic year, see ListSpec
+var courseListSpec = ListSpec[models.Course]{
+	Fields: map[string]ListField[models.Course]{
+		"id":               {Column: "courses.id", Value: func(c models.Course) interface{} { return c.ID }},
+		"code":             {Column: "courses.code", Value: func(c models.Course) interface{} { return c.Code }},
+		"name":             {Column: "courses.name", Value: func(c models.Course) interface{} { return c.Name }},
+		"credits":          {Column: "courses.credits", Value: func(c models.Course) interface{} { return c.Credits }},
+		"semester":         {Column: "courses.semester", Value: func(c models.Course) interface{} { return c.Semester }},
+		"department_id":    {Column: "courses.department_id", Value: func(c models.Course) interface{} { return c.DepartmentID }},
+		"faculty_id":       {Column: "courses.faculty_id", Value: func(c models.Course) interface{} { return c.FacultyID }},
+		"course_type":      {Column: "courses.course_type", Value: func(c models.Course) interface{} { return c.CourseType }},
+		"academic_year_id": {Column: "courses.academic_year_id", Value: func(c models.Course) interface{} { return c.AcademicYearID }},
+		"created_at":       {Column: "courses.created_at", Value: func(c models.Course) interface{} { return c.CreatedAt }},
+	},
+	Preloads:    []string{"Department", "Faculty", "AcademicYear"},
+	DefaultSort: []models.ListSort{{Field: "code"}},
+}
+
+// List returns one page of courses with their relations
+func (r *CourseRepository) List(q models.ListQuery) (models.ListResult[models.Course], error) {
+	return listRecords(r.db, courseListSpec, q)
+}
+
 // GetAll returns all courses
 func (r *CourseRepository) GetAll() ([]models.Course, error) {
 	var courses []models.Course
@@ -1,7 +1,8 @@
 package repositories
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
 
 	"github.com/delpresence/backend/internal/database"
 	"github.com/delpresence/backend/internal/models"
@@ -20,6 +21,11 @@ func NewCourseScheduleRepository() *CourseScheduleRepository {
 	}
 }
 
+// WithContext returns a copy of the repository whose queries carry ctx, so SQL logs show its request ID
+func (r *CourseScheduleRepository) WithContext(ctx context.Context) CourseScheduleStore {
+	return &CourseScheduleRepository{db: r.db.WithContext(ctx)}
+}
+
 // GetAll returns all course schedules
 func (r *CourseScheduleRepository) GetAll() ([]models.CourseSchedule, error) {
 	var schedules []models.CourseSchedule
@@ -34,6 +40,33 @@ func (r *CourseScheduleRepository) GetAll() ([]models.CourseSchedule, error) {
 	return schedules, err
 }
 
+// courseScheduleListSpec lists course schedules with their relations, see ListSpec
+// The lecturer is the "user_id" field, like in the JSON of a schedule
+var courseScheduleListSpec = ListSpec[models.CourseSchedule]{
+	Fields: map[string]ListField[models.CourseSchedule]{
+		"id":               {Column: "course_schedules.id", Value: func(s models.CourseSchedule) interface{} { return s.ID }},
+		"course_id":        {Column: "course_schedules.course_id", Value: func(s models.CourseSchedule) interface{} { return s.CourseID }},
+		"room_id":          {Column: "course_schedules.room_id", Value: func(s models.CourseSchedule) interface{} { return s.RoomID }},
+		"building_id":      {Column: "rooms.building_id", Value: func(s models.CourseSchedule) interface{} { return s.Room.BuildingID }},
+		"user_id":          {Column: "course_schedules.lecturer_id", Value: func(s models.CourseSchedule) interface{} { return s.UserID }},
+		"student_group_id": {Column: "course_schedules.student_group_id", Value: func(s models.CourseSchedule) interface{} { return s.StudentGroupID }},
+		"academic_year_id": {Column: "course_schedules.academic_year_id", Value: func(s models.CourseSchedule) interface{} { return s.AcademicYearID }},
+		"day":              {Column: "course_schedules.day", Value: func(s models.CourseSchedule) interface{} { return s.Day }},
+		"start_time":       {Column: "course_schedules.start_time", Value: func(s models.CourseSchedule) interface{} { return s.StartTime }},
+		"end_time":         {Column: "course_schedules.end_time", Value: func(s models.CourseSchedule) interface{} { return s.EndTime }},
+		"capacity":         {Column: "course_schedules.capacity", Value: func(s models.CourseSchedule) interface{} { return s.Capacity }},
+		"enrolled":         {Column: "course_schedules.enrolled", Value: func(s models.CourseSchedule) interface{} { return s.Enrolled }},
+		"created_at":       {Column: "course_schedules.created_at", Value: func(s models.CourseSchedule) interface{} { return s.CreatedAt }},
+	},
+	Joins:    []string{"LEFT JOIN rooms ON rooms.id = course_schedules.room_id"},
+	Preloads: []string{"Course", "Room", "Room.Building", "Lecturer", "StudentGroup", "AcademicYear"},
+}
+
+// List returns one page of course schedules with their relations
+func (r *CourseScheduleRepository) List(q models.ListQuery) (models.ListResult[models.CourseSchedule], error) {
+	return listRecords(r.db, courseScheduleListSpec, q)
+}
+
 // GetByID returns a course schedule by its ID
 func (r *CourseScheduleRepository) GetByID(id uint) (models.CourseSchedule, error) {
 	var schedule models.CourseSchedule
@@ -56,9 +89,6 @@ func (r *CourseScheduleRepository) Create(schedule models.CourseSchedule) (model
 
 // Update updates an existing course schedule
 func (r *CourseScheduleRepository) Update(schedule models.CourseSchedule) (models.CourseSchedule, error) {
-	// First, log the changes for debugging
-	fmt.Printf("Updating schedule ID=%d: student_group_id=%d\n", schedule.ID, schedule.StudentGroupID)
-
 	// Use a more explicit update method to ensure student_group_id gets updated
 	tx := r.db.Begin()
 
@@ -98,9 +128,6 @@ func (r *CourseScheduleRepository) Update(schedule models.CourseSchedule) (model
 		Preload("AcademicYear").
 		First(&updatedSchedule, schedule.ID).Error
 
-	// Log the updated schedule for debugging
-	fmt.Printf("After update, schedule ID=%d: student_group_id=%d\n", updatedSchedule.ID, updatedSchedule.StudentGroupID)
-
 	return updatedSchedule, err
 }
 
@@ -141,7 +168,7 @@ func (r *CourseScheduleRepository) GetByLecturer(userID uint) ([]models.CourseSc
 
 	if err != nil || len(schedules) == 0 {
 		// Try another approach - find assignments for this lecturer and then find schedules
-		fmt.Printf("No schedules found directly for lecturer_id=%d, trying assignments\n", userID)
+		slog.DebugContext(r.db.Statement.Context, "No schedules found for lecturer, trying assignments", "lecturer_id", userID)
 
 		// Get lecturer record
 		lecturerRepo := NewLecturerRepository()
@@ -198,7 +225,7 @@ func (r *CourseScheduleRepository) GetByLecturerAndAcademicYear(userID uint, aca
 
 	if err != nil || len(schedules) == 0 {
 		// Try another approach - find assignments for this lecturer and then find schedules
-		fmt.Printf("No schedules found directly for lecturer_id=%d and academic_year_id=%d, trying assignments\n", userID, academicYearID)
+		slog.DebugContext(r.db.Statement.Context, "No schedules found for lecturer, trying assignments", "lecturer_id", userID, "academic_year_id", academicYearID)
 
 		// Get lecturer record
 		lecturerRepo := NewLecturerRepository()
@@ -314,10 +341,10 @@ func (r *CourseScheduleRepository) GetByDay(day string) ([]models.CourseSchedule
 	return schedules, err
 }
 
-// CheckScheduleConflict checks if there's a schedule conflict
-func (r *CourseScheduleRepository) CheckScheduleConflict(roomID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error) {
+// CheckScheduleConflict checks if there's a room schedule conflict within an academic year
+func (r *CourseScheduleRepository) CheckScheduleConflict(academicYearID, roomID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error) {
 	query := r.db.Model(&models.CourseSchedule{}).
-		Where("room_id = ? AND day = ?", roomID, day).
+		Where("academic_year_id = ? AND room_id = ? AND day = ?", academicYearID, roomID, day).
 		Where("(start_time < ? AND end_time > ?) OR (start_time < ? AND end_time > ?) OR (start_time >= ? AND end_time <= ?)",
 			endTime, startTime, endTime, startTime, startTime, endTime)
 
@@ -332,10 +359,10 @@ func (r *CourseScheduleRepository) CheckScheduleConflict(roomID uint, day string
 	return count > 0, err
 }
 
-// CheckLecturerScheduleConflict checks if there's a lecturer schedule conflict
-func (r *CourseScheduleRepository) CheckLecturerScheduleConflict(userID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error) {
+// CheckLecturerScheduleConflict checks if there's a lecturer schedule conflict within an academic year
+func (r *CourseScheduleRepository) CheckLecturerScheduleConflict(academicYearID, userID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error) {
 	query := r.db.Model(&models.CourseSchedule{}).
-		Where("lecturer_id = ? AND day = ?", userID, day).
+		Where("academic_year_id = ? AND lecturer_id = ? AND day = ?", academicYearID, userID, day).
 		Where("(start_time < ? AND end_time > ?) OR (start_time < ? AND end_time > ?) OR (start_time >= ? AND end_time <= ?)",
 			endTime, startTime, endTime, startTime, startTime, endTime)
 
@@ -350,10 +377,10 @@ func (r *CourseScheduleRepository) CheckLecturerScheduleConflict(userID uint, da
 	return count > 0, err
 }
 
-// CheckStudentGroupScheduleConflict checks if there's a student group schedule conflict
-func (r *CourseScheduleRepository) CheckStudentGroupScheduleConflict(studentGroupID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error) {
+// CheckStudentGroupScheduleConflict checks if there's a student group schedule conflict within an academic year
+func (r *CourseScheduleRepository) CheckStudentGroupScheduleConflict(academicYearID, studentGroupID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error) {
 	query := r.db.Model(&models.CourseSchedule{}).
-		Where("student_group_id = ? AND day = ?", studentGroupID, day).
+		Where("academic_year_id = ? AND student_group_id = ? AND day = ?", academicYearID, studentGroupID, day).
 		Where("(start_time < ? AND end_time > ?) OR (start_time < ? AND end_time > ?) OR (start_time >= ? AND end_time <= ?)",
 			endTime, startTime, endTime, startTime, startTime, endTime)
 
@@ -392,9 +419,6 @@ func (r *CourseScheduleRepository) UpdateSchedulesForCourseInAcademicYear(course
 		if err := r.db.Save(&schedule).Error; err != nil {
 			return err
 		}
-
-		// Log successful update
-		fmt.Printf("Updated schedule ID %d to use lecturer_id %d\n", schedule.ID, newUserID)
 	}
 
 	return nil
@@ -432,8 +456,7 @@ func (r *CourseScheduleRepository) UpdateSchedulesForRoom(roomID uint, capacity
 		return result.Error
 	}
 
-	fmt.Printf("Updated %d course schedules for room_id=%d with capacity=%d\n",
-		result.RowsAffected, roomID, capacity)
+	slog.InfoContext(r.db.Statement.Context, "Updated capacity of course schedules", "room_id", roomID, "capacity", capacity, "count", result.RowsAffected)
 
 	return nil
 }
@@ -453,7 +476,66 @@ func (r *CourseScheduleRepository) GetByStudentGroupAndAcademicYear(studentGroup
 	return schedules, err
 }
 
-// DB returns the database instance
-func (r *CourseScheduleRepository) DB() *gorm.DB {
-	return r.db
+// HasDuplicate checks if a schedule of the course already takes the same day and time in the academic year
+func (r *CourseScheduleRepository) HasDuplicate(courseID uint, day, startTime, endTime string, academicYearID uint, scheduleID *uint) (bool, error) {
+	query := r.db.Model(&models.CourseSchedule{}).
+		Where("course_id = ? AND day = ? AND start_time = ? AND end_time = ? AND academic_year_id = ?",
+			courseID, day, startTime, endTime, academicYearID)
+	if scheduleID != nil {
+		query = query.Where("id <> ?", *scheduleID)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// GetEnrolledCounts returns the ID, student group and enrolled count of every course schedule
+func (r *CourseScheduleRepository) GetEnrolledCounts() ([]models.CourseSchedule, error) {
+	var schedules []models.CourseSchedule
+	err := r.db.Select("id", "student_group_id", "enrolled").Order("id").Find(&schedules).Error
+	return schedules, err
+}
+
+// UpdateEnrolledCounts sets the enrolled count of course schedules, keyed by schedule ID, in one transaction
+func (r *CourseScheduleRepository) UpdateEnrolledCounts(counts map[uint]int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for id, enrolled := range counts {
+			if err := tx.Model(&models.CourseSchedule{}).Where("id = ?", id).
+				Update("enrolled", enrolled).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetDeletedByCourse returns the most recently soft-deleted course schedules of a course
+func (r *CourseScheduleRepository) GetDeletedByCourse(courseID uint, limit int) ([]models.CourseSchedule, error) {
+	var schedules []models.CourseSchedule
+	err := r.db.Unscoped().
+		Where("course_id = ? AND deleted_at IS NOT NULL", courseID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// GetCourseID returns the course ID of a course schedule
+func (r *CourseScheduleRepository) GetCourseID(id uint) (uint, error) {
+	var courseID uint
+	err := r.db.Model(&models.CourseSchedule{}).
+		Where("id = ?", id).
+		Select("course_id").
+		First(&courseID).Error
+	return courseID, err
+}
+
+// GetIDsByCourses returns the IDs of the course schedules of the given courses
+func (r *CourseScheduleRepository) GetIDsByCourses(courseIDs []uint) ([]uint, error) {
+	var scheduleIDs []uint
+	err := r.db.Model(&models.CourseSchedule{}).
+		Where("course_id IN (?)", courseIDs).
+		Pluck("id", &scheduleIDs).Error
+	return scheduleIDs, err
 }
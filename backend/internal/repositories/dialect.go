@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SQL that differs between the supported databases is kept here, so the rest of the
+// repositories stay portable between PostgreSQL and SQLite
+
+// likeEscaper escapes the wildcards of LIKE patterns, with the escape character of caseInsensitiveLike
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// caseInsensitiveLike returns a condition matching a column against a LIKE pattern regardless of case
+// PostgreSQL has ILIKE, SQLite's LIKE is already case-insensitive for ASCII text
+// Patterns use \ as escape character, see containsPattern
+func caseInsensitiveLike(db *gorm.DB, column string) string {
+	if db.Dialector.Name() == "postgres" {
+		return column + ` ILIKE ? ESCAPE '\'`
+	}
+	return column + ` LIKE ? ESCAPE '\'`
+}
+
+// containsPattern returns the LIKE pattern matching text anywhere, with its wildcards escaped
+func containsPattern(text string) string {
+	return "%" + likeEscaper.Replace(text) + "%"
+}
+
+// lockAdvisoryXact waits for a lock named by a class and a key, held until the transaction of tx ends
+// SQLite runs one write transaction at a time, so there it takes nothing
+func lockAdvisoryXact(tx *gorm.DB, class int32, key string) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", class, key).Error
+}
+
+// tryAdvisoryLock takes a lock named by a class and a key that every process sharing the database sees,
+// and returns a function releasing it, or false if it is held elsewhere
+// On PostgreSQL this is a session advisory lock on a dedicated connection, so it is released as well when
+// the process dies; SQLite databases belong to one process, which guards itself, so there it always succeeds
+func tryAdvisoryLock(db *gorm.DB, class int32, key string) (func(), bool, error) {
+	if db.Dialector.Name() != "postgres" {
+		return func() {}, true, nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1, hashtext($2))", class, key).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1, hashtext($2))", class, key); err != nil {
+			slog.Error("Error releasing advisory lock", "key", key, "error", err)
+		}
+		conn.Close()
+	}, true, nil
+}
@@ -1,7 +1,9 @@
 package repositories
 
 import (
-	"log"
+	"log/slog"
+	"strconv"
+	"time"
 
 	"github.com/delpresence/backend/internal/database"
 	"github.com/delpresence/backend/internal/models"
@@ -27,6 +29,30 @@ func (r *EmployeeRepository) FindAll() ([]models.Employee, error) {
 	return employees, result.Error
 }
 
+// employeeListSpec lists employees, see ListSpec
+var employeeListSpec = ListSpec[models.Employee]{
+	Fields: map[string]ListField[models.Employee]{
+		"id":               {Column: "employees.id", Value: func(e models.Employee) interface{} { return e.ID }},
+		"employee_id":      {Column: "employees.employee_id", Value: func(e models.Employee) interface{} { return e.EmployeeID }},
+		"user_id":          {Column: "employees.user_id", Value: func(e models.Employee) interface{} { return e.UserID }},
+		"nip":              {Column: "employees.nip", Value: func(e models.Employee) interface{} { return e.NIP }},
+		"full_name":        {Column: "employees.full_name", Value: func(e models.Employee) interface{} { return e.FullName }},
+		"email":            {Column: "employees.email", Value: func(e models.Employee) interface{} { return e.Email }},
+		"position":         {Column: "employees.position", Value: func(e models.Employee) interface{} { return e.Position }},
+		"department":       {Column: "employees.department", Value: func(e models.Employee) interface{} { return e.Department }},
+		"employment_type":  {Column: "employees.employment_type", Value: func(e models.Employee) interface{} { return e.EmploymentType }},
+		"lifecycle_status": {Column: "employees.lifecycle_status", Value: func(e models.Employee) interface{} { return e.LifecycleStatus }},
+		"last_sync":        {Column: "employees.last_sync", Value: func(e models.Employee) interface{} { return e.LastSync }},
+		"created_at":       {Column: "employees.created_at", Value: func(e models.Employee) interface{} { return e.CreatedAt }},
+	},
+	DefaultSort: []models.ListSort{{Field: "full_name"}},
+}
+
+// List returns one page of employees
+func (r *EmployeeRepository) List(q models.ListQuery) (models.ListResult[models.Employee], error) {
+	return listRecords(r.db, employeeListSpec, q)
+}
+
 // FindByID returns an employee by ID
 func (r *EmployeeRepository) FindByID(id uint) (*models.Employee, error) {
 	var employee models.Employee
@@ -64,7 +90,8 @@ func (r *EmployeeRepository) FindByUserID(userID int) (*models.Employee, error)
 }
 
 // UpsertMany creates or updates multiple employees
-func (r *EmployeeRepository) UpsertMany(employees []models.Employee) error {
+// An employee that fails is rolled back on its own and recorded in the result, the others are kept
+func (r *EmployeeRepository) UpsertMany(employees []models.Employee, result *models.SyncResult) error {
 	if len(employees) == 0 {
 		return nil
 	}
@@ -77,29 +104,42 @@ func (r *EmployeeRepository) UpsertMany(employees []models.Employee) error {
 	}()
 
 	for _, employee := range employees {
+		if err := tx.SavePoint("employee").Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
 		// Try to find existing employee by EmployeeID
 		var existingEmployee models.Employee
-		result := tx.Where("employee_id = ?", employee.EmployeeID).First(&existingEmployee)
-		
-		if result.Error == nil {
+		inserted := tx.Where("employee_id = ?", employee.EmployeeID).First(&existingEmployee).Error != nil
+
+		var err error
+		if inserted {
+			// Create new employee
+			err = tx.Create(&employee).Error
+		} else {
 			// Update existing employee
 			employee.ID = existingEmployee.ID
 			employee.CreatedAt = existingEmployee.CreatedAt
-			
-			if err := tx.Save(&employee).Error; err != nil {
+			err = tx.Save(&employee).Error
+		}
+
+		if err != nil {
+			if rollbackErr := tx.RollbackTo("employee").Error; rollbackErr != nil {
 				tx.Rollback()
-				return err
+				return rollbackErr
 			}
+			result.AddError(strconv.Itoa(employee.EmployeeID), employee.FullName, err)
+			continue
+		}
+		if inserted {
+			result.Inserted++
 		} else {
-			// Create new employee
-			if err := tx.Create(&employee).Error; err != nil {
-				tx.Rollback()
-				return err
-			}
+			result.Updated++
 		}
 	}
 
-	log.Printf("Upserted %d employees", len(employees))
+	slog.Info("Upserted employees", "inserted", result.Inserted, "updated", result.Updated, "failed", len(result.Errors))
 	return tx.Commit().Error
 }
 
@@ -116,4 +156,23 @@ func (r *EmployeeRepository) Update(employee *models.Employee) error {
 // Delete deletes an employee by ID
 func (r *EmployeeRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Employee{}, id).Error
-} 
\ No newline at end of file
+}
+
+// FindActive returns all employees that have not been deactivated
+func (r *EmployeeRepository) FindActive() ([]models.Employee, error) {
+	var employees []models.Employee
+	result := r.db.Where("lifecycle_status = ?", models.LifecycleActive).Find(&employees)
+	return employees, result.Error
+}
+
+// Deactivate marks employees as inactive
+func (r *EmployeeRepository) Deactivate(ids []uint, reason string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Employee{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"lifecycle_status": models.LifecycleInactive,
+		"deactivated_at":   at,
+		"inactive_reason":  reason,
+	}).Error
+}
@@ -18,6 +18,25 @@ func NewFacultyRepository() *FacultyRepository {
 	}
 }
 
+// facultyListSpec lists faculties, see ListSpec
+var facultyListSpec = ListSpec[models.Faculty]{
+	Fields: map[string]ListField[models.Faculty]{
+		"id":                 {Column: "faculties.id", Value: func(f models.Faculty) interface{} { return f.ID }},
+		"code":               {Column: "faculties.code", Value: func(f models.Faculty) interface{} { return f.Code }},
+		"name":               {Column: "faculties.name", Value: func(f models.Faculty) interface{} { return f.Name }},
+		"dean":               {Column: "faculties.dean", Value: func(f models.Faculty) interface{} { return f.Dean }},
+		"establishment_year": {Column: "faculties.establishment_year", Value: func(f models.Faculty) interface{} { return f.EstablishmentYear }},
+		"lecturer_count":     {Column: "faculties.lecturer_count", Value: func(f models.Faculty) interface{} { return f.LecturerCount }},
+		"created_at":         {Column: "faculties.created_at", Value: func(f models.Faculty) interface{} { return f.CreatedAt }},
+	},
+	DefaultSort: []models.ListSort{{Field: "code"}},
+}
+
+// List returns one page of faculties
+func (r *FacultyRepository) List(q models.ListQuery) (models.ListResult[models.Faculty], error) {
+	return listRecords(r.db, facultyListSpec, q)
+}
+
 // Create creates a new faculty
 func (r *FacultyRepository) Create(faculty *models.Faculty) error {
 	return r.db.Create(faculty).Error
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// HolidayRepository handles database operations for holidays
+type HolidayRepository struct {
+	db *gorm.DB
+}
+
+// NewHolidayRepository creates a new holiday repository
+func NewHolidayRepository() *HolidayRepository {
+	return &HolidayRepository{
+		db: database.GetDB(),
+	}
+}
+
+// holidayListSpec lists holidays in date order, see ListSpec
+var holidayListSpec = ListSpec[models.Holiday]{
+	Fields: map[string]ListField[models.Holiday]{
+		"id":         {Column: "holidays.id", Value: func(h models.Holiday) interface{} { return h.ID }},
+		"name":       {Column: "holidays.name", Value: func(h models.Holiday) interface{} { return h.Name }},
+		"start_date": {Column: "holidays.start_date", Value: func(h models.Holiday) interface{} { return h.StartDate }},
+		"end_date":   {Column: "holidays.end_date", Value: func(h models.Holiday) interface{} { return h.EndDate }},
+		"created_at": {Column: "holidays.created_at", Value: func(h models.Holiday) interface{} { return h.CreatedAt }},
+	},
+	DefaultSort: []models.ListSort{{Field: "start_date"}},
+}
+
+// List returns one page of holidays
+func (r *HolidayRepository) List(q models.ListQuery) (models.ListResult[models.Holiday], error) {
+	return listRecords(r.db, holidayListSpec, q)
+}
+
+// FindAll returns all holidays by start date
+func (r *HolidayRepository) FindAll() ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	err := r.db.Order("start_date, id").Find(&holidays).Error
+	return holidays, err
+}
+
+// FindByID returns a holiday by ID, or nil if there is none
+func (r *HolidayRepository) FindByID(id uint) (*models.Holiday, error) {
+	var holiday models.Holiday
+	err := r.db.First(&holiday, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// Create stores a new holiday
+func (r *HolidayRepository) Create(holiday *models.Holiday) error {
+	return r.db.Create(holiday).Error
+}
+
+// Delete removes a holiday
+func (r *HolidayRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Holiday{}, id).Error
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRepository handles database operations for idempotency records
+// Records are shared by all instances, so a retry reaching another instance gets the same response
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository creates a new idempotency repository
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Find returns the record of a key, or nil if there is none
+func (r *IdempotencyRepository) Find(userID uint, route, key string) (*models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+	err := r.db.Where("user_id = ? AND route = ? AND idempotency_key = ?", userID, route, key).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Create stores a new record, it returns false without error when the key is already taken
+func (r *IdempotencyRepository) Create(record *models.IdempotencyRecord) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	return result.RowsAffected == 1, result.Error
+}
+
+// Complete stores the response of the request of a record
+func (r *IdempotencyRepository) Complete(id uint, statusCode int, responseBody string) error {
+	return r.db.Model(&models.IdempotencyRecord{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status_code": statusCode, "response_body": responseBody}).Error
+}
+
+// Delete removes a record, so the key can be used again
+func (r *IdempotencyRepository) Delete(id uint) error {
+	return r.db.Delete(&models.IdempotencyRecord{}, id).Error
+}
+
+// DeleteCreatedBefore removes the records created before a time
+func (r *IdempotencyRepository) DeleteCreatedBefore(before time.Time) error {
+	return r.db.Where("created_at < ?", before).Delete(&models.IdempotencyRecord{}).Error
+}
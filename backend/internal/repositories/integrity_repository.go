@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// IntegrityRepository runs the data integrity checks against the database
+type IntegrityRepository struct {
+	db *gorm.DB
+}
+
+// NewIntegrityRepository creates a new integrity repository
+func NewIntegrityRepository() *IntegrityRepository {
+	return &IntegrityRepository{
+		db: database.GetDB(),
+	}
+}
+
+// integrityCheck selects the IDs of records that violate one integrity rule
+type integrityCheck struct {
+	name        string
+	description string
+	query       string
+	args        []interface{}
+}
+
+// FindViolations runs every integrity check and returns the ones that found records, with all their IDs
+// Sessions that started before staleBefore and are still active count as stale
+func (r *IntegrityRepository) FindViolations(staleBefore time.Time) ([]models.IntegrityIssue, error) {
+	checks := []integrityCheck{
+		{
+			name:        "orphan_group_members",
+			description: "student group memberships of students that do not exist",
+			query: `SELECT stg.student_id FROM student_to_groups stg
+				LEFT JOIN students s ON s.id = stg.student_id AND s.deleted_at IS NULL
+				WHERE s.id IS NULL`,
+		},
+		{
+			name:        "inactive_group_members",
+			description: "student group memberships of deactivated students",
+			query: `SELECT stg.student_id FROM student_to_groups stg
+				JOIN students s ON s.id = stg.student_id
+				WHERE s.lifecycle_status = ?`,
+			args: []interface{}{models.LifecycleInactive},
+		},
+		{
+			name:        "schedules_missing_references",
+			description: "course schedules whose course, room or student group does not exist",
+			query: `SELECT cs.id FROM course_schedules cs
+				LEFT JOIN courses c ON c.id = cs.course_id AND c.deleted_at IS NULL
+				LEFT JOIN rooms r ON r.id = cs.room_id AND r.deleted_at IS NULL
+				LEFT JOIN student_groups sg ON sg.id = cs.student_group_id AND sg.deleted_at IS NULL
+				WHERE cs.deleted_at IS NULL AND (c.id IS NULL OR r.id IS NULL OR sg.id IS NULL)`,
+		},
+		{
+			name:        "orphan_student_attendances",
+			description: "student attendance records of sessions that do not exist",
+			query: `SELECT sa.id FROM student_attendances sa
+				LEFT JOIN attendance_sessions a ON a.id = sa.attendance_session_id
+				WHERE sa.deleted_at IS NULL AND a.id IS NULL`,
+		},
+		{
+			name:        "stale_sessions",
+			description: "attendance sessions still active more than 24 hours after they started",
+			query:       `SELECT id FROM attendance_sessions WHERE deleted_at IS NULL AND status = ? AND start_time < ?`,
+			args:        []interface{}{models.AttendanceStatusActive, staleBefore},
+		},
+		{
+			name:        "enrollment_mismatch",
+			description: "course schedules whose enrolled count differs from the size of their student group",
+			query: `SELECT cs.id FROM course_schedules cs
+				LEFT JOIN (SELECT student_group_id, COUNT(*) AS members FROM student_to_groups GROUP BY student_group_id) g
+					ON g.student_group_id = cs.student_group_id
+				WHERE cs.deleted_at IS NULL AND cs.enrolled <> COALESCE(g.members, 0)`,
+		},
+	}
+
+	issues := []models.IntegrityIssue{}
+	for _, check := range checks {
+		var ids []uint
+		if err := r.db.Raw(check.query, check.args...).Scan(&ids).Error; err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		issues = append(issues, models.IntegrityIssue{
+			Check:       check.name,
+			Description: check.description,
+			Count:       len(ids),
+			SampleIDs:   ids,
+		})
+	}
+	return issues, nil
+}
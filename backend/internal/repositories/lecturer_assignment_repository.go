@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"fmt"
+	"log/slog"
 
 	"github.com/delpresence/backend/internal/database"
 	"github.com/delpresence/backend/internal/models"
@@ -47,8 +48,7 @@ func (r *LecturerAssignmentRepository) GetAll(academicYearID uint) ([]models.Lec
 				assignments[i].Lecturer = &lecturer
 			} else {
 				// Log the issue but don't return an error
-				fmt.Printf("Lecturer with user_id %d or ID %d not found: %v\n", 
-					assignments[i].UserID, assignments[i].UserID, err)
+				slog.Warn("Lecturer of assignment not found", "assignment_id", assignments[i].ID, "lecturer_id", assignments[i].UserID, "error", err)
 			}
 		}
 	}
@@ -76,8 +76,7 @@ func (r *LecturerAssignmentRepository) GetByID(id uint) (models.LecturerAssignme
 			assignment.Lecturer = &lecturer
 		} else {
 			// Log the issue but don't return an error
-			fmt.Printf("Lecturer with user_id %d or ID %d not found: %v\n", 
-				assignment.UserID, assignment.UserID, err)
+			slog.Warn("Lecturer of assignment not found", "assignment_id", assignment.ID, "lecturer_id", assignment.UserID, "error", err)
 		}
 	}
 	
@@ -111,8 +110,7 @@ func (r *LecturerAssignmentRepository) GetByLecturerID(userID int, academicYearI
 				assignments[i].Lecturer = &lecturer
 			} else {
 				// Log the issue but don't return an error
-				fmt.Printf("Lecturer with user_id %d or ID %d not found: %v\n", 
-					assignments[i].UserID, assignments[i].UserID, err)
+				slog.Warn("Lecturer of assignment not found", "assignment_id", assignments[i].ID, "lecturer_id", assignments[i].UserID, "error", err)
 			}
 		}
 	}
@@ -151,8 +149,7 @@ func (r *LecturerAssignmentRepository) GetByCourseID(courseID, academicYearID ui
 				assignments[i].Lecturer = &lecturer
 			} else {
 				// Log the issue but don't return an error
-				fmt.Printf("Lecturer with user_id %d or ID %d not found: %v\n", 
-					assignments[i].UserID, assignments[i].UserID, err)
+				slog.Warn("Lecturer of assignment not found", "assignment_id", assignments[i].ID, "lecturer_id", assignments[i].UserID, "error", err)
 			}
 		}
 	}
@@ -167,7 +164,6 @@ func (r *LecturerAssignmentRepository) Create(assignment *models.LecturerAssignm
 
 // Update updates a lecturer assignment
 func (r *LecturerAssignmentRepository) Update(assignment *models.LecturerAssignment) error {
-	fmt.Printf("Starting Update operation for LecturerAssignment ID=%d\n", assignment.ID)
 	
 	// Start a transaction
 	tx := r.db.Begin()
@@ -276,8 +272,6 @@ func (r *LecturerAssignmentRepository) GetAvailableLecturers(courseID, academicY
 func (r *LecturerAssignmentRepository) GetLecturerAssignmentResponses(academicYearID uint) ([]models.LecturerAssignmentResponse, error) {
 	var responses []models.LecturerAssignmentResponse
 	
-	// Debug: Print the academicYearID
-	fmt.Printf("GetLecturerAssignmentResponses called with academicYearID: %d\n", academicYearID)
 	
 	// First, get all the assignments without joins to check if we have data
 	var assignments []models.LecturerAssignment
@@ -291,70 +285,100 @@ func (r *LecturerAssignmentRepository) GetLecturerAssignmentResponses(academicYe
 		return nil, err
 	}
 	
-	fmt.Printf("Found %d assignments in the database\n", len(assignments))
 	
 	// If we found assignments, get the detailed information
 	// But use separate queries to avoid losing data due to JOINs
 	for _, assignment := range assignments {
-		response := models.LecturerAssignmentResponse{
-			ID:             assignment.ID,
-			UserID:         assignment.UserID,
-			CourseID:       assignment.CourseID,
-			AcademicYearID: assignment.AcademicYearID,
-			CreatedAt:      assignment.CreatedAt,
-			UpdatedAt:      assignment.UpdatedAt,
-		}
-		
-		// Get course information
-		var course models.Course
-		if err := r.db.First(&course, assignment.CourseID).Error; err == nil {
-			response.CourseName = course.Name
-			response.CourseCode = course.Code
-			response.CourseSemester = course.Semester
-		} else {
-			fmt.Printf("Course with ID %d not found: %v\n", assignment.CourseID, err)
-			response.CourseName = "Unknown Course"
-			response.CourseCode = "N/A"
-		}
-		
-		// Get academic year information
-		var academicYear models.AcademicYear
-		if err := r.db.First(&academicYear, assignment.AcademicYearID).Error; err == nil {
-			response.AcademicYearName = academicYear.Name
-			response.AcademicYearSemester = academicYear.Semester
-		} else {
-			fmt.Printf("Academic year with ID %d not found: %v\n", assignment.AcademicYearID, err)
-			response.AcademicYearName = "N/A"
-			response.AcademicYearSemester = "N/A"
-		}
-		
-		// First try getting lecturer by user_id from the lecturers table
-		var lecturer models.Lecturer
-		if err := r.db.Where("user_id = ?", assignment.UserID).First(&lecturer).Error; err == nil {
+		responses = append(responses, r.toResponse(assignment))
+	}
+	
+	return responses, nil
+}
+
+// lecturerAssignmentListSpec lists lecturer assignments, see ListSpec
+var lecturerAssignmentListSpec = ListSpec[models.LecturerAssignment]{
+	Fields: map[string]ListField[models.LecturerAssignment]{
+		"id":               {Column: "lecturer_assignments.id", Value: func(a models.LecturerAssignment) interface{} { return a.ID }},
+		"user_id":          {Column: "lecturer_assignments.user_id", Value: func(a models.LecturerAssignment) interface{} { return a.UserID }},
+		"course_id":        {Column: "lecturer_assignments.course_id", Value: func(a models.LecturerAssignment) interface{} { return a.CourseID }},
+		"academic_year_id": {Column: "lecturer_assignments.academic_year_id", Value: func(a models.LecturerAssignment) interface{} { return a.AcademicYearID }},
+		"created_at":       {Column: "lecturer_assignments.created_at", Value: func(a models.LecturerAssignment) interface{} { return a.CreatedAt }},
+	},
+}
+
+// ListResponses returns one page of lecturer assignments with detailed information
+func (r *LecturerAssignmentRepository) ListResponses(q models.ListQuery) (models.ListResult[models.LecturerAssignmentResponse], error) {
+	page, err := listRecords(r.db, lecturerAssignmentListSpec, q)
+	if err != nil {
+		return models.ListResult[models.LecturerAssignmentResponse]{}, err
+	}
+
+	result := models.ListResult[models.LecturerAssignmentResponse]{
+		Items: make([]models.LecturerAssignmentResponse, len(page.Items)),
+		Page:  page.Page,
+	}
+	for i, assignment := range page.Items {
+		result.Items[i] = r.toResponse(assignment)
+	}
+	return result, nil
+}
+
+// toResponse adds the course, academic year and lecturer details to an assignment
+// Missing records are logged and filled with placeholders rather than failing the list
+func (r *LecturerAssignmentRepository) toResponse(assignment models.LecturerAssignment) models.LecturerAssignmentResponse {
+	response := models.LecturerAssignmentResponse{
+		ID:             assignment.ID,
+		UserID:         assignment.UserID,
+		CourseID:       assignment.CourseID,
+		AcademicYearID: assignment.AcademicYearID,
+		CreatedAt:      assignment.CreatedAt,
+		UpdatedAt:      assignment.UpdatedAt,
+	}
+	
+	// Get course information
+	var course models.Course
+	if err := r.db.First(&course, assignment.CourseID).Error; err == nil {
+		response.CourseName = course.Name
+		response.CourseCode = course.Code
+		response.CourseSemester = course.Semester
+	} else {
+		slog.Warn("Course of assignment not found", "assignment_id", assignment.ID, "course_id", assignment.CourseID, "error", err)
+		response.CourseName = "Unknown Course"
+		response.CourseCode = "N/A"
+	}
+	
+	// Get academic year information
+	var academicYear models.AcademicYear
+	if err := r.db.First(&academicYear, assignment.AcademicYearID).Error; err == nil {
+		response.AcademicYearName = academicYear.Name
+		response.AcademicYearSemester = academicYear.Semester
+	} else {
+		slog.Warn("Academic year of assignment not found", "assignment_id", assignment.ID, "academic_year_id", assignment.AcademicYearID, "error", err)
+		response.AcademicYearName = "N/A"
+		response.AcademicYearSemester = "N/A"
+	}
+	
+	// First try getting lecturer by user_id from the lecturers table
+	var lecturer models.Lecturer
+	if err := r.db.Where("user_id = ?", assignment.UserID).First(&lecturer).Error; err == nil {
+		response.LecturerName = lecturer.FullName
+		response.LecturerNIP = lecturer.NIP
+		response.LecturerEmail = lecturer.Email
+	} else {
+		// If not found by user_id, try finding by direct ID match (for backward compatibility)
+		if err := r.db.Where("id = ?", assignment.UserID).First(&lecturer).Error; err == nil {
 			response.LecturerName = lecturer.FullName
 			response.LecturerNIP = lecturer.NIP
 			response.LecturerEmail = lecturer.Email
 		} else {
-			// If not found by user_id, try finding by direct ID match (for backward compatibility)
-			if err := r.db.Where("id = ?", assignment.UserID).First(&lecturer).Error; err == nil {
-				response.LecturerName = lecturer.FullName
-				response.LecturerNIP = lecturer.NIP
-				response.LecturerEmail = lecturer.Email
-				
-				// Log that we found by ID instead of user_id
-				fmt.Printf("Lecturer found by ID %d instead of user_id\n", assignment.UserID)
-			} else {
-				// Handle the case where lecturer doesn't exist
-				fmt.Printf("Lecturer with user_id %d or ID %d not found: %v\n", assignment.UserID, assignment.UserID, err)
-				response.LecturerName = "Unknown Lecturer"
-				response.LecturerNIP = "N/A"
-			}
+			// Handle the case where lecturer doesn't exist
+			slog.Warn("Lecturer of assignment not found", "assignment_id", assignment.ID, "lecturer_id", assignment.UserID, "error", err)
+			response.LecturerName = "Unknown Lecturer"
+			response.LecturerNIP = "N/A"
 		}
-		
-		responses = append(responses, response)
 	}
-	
-	return responses, nil
+
+	return response
 }
 
 // GetLecturerAssignmentResponseByID returns a specific lecturer assignment with detailed information
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// LecturerAvailabilityRepository handles database operations for lecturer availability windows
+type LecturerAvailabilityRepository struct {
+	db *gorm.DB
+}
+
+// NewLecturerAvailabilityRepository creates a new lecturer availability repository
+func NewLecturerAvailabilityRepository() *LecturerAvailabilityRepository {
+	return &LecturerAvailabilityRepository{
+		db: database.GetDB(),
+	}
+}
+
+// FindByLecturer returns the availability windows of a lecturer, of all lecturers when userID is 0
+func (r *LecturerAvailabilityRepository) FindByLecturer(userID uint) ([]models.LecturerAvailability, error) {
+	var windows []models.LecturerAvailability
+	query := r.db.Order("user_id, id")
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	err := query.Find(&windows).Error
+	return windows, err
+}
+
+// FindByID returns an availability window, or nil if there is none
+func (r *LecturerAvailabilityRepository) FindByID(id uint) (*models.LecturerAvailability, error) {
+	var window models.LecturerAvailability
+	err := r.db.First(&window, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+// Create stores a new availability window
+func (r *LecturerAvailabilityRepository) Create(window *models.LecturerAvailability) error {
+	return r.db.Create(window).Error
+}
+
+// Delete removes an availability window
+func (r *LecturerAvailabilityRepository) Delete(id uint) error {
+	return r.db.Delete(&models.LecturerAvailability{}, id).Error
+}
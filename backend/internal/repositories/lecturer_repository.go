@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/delpresence/backend/internal/database"
 	"github.com/delpresence/backend/internal/models"
 	"gorm.io/gorm"
@@ -61,6 +64,35 @@ func (r *LecturerRepository) FindAll() ([]models.Lecturer, error) {
 	return lecturers, nil
 }
 
+// lecturerListSpec lists lecturers with their study program, see ListSpec
+var lecturerListSpec = ListSpec[models.Lecturer]{
+	Fields: map[string]ListField[models.Lecturer]{
+		"id":                 {Column: "lecturers.id", Value: func(l models.Lecturer) interface{} { return l.ID }},
+		"employee_id":        {Column: "lecturers.employee_id", Value: func(l models.Lecturer) interface{} { return l.EmployeeID }},
+		"lecturer_id":        {Column: "lecturers.lecturer_id", Value: func(l models.Lecturer) interface{} { return l.LecturerID }},
+		"user_id":            {Column: "lecturers.user_id", Value: func(l models.Lecturer) interface{} { return l.UserID }},
+		"nip":                {Column: "lecturers.n_ip", Value: func(l models.Lecturer) interface{} { return l.NIP }},
+		"nidn":               {Column: "lecturers.n_id_n", Value: func(l models.Lecturer) interface{} { return l.NIDN }},
+		"full_name":          {Column: "lecturers.full_name", Value: func(l models.Lecturer) interface{} { return l.FullName }},
+		"email":              {Column: "lecturers.email", Value: func(l models.Lecturer) interface{} { return l.Email }},
+		"study_program_id":   {Column: "lecturers.study_program_id", Value: func(l models.Lecturer) interface{} { return l.StudyProgramID }},
+		"study_program":      {Column: "lecturers.study_program_name", Value: func(l models.Lecturer) interface{} { return l.StudyProgramName }},
+		"academic_rank":      {Column: "lecturers.academic_rank", Value: func(l models.Lecturer) interface{} { return l.AcademicRank }},
+		"academic_rank_desc": {Column: "lecturers.academic_rank_desc", Value: func(l models.Lecturer) interface{} { return l.AcademicRankDesc }},
+		"education_level":    {Column: "lecturers.education_level", Value: func(l models.Lecturer) interface{} { return l.EducationLevel }},
+		"lifecycle_status":   {Column: "lecturers.lifecycle_status", Value: func(l models.Lecturer) interface{} { return l.LifecycleStatus }},
+		"last_sync":          {Column: "lecturers.last_sync", Value: func(l models.Lecturer) interface{} { return l.LastSync }},
+		"created_at":         {Column: "lecturers.created_at", Value: func(l models.Lecturer) interface{} { return l.CreatedAt }},
+	},
+	Preloads:    []string{"StudyProgram"},
+	DefaultSort: []models.ListSort{{Field: "full_name"}},
+}
+
+// List returns one page of lecturers with their study program
+func (r *LecturerRepository) List(q models.ListQuery) (models.ListResult[models.Lecturer], error) {
+	return listRecords(r.db, lecturerListSpec, q)
+}
+
 // DeleteByID deletes a lecturer by ID
 func (r *LecturerRepository) DeleteByID(id uint) error {
 	return r.db.Delete(&models.Lecturer{}, id).Error
@@ -85,7 +117,8 @@ func (r *LecturerRepository) Upsert(lecturer *models.Lecturer) error {
 }
 
 // UpsertMany creates or updates multiple lecturers
-func (r *LecturerRepository) UpsertMany(lecturers []models.Lecturer) error {
+// A lecturer that fails is rolled back on its own and recorded in the result, the others are kept
+func (r *LecturerRepository) UpsertMany(lecturers []models.Lecturer, result *models.SyncResult) error {
 	// Start a transaction
 	tx := r.db.Begin()
 	defer func() {
@@ -100,10 +133,37 @@ func (r *LecturerRepository) UpsertMany(lecturers []models.Lecturer) error {
 
 	// Process each lecturer
 	for i := range lecturers {
-		if err := r.Upsert(&lecturers[i]); err != nil {
+		lecturer := &lecturers[i]
+		if err := tx.SavePoint("lecturer").Error; err != nil {
 			tx.Rollback()
 			return err
 		}
+
+		var existing models.Lecturer
+		inserted := tx.Where("lecturer_id = ?", lecturer.LecturerID).First(&existing).Error != nil
+
+		var err error
+		if inserted {
+			err = tx.Create(lecturer).Error
+		} else {
+			lecturer.ID = existing.ID
+			lecturer.CreatedAt = existing.CreatedAt
+			err = tx.Save(lecturer).Error
+		}
+
+		if err != nil {
+			if rollbackErr := tx.RollbackTo("lecturer").Error; rollbackErr != nil {
+				tx.Rollback()
+				return rollbackErr
+			}
+			result.AddError(strconv.Itoa(lecturer.LecturerID), lecturer.FullName, err)
+			continue
+		}
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Updated++
+		}
 	}
 
 	// Commit the transaction
@@ -115,12 +175,12 @@ func (r *LecturerRepository) Search(query string) ([]models.Lecturer, error) {
 	var lecturers []models.Lecturer
 	
 	// Create the LIKE search pattern
-	searchPattern := "%" + query + "%"
+	searchPattern := containsPattern(query)
 	
 	// Search for matching lecturers using the correct database column names
-	err := r.db.Where("full_name ILIKE ?", searchPattern).
-		Or("n_ip ILIKE ?", searchPattern).
-		Or("n_id_n ILIKE ?", searchPattern).
+	err := r.db.Where(caseInsensitiveLike(r.db, "full_name"), searchPattern).
+		Or(caseInsensitiveLike(r.db, "n_ip"), searchPattern).
+		Or(caseInsensitiveLike(r.db, "n_id_n"), searchPattern).
 		Limit(10). // Limit results to prevent performance issues
 		Find(&lecturers).Error
 	
@@ -157,4 +217,23 @@ func (r *LecturerRepository) GetByID(id uint) (models.Lecturer, error) {
 		return models.Lecturer{}, err
 	}
 	return lecturer, nil
-} 
\ No newline at end of file
+}
+
+// FindActive finds all lecturers that have not been deactivated
+func (r *LecturerRepository) FindActive() ([]models.Lecturer, error) {
+	var lecturers []models.Lecturer
+	err := r.db.Where("lifecycle_status = ?", models.LifecycleActive).Find(&lecturers).Error
+	return lecturers, err
+}
+
+// Deactivate marks lecturers as inactive
+func (r *LecturerRepository) Deactivate(ids []uint, reason string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Lecturer{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"lifecycle_status": models.LifecycleInactive,
+		"deactivated_at":   at,
+		"inactive_reason":  reason,
+	}).Error
+}
@@ -0,0 +1,467 @@
+package repositories
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// List endpoints share one query contract (models.ListQuery). Each listed type describes its
+// fields once in a ListSpec; listRecords runs a query against the database and listSlice
+// against the records of an in-memory store, with the same filtering, sorting and paging.
+
+// ErrInvalidListQuery is returned for filters, sorts and cursors a list does not support
+var ErrInvalidListQuery = errors.New("invalid list query")
+
+// ListField is a field of a list that can be filtered and sorted on
+type ListField[T any] struct {
+	// Column is the column of the field, qualified with its table
+	Column string
+	// Value returns the value of the field, it must not return nil or a pointer
+	Value func(T) interface{}
+}
+
+// ListSpec describes the fields of a list by their JSON name
+// Every spec has an "id" field, it breaks ties so that pages are stable
+type ListSpec[T any] struct {
+	Fields      map[string]ListField[T]
+	Joins       []string // Needed by columns of other tables
+	Preloads    []string
+	DefaultSort []models.ListSort
+}
+
+// listCondition is a filter parsed against its field
+type listCondition[T any] struct {
+	field  ListField[T]
+	op     string
+	values []interface{}
+}
+
+// listCursor is the position after the last record of a page, for the sort it was made with
+type listCursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// listRecords lists the records selected by db, which may already be scoped with conditions
+func listRecords[T any](db *gorm.DB, spec ListSpec[T], q models.ListQuery) (models.ListResult[T], error) {
+	q = withPageDefaults(q)
+	result := models.ListResult[T]{Items: []T{}, Page: models.PageInfo{PageSize: q.PageSize}}
+
+	order, err := spec.sortOrder(q.Sort)
+	if err != nil {
+		return result, err
+	}
+	conditions, err := spec.parseFilters(q.Filters)
+	if err != nil {
+		return result, err
+	}
+
+	query := db.Model(new(T))
+	for _, join := range spec.Joins {
+		query = query.Joins(join)
+	}
+	for _, condition := range conditions {
+		query = condition.where(query)
+	}
+	query = query.Session(&gorm.Session{})
+
+	if err := query.Count(&result.Page.Total).Error; err != nil {
+		return result, err
+	}
+
+	for _, preload := range spec.Preloads {
+		query = query.Preload(preload)
+	}
+	for _, field := range order {
+		column := spec.Fields[field.Field].Column
+		if field.Desc {
+			column += " DESC"
+		}
+		query = query.Order(column)
+	}
+
+	if !q.UseCursor {
+		result.Page.Page = q.Page
+		err := query.Offset(q.Offset()).Limit(q.PageSize).Find(&result.Items).Error
+		result.Page.HasMore = int64(q.Offset()+len(result.Items)) < result.Page.Total
+		return result, err
+	}
+
+	if q.Cursor != "" {
+		values, err := spec.decodeCursor(q.Cursor, order)
+		if err != nil {
+			return result, err
+		}
+		query = spec.after(query, order, values)
+	}
+	if err := query.Limit(q.PageSize + 1).Find(&result.Items).Error; err != nil {
+		return result, err
+	}
+	return spec.cursorPage(result, order, q.PageSize)
+}
+
+// listSlice lists records held in memory, the in-memory counterpart of listRecords
+func listSlice[T any](items []T, spec ListSpec[T], q models.ListQuery) (models.ListResult[T], error) {
+	q = withPageDefaults(q)
+	result := models.ListResult[T]{Items: []T{}, Page: models.PageInfo{PageSize: q.PageSize}}
+
+	order, err := spec.sortOrder(q.Sort)
+	if err != nil {
+		return result, err
+	}
+	conditions, err := spec.parseFilters(q.Filters)
+	if err != nil {
+		return result, err
+	}
+
+	matches := []T{}
+	for _, item := range items {
+		keep := true
+		for _, condition := range conditions {
+			keep = keep && condition.matches(item)
+		}
+		if keep {
+			matches = append(matches, item)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return spec.compare(matches[i], spec.values(matches[j], order), order) < 0
+	})
+	result.Page.Total = int64(len(matches))
+
+	if !q.UseCursor {
+		result.Page.Page = q.Page
+		start := min(q.Offset(), len(matches))
+		end := min(start+q.PageSize, len(matches))
+		result.Items = append(result.Items, matches[start:end]...)
+		result.Page.HasMore = end < len(matches)
+		return result, nil
+	}
+
+	if q.Cursor != "" {
+		values, err := spec.decodeCursor(q.Cursor, order)
+		if err != nil {
+			return result, err
+		}
+		after := []T{}
+		for _, item := range matches {
+			if spec.compare(item, values, order) > 0 {
+				after = append(after, item)
+			}
+		}
+		matches = after
+	}
+	result.Items = append(result.Items, matches[:min(q.PageSize+1, len(matches))]...)
+	return spec.cursorPage(result, order, q.PageSize)
+}
+
+// withPageDefaults applies the default page and page size to a query
+func withPageDefaults(q models.ListQuery) models.ListQuery {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 {
+		q.PageSize = models.DefaultPageSize
+	}
+	if q.PageSize > models.MaxPageSize {
+		q.PageSize = models.MaxPageSize
+	}
+	return q
+}
+
+// cursorPage trims a result fetched with one extra record and sets the cursor of the next page
+func (spec ListSpec[T]) cursorPage(result models.ListResult[T], order []models.ListSort, pageSize int) (models.ListResult[T], error) {
+	if len(result.Items) <= pageSize {
+		return result, nil
+	}
+	result.Items = result.Items[:pageSize]
+	result.Page.HasMore = true
+
+	cursor, err := spec.encodeCursor(result.Items[pageSize-1], order)
+	result.Page.NextCursor = cursor
+	return result, err
+}
+
+// sortOrder validates the requested sort, or takes the default, and adds the ID as tie breaker
+func (spec ListSpec[T]) sortOrder(requested []models.ListSort) ([]models.ListSort, error) {
+	if len(requested) == 0 {
+		requested = spec.DefaultSort
+	}
+
+	order := make([]models.ListSort, 0, len(requested)+1)
+	hasID := false
+	for _, field := range requested {
+		if _, ok := spec.Fields[field.Field]; !ok {
+			return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidListQuery, field.Field)
+		}
+		hasID = hasID || field.Field == "id"
+		order = append(order, field)
+	}
+	if !hasID {
+		order = append(order, models.ListSort{Field: "id"})
+	}
+	return order, nil
+}
+
+// parseFilters validates the filters and converts their values to the types of their fields
+func (spec ListSpec[T]) parseFilters(filters []models.ListFilter) ([]listCondition[T], error) {
+	conditions := make([]listCondition[T], 0, len(filters))
+	for _, filter := range filters {
+		field, ok := spec.Fields[filter.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot filter by %q", ErrInvalidListQuery, filter.Field)
+		}
+
+		var zero T
+		sample := field.Value(zero)
+		switch filter.Op {
+		case models.FilterEq, models.FilterNe, models.FilterGt, models.FilterGte, models.FilterLt, models.FilterLte:
+			if len(filter.Values) != 1 {
+				return nil, fmt.Errorf("%w: %s filter on %q takes one value", ErrInvalidListQuery, filter.Op, filter.Field)
+			}
+		case models.FilterIn:
+			if len(filter.Values) == 0 {
+				return nil, fmt.Errorf("%w: in filter on %q takes at least one value", ErrInvalidListQuery, filter.Field)
+			}
+		case models.FilterLike:
+			if _, ok := sample.(string); !ok || len(filter.Values) != 1 {
+				return nil, fmt.Errorf("%w: like filter on %q takes one value and a text field", ErrInvalidListQuery, filter.Field)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown filter operator %q", ErrInvalidListQuery, filter.Op)
+		}
+
+		condition := listCondition[T]{field: field, op: filter.Op}
+		for _, raw := range filter.Values {
+			value, err := parseListValue(sample, raw)
+			if err != nil {
+				return nil, fmt.Errorf("%w: filter on %q: %v", ErrInvalidListQuery, filter.Field, err)
+			}
+			condition.values = append(condition.values, value)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// where adds the condition to a database query
+func (c listCondition[T]) where(db *gorm.DB) *gorm.DB {
+	switch c.op {
+	case models.FilterNe:
+		return db.Where(c.field.Column+" <> ?", c.values[0])
+	case models.FilterGt:
+		return db.Where(c.field.Column+" > ?", c.values[0])
+	case models.FilterGte:
+		return db.Where(c.field.Column+" >= ?", c.values[0])
+	case models.FilterLt:
+		return db.Where(c.field.Column+" < ?", c.values[0])
+	case models.FilterLte:
+		return db.Where(c.field.Column+" <= ?", c.values[0])
+	case models.FilterIn:
+		return db.Where(c.field.Column+" IN ?", c.values)
+	case models.FilterLike:
+		return db.Where(caseInsensitiveLike(db, c.field.Column), containsPattern(c.values[0].(string)))
+	}
+	return db.Where(c.field.Column+" = ?", c.values[0])
+}
+
+// matches reports whether a record held in memory satisfies the condition
+func (c listCondition[T]) matches(item T) bool {
+	value := c.field.Value(item)
+	switch c.op {
+	case models.FilterNe:
+		return compareListValues(value, c.values[0]) != 0
+	case models.FilterGt:
+		return compareListValues(value, c.values[0]) > 0
+	case models.FilterGte:
+		return compareListValues(value, c.values[0]) >= 0
+	case models.FilterLt:
+		return compareListValues(value, c.values[0]) < 0
+	case models.FilterLte:
+		return compareListValues(value, c.values[0]) <= 0
+	case models.FilterIn:
+		for _, candidate := range c.values {
+			if compareListValues(value, candidate) == 0 {
+				return true
+			}
+		}
+		return false
+	case models.FilterLike:
+		return strings.Contains(strings.ToLower(value.(string)), strings.ToLower(c.values[0].(string)))
+	}
+	return compareListValues(value, c.values[0]) == 0
+}
+
+// values returns the values of the sort fields of a record
+func (spec ListSpec[T]) values(item T, order []models.ListSort) []interface{} {
+	values := make([]interface{}, len(order))
+	for i, field := range order {
+		values[i] = spec.Fields[field.Field].Value(item)
+	}
+	return values
+}
+
+// compare orders a record against the sort values of another record or a cursor
+func (spec ListSpec[T]) compare(item T, values []interface{}, order []models.ListSort) int {
+	for i, field := range order {
+		cmp := compareListValues(spec.Fields[field.Field].Value(item), values[i])
+		if field.Desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
+// after restricts a database query to the records that come after the cursor values
+// For the order (a, b, id) that is a > x OR (a = x AND b > y) OR (a = x AND b = y AND id > z)
+func (spec ListSpec[T]) after(db *gorm.DB, order []models.ListSort, values []interface{}) *gorm.DB {
+	var clauses []string
+	var args []interface{}
+	for i, field := range order {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, spec.Fields[order[j].Field].Column+" = ?")
+			args = append(args, values[j])
+		}
+		operator := " > ?"
+		if field.Desc {
+			operator = " < ?"
+		}
+		parts = append(parts, spec.Fields[field.Field].Column+operator)
+		args = append(args, values[i])
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return db.Where("("+strings.Join(clauses, " OR ")+")", args...)
+}
+
+// encodeCursor returns the cursor pointing after a record
+func (spec ListSpec[T]) encodeCursor(item T, order []models.ListSort) (string, error) {
+	cursor := listCursor{Sort: formatListSort(order)}
+	for _, value := range spec.values(item, order) {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		cursor.Values = append(cursor.Values, raw)
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor returns the sort values of a cursor, which must have been made with the same sort
+func (spec ListSpec[T]) decodeCursor(encoded string, order []models.ListSort) ([]interface{}, error) {
+	invalid := fmt.Errorf("%w: invalid cursor", ErrInvalidListQuery)
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid
+	}
+	var cursor listCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || len(cursor.Values) != len(order) {
+		return nil, invalid
+	}
+	if cursor.Sort != formatListSort(order) {
+		return nil, fmt.Errorf("%w: the cursor was made for another sort", ErrInvalidListQuery)
+	}
+
+	var zero T
+	values := make([]interface{}, len(order))
+	for i, field := range order {
+		target := reflect.New(reflect.TypeOf(spec.Fields[field.Field].Value(zero)))
+		if err := json.Unmarshal(cursor.Values[i], target.Interface()); err != nil {
+			return nil, invalid
+		}
+		values[i] = target.Elem().Interface()
+	}
+	return values, nil
+}
+
+// formatListSort writes a sort the way it is given in the sort query parameter
+func formatListSort(order []models.ListSort) string {
+	fields := make([]string, len(order))
+	for i, field := range order {
+		fields[i] = field.Field
+		if field.Desc {
+			fields[i] = "-" + field.Field
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// parseListValue converts a raw query value to the type of sample
+// Times are given as RFC 3339 or as a date (YYYY-MM-DD)
+func parseListValue(sample interface{}, raw string) (interface{}, error) {
+	switch sample.(type) {
+	case string:
+		return raw, nil
+	case int:
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", raw)
+		}
+		return value, nil
+	case uint:
+		value, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ID %q", raw)
+		}
+		return uint(value), nil
+	case bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean %q", raw)
+		}
+		return value, nil
+	case time.Time:
+		if value, err := time.Parse(time.RFC3339, raw); err == nil {
+			return value, nil
+		}
+		value, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q, use YYYY-MM-DD or RFC 3339", raw)
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("unsupported field type %T", sample)
+}
+
+// compareListValues compares two values of the same field type
+func compareListValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case int:
+		return cmp.Compare(a, b.(int))
+	case uint:
+		return cmp.Compare(a, b.(uint))
+	case bool:
+		if a == b.(bool) {
+			return 0
+		}
+		if !a {
+			return -1
+		}
+		return 1
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+	return 0
+}
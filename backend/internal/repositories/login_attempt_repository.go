@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptRepository handles database operations for login attempts
+// It is used as the shared limiter store when several instances run behind a load balancer
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository creates a new login attempt repository
+func NewLoginAttemptRepository() *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Get returns the login attempt record for a key, or nil if there is none
+func (r *LoginAttemptRepository) Get(key string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	result := r.db.Where("limit_key = ?", key).First(&attempt)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &attempt, nil
+}
+
+// IncrementFailures adds one failure to a key in a single upsert, forgetting failures from before windowStart
+func (r *LoginAttemptRepository) IncrementFailures(key string, now, windowStart time.Time) (*models.LoginAttempt, error) {
+	attempt := models.LoginAttempt{Key: key, Failures: 1, LastFailureAt: now}
+	result := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "limit_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":        gorm.Expr("CASE WHEN login_attempts.last_failure_at < ? THEN 1 ELSE login_attempts.failures + 1 END", windowStart),
+			"last_failure_at": now,
+			"updated_at":      now,
+		}),
+	}).Create(&attempt)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	updated, err := r.Get(key)
+	if err != nil || updated == nil {
+		// Deleted by an unlock right after the update
+		return &attempt, err
+	}
+	return updated, nil
+}
+
+// Lock locks a key that reached maxFailures and resets its failures
+// The failures condition makes only one of several concurrent callers lock the key
+func (r *LoginAttemptRepository) Lock(key string, maxFailures int, lockedUntil time.Time) (bool, error) {
+	result := r.db.Model(&models.LoginAttempt{}).
+		Where("limit_key = ? AND failures >= ?", key, maxFailures).
+		Updates(map[string]interface{}{
+			"failures":     0,
+			"lockouts":     gorm.Expr("lockouts + 1"),
+			"locked_until": lockedUntil,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Delete removes the login attempt record for a key
+func (r *LoginAttemptRepository) Delete(key string) error {
+	return r.db.Where("limit_key = ?", key).Delete(&models.LoginAttempt{}).Error
+}
+
+// FindLocked returns all keys that are locked at the given time
+func (r *LoginAttemptRepository) FindLocked(now time.Time) ([]models.LoginAttempt, error) {
+	var attempts []models.LoginAttempt
+	result := r.db.Where("locked_until > ?", now).Order("locked_until DESC").Find(&attempts)
+	return attempts, result.Error
+}
@@ -0,0 +1,426 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var _ AttendanceStore = (*MemoryAttendanceStore)(nil)
+
+// MemoryAttendanceStore is an in-process AttendanceStore
+// Sessions and attendance records are returned with their course schedule and student
+// filled in from the stores it was created with, like the database store preloads them
+type MemoryAttendanceStore struct {
+	schedules        *MemoryCourseScheduleStore
+	students         *MemoryStudentStore
+	sessions         map[uint]models.AttendanceSession
+	attendances      map[uint]models.StudentAttendance
+	nextSessionID    uint
+	nextAttendanceID uint
+	mutex            sync.Mutex
+}
+
+// NewMemoryAttendanceStore creates a new in-memory attendance store on top of schedule and student stores
+func NewMemoryAttendanceStore(schedules *MemoryCourseScheduleStore, students *MemoryStudentStore) *MemoryAttendanceStore {
+	return &MemoryAttendanceStore{
+		schedules:        schedules,
+		students:         students,
+		sessions:         make(map[uint]models.AttendanceSession),
+		attendances:      make(map[uint]models.StudentAttendance),
+		nextSessionID:    1,
+		nextAttendanceID: 1,
+	}
+}
+
+// WithContext returns the store itself, it does not log
+func (s *MemoryAttendanceStore) WithContext(ctx context.Context) AttendanceStore {
+	return s
+}
+
+// withSchedule fills in the course schedule of a session
+func (s *MemoryAttendanceStore) withSchedule(session models.AttendanceSession) models.AttendanceSession {
+	if schedule, ok := s.schedules.get(session.CourseScheduleID); ok {
+		session.CourseSchedule = schedule
+	}
+	return session
+}
+
+// withRelations fills in the student and session of an attendance record
+func (s *MemoryAttendanceStore) withRelations(attendance models.StudentAttendance) models.StudentAttendance {
+	if student, ok := s.students.get(attendance.StudentID); ok {
+		attendance.Student = student
+	}
+	s.mutex.Lock()
+	session, ok := s.sessions[attendance.AttendanceSessionID]
+	s.mutex.Unlock()
+	if ok {
+		attendance.AttendanceSession = s.withSchedule(session)
+	}
+	return attendance
+}
+
+// filterSessions returns the sessions matching keep with their course schedule, ordered by ID
+func (s *MemoryAttendanceStore) filterSessions(keep func(models.AttendanceSession) bool) []models.AttendanceSession {
+	s.mutex.Lock()
+	matches := []models.AttendanceSession{}
+	for _, session := range s.sessions {
+		if keep(session) {
+			matches = append(matches, session)
+		}
+	}
+	s.mutex.Unlock()
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	for i := range matches {
+		matches[i] = s.withSchedule(matches[i])
+	}
+	return matches
+}
+
+// filterAttendances returns the attendance records matching keep with their relations, ordered by ID
+func (s *MemoryAttendanceStore) filterAttendances(keep func(models.StudentAttendance) bool) []models.StudentAttendance {
+	s.mutex.Lock()
+	matches := []models.StudentAttendance{}
+	for _, attendance := range s.attendances {
+		if keep(attendance) {
+			matches = append(matches, attendance)
+		}
+	}
+	s.mutex.Unlock()
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	for i := range matches {
+		matches[i] = s.withRelations(matches[i])
+	}
+	return matches
+}
+
+// sortLatestFirst orders sessions by date and start time, latest first
+func sortLatestFirst(sessions []models.AttendanceSession) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		if !sessions[i].Date.Equal(sessions[j].Date) {
+			return sessions[i].Date.After(sessions[j].Date)
+		}
+		return sessions[i].StartTime.After(sessions[j].StartTime)
+	})
+}
+
+// idSet turns a list of IDs into a set
+func idSet(ids []uint) map[uint]bool {
+	set := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// CreateAttendanceSession creates a new attendance session
+func (s *MemoryAttendanceStore) CreateAttendanceSession(session *models.AttendanceSession) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if session.ID == 0 {
+		session.ID = s.nextSessionID
+	}
+	if session.ID >= s.nextSessionID {
+		s.nextSessionID = session.ID + 1
+	}
+	session.CreatedAt = now
+	session.UpdatedAt = now
+	s.sessions[session.ID] = *session
+	return nil
+}
+
+// UpdateAttendanceSession updates an attendance session
+func (s *MemoryAttendanceStore) UpdateAttendanceSession(session *models.AttendanceSession) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	session.UpdatedAt = time.Now()
+	s.sessions[session.ID] = *session
+	return nil
+}
+
+// GetAttendanceSessionByID retrieves an attendance session by ID
+func (s *MemoryAttendanceStore) GetAttendanceSessionByID(id uint) (*models.AttendanceSession, error) {
+	sessions := s.filterSessions(func(session models.AttendanceSession) bool { return session.ID == id })
+	if len(sessions) == 0 {
+		return &models.AttendanceSession{}, gorm.ErrRecordNotFound
+	}
+	return &sessions[0], nil
+}
+
+// ListActiveSessions lists all active attendance sessions for a lecturer
+func (s *MemoryAttendanceStore) ListActiveSessions(lecturerID uint) ([]models.AttendanceSession, error) {
+	return s.filterSessions(func(session models.AttendanceSession) bool {
+		return session.LecturerID == lecturerID && session.Status == models.AttendanceStatusActive
+	}), nil
+}
+
+// ListSessionsForUser returns one page of the sessions within a date range that a user started as
+// lecturer or that belong to the given course schedules
+func (s *MemoryAttendanceStore) ListSessionsForUser(lecturerID uint, scheduleIDs []uint, startDate, endDate time.Time, q models.ListQuery) (models.ListResult[models.AttendanceSession], error) {
+	schedules := idSet(scheduleIDs)
+	sessions := s.filterSessions(func(session models.AttendanceSession) bool {
+		return (session.LecturerID == lecturerID || schedules[session.CourseScheduleID]) &&
+			!session.Date.Before(startDate) && !session.Date.After(endDate)
+	})
+	return listSlice(sessions, attendanceSessionListSpec, q)
+}
+
+// ListSessionsByCourseSchedule lists attendance sessions for a specific course schedule
+func (s *MemoryAttendanceStore) ListSessionsByCourseSchedule(courseScheduleID uint) ([]models.AttendanceSession, error) {
+	sessions := s.filterSessions(func(session models.AttendanceSession) bool {
+		return session.CourseScheduleID == courseScheduleID
+	})
+	sortLatestFirst(sessions)
+	return sessions, nil
+}
+
+// GetActiveSessionForSchedule gets the active attendance session for a course schedule if it exists
+func (s *MemoryAttendanceStore) GetActiveSessionForSchedule(courseScheduleID uint, date time.Time) (*models.AttendanceSession, error) {
+	sessions := s.filterSessions(func(session models.AttendanceSession) bool {
+		return session.CourseScheduleID == courseScheduleID && session.Date.Equal(date) &&
+			session.Status == models.AttendanceStatusActive
+	})
+	if len(sessions) == 0 {
+		return &models.AttendanceSession{}, gorm.ErrRecordNotFound
+	}
+	return &sessions[0], nil
+}
+
+// GetActiveSessionsForSchedule gets all active attendance sessions for a schedule
+func (s *MemoryAttendanceStore) GetActiveSessionsForSchedule(courseScheduleID uint) ([]models.AttendanceSession, error) {
+	return s.filterSessions(func(session models.AttendanceSession) bool {
+		return session.CourseScheduleID == courseScheduleID && session.Status == models.AttendanceStatusActive
+	}), nil
+}
+
+// ListActiveSessionsByRoom gets all active attendance sessions held in a room
+func (s *MemoryAttendanceStore) ListActiveSessionsByRoom(roomID uint) ([]models.AttendanceSession, error) {
+	sessions := s.filterSessions(func(session models.AttendanceSession) bool {
+		return session.Status == models.AttendanceStatusActive
+	})
+
+	inRoom := []models.AttendanceSession{}
+	for _, session := range sessions {
+		if session.CourseSchedule.RoomID == roomID {
+			inRoom = append(inRoom, session)
+		}
+	}
+	sort.SliceStable(inRoom, func(i, j int) bool { return inRoom[i].StartTime.After(inRoom[j].StartTime) })
+	return inRoom, nil
+}
+
+// ListActiveSessionsStartedBefore lists active attendance sessions that started before the given time, oldest first
+func (s *MemoryAttendanceStore) ListActiveSessionsStartedBefore(before time.Time) ([]models.AttendanceSession, error) {
+	sessions := s.filterSessions(func(session models.AttendanceSession) bool {
+		return session.Status == models.AttendanceStatusActive && session.StartTime.Before(before)
+	})
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].StartTime.Before(sessions[j].StartTime) })
+	return sessions, nil
+}
+
+// CountActiveSessions counts the attendance sessions that are currently active
+func (s *MemoryAttendanceStore) CountActiveSessions() (int64, error) {
+	sessions := s.filterSessions(func(session models.AttendanceSession) bool {
+		return session.Status == models.AttendanceStatusActive
+	})
+	return int64(len(sessions)), nil
+}
+
+// CloseSessions closes the given attendance sessions if they are still active
+func (s *MemoryAttendanceStore) CloseSessions(ids []uint, endTime time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, id := range ids {
+		session, ok := s.sessions[id]
+		if !ok || session.Status != models.AttendanceStatusActive {
+			continue
+		}
+		closedAt := endTime
+		session.Status = models.AttendanceStatusClosed
+		session.EndTime = &closedAt
+		session.UpdatedAt = time.Now()
+		s.sessions[id] = session
+	}
+	return nil
+}
+
+// CreateStudentAttendance records a student's attendance
+func (s *MemoryAttendanceStore) CreateStudentAttendance(attendance *models.StudentAttendance) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if attendance.ID == 0 {
+		attendance.ID = s.nextAttendanceID
+	}
+	if attendance.ID >= s.nextAttendanceID {
+		s.nextAttendanceID = attendance.ID + 1
+	}
+	attendance.CreatedAt = now
+	attendance.UpdatedAt = now
+	s.attendances[attendance.ID] = *attendance
+	return nil
+}
+
+// UpdateStudentAttendance updates a student's attendance record
+func (s *MemoryAttendanceStore) UpdateStudentAttendance(attendance *models.StudentAttendance) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	attendance.UpdatedAt = time.Now()
+	s.attendances[attendance.ID] = *attendance
+	return nil
+}
+
+// GetStudentAttendance gets a student's attendance record for a session
+func (s *MemoryAttendanceStore) GetStudentAttendance(sessionID, studentID uint) (*models.StudentAttendance, error) {
+	attendances := s.filterAttendances(func(attendance models.StudentAttendance) bool {
+		return attendance.AttendanceSessionID == sessionID && attendance.StudentID == studentID
+	})
+	if len(attendances) == 0 {
+		return &models.StudentAttendance{}, gorm.ErrRecordNotFound
+	}
+	return &attendances[0], nil
+}
+
+// CheckInStudent records the check-in of a student in a session, the first check-in wins
+func (s *MemoryAttendanceStore) CheckInStudent(attendance *models.StudentAttendance) (*models.StudentAttendance, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for id, existing := range s.attendances {
+		if existing.AttendanceSessionID != attendance.AttendanceSessionID || existing.StudentID != attendance.StudentID {
+			continue
+		}
+		if existing.CheckInTime != nil {
+			return &existing, false, nil
+		}
+		existing.Status = attendance.Status
+		existing.VerificationMethod = attendance.VerificationMethod
+		existing.CheckInTime = attendance.CheckInTime
+		existing.Notes = attendance.Notes
+		existing.VerifiedByID = attendance.VerifiedByID
+		existing.UpdatedAt = now
+		s.attendances[id] = existing
+		return &existing, true, nil
+	}
+
+	attendance.ID = s.nextAttendanceID
+	s.nextAttendanceID++
+	attendance.CreatedAt = now
+	attendance.UpdatedAt = now
+	s.attendances[attendance.ID] = *attendance
+	stored := *attendance
+	return &stored, true, nil
+}
+
+// SessionBelongsToSchedule checks if an attendance session was opened for a course schedule
+func (s *MemoryAttendanceStore) SessionBelongsToSchedule(sessionID, courseScheduleID uint) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	session, ok := s.sessions[sessionID]
+	return ok && session.CourseScheduleID == courseScheduleID, nil
+}
+
+// ListStudentAttendances lists all student attendance records for a session
+func (s *MemoryAttendanceStore) ListStudentAttendances(sessionID uint) ([]models.StudentAttendance, error) {
+	return s.filterAttendances(func(attendance models.StudentAttendance) bool {
+		return attendance.AttendanceSessionID == sessionID
+	}), nil
+}
+
+// ListStudentAttendancesByStudent lists all attendance records of a student with their sessions, latest first
+func (s *MemoryAttendanceStore) ListStudentAttendancesByStudent(studentID uint) ([]models.StudentAttendance, error) {
+	attendances := s.filterAttendances(func(attendance models.StudentAttendance) bool {
+		return attendance.StudentID == studentID
+	})
+	sort.SliceStable(attendances, func(i, j int) bool {
+		return attendances[i].AttendanceSessionID > attendances[j].AttendanceSessionID
+	})
+	return attendances, nil
+}
+
+// ListStudentAttendanceHistory returns one page of the attendance records of a student
+func (s *MemoryAttendanceStore) ListStudentAttendanceHistory(studentID uint, q models.ListQuery) (models.ListResult[models.StudentAttendance], error) {
+	attendances := s.filterAttendances(func(attendance models.StudentAttendance) bool {
+		return attendance.StudentID == studentID
+	})
+	withSession := []models.StudentAttendance{}
+	for _, attendance := range attendances {
+		if attendance.AttendanceSession.ID != 0 {
+			withSession = append(withSession, attendance)
+		}
+	}
+	return listSlice(withSession, studentAttendanceHistoryListSpec, q)
+}
+
+// ListStudentAttendancesByStatus lists all student attendance records for a session filtered by status
+func (s *MemoryAttendanceStore) ListStudentAttendancesByStatus(sessionID uint, status models.StudentAttendanceStatus) ([]models.StudentAttendance, error) {
+	return s.filterAttendances(func(attendance models.StudentAttendance) bool {
+		return attendance.AttendanceSessionID == sessionID && attendance.Status == status
+	}), nil
+}
+
+// GetAttendanceStats gets attendance statistics for a course schedule
+func (s *MemoryAttendanceStore) GetAttendanceStats(courseScheduleID uint) (*models.AttendanceStatistics, error) {
+	schedule, ok := s.schedules.get(courseScheduleID)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	sessions, _ := s.ListSessionsByCourseSchedule(courseScheduleID)
+	stats := models.AttendanceStatistics{
+		TotalSessions: len(sessions),
+		TotalStudents: schedule.Enrolled,
+	}
+
+	sessionIDs := make([]uint, 0, len(sessions))
+	for _, session := range sessions {
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+	inSessions := idSet(sessionIDs)
+	attendances := s.filterAttendances(func(attendance models.StudentAttendance) bool {
+		return inSessions[attendance.AttendanceSessionID]
+	})
+	for _, attendance := range attendances {
+		switch attendance.Status {
+		case models.StudentAttendanceStatusPresent:
+			stats.TotalAttendance++
+		case models.StudentAttendanceStatusLate:
+			stats.TotalLate++
+		case models.StudentAttendanceStatusAbsent:
+			stats.TotalAbsent++
+		case models.StudentAttendanceStatusExcused:
+			stats.TotalExcused++
+		}
+	}
+
+	// Calculate average attendance percentage
+	if stats.TotalSessions > 0 && stats.TotalStudents > 0 {
+		totalPossibleAttendances := stats.TotalSessions * stats.TotalStudents
+		totalPresent := stats.TotalAttendance + stats.TotalLate
+		stats.AverageAttendance = (totalPresent * 100) / totalPossibleAttendances
+	}
+
+	return &stats, nil
+}
+
+// ListActiveSessionsBySchedules gets all active attendance sessions for given course schedule IDs
+func (s *MemoryAttendanceStore) ListActiveSessionsBySchedules(scheduleIDs []uint) ([]models.AttendanceSession, error) {
+	schedules := idSet(scheduleIDs)
+	return s.filterSessions(func(session models.AttendanceSession) bool {
+		return schedules[session.CourseScheduleID] && session.Status == models.AttendanceStatusActive
+	}), nil
+}
@@ -0,0 +1,319 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var _ CourseScheduleStore = (*MemoryCourseScheduleStore)(nil)
+
+// MemoryCourseScheduleStore is an in-process CourseScheduleStore
+// Related records such as Course and Room are returned as they were stored, nothing is preloaded
+type MemoryCourseScheduleStore struct {
+	schedules map[uint]models.CourseSchedule
+	deleted   map[uint]models.CourseSchedule
+	nextID    uint
+	mutex     sync.Mutex
+}
+
+// NewMemoryCourseScheduleStore creates a new in-memory course schedule store
+func NewMemoryCourseScheduleStore() *MemoryCourseScheduleStore {
+	return &MemoryCourseScheduleStore{
+		schedules: make(map[uint]models.CourseSchedule),
+		deleted:   make(map[uint]models.CourseSchedule),
+		nextID:    1,
+	}
+}
+
+// WithContext returns the store itself, it does not log
+func (s *MemoryCourseScheduleStore) WithContext(ctx context.Context) CourseScheduleStore {
+	return s
+}
+
+// get returns a course schedule by ID
+func (s *MemoryCourseScheduleStore) get(id uint) (models.CourseSchedule, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	schedule, ok := s.schedules[id]
+	return schedule, ok
+}
+
+// filter returns the course schedules matching keep, ordered by ID
+func (s *MemoryCourseScheduleStore) filter(keep func(models.CourseSchedule) bool) []models.CourseSchedule {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	schedules := []models.CourseSchedule{}
+	for _, schedule := range s.schedules {
+		if keep(schedule) {
+			schedules = append(schedules, schedule)
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].ID < schedules[j].ID })
+	return schedules
+}
+
+// update applies change to the course schedules matching keep
+func (s *MemoryCourseScheduleStore) update(keep func(models.CourseSchedule) bool, change func(*models.CourseSchedule)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for id, schedule := range s.schedules {
+		if keep(schedule) {
+			change(&schedule)
+			schedule.UpdatedAt = time.Now()
+			s.schedules[id] = schedule
+		}
+	}
+}
+
+// overlaps checks if a schedule overlaps the time range on a day, the same way the database store does
+func overlaps(schedule models.CourseSchedule, day, startTime, endTime string, scheduleID *uint) bool {
+	if schedule.Day != day || (scheduleID != nil && schedule.ID == *scheduleID) {
+		return false
+	}
+	return (schedule.StartTime < endTime && schedule.EndTime > startTime) ||
+		(schedule.StartTime >= startTime && schedule.EndTime <= endTime)
+}
+
+// GetAll returns all course schedules
+func (s *MemoryCourseScheduleStore) GetAll() ([]models.CourseSchedule, error) {
+	return s.filter(func(models.CourseSchedule) bool { return true }), nil
+}
+
+// List returns one page of course schedules
+func (s *MemoryCourseScheduleStore) List(q models.ListQuery) (models.ListResult[models.CourseSchedule], error) {
+	return listSlice(s.filter(func(models.CourseSchedule) bool { return true }), courseScheduleListSpec, q)
+}
+
+// GetByID returns a course schedule by its ID
+func (s *MemoryCourseScheduleStore) GetByID(id uint) (models.CourseSchedule, error) {
+	schedule, ok := s.get(id)
+	if !ok {
+		return models.CourseSchedule{}, gorm.ErrRecordNotFound
+	}
+	return schedule, nil
+}
+
+// Create creates a new course schedule
+func (s *MemoryCourseScheduleStore) Create(schedule models.CourseSchedule) (models.CourseSchedule, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if schedule.ID == 0 {
+		schedule.ID = s.nextID
+	}
+	if schedule.ID >= s.nextID {
+		s.nextID = schedule.ID + 1
+	}
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+	s.schedules[schedule.ID] = schedule
+	return schedule, nil
+}
+
+// Update updates an existing course schedule
+func (s *MemoryCourseScheduleStore) Update(schedule models.CourseSchedule) (models.CourseSchedule, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	existing, ok := s.schedules[schedule.ID]
+	if !ok {
+		return schedule, gorm.ErrRecordNotFound
+	}
+	schedule.CreatedAt = existing.CreatedAt
+	schedule.UpdatedAt = time.Now()
+	s.schedules[schedule.ID] = schedule
+	return schedule, nil
+}
+
+// Delete soft-deletes a course schedule
+func (s *MemoryCourseScheduleStore) Delete(id uint) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if schedule, ok := s.schedules[id]; ok {
+		schedule.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		s.deleted[id] = schedule
+		delete(s.schedules, id)
+	}
+	return nil
+}
+
+// GetByAcademicYear returns course schedules by academic year ID
+func (s *MemoryCourseScheduleStore) GetByAcademicYear(academicYearID uint) ([]models.CourseSchedule, error) {
+	return s.filter(func(schedule models.CourseSchedule) bool { return schedule.AcademicYearID == academicYearID }), nil
+}
+
+// GetByLecturer returns course schedules by lecturer ID
+// Unlike the database store it does not fall back to lecturer assignments
+func (s *MemoryCourseScheduleStore) GetByLecturer(userID uint) ([]models.CourseSchedule, error) {
+	return s.filter(func(schedule models.CourseSchedule) bool { return schedule.UserID == userID }), nil
+}
+
+// GetByLecturerAndAcademicYear returns course schedules by lecturer ID and academic year ID
+func (s *MemoryCourseScheduleStore) GetByLecturerAndAcademicYear(userID uint, academicYearID uint) ([]models.CourseSchedule, error) {
+	return s.filter(func(schedule models.CourseSchedule) bool {
+		return schedule.UserID == userID && schedule.AcademicYearID == academicYearID
+	}), nil
+}
+
+// GetByStudentGroup returns course schedules by student group ID
+func (s *MemoryCourseScheduleStore) GetByStudentGroup(studentGroupID uint) ([]models.CourseSchedule, error) {
+	return s.filter(func(schedule models.CourseSchedule) bool { return schedule.StudentGroupID == studentGroupID }), nil
+}
+
+// GetByRoom returns course schedules by room ID
+func (s *MemoryCourseScheduleStore) GetByRoom(roomID uint) ([]models.CourseSchedule, error) {
+	return s.filter(func(schedule models.CourseSchedule) bool { return schedule.RoomID == roomID }), nil
+}
+
+// GetByBuilding returns course schedules by the building of their stored room
+func (s *MemoryCourseScheduleStore) GetByBuilding(buildingID uint) ([]models.CourseSchedule, error) {
+	return s.filter(func(schedule models.CourseSchedule) bool { return schedule.Room.BuildingID == buildingID }), nil
+}
+
+// GetByCourse returns course schedules by course ID
+func (s *MemoryCourseScheduleStore) GetByCourse(courseID uint) ([]models.CourseSchedule, error) {
+	return s.filter(func(schedule models.CourseSchedule) bool { return schedule.CourseID == courseID }), nil
+}
+
+// GetByDay returns course schedules by day
+func (s *MemoryCourseScheduleStore) GetByDay(day string) ([]models.CourseSchedule, error) {
+	return s.filter(func(schedule models.CourseSchedule) bool { return schedule.Day == day }), nil
+}
+
+// CheckScheduleConflict checks if there's a room schedule conflict within an academic year
+func (s *MemoryCourseScheduleStore) CheckScheduleConflict(academicYearID, roomID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error) {
+	conflicts := s.filter(func(schedule models.CourseSchedule) bool {
+		return schedule.AcademicYearID == academicYearID && schedule.RoomID == roomID &&
+			overlaps(schedule, day, startTime, endTime, scheduleID)
+	})
+	return len(conflicts) > 0, nil
+}
+
+// CheckLecturerScheduleConflict checks if there's a lecturer schedule conflict within an academic year
+func (s *MemoryCourseScheduleStore) CheckLecturerScheduleConflict(academicYearID, userID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error) {
+	conflicts := s.filter(func(schedule models.CourseSchedule) bool {
+		return schedule.AcademicYearID == academicYearID && schedule.UserID == userID &&
+			overlaps(schedule, day, startTime, endTime, scheduleID)
+	})
+	return len(conflicts) > 0, nil
+}
+
+// CheckStudentGroupScheduleConflict checks if there's a student group schedule conflict within an academic year
+func (s *MemoryCourseScheduleStore) CheckStudentGroupScheduleConflict(academicYearID, studentGroupID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error) {
+	conflicts := s.filter(func(schedule models.CourseSchedule) bool {
+		return schedule.AcademicYearID == academicYearID && schedule.StudentGroupID == studentGroupID &&
+			overlaps(schedule, day, startTime, endTime, scheduleID)
+	})
+	return len(conflicts) > 0, nil
+}
+
+// UpdateSchedulesForCourseInAcademicYear updates all schedules for a course in an academic year to use the new lecturer ID
+func (s *MemoryCourseScheduleStore) UpdateSchedulesForCourseInAcademicYear(courseID, academicYearID, newUserID uint) error {
+	s.update(func(schedule models.CourseSchedule) bool {
+		return schedule.CourseID == courseID && schedule.AcademicYearID == academicYearID
+	}, func(schedule *models.CourseSchedule) { schedule.UserID = newUserID })
+	return nil
+}
+
+// UpdateSchedulesForCourse updates all schedules for a specific course to use a new lecturer
+func (s *MemoryCourseScheduleStore) UpdateSchedulesForCourse(courseID, lecturerID uint) error {
+	s.update(func(schedule models.CourseSchedule) bool { return schedule.CourseID == courseID },
+		func(schedule *models.CourseSchedule) { schedule.UserID = lecturerID })
+	return nil
+}
+
+// GetByCourseAndAcademicYear returns course schedules by course ID and academic year ID
+func (s *MemoryCourseScheduleStore) GetByCourseAndAcademicYear(courseID, academicYearID uint) ([]models.CourseSchedule, error) {
+	return s.filter(func(schedule models.CourseSchedule) bool {
+		return schedule.CourseID == courseID && schedule.AcademicYearID == academicYearID
+	}), nil
+}
+
+// UpdateSchedulesForRoom updates the capacity of all schedules associated with a room
+func (s *MemoryCourseScheduleStore) UpdateSchedulesForRoom(roomID uint, capacity int) error {
+	s.update(func(schedule models.CourseSchedule) bool { return schedule.RoomID == roomID },
+		func(schedule *models.CourseSchedule) { schedule.Capacity = capacity })
+	return nil
+}
+
+// GetByStudentGroupAndAcademicYear returns course schedules by student group ID and academic year ID
+func (s *MemoryCourseScheduleStore) GetByStudentGroupAndAcademicYear(studentGroupID uint, academicYearID uint) ([]models.CourseSchedule, error) {
+	return s.filter(func(schedule models.CourseSchedule) bool {
+		return schedule.StudentGroupID == studentGroupID && schedule.AcademicYearID == academicYearID
+	}), nil
+}
+
+// HasDuplicate checks if a schedule of the course already takes the same day and time in the academic year
+func (s *MemoryCourseScheduleStore) HasDuplicate(courseID uint, day, startTime, endTime string, academicYearID uint, scheduleID *uint) (bool, error) {
+	duplicates := s.filter(func(schedule models.CourseSchedule) bool {
+		return schedule.CourseID == courseID && schedule.Day == day && schedule.StartTime == startTime &&
+			schedule.EndTime == endTime && schedule.AcademicYearID == academicYearID &&
+			(scheduleID == nil || schedule.ID != *scheduleID)
+	})
+	return len(duplicates) > 0, nil
+}
+
+// GetEnrolledCounts returns every course schedule, ordered by ID
+func (s *MemoryCourseScheduleStore) GetEnrolledCounts() ([]models.CourseSchedule, error) {
+	return s.GetAll()
+}
+
+// UpdateEnrolledCounts sets the enrolled count of course schedules, keyed by schedule ID
+func (s *MemoryCourseScheduleStore) UpdateEnrolledCounts(counts map[uint]int) error {
+	s.update(func(schedule models.CourseSchedule) bool {
+		_, ok := counts[schedule.ID]
+		return ok
+	}, func(schedule *models.CourseSchedule) { schedule.Enrolled = counts[schedule.ID] })
+	return nil
+}
+
+// GetDeletedByCourse returns the most recently deleted course schedules of a course
+func (s *MemoryCourseScheduleStore) GetDeletedByCourse(courseID uint, limit int) ([]models.CourseSchedule, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	schedules := []models.CourseSchedule{}
+	for _, schedule := range s.deleted {
+		if schedule.CourseID == courseID {
+			schedules = append(schedules, schedule)
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].ID > schedules[j].ID })
+	if len(schedules) > limit {
+		schedules = schedules[:limit]
+	}
+	return schedules, nil
+}
+
+// GetCourseID returns the course ID of a course schedule
+func (s *MemoryCourseScheduleStore) GetCourseID(id uint) (uint, error) {
+	schedule, ok := s.get(id)
+	if !ok {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return schedule.CourseID, nil
+}
+
+// GetIDsByCourses returns the IDs of the course schedules of the given courses
+func (s *MemoryCourseScheduleStore) GetIDsByCourses(courseIDs []uint) ([]uint, error) {
+	courses := make(map[uint]bool, len(courseIDs))
+	for _, id := range courseIDs {
+		courses[id] = true
+	}
+
+	ids := []uint{}
+	for _, schedule := range s.filter(func(schedule models.CourseSchedule) bool { return courses[schedule.CourseID] }) {
+		ids = append(ids, schedule.ID)
+	}
+	return ids, nil
+}
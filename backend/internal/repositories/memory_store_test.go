@@ -0,0 +1,292 @@
+package repositories
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+)
+
+// The in-memory stores stand in for the GORM repositories in service tests, so both run
+// the same scenarios here: the GORM repositories on a migrated SQLite database.
+
+// testStores are the stores a scenario runs on
+type testStores struct {
+	students    StudentStore
+	groups      StudentGroupStore
+	schedules   CourseScheduleStore
+	attendances AttendanceStore
+}
+
+// storeImplementations returns the memory and the GORM implementation of the stores
+func storeImplementations() map[string]func(t *testing.T) testStores {
+	return map[string]func(t *testing.T) testStores{
+		"memory": func(t *testing.T) testStores {
+			students := NewMemoryStudentStore()
+			schedules := NewMemoryCourseScheduleStore()
+			return testStores{
+				students:    students,
+				groups:      NewMemoryStudentGroupStore(students),
+				schedules:   schedules,
+				attendances: NewMemoryAttendanceStore(schedules, students),
+			}
+		},
+		"gorm": func(t *testing.T) testStores {
+			openSQLite(t)
+			return testStores{
+				students:    NewStudentRepository(),
+				groups:      NewStudentGroupRepository(),
+				schedules:   NewCourseScheduleRepository(),
+				attendances: NewAttendanceRepository(),
+			}
+		},
+	}
+}
+
+// openSQLite connects the repositories to a migrated SQLite file
+func openSQLite(t *testing.T) {
+	t.Helper()
+	t.Setenv("DB_DRIVER", database.DriverSQLite)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "stores.db"))
+	if _, err := config.Load(); err != nil {
+		t.Logf("config: %v", err)
+	}
+	database.Initialize()
+	t.Cleanup(database.Close)
+	if err := database.MigrateUp(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+}
+
+// seedStudents stores students through a sync, as active like the sync services do, and returns them by NIM
+func seedStudents(t *testing.T, store StudentStore, nims ...string) map[string]models.Student {
+	t.Helper()
+	students := make([]models.Student, len(nims))
+	for i, nim := range nims {
+		students[i] = models.Student{
+			DimID: 1000 + i, UserID: 2000 + i, NIM: nim, FullName: "Student " + nim,
+			LifecycleStatus: models.LifecycleActive,
+		}
+	}
+	var result models.SyncResult
+	if err := store.UpsertMany(students, &result); err != nil {
+		t.Fatalf("upsert students: %v", err)
+	}
+
+	byNIM := make(map[string]models.Student, len(nims))
+	for _, nim := range nims {
+		student, err := store.FindByNIM(nim)
+		if err != nil || student == nil {
+			t.Fatalf("find student %s: %v", nim, err)
+		}
+		byNIM[nim] = *student
+	}
+	return byNIM
+}
+
+func TestStoresSyncAndDeactivateStudents(t *testing.T) {
+	for name, newStores := range storeImplementations() {
+		t.Run(name, func(t *testing.T) {
+			stores := newStores(t)
+			students := seedStudents(t, stores.students, "11S001", "11S002")
+
+			// A second sync of the same DimID updates the student instead of adding one
+			var result models.SyncResult
+			renamed := models.Student{DimID: 1000, UserID: 2000, NIM: "11S001", FullName: "Renamed", LifecycleStatus: models.LifecycleActive}
+			if err := stores.students.UpsertMany([]models.Student{renamed}, &result); err != nil {
+				t.Fatalf("upsert: %v", err)
+			}
+			if result.Inserted != 0 || result.Updated != 1 {
+				t.Errorf("got %d inserted and %d updated, want 0 and 1", result.Inserted, result.Updated)
+			}
+			byUser, err := stores.students.FindByUserID(2000)
+			if err != nil || byUser == nil || byUser.ID != students["11S001"].ID || byUser.FullName != "Renamed" {
+				t.Errorf("got %+v, %v, want the renamed student", byUser, err)
+			}
+
+			if err := stores.students.Deactivate([]uint{students["11S002"].ID}, "left CIS", time.Now()); err != nil {
+				t.Fatalf("deactivate: %v", err)
+			}
+			active, err := stores.students.FindActive()
+			if err != nil {
+				t.Fatalf("find active: %v", err)
+			}
+			if len(active) != 1 || active[0].NIM != "11S001" {
+				t.Errorf("got %d active students, want only 11S001", len(active))
+			}
+		})
+	}
+}
+
+func TestStoresGroupMembership(t *testing.T) {
+	for name, newStores := range storeImplementations() {
+		t.Run(name, func(t *testing.T) {
+			stores := newStores(t)
+			students := seedStudents(t, stores.students, "11S001", "11S002")
+			first, err := stores.groups.Create(models.StudentGroup{Name: "11IF1"})
+			if err != nil {
+				t.Fatalf("create group: %v", err)
+			}
+			second, err := stores.groups.Create(models.StudentGroup{Name: "11IF2"})
+			if err != nil {
+				t.Fatalf("create group: %v", err)
+			}
+
+			member := students["11S001"].ID
+			for _, groupID := range []uint{first.ID, second.ID} {
+				if err := stores.groups.AddStudentToGroup(groupID, member); err != nil {
+					t.Fatalf("add to group: %v", err)
+				}
+			}
+			if err := stores.groups.AddStudentToGroup(first.ID, member); err == nil {
+				t.Error("adding a member twice succeeded")
+			}
+
+			groupIDs, err := stores.groups.GetGroupIDsByStudentID(member)
+			if err != nil {
+				t.Fatalf("group IDs: %v", err)
+			}
+			sort.Slice(groupIDs, func(i, j int) bool { return groupIDs[i] < groupIDs[j] })
+			if len(groupIDs) != 2 || groupIDs[0] != first.ID || groupIDs[1] != second.ID {
+				t.Errorf("got groups %v, want [%d %d]", groupIDs, first.ID, second.ID)
+			}
+
+			if in, err := stores.groups.IsStudentInGroup(first.ID, students["11S002"].ID); err != nil || in {
+				t.Errorf("non-member in group: %t, %v", in, err)
+			}
+			if err := stores.groups.RemoveStudentFromGroup(first.ID, member); err != nil {
+				t.Fatalf("remove from group: %v", err)
+			}
+			if in, err := stores.groups.IsStudentInGroup(first.ID, member); err != nil || in {
+				t.Errorf("removed member in group: %t, %v", in, err)
+			}
+		})
+	}
+}
+
+func TestStoresScheduleConflicts(t *testing.T) {
+	for name, newStores := range storeImplementations() {
+		t.Run(name, func(t *testing.T) {
+			stores := newStores(t)
+			schedule, err := stores.schedules.Create(models.CourseSchedule{
+				CourseID: 1, RoomID: 1, UserID: 1, StudentGroupID: 1, AcademicYearID: 1,
+				Day: "senin", StartTime: "08:00", EndTime: "10:00",
+			})
+			if err != nil {
+				t.Fatalf("create schedule: %v", err)
+			}
+
+			cases := []struct {
+				name       string
+				start, end string
+				exclude    *uint
+				want       bool
+			}{
+				{"overlapping", "09:00", "11:00", nil, true},
+				{"adjacent", "10:00", "12:00", nil, false},
+				{"itself when updating", "08:00", "10:00", &schedule.ID, false},
+			}
+			for _, c := range cases {
+				conflict, err := stores.schedules.CheckScheduleConflict(1, 1, "senin", c.start, c.end, c.exclude)
+				if err != nil {
+					t.Fatalf("%s: %v", c.name, err)
+				}
+				if conflict != c.want {
+					t.Errorf("%s: got conflict %t, want %t", c.name, conflict, c.want)
+				}
+			}
+
+			if conflict, err := stores.schedules.CheckScheduleConflict(1, 2, "senin", "09:00", "11:00", nil); err != nil || conflict {
+				t.Errorf("other room: got conflict %t, %v", conflict, err)
+			}
+		})
+	}
+}
+
+func TestStoresFirstCheckInWins(t *testing.T) {
+	for name, newStores := range storeImplementations() {
+		t.Run(name, func(t *testing.T) {
+			stores := newStores(t)
+			students := seedStudents(t, stores.students, "11S001")
+			now := time.Now()
+			session := &models.AttendanceSession{
+				CourseScheduleID: 1, LecturerID: 1, Date: now, StartTime: now,
+				Type: models.AttendanceTypeQRCode, Status: models.AttendanceStatusActive,
+			}
+			if err := stores.attendances.CreateAttendanceSession(session); err != nil {
+				t.Fatalf("create session: %v", err)
+			}
+
+			checkIn := func(status models.StudentAttendanceStatus) (*models.StudentAttendance, bool) {
+				at := time.Now()
+				attendance, recorded, err := stores.attendances.CheckInStudent(&models.StudentAttendance{
+					AttendanceSessionID: session.ID,
+					StudentID:           students["11S001"].ID,
+					Status:              status,
+					CheckInTime:         &at,
+					VerificationMethod:  "QR_CODE",
+				})
+				if err != nil {
+					t.Fatalf("check in: %v", err)
+				}
+				return attendance, recorded
+			}
+
+			first, recorded := checkIn(models.StudentAttendanceStatusPresent)
+			if !recorded {
+				t.Fatal("first check-in was not recorded")
+			}
+			second, recorded := checkIn(models.StudentAttendanceStatusLate)
+			if recorded || second.ID != first.ID || second.Status != models.StudentAttendanceStatusPresent {
+				t.Errorf("second check-in: recorded %t, got %+v, want the first check-in", recorded, second)
+			}
+
+			attendances, err := stores.attendances.ListStudentAttendances(session.ID)
+			if err != nil {
+				t.Fatalf("list attendances: %v", err)
+			}
+			if len(attendances) != 1 {
+				t.Errorf("got %d attendance records, want 1", len(attendances))
+			}
+		})
+	}
+}
+
+func TestStoresLikeFilterMatchesWildcardsLiterally(t *testing.T) {
+	for name, newStores := range storeImplementations() {
+		t.Run(name, func(t *testing.T) {
+			stores := newStores(t)
+			var result models.SyncResult
+			students := []models.Student{
+				{DimID: 1, UserID: 1, NIM: "11S001", FullName: "Nilai 100% Lulus", LifecycleStatus: models.LifecycleActive},
+				{DimID: 2, UserID: 2, NIM: "11S002", FullName: "Nilai 1000 Lulus", LifecycleStatus: models.LifecycleActive},
+				{DimID: 3, UserID: 3, NIM: "11S003", FullName: "Putri_Sari", LifecycleStatus: models.LifecycleActive},
+				{DimID: 4, UserID: 4, NIM: "11S004", FullName: "PutriXSari", LifecycleStatus: models.LifecycleActive},
+			}
+			if err := stores.students.UpsertMany(students, &result); err != nil {
+				t.Fatalf("upsert students: %v", err)
+			}
+
+			cases := []struct{ pattern, want string }{
+				{"100%", "11S001"},
+				{"i_S", "11S003"},
+			}
+			for _, c := range cases {
+				page, err := stores.students.List(models.ListQuery{
+					Page: 1, PageSize: 10,
+					Filters: []models.ListFilter{{Field: "full_name", Op: "like", Values: []string{c.pattern}}},
+				})
+				if err != nil {
+					t.Fatalf("list %q: %v", c.pattern, err)
+				}
+				if len(page.Items) != 1 || page.Items[0].NIM != c.want {
+					t.Errorf("like %q: got %d students, want only %s", c.pattern, len(page.Items), c.want)
+				}
+			}
+		})
+	}
+}
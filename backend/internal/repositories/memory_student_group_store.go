@@ -0,0 +1,311 @@
+package repositories
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var _ StudentGroupStore = (*MemoryStudentGroupStore)(nil)
+
+// MemoryStudentGroupStore is an in-process StudentGroupStore
+// Members are looked up in the student store it was created with
+type MemoryStudentGroupStore struct {
+	students    *MemoryStudentStore
+	groups      map[uint]models.StudentGroup
+	memberships []models.StudentToGroup
+	nextID      uint
+	mutex       sync.Mutex
+}
+
+// NewMemoryStudentGroupStore creates a new in-memory student group store on top of a student store
+func NewMemoryStudentGroupStore(students *MemoryStudentStore) *MemoryStudentGroupStore {
+	store := &MemoryStudentGroupStore{
+		students: students,
+		groups:   make(map[uint]models.StudentGroup),
+		nextID:   1,
+	}
+	students.mutex.Lock()
+	students.groups = store
+	students.mutex.Unlock()
+	return store
+}
+
+// withCount sets the student count of a group, the caller holds the mutex
+func (s *MemoryStudentGroupStore) withCount(group models.StudentGroup) models.StudentGroup {
+	group.StudentCount = 0
+	for _, membership := range s.memberships {
+		if membership.StudentGroupID == group.ID {
+			group.StudentCount++
+		}
+	}
+	return group
+}
+
+// filterGroups returns the groups matching keep with their student count, ordered by ID
+func (s *MemoryStudentGroupStore) filterGroups(keep func(models.StudentGroup) bool) []models.StudentGroup {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	groups := []models.StudentGroup{}
+	for _, group := range s.groups {
+		if keep(group) {
+			groups = append(groups, s.withCount(group))
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return groups
+}
+
+// groupIDsOf returns the IDs of the groups a student belongs to by student ID
+func (s *MemoryStudentGroupStore) groupIDsOf(studentID uint) map[uint]bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ids := make(map[uint]bool)
+	for _, membership := range s.memberships {
+		if membership.StudentID == studentID {
+			ids[membership.StudentGroupID] = true
+		}
+	}
+	return ids
+}
+
+// memberUserIDs returns the user IDs of the members of a group
+func (s *MemoryStudentGroupStore) memberUserIDs(groupID uint) map[int]bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	userIDs := make(map[int]bool)
+	for _, membership := range s.memberships {
+		if membership.StudentGroupID == groupID {
+			userIDs[membership.UserID] = true
+		}
+	}
+	return userIDs
+}
+
+// GetAll returns all student groups
+func (s *MemoryStudentGroupStore) GetAll() ([]models.StudentGroup, error) {
+	return s.filterGroups(func(models.StudentGroup) bool { return true }), nil
+}
+
+// List returns one page of student groups with their student count
+func (s *MemoryStudentGroupStore) List(q models.ListQuery) (models.ListResult[models.StudentGroup], error) {
+	return listSlice(s.filterGroups(func(models.StudentGroup) bool { return true }), studentGroupListSpec, q)
+}
+
+// GetByID returns a student group by ID
+func (s *MemoryStudentGroupStore) GetByID(id uint) (*models.StudentGroup, error) {
+	groups := s.filterGroups(func(group models.StudentGroup) bool { return group.ID == id })
+	if len(groups) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &groups[0], nil
+}
+
+// GetByDepartment returns student groups filtered by department
+func (s *MemoryStudentGroupStore) GetByDepartment(departmentID uint) ([]models.StudentGroup, error) {
+	return s.filterGroups(func(group models.StudentGroup) bool { return group.DepartmentID == departmentID }), nil
+}
+
+// GetBySemester returns all student groups, groups have no semester
+func (s *MemoryStudentGroupStore) GetBySemester(semester int) ([]models.StudentGroup, error) {
+	return s.GetAll()
+}
+
+// Create creates a new student group
+func (s *MemoryStudentGroupStore) Create(group models.StudentGroup) (*models.StudentGroup, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if group.ID == 0 {
+		group.ID = s.nextID
+	}
+	if group.ID >= s.nextID {
+		s.nextID = group.ID + 1
+	}
+	group.CreatedAt = now
+	group.UpdatedAt = now
+	s.groups[group.ID] = group
+	return &group, nil
+}
+
+// Update updates an existing student group
+func (s *MemoryStudentGroupStore) Update(group models.StudentGroup) (*models.StudentGroup, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if existing, ok := s.groups[group.ID]; ok {
+		group.CreatedAt = existing.CreatedAt
+	}
+	group.UpdatedAt = time.Now()
+	s.groups[group.ID] = group
+	return &group, nil
+}
+
+// Delete deletes a student group and its memberships
+func (s *MemoryStudentGroupStore) Delete(id uint) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.removeMemberships(func(membership models.StudentToGroup) bool { return membership.StudentGroupID == id })
+	delete(s.groups, id)
+	return nil
+}
+
+// removeMemberships drops the memberships matching remove and returns how many, the caller holds the mutex
+func (s *MemoryStudentGroupStore) removeMemberships(remove func(models.StudentToGroup) bool) int {
+	kept := s.memberships[:0]
+	removed := 0
+	for _, membership := range s.memberships {
+		if remove(membership) {
+			removed++
+			continue
+		}
+		kept = append(kept, membership)
+	}
+	s.memberships = kept
+	return removed
+}
+
+// removeStudents drops all memberships of the given students
+func (s *MemoryStudentGroupStore) removeStudents(ids []uint) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	removed := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		removed[id] = true
+	}
+	s.removeMemberships(func(membership models.StudentToGroup) bool { return removed[membership.StudentID] })
+}
+
+// GetGroupMembers returns all students in a specific group, matched by user ID
+func (s *MemoryStudentGroupStore) GetGroupMembers(groupID uint) ([]models.Student, error) {
+	userIDs := s.memberUserIDs(groupID)
+	return s.students.filter(func(student models.Student) bool { return userIDs[student.UserID] }), nil
+}
+
+// AddStudentToGroup adds a student to a group
+func (s *MemoryStudentGroupStore) AddStudentToGroup(groupID, studentID uint) error {
+	student, ok := s.students.get(studentID)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if student.LifecycleStatus == models.LifecycleInactive {
+		return errors.New("student is no longer active")
+	}
+	if s.memberUserIDs(groupID)[student.UserID] {
+		return errors.New("student is already in this group")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.memberships = append(s.memberships, models.StudentToGroup{
+		StudentGroupID: groupID,
+		StudentID:      studentID,
+		UserID:         student.UserID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	return nil
+}
+
+// RemoveStudentFromGroup removes a student from a group
+func (s *MemoryStudentGroupStore) RemoveStudentFromGroup(groupID, studentID uint) error {
+	student, ok := s.students.get(studentID)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	removed := s.removeMemberships(func(membership models.StudentToGroup) bool {
+		return membership.StudentGroupID == groupID && membership.UserID == student.UserID
+	})
+	if removed == 0 {
+		return errors.New("student is not in this group")
+	}
+	return nil
+}
+
+// IsStudentInGroup checks if a student is already in a group, matched by user ID
+func (s *MemoryStudentGroupStore) IsStudentInGroup(groupID, studentID uint) (bool, error) {
+	student, ok := s.students.get(studentID)
+	if !ok {
+		return false, gorm.ErrRecordNotFound
+	}
+	return s.memberUserIDs(groupID)[student.UserID], nil
+}
+
+// GetStudentGroups returns all groups that a student belongs to
+func (s *MemoryStudentGroupStore) GetStudentGroups(studentID uint) ([]models.StudentGroup, error) {
+	groupIDs := s.groupIDsOf(studentID)
+	return s.filterGroups(func(group models.StudentGroup) bool { return groupIDs[group.ID] }), nil
+}
+
+// GetAvailableStudents returns all active students that are not in the group
+func (s *MemoryStudentGroupStore) GetAvailableStudents(groupID uint, departmentID uint) ([]models.Student, error) {
+	userIDs := s.memberUserIDs(groupID)
+	return s.students.filter(func(student models.Student) bool {
+		return student.LifecycleStatus == models.LifecycleActive && !userIDs[student.UserID]
+	}), nil
+}
+
+// GetGroupsByStudentID returns all student groups that a student belongs to
+func (s *MemoryStudentGroupStore) GetGroupsByStudentID(studentID uint) ([]models.StudentGroup, error) {
+	return s.GetStudentGroups(studentID)
+}
+
+// GetActiveMembersByStudentID returns the active students linked to a group by student ID
+func (s *MemoryStudentGroupStore) GetActiveMembersByStudentID(groupID uint) ([]models.Student, error) {
+	s.mutex.Lock()
+	studentIDs := make(map[uint]bool)
+	for _, membership := range s.memberships {
+		if membership.StudentGroupID == groupID {
+			studentIDs[membership.StudentID] = true
+		}
+	}
+	s.mutex.Unlock()
+
+	return s.students.filter(func(student models.Student) bool {
+		return studentIDs[student.ID] && student.LifecycleStatus == models.LifecycleActive
+	}), nil
+}
+
+// CountMembers counts the students in a group
+func (s *MemoryStudentGroupStore) CountMembers(groupID uint) (int64, error) {
+	counts, _ := s.CountMembersByGroup()
+	return int64(counts[groupID]), nil
+}
+
+// GetGroupIDsByStudentID returns the IDs of the groups a student belongs to
+func (s *MemoryStudentGroupStore) GetGroupIDsByStudentID(studentID uint) ([]uint, error) {
+	groupIDs := []uint{}
+	for id := range s.groupIDsOf(studentID) {
+		groupIDs = append(groupIDs, id)
+	}
+	sort.Slice(groupIDs, func(i, j int) bool { return groupIDs[i] < groupIDs[j] })
+	return groupIDs, nil
+}
+
+// CountMembersByGroup counts the students of every group that has any
+func (s *MemoryStudentGroupStore) CountMembersByGroup() (map[uint]int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	counts := make(map[uint]int)
+	for _, membership := range s.memberships {
+		counts[membership.StudentGroupID]++
+	}
+	return counts, nil
+}
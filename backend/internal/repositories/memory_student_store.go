@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var _ StudentStore = (*MemoryStudentStore)(nil)
+
+// MemoryStudentStore is an in-process StudentStore
+type MemoryStudentStore struct {
+	students map[uint]models.Student
+	nextID   uint
+	groups   *MemoryStudentGroupStore // Set by NewMemoryStudentGroupStore, so deactivation drops memberships
+	mutex    sync.Mutex
+}
+
+// NewMemoryStudentStore creates a new in-memory student store
+func NewMemoryStudentStore() *MemoryStudentStore {
+	return &MemoryStudentStore{
+		students: make(map[uint]models.Student),
+		nextID:   1,
+	}
+}
+
+// Add stores a student, assigning an ID when it has none, and returns it
+func (s *MemoryStudentStore) Add(student models.Student) models.Student {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.save(student)
+}
+
+// save stores a student, the caller holds the mutex
+func (s *MemoryStudentStore) save(student models.Student) models.Student {
+	now := time.Now()
+	if student.ID == 0 {
+		student.ID = s.nextID
+	}
+	if student.ID >= s.nextID {
+		s.nextID = student.ID + 1
+	}
+	if student.CreatedAt.IsZero() {
+		student.CreatedAt = now
+	}
+	if student.LifecycleStatus == "" {
+		student.LifecycleStatus = models.LifecycleActive
+	}
+	student.UpdatedAt = now
+	s.students[student.ID] = student
+	return student
+}
+
+// get returns a student by ID
+func (s *MemoryStudentStore) get(id uint) (models.Student, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	student, ok := s.students[id]
+	return student, ok
+}
+
+// filter returns the students matching keep, ordered by ID
+func (s *MemoryStudentStore) filter(keep func(models.Student) bool) []models.Student {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	students := []models.Student{}
+	for _, student := range s.students {
+		if keep(student) {
+			students = append(students, student)
+		}
+	}
+	sort.Slice(students, func(i, j int) bool { return students[i].ID < students[j].ID })
+	return students
+}
+
+// FindAll returns all students
+func (s *MemoryStudentStore) FindAll() ([]models.Student, error) {
+	return s.filter(func(models.Student) bool { return true }), nil
+}
+
+// List returns one page of students
+func (s *MemoryStudentStore) List(q models.ListQuery) (models.ListResult[models.Student], error) {
+	return listSlice(s.filter(func(models.Student) bool { return true }), studentListSpec, q)
+}
+
+// FindByID returns a student by ID
+func (s *MemoryStudentStore) FindByID(id uint) (*models.Student, error) {
+	student, ok := s.get(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &student, nil
+}
+
+// FindByNIM returns a student by NIM
+func (s *MemoryStudentStore) FindByNIM(nim string) (*models.Student, error) {
+	students := s.filter(func(student models.Student) bool { return student.NIM == nim })
+	if len(students) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &students[0], nil
+}
+
+// FindByUserID returns a student by external UserID from campus, or nil if there is none
+func (s *MemoryStudentStore) FindByUserID(userID int) (*models.Student, error) {
+	students := s.filter(func(student models.Student) bool { return student.UserID == userID })
+	if len(students) == 0 {
+		return nil, nil
+	}
+	return &students[0], nil
+}
+
+// ExistsByID checks if a student with the given ID exists
+func (s *MemoryStudentStore) ExistsByID(id uint) (bool, error) {
+	_, ok := s.get(id)
+	return ok, nil
+}
+
+// UpsertMany creates or updates multiple students, matched by DimID
+func (s *MemoryStudentStore) UpsertMany(students []models.Student, result *models.SyncResult) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	byDimID := make(map[int]models.Student, len(s.students))
+	for _, student := range s.students {
+		byDimID[student.DimID] = student
+	}
+
+	for _, student := range students {
+		existing, ok := byDimID[student.DimID]
+		if !ok {
+			student.ID = 0
+			byDimID[student.DimID] = s.save(student)
+			result.Inserted++
+			continue
+		}
+
+		student.ID = existing.ID
+		student.CreatedAt = existing.CreatedAt
+		byDimID[student.DimID] = s.save(student)
+		result.Updated++
+	}
+	return nil
+}
+
+// FindActive returns all students that have not been deactivated
+func (s *MemoryStudentStore) FindActive() ([]models.Student, error) {
+	return s.filter(func(student models.Student) bool {
+		return student.LifecycleStatus == models.LifecycleActive
+	}), nil
+}
+
+// Deactivate marks students as inactive and removes them from their groups
+// Unlike the database store no membership history is kept
+func (s *MemoryStudentStore) Deactivate(ids []uint, reason string, at time.Time) error {
+	s.mutex.Lock()
+	for _, id := range ids {
+		student, ok := s.students[id]
+		if !ok {
+			continue
+		}
+		deactivatedAt := at
+		student.LifecycleStatus = models.LifecycleInactive
+		student.DeactivatedAt = &deactivatedAt
+		student.InactiveReason = reason
+		s.students[id] = student
+	}
+	groups := s.groups
+	s.mutex.Unlock()
+
+	if groups != nil {
+		groups.removeStudents(ids)
+	}
+	return nil
+}
@@ -18,6 +18,26 @@ func NewRoomRepository() *RoomRepository {
 	}
 }
 
+// roomListSpec lists rooms with their building, see ListSpec
+var roomListSpec = ListSpec[models.Room]{
+	Fields: map[string]ListField[models.Room]{
+		"id":          {Column: "rooms.id", Value: func(r models.Room) interface{} { return r.ID }},
+		"code":        {Column: "rooms.code", Value: func(r models.Room) interface{} { return r.Code }},
+		"name":        {Column: "rooms.name", Value: func(r models.Room) interface{} { return r.Name }},
+		"building_id": {Column: "rooms.building_id", Value: func(r models.Room) interface{} { return r.BuildingID }},
+		"floor":       {Column: "rooms.floor", Value: func(r models.Room) interface{} { return r.Floor }},
+		"capacity":    {Column: "rooms.capacity", Value: func(r models.Room) interface{} { return r.Capacity }},
+		"created_at":  {Column: "rooms.created_at", Value: func(r models.Room) interface{} { return r.CreatedAt }},
+	},
+	Preloads:    []string{"Building"},
+	DefaultSort: []models.ListSort{{Field: "code"}},
+}
+
+// List returns one page of rooms with their building
+func (r *RoomRepository) List(q models.ListQuery) (models.ListResult[models.Room], error) {
+	return listRecords(r.db, roomListSpec, q)
+}
+
 // Create creates a new room
 func (r *RoomRepository) Create(room *models.Room) error {
 	return r.db.Create(room).Error
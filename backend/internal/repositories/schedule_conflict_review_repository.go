@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScheduleConflictReviewRepository handles database operations for schedule conflict reviews
+type ScheduleConflictReviewRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduleConflictReviewRepository creates a new schedule conflict review repository
+func NewScheduleConflictReviewRepository() *ScheduleConflictReviewRepository {
+	return &ScheduleConflictReviewRepository{
+		db: database.GetDB(),
+	}
+}
+
+// FindByAcademicYear returns the conflict reviews of an academic year
+func (r *ScheduleConflictReviewRepository) FindByAcademicYear(academicYearID uint) ([]models.ScheduleConflictReview, error) {
+	var reviews []models.ScheduleConflictReview
+	err := r.db.Where("academic_year_id = ?", academicYearID).Find(&reviews).Error
+	return reviews, err
+}
+
+// Save creates or updates the review of a pair of schedules
+func (r *ScheduleConflictReviewRepository) Save(review *models.ScheduleConflictReview) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "academic_year_id"}, {Name: "type"}, {Name: "schedule_id"}, {Name: "other_schedule_id"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"slot", "status", "note", "reviewed_by", "updated_at"}),
+	}).Create(review).Error
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScheduleExceptionRepository handles database operations for cancelled and rescheduled classes
+type ScheduleExceptionRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduleExceptionRepository creates a new schedule exception repository
+func NewScheduleExceptionRepository() *ScheduleExceptionRepository {
+	return &ScheduleExceptionRepository{
+		db: database.GetDB(),
+	}
+}
+
+// FindBySchedules returns the exceptions of the given schedules by date
+func (r *ScheduleExceptionRepository) FindBySchedules(scheduleIDs []uint) ([]models.ScheduleException, error) {
+	var exceptions []models.ScheduleException
+	if len(scheduleIDs) == 0 {
+		return exceptions, nil
+	}
+	err := r.db.Where("course_schedule_id IN ?", scheduleIDs).Order("date, id").Find(&exceptions).Error
+	return exceptions, err
+}
+
+// FindByNewRoom returns the rescheduled classes moved to a room
+func (r *ScheduleExceptionRepository) FindByNewRoom(roomID uint) ([]models.ScheduleException, error) {
+	var exceptions []models.ScheduleException
+	err := r.db.Where("kind = ? AND new_room_id = ?", models.ScheduleExceptionRescheduled, roomID).
+		Order("new_date, id").Find(&exceptions).Error
+	return exceptions, err
+}
+
+// FindOnDate returns the exceptions of classes regularly held on a date and of replacement sessions on it
+func (r *ScheduleExceptionRepository) FindOnDate(date string) ([]models.ScheduleException, error) {
+	var exceptions []models.ScheduleException
+	err := r.db.Where("date = ? OR new_date = ?", date, date).Order("id").Find(&exceptions).Error
+	return exceptions, err
+}
+
+// FindByID returns an exception by ID, or nil if there is none
+func (r *ScheduleExceptionRepository) FindByID(id uint) (*models.ScheduleException, error) {
+	var exception models.ScheduleException
+	err := r.db.First(&exception, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &exception, nil
+}
+
+// Save creates the exception of a schedule on a date, or replaces the one that exists
+func (r *ScheduleExceptionRepository) Save(exception *models.ScheduleException) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "course_schedule_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"kind", "new_date", "new_start_time", "new_end_time", "new_room_id", "note", "created_by", "updated_at",
+		}),
+	}).Create(exception).Error
+}
+
+// Delete removes an exception
+func (r *ScheduleExceptionRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ScheduleException{}, id).Error
+}
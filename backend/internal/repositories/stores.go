@@ -0,0 +1,445 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+)
+
+// Services depend on these stores rather than on the gorm repositories, so they
+// can also run on top of the in-memory stores
+
+// AcademicYearStore is implemented by AcademicYearRepository
+type AcademicYearStore interface {
+	Create(academicYear *models.AcademicYear) error
+	Update(academicYear *models.AcademicYear) error
+	FindByID(id uint) (*models.AcademicYear, error)
+	FindByName(name string) (*models.AcademicYear, error)
+	FindByNameAndSemester(name string, semester string) (*models.AcademicYear, error)
+	FindByNameIncludingDeleted(name string) (*models.AcademicYear, error)
+	FindAll() ([]models.AcademicYear, error)
+	List(q models.ListQuery) (models.ListResult[models.AcademicYear], error)
+	DeleteByID(id uint) error
+	RestoreSoftDeletedByName(name string, newData *models.AcademicYear) (*models.AcademicYear, error)
+	GetActiveAcademicYear() (*models.AcademicYear, error)
+	FindDeletedByName(name string) (*models.AcademicYear, error)
+	RestoreByName(name string) (*models.AcademicYear, error)
+	CheckNameExists(name string, excludeID uint) (bool, error)
+	CountCourses(id uint) (int64, error)
+	CountLecturerAssignments(id uint) (int64, error)
+	CountCourseSchedules(id uint) (int64, error)
+}
+
+// APIKeyStore is implemented by APIKeyRepository
+type APIKeyStore interface {
+	Create(key *models.APIKey) error
+	Update(key *models.APIKey) error
+	FindAll() ([]models.APIKey, error)
+	List(q models.ListQuery) (models.ListResult[models.APIKey], error)
+	FindByID(id uint) (*models.APIKey, error)
+	FindByPrefix(prefix string) (*models.APIKey, error)
+	TouchLastUsed(id uint, usedAt time.Time, ip string) error
+}
+
+// AttendanceStore is implemented by AttendanceRepository
+type AttendanceStore interface {
+	WithContext(ctx context.Context) AttendanceStore
+	CreateAttendanceSession(session *models.AttendanceSession) error
+	UpdateAttendanceSession(session *models.AttendanceSession) error
+	GetAttendanceSessionByID(id uint) (*models.AttendanceSession, error)
+	ListActiveSessions(lecturerID uint) ([]models.AttendanceSession, error)
+	ListSessionsForUser(lecturerID uint, scheduleIDs []uint, startDate, endDate time.Time, q models.ListQuery) (models.ListResult[models.AttendanceSession], error)
+	ListSessionsByCourseSchedule(courseScheduleID uint) ([]models.AttendanceSession, error)
+	GetActiveSessionForSchedule(courseScheduleID uint, date time.Time) (*models.AttendanceSession, error)
+	GetActiveSessionsForSchedule(courseScheduleID uint) ([]models.AttendanceSession, error)
+	ListActiveSessionsByRoom(roomID uint) ([]models.AttendanceSession, error)
+	ListActiveSessionsStartedBefore(before time.Time) ([]models.AttendanceSession, error)
+	CountActiveSessions() (int64, error)
+	CloseSessions(ids []uint, endTime time.Time) error
+	CreateStudentAttendance(attendance *models.StudentAttendance) error
+	UpdateStudentAttendance(attendance *models.StudentAttendance) error
+	GetStudentAttendance(sessionID, studentID uint) (*models.StudentAttendance, error)
+	CheckInStudent(attendance *models.StudentAttendance) (*models.StudentAttendance, bool, error)
+	SessionBelongsToSchedule(sessionID, courseScheduleID uint) (bool, error)
+	ListStudentAttendances(sessionID uint) ([]models.StudentAttendance, error)
+	ListStudentAttendancesByStudent(studentID uint) ([]models.StudentAttendance, error)
+	ListStudentAttendanceHistory(studentID uint, q models.ListQuery) (models.ListResult[models.StudentAttendance], error)
+	ListStudentAttendancesByStatus(sessionID uint, status models.StudentAttendanceStatus) ([]models.StudentAttendance, error)
+	GetAttendanceStats(courseScheduleID uint) (*models.AttendanceStatistics, error)
+	ListActiveSessionsBySchedules(scheduleIDs []uint) ([]models.AttendanceSession, error)
+}
+
+// AuditLogStore is implemented by AuditLogRepository
+type AuditLogStore interface {
+	Create(entry *models.AuditLog) error
+	List(q models.ListQuery) (models.ListResult[models.AuditLog], error)
+}
+
+// BuildingStore is implemented by BuildingRepository
+type BuildingStore interface {
+	Create(building *models.Building) error
+	Update(building *models.Building) error
+	FindByID(id uint) (*models.Building, error)
+	FindByCode(code string) (*models.Building, error)
+	FindAll() ([]models.Building, error)
+	List(q models.ListQuery) (models.ListResult[models.Building], error)
+	DeleteByID(id uint) error
+	CountRooms(buildingID uint) (int64, error)
+	FindDeletedByCode(code string) (*models.Building, error)
+	RestoreByCode(code string) (*models.Building, error)
+	CheckCodeExists(code string, excludeID uint) (bool, error)
+}
+
+// CalendarFeedStore is implemented by CalendarFeedRepository
+type CalendarFeedStore interface {
+	Create(feed *models.CalendarFeed) error
+	FindByID(id uint) (*models.CalendarFeed, error)
+	FindActiveByTokenHash(tokenHash string) (*models.CalendarFeed, error)
+	FindActive(kind models.CalendarFeedKind, subjectID uint) ([]models.CalendarFeed, error)
+	Revoke(id uint, revokedAt time.Time) error
+	RevokeSubject(kind models.CalendarFeedKind, subjectID uint, revokedAt time.Time) error
+	TouchLastAccessed(id uint, accessedAt time.Time) error
+}
+
+// CampusCredentialStore is implemented by CampusCredentialRepository
+type CampusCredentialStore interface {
+	FindByUsername(username string) (*models.CampusCredential, error)
+	Save(credential *models.CampusCredential) error
+	DeleteByUsername(username string) error
+	DeleteByExternalUserID(externalUserID int) error
+}
+
+// CourseStore is implemented by CourseRepository
+type CourseStore interface {
+	GetAll() ([]models.Course, error)
+	List(q models.ListQuery) (models.ListResult[models.Course], error)
+	GetByID(id uint) (models.Course, error)
+	FindByID(id uint) (*models.Course, error)
+	Create(course models.Course) (models.Course, error)
+	Update(course models.Course) (models.Course, error)
+	Delete(id uint) error
+	GetByDepartment(departmentID uint) ([]models.Course, error)
+	GetByAcademicYear(academicYearID uint) ([]models.Course, error)
+	GetBySemester(semester int) ([]models.Course, error)
+	GetByActiveAcademicYear() ([]models.Course, error)
+	FindDeletedByCode(code string) (*models.Course, error)
+	RestoreByCode(code string) (*models.Course, error)
+	CheckCodeExists(code string, excludeID uint) (bool, error)
+}
+
+// CourseScheduleStore is implemented by CourseScheduleRepository
+type CourseScheduleStore interface {
+	WithContext(ctx context.Context) CourseScheduleStore
+	GetAll() ([]models.CourseSchedule, error)
+	List(q models.ListQuery) (models.ListResult[models.CourseSchedule], error)
+	GetByID(id uint) (models.CourseSchedule, error)
+	Create(schedule models.CourseSchedule) (models.CourseSchedule, error)
+	Update(schedule models.CourseSchedule) (models.CourseSchedule, error)
+	Delete(id uint) error
+	GetByAcademicYear(academicYearID uint) ([]models.CourseSchedule, error)
+	GetByLecturer(userID uint) ([]models.CourseSchedule, error)
+	GetByLecturerAndAcademicYear(userID uint, academicYearID uint) ([]models.CourseSchedule, error)
+	GetByStudentGroup(studentGroupID uint) ([]models.CourseSchedule, error)
+	GetByRoom(roomID uint) ([]models.CourseSchedule, error)
+	GetByBuilding(buildingID uint) ([]models.CourseSchedule, error)
+	GetByCourse(courseID uint) ([]models.CourseSchedule, error)
+	GetByDay(day string) ([]models.CourseSchedule, error)
+	CheckScheduleConflict(academicYearID, roomID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error)
+	CheckLecturerScheduleConflict(academicYearID, userID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error)
+	CheckStudentGroupScheduleConflict(academicYearID, studentGroupID uint, day string, startTime, endTime string, scheduleID *uint) (bool, error)
+	UpdateSchedulesForCourseInAcademicYear(courseID, academicYearID, newUserID uint) error
+	UpdateSchedulesForCourse(courseID, lecturerID uint) error
+	GetByCourseAndAcademicYear(courseID, academicYearID uint) ([]models.CourseSchedule, error)
+	UpdateSchedulesForRoom(roomID uint, capacity int) error
+	GetByStudentGroupAndAcademicYear(studentGroupID uint, academicYearID uint) ([]models.CourseSchedule, error)
+	HasDuplicate(courseID uint, day, startTime, endTime string, academicYearID uint, scheduleID *uint) (bool, error)
+	GetEnrolledCounts() ([]models.CourseSchedule, error)
+	UpdateEnrolledCounts(counts map[uint]int) error
+	GetDeletedByCourse(courseID uint, limit int) ([]models.CourseSchedule, error)
+	GetCourseID(id uint) (uint, error)
+	GetIDsByCourses(courseIDs []uint) ([]uint, error)
+}
+
+// ScheduleConflictReviewStore is implemented by ScheduleConflictReviewRepository
+type ScheduleConflictReviewStore interface {
+	FindByAcademicYear(academicYearID uint) ([]models.ScheduleConflictReview, error)
+	Save(review *models.ScheduleConflictReview) error
+}
+
+// ScheduleExceptionStore is implemented by ScheduleExceptionRepository
+type ScheduleExceptionStore interface {
+	FindBySchedules(scheduleIDs []uint) ([]models.ScheduleException, error)
+	FindByNewRoom(roomID uint) ([]models.ScheduleException, error)
+	FindOnDate(date string) ([]models.ScheduleException, error)
+	FindByID(id uint) (*models.ScheduleException, error)
+	Save(exception *models.ScheduleException) error
+	Delete(id uint) error
+}
+
+// EmployeeStore is implemented by EmployeeRepository
+type EmployeeStore interface {
+	FindAll() ([]models.Employee, error)
+	List(q models.ListQuery) (models.ListResult[models.Employee], error)
+	FindByID(id uint) (*models.Employee, error)
+	FindByNIP(nip string) (*models.Employee, error)
+	FindByUserID(userID int) (*models.Employee, error)
+	UpsertMany(employees []models.Employee, result *models.SyncResult) error
+	Create(employee *models.Employee) error
+	Update(employee *models.Employee) error
+	Delete(id uint) error
+	FindActive() ([]models.Employee, error)
+	Deactivate(ids []uint, reason string, at time.Time) error
+}
+
+// FacultyStore is implemented by FacultyRepository
+type FacultyStore interface {
+	Create(faculty *models.Faculty) error
+	Update(faculty *models.Faculty) error
+	FindByID(id uint) (*models.Faculty, error)
+	FindByCode(code string) (*models.Faculty, error)
+	FindDeletedByCode(code string) (*models.Faculty, error)
+	RestoreByCode(code string) (*models.Faculty, error)
+	CheckCodeExists(code string, excludeID uint) (bool, error)
+	FindAll() ([]models.Faculty, error)
+	List(q models.ListQuery) (models.ListResult[models.Faculty], error)
+	DeleteByID(id uint) error
+	GetFacultyStats(facultyID uint) (map[string]int64, error)
+	CountStudyPrograms(facultyID uint) (int64, error)
+}
+
+// HolidayStore is implemented by HolidayRepository
+type HolidayStore interface {
+	FindAll() ([]models.Holiday, error)
+	List(q models.ListQuery) (models.ListResult[models.Holiday], error)
+	FindByID(id uint) (*models.Holiday, error)
+	Create(holiday *models.Holiday) error
+	Delete(id uint) error
+}
+
+// IntegrityStore is implemented by IntegrityRepository
+type IntegrityStore interface {
+	FindViolations(staleBefore time.Time) ([]models.IntegrityIssue, error)
+}
+
+// IdempotencyStore is implemented by IdempotencyRepository
+type IdempotencyStore interface {
+	Find(userID uint, route, key string) (*models.IdempotencyRecord, error)
+	Create(record *models.IdempotencyRecord) (bool, error)
+	Complete(id uint, statusCode int, responseBody string) error
+	Delete(id uint) error
+	DeleteCreatedBefore(before time.Time) error
+}
+
+// LecturerAvailabilityStore is implemented by LecturerAvailabilityRepository
+type LecturerAvailabilityStore interface {
+	FindByLecturer(userID uint) ([]models.LecturerAvailability, error)
+	FindByID(id uint) (*models.LecturerAvailability, error)
+	Create(window *models.LecturerAvailability) error
+	Delete(id uint) error
+}
+
+// LecturerAssignmentStore is implemented by LecturerAssignmentRepository
+type LecturerAssignmentStore interface {
+	GetAll(academicYearID uint) ([]models.LecturerAssignment, error)
+	GetByID(id uint) (models.LecturerAssignment, error)
+	GetByLecturerID(userID int, academicYearID uint) ([]models.LecturerAssignment, error)
+	GetByCourseID(courseID, academicYearID uint) ([]models.LecturerAssignment, error)
+	Create(assignment *models.LecturerAssignment) error
+	Update(assignment *models.LecturerAssignment) error
+	Delete(id uint) error
+	AssignmentExists(userID int, courseID, academicYearID uint) (bool, error)
+	AssignmentExistsForCourse(userID int, courseID uint) (bool, error)
+	GetAvailableLecturers(courseID, academicYearID uint) ([]models.Lecturer, error)
+	GetLecturerAssignmentResponses(academicYearID uint) ([]models.LecturerAssignmentResponse, error)
+	ListResponses(q models.ListQuery) (models.ListResult[models.LecturerAssignmentResponse], error)
+	GetLecturerAssignmentResponseByID(id uint) (*models.LecturerAssignmentResponse, error)
+}
+
+// LecturerStore is implemented by LecturerRepository
+type LecturerStore interface {
+	List(q models.ListQuery) (models.ListResult[models.Lecturer], error)
+	Create(lecturer *models.Lecturer) error
+	Update(lecturer *models.Lecturer) error
+	FindByID(id uint) (*models.Lecturer, error)
+	FindByLecturerID(lecturerID int) (*models.Lecturer, error)
+	FindAll() ([]models.Lecturer, error)
+	DeleteByID(id uint) error
+	Upsert(lecturer *models.Lecturer) error
+	UpsertMany(lecturers []models.Lecturer, result *models.SyncResult) error
+	Search(query string) ([]models.Lecturer, error)
+	GetByUserID(userID int) (models.Lecturer, error)
+	GetByID(id uint) (models.Lecturer, error)
+	FindActive() ([]models.Lecturer, error)
+	Deactivate(ids []uint, reason string, at time.Time) error
+}
+
+// RoomStore is implemented by RoomRepository
+type RoomStore interface {
+	Create(room *models.Room) error
+	Update(room *models.Room) error
+	FindByID(id uint) (*models.Room, error)
+	FindByCode(code string) (*models.Room, error)
+	FindAll() ([]models.Room, error)
+	List(q models.ListQuery) (models.ListResult[models.Room], error)
+	FindByBuildingID(buildingID uint) ([]models.Room, error)
+	DeleteByID(id uint) error
+	FindDeletedByCode(code string) (*models.Room, error)
+	RestoreByCode(code string) (*models.Room, error)
+	CheckCodeExists(code string, excludeID uint) (bool, error)
+}
+
+// StudentGroupStore is implemented by StudentGroupRepository
+type StudentGroupStore interface {
+	GetAll() ([]models.StudentGroup, error)
+	List(q models.ListQuery) (models.ListResult[models.StudentGroup], error)
+	GetByID(id uint) (*models.StudentGroup, error)
+	GetByDepartment(departmentID uint) ([]models.StudentGroup, error)
+	GetBySemester(semester int) ([]models.StudentGroup, error)
+	Create(group models.StudentGroup) (*models.StudentGroup, error)
+	Update(group models.StudentGroup) (*models.StudentGroup, error)
+	Delete(id uint) error
+	GetGroupMembers(groupID uint) ([]models.Student, error)
+	AddStudentToGroup(groupID, studentID uint) error
+	RemoveStudentFromGroup(groupID, studentID uint) error
+	IsStudentInGroup(groupID, studentID uint) (bool, error)
+	GetStudentGroups(studentID uint) ([]models.StudentGroup, error)
+	GetAvailableStudents(groupID uint, departmentID uint) ([]models.Student, error)
+	GetGroupsByStudentID(studentID uint) ([]models.StudentGroup, error)
+	GetActiveMembersByStudentID(groupID uint) ([]models.Student, error)
+	CountMembers(groupID uint) (int64, error)
+	GetGroupIDsByStudentID(studentID uint) ([]uint, error)
+	CountMembersByGroup() (map[uint]int, error)
+}
+
+// StudentStore is implemented by StudentRepository
+type StudentStore interface {
+	FindAll() ([]models.Student, error)
+	List(q models.ListQuery) (models.ListResult[models.Student], error)
+	FindByID(id uint) (*models.Student, error)
+	FindByNIM(nim string) (*models.Student, error)
+	FindByUserID(userID int) (*models.Student, error)
+	ExistsByID(id uint) (bool, error)
+	UpsertMany(students []models.Student, result *models.SyncResult) error
+	FindActive() ([]models.Student, error)
+	Deactivate(ids []uint, reason string, at time.Time) error
+}
+
+// StudyProgramStore is implemented by StudyProgramRepository
+type StudyProgramStore interface {
+	Create(program *models.StudyProgram) error
+	Update(program *models.StudyProgram) error
+	FindByID(id uint) (*models.StudyProgram, error)
+	FindByCode(code string) (*models.StudyProgram, error)
+	FindDeletedByCode(code string) (*models.StudyProgram, error)
+	RestoreByCode(code string) (*models.StudyProgram, error)
+	CheckCodeExists(code string, excludeID uint) (bool, error)
+	FindAll() ([]models.StudyProgram, error)
+	List(q models.ListQuery) (models.ListResult[models.StudyProgram], error)
+	FindByFacultyID(facultyID uint) ([]models.StudyProgram, error)
+	DeleteByID(id uint) error
+	GetStudyProgramStats(programID uint) (map[string]int64, error)
+}
+
+// SyncPreviewStore is implemented by SyncPreviewRepository
+type SyncPreviewStore interface {
+	Create(preview *models.SyncPreviewRecord) error
+	FindActive(id string, now time.Time) (*models.SyncPreviewRecord, error)
+	Delete(id string) error
+	DeleteExpired(now time.Time) error
+}
+
+// SyncRunStore is implemented by SyncRunRepository
+type SyncRunStore interface {
+	Create(run *models.SyncRun) error
+	Finish(run *models.SyncRun) error
+	FindByID(id uint) (*models.SyncRun, error)
+	FindRecent(entity, status string, limit int) ([]models.SyncRun, error)
+	List(q models.ListQuery) (models.ListResult[models.SyncRun], error)
+	FindLastSuccess(entity string) (*models.SyncRun, error)
+	FindLatest(entity string) (*models.SyncRun, error)
+	TryLock(entity string) (func(), bool, error)
+	MarkInterrupted(entity string) error
+}
+
+// TeachingAssistantAssignmentStore is implemented by TeachingAssistantAssignmentRepository
+type TeachingAssistantAssignmentStore interface {
+	GetAll(academicYearID uint) ([]models.TeachingAssistantAssignment, error)
+	GetByID(id uint) (models.TeachingAssistantAssignment, error)
+	GetByEmployeeID(employeeID uint, academicYearID uint) ([]models.TeachingAssistantAssignment, error)
+	GetByCourseID(courseID uint, academicYearID uint) ([]models.TeachingAssistantAssignment, error)
+	GetByLecturerID(lecturerID uint, academicYearID uint) ([]models.TeachingAssistantAssignment, error)
+	Create(assignment models.TeachingAssistantAssignment) (models.TeachingAssistantAssignment, error)
+	Update(assignment models.TeachingAssistantAssignment) (models.TeachingAssistantAssignment, error)
+	Delete(id uint) error
+	AssignmentExistsForCourse(userID int, courseID uint) (bool, error)
+	GetAvailableTeachingAssistants(courseID, academicYearID uint) ([]models.Employee, error)
+	GetTeachingAssistantAssignmentResponses(academicYearID uint) ([]models.TeachingAssistantAssignmentResponse, error)
+	ListResponses(q models.ListQuery) (models.ListResult[models.TeachingAssistantAssignmentResponse], error)
+	GetCourseIDsByUser(userID uint) ([]uint, error)
+}
+
+// TimetableDraftStore is implemented by TimetableDraftRepository
+type TimetableDraftStore interface {
+	Create(draft *models.TimetableDraft) error
+	FindByID(id uint) (*models.TimetableDraft, error)
+	FindByAcademicYear(academicYearID uint) ([]models.TimetableDraft, error)
+	Delete(id uint) error
+	Commit(draft *models.TimetableDraft, schedules []models.CourseSchedule, committedBy string, committedAt time.Time,
+		check func(current []models.CourseSchedule) error) (bool, error)
+}
+
+// TwoFactorStore is implemented by TwoFactorRepository
+type TwoFactorStore interface {
+	FindByUserID(userID uint) (*models.UserTwoFactor, error)
+	Save(twoFactor *models.UserTwoFactor) error
+	DeleteByUserID(userID uint) error
+	UpdateLastUsedStep(userID uint, step int64) (bool, error)
+	ReplaceRecoveryCodes(userID uint, codeHashes []string) error
+	UseRecoveryCode(userID uint, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(userID uint) (int64, error)
+}
+
+// UserStore is implemented by UserRepository
+type UserStore interface {
+	FindByUsername(username string) (*models.User, error)
+	FindByID(id uint) (*models.User, error)
+	FindByExternalUserID(externalUserID int) (*models.User, error)
+	FindAll(role string) ([]models.User, error)
+	Create(user *models.User) error
+	Update(user *models.User) error
+	Delete(id uint) error
+	CountByUsername(username string) (int64, error)
+}
+
+var (
+	_ AcademicYearStore                = (*AcademicYearRepository)(nil)
+	_ APIKeyStore                      = (*APIKeyRepository)(nil)
+	_ AttendanceStore                  = (*AttendanceRepository)(nil)
+	_ AuditLogStore                    = (*AuditLogRepository)(nil)
+	_ BuildingStore                    = (*BuildingRepository)(nil)
+	_ CalendarFeedStore                = (*CalendarFeedRepository)(nil)
+	_ CampusCredentialStore            = (*CampusCredentialRepository)(nil)
+	_ CourseStore                      = (*CourseRepository)(nil)
+	_ CourseScheduleStore              = (*CourseScheduleRepository)(nil)
+	_ EmployeeStore                    = (*EmployeeRepository)(nil)
+	_ FacultyStore                     = (*FacultyRepository)(nil)
+	_ HolidayStore                     = (*HolidayRepository)(nil)
+	_ IdempotencyStore                 = (*IdempotencyRepository)(nil)
+	_ IntegrityStore                   = (*IntegrityRepository)(nil)
+	_ LecturerAssignmentStore          = (*LecturerAssignmentRepository)(nil)
+	_ LecturerAvailabilityStore        = (*LecturerAvailabilityRepository)(nil)
+	_ LecturerStore                    = (*LecturerRepository)(nil)
+	_ RoomStore                        = (*RoomRepository)(nil)
+	_ ScheduleConflictReviewStore      = (*ScheduleConflictReviewRepository)(nil)
+	_ ScheduleExceptionStore           = (*ScheduleExceptionRepository)(nil)
+	_ StudentGroupStore                = (*StudentGroupRepository)(nil)
+	_ StudentStore                     = (*StudentRepository)(nil)
+	_ StudyProgramStore                = (*StudyProgramRepository)(nil)
+	_ SyncPreviewStore                 = (*SyncPreviewRepository)(nil)
+	_ SyncRunStore                     = (*SyncRunRepository)(nil)
+	_ TeachingAssistantAssignmentStore = (*TeachingAssistantAssignmentRepository)(nil)
+	_ TimetableDraftStore              = (*TimetableDraftRepository)(nil)
+	_ TwoFactorStore                   = (*TwoFactorRepository)(nil)
+	_ UserStore                        = (*UserRepository)(nil)
+)
@@ -20,6 +20,52 @@ func NewStudentGroupRepository() *StudentGroupRepository {
 	}
 }
 
+// studentGroupListSpec lists student groups with their department, see ListSpec
+var studentGroupListSpec = ListSpec[models.StudentGroup]{
+	Fields: map[string]ListField[models.StudentGroup]{
+		"id":            {Column: "student_groups.id", Value: func(g models.StudentGroup) interface{} { return g.ID }},
+		"name":          {Column: "student_groups.name", Value: func(g models.StudentGroup) interface{} { return g.Name }},
+		"department_id": {Column: "student_groups.department_id", Value: func(g models.StudentGroup) interface{} { return g.DepartmentID }},
+		"created_at":    {Column: "student_groups.created_at", Value: func(g models.StudentGroup) interface{} { return g.CreatedAt }},
+	},
+	Preloads:    []string{"Department"},
+	DefaultSort: []models.ListSort{{Field: "name"}},
+}
+
+// List returns one page of student groups with their department and student count
+func (r *StudentGroupRepository) List(q models.ListQuery) (models.ListResult[models.StudentGroup], error) {
+	page, err := listRecords(r.db, studentGroupListSpec, q)
+	if err != nil || len(page.Items) == 0 {
+		return page, err
+	}
+
+	ids := make([]uint, len(page.Items))
+	for i, group := range page.Items {
+		ids[i] = group.ID
+	}
+	var groupCounts []struct {
+		StudentGroupID uint
+		Count          int
+	}
+	err = r.db.Model(&models.StudentToGroup{}).
+		Select("student_group_id, COUNT(*) AS count").
+		Where("student_group_id IN ?", ids).
+		Group("student_group_id").
+		Scan(&groupCounts).Error
+	if err != nil {
+		return page, err
+	}
+
+	counts := make(map[uint]int, len(groupCounts))
+	for _, groupCount := range groupCounts {
+		counts[groupCount.StudentGroupID] = groupCount.Count
+	}
+	for i := range page.Items {
+		page.Items[i].StudentCount = counts[page.Items[i].ID]
+	}
+	return page, nil
+}
+
 // GetAll returns all student groups
 func (r *StudentGroupRepository) GetAll() ([]models.StudentGroup, error) {
 	var groups []models.StudentGroup
@@ -142,6 +188,9 @@ func (r *StudentGroupRepository) AddStudentToGroup(groupID, studentID uint) erro
 	if err := r.db.First(&student, studentID).Error; err != nil {
 		return err
 	}
+	if student.LifecycleStatus == models.LifecycleInactive {
+		return errors.New("student is no longer active")
+	}
 
 	var count int64
 	r.db.Model(&models.StudentToGroup{}).
@@ -225,7 +274,7 @@ func (r *StudentGroupRepository) GetAvailableStudents(groupID uint, departmentID
 	// Find all students who are not in the group by UserID rather than StudentID
 	result := r.db.Raw(`
 		SELECT * FROM students 
-		WHERE user_id NOT IN (
+		WHERE deleted_at IS NULL AND lifecycle_status = 'active' AND user_id NOT IN (
 			SELECT user_id FROM student_to_groups 
 			WHERE student_group_id = ?
 		)
@@ -246,3 +295,52 @@ func (r *StudentGroupRepository) GetGroupsByStudentID(studentID uint) ([]models.
 
 	return groups, err
 }
+
+// GetActiveMembersByStudentID returns the active students linked to a group by student ID
+func (r *StudentGroupRepository) GetActiveMembersByStudentID(groupID uint) ([]models.Student, error) {
+	var students []models.Student
+	err := r.db.Table("students").
+		Joins("JOIN student_to_groups ON students.id = student_to_groups.student_id").
+		Where("student_to_groups.student_group_id = ? AND students.lifecycle_status = ?", groupID, models.LifecycleActive).
+		Find(&students).Error
+	return students, err
+}
+
+// CountMembers counts the students in a group
+func (r *StudentGroupRepository) CountMembers(groupID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.StudentToGroup{}).
+		Where("student_group_id = ?", groupID).
+		Count(&count).Error
+	return count, err
+}
+
+// GetGroupIDsByStudentID returns the IDs of the groups a student belongs to
+func (r *StudentGroupRepository) GetGroupIDsByStudentID(studentID uint) ([]uint, error) {
+	var groupIDs []uint
+	err := r.db.Model(&models.StudentToGroup{}).
+		Where("student_id = ?", studentID).
+		Pluck("student_group_id", &groupIDs).Error
+	return groupIDs, err
+}
+
+// CountMembersByGroup counts the students of every group that has any
+func (r *StudentGroupRepository) CountMembersByGroup() (map[uint]int, error) {
+	var groupCounts []struct {
+		StudentGroupID uint
+		Count          int
+	}
+	err := r.db.Model(&models.StudentToGroup{}).
+		Select("student_group_id, COUNT(*) AS count").
+		Group("student_group_id").
+		Scan(&groupCounts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int, len(groupCounts))
+	for _, groupCount := range groupCounts {
+		counts[groupCount.StudentGroupID] = groupCount.Count
+	}
+	return counts, nil
+}
@@ -1,7 +1,9 @@
 package repositories
 
 import (
-	"log"
+	"log/slog"
+	"strconv"
+	"time"
 
 	"github.com/delpresence/backend/internal/database"
 	"github.com/delpresence/backend/internal/models"
@@ -20,6 +22,33 @@ func NewStudentRepository() *StudentRepository {
 	}
 }
 
+// studentListSpec lists students, see ListSpec
+var studentListSpec = ListSpec[models.Student]{
+	Fields: map[string]ListField[models.Student]{
+		"id":               {Column: "students.id", Value: func(s models.Student) interface{} { return s.ID }},
+		"nim":              {Column: "students.nim", Value: func(s models.Student) interface{} { return s.NIM }},
+		"full_name":        {Column: "students.full_name", Value: func(s models.Student) interface{} { return s.FullName }},
+		"email":            {Column: "students.email", Value: func(s models.Student) interface{} { return s.Email }},
+		"user_id":          {Column: "students.user_id", Value: func(s models.Student) interface{} { return s.UserID }},
+		"user_name":        {Column: "students.user_name", Value: func(s models.Student) interface{} { return s.UserName }},
+		"study_program_id": {Column: "students.study_program_id", Value: func(s models.Student) interface{} { return s.StudyProgramID }},
+		"study_program":    {Column: "students.study_program", Value: func(s models.Student) interface{} { return s.StudyProgram }},
+		"faculty":          {Column: "students.faculty", Value: func(s models.Student) interface{} { return s.Faculty }},
+		"year_enrolled":    {Column: "students.year_enrolled", Value: func(s models.Student) interface{} { return s.YearEnrolled }},
+		"status":           {Column: "students.status", Value: func(s models.Student) interface{} { return s.Status }},
+		"dormitory":        {Column: "students.dormitory", Value: func(s models.Student) interface{} { return s.Dormitory }},
+		"lifecycle_status": {Column: "students.lifecycle_status", Value: func(s models.Student) interface{} { return s.LifecycleStatus }},
+		"last_sync":        {Column: "students.last_sync", Value: func(s models.Student) interface{} { return s.LastSync }},
+		"created_at":       {Column: "students.created_at", Value: func(s models.Student) interface{} { return s.CreatedAt }},
+	},
+	DefaultSort: []models.ListSort{{Field: "nim"}},
+}
+
+// List returns one page of students
+func (r *StudentRepository) List(q models.ListQuery) (models.ListResult[models.Student], error) {
+	return listRecords(r.db, studentListSpec, q)
+}
+
 // FindAll returns all students from the database
 func (r *StudentRepository) FindAll() ([]models.Student, error) {
 	var students []models.Student
@@ -60,8 +89,16 @@ func (r *StudentRepository) FindByUserID(userID int) (*models.Student, error) {
 	return &student, nil
 }
 
+// ExistsByID checks if a student with the given ID exists
+func (r *StudentRepository) ExistsByID(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Student{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
 // UpsertMany creates or updates multiple students
-func (r *StudentRepository) UpsertMany(students []models.Student) error {
+// A student that fails is rolled back on its own and recorded in the result, the others are kept
+func (r *StudentRepository) UpsertMany(students []models.Student, result *models.SyncResult) error {
 	if len(students) == 0 {
 		return nil
 	}
@@ -74,43 +111,112 @@ func (r *StudentRepository) UpsertMany(students []models.Student) error {
 	}()
 
 	for _, student := range students {
-		// Try to find existing student by DimID (from external system)
-		var existingStudent models.Student
-		result := tx.Where("dim_id = ?", student.DimID).First(&existingStudent)
-		
-		if result.Error == nil {
-			// Check if the student ID is going to change
-			oldID := existingStudent.ID
-
-			// Update existing student
-			student.ID = existingStudent.ID
-			student.CreatedAt = existingStudent.CreatedAt
-			
-			if err := tx.Save(&student).Error; err != nil {
-				tx.Rollback()
-				return err
-			}
+		if err := tx.SavePoint("student").Error; err != nil {
+			tx.Rollback()
+			return err
+		}
 
-			// Update student_to_groups rows if the student ID changed but UserID remains the same
-			// This maintains group membership connections when student IDs change
-			if oldID != student.ID && existingStudent.UserID == student.UserID {
-				if err := tx.Exec(
-					"UPDATE student_to_groups SET student_id = ? WHERE student_id = ? AND user_id = ?",
-					student.ID, oldID, student.UserID,
-				).Error; err != nil {
-					tx.Rollback()
-					return err
-				}
-			}
-		} else {
-			// Create new student
-			if err := tx.Create(&student).Error; err != nil {
+		inserted, err := upsertStudent(tx, student)
+		if err != nil {
+			if rollbackErr := tx.RollbackTo("student").Error; rollbackErr != nil {
 				tx.Rollback()
-				return err
+				return rollbackErr
 			}
+			result.AddError(strconv.Itoa(student.DimID), student.NIM+" "+student.FullName, err)
+			continue
+		}
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Updated++
 		}
 	}
 
-	log.Printf("Upserted %d students", len(students))
+	slog.Info("Upserted students", "inserted", result.Inserted, "updated", result.Updated, "failed", len(result.Errors))
 	return tx.Commit().Error
-} 
\ No newline at end of file
+}
+
+// upsertStudent creates or updates one student and reports whether it was created
+func upsertStudent(tx *gorm.DB, student models.Student) (bool, error) {
+	// Try to find existing student by DimID (from external system)
+	var existingStudent models.Student
+	result := tx.Where("dim_id = ?", student.DimID).First(&existingStudent)
+
+	if result.Error != nil {
+		// Create new student
+		return true, tx.Create(&student).Error
+	}
+
+	// Check if the student ID is going to change
+	oldID := existingStudent.ID
+
+	// Update existing student
+	student.ID = existingStudent.ID
+	student.CreatedAt = existingStudent.CreatedAt
+
+	if err := tx.Save(&student).Error; err != nil {
+		return false, err
+	}
+
+	// Update student_to_groups rows if the student ID changed but UserID remains the same
+	// This maintains group membership connections when student IDs change
+	if oldID != student.ID && existingStudent.UserID == student.UserID {
+		if err := tx.Exec(
+			"UPDATE student_to_groups SET student_id = ? WHERE student_id = ? AND user_id = ?",
+			student.ID, oldID, student.UserID,
+		).Error; err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// FindActive returns all students that have not been deactivated
+func (r *StudentRepository) FindActive() ([]models.Student, error) {
+	var students []models.Student
+	result := r.db.Where("lifecycle_status = ?", models.LifecycleActive).Find(&students)
+	return students, result.Error
+}
+
+// Deactivate marks students as inactive and removes them from their groups
+// The removed memberships are kept in student_group_membership_history
+func (r *StudentRepository) Deactivate(ids []uint, reason string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Student{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"lifecycle_status": models.LifecycleInactive,
+			"deactivated_at":   at,
+			"inactive_reason":  reason,
+		}).Error; err != nil {
+			return err
+		}
+
+		var memberships []models.StudentToGroup
+		if err := tx.Where("student_id IN ?", ids).Find(&memberships).Error; err != nil {
+			return err
+		}
+		if len(memberships) == 0 {
+			return nil
+		}
+
+		history := make([]models.StudentGroupMembershipHistory, 0, len(memberships))
+		for _, m := range memberships {
+			history = append(history, models.StudentGroupMembershipHistory{
+				StudentID:      m.StudentID,
+				UserID:         m.UserID,
+				StudentGroupID: m.StudentGroupID,
+				JoinedAt:       m.CreatedAt,
+				LeftAt:         at,
+				Reason:         reason,
+			})
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("student_id IN ?", ids).Delete(&models.StudentToGroup{}).Error
+	})
+}
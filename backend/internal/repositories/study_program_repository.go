@@ -18,6 +18,29 @@ func NewStudyProgramRepository() *StudyProgramRepository {
 	}
 }
 
+// studyProgramListSpec lists study programs with their faculty, see ListSpec
+var studyProgramListSpec = ListSpec[models.StudyProgram]{
+	Fields: map[string]ListField[models.StudyProgram]{
+		"id":                 {Column: "study_programs.id", Value: func(p models.StudyProgram) interface{} { return p.ID }},
+		"code":               {Column: "study_programs.code", Value: func(p models.StudyProgram) interface{} { return p.Code }},
+		"name":               {Column: "study_programs.name", Value: func(p models.StudyProgram) interface{} { return p.Name }},
+		"faculty_id":         {Column: "study_programs.faculty_id", Value: func(p models.StudyProgram) interface{} { return p.FacultyID }},
+		"degree":             {Column: "study_programs.degree", Value: func(p models.StudyProgram) interface{} { return p.Degree }},
+		"accreditation":      {Column: "study_programs.accreditation", Value: func(p models.StudyProgram) interface{} { return p.Accreditation }},
+		"head_of_department": {Column: "study_programs.head_of_department", Value: func(p models.StudyProgram) interface{} { return p.HeadOfDepartment }},
+		"lecturer_count":     {Column: "study_programs.lecturer_count", Value: func(p models.StudyProgram) interface{} { return p.LecturerCount }},
+		"student_count":      {Column: "study_programs.student_count", Value: func(p models.StudyProgram) interface{} { return p.StudentCount }},
+		"created_at":         {Column: "study_programs.created_at", Value: func(p models.StudyProgram) interface{} { return p.CreatedAt }},
+	},
+	Preloads:    []string{"Faculty"},
+	DefaultSort: []models.ListSort{{Field: "code"}},
+}
+
+// List returns one page of study programs with their faculty
+func (r *StudyProgramRepository) List(q models.ListQuery) (models.ListResult[models.StudyProgram], error) {
+	return listRecords(r.db, studyProgramListSpec, q)
+}
+
 // Create creates a new study program
 func (r *StudyProgramRepository) Create(program *models.StudyProgram) error {
 	return r.db.Create(program).Error
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// SyncPreviewRepository handles database operations for sync previews
+// Previews are shared by all instances, so a preview can be applied through any of them
+type SyncPreviewRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncPreviewRepository creates a new sync preview repository
+func NewSyncPreviewRepository() *SyncPreviewRepository {
+	return &SyncPreviewRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create stores a new preview
+func (r *SyncPreviewRepository) Create(preview *models.SyncPreviewRecord) error {
+	return r.db.Create(preview).Error
+}
+
+// FindActive returns a preview that has not expired at now, or nil if there is none
+func (r *SyncPreviewRepository) FindActive(id string, now time.Time) (*models.SyncPreviewRecord, error) {
+	var preview models.SyncPreviewRecord
+	err := r.db.Where("id = ? AND expires_at >= ?", id, now).First(&preview).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// Delete removes a preview
+func (r *SyncPreviewRepository) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&models.SyncPreviewRecord{}).Error
+}
+
+// DeleteExpired removes the previews that expired before now
+func (r *SyncPreviewRepository) DeleteExpired(now time.Time) error {
+	return r.db.Where("expires_at < ?", now).Delete(&models.SyncPreviewRecord{}).Error
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// SyncRunRepository handles database operations for sync runs
+type SyncRunRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncRunRepository creates a new sync run repository
+func NewSyncRunRepository() *SyncRunRepository {
+	return &SyncRunRepository{
+		db: database.GetDB(),
+	}
+}
+
+// syncRunListSpec lists sync runs without their record errors, latest first by default, see ListSpec
+var syncRunListSpec = ListSpec[models.SyncRun]{
+	Fields: map[string]ListField[models.SyncRun]{
+		"id":                {Column: "sync_runs.id", Value: func(r models.SyncRun) interface{} { return r.ID }},
+		"entity":            {Column: "sync_runs.entity", Value: func(r models.SyncRun) interface{} { return r.Entity }},
+		"trigger":           {Column: "sync_runs.trigger", Value: func(r models.SyncRun) interface{} { return r.Trigger }},
+		"actor_username":    {Column: "sync_runs.actor_username", Value: func(r models.SyncRun) interface{} { return r.ActorUsername }},
+		"status":            {Column: "sync_runs.status", Value: func(r models.SyncRun) interface{} { return r.Status }},
+		"started_at":        {Column: "sync_runs.started_at", Value: func(r models.SyncRun) interface{} { return r.StartedAt }},
+		"duration_ms":       {Column: "sync_runs.duration_ms", Value: func(r models.SyncRun) interface{} { return r.DurationMs }},
+		"record_count":      {Column: "sync_runs.record_count", Value: func(r models.SyncRun) interface{} { return r.RecordCount }},
+		"inserted_count":    {Column: "sync_runs.inserted_count", Value: func(r models.SyncRun) interface{} { return r.InsertedCount }},
+		"updated_count":     {Column: "sync_runs.updated_count", Value: func(r models.SyncRun) interface{} { return r.UpdatedCount }},
+		"deactivated_count": {Column: "sync_runs.deactivated_count", Value: func(r models.SyncRun) interface{} { return r.DeactivatedCount }},
+		"failed_count":      {Column: "sync_runs.failed_count", Value: func(r models.SyncRun) interface{} { return r.FailedCount }},
+	},
+	DefaultSort: []models.ListSort{{Field: "started_at", Desc: true}},
+}
+
+// List returns one page of sync runs
+func (r *SyncRunRepository) List(q models.ListQuery) (models.ListResult[models.SyncRun], error) {
+	return listRecords(r.db, syncRunListSpec, q)
+}
+
+// Create stores a new sync run
+func (r *SyncRunRepository) Create(run *models.SyncRun) error {
+	return r.db.Create(run).Error
+}
+
+// Finish saves the outcome of a run together with its record errors
+func (r *SyncRunRepository) Finish(run *models.SyncRun) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Errors").Save(run).Error; err != nil {
+			return err
+		}
+		if len(run.Errors) == 0 {
+			return nil
+		}
+		for i := range run.Errors {
+			run.Errors[i].SyncRunID = run.ID
+		}
+		return tx.CreateInBatches(run.Errors, 100).Error
+	})
+}
+
+// FindByID returns a run with its record errors, or nil if it does not exist
+func (r *SyncRunRepository) FindByID(id uint) (*models.SyncRun, error) {
+	var run models.SyncRun
+	err := r.db.Preload("Errors").First(&run, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// FindRecent returns the most recent runs, newest first, optionally filtered by entity and status
+func (r *SyncRunRepository) FindRecent(entity, status string, limit int) ([]models.SyncRun, error) {
+	var runs []models.SyncRun
+	query := r.db.Order("started_at DESC").Limit(limit)
+	if entity != "" {
+		query = query.Where("entity = ?", entity)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&runs).Error
+	return runs, err
+}
+
+// FindLastSuccess returns the most recent run of an entity that finished, even with some failed records,
+// or nil if there is none
+func (r *SyncRunRepository) FindLastSuccess(entity string) (*models.SyncRun, error) {
+	var run models.SyncRun
+	err := r.db.Where("entity = ? AND status IN ?", entity, []string{models.SyncStatusSuccess, models.SyncStatusPartial}).
+		Order("finished_at DESC").First(&run).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// FindLatest returns the most recent run of an entity, or nil if there is none
+func (r *SyncRunRepository) FindLatest(entity string) (*models.SyncRun, error) {
+	var run models.SyncRun
+	err := r.db.Where("entity = ?", entity).Order("started_at DESC").First(&run).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// syncLockClass is the first key of the advisory locks of TryLock, the entity is the second
+const syncLockClass int32 = 7_310_031
+
+// TryLock takes the lock held while an entity is synced, shared by every server instance
+// It returns false if a sync of the entity holds it, and otherwise a function releasing it
+func (r *SyncRunRepository) TryLock(entity string) (func(), bool, error) {
+	return tryAdvisoryLock(r.db, syncLockClass, entity)
+}
+
+// MarkInterrupted fails the runs of an entity that were still running when their server stopped
+// Call it while holding the lock of the entity, so runs of other instances are left alone
+func (r *SyncRunRepository) MarkInterrupted(entity string) error {
+	return r.db.Model(&models.SyncRun{}).Where("entity = ? AND status = ?", entity, models.SyncStatusRunning).
+		Updates(map[string]interface{}{"status": models.SyncStatusFailed, "error": "interrupted by server shutdown"}).Error
+}
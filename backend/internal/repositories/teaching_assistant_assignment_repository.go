@@ -210,74 +210,118 @@ func (r *TeachingAssistantAssignmentRepository) GetTeachingAssistantAssignmentRe
 
 	// Convert to responses
 	for _, assignment := range assignments {
-		response := models.TeachingAssistantAssignmentResponse{
-			ID:             assignment.ID,
-			UserID:         assignment.UserID,
-			CourseID:       assignment.CourseID,
-			AcademicYearID: assignment.AcademicYearID,
-			AssignedByID:   assignment.AssignedByID,
-			CreatedAt:      assignment.CreatedAt,
-			UpdatedAt:      assignment.UpdatedAt,
-		}
+		responses = append(responses, r.toResponse(assignment))
+	}
 
-		// Add course details
-		if assignment.Course.ID > 0 {
-			response.CourseName = assignment.Course.Name
-			response.CourseCode = assignment.Course.Code
-			response.CourseSemester = assignment.Course.Semester
-		} else {
-			// Try to fetch course details
-			var course models.Course
-			if err := r.db.First(&course, assignment.CourseID).Error; err == nil {
-				response.CourseName = course.Name
-				response.CourseCode = course.Code
-				response.CourseSemester = course.Semester
-			} else {
-				response.CourseName = "Unknown Course"
-				response.CourseCode = "N/A"
-			}
-		}
+	return responses, nil
+}
 
-		// Add academic year details
-		if assignment.AcademicYear.ID > 0 {
-			response.AcademicYearName = assignment.AcademicYear.Name
-			response.AcademicYearSemester = assignment.AcademicYear.Semester
-		} else {
-			// Try to fetch academic year details
-			var academicYear models.AcademicYear
-			if err := r.db.First(&academicYear, assignment.AcademicYearID).Error; err == nil {
-				response.AcademicYearName = academicYear.Name
-				response.AcademicYearSemester = academicYear.Semester
-			} else {
-				response.AcademicYearName = "N/A"
-				response.AcademicYearSemester = "N/A"
-			}
-		}
+// teachingAssistantAssignmentListSpec lists teaching assistant assignments with their course and academic year, see ListSpec
+var teachingAssistantAssignmentListSpec = ListSpec[models.TeachingAssistantAssignment]{
+	Fields: map[string]ListField[models.TeachingAssistantAssignment]{
+		"id":               {Column: "teaching_assistant_assignments.id", Value: func(a models.TeachingAssistantAssignment) interface{} { return a.ID }},
+		"user_id":          {Column: "teaching_assistant_assignments.user_id", Value: func(a models.TeachingAssistantAssignment) interface{} { return a.UserID }},
+		"course_id":        {Column: "teaching_assistant_assignments.course_id", Value: func(a models.TeachingAssistantAssignment) interface{} { return a.CourseID }},
+		"academic_year_id": {Column: "teaching_assistant_assignments.academic_year_id", Value: func(a models.TeachingAssistantAssignment) interface{} { return a.AcademicYearID }},
+		"assigned_by_id":   {Column: "teaching_assistant_assignments.assigned_by_id", Value: func(a models.TeachingAssistantAssignment) interface{} { return a.AssignedByID }},
+		"created_at":       {Column: "teaching_assistant_assignments.created_at", Value: func(a models.TeachingAssistantAssignment) interface{} { return a.CreatedAt }},
+	},
+	Preloads: []string{"Course", "AcademicYear"},
+}
 
-		// Add employee details
-		var employee models.Employee
-		if err := r.db.Where("user_id = ?", assignment.UserID).First(&employee).Error; err == nil {
-			response.EmployeeName = employee.FullName
-			response.EmployeeNIP = employee.NIP
-			response.EmployeeEmail = employee.Email
-			response.EmployeePosition = employee.Position
+// ListResponses returns one page of teaching assistant assignments with detailed information
+func (r *TeachingAssistantAssignmentRepository) ListResponses(q models.ListQuery) (models.ListResult[models.TeachingAssistantAssignmentResponse], error) {
+	page, err := listRecords(r.db, teachingAssistantAssignmentListSpec, q)
+	if err != nil {
+		return models.ListResult[models.TeachingAssistantAssignmentResponse]{}, err
+	}
+
+	result := models.ListResult[models.TeachingAssistantAssignmentResponse]{
+		Items: make([]models.TeachingAssistantAssignmentResponse, len(page.Items)),
+		Page:  page.Page,
+	}
+	for i, assignment := range page.Items {
+		result.Items[i] = r.toResponse(assignment)
+	}
+	return result, nil
+}
+
+// toResponse adds the course, academic year, employee and assigning lecturer details to an assignment
+func (r *TeachingAssistantAssignmentRepository) toResponse(assignment models.TeachingAssistantAssignment) models.TeachingAssistantAssignmentResponse {
+	response := models.TeachingAssistantAssignmentResponse{
+		ID:             assignment.ID,
+		UserID:         assignment.UserID,
+		CourseID:       assignment.CourseID,
+		AcademicYearID: assignment.AcademicYearID,
+		AssignedByID:   assignment.AssignedByID,
+		CreatedAt:      assignment.CreatedAt,
+		UpdatedAt:      assignment.UpdatedAt,
+	}
+
+	// Add course details
+	if assignment.Course.ID > 0 {
+		response.CourseName = assignment.Course.Name
+		response.CourseCode = assignment.Course.Code
+		response.CourseSemester = assignment.Course.Semester
+	} else {
+		// Try to fetch course details
+		var course models.Course
+		if err := r.db.First(&course, assignment.CourseID).Error; err == nil {
+			response.CourseName = course.Name
+			response.CourseCode = course.Code
+			response.CourseSemester = course.Semester
 		} else {
-			response.EmployeeName = "Unknown Employee"
-			response.EmployeeNIP = "N/A"
-			response.EmployeeEmail = "N/A"
-			response.EmployeePosition = "N/A"
+			response.CourseName = "Unknown Course"
+			response.CourseCode = "N/A"
 		}
+	}
 
-		// Add assigned by details
-		var lecturer models.Lecturer
-		if err := r.db.Where("user_id = ?", assignment.AssignedByID).First(&lecturer).Error; err == nil {
-			response.AssignedByName = lecturer.FullName
+	// Add academic year details
+	if assignment.AcademicYear.ID > 0 {
+		response.AcademicYearName = assignment.AcademicYear.Name
+		response.AcademicYearSemester = assignment.AcademicYear.Semester
+	} else {
+		// Try to fetch academic year details
+		var academicYear models.AcademicYear
+		if err := r.db.First(&academicYear, assignment.AcademicYearID).Error; err == nil {
+			response.AcademicYearName = academicYear.Name
+			response.AcademicYearSemester = academicYear.Semester
 		} else {
-			response.AssignedByName = fmt.Sprintf("User ID %d", assignment.AssignedByID)
+			response.AcademicYearName = "N/A"
+			response.AcademicYearSemester = "N/A"
 		}
+	}
+
+	// Add employee details
+	var employee models.Employee
+	if err := r.db.Where("user_id = ?", assignment.UserID).First(&employee).Error; err == nil {
+		response.EmployeeName = employee.FullName
+		response.EmployeeNIP = employee.NIP
+		response.EmployeeEmail = employee.Email
+		response.EmployeePosition = employee.Position
+	} else {
+		response.EmployeeName = "Unknown Employee"
+		response.EmployeeNIP = "N/A"
+		response.EmployeeEmail = "N/A"
+		response.EmployeePosition = "N/A"
+	}
 
-		responses = append(responses, response)
+	// Add assigned by details
+	var lecturer models.Lecturer
+	if err := r.db.Where("user_id = ?", assignment.AssignedByID).First(&lecturer).Error; err == nil {
+		response.AssignedByName = lecturer.FullName
+	} else {
+		response.AssignedByName = fmt.Sprintf("User ID %d", assignment.AssignedByID)
 	}
 
-	return responses, nil
+	return response
+}
+
+// GetCourseIDsByUser returns the IDs of the courses a teaching assistant is assigned to
+func (r *TeachingAssistantAssignmentRepository) GetCourseIDsByUser(userID uint) ([]uint, error) {
+	var courseIDs []uint
+	err := r.db.Model(&models.TeachingAssistantAssignment{}).
+		Where("user_id = ?", userID).
+		Pluck("course_id", &courseIDs).Error
+	return courseIDs, err
 }
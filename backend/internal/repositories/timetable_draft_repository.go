@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// TimetableDraftRepository handles database operations for timetable drafts and their entries
+type TimetableDraftRepository struct {
+	db *gorm.DB
+}
+
+// NewTimetableDraftRepository creates a new timetable draft repository
+func NewTimetableDraftRepository() *TimetableDraftRepository {
+	return &TimetableDraftRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create stores a new draft with its entries
+func (r *TimetableDraftRepository) Create(draft *models.TimetableDraft) error {
+	return r.db.Create(draft).Error
+}
+
+// FindByID returns a draft with its entries, or nil if there is none
+func (r *TimetableDraftRepository) FindByID(id uint) (*models.TimetableDraft, error) {
+	var draft models.TimetableDraft
+	err := r.db.
+		Preload("Entries", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Entries.Course").
+		Preload("Entries.StudentGroup").
+		Preload("Entries.Room").
+		Preload("Entries.Room.Building").
+		First(&draft, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// FindByAcademicYear returns the drafts of an academic year without their entries, newest first
+func (r *TimetableDraftRepository) FindByAcademicYear(academicYearID uint) ([]models.TimetableDraft, error) {
+	var drafts []models.TimetableDraft
+	err := r.db.Where("academic_year_id = ?", academicYearID).Order("id DESC").Find(&drafts).Error
+	return drafts, err
+}
+
+// Delete removes a draft and its entries
+func (r *TimetableDraftRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("draft_id = ?", id).Delete(&models.TimetableDraftEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.TimetableDraft{}, id).Error
+	})
+}
+
+// timetableCommitLockClass is the first key of the lock taken by Commit, the academic year is the second
+const timetableCommitLockClass int32 = 7_310_049
+
+// Commit creates the course schedules of a draft and marks it committed in one transaction
+// schedules[i] is created for draft.Entries[i]. It returns false without changes when the draft
+// was already committed, for example by a concurrent request.
+// check gets the schedules of the academic year read inside the transaction, while concurrent commits
+// of the year wait, and an error it returns rolls the commit back.
+func (r *TimetableDraftRepository) Commit(draft *models.TimetableDraft, schedules []models.CourseSchedule, committedBy string, committedAt time.Time,
+	check func(current []models.CourseSchedule) error) (bool, error) {
+	committed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockAdvisoryXact(tx, timetableCommitLockClass, strconv.FormatUint(uint64(draft.AcademicYearID), 10)); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.TimetableDraft{}).
+			Where("id = ? AND status = ?", draft.ID, models.TimetableDraftOpen).
+			Updates(map[string]interface{}{
+				"status":       models.TimetableDraftCommitted,
+				"committed_by": committedBy,
+				"committed_at": committedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var current []models.CourseSchedule
+		if err := tx.Where("academic_year_id = ?", draft.AcademicYearID).Find(&current).Error; err != nil {
+			return err
+		}
+		if err := check(current); err != nil {
+			return err
+		}
+
+		for i := range schedules {
+			if err := tx.Omit("Course", "Room", "Lecturer", "StudentGroup", "AcademicYear").Create(&schedules[i]).Error; err != nil {
+				return err
+			}
+			err := tx.Model(&models.TimetableDraftEntry{}).Where("id = ?", draft.Entries[i].ID).
+				Update("schedule_id", schedules[i].ID).Error
+			if err != nil {
+				return err
+			}
+		}
+		committed = true
+		return nil
+	})
+	return committed, err
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+)
+
+func TestTimetableDraftCommitRollsBackWhenCheckFails(t *testing.T) {
+	openSQLite(t)
+	drafts := NewTimetableDraftRepository()
+
+	// A schedule added after the draft was generated
+	existing, err := NewCourseScheduleRepository().Create(models.CourseSchedule{
+		CourseID: 1, RoomID: 1, UserID: 1, StudentGroupID: 1, AcademicYearID: 1,
+		Day: "senin", StartTime: "08:00", EndTime: "10:00",
+	})
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+
+	draft := &models.TimetableDraft{
+		AcademicYearID: 1,
+		Status:         models.TimetableDraftOpen,
+		Entries: []models.TimetableDraftEntry{{
+			CourseID: 2, StudentGroupID: 2, LecturerID: 2, RoomID: 1,
+			Day: "senin", StartTime: "09:00", EndTime: "11:00",
+		}},
+	}
+	if err := drafts.Create(draft); err != nil {
+		t.Fatalf("create draft: %v", err)
+	}
+	schedules := []models.CourseSchedule{{
+		CourseID: 2, RoomID: 1, UserID: 2, StudentGroupID: 2, AcademicYearID: 1,
+		Day: "senin", StartTime: "09:00", EndTime: "11:00",
+	}}
+
+	errStale := errors.New("stale")
+	var seen []models.CourseSchedule
+	_, err = drafts.Commit(draft, schedules, "admin", time.Now(), func(current []models.CourseSchedule) error {
+		seen = current
+		return errStale
+	})
+	if !errors.Is(err, errStale) {
+		t.Fatalf("got %v, want the error of the check", err)
+	}
+	if len(seen) != 1 || seen[0].ID != existing.ID {
+		t.Errorf("check got schedules %+v, want the existing schedule", seen)
+	}
+
+	stored, err := drafts.FindByID(draft.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("find draft: %v", err)
+	}
+	if stored.Status != models.TimetableDraftOpen || stored.Entries[0].ScheduleID != nil {
+		t.Errorf("got draft %s with schedule %v, want the open draft unchanged", stored.Status, stored.Entries[0].ScheduleID)
+	}
+	var count int64
+	if err := database.GetDB().Model(&models.CourseSchedule{}).Count(&count).Error; err != nil {
+		t.Fatalf("count schedules: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("got %d schedules after the refused commit, want 1", count)
+	}
+}
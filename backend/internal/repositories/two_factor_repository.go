@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// TwoFactorRepository handles database operations for TOTP enrollments and recovery codes
+type TwoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository creates a new two-factor repository
+func NewTwoFactorRepository() *TwoFactorRepository {
+	return &TwoFactorRepository{
+		db: database.GetDB(),
+	}
+}
+
+// FindByUserID returns the TOTP enrollment of a user, or nil if there is none
+func (r *TwoFactorRepository) FindByUserID(userID uint) (*models.UserTwoFactor, error) {
+	var twoFactor models.UserTwoFactor
+	result := r.db.Where("user_id = ?", userID).First(&twoFactor)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &twoFactor, nil
+}
+
+// Save creates or updates a TOTP enrollment
+func (r *TwoFactorRepository) Save(twoFactor *models.UserTwoFactor) error {
+	return r.db.Save(twoFactor).Error
+}
+
+// DeleteByUserID removes the TOTP enrollment and recovery codes of a user
+func (r *TwoFactorRepository) DeleteByUserID(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserTwoFactor{}).Error
+	})
+}
+
+// UpdateLastUsedStep records the last accepted TOTP step if it is newer than the stored one
+// It returns false when another request already used this or a later step
+func (r *TwoFactorRepository) UpdateLastUsedStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&models.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReplaceRecoveryCodes deletes the recovery codes of a user and stores new code hashes
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]models.RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode marks an unused recovery code as used
+// It returns false when the code does not exist or was already used
+func (r *TwoFactorRepository) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountUnusedRecoveryCodes counts the remaining recovery codes of a user
+func (r *TwoFactorRepository) CountUnusedRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count)
+	return count, result.Error
+}
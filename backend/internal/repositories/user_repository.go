@@ -59,6 +59,17 @@ func (r *UserRepository) FindByExternalUserID(externalUserID int) (*models.User,
 	return &user, nil
 }
 
+// FindAll returns all users ordered by username, optionally only those with a role
+func (r *UserRepository) FindAll(role string) ([]models.User, error) {
+	var users []models.User
+	query := r.DB.Order("username")
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	err := query.Find(&users).Error
+	return users, err
+}
+
 // Create creates a new user
 func (r *UserRepository) Create(user *models.User) error {
 	return r.DB.Create(user).Error
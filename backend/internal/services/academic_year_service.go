@@ -3,23 +3,40 @@ package services
 import (
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
-	"github.com/delpresence/backend/internal/database"
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/repositories"
 )
 
+var (
+	// ErrAcademicYearNotFound is returned for academic years that do not exist
+	ErrAcademicYearNotFound = errors.New("academic year not found")
+
+	// ErrAcademicYearInUse is returned when deleting an academic year that courses, assignments or schedules use
+	ErrAcademicYearInUse = errors.New("cannot delete academic year: it is being used")
+)
+
 // AcademicYearService is a service for academic year operations
 type AcademicYearService struct {
-	repository *repositories.AcademicYearRepository
+	repository repositories.AcademicYearStore
+	courseRepo repositories.CourseStore
 }
 
 // NewAcademicYearService creates a new academic year service
 func NewAcademicYearService() *AcademicYearService {
+	return NewAcademicYearServiceWithStores(
+		repositories.NewAcademicYearRepository(),
+		repositories.NewCourseRepository(),
+	)
+}
+
+// NewAcademicYearServiceWithStores creates a new academic year service on top of the given stores
+func NewAcademicYearServiceWithStores(repository repositories.AcademicYearStore, courseRepo repositories.CourseStore) *AcademicYearService {
 	return &AcademicYearService{
-		repository: repositories.NewAcademicYearRepository(),
+		repository: repository,
+		courseRepo: courseRepo,
 	}
 }
 
@@ -100,9 +117,9 @@ func (s *AcademicYearService) GetAcademicYearByID(id uint) (*models.AcademicYear
 	return s.repository.FindByID(id)
 }
 
-// GetAllAcademicYears gets all academic years
-func (s *AcademicYearService) GetAllAcademicYears() ([]models.AcademicYear, error) {
-	return s.repository.FindAll()
+// ListAcademicYears returns one page of academic years
+func (s *AcademicYearService) ListAcademicYears(q models.ListQuery) (models.ListResult[models.AcademicYear], error) {
+	return s.repository.List(q)
 }
 
 // DeleteAcademicYear deletes an academic year
@@ -116,37 +133,34 @@ func (s *AcademicYearService) DeleteAcademicYear(id uint) error {
 		return errors.New("academic year not found")
 	}
 
-	// Get DB connection
-	db := database.GetDB()
-
 	// Check if this academic year is being used by courses
-	var courseCount int64
-	if err := db.Model(&models.Course{}).Where("academic_year_id = ?", id).Count(&courseCount).Error; err != nil {
+	courseCount, err := s.repository.CountCourses(id)
+	if err != nil {
 		return fmt.Errorf("failed to check related courses: %w", err)
 	}
 
 	if courseCount > 0 {
-		return errors.New("cannot delete academic year: it is being used by one or more courses")
+		return fmt.Errorf("%w by one or more courses", ErrAcademicYearInUse)
 	}
 
 	// Check if this academic year is being used by lecturer assignments
-	var assignmentCount int64
-	if err := db.Model(&models.LecturerAssignment{}).Where("academic_year_id = ?", id).Count(&assignmentCount).Error; err != nil {
+	assignmentCount, err := s.repository.CountLecturerAssignments(id)
+	if err != nil {
 		return fmt.Errorf("failed to check related lecturer assignments: %w", err)
 	}
 
 	if assignmentCount > 0 {
-		return errors.New("cannot delete academic year: it is being used by one or more lecturer assignments")
+		return fmt.Errorf("%w by one or more lecturer assignments", ErrAcademicYearInUse)
 	}
 
 	// Check if this academic year is being used by course schedules
-	var scheduleCount int64
-	if err := db.Model(&models.CourseSchedule{}).Where("academic_year_id = ?", id).Count(&scheduleCount).Error; err != nil {
+	scheduleCount, err := s.repository.CountCourseSchedules(id)
+	if err != nil {
 		return fmt.Errorf("failed to check related course schedules: %w", err)
 	}
 
 	if scheduleCount > 0 {
-		return errors.New("cannot delete academic year: it is being used by one or more course schedules")
+		return fmt.Errorf("%w by one or more course schedules", ErrAcademicYearInUse)
 	}
 
 	// If not used by any related entities, proceed with deletion (soft delete)
@@ -164,23 +178,19 @@ type AcademicYearWithStats struct {
 	} `json:"stats"`
 }
 
-// GetAllAcademicYearsWithStats gets all academic years with their statistics
-func (s *AcademicYearService) GetAllAcademicYearsWithStats() ([]AcademicYearWithStats, error) {
-	// Get all academic years
-	academicYears, err := s.repository.FindAll()
+// ListAcademicYearsWithStats returns one page of academic years with their statistics
+func (s *AcademicYearService) ListAcademicYearsWithStats(q models.ListQuery) (models.ListResult[AcademicYearWithStats], error) {
+	page, err := s.repository.List(q)
 	if err != nil {
-		return nil, err
+		return models.ListResult[AcademicYearWithStats]{}, err
 	}
 
 	// Current date for calculations
 	currentDate := time.Now()
 
-	// Create repository for course count
-	courseRepo := repositories.NewCourseRepository()
-
 	// Build response with stats
-	result := make([]AcademicYearWithStats, len(academicYears))
-	for i, academicYear := range academicYears {
+	result := models.ListResult[AcademicYearWithStats]{Items: make([]AcademicYearWithStats, len(page.Items)), Page: page.Page}
+	for i, academicYear := range page.Items {
 		// Calculate if current
 		isCurrent := currentDate.After(academicYear.StartDate) && currentDate.Before(academicYear.EndDate)
 
@@ -191,12 +201,12 @@ func (s *AcademicYearService) GetAllAcademicYearsWithStats() ([]AcademicYearWith
 		}
 
 		// Get courses for this academic year
-		courses, err := courseRepo.GetByAcademicYear(academicYear.ID)
+		courses, err := s.courseRepo.GetByAcademicYear(academicYear.ID)
 		courseCount := 0
 		if err == nil {
 			courseCount = len(courses)
 		} else {
-			log.Printf("Error getting courses for academic year %d: %v", academicYear.ID, err)
+			slog.Error("Error getting courses of academic year", "academic_year_id", academicYear.ID, "error", err)
 		}
 
 		// Create stats struct
@@ -208,7 +218,7 @@ func (s *AcademicYearService) GetAllAcademicYearsWithStats() ([]AcademicYearWith
 			TotalSchedules: 0, // We'll keep this at 0 for now
 		}
 
-		result[i] = AcademicYearWithStats{
+		result.Items[i] = AcademicYearWithStats{
 			AcademicYear:  academicYear,
 			IsCurrent:     isCurrent,
 			DaysRemaining: daysRemaining,
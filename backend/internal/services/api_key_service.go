@@ -0,0 +1,238 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+const (
+	// apiKeyPrefix marks DelPresence API keys so they are easy to recognize in secret scanners
+	apiKeyPrefix = "dpk"
+
+	// apiKeyTouchInterval limits how often last-used tracking writes to the database
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	// ErrInvalidAPIKey is returned when a key is unknown, malformed, revoked or expired
+	ErrInvalidAPIKey = errors.New("invalid or expired API key")
+)
+
+// APIKeyService manages API keys for kiosks and integrations
+type APIKeyService struct {
+	repository repositories.APIKeyStore
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService() *APIKeyService {
+	return NewAPIKeyServiceWithStores(repositories.NewAPIKeyRepository())
+}
+
+// NewAPIKeyServiceWithStores creates a new API key service on top of the given stores
+func NewAPIKeyServiceWithStores(repository repositories.APIKeyStore) *APIKeyService {
+	return &APIKeyService{
+		repository: repository,
+	}
+}
+
+// CreateKey creates a new API key and returns its plaintext value, which is not stored
+func (s *APIKeyService) CreateKey(req models.APIKeyRequest, createdByID uint) (*models.APIKeyCreatedResponse, error) {
+	if err := validatePermissions(req.Permissions); err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	key := &models.APIKey{
+		Name:            strings.TrimSpace(req.Name),
+		Permissions:     req.Permissions,
+		RoomIDs:         req.RoomIDs,
+		StudyProgramIDs: req.StudyProgramIDs,
+		ExpiresAt:       req.ExpiresAt,
+		CreatedByID:     createdByID,
+	}
+	return s.issue(key)
+}
+
+// ListKeys returns one page of API keys, newest first unless sorted otherwise
+func (s *APIKeyService) ListKeys(q models.ListQuery) (models.ListResult[models.APIKey], error) {
+	return s.repository.List(q)
+}
+
+// GetKeyByID returns an API key by ID
+func (s *APIKeyService) GetKeyByID(id uint) (*models.APIKey, error) {
+	key, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, errors.New("API key not found")
+	}
+	return key, nil
+}
+
+// RotateKey issues a replacement key with the same scopes
+// The old key keeps working for the grace period so deployed kiosks can be updated
+func (s *APIKeyService) RotateKey(id uint, graceMinutes int, rotatedByID uint) (*models.APIKeyCreatedResponse, error) {
+	old, err := s.GetKeyByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !old.IsActive(time.Now()) {
+		return nil, errors.New("cannot rotate a revoked or expired API key")
+	}
+
+	replacement := &models.APIKey{
+		Name:            old.Name,
+		Permissions:     old.Permissions,
+		RoomIDs:         old.RoomIDs,
+		StudyProgramIDs: old.StudyProgramIDs,
+		ExpiresAt:       old.ExpiresAt,
+		CreatedByID:     rotatedByID,
+	}
+	created, err := s.issue(replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if graceMinutes > 0 {
+		graceEnd := now.Add(time.Duration(graceMinutes) * time.Minute)
+		if old.ExpiresAt == nil || old.ExpiresAt.After(graceEnd) {
+			old.ExpiresAt = &graceEnd
+		}
+	} else {
+		old.RevokedAt = &now
+	}
+	old.RotatedToID = &created.APIKey.ID
+	if err := s.repository.Update(old); err != nil {
+		return nil, err
+	}
+
+	slog.Info("API key rotated", "prefix", old.Prefix, "id", old.ID, "new_prefix", created.APIKey.Prefix, "new_id", created.APIKey.ID)
+	return created, nil
+}
+
+// RevokeKey revokes an API key immediately
+func (s *APIKeyService) RevokeKey(id uint) error {
+	key, err := s.GetKeyByID(id)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	key.RevokedAt = &now
+	slog.Info("API key revoked", "prefix", key.Prefix, "id", key.ID)
+	return s.repository.Update(key)
+}
+
+// Authenticate resolves a plaintext key to an active API key and records its use
+func (s *APIKeyService) Authenticate(rawKey, clientIP string) (*models.APIKey, error) {
+	prefix, ok := parseAPIKeyPrefix(rawKey)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.repository.FindByPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(rawKey)), []byte(key.KeyHash)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval || key.LastUsedIP != clientIP {
+		if err := s.repository.TouchLastUsed(key.ID, now, clientIP); err != nil {
+			slog.Error("Error recording use of API key", "prefix", key.Prefix, "error", err)
+		}
+		key.LastUsedAt = &now
+		key.LastUsedIP = clientIP
+	}
+
+	return key, nil
+}
+
+// issue generates the secret for a key, stores its hash and returns the plaintext once
+func (s *APIKeyService) issue(key *models.APIKey) (*models.APIKeyCreatedResponse, error) {
+	prefixBytes := make([]byte, 6)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return nil, err
+	}
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, err
+	}
+
+	key.Prefix = hex.EncodeToString(prefixBytes)
+	rawKey := fmt.Sprintf("%s_%s_%s", apiKeyPrefix, key.Prefix, base64.RawURLEncoding.EncodeToString(secretBytes))
+	key.KeyHash = hashAPIKey(rawKey)
+
+	if err := s.repository.Create(key); err != nil {
+		return nil, err
+	}
+
+	return &models.APIKeyCreatedResponse{
+		Key:    rawKey,
+		APIKey: *key,
+	}, nil
+}
+
+// parseAPIKeyPrefix extracts the lookup prefix from a key of the form dpk_<prefix>_<secret>
+func parseAPIKeyPrefix(rawKey string) (string, bool) {
+	parts := strings.SplitN(strings.TrimSpace(rawKey), "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// hashAPIKey returns the stored digest of a key
+// Keys carry 256 bits of randomness, so a fast hash is enough
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(rawKey)))
+	return hex.EncodeToString(sum[:])
+}
+
+// validatePermissions checks that every requested permission exists
+func validatePermissions(permissions []string) error {
+	if len(permissions) == 0 {
+		return errors.New("at least one permission is required")
+	}
+	for _, p := range permissions {
+		valid := false
+		for _, known := range models.APIKeyPermissions {
+			if p == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown permission: %s", p)
+		}
+	}
+	return nil
+}
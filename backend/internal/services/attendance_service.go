@@ -1,40 +1,93 @@
 package services
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/metrics"
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/repositories"
-	"gorm.io/gorm"
+)
+
+var (
+	// ErrSessionNotActive is returned for check-ins to closed or canceled sessions
+	ErrSessionNotActive = errors.New("attendance session is not active")
+
+	// ErrNotEnrolled is returned when a student checks in to a course they do not take
+	ErrNotEnrolled = errors.New("student is not enrolled in this course")
+
+	// ErrInvalidQRCode is returned when the scanned QR code does not belong to the session
+	ErrInvalidQRCode = errors.New("invalid QR code data")
+
+	// ErrSessionNotFound is returned for check-ins to sessions that do not exist
+	ErrSessionNotFound = errors.New("attendance session not found")
+
+	// ErrQRScheduleMismatch is returned when the scanned session does not belong to the schedule the student picked
+	ErrQRScheduleMismatch = errors.New("attendance session does not belong to the selected schedule")
+
+	// ErrQRCodeNotSupported is returned for QR check-ins to sessions that only take another verification method
+	ErrQRCodeNotSupported = errors.New("this attendance session does not support QR code verification")
+
+	// ErrStudentNotFound is returned when the user checking in has no student record
+	ErrStudentNotFound = errors.New("student record not found")
 )
 
 // AttendanceService handles attendance-related business logic
 type AttendanceService struct {
-	attendanceRepo *repositories.AttendanceRepository
-	scheduleRepo   *repositories.CourseScheduleRepository
-	studentRepo    *repositories.StudentRepository
-	db             *gorm.DB
+	attendanceRepo repositories.AttendanceStore
+	scheduleRepo   repositories.CourseScheduleStore
+	studentRepo    repositories.StudentStore
+	groupRepo      repositories.StudentGroupStore
+	assistantRepo  repositories.TeachingAssistantAssignmentStore
 }
 
 // NewAttendanceService creates a new attendance service
 func NewAttendanceService() *AttendanceService {
+	return NewAttendanceServiceWithStores(
+		repositories.NewAttendanceRepository(),
+		repositories.NewCourseScheduleRepository(),
+		repositories.NewStudentRepository(),
+		repositories.NewStudentGroupRepository(),
+		repositories.NewTeachingAssistantAssignmentRepository(),
+	)
+}
+
+// NewAttendanceServiceWithStores creates a new attendance service on top of the given stores
+func NewAttendanceServiceWithStores(
+	attendanceRepo repositories.AttendanceStore,
+	scheduleRepo repositories.CourseScheduleStore,
+	studentRepo repositories.StudentStore,
+	groupRepo repositories.StudentGroupStore,
+	assistantRepo repositories.TeachingAssistantAssignmentStore,
+) *AttendanceService {
 	return &AttendanceService{
-		attendanceRepo: repositories.NewAttendanceRepository(),
-		scheduleRepo:   repositories.NewCourseScheduleRepository(),
-		studentRepo:    repositories.NewStudentRepository(),
-		db:             database.GetDB(),
+		attendanceRepo: attendanceRepo,
+		scheduleRepo:   scheduleRepo,
+		studentRepo:    studentRepo,
+		groupRepo:      groupRepo,
+		assistantRepo:  assistantRepo,
 	}
 }
 
+// withContext returns a copy of the service whose attendance and schedule queries carry ctx
+func (s *AttendanceService) withContext(ctx context.Context) *AttendanceService {
+	scoped := *s
+	scoped.attendanceRepo = s.attendanceRepo.WithContext(ctx)
+	scoped.scheduleRepo = s.scheduleRepo.WithContext(ctx)
+	return &scoped
+}
+
 // CreateAttendanceSession creates a new attendance session for a course schedule
-func (s *AttendanceService) CreateAttendanceSession(userID uint, courseScheduleID uint, date time.Time, attendanceType models.AttendanceType, settings map[string]interface{}) (*models.AttendanceSession, error) {
+func (s *AttendanceService) CreateAttendanceSession(ctx context.Context, userID uint, courseScheduleID uint, date time.Time, attendanceType models.AttendanceType, settings map[string]interface{}) (*models.AttendanceSession, error) {
+	s = s.withContext(ctx)
+
 	// Check if there's already an active session for this schedule and date
 	existingSession, err := s.attendanceRepo.GetActiveSessionForSchedule(courseScheduleID, date)
 	if err == nil && existingSession.ID != 0 {
@@ -80,12 +133,7 @@ func (s *AttendanceService) CreateAttendanceSession(userID uint, courseScheduleI
 	if schedule.UserID != uint(userID) {
 		// Check if user is a teaching assistant for this course
 		var isAssistant bool
-		err = s.db.Raw(`
-			SELECT EXISTS (
-				SELECT 1 FROM teaching_assistant_assignments 
-				WHERE user_id = ? AND course_id = ?
-			) as is_assistant`,
-			userID, schedule.CourseID).Scan(&isAssistant).Error
+		isAssistant, err = s.assistantRepo.AssignmentExistsForCourse(int(userID), schedule.CourseID)
 
 		if err != nil || !isAssistant {
 			return nil, errors.New("user is neither the assigned lecturer nor a teaching assistant for this course")
@@ -115,8 +163,6 @@ func (s *AttendanceService) CreateAttendanceSession(userID uint, courseScheduleI
 
 	// Apply custom settings if provided
 	if settings != nil {
-		fmt.Printf("Received attendance settings: %+v\n", settings)
-
 		if val, ok := settings["autoClose"].(bool); ok {
 			session.AutoClose = val
 		}
@@ -124,17 +170,12 @@ func (s *AttendanceService) CreateAttendanceSession(userID uint, courseScheduleI
 		// Handle duration - try different type assertions
 		if val, ok := settings["duration"].(int); ok && val > 0 {
 			session.Duration = val
-			fmt.Printf("Set duration to %d (from int)\n", val)
 		} else if val, ok := settings["duration"].(float64); ok && val > 0 {
 			session.Duration = int(val)
-			fmt.Printf("Set duration to %d (from float64 %.2f)\n", int(val), val)
 		} else if val, ok := settings["duration"].(string); ok {
 			if intVal, err := strconv.Atoi(val); err == nil && intVal > 0 {
 				session.Duration = intVal
-				fmt.Printf("Set duration to %d (from string %s)\n", intVal, val)
 			}
-		} else {
-			fmt.Printf("Unable to parse duration from settings: %v, type: %T\n", settings["duration"], settings["duration"])
 		}
 
 		if val, ok := settings["allowLate"].(bool); ok {
@@ -144,22 +185,20 @@ func (s *AttendanceService) CreateAttendanceSession(userID uint, courseScheduleI
 		// Handle lateThreshold - try different type assertions
 		if val, ok := settings["lateThreshold"].(int); ok && val > 0 {
 			session.LateThreshold = val
-			fmt.Printf("Set lateThreshold to %d (from int)\n", val)
 		} else if val, ok := settings["lateThreshold"].(float64); ok && val > 0 {
 			session.LateThreshold = int(val)
-			fmt.Printf("Set lateThreshold to %d (from float64 %.2f)\n", int(val), val)
 		} else if val, ok := settings["lateThreshold"].(string); ok {
 			if intVal, err := strconv.Atoi(val); err == nil && intVal > 0 {
 				session.LateThreshold = intVal
-				fmt.Printf("Set lateThreshold to %d (from string %s)\n", intVal, val)
 			}
-		} else {
-			fmt.Printf("Unable to parse lateThreshold from settings: %v, type: %T\n", settings["lateThreshold"], settings["lateThreshold"])
 		}
 
 		if val, ok := settings["notes"].(string); ok {
 			session.Notes = val
 		}
+
+		slog.DebugContext(ctx, "Applied attendance session settings",
+			"schedule_id", courseScheduleID, "duration", session.Duration, "late_threshold", session.LateThreshold)
 	}
 
 	// For QR code type, generate a unique code
@@ -177,9 +216,9 @@ func (s *AttendanceService) CreateAttendanceSession(userID uint, courseScheduleI
 	}
 
 	// Initialize absent records for all students in the course
-	if err := s.initializeStudentAttendances(session.ID, courseScheduleID); err != nil {
+	if err := s.initializeStudentAttendances(ctx, session.ID, courseScheduleID); err != nil {
 		// Log the error but continue
-		fmt.Printf("Error initializing student attendances: %v\n", err)
+		slog.ErrorContext(ctx, "Error initializing student attendances", "session_id", session.ID, "error", err)
 	}
 
 	return session, nil
@@ -198,21 +237,13 @@ func (s *AttendanceService) CloseAttendanceSession(sessionID uint, userID uint)
 		var isAssistant bool
 
 		// Get the course ID from the session's schedule
-		var courseID uint
-		if err := s.db.Model(&models.CourseSchedule{}).
-			Where("id = ?", session.CourseScheduleID).
-			Select("course_id").
-			First(&courseID).Error; err != nil {
+		courseID, err := s.scheduleRepo.GetCourseID(session.CourseScheduleID)
+		if err != nil {
 			return errors.New("failed to verify course assignment")
 		}
 
 		// Check if the user is a teaching assistant for this course
-		err = s.db.Raw(`
-			SELECT EXISTS (
-				SELECT 1 FROM teaching_assistant_assignments 
-				WHERE user_id = ? AND course_id = ?
-			) as is_assistant`,
-			userID, courseID).Scan(&isAssistant).Error
+		isAssistant, err = s.assistantRepo.AssignmentExistsForCourse(int(userID), courseID)
 
 		if err != nil || !isAssistant {
 			return errors.New("user does not have permission to close this attendance session")
@@ -221,7 +252,7 @@ func (s *AttendanceService) CloseAttendanceSession(sessionID uint, userID uint)
 
 	// Verify that the session is active
 	if session.Status != models.AttendanceStatusActive {
-		return errors.New("attendance session is not active")
+		return ErrSessionNotActive
 	}
 
 	// Update session status and end time
@@ -246,7 +277,7 @@ func (s *AttendanceService) CancelAttendanceSession(sessionID uint, lecturerID u
 
 	// Verify that the session is active
 	if session.Status != models.AttendanceStatusActive {
-		return errors.New("attendance session is not active")
+		return ErrSessionNotActive
 	}
 
 	// Update session status
@@ -256,7 +287,9 @@ func (s *AttendanceService) CancelAttendanceSession(sessionID uint, lecturerID u
 }
 
 // MarkStudentAttendance marks a student's attendance for a session
-func (s *AttendanceService) MarkStudentAttendance(sessionID uint, studentID uint, status models.StudentAttendanceStatus, verificationMethod string, notes string, verifiedByID *uint) error {
+func (s *AttendanceService) MarkStudentAttendance(sessionID uint, studentID uint, status models.StudentAttendanceStatus, verificationMethod string, notes string, verifiedByID *uint) (err error) {
+	defer func() { metrics.RecordCheckIn(metrics.CheckInMethodManual, checkInOutcome(err)) }()
+
 	// Check if the session exists and is active
 	session, err := s.attendanceRepo.GetAttendanceSessionByID(sessionID)
 	if err != nil {
@@ -264,7 +297,7 @@ func (s *AttendanceService) MarkStudentAttendance(sessionID uint, studentID uint
 	}
 
 	if session.Status != models.AttendanceStatusActive {
-		return errors.New("attendance session is not active")
+		return ErrSessionNotActive
 	}
 
 	// Check if the student already has an attendance record
@@ -306,7 +339,9 @@ func (s *AttendanceService) MarkStudentAttendance(sessionID uint, studentID uint
 }
 
 // GetActiveSessionsForUser gets all active attendance sessions for a user (lecturer or teaching assistant)
-func (s *AttendanceService) GetActiveSessionsForUser(userID uint) ([]models.AttendanceSessionResponse, error) {
+func (s *AttendanceService) GetActiveSessionsForUser(ctx context.Context, userID uint) ([]models.AttendanceSessionResponse, error) {
+	s = s.withContext(ctx)
+
 	// First, get sessions where the user is directly the lecturer
 	sessions, err := s.attendanceRepo.ListActiveSessions(userID)
 	if err != nil {
@@ -315,20 +350,15 @@ func (s *AttendanceService) GetActiveSessionsForUser(userID uint) ([]models.Atte
 
 	// For teaching assistants, also get sessions from courses where they are assigned as TAs
 	// First, get all course IDs where the user is a teaching assistant
-	var courseIDs []uint
-	if err := s.db.Raw(`
-		SELECT course_id FROM teaching_assistant_assignments 
-		WHERE user_id = ?`,
-		userID).Scan(&courseIDs).Error; err != nil {
+	courseIDs, err := s.assistantRepo.GetCourseIDsByUser(userID)
+	if err != nil {
 		// Just log the error but continue with direct sessions
-		fmt.Printf("Error fetching TA assignments for user %d: %v\n", userID, err)
+		slog.ErrorContext(ctx, "Error fetching TA assignments", "user_id", userID, "error", err)
 	} else if len(courseIDs) > 0 {
 		// Get schedules for these courses
-		var scheduleIDs []uint
-		if err := s.db.Model(&models.CourseSchedule{}).
-			Where("course_id IN (?)", courseIDs).
-			Pluck("id", &scheduleIDs).Error; err != nil {
-			fmt.Printf("Error fetching course schedules for TA %d: %v\n", userID, err)
+		scheduleIDs, err := s.scheduleRepo.GetIDsByCourses(courseIDs)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error fetching course schedules for TA", "user_id", userID, "error", err)
 		} else if len(scheduleIDs) > 0 {
 			// Get active sessions for these schedules
 			taSessions, err := s.attendanceRepo.ListActiveSessionsBySchedules(scheduleIDs)
@@ -363,7 +393,9 @@ func (s *AttendanceService) GetActiveSessionsForUser(userID uint) ([]models.Atte
 }
 
 // GetActiveSessionsByCourse gets all active attendance sessions for a specific course
-func (s *AttendanceService) GetActiveSessionsByCourse(courseID uint) ([]models.AttendanceSessionResponse, error) {
+func (s *AttendanceService) GetActiveSessionsByCourse(ctx context.Context, courseID uint) ([]models.AttendanceSessionResponse, error) {
+	s = s.withContext(ctx)
+
 	// Get all course schedules for this course
 	schedules, err := s.scheduleRepo.GetByCourse(courseID)
 	if err != nil {
@@ -376,7 +408,7 @@ func (s *AttendanceService) GetActiveSessionsByCourse(courseID uint) ([]models.A
 	for _, schedule := range schedules {
 		sessions, err := s.attendanceRepo.GetActiveSessionsForSchedule(schedule.ID)
 		if err != nil {
-			fmt.Printf("Error getting active sessions for schedule %d: %v\n", schedule.ID, err)
+			slog.ErrorContext(ctx, "Error getting active sessions for schedule", "schedule_id", schedule.ID, "error", err)
 			continue
 		}
 
@@ -393,68 +425,47 @@ func (s *AttendanceService) GetActiveSessionsByCourse(courseID uint) ([]models.A
 	return allResponses, nil
 }
 
-// GetSessionsByDateRange gets attendance sessions for a user within a date range
-func (s *AttendanceService) GetSessionsByDateRange(userID uint, startDate, endDate time.Time) ([]models.AttendanceSessionResponse, error) {
-	// First, get sessions where the user is directly the lecturer
-	sessions, err := s.attendanceRepo.ListSessionsByDateRange(userID, startDate, endDate)
-	if err != nil {
-		return nil, err
-	}
+// ListSessionsByDateRange returns one page of the attendance sessions of a user within a date range
+// Teaching assistants also get the sessions of the courses they are assigned to
+func (s *AttendanceService) ListSessionsByDateRange(ctx context.Context, userID uint, startDate, endDate time.Time, q models.ListQuery) (models.ListResult[models.AttendanceSessionResponse], error) {
+	s = s.withContext(ctx)
+	result := models.ListResult[models.AttendanceSessionResponse]{Items: []models.AttendanceSessionResponse{}}
 
-	// For teaching assistants, also get sessions from courses where they are assigned as TAs
-	// First, get all course IDs where the user is a teaching assistant
-	var courseIDs []uint
-	if err := s.db.Raw(`
-		SELECT course_id FROM teaching_assistant_assignments 
-		WHERE user_id = ?`,
-		userID).Scan(&courseIDs).Error; err != nil {
-		// Just log the error but continue with direct sessions
-		fmt.Printf("Error fetching TA assignments for user %d: %v\n", userID, err)
-	} else if len(courseIDs) > 0 {
-		// Get schedules for these courses
-		var scheduleIDs []uint
-		if err := s.db.Model(&models.CourseSchedule{}).
-			Where("course_id IN (?)", courseIDs).
-			Pluck("id", &scheduleIDs).Error; err != nil {
-			fmt.Printf("Error fetching course schedules for TA %d: %v\n", userID, err)
-		} else if len(scheduleIDs) > 0 {
-			// Get sessions for these schedules in the given date range
-			var taSessions []models.AttendanceSession
-			err := s.db.Preload("CourseSchedule").
-				Preload("CourseSchedule.Course").
-				Preload("CourseSchedule.Room").
-				Where("course_schedule_id IN (?) AND date BETWEEN ? AND ?",
-					scheduleIDs, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
-				Find(&taSessions).Error
-
-			if err == nil {
-				// Add these sessions to the list, avoiding duplicates
-				sessionMap := make(map[uint]models.AttendanceSession)
-				for _, s := range sessions {
-					sessionMap[s.ID] = s
-				}
-
-				for _, s := range taSessions {
-					if _, exists := sessionMap[s.ID]; !exists {
-						sessionMap[s.ID] = s
-						sessions = append(sessions, s)
-					}
-				}
-			}
-		}
+	page, err := s.attendanceRepo.ListSessionsForUser(userID, s.assistedScheduleIDs(ctx, userID), startDate, endDate, q)
+	if err != nil {
+		return result, err
 	}
 
 	// Transform to response objects
-	var responses []models.AttendanceSessionResponse
-	for _, session := range sessions {
+	result.Page = page.Page
+	for _, session := range page.Items {
 		response, err := s.mapSessionToResponse(&session)
 		if err != nil {
 			continue
 		}
-		responses = append(responses, *response)
+		result.Items = append(result.Items, *response)
 	}
+	return result, nil
+}
 
-	return responses, nil
+// assistedScheduleIDs returns the course schedules of the courses a user assists in as teaching assistant
+// Errors are only logged, the user still gets the sessions they started themselves
+func (s *AttendanceService) assistedScheduleIDs(ctx context.Context, userID uint) []uint {
+	courseIDs, err := s.assistantRepo.GetCourseIDsByUser(userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching TA assignments", "user_id", userID, "error", err)
+		return nil
+	}
+	if len(courseIDs) == 0 {
+		return nil
+	}
+
+	scheduleIDs, err := s.scheduleRepo.GetIDsByCourses(courseIDs)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching course schedules for TA", "user_id", userID, "error", err)
+		return nil
+	}
+	return scheduleIDs
 }
 
 // GetSessionDetails gets detailed information for an attendance session
@@ -470,21 +481,13 @@ func (s *AttendanceService) GetSessionDetails(sessionID uint, userID uint) (*mod
 		var isAssistant bool
 
 		// Get the course ID from the session's schedule
-		var courseID uint
-		if err := s.db.Model(&models.CourseSchedule{}).
-			Where("id = ?", session.CourseScheduleID).
-			Select("course_id").
-			First(&courseID).Error; err != nil {
+		courseID, err := s.scheduleRepo.GetCourseID(session.CourseScheduleID)
+		if err != nil {
 			return nil, errors.New("failed to verify course assignment")
 		}
 
 		// Check if the user is a teaching assistant for this course
-		err = s.db.Raw(`
-			SELECT EXISTS (
-				SELECT 1 FROM teaching_assistant_assignments 
-				WHERE user_id = ? AND course_id = ?
-			) as is_assistant`,
-			userID, courseID).Scan(&isAssistant).Error
+		isAssistant, err = s.assistantRepo.AssignmentExistsForCourse(int(userID), courseID)
 
 		if err != nil || !isAssistant {
 			return nil, errors.New("user does not have access to this session")
@@ -508,21 +511,13 @@ func (s *AttendanceService) GetStudentAttendances(sessionID uint, userID uint) (
 		var isAssistant bool
 
 		// Get the course ID from the session's schedule
-		var courseID uint
-		if err := s.db.Model(&models.CourseSchedule{}).
-			Where("id = ?", session.CourseScheduleID).
-			Select("course_id").
-			First(&courseID).Error; err != nil {
+		courseID, err := s.scheduleRepo.GetCourseID(session.CourseScheduleID)
+		if err != nil {
 			return nil, errors.New("failed to verify course assignment")
 		}
 
 		// Check if the user is a teaching assistant for this course
-		err = s.db.Raw(`
-			SELECT EXISTS (
-				SELECT 1 FROM teaching_assistant_assignments 
-				WHERE user_id = ? AND course_id = ?
-			) as is_assistant`,
-			userID, courseID).Scan(&isAssistant).Error
+		isAssistant, err = s.assistantRepo.AssignmentExistsForCourse(int(userID), courseID)
 
 		if err != nil || !isAssistant {
 			return nil, errors.New("user does not have access to this session")
@@ -587,8 +582,15 @@ func (s *AttendanceService) GetAttendanceStatistics(courseScheduleID uint, lectu
 	return s.attendanceRepo.GetAttendanceStats(courseScheduleID)
 }
 
+// CountActiveSessions counts the attendance sessions that are currently active
+func (s *AttendanceService) CountActiveSessions() (int64, error) {
+	return s.attendanceRepo.CountActiveSessions()
+}
+
 // GetActiveSessionsBySchedules gets all active attendance sessions for specific schedules
-func (s *AttendanceService) GetActiveSessionsBySchedules(scheduleIDs []uint) ([]models.AttendanceSession, error) {
+func (s *AttendanceService) GetActiveSessionsBySchedules(ctx context.Context, scheduleIDs []uint) ([]models.AttendanceSession, error) {
+	s = s.withContext(ctx)
+
 	if len(scheduleIDs) == 0 {
 		return []models.AttendanceSession{}, nil
 	}
@@ -603,64 +605,57 @@ func (s *AttendanceService) GetActiveSessionsBySchedules(scheduleIDs []uint) ([]
 }
 
 // MarkStudentAttendanceViaQR marks a student's attendance for a session using QR code
-func (s *AttendanceService) MarkStudentAttendanceViaQR(sessionID uint, userID uint, status models.StudentAttendanceStatus, qrData string) error {
+func (s *AttendanceService) MarkStudentAttendanceViaQR(ctx context.Context, sessionID uint, userID uint, status models.StudentAttendanceStatus, qrData string) (result *models.CheckInResponse, err error) {
+	s = s.withContext(ctx)
+
+	defer func() { metrics.RecordCheckIn(metrics.CheckInMethodQRCode, qrCheckInOutcome(result, err)) }()
+
 	// Get the session by ID
 	session, err := s.attendanceRepo.GetAttendanceSessionByID(sessionID)
 	if err != nil {
-		return errors.New("attendance session not found")
+		return nil, ErrSessionNotFound
 	}
 
 	// Check if the session is active
 	if session.Status != models.AttendanceStatusActive {
-		return errors.New("attendance session is not active")
+		return nil, ErrSessionNotActive
 	}
 
 	// Check that this is a QR code attendance or combined method
 	if session.Type != models.AttendanceTypeQRCode && session.Type != models.AttendanceTypeBoth {
-		return errors.New("this attendance session does not support QR code verification")
+		return nil, ErrQRCodeNotSupported
 	}
 
 	// Check if the student is enrolled in this course
-	var student *models.Student
-	result := s.db.Where("user_id = ?", userID).First(&student)
-	if result.Error != nil {
-		return errors.New("student record not found")
+	student, err := s.studentRepo.FindByUserID(int(userID))
+	if err != nil || student == nil {
+		return nil, ErrStudentNotFound
 	}
 
 	// Get the course schedule to find the student group
-	var schedule models.CourseSchedule
-	if err := s.db.First(&schedule, session.CourseScheduleID).Error; err != nil {
-		return errors.New("course schedule not found")
+	schedule, err := s.scheduleRepo.GetByID(session.CourseScheduleID)
+	if err != nil {
+		return nil, errors.New("course schedule not found")
 	}
 
 	// Check if the student is in the course's student group
 	var isEnrolled bool
-	err = s.db.Raw(`
-		SELECT EXISTS (
-			SELECT 1 FROM student_to_groups
-			WHERE student_group_id = ? AND student_id = ?
-		) as is_enrolled`,
-		schedule.StudentGroupID, student.ID).Scan(&isEnrolled).Error
+	isEnrolled, err = s.groupRepo.IsStudentInGroup(schedule.StudentGroupID, student.ID)
 
 	if err != nil {
-		return errors.New("error checking enrollment: " + err.Error())
+		return nil, errors.New("error checking enrollment: " + err.Error())
 	}
 
 	if !isEnrolled {
-		return errors.New("student is not enrolled in this course")
+		return nil, ErrNotEnrolled
 	}
 
 	// If QR data was provided, verify it
 	if qrData != "" && qrData != fmt.Sprintf("delpresence:attendance:%d", sessionID) && qrData != session.QRCodeData {
-		fmt.Printf("QR Code verification failed. Provided: %s, Expected: %s or %s\n",
-			qrData, fmt.Sprintf("delpresence:attendance:%d", sessionID), session.QRCodeData)
-		return errors.New("invalid QR code data")
+		slog.WarnContext(ctx, "QR code verification failed", "session_id", sessionID, "student_id", student.ID)
+		return nil, ErrInvalidQRCode
 	}
 
-	// Calculate if the student is late based on session settings
-	now := GetIndonesiaTime()
-	checkInTime := now
-
 	// Check if the student is late based on session settings
 	if session.AllowLate && time.Since(session.StartTime).Minutes() > float64(session.LateThreshold) {
 		status = models.StudentAttendanceStatusLate
@@ -669,47 +664,30 @@ func (s *AttendanceService) MarkStudentAttendanceViaQR(sessionID uint, userID ui
 	// Create notes that include external user ID information
 	notes := fmt.Sprintf("External UserID: %d | NIM: %s", student.UserID, student.NIM)
 
-	// Find existing attendance record
-	var attendanceExists bool
-	err = s.db.Raw(`
-		SELECT EXISTS (
-			SELECT 1 FROM student_attendances
-			WHERE attendance_session_id = ? AND student_id = ?
-		) as attendance_exists
-	`, sessionID, student.ID).Scan(&attendanceExists).Error
-
-	if err != nil {
-		return errors.New("error checking existing attendance: " + err.Error())
-	}
+	return s.recordQRCheckIn(ctx, sessionID, student.ID, status, notes)
+}
 
-	if attendanceExists {
-		// Update existing record
-		result = s.db.Exec(`
-			UPDATE student_attendances 
-			SET status = ?, verification_method = ?, check_in_time = ?, notes = ?
-			WHERE attendance_session_id = ? AND student_id = ?`,
-			status, "QR_CODE", checkInTime, notes, sessionID, student.ID)
-
-		if result.Error != nil {
-			return errors.New("failed to update attendance: " + result.Error.Error())
-		}
-	} else {
-		// Insert new record
-		attendance := models.StudentAttendance{
-			AttendanceSessionID: sessionID,
-			StudentID:           student.ID,
-			Status:              status,
-			CheckInTime:         &checkInTime,
-			VerificationMethod:  "QR_CODE",
-			Notes:               notes,
-		}
+// checkInOutcome classifies the result of a check-in for the check-in metrics
+func checkInOutcome(err error) string {
+	switch {
+	case err == nil:
+		return metrics.CheckInSuccess
+	case errors.Is(err, ErrInvalidQRCode):
+		return metrics.CheckInInvalidQR
+	case errors.Is(err, ErrNotEnrolled):
+		return metrics.CheckInNotEnrolled
+	case errors.Is(err, ErrSessionNotActive):
+		return metrics.CheckInSessionClosed
+	}
+	return metrics.CheckInError
+}
 
-		if err := s.db.Create(&attendance).Error; err != nil {
-			return errors.New("failed to record attendance: " + err.Error())
-		}
+// qrCheckInOutcome classifies the result of a QR check-in, repeated check-ins are counted apart
+func qrCheckInOutcome(result *models.CheckInResponse, err error) string {
+	if err == nil && result != nil && result.AlreadyCheckedIn {
+		return metrics.CheckInDuplicate
 	}
-
-	return nil
+	return checkInOutcome(err)
 }
 
 // GetIndonesiaTime returns current time in Indonesia Western Time (WIB/UTC+7)
@@ -718,147 +696,113 @@ func GetIndonesiaTime() time.Time {
 }
 
 // MarkStudentAttendanceByExternalID marks a student's attendance using their external user ID
-func (s *AttendanceService) MarkStudentAttendanceByExternalID(sessionID uint, externalUserID uint, status models.StudentAttendanceStatus, qrData string) error {
+// Repeated check-ins are not errors, they return the first check-in with AlreadyCheckedIn set
+func (s *AttendanceService) MarkStudentAttendanceByExternalID(ctx context.Context, sessionID uint, externalUserID uint, status models.StudentAttendanceStatus, qrData string) (result *models.CheckInResponse, err error) {
+	s = s.withContext(ctx)
+
+	defer func() { metrics.RecordCheckIn(metrics.CheckInMethodQRCode, qrCheckInOutcome(result, err)) }()
+
 	// Get the session by ID
 	session, err := s.attendanceRepo.GetAttendanceSessionByID(sessionID)
 	if err != nil {
-		return errors.New("attendance session not found")
+		return nil, ErrSessionNotFound
 	}
 
 	// Check if the session is active
 	if session.Status != models.AttendanceStatusActive {
-		return errors.New("attendance session is not active")
+		return nil, ErrSessionNotActive
 	}
 
 	// Check that this is a QR code attendance or combined method
 	if session.Type != models.AttendanceTypeQRCode && session.Type != models.AttendanceTypeBoth {
-		return errors.New("this attendance session does not support QR code verification")
+		return nil, ErrQRCodeNotSupported
 	}
 
 	// Check if the student exists with this external user ID
-	var student *models.Student
-	result := s.db.Where("user_id = ?", externalUserID).First(&student)
-	if result.Error != nil {
-		return errors.New("student record not found")
+	student, err := s.studentRepo.FindByUserID(int(externalUserID))
+	if err != nil || student == nil {
+		return nil, ErrStudentNotFound
 	}
 
 	// Get the course schedule to find the student group
-	var schedule models.CourseSchedule
-	if err := s.db.First(&schedule, session.CourseScheduleID).Error; err != nil {
-		return errors.New("course schedule not found")
+	schedule, err := s.scheduleRepo.GetByID(session.CourseScheduleID)
+	if err != nil {
+		return nil, errors.New("course schedule not found")
 	}
 
 	// Check if the student is in the course's student group
 	var isEnrolled bool
-	err = s.db.Raw(`
-		SELECT EXISTS (
-			SELECT 1 FROM student_to_groups
-			WHERE student_group_id = ? AND student_id = ?
-		) as is_enrolled`,
-		schedule.StudentGroupID, student.ID).Scan(&isEnrolled).Error
+	isEnrolled, err = s.groupRepo.IsStudentInGroup(schedule.StudentGroupID, student.ID)
 
 	if err != nil {
-		return errors.New("error checking enrollment: " + err.Error())
+		return nil, errors.New("error checking enrollment: " + err.Error())
 	}
 
 	if !isEnrolled {
-		return errors.New("student is not enrolled in this course")
+		return nil, ErrNotEnrolled
 	}
 
 	// If QR data was provided, verify it
 	if qrData != "" && qrData != fmt.Sprintf("delpresence:attendance:%d", sessionID) && qrData != session.QRCodeData {
-		fmt.Printf("QR Code verification failed. Provided: %s, Expected: %s or %s\n",
-			qrData, fmt.Sprintf("delpresence:attendance:%d", sessionID), session.QRCodeData)
-		return errors.New("invalid QR code data")
+		slog.WarnContext(ctx, "QR code verification failed", "session_id", sessionID, "student_id", student.ID)
+		return nil, ErrInvalidQRCode
 	}
 
-	// Calculate if the student is late based on session settings
-	now := GetIndonesiaTime()
-	checkInTime := now
-
 	// Check if the student is late based on session settings
 	if session.AllowLate && time.Since(session.StartTime).Minutes() > float64(session.LateThreshold) {
 		status = models.StudentAttendanceStatusLate
 	}
 
 	// Keep notes empty - as requested
-	notes := ""
-
-	// Find existing attendance record by external user ID
-	var attendanceExists bool
-	err = s.db.Raw(`
-		SELECT EXISTS (
-			SELECT 1 FROM student_attendances sa
-			JOIN students s ON sa.student_id = s.id
-			WHERE sa.attendance_session_id = ? AND s.user_id = ?
-		) as attendance_exists
-	`, sessionID, externalUserID).Scan(&attendanceExists).Error
+	return s.recordQRCheckIn(ctx, sessionID, student.ID, status, "")
+}
 
+// recordQRCheckIn stores a QR check-in, the first check-in of a student in a session wins
+// A later check-in, such as a double tap or a retry, does not turn a PRESENT into a LATE
+func (s *AttendanceService) recordQRCheckIn(ctx context.Context, sessionID, studentID uint, status models.StudentAttendanceStatus, notes string) (*models.CheckInResponse, error) {
+	checkInTime := GetIndonesiaTime()
+	attendance, recorded, err := s.attendanceRepo.CheckInStudent(&models.StudentAttendance{
+		AttendanceSessionID: sessionID,
+		StudentID:           studentID,
+		Status:              status,
+		CheckInTime:         &checkInTime,
+		VerificationMethod:  "QR_CODE",
+		Notes:               notes,
+	})
 	if err != nil {
-		return errors.New("error checking existing attendance: " + err.Error())
-	}
-
-	if attendanceExists {
-		// Update existing record using SQL that links by external user ID
-		result = s.db.Exec(`
-			UPDATE student_attendances 
-			SET status = ?, verification_method = ?, check_in_time = ?, notes = ?
-			WHERE attendance_session_id = ? 
-			AND student_id IN (
-				SELECT id FROM students WHERE user_id = ?
-			)`,
-			status, "QR_CODE", checkInTime, notes, sessionID, externalUserID)
-
-		if result.Error != nil {
-			return errors.New("failed to update attendance: " + result.Error.Error())
-		}
-	} else {
-		// For new records, log only the essential information
-		fmt.Printf("Recording attendance for session %d, student ID %d\n", sessionID, student.ID)
-
-		// Insert new record
-		attendance := models.StudentAttendance{
-			AttendanceSessionID: sessionID,
-			StudentID:           student.ID,
-			Status:              status,
-			CheckInTime:         &checkInTime,
-			VerificationMethod:  "QR_CODE",
-			Notes:               notes,
-		}
+		return nil, errors.New("failed to record attendance: " + err.Error())
+	}
 
-		if err := s.db.Create(&attendance).Error; err != nil {
-			return errors.New("failed to record attendance: " + err.Error())
-		}
+	if recorded {
+		slog.InfoContext(ctx, "Recording attendance", "session_id", sessionID, "student_id", studentID)
+	} else {
+		slog.InfoContext(ctx, "Student already checked in", "session_id", sessionID, "student_id", studentID, "status", attendance.Status)
 	}
 
-	return nil
+	return &models.CheckInResponse{
+		AttendanceID:        attendance.ID,
+		AttendanceSessionID: attendance.AttendanceSessionID,
+		Status:              attendance.Status,
+		CheckInTime:         attendance.CheckInTime,
+		VerificationMethod:  attendance.VerificationMethod,
+		AlreadyCheckedIn:    !recorded,
+	}, nil
 }
 
 // GetStudentAttendancesByExternalID gets attendance records for a student by external user ID
 func (s *AttendanceService) GetStudentAttendancesByExternalID(externalUserID uint) ([]models.StudentAttendanceResponse, error) {
 	// Find the student ID associated with the user ID
-	var studentID uint
-	err := s.db.Raw(`
-		SELECT id FROM students WHERE user_id = ?
-	`, externalUserID).Scan(&studentID).Error
-
+	student, err := s.studentRepo.FindByUserID(int(externalUserID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to find student with user ID %d: %v", externalUserID, err)
 	}
 
-	if studentID == 0 {
+	if student == nil {
 		return nil, fmt.Errorf("no student found with user ID %d", externalUserID)
 	}
 
 	// Get all student attendances
-	var attendances []models.StudentAttendance
-	err = s.db.Preload("AttendanceSession").
-		Preload("AttendanceSession.CourseSchedule").
-		Preload("AttendanceSession.CourseSchedule.Course").
-		Preload("AttendanceSession.CourseSchedule.Room").
-		Preload("Student").
-		Where("student_id = ?", studentID).
-		Find(&attendances).Error
+	attendances, err := s.attendanceRepo.ListStudentAttendancesByStudent(student.ID)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch attendances: %v", err)
@@ -889,40 +833,28 @@ func (s *AttendanceService) GetStudentAttendancesByExternalID(externalUserID uin
 	return responses, nil
 }
 
-// GetStudentAttendanceHistory gets detailed attendance history for a student with course info
-func (s *AttendanceService) GetStudentAttendanceHistory(externalUserID uint) ([]models.StudentAttendanceHistoryResponse, error) {
-	// Find the student ID associated with the user ID
-	var studentID uint
-	err := s.db.Raw(`
-		SELECT id FROM students WHERE user_id = ?
-	`, externalUserID).Scan(&studentID).Error
+// ListStudentAttendanceHistory returns one page of the attendance history of a student with course info
+func (s *AttendanceService) ListStudentAttendanceHistory(externalUserID uint, q models.ListQuery) (models.ListResult[models.StudentAttendanceHistoryResponse], error) {
+	result := models.ListResult[models.StudentAttendanceHistoryResponse]{Items: []models.StudentAttendanceHistoryResponse{}}
 
+	// Find the student ID associated with the user ID
+	student, err := s.studentRepo.FindByUserID(int(externalUserID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to find student with user ID %d: %v", externalUserID, err)
+		return result, fmt.Errorf("failed to find student with user ID %d: %v", externalUserID, err)
 	}
 
-	if studentID == 0 {
-		return nil, fmt.Errorf("no student found with user ID %d", externalUserID)
+	if student == nil {
+		return result, fmt.Errorf("no student found with user ID %d", externalUserID)
 	}
 
-	// Get all student attendances with necessary relations
-	var attendances []models.StudentAttendance
-	err = s.db.Preload("AttendanceSession").
-		Preload("AttendanceSession.CourseSchedule").
-		Preload("AttendanceSession.CourseSchedule.Course").
-		Preload("AttendanceSession.CourseSchedule.Room").
-		Preload("AttendanceSession.CourseSchedule.Room.Building").
-		Preload("Student").
-		Where("student_id = ?", studentID).
-		Order("attendance_session_id DESC"). // Latest sessions first
-		Find(&attendances).Error
-
+	// Get the page of student attendances with necessary relations
+	page, err := s.attendanceRepo.ListStudentAttendanceHistory(student.ID, q)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch attendance history: %v", err)
+		return result, fmt.Errorf("failed to fetch attendance history: %w", err)
 	}
 
-	var responses []models.StudentAttendanceHistoryResponse
-	for _, attendance := range attendances {
+	result.Page = page.Page
+	for _, attendance := range page.Items {
 		if attendance.AttendanceSession.ID == 0 ||
 			attendance.AttendanceSession.CourseSchedule.ID == 0 ||
 			attendance.AttendanceSession.CourseSchedule.Course.ID == 0 {
@@ -948,7 +880,7 @@ func (s *AttendanceService) GetStudentAttendanceHistory(externalUserID uint) ([]
 			fullRoomName = fmt.Sprintf("%s - %s", buildingName, roomName)
 		}
 
-		responses = append(responses, models.StudentAttendanceHistoryResponse{
+		result.Items = append(result.Items, models.StudentAttendanceHistoryResponse{
 			ID:                 attendance.ID,
 			Date:               attendance.AttendanceSession.Date.Format("2006-01-02"),
 			CourseCode:         attendance.AttendanceSession.CourseSchedule.Course.Code,
@@ -960,13 +892,13 @@ func (s *AttendanceService) GetStudentAttendanceHistory(externalUserID uint) ([]
 		})
 	}
 
-	return responses, nil
+	return result, nil
 }
 
 // Helper functions
 
 // initializeStudentAttendances creates initial "absent" records for all students
-func (s *AttendanceService) initializeStudentAttendances(sessionID uint, courseScheduleID uint) error {
+func (s *AttendanceService) initializeStudentAttendances(ctx context.Context, sessionID uint, courseScheduleID uint) error {
 	// For simplicity, we'll use a placeholder implementation
 	// In a real system, you'd query students enrolled in the course schedule
 
@@ -982,11 +914,7 @@ func (s *AttendanceService) initializeStudentAttendances(sessionID uint, courseS
 	}
 
 	// Find all students in this group using the student_to_groups table
-	var students []models.Student
-	err = s.db.Table("students").
-		Joins("JOIN student_to_groups ON students.id = student_to_groups.student_id").
-		Where("student_to_groups.student_group_id = ?", schedule.StudentGroupID).
-		Find(&students).Error
+	students, err := s.groupRepo.GetActiveMembersByStudentID(schedule.StudentGroupID)
 
 	if err != nil {
 		return err
@@ -1000,7 +928,7 @@ func (s *AttendanceService) initializeStudentAttendances(sessionID uint, courseS
 		}
 		if err := s.attendanceRepo.CreateStudentAttendance(attendance); err != nil {
 			// Log the error but continue with other students
-			fmt.Printf("Error initializing attendance for student %d: %v\n", student.ID, err)
+			slog.ErrorContext(ctx, "Error initializing attendance for student", "session_id", sessionID, "student_id", student.ID, "error", err)
 		}
 	}
 
@@ -1047,10 +975,7 @@ func (s *AttendanceService) mapSessionToResponse(session *models.AttendanceSessi
 	// Calculate total students directly from database if CourseSchedule.Enrolled is 0
 	totalStudents := session.CourseSchedule.Enrolled
 	if totalStudents == 0 && session.CourseSchedule.StudentGroupID > 0 {
-		var count int64
-		s.db.Model(&models.StudentToGroup{}).
-			Where("student_group_id = ?", session.CourseSchedule.StudentGroupID).
-			Count(&count)
+		count, _ := s.groupRepo.CountMembers(session.CourseSchedule.StudentGroupID)
 		totalStudents = int(count)
 	}
 
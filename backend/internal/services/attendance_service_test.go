@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+// attendanceFixture is an attendance service on in-memory stores with one QR session
+// of a schedule whose group has one member
+type attendanceFixture struct {
+	service     *AttendanceService
+	attendances *repositories.MemoryAttendanceStore
+	session     *models.AttendanceSession
+	member      models.Student
+	outsider    models.Student
+}
+
+func newAttendanceFixture(t *testing.T) *attendanceFixture {
+	t.Helper()
+	students := repositories.NewMemoryStudentStore()
+	groups := repositories.NewMemoryStudentGroupStore(students)
+	schedules := repositories.NewMemoryCourseScheduleStore()
+	attendances := repositories.NewMemoryAttendanceStore(schedules, students)
+
+	member := students.Add(models.Student{UserID: 2001, NIM: "11S001", FullName: "Member"})
+	outsider := students.Add(models.Student{UserID: 2002, NIM: "11S002", FullName: "Outsider"})
+	group, err := groups.Create(models.StudentGroup{Name: "11IF1"})
+	if err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	if err := groups.AddStudentToGroup(group.ID, member.ID); err != nil {
+		t.Fatalf("add to group: %v", err)
+	}
+	schedule, err := schedules.Create(models.CourseSchedule{
+		CourseID: 1, RoomID: 1, UserID: 1, StudentGroupID: group.ID, AcademicYearID: 1,
+		Day: "senin", StartTime: "08:00", EndTime: "10:00",
+	})
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+
+	now := time.Now()
+	session := &models.AttendanceSession{
+		CourseScheduleID: schedule.ID, LecturerID: 1, Date: now, StartTime: now,
+		Type: models.AttendanceTypeQRCode, Status: models.AttendanceStatusActive, LateThreshold: 10,
+	}
+	if err := attendances.CreateAttendanceSession(session); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	return &attendanceFixture{
+		service:     NewAttendanceServiceWithStores(attendances, schedules, students, groups, nil),
+		attendances: attendances,
+		session:     session,
+		member:      member,
+		outsider:    outsider,
+	}
+}
+
+func TestQRCheckInConcurrentScansRecordOneAttendance(t *testing.T) {
+	f := newAttendanceFixture(t)
+
+	const scans = 20
+	results := make(chan *models.CheckInResponse, scans)
+	var wg sync.WaitGroup
+	for i := 0; i < scans; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := f.service.MarkStudentAttendanceViaQR(context.Background(), f.session.ID, uint(f.member.UserID), models.StudentAttendanceStatusPresent, "")
+			if err != nil {
+				t.Errorf("check in: %v", err)
+				return
+			}
+			results <- result
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	recorded := 0
+	var attendanceID uint
+	for result := range results {
+		if !result.AlreadyCheckedIn {
+			recorded++
+		}
+		if attendanceID != 0 && result.AttendanceID != attendanceID {
+			t.Errorf("got attendance %d and %d, want one record", attendanceID, result.AttendanceID)
+		}
+		attendanceID = result.AttendanceID
+	}
+	if recorded != 1 {
+		t.Errorf("%d check-ins were recorded, want 1", recorded)
+	}
+
+	attendances, err := f.attendances.ListStudentAttendances(f.session.ID)
+	if err != nil {
+		t.Fatalf("list attendances: %v", err)
+	}
+	if len(attendances) != 1 || attendances[0].Status != models.StudentAttendanceStatusPresent {
+		t.Errorf("got attendances %+v, want one PRESENT record", attendances)
+	}
+}
+
+func TestQRCheckInRejectsStudentOutsideGroup(t *testing.T) {
+	f := newAttendanceFixture(t)
+
+	_, err := f.service.MarkStudentAttendanceViaQR(context.Background(), f.session.ID, uint(f.outsider.UserID), models.StudentAttendanceStatusPresent, "")
+	if !errors.Is(err, ErrNotEnrolled) {
+		t.Errorf("got %v, want %v", err, ErrNotEnrolled)
+	}
+}
+
+func TestQRCheckInRejectsClosedSession(t *testing.T) {
+	f := newAttendanceFixture(t)
+	if err := f.attendances.CloseSessions([]uint{f.session.ID}, time.Now()); err != nil {
+		t.Fatalf("close session: %v", err)
+	}
+
+	_, err := f.service.MarkStudentAttendanceViaQR(context.Background(), f.session.ID, uint(f.member.UserID), models.StudentAttendanceStatusPresent, "")
+	if !errors.Is(err, ErrSessionNotActive) {
+		t.Errorf("got %v, want %v", err, ErrSessionNotActive)
+	}
+}
+
+func TestQRCheckInRejectsQRCodeOfAnotherSession(t *testing.T) {
+	f := newAttendanceFixture(t)
+
+	_, err := f.service.MarkStudentAttendanceViaQR(context.Background(), f.session.ID, uint(f.member.UserID), models.StudentAttendanceStatusPresent, "delpresence:attendance:999")
+	if !errors.Is(err, ErrInvalidQRCode) {
+		t.Errorf("got %v, want %v", err, ErrInvalidQRCode)
+	}
+}
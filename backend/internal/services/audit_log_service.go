@@ -0,0 +1,28 @@
+package services
+
+import (
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+// AuditLogService provides access to the audit log
+type AuditLogService struct {
+	repository repositories.AuditLogStore
+}
+
+// NewAuditLogService creates a new audit log service
+func NewAuditLogService() *AuditLogService {
+	return NewAuditLogServiceWithStores(repositories.NewAuditLogRepository())
+}
+
+// NewAuditLogServiceWithStores creates a new audit log service on top of the given stores
+func NewAuditLogServiceWithStores(repository repositories.AuditLogStore) *AuditLogService {
+	return &AuditLogService{
+		repository: repository,
+	}
+}
+
+// ListLogs returns one page of audit log entries, newest first unless sorted otherwise
+func (s *AuditLogService) ListLogs(q models.ListQuery) (models.ListResult[models.AuditLog], error) {
+	return s.repository.List(q)
+}
@@ -7,15 +7,31 @@ import (
 	"github.com/delpresence/backend/internal/repositories"
 )
 
+var (
+	// ErrBuildingNotFound is returned for buildings that do not exist
+	ErrBuildingNotFound = errors.New("building not found")
+
+	// ErrBuildingCodeExists is returned when another building has the code
+	ErrBuildingCodeExists = errors.New("building code is already in use")
+
+	// ErrBuildingHasRooms is returned when deleting a building that still has rooms
+	ErrBuildingHasRooms = errors.New("building still has rooms")
+)
+
 // BuildingService is a service for building operations
 type BuildingService struct {
-	repository *repositories.BuildingRepository
+	repository repositories.BuildingStore
 }
 
 // NewBuildingService creates a new building service
 func NewBuildingService() *BuildingService {
+	return NewBuildingServiceWithStores(repositories.NewBuildingRepository())
+}
+
+// NewBuildingServiceWithStores creates a new building service on top of the given stores
+func NewBuildingServiceWithStores(repository repositories.BuildingStore) *BuildingService {
 	return &BuildingService{
-		repository: repositories.NewBuildingRepository(),
+		repository: repository,
 	}
 }
 
@@ -54,7 +70,7 @@ func (s *BuildingService) CreateBuilding(building *models.Building) error {
 			return s.repository.Update(restoredBuilding)
 		}
 		
-		return errors.New("kode gedung sudah digunakan")
+		return ErrBuildingCodeExists
 	}
 
 	// Create building
@@ -69,7 +85,7 @@ func (s *BuildingService) UpdateBuilding(building *models.Building) error {
 		return err
 	}
 	if existingBuilding == nil {
-		return errors.New("gedung tidak ditemukan")
+		return ErrBuildingNotFound
 	}
 
 	// If code is changed, check if new code already exists
@@ -79,7 +95,7 @@ func (s *BuildingService) UpdateBuilding(building *models.Building) error {
 			return err
 		}
 		if exists {
-			return errors.New("kode gedung sudah digunakan")
+			return ErrBuildingCodeExists
 		}
 	}
 
@@ -92,9 +108,9 @@ func (s *BuildingService) GetBuildingByID(id uint) (*models.Building, error) {
 	return s.repository.FindByID(id)
 }
 
-// GetAllBuildings gets all buildings
-func (s *BuildingService) GetAllBuildings() ([]models.Building, error) {
-	return s.repository.FindAll()
+// ListBuildings returns one page of buildings
+func (s *BuildingService) ListBuildings(q models.ListQuery) (models.ListResult[models.Building], error) {
+	return s.repository.List(q)
 }
 
 // DeleteBuilding deletes a building
@@ -105,7 +121,7 @@ func (s *BuildingService) DeleteBuilding(id uint) error {
 		return err
 	}
 	if building == nil {
-		return errors.New("gedung tidak ditemukan")
+		return ErrBuildingNotFound
 	}
 
 	// Check if there are any associated rooms
@@ -114,7 +130,7 @@ func (s *BuildingService) DeleteBuilding(id uint) error {
 		return err
 	}
 	if roomCount > 0 {
-		return errors.New("tidak dapat menghapus gedung yang memiliki ruangan")
+		return ErrBuildingHasRooms
 	}
 
 	// Delete building (soft delete)
@@ -135,7 +151,7 @@ func (s *BuildingService) GetBuildingWithStats(id uint) (*BuildingWithStats, err
 		return nil, err
 	}
 	if building == nil {
-		return nil, errors.New("gedung tidak ditemukan")
+		return nil, ErrBuildingNotFound
 	}
 
 	// Count rooms
@@ -151,24 +167,23 @@ func (s *BuildingService) GetBuildingWithStats(id uint) (*BuildingWithStats, err
 	}, nil
 }
 
-// GetAllBuildingsWithStats gets all buildings with their statistics
-func (s *BuildingService) GetAllBuildingsWithStats() ([]BuildingWithStats, error) {
-	// Get all buildings
-	buildings, err := s.repository.FindAll()
+// ListBuildingsWithStats returns one page of buildings with their statistics
+func (s *BuildingService) ListBuildingsWithStats(q models.ListQuery) (models.ListResult[BuildingWithStats], error) {
+	page, err := s.repository.List(q)
 	if err != nil {
-		return nil, err
+		return models.ListResult[BuildingWithStats]{}, err
 	}
 
 	// Build response with stats
-	result := make([]BuildingWithStats, len(buildings))
-	for i, building := range buildings {
+	result := models.ListResult[BuildingWithStats]{Items: make([]BuildingWithStats, len(page.Items)), Page: page.Page}
+	for i, building := range page.Items {
 		// Count rooms for each building
 		roomCount, err := s.repository.CountRooms(building.ID)
 		if err != nil {
-			return nil, err
+			return models.ListResult[BuildingWithStats]{}, err
 		}
 
-		result[i] = BuildingWithStats{
+		result.Items[i] = BuildingWithStats{
 			Building:  building,
 			RoomCount: roomCount,
 		}
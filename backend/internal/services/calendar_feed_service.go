@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+	"github.com/delpresence/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// calendarFeedRefresh is how often calendar apps are asked to fetch a feed again
+const calendarFeedRefresh = time.Hour
+
+var (
+	// ErrCalendarFeedNotFound is returned for feed tokens that do not exist or were revoked
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+	// ErrLecturerNotFound is returned when the signed-in user has no lecturer record
+	ErrLecturerNotFound = errors.New("lecturer record not found")
+)
+
+// CalendarFeedService issues secret iCalendar feed URLs and renders the timetable behind them
+// Feeds are rendered from the current schedules on every fetch, so changes reach calendar apps on
+// their next refresh.
+type CalendarFeedService struct {
+	feeds         repositories.CalendarFeedStore
+	schedules     repositories.CourseScheduleStore
+	students      repositories.StudentStore
+	studentGroups repositories.StudentGroupStore
+	lecturers     repositories.LecturerStore
+	rooms         repositories.RoomStore
+	holidays      repositories.HolidayStore
+	exceptions    repositories.ScheduleExceptionStore
+}
+
+// NewCalendarFeedService creates a new calendar feed service
+func NewCalendarFeedService() *CalendarFeedService {
+	return NewCalendarFeedServiceWithStores(
+		repositories.NewCalendarFeedRepository(),
+		repositories.NewCourseScheduleRepository(),
+		repositories.NewStudentRepository(),
+		repositories.NewStudentGroupRepository(),
+		repositories.NewLecturerRepository(),
+		repositories.NewRoomRepository(),
+		repositories.NewHolidayRepository(),
+		repositories.NewScheduleExceptionRepository(),
+	)
+}
+
+// NewCalendarFeedServiceWithStores creates a new calendar feed service on top of the given stores
+func NewCalendarFeedServiceWithStores(
+	feeds repositories.CalendarFeedStore,
+	schedules repositories.CourseScheduleStore,
+	students repositories.StudentStore,
+	studentGroups repositories.StudentGroupStore,
+	lecturers repositories.LecturerStore,
+	rooms repositories.RoomStore,
+	holidays repositories.HolidayStore,
+	exceptions repositories.ScheduleExceptionStore,
+) *CalendarFeedService {
+	return &CalendarFeedService{
+		feeds:         feeds,
+		schedules:     schedules,
+		students:      students,
+		studentGroups: studentGroups,
+		lecturers:     lecturers,
+		rooms:         rooms,
+		holidays:      holidays,
+		exceptions:    exceptions,
+	}
+}
+
+// CreateStudentFeed issues a feed of the classes of the signed-in student, revoking the previous one
+func (s *CalendarFeedService) CreateStudentFeed(userID uint, baseURL string) (*models.CalendarFeedCreatedResponse, error) {
+	student, err := s.students.FindByUserID(int(userID))
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, ErrStudentNotFound
+	}
+	return s.replace(models.CalendarFeedStudent, student.ID, "", userID, baseURL)
+}
+
+// RevokeStudentFeed revokes the feed of the signed-in student
+func (s *CalendarFeedService) RevokeStudentFeed(userID uint) error {
+	student, err := s.students.FindByUserID(int(userID))
+	if err != nil {
+		return err
+	}
+	if student == nil {
+		return ErrStudentNotFound
+	}
+	return s.feeds.RevokeSubject(models.CalendarFeedStudent, student.ID, time.Now())
+}
+
+// CreateLecturerFeed issues a feed of the classes of the signed-in lecturer, revoking the previous one
+func (s *CalendarFeedService) CreateLecturerFeed(userID uint, baseURL string) (*models.CalendarFeedCreatedResponse, error) {
+	lecturerID, err := s.lecturerID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.replace(models.CalendarFeedLecturer, lecturerID, "", userID, baseURL)
+}
+
+// RevokeLecturerFeed revokes the feed of the signed-in lecturer
+func (s *CalendarFeedService) RevokeLecturerFeed(userID uint) error {
+	lecturerID, err := s.lecturerID(userID)
+	if err != nil {
+		return err
+	}
+	return s.feeds.RevokeSubject(models.CalendarFeedLecturer, lecturerID, time.Now())
+}
+
+// CreateRoomFeed issues a feed of the classes in a room, such as for a building manager
+// A room can have several feeds, each revoked on its own.
+func (s *CalendarFeedService) CreateRoomFeed(roomID uint, label string, createdByID uint, baseURL string) (*models.CalendarFeedCreatedResponse, error) {
+	room, err := s.rooms.FindByID(roomID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRoomNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(models.CalendarFeedRoom, room.ID, strings.TrimSpace(label), createdByID, baseURL)
+}
+
+// ListRoomFeeds returns the feeds of a room that are not revoked
+func (s *CalendarFeedService) ListRoomFeeds(roomID uint) ([]models.CalendarFeed, error) {
+	return s.feeds.FindActive(models.CalendarFeedRoom, roomID)
+}
+
+// RevokeFeed revokes any feed
+func (s *CalendarFeedService) RevokeFeed(id uint) error {
+	feed, err := s.feeds.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if feed == nil || feed.RevokedAt != nil {
+		return ErrCalendarFeedNotFound
+	}
+	return s.feeds.Revoke(id, time.Now())
+}
+
+// lecturerID returns the schedule lecturer_id of the signed-in lecturer
+func (s *CalendarFeedService) lecturerID(userID uint) (uint, error) {
+	lecturer, err := s.lecturers.GetByUserID(int(userID))
+	if err != nil {
+		return 0, err
+	}
+	if lecturer.ID == 0 {
+		return 0, ErrLecturerNotFound
+	}
+	return uint(lecturer.UserID), nil
+}
+
+// replace revokes the feeds of a student or lecturer and issues a new one
+func (s *CalendarFeedService) replace(kind models.CalendarFeedKind, subjectID uint, label string, createdByID uint, baseURL string) (*models.CalendarFeedCreatedResponse, error) {
+	if err := s.feeds.RevokeSubject(kind, subjectID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.issue(kind, subjectID, label, createdByID, baseURL)
+}
+
+// issue generates the token of a feed, stores its hash and returns the feed URL once
+func (s *CalendarFeedService) issue(kind models.CalendarFeedKind, subjectID uint, label string, createdByID uint, baseURL string) (*models.CalendarFeedCreatedResponse, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+
+	feed := &models.CalendarFeed{
+		Kind:        kind,
+		SubjectID:   subjectID,
+		Label:       label,
+		TokenHash:   hashCalendarToken(token),
+		CreatedByID: createdByID,
+	}
+	if err := s.feeds.Create(feed); err != nil {
+		return nil, err
+	}
+	return &models.CalendarFeedCreatedResponse{
+		URL:  strings.TrimSuffix(baseURL, "/") + "/api/calendar/" + token + ".ics",
+		Feed: *feed,
+	}, nil
+}
+
+// hashCalendarToken returns the stored digest of a feed token
+// Tokens carry 256 bits of randomness, so a fast hash is enough
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSuffix(strings.TrimSpace(token), ".ics")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Calendar renders the timetable behind a feed token
+// Each schedule is repeated weekly from the start to the end date of its academic year. Classes on a
+// holiday or cancelled by an exception stay in the feed as cancelled events, so calendar apps that
+// already have them mark them cancelled, and moved classes keep their event at the new date and time.
+func (s *CalendarFeedService) Calendar(ctx context.Context, token string) (*utils.ICalendar, error) {
+	feed, err := s.feeds.FindActiveByTokenHash(hashCalendarToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	schedules, name, err := s.feedSchedules(ctx, feed)
+	if err != nil {
+		return nil, err
+	}
+	scheduleIDs := make([]uint, len(schedules))
+	for i, schedule := range schedules {
+		scheduleIDs[i] = schedule.ID
+	}
+	exceptions, err := s.exceptions.FindBySchedules(scheduleIDs)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.holidays.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := s.replacementRooms(ctx, exceptions)
+	if err != nil {
+		return nil, err
+	}
+
+	feedRoomID := uint(0)
+	if feed.Kind == models.CalendarFeedRoom {
+		feedRoomID = feed.SubjectID
+	}
+	calendar := &utils.ICalendar{
+		Name:            name,
+		RefreshInterval: calendarFeedRefresh,
+		Events:          calendarEvents(schedules, exceptions, holidays, rooms, feed.Kind != models.CalendarFeedStudent, feedRoomID),
+	}
+
+	if err := s.feeds.TouchLastAccessed(feed.ID, time.Now()); err != nil {
+		slog.ErrorContext(ctx, "Error recording access to calendar feed", "feed_id", feed.ID, "error", err)
+	}
+	return calendar, nil
+}
+
+// feedSchedules returns the schedules behind a feed and the calendar name
+func (s *CalendarFeedService) feedSchedules(ctx context.Context, feed *models.CalendarFeed) ([]models.CourseSchedule, string, error) {
+	store := s.schedules.WithContext(ctx)
+	switch feed.Kind {
+	case models.CalendarFeedStudent:
+		groupIDs, err := s.studentGroups.GetGroupIDsByStudentID(feed.SubjectID)
+		if err != nil {
+			return nil, "", err
+		}
+		var schedules []models.CourseSchedule
+		for _, groupID := range groupIDs {
+			groupSchedules, err := store.GetByStudentGroup(groupID)
+			if err != nil {
+				return nil, "", err
+			}
+			schedules = append(schedules, groupSchedules...)
+		}
+		return schedules, "Jadwal Kuliah", nil
+
+	case models.CalendarFeedLecturer:
+		schedules, err := store.GetByLecturer(feed.SubjectID)
+		return schedules, "Jadwal Mengajar", err
+
+	case models.CalendarFeedRoom:
+		room, err := s.rooms.FindByID(feed.SubjectID)
+		if err != nil {
+			return nil, "", err
+		}
+		schedules, err := store.GetByRoom(room.ID)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// Classes of other rooms with a replacement session in this room
+		seen := make(map[uint]bool, len(schedules))
+		for _, schedule := range schedules {
+			seen[schedule.ID] = true
+		}
+		movedIn, err := s.exceptions.FindByNewRoom(room.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, exception := range movedIn {
+			if seen[exception.CourseScheduleID] {
+				continue
+			}
+			seen[exception.CourseScheduleID] = true
+			schedule, err := store.GetByID(exception.CourseScheduleID)
+			if err != nil {
+				return nil, "", err
+			}
+			schedules = append(schedules, schedule)
+		}
+		return schedules, fmt.Sprintf("Jadwal Ruangan %s", room.Name), nil
+	}
+	return nil, "", ErrCalendarFeedNotFound
+}
+
+// replacementRooms loads the rooms of replacement sessions, with their building
+// A room deleted since the exception was made leaves its sessions without a location, it does
+// not take down the whole feed.
+func (s *CalendarFeedService) replacementRooms(ctx context.Context, exceptions []models.ScheduleException) (map[uint]models.Room, error) {
+	rooms := make(map[uint]models.Room)
+	for _, exception := range exceptions {
+		if exception.NewRoomID == nil {
+			continue
+		}
+		if _, ok := rooms[*exception.NewRoomID]; ok {
+			continue
+		}
+		room, err := s.rooms.FindByID(*exception.NewRoomID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			slog.WarnContext(ctx, "Replacement room of schedule exception not found", "exception_id", exception.ID, "room_id", *exception.NewRoomID)
+			rooms[*exception.NewRoomID] = models.Room{}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rooms[room.ID] = *room
+	}
+	return rooms, nil
+}
+
+// calendarEvents expands schedules into one event per class, applying holidays and exceptions
+// Events outside roomID are left out when roomID is set. withGroup adds the student group to the title,
+// for lecturers and rooms that see the classes of several groups.
+func calendarEvents(schedules []models.CourseSchedule, exceptions []models.ScheduleException, holidays []models.Holiday, rooms map[uint]models.Room, withGroup bool, roomID uint) []utils.ICalEvent {
+	location := getIndonesiaLocation()
+	type occurrence struct {
+		scheduleID uint
+		date       string
+	}
+	byOccurrence := make(map[occurrence]models.ScheduleException, len(exceptions))
+	for _, exception := range exceptions {
+		byOccurrence[occurrence{exception.CourseScheduleID, exception.Date}] = exception
+	}
+
+	events := []utils.ICalEvent{}
+	seen := make(map[uint]bool, len(schedules))
+	for _, schedule := range schedules {
+		if seen[schedule.ID] {
+			continue
+		}
+		seen[schedule.ID] = true
+
+		weekday, ok := scheduleWeekday(schedule.Day)
+		start, startOK := clockMinutes(schedule.StartTime)
+		end, endOK := clockMinutes(schedule.EndTime)
+		if !ok || !startOK || !endOK || end <= start {
+			continue
+		}
+		first, last := academicYearDates(schedule.AcademicYear)
+		date, err := time.ParseInLocation(dateLayout, first, location)
+		if err != nil {
+			continue
+		}
+		for date.Weekday() != weekday {
+			date = date.AddDate(0, 0, 1)
+		}
+
+		summary := strings.TrimSpace(schedule.Course.Code + " " + schedule.Course.Name)
+		if withGroup && schedule.StudentGroup.Name != "" {
+			summary += " (" + schedule.StudentGroup.Name + ")"
+		}
+
+		for ; date.Format(dateLayout) <= last; date = date.AddDate(0, 0, 7) {
+			key := date.Format(dateLayout)
+			event := utils.ICalEvent{
+				UID:          fmt.Sprintf("schedule-%d-%s@delpresence", schedule.ID, date.Format("20060102")),
+				Start:        date.Add(time.Duration(start) * time.Minute),
+				End:          date.Add(time.Duration(end) * time.Minute),
+				Summary:      summary,
+				Location:     roomLocation(schedule.Room),
+				LastModified: schedule.UpdatedAt,
+			}
+			description := []string{"Kelas: " + schedule.StudentGroup.Name}
+			eventRoomID := schedule.RoomID
+
+			if exception, ok := byOccurrence[occurrence{schedule.ID, key}]; ok {
+				event.Sequence = 1
+				if exception.UpdatedAt.After(event.LastModified) {
+					event.LastModified = exception.UpdatedAt
+				}
+				if exception.Kind == models.ScheduleExceptionRescheduled {
+					// A replacement session that cannot be read keeps the class at its regular date and time
+					newDate, err := time.ParseInLocation(dateLayout, exception.NewDate, location)
+					newStart, startOK := clockMinutes(exception.NewStartTime)
+					newEnd, endOK := clockMinutes(exception.NewEndTime)
+					if err == nil && startOK && endOK && newEnd > newStart {
+						event.Start = newDate.Add(time.Duration(newStart) * time.Minute)
+						event.End = newDate.Add(time.Duration(newEnd) * time.Minute)
+						if exception.NewRoomID != nil {
+							eventRoomID = *exception.NewRoomID
+							event.Location = roomLocation(rooms[eventRoomID])
+						}
+						description = append(description, "Kuliah pengganti untuk "+key)
+					}
+				} else {
+					event.Cancelled = true
+					description = append(description, "Dibatalkan")
+				}
+				if exception.Note != "" {
+					description = append(description, exception.Note)
+				}
+			} else if holiday := holidayOn(holidays, key); holiday != nil {
+				event.Sequence = 1
+				event.Cancelled = true
+				if holiday.UpdatedAt.After(event.LastModified) {
+					event.LastModified = holiday.UpdatedAt
+				}
+				description = append(description, "Libur: "+holiday.Name)
+			}
+
+			if roomID != 0 && eventRoomID != roomID {
+				continue
+			}
+			event.Description = strings.Join(description, "\n")
+			events = append(events, event)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events
+}
+
+// roomLocation returns the room and building of a class as the event location
+func roomLocation(room models.Room) string {
+	if room.Building.Name == "" {
+		return room.Name
+	}
+	return room.Name + ", " + room.Building.Name
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// testCalendarSchedule is a Monday class from 08:00 to 10:00 in a three week academic year
+func testCalendarSchedule() models.CourseSchedule {
+	location := getIndonesiaLocation()
+	return models.CourseSchedule{
+		ID:        1,
+		Course:    models.Course{Code: "IF101", Name: "Pemrograman"},
+		RoomID:    1,
+		Room:      models.Room{ID: 1, Name: "GD 511"},
+		Day:       "Senin",
+		StartTime: "08:00",
+		EndTime:   "10:00",
+		AcademicYear: models.AcademicYear{
+			StartDate: time.Date(2026, 8, 3, 0, 0, 0, 0, location),
+			EndDate:   time.Date(2026, 8, 17, 0, 0, 0, 0, location),
+		},
+	}
+}
+
+func TestCalendarEventsKeepsClassWithUnreadableReplacement(t *testing.T) {
+	exceptions := []models.ScheduleException{{
+		CourseScheduleID: 1,
+		Date:             "2026-08-10",
+		Kind:             models.ScheduleExceptionRescheduled,
+		NewDate:          "not-a-date",
+		NewStartTime:     "13:00",
+		NewEndTime:       "15:00",
+	}}
+
+	events := calendarEvents([]models.CourseSchedule{testCalendarSchedule()}, exceptions, nil, nil, false, 0)
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	moved := events[1]
+	want := time.Date(2026, 8, 10, 8, 0, 0, 0, getIndonesiaLocation())
+	if !moved.Start.Equal(want) || moved.Cancelled {
+		t.Errorf("got event at %s (cancelled %t), want the regular class at %s", moved.Start, moved.Cancelled, want)
+	}
+}
+
+// missingRoomStore is a RoomStore whose rooms were all deleted
+type missingRoomStore struct {
+	repositories.RoomStore
+}
+
+// FindByID returns the error of the GORM repository for a missing room
+func (missingRoomStore) FindByID(id uint) (*models.Room, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestReplacementRoomsSkipsDeletedRoom(t *testing.T) {
+	service := &CalendarFeedService{rooms: missingRoomStore{}}
+	roomID := uint(7)
+	exceptions := []models.ScheduleException{{
+		CourseScheduleID: 1,
+		Date:             "2026-08-10",
+		Kind:             models.ScheduleExceptionRescheduled,
+		NewDate:          "2026-08-11",
+		NewStartTime:     "13:00",
+		NewEndTime:       "15:00",
+		NewRoomID:        &roomID,
+	}}
+
+	rooms, err := service.replacementRooms(context.Background(), exceptions)
+	if err != nil {
+		t.Fatalf("replacement rooms: %v", err)
+	}
+
+	events := calendarEvents([]models.CourseSchedule{testCalendarSchedule()}, exceptions, nil, rooms, false, 0)
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	want := time.Date(2026, 8, 11, 13, 0, 0, 0, getIndonesiaLocation())
+	if !events[1].Start.Equal(want) || events[1].Location != "" {
+		t.Errorf("got event at %s in %q, want the replacement session at %s without a room", events[1].Start, events[1].Location, want)
+	}
+}
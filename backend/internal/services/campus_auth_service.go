@@ -3,21 +3,22 @@ package services
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
-	"os"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/delpresence/backend/internal/metrics"
+	"github.com/delpresence/backend/internal/config"
 	"github.com/delpresence/backend/internal/models"
 )
 
 const (
-	CampusAuthURL       = "https://cis-dev.del.ac.id/api/jwt-api/do-auth"
 	tokenExpirationTime = 45 * time.Minute // Tokens typically expire after 50 minutes, let's refresh a bit earlier
 )
 
@@ -25,6 +26,8 @@ const (
 type CampusAuthService struct {
 	username    string
 	password    string
+	authURL     string
+	fallbackURL string
 	token       string
 	tokenExpiry time.Time
 	mutex       sync.Mutex
@@ -48,15 +51,41 @@ func NewCampusAuthTransport(base http.RoundTripper, authService *CampusAuthServi
 	}
 }
 
-// RoundTrip implements the http.RoundTripper interface
+// errCampusToken marks requests that failed because no campus token could be obtained
+var errCampusToken = errors.New("failed to get authentication token")
+
+// RoundTrip implements the http.RoundTripper interface and records the latency and errors of the request
 func (t *CampusAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.roundTrip(req)
+	metrics.ObserveCampusRequest(req.URL.Path, time.Since(start), campusErrorReason(resp, err))
+	return resp, err
+}
+
+// campusErrorReason classifies a failed campus request for the error counter, or returns "" on success
+func campusErrorReason(resp *http.Response, err error) string {
+	switch {
+	case errors.Is(err, errCampusToken), err != nil && resp != nil && resp.StatusCode == http.StatusUnauthorized:
+		return "auth"
+	case err != nil:
+		return "transport"
+	case resp.StatusCode >= 500:
+		return "status_5xx"
+	case resp.StatusCode >= 400:
+		return "status_4xx"
+	}
+	return ""
+}
+
+// roundTrip sends the request with a valid token, refreshing it once when it has expired
+func (t *CampusAuthTransport) roundTrip(req *http.Request) (*http.Response, error) {
 	// Clone the request to avoid modifying the original
 	reqClone := req.Clone(req.Context())
 	
 	// Get a valid token
 	token, err := t.authService.GetToken()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get authentication token: %w", err)
+		return nil, fmt.Errorf("%w: %w", errCampusToken, err)
 	}
 	
 	// Add the token to the request
@@ -74,7 +103,7 @@ func (t *CampusAuthTransport) RoundTrip(req *http.Request) (*http.Response, erro
 	// Check if the response indicates an expired token (401 Unauthorized)
 	if resp.StatusCode == http.StatusUnauthorized {
 		// Try to refresh the token
-		log.Println("Token expired, attempting to refresh...")
+		slog.Info("Campus token expired, refreshing")
 		token, err = t.authService.RefreshToken()
 		if err != nil {
 			return resp, err // Return the original 401 response and the error
@@ -107,21 +136,14 @@ func (s *CampusAuthService) GetClient() *http.Client {
 
 // NewCampusAuthService creates a new CampusAuthService
 func NewCampusAuthService() *CampusAuthService {
-	// Get credentials from environment variables with fallbacks
-	username := os.Getenv("CAMPUS_API_USERNAME")
-	password := os.Getenv("CAMPUS_API_PASSWORD")
-
-	// If not in env, use defaults (for development only, should be set in production)
-	if username == "" {
-		username = "johannes"
-	}
-	if password == "" {
-		password = "Del@2022"
-	}
+	// Credentials are required in production, see config.Validate
+	cfg := config.Get().Campus
 
 	return &CampusAuthService{
-		username: username,
-		password: password,
+		username:    cfg.APIUsername,
+		password:    cfg.APIPassword,
+		authURL:     cfg.ServiceAuthURL,
+		fallbackURL: cfg.AuthFallbackURL,
 	}
 }
 
@@ -141,18 +163,18 @@ func (s *CampusAuthService) GetToken() (string, error) {
 
 // authenticate logs in to the campus API and gets a new token
 func (s *CampusAuthService) authenticate() (string, error) {
-	log.Printf("Attempting to authenticate with campus API using username: %s", s.username)
+	slog.Info("Authenticating with campus API", "username", s.username)
 	
 	// Try different authentication methods in sequence
 	token, err := s.authenticateWithMultipartForm()
 	if err != nil {
-		log.Printf("Multipart form authentication failed: %v. Trying JSON auth...", err)
+		slog.Warn("Multipart form authentication failed, trying JSON", "error", err)
 		token, err = s.authenticateWithJSON()
 		if err != nil {
-			log.Printf("JSON authentication failed: %v. Trying URL encoded form...", err)
+			slog.Warn("JSON authentication failed, trying URL encoded form", "error", err)
 			token, err = s.authenticateWithURLEncodedForm()
 			if err != nil {
-				log.Printf("All authentication methods failed")
+				slog.Error("All campus authentication methods failed")
 				return "", fmt.Errorf("all authentication methods failed, last error: %w", err)
 			}
 		}
@@ -194,13 +216,13 @@ func (s *CampusAuthService) authenticateWithMultipartForm() (string, error) {
 	}
 	
 	// Try primary URL
-	token, err := s.sendAuthRequest(CampusAuthURL, &requestBody, multipartWriter.FormDataContentType())
+	token, err := s.sendAuthRequest(s.authURL, &requestBody, multipartWriter.FormDataContentType())
 	if err != nil && (strings.Contains(err.Error(), "no route to host") || 
 		strings.Contains(err.Error(), "connection refused") ||
 		strings.Contains(err.Error(), "i/o timeout")) {
 		// Try alternative URL
-		alternativeURL := "http://cis.del.ac.id/api/jwt-api/do-auth"
-		log.Printf("Primary URL failed, trying alternative URL (multipart form): %s", alternativeURL)
+		alternativeURL := s.fallbackURL
+		slog.Warn("Primary URL failed, trying alternative URL", "url", alternativeURL, "format", "multipart")
 		
 		// Reset request body and recreate form
 		requestBody.Reset()
@@ -252,13 +274,13 @@ func (s *CampusAuthService) authenticateWithJSON() (string, error) {
 	}
 	
 	// Try primary URL
-	token, err := s.sendAuthRequest(CampusAuthURL, bytes.NewBuffer(payloadBytes), "application/json")
+	token, err := s.sendAuthRequest(s.authURL, bytes.NewBuffer(payloadBytes), "application/json")
 	if err != nil && (strings.Contains(err.Error(), "no route to host") || 
 		strings.Contains(err.Error(), "connection refused") ||
 		strings.Contains(err.Error(), "i/o timeout")) {
 		// Try alternative URL
-		alternativeURL := "http://cis.del.ac.id/api/jwt-api/do-auth"
-		log.Printf("Primary URL failed, trying alternative URL (JSON): %s", alternativeURL)
+		alternativeURL := s.fallbackURL
+		slog.Warn("Primary URL failed, trying alternative URL", "url", alternativeURL, "format", "json")
 		return s.sendAuthRequest(alternativeURL, bytes.NewBuffer(payloadBytes), "application/json")
 	}
 	
@@ -271,13 +293,13 @@ func (s *CampusAuthService) authenticateWithURLEncodedForm() (string, error) {
 	formData := fmt.Sprintf("username=%s&password=%s", s.username, s.password)
 	
 	// Try primary URL
-	token, err := s.sendAuthRequest(CampusAuthURL, bytes.NewBufferString(formData), "application/x-www-form-urlencoded")
+	token, err := s.sendAuthRequest(s.authURL, bytes.NewBufferString(formData), "application/x-www-form-urlencoded")
 	if err != nil && (strings.Contains(err.Error(), "no route to host") || 
 		strings.Contains(err.Error(), "connection refused") ||
 		strings.Contains(err.Error(), "i/o timeout")) {
 		// Try alternative URL
-		alternativeURL := "http://cis.del.ac.id/api/jwt-api/do-auth"
-		log.Printf("Primary URL failed, trying alternative URL (URL encoded form): %s", alternativeURL)
+		alternativeURL := s.fallbackURL
+		slog.Warn("Primary URL failed, trying alternative URL", "url", alternativeURL, "format", "form")
 		return s.sendAuthRequest(alternativeURL, bytes.NewBufferString(formData), "application/x-www-form-urlencoded")
 	}
 	
@@ -287,7 +309,7 @@ func (s *CampusAuthService) authenticateWithURLEncodedForm() (string, error) {
 // sendAuthRequest sends an authentication request with the given content
 func (s *CampusAuthService) sendAuthRequest(url string, body io.Reader, contentType string) (string, error) {
 	// Create request
-	log.Printf("Making request to URL: %s with Content-Type: %s", url, contentType)
+	slog.Debug("Sending campus authentication request", "url", url, "content_type", contentType)
 	req, err := http.NewRequest("POST", url, body)
 	if err != nil {
 		return "", fmt.Errorf("error creating request: %w", err)
@@ -300,7 +322,7 @@ func (s *CampusAuthService) sendAuthRequest(url string, body io.Reader, contentT
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Printf("Error connecting to URL %s: %v", url, err)
+		slog.Warn("Error connecting to campus API", "url", url, "error", err)
 		return "", fmt.Errorf("error sending request to %s: %w", url, err)
 	}
 	defer resp.Body.Close()
@@ -308,7 +330,7 @@ func (s *CampusAuthService) sendAuthRequest(url string, body io.Reader, contentT
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		log.Printf("Authentication failed with status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
+		slog.Warn("Campus authentication failed", "status", resp.StatusCode, "response", string(bodyBytes))
 		return "", fmt.Errorf("authentication failed with status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
 	}
 	
@@ -316,25 +338,23 @@ func (s *CampusAuthService) sendAuthRequest(url string, body io.Reader, contentT
 	var authResp models.CampusLoginResponse
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("Error reading response body: %v", err)
+		slog.Warn("Error reading campus authentication response", "error", err)
 		return "", fmt.Errorf("error reading response body: %w", err)
 	}
 	
-	log.Printf("Received response from campus API: %s", string(bodyBytes))
-	
 	err = json.Unmarshal(bodyBytes, &authResp)
 	if err != nil {
-		log.Printf("Error parsing response: %v, raw response: %s", err, string(bodyBytes))
+		slog.Warn("Error parsing campus authentication response", "response", string(bodyBytes), "error", err)
 		return "", fmt.Errorf("error parsing response (JSON Unmarshal error): %w, raw response: %s", err, string(bodyBytes))
 	}
 	
 	// Check if result is successful
 	if !authResp.Result {
-		log.Printf("Authentication failed: %s", authResp.Error)
+		slog.Warn("Campus authentication failed", "error", authResp.Error)
 		return "", fmt.Errorf("authentication failed: %s", authResp.Error)
 	}
 	
-	log.Printf("Authentication successful, token received")
+	slog.Info("Authenticated with campus API")
 	
 	// Save token and set expiry
 	s.token = authResp.Token
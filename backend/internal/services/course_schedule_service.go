@@ -1,8 +1,9 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"fmt"
+	"log/slog"
 	"sort"
 
 	"github.com/delpresence/backend/internal/models"
@@ -11,29 +12,79 @@ import (
 
 // CourseScheduleService provides business logic for course schedules
 type CourseScheduleService struct {
-	repo             *repositories.CourseScheduleRepository
-	courseRepo       *repositories.CourseRepository
-	roomRepo         *repositories.RoomRepository
-	studentGroupRepo *repositories.StudentGroupRepository
-	lecturerRepo     *repositories.UserRepository
-	academicYearRepo *repositories.AcademicYearRepository
+	repo                   repositories.CourseScheduleStore
+	courseRepo             repositories.CourseStore
+	roomRepo               repositories.RoomStore
+	studentGroupRepo       repositories.StudentGroupStore
+	lecturerRepo           repositories.UserStore
+	academicYearRepo       repositories.AcademicYearStore
+	lecturerProfileRepo    repositories.LecturerStore
+	lecturerAssignmentRepo repositories.LecturerAssignmentStore
+	studentRepo            repositories.StudentStore
 }
 
 // NewCourseScheduleService creates a new instance of CourseScheduleService
 func NewCourseScheduleService() *CourseScheduleService {
+	return NewCourseScheduleServiceWithStores(CourseScheduleStores{
+		Schedules:           repositories.NewCourseScheduleRepository(),
+		Courses:             repositories.NewCourseRepository(),
+		Rooms:               repositories.NewRoomRepository(),
+		StudentGroups:       repositories.NewStudentGroupRepository(),
+		Users:               repositories.NewUserRepository(),
+		AcademicYears:       repositories.NewAcademicYearRepository(),
+		Lecturers:           repositories.NewLecturerRepository(),
+		LecturerAssignments: repositories.NewLecturerAssignmentRepository(),
+		Students:            repositories.NewStudentRepository(),
+	})
+}
+
+// CourseScheduleStores are the stores a CourseScheduleService works on
+type CourseScheduleStores struct {
+	Schedules           repositories.CourseScheduleStore
+	Courses             repositories.CourseStore
+	Rooms               repositories.RoomStore
+	StudentGroups       repositories.StudentGroupStore
+	Users               repositories.UserStore
+	AcademicYears       repositories.AcademicYearStore
+	Lecturers           repositories.LecturerStore
+	LecturerAssignments repositories.LecturerAssignmentStore
+	Students            repositories.StudentStore
+}
+
+// NewCourseScheduleServiceWithStores creates a new CourseScheduleService on top of the given stores
+func NewCourseScheduleServiceWithStores(stores CourseScheduleStores) *CourseScheduleService {
 	return &CourseScheduleService{
-		repo:             repositories.NewCourseScheduleRepository(),
-		courseRepo:       repositories.NewCourseRepository(),
-		roomRepo:         repositories.NewRoomRepository(),
-		studentGroupRepo: repositories.NewStudentGroupRepository(),
-		lecturerRepo:     repositories.NewUserRepository(),
-		academicYearRepo: repositories.NewAcademicYearRepository(),
+		repo:                   stores.Schedules,
+		courseRepo:             stores.Courses,
+		roomRepo:               stores.Rooms,
+		studentGroupRepo:       stores.StudentGroups,
+		lecturerRepo:           stores.Users,
+		academicYearRepo:       stores.AcademicYears,
+		lecturerProfileRepo:    stores.Lecturers,
+		lecturerAssignmentRepo: stores.LecturerAssignments,
+		studentRepo:            stores.Students,
 	}
 }
 
-// GetAllSchedules retrieves all course schedules
-func (s *CourseScheduleService) GetAllSchedules() ([]models.CourseSchedule, error) {
-	return s.repo.GetAll()
+// withContext returns a copy of the service whose schedule queries carry ctx
+func (s *CourseScheduleService) withContext(ctx context.Context) *CourseScheduleService {
+	scoped := *s
+	scoped.repo = s.repo.WithContext(ctx)
+	return &scoped
+}
+
+// ListSchedules returns one page of course schedules, formatted like FormatSchedulesForResponse
+func (s *CourseScheduleService) ListSchedules(ctx context.Context, q models.ListQuery) (models.ListResult[models.CourseScheduleResponse], error) {
+	s = s.withContext(ctx)
+
+	page, err := s.repo.List(q)
+	if err != nil {
+		return models.ListResult[models.CourseScheduleResponse]{}, err
+	}
+	return models.ListResult[models.CourseScheduleResponse]{
+		Items: s.FormatSchedulesForResponse(page.Items),
+		Page:  page.Page,
+	}, nil
 }
 
 // GetScheduleByID retrieves a course schedule by ID
@@ -42,7 +93,9 @@ func (s *CourseScheduleService) GetScheduleByID(id uint) (models.CourseSchedule,
 }
 
 // CreateSchedule creates a new course schedule
-func (s *CourseScheduleService) CreateSchedule(schedule models.CourseSchedule) (models.CourseSchedule, error) {
+func (s *CourseScheduleService) CreateSchedule(ctx context.Context, schedule models.CourseSchedule) (models.CourseSchedule, error) {
+	s = s.withContext(ctx)
+
 	// Validate course
 	_, err := s.courseRepo.GetByID(schedule.CourseID)
 	if err != nil {
@@ -59,11 +112,11 @@ func (s *CourseScheduleService) CreateSchedule(schedule models.CourseSchedule) (
 	if schedule.Capacity <= 0 {
 		// Use room capacity as the default
 		schedule.Capacity = room.Capacity
-		fmt.Printf("Setting schedule capacity to room capacity: %d\n", room.Capacity)
+		slog.DebugContext(ctx, "Setting schedule capacity to room capacity", "capacity", room.Capacity)
 	}
 
 	// Always prioritize finding the lecturer from course assignments first
-	lecturerAssignmentRepo := repositories.NewLecturerAssignmentRepository()
+	lecturerAssignmentRepo := s.lecturerAssignmentRepo
 
 	// Get academic years and use the provided one or any available one
 	academicYearID := schedule.AcademicYearID
@@ -87,57 +140,40 @@ func (s *CourseScheduleService) CreateSchedule(schedule models.CourseSchedule) (
 
 	// If there are existing schedules for this course, prioritize using the same lecturer ID
 	if err == nil && len(existingSchedules) > 0 {
-		fmt.Printf("Found %d existing schedules for course ID=%d\n", len(existingSchedules), schedule.CourseID)
+		slog.DebugContext(ctx, "Found existing schedules", "course_id", schedule.CourseID, "count", len(existingSchedules))
 
 		// Sort all schedules by ID descending to get the most recent first
 		sort.Slice(existingSchedules, func(i, j int) bool {
 			return existingSchedules[i].ID > existingSchedules[j].ID
 		})
 
-		// Log all existing schedules for debugging
-		for i, s := range existingSchedules {
-			fmt.Printf("  Existing schedule #%d: ID=%d, lecturer_id=%d\n", i+1, s.ID, s.UserID)
-		}
-
 		// Use the first schedule (highest ID = most recent) that has a non-zero lecturer ID
 		for _, s := range existingSchedules {
 			if s.UserID > 0 {
 				consistentLecturerID = s.UserID
-				fmt.Printf("Selected lecturer ID=%d from existing schedule ID=%d for consistency\n",
-					consistentLecturerID, s.ID)
+				slog.DebugContext(ctx, "Selected lecturer from existing schedule", "lecturer_id", consistentLecturerID, "schedule_id", s.ID)
 				break
 			}
 		}
 	} else {
 		// Try to find recently deleted schedules that might have been soft-deleted
-		fmt.Printf("No active schedules found, checking for deleted schedules for course ID=%d\n", schedule.CourseID)
+		slog.DebugContext(ctx, "No active schedules found, checking deleted schedules", "course_id", schedule.CourseID)
 
-		var deletedSchedules []models.CourseSchedule
-		err := s.repo.DB().Unscoped().
-			Where("course_id = ? AND deleted_at IS NOT NULL", schedule.CourseID).
-			Order("id DESC").
-			Limit(5).
-			Find(&deletedSchedules).Error
+		deletedSchedules, err := s.repo.GetDeletedByCourse(schedule.CourseID, 5)
 
 		if err == nil && len(deletedSchedules) > 0 {
-			fmt.Printf("Found %d deleted schedules for course ID=%d\n", len(deletedSchedules), schedule.CourseID)
-
-			// Log all deleted schedules for debugging
-			for i, s := range deletedSchedules {
-				fmt.Printf("  Deleted schedule #%d: ID=%d, lecturer_id=%d\n", i+1, s.ID, s.UserID)
-			}
+			slog.DebugContext(ctx, "Found deleted schedules", "course_id", schedule.CourseID, "count", len(deletedSchedules))
 
 			// Use the first deleted schedule with a non-zero lecturer ID
 			for _, s := range deletedSchedules {
 				if s.UserID > 0 {
 					consistentLecturerID = s.UserID
-					fmt.Printf("Selected lecturer ID=%d from deleted schedule ID=%d for consistency\n",
-						consistentLecturerID, s.ID)
+					slog.DebugContext(ctx, "Selected lecturer from deleted schedule", "lecturer_id", consistentLecturerID, "schedule_id", s.ID)
 					break
 				}
 			}
-		} else {
-			fmt.Printf("No deleted schedules found for course ID=%d, error: %v\n", schedule.CourseID, err)
+		} else if err != nil {
+			slog.ErrorContext(ctx, "Error finding deleted schedules", "course_id", schedule.CourseID, "error", err)
 		}
 	}
 
@@ -146,7 +182,7 @@ func (s *CourseScheduleService) CreateSchedule(schedule models.CourseSchedule) (
 		// Only override if no specific lecturer was requested
 		if schedule.UserID == 0 {
 			schedule.UserID = consistentLecturerID
-			fmt.Printf("Using consistent lecturer ID=%d from existing/deleted schedules\n", consistentLecturerID)
+			slog.DebugContext(ctx, "Using lecturer from existing or deleted schedules", "lecturer_id", consistentLecturerID)
 		}
 	} else {
 		// Check lecturer assignments as fallback
@@ -159,12 +195,12 @@ func (s *CourseScheduleService) CreateSchedule(schedule models.CourseSchedule) (
 			// Only override UserID if a specific one wasn't requested
 			if schedule.UserID == 0 {
 				schedule.UserID = lecturerID
-				fmt.Printf("Using lecturer ID=%d from course assignments\n", lecturerID)
+				slog.DebugContext(ctx, "Using lecturer from course assignments", "lecturer_id", lecturerID)
 			}
 		} else if schedule.CourseID == 1 && schedule.UserID == 0 {
 			// Special case for course ID 1 based on examples
 			// This ensures consistency with existing schedules mentioned in the bug report
-			fmt.Printf("Special case: For course ID 1, using lecturer ID 5106 for consistency\n")
+			slog.DebugContext(ctx, "Using default lecturer for course 1", "lecturer_id", 5106)
 			schedule.UserID = 5106
 		}
 	}
@@ -175,48 +211,42 @@ func (s *CourseScheduleService) CreateSchedule(schedule models.CourseSchedule) (
 		user, err := s.lecturerRepo.FindByID(schedule.UserID)
 		if err != nil || user == nil || user.Role != "Dosen" {
 			// Try to find lecturer in the lecturer table
-			lecturerRepo := repositories.NewLecturerRepository()
+			lecturerRepo := s.lecturerProfileRepo
 
-			fmt.Printf("Looking up lecturer with UserID=%d in lecturer table\n", schedule.UserID)
+			slog.DebugContext(ctx, "Looking up lecturer in lecturer table", "user_id", schedule.UserID)
 
 			// First, try as external UserID (most common case)
 			lecturer, err := lecturerRepo.GetByUserID(int(schedule.UserID))
 			if err == nil && lecturer.ID > 0 {
-				fmt.Printf("Found lecturer by UserID=%d: ID=%d, FullName=%s\n",
-					schedule.UserID, lecturer.ID, lecturer.FullName)
+				slog.DebugContext(ctx, "Found lecturer by user ID", "user_id", schedule.UserID, "lecturer_id", lecturer.ID)
 				// Keep using the provided UserID which matches lecturer.UserID
 			} else {
 				// Try by direct ID
 				lecturer, err = lecturerRepo.GetByID(schedule.UserID)
 				if err == nil && lecturer.ID > 0 {
-					fmt.Printf("Found lecturer by direct ID=%d: UserID=%d, FullName=%s\n",
-						schedule.UserID, lecturer.UserID, lecturer.FullName)
+					slog.DebugContext(ctx, "Found lecturer by ID", "lecturer_id", schedule.UserID, "user_id", lecturer.UserID)
 					// Found by direct ID, use the UserID if available
 					if lecturer.UserID > 0 {
 						schedule.UserID = uint(lecturer.UserID)
-						fmt.Printf("Resolved lecturer ID to external UserID=%d\n", schedule.UserID)
+						slog.DebugContext(ctx, "Resolved lecturer ID to external user ID", "user_id", schedule.UserID)
 					}
 				} else {
 					// Try one last approach - lookup directly in users table with Role=Dosen
-					fmt.Printf("Trying direct user lookup for ID=%d with Role=Dosen\n", schedule.UserID)
-					var user models.User
-					if err := s.repo.DB().Where("id = ? AND role = ?", schedule.UserID, "Dosen").First(&user).Error; err == nil {
-						fmt.Printf("Found user with ID=%d and Role=Dosen: %s\n", user.ID, user.Username)
+					if user, err := s.lecturerRepo.FindByID(schedule.UserID); err == nil && user != nil && user.Role == "Dosen" {
+						slog.DebugContext(ctx, "Found lecturer in users table", "user_id", user.ID)
 						// Keep using the UserID since it's valid
 					} else {
 						return models.CourseSchedule{}, errors.New("invalid lecturer ID: not found in any tables")
 					}
 				}
 			}
-		} else {
-			fmt.Printf("Found lecturer in users table: ID=%d, Username=%s\n", user.ID, user.Username)
 		}
 	} else {
 		// No lecturer ID provided and none found from assignments or existing schedules
 		return models.CourseSchedule{}, errors.New("no lecturer ID provided or found from assignments")
 	}
 
-	fmt.Printf("Final lecturer ID for new schedule: %d\n", schedule.UserID)
+	slog.DebugContext(ctx, "Resolved lecturer for new schedule", "user_id", schedule.UserID)
 
 	// Validate student group
 	_, err = s.studentGroupRepo.GetByID(schedule.StudentGroupID)
@@ -244,14 +274,16 @@ func (s *CourseScheduleService) CreateSchedule(schedule models.CourseSchedule) (
 		return models.CourseSchedule{}, err
 	}
 
-	fmt.Printf("Created new schedule with ID=%d, lecturer_id=%d, student_group_id=%d\n",
-		createdSchedule.ID, createdSchedule.UserID, createdSchedule.StudentGroupID)
+	slog.InfoContext(ctx, "Created course schedule",
+		"schedule_id", createdSchedule.ID, "lecturer_id", createdSchedule.UserID, "student_group_id", createdSchedule.StudentGroupID)
 
 	return createdSchedule, nil
 }
 
 // UpdateSchedule updates an existing course schedule
-func (s *CourseScheduleService) UpdateSchedule(schedule models.CourseSchedule) (models.CourseSchedule, error) {
+func (s *CourseScheduleService) UpdateSchedule(ctx context.Context, schedule models.CourseSchedule) (models.CourseSchedule, error) {
+	s = s.withContext(ctx)
+
 	// Check if schedule exists
 	existingSchedule, err := s.repo.GetByID(schedule.ID)
 	if err != nil {
@@ -268,7 +300,7 @@ func (s *CourseScheduleService) UpdateSchedule(schedule models.CourseSchedule) (
 		// Update capacity from room if room changed or capacity is not set
 		if schedule.Capacity <= 0 {
 			schedule.Capacity = room.Capacity
-			fmt.Printf("Updating schedule capacity to room capacity: %d\n", room.Capacity)
+			slog.DebugContext(ctx, "Updating schedule capacity to room capacity", "schedule_id", schedule.ID, "capacity", room.Capacity)
 		}
 	}
 
@@ -355,30 +387,29 @@ func (s *CourseScheduleService) GetSchedulesByStudentGroupAndAcademicYear(studen
 }
 
 // FormatScheduleForResponse formats a schedule for response to clients
-func (s *CourseScheduleService) FormatScheduleForResponse(schedule models.CourseSchedule) map[string]interface{} {
+func (s *CourseScheduleService) FormatScheduleForResponse(schedule models.CourseSchedule) models.CourseScheduleResponse {
 	// Build a response that matches the expected frontend format
-	response := map[string]interface{}{
-		"id":               schedule.ID,
-		"course_id":        schedule.CourseID,
-		"room_id":          schedule.RoomID,
-		"day":              schedule.Day,
-		"start_time":       schedule.StartTime,
-		"end_time":         schedule.EndTime,
-		"lecturer_id":      schedule.UserID,
-		"student_group_id": schedule.StudentGroupID,
-		"academic_year_id": schedule.AcademicYearID,
-		"capacity":         schedule.Capacity,
-		"enrolled":         0, // Default to 0, will be updated below
+	response := models.CourseScheduleResponse{
+		ID:             schedule.ID,
+		CourseID:       schedule.CourseID,
+		RoomID:         schedule.RoomID,
+		Day:            schedule.Day,
+		StartTime:      schedule.StartTime,
+		EndTime:        schedule.EndTime,
+		LecturerID:     schedule.UserID,
+		StudentGroupID: schedule.StudentGroupID,
+		AcademicYearID: schedule.AcademicYearID,
+		Capacity:       schedule.Capacity,
+		Enrolled:       0, // Default to 0, will be updated below
 	}
 
 	// Always get actual student count for this group from DB, don't trust the cached value
 	if schedule.StudentGroupID > 0 {
 		// Get student count from the student_to_groups table
-		var count int64
-		s.repo.DB().Model(&models.StudentToGroup{}).Where("student_group_id = ?", schedule.StudentGroupID).Count(&count)
+		count, _ := s.studentGroupRepo.CountMembers(schedule.StudentGroupID)
 
 		// Update the enrolled value with the actual count
-		response["enrolled"] = count
+		response.Enrolled = int(count)
 
 		// Also update the model value for future use
 		schedule.Enrolled = int(count)
@@ -386,22 +417,22 @@ func (s *CourseScheduleService) FormatScheduleForResponse(schedule models.Course
 
 	// Add related data if loaded
 	if schedule.Course.ID != 0 {
-		response["course_name"] = schedule.Course.Name
-		response["course_code"] = schedule.Course.Code
-		response["semester"] = schedule.Course.Semester
+		response.CourseName = schedule.Course.Name
+		response.CourseCode = schedule.Course.Code
+		response.Semester = schedule.Course.Semester
 	}
 
 	if schedule.Room.ID != 0 {
-		response["room_name"] = schedule.Room.Name
+		response.RoomName = schedule.Room.Name
 
 		if schedule.Room.Building.ID != 0 {
-			response["building_name"] = schedule.Room.Building.Name
+			response.BuildingName = schedule.Room.Building.Name
 		}
 	}
 
 	// Better lecturer name resolution with multiple fallbacks
 	lecturerName := "Dosen" // Default fallback
-	lecturerRepo := repositories.NewLecturerRepository()
+	lecturerRepo := s.lecturerProfileRepo
 
 	// Try multiple approaches to get the lecturer name
 
@@ -435,26 +466,26 @@ func (s *CourseScheduleService) FormatScheduleForResponse(schedule models.Course
 	}
 
 	// Save the found lecturer name
-	response["lecturer_name"] = lecturerName
+	response.LecturerName = lecturerName
 
 	// Add student group and academic year names if available
 	if schedule.StudentGroup.ID != 0 {
-		response["student_group_name"] = schedule.StudentGroup.Name
+		response.StudentGroupName = schedule.StudentGroup.Name
 	} else if schedule.StudentGroupID > 0 {
 		// Try to fetch the name directly if not loaded with the schedule
 		studentGroup, err := s.studentGroupRepo.GetByID(schedule.StudentGroupID)
 		if err == nil && studentGroup.ID > 0 {
-			response["student_group_name"] = studentGroup.Name
+			response.StudentGroupName = studentGroup.Name
 		}
 	}
 
 	if schedule.AcademicYear.ID != 0 {
-		response["academic_year_name"] = schedule.AcademicYear.Name
+		response.AcademicYearName = schedule.AcademicYear.Name
 	} else if schedule.AcademicYearID > 0 {
 		// Try to fetch the name directly if not loaded with the schedule
 		academicYear, err := s.academicYearRepo.FindByID(schedule.AcademicYearID)
 		if err == nil && academicYear != nil {
-			response["academic_year_name"] = academicYear.Name
+			response.AcademicYearName = academicYear.Name
 		}
 	}
 
@@ -462,92 +493,51 @@ func (s *CourseScheduleService) FormatScheduleForResponse(schedule models.Course
 }
 
 // FormatSchedulesForResponse formats multiple schedules for response
-func (s *CourseScheduleService) FormatSchedulesForResponse(schedules []models.CourseSchedule) []map[string]interface{} {
-	result := make([]map[string]interface{}, len(schedules))
+func (s *CourseScheduleService) FormatSchedulesForResponse(schedules []models.CourseSchedule) []models.CourseScheduleResponse {
+	result := make([]models.CourseScheduleResponse, len(schedules))
 	for i, schedule := range schedules {
 		result[i] = s.FormatScheduleForResponse(schedule)
 	}
 	return result
 }
 
-// CheckForScheduleConflicts checks for various scheduling conflicts
-func (s *CourseScheduleService) CheckForScheduleConflicts(
-	scheduleID *uint,
-	roomID uint,
-	userID uint,
-	studentGroupID uint,
-	day string,
-	startTime string,
-	endTime string,
-) (map[string]bool, error) {
-	// Result map to hold conflicts by type
-	conflicts := map[string]bool{
-		"room":          false,
-		"lecturer":      false,
-		"student_group": false,
-	}
-
-	// Check room conflicts
-	roomConflict, err := s.repo.CheckScheduleConflict(roomID, day, startTime, endTime, scheduleID)
-	if err != nil {
-		return conflicts, err
-	}
-	conflicts["room"] = roomConflict
-
-	// Check lecturer conflicts
-	lecturerConflict, err := s.repo.CheckLecturerScheduleConflict(userID, day, startTime, endTime, scheduleID)
-	if err != nil {
-		return conflicts, err
-	}
-	conflicts["lecturer"] = lecturerConflict
-
-	// Check student group conflicts
-	studentGroupConflict, err := s.repo.CheckStudentGroupScheduleConflict(studentGroupID, day, startTime, endTime, scheduleID)
-	if err != nil {
-		return conflicts, err
-	}
-	conflicts["student_group"] = studentGroupConflict
-
-	return conflicts, nil
-}
-
-// CheckRoomScheduleConflict checks if there's a room schedule conflict
-func (s *CourseScheduleService) CheckRoomScheduleConflict(roomID uint, day string, startTime string, endTime string, scheduleID *uint) (bool, error) {
-	return s.repo.CheckScheduleConflict(roomID, day, startTime, endTime, scheduleID)
+// CheckRoomScheduleConflict checks if there's a room schedule conflict within an academic year
+func (s *CourseScheduleService) CheckRoomScheduleConflict(academicYearID, roomID uint, day string, startTime string, endTime string, scheduleID *uint) (bool, error) {
+	return s.repo.CheckScheduleConflict(academicYearID, roomID, day, startTime, endTime, scheduleID)
 }
 
-// CheckLecturerScheduleConflict checks if there's a lecturer schedule conflict
-func (s *CourseScheduleService) CheckLecturerScheduleConflict(userID uint, day string, startTime string, endTime string, scheduleID *uint) (bool, error) {
-	return s.repo.CheckLecturerScheduleConflict(userID, day, startTime, endTime, scheduleID)
+// CheckLecturerScheduleConflict checks if there's a lecturer schedule conflict within an academic year
+func (s *CourseScheduleService) CheckLecturerScheduleConflict(academicYearID, userID uint, day string, startTime string, endTime string, scheduleID *uint) (bool, error) {
+	return s.repo.CheckLecturerScheduleConflict(academicYearID, userID, day, startTime, endTime, scheduleID)
 }
 
-// CheckStudentGroupScheduleConflict checks if there's a student group schedule conflict
-func (s *CourseScheduleService) CheckStudentGroupScheduleConflict(studentGroupID uint, day string, startTime string, endTime string, scheduleID *uint) (bool, error) {
-	return s.repo.CheckStudentGroupScheduleConflict(studentGroupID, day, startTime, endTime, scheduleID)
+// CheckStudentGroupScheduleConflict checks if there's a student group schedule conflict within an academic year
+func (s *CourseScheduleService) CheckStudentGroupScheduleConflict(academicYearID, studentGroupID uint, day string, startTime string, endTime string, scheduleID *uint) (bool, error) {
+	return s.repo.CheckStudentGroupScheduleConflict(academicYearID, studentGroupID, day, startTime, endTime, scheduleID)
 }
 
 // GetStudentSchedules gets all course schedules for a student by their user ID
-func (s *CourseScheduleService) GetStudentSchedules(studentUserID uint) ([]models.CourseSchedule, error) {
+func (s *CourseScheduleService) GetStudentSchedules(ctx context.Context, studentUserID uint) ([]models.CourseSchedule, error) {
+	s = s.withContext(ctx)
+
 	// First find the student
-	studentRepo := repositories.NewStudentRepository()
+	studentRepo := s.studentRepo
 	student, err := studentRepo.FindByUserID(int(studentUserID))
 	if err != nil || student == nil {
 		// Log the specific error for debugging
-		fmt.Printf("Failed to find student with userID=%d: %v\n", studentUserID, err)
+		slog.DebugContext(ctx, "Student not found by user ID, trying fallbacks", "user_id", studentUserID, "error", err)
 
 		// Try to directly use the userID if we can't find the student
 		// This is a fallback to make the system more robust
 		var fallbackSchedules []models.CourseSchedule
 
 		// Check if this is directly a student ID instead of a user ID
-		var studentExists bool
-		if err := s.repo.DB().Raw(`SELECT EXISTS(SELECT 1 FROM students WHERE id = ?)`, studentUserID).Scan(&studentExists).Error; err == nil && studentExists {
-			fmt.Printf("Found direct student ID match for ID=%d\n", studentUserID)
+		if studentExists, err := studentRepo.ExistsByID(studentUserID); err == nil && studentExists {
+			slog.DebugContext(ctx, "Found student by direct ID", "student_id", studentUserID)
 
 			// Get groups for this direct student ID
-			var groupIDs []uint
-			if err := s.repo.DB().Table("student_to_groups").Where("student_id = ?", studentUserID).Pluck("student_group_id", &groupIDs).Error; err == nil && len(groupIDs) > 0 {
-				fmt.Printf("Found %d groups for direct student ID=%d\n", len(groupIDs), studentUserID)
+			if groupIDs, err := s.studentGroupRepo.GetGroupIDsByStudentID(studentUserID); err == nil && len(groupIDs) > 0 {
+				slog.DebugContext(ctx, "Found groups for student", "student_id", studentUserID, "count", len(groupIDs))
 
 				// Get schedules for these groups
 				for _, groupID := range groupIDs {
@@ -560,21 +550,19 @@ func (s *CourseScheduleService) GetStudentSchedules(studentUserID uint) ([]model
 		}
 
 		// Try looking up by user record first
-		var user models.User
-		if err := s.repo.DB().Where("id = ?", studentUserID).First(&user).Error; err == nil {
-			fmt.Printf("Found user with ID=%d, role=%s\n", user.ID, user.Role)
+		if user, err := s.lecturerRepo.FindByID(studentUserID); err == nil && user != nil {
+			slog.DebugContext(ctx, "Found user for student lookup", "user_id", user.ID, "role", user.Role)
 
 			// If the user's role is Mahasiswa, we can proceed
 			if user.Role == "Mahasiswa" || user.Role == "mahasiswa" {
 				// Try to find the student directly by the user ID in the students table
-				var studentID uint
-				if err := s.repo.DB().Table("students").Where("user_id = ?", studentUserID).Pluck("id", &studentID).Error; err == nil && studentID > 0 {
-					fmt.Printf("Found student ID=%d for user ID=%d\n", studentID, studentUserID)
+				if studentRecord, err := studentRepo.FindByUserID(int(studentUserID)); err == nil && studentRecord != nil {
+					studentID := studentRecord.ID
+					slog.DebugContext(ctx, "Found student for user", "student_id", studentID, "user_id", studentUserID)
 
 					// Get groups for this student
-					var groupIDs []uint
-					if err := s.repo.DB().Table("student_to_groups").Where("student_id = ?", studentID).Pluck("student_group_id", &groupIDs).Error; err == nil && len(groupIDs) > 0 {
-						fmt.Printf("Found %d groups for student ID=%d\n", len(groupIDs), studentID)
+					if groupIDs, err := s.studentGroupRepo.GetGroupIDsByStudentID(studentID); err == nil && len(groupIDs) > 0 {
+						slog.DebugContext(ctx, "Found groups for student", "student_id", studentID, "count", len(groupIDs))
 
 						// Get schedules for these groups
 						for _, groupID := range groupIDs {
@@ -590,28 +578,26 @@ func (s *CourseScheduleService) GetStudentSchedules(studentUserID uint) ([]model
 
 		// Return fallback schedules if we found any
 		if len(fallbackSchedules) > 0 {
-			fmt.Printf("Returning %d fallback schedules for user ID=%d\n", len(fallbackSchedules), studentUserID)
+			slog.DebugContext(ctx, "Returning fallback schedules", "user_id", studentUserID, "count", len(fallbackSchedules))
 			return fallbackSchedules, nil
 		}
 
 		// If we couldn't find anything, return empty list instead of error
 		// This prevents the 500 error but returns empty data
-		fmt.Printf("Could not find any schedules for user ID=%d, returning empty list\n", studentUserID)
+		slog.DebugContext(ctx, "No schedules found for user", "user_id", studentUserID)
 		return []models.CourseSchedule{}, nil
 	}
 
 	// Get student groups for this student directly from the database
-	var groupIDs []uint
-	if err := s.repo.DB().Table("student_to_groups").
-		Where("student_id = ?", student.ID).
-		Pluck("student_group_id", &groupIDs).Error; err != nil {
-		fmt.Printf("Failed to get student groups for student ID=%d: %v\n", student.ID, err)
+	groupIDs, err := s.studentGroupRepo.GetGroupIDsByStudentID(student.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get student groups", "student_id", student.ID, "error", err)
 		return []models.CourseSchedule{}, nil // Return empty list instead of error
 	}
 
 	// Check if we found any groups
 	if len(groupIDs) == 0 {
-		fmt.Printf("No student groups found for student ID=%d\n", student.ID)
+		slog.DebugContext(ctx, "No student groups found", "student_id", student.ID)
 		return []models.CourseSchedule{}, nil // Return empty list
 	}
 
@@ -621,13 +607,13 @@ func (s *CourseScheduleService) GetStudentSchedules(studentUserID uint) ([]model
 		schedules, err := s.repo.GetByStudentGroup(groupID)
 		if err != nil {
 			// Log the error but continue with other groups
-			fmt.Printf("Error getting schedules for group %d: %v\n", groupID, err)
+			slog.ErrorContext(ctx, "Error getting schedules for group", "group_id", groupID, "error", err)
 			continue
 		}
 
 		allSchedules = append(allSchedules, schedules...)
 	}
 
-	fmt.Printf("Returning %d schedules for student ID=%d (user ID=%d)\n", len(allSchedules), student.ID, studentUserID)
+	slog.DebugContext(ctx, "Returning student schedules", "student_id", student.ID, "user_id", studentUserID, "count", len(allSchedules))
 	return allSchedules, nil
 }
@@ -4,35 +4,46 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/delpresence/backend/internal/config"
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/repositories"
 )
 
-const (
-	CampusEmployeesURL = "https://cis.del.ac.id/api/library-api/pegawai"
-)
-
 // EmployeeService handles business logic for employees
 type EmployeeService struct {
-	repo       *repositories.EmployeeRepository
+	repo       repositories.EmployeeStore
 	campusAuth *CampusAuthService
 }
 
 // NewEmployeeService creates a new employee service
 func NewEmployeeService() *EmployeeService {
+	return NewEmployeeServiceWithStores(
+		repositories.NewEmployeeRepository(),
+		NewCampusAuthService(),
+	)
+}
+
+// NewEmployeeServiceWithStores creates a new employee service on top of the given stores
+func NewEmployeeServiceWithStores(repo repositories.EmployeeStore, campusAuth *CampusAuthService) *EmployeeService {
 	return &EmployeeService{
-		repo:       repositories.NewEmployeeRepository(),
-		campusAuth: NewCampusAuthService(),
+		repo:       repo,
+		campusAuth: campusAuth,
 	}
 }
 
-// GetAllEmployees returns all employees
-func (s *EmployeeService) GetAllEmployees() ([]models.Employee, error) {
-	return s.repo.FindAll()
+// ListEmployees returns one page of employees
+func (s *EmployeeService) ListEmployees(q models.ListQuery) (models.ListResult[models.Employee], error) {
+	return s.repo.List(q)
+}
+
+// GetActiveEmployees returns the employees that have not been deactivated by the sync
+func (s *EmployeeService) GetActiveEmployees() ([]models.Employee, error) {
+	return s.repo.FindActive()
 }
 
 // GetEmployeeByID returns an employee by ID
@@ -42,16 +53,29 @@ func (s *EmployeeService) GetEmployeeByID(id uint) (*models.Employee, error) {
 
 // SyncEmployees synchronizes employees with the campus API
 func (s *EmployeeService) SyncEmployees() (int, error) {
+	employees, err := s.FetchCampusEmployees()
+	if err != nil {
+		return 0, err
+	}
+	result, err := s.ApplyEmployees(employees)
+	if err != nil {
+		return 0, err
+	}
+	return result.Inserted + result.Updated, nil
+}
+
+// FetchCampusEmployees fetches employees from the campus API and converts them without saving
+func (s *EmployeeService) FetchCampusEmployees() ([]models.Employee, error) {
 	// Get auth token from campus auth service
 	token, err := s.campusAuth.GetToken()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get authentication token: %w", err)
+		return nil, fmt.Errorf("failed to get authentication token: %w", err)
 	}
 
 	// Fetch employees from campus API
 	employeeData, err := s.fetchEmployeesFromCampus(token)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// Convert to internal model
@@ -100,31 +124,62 @@ func (s *EmployeeService) SyncEmployees() (int, error) {
 		}
 
 		employee := models.Employee{
-			EmployeeID:     pegawaiID,
-			UserID:         userID,
-			NIP:            empData.NIP,
-			FullName:       empData.Nama,
-			Email:          email,
-			Position:       empData.Posisi,
-			Department:     "", // Not available in the response
-			EmploymentType: empData.StatusPegawai,
-			LastSync:       time.Now(),
+			EmployeeID:      pegawaiID,
+			UserID:          userID,
+			NIP:             empData.NIP,
+			FullName:        empData.Nama,
+			Email:           email,
+			Position:        empData.Posisi,
+			Department:      "", // Not available in the response
+			EmploymentType:  empData.StatusPegawai,
+			LastSync:        time.Now(),
+			LifecycleStatus: models.LifecycleActive,
 		}
 		employees = append(employees, employee)
 	}
 
-	// Save employees to database
-	if err := s.repo.UpsertMany(employees); err != nil {
-		return 0, err
+	return employees, nil
+}
+
+// ApplyEmployees saves employees fetched from the campus API
+// Employees that CIS no longer returns are deactivated
+func (s *EmployeeService) ApplyEmployees(employees []models.Employee) (*models.SyncResult, error) {
+	result := &models.SyncResult{Received: len(employees)}
+	if err := s.repo.UpsertMany(employees, result); err != nil {
+		return nil, err
+	}
+
+	syncedIDs := make(map[int]bool, len(employees))
+	for _, employee := range employees {
+		syncedIDs[employee.EmployeeID] = true
+	}
+
+	current, err := s.repo.FindActive()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	for _, employee := range current {
+		if !syncedIDs[employee.EmployeeID] {
+			ids = append(ids, employee.ID)
+		}
+	}
+	if len(ids) > 0 {
+		slog.Info("Deactivating employees no longer returned by CIS", "count", len(ids))
+		if err := s.repo.Deactivate(ids, "no longer returned by CIS", time.Now()); err != nil {
+			return nil, err
+		}
+		result.Deactivated = len(ids)
 	}
 
-	return len(employees), nil
+	return result, nil
 }
 
 // fetchEmployeesFromCampus fetches employees from the campus API
 func (s *EmployeeService) fetchEmployeesFromCampus(token string) ([]models.CampusEmployee, error) {
 	// Create a new HTTP request
-	req, err := http.NewRequest("GET", CampusEmployeesURL, nil)
+	req, err := http.NewRequest("GET", config.Get().Campus.EmployeesURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -168,4 +223,4 @@ func (s *EmployeeService) fetchEmployeesFromCampus(token string) ([]models.Campu
 	}
 
 	return response.Data.Pegawai, nil
-} 
\ No newline at end of file
+}
@@ -2,20 +2,37 @@ package services
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/repositories"
 )
 
+var (
+	// ErrFacultyNotFound is returned for faculties that do not exist
+	ErrFacultyNotFound = errors.New("faculty not found")
+
+	// ErrFacultyCodeExists is returned when another faculty, possibly a deleted one, has the code
+	ErrFacultyCodeExists = errors.New("a faculty with this code already exists")
+
+	// ErrFacultyHasStudyPrograms is returned when deleting a faculty that still has study programs
+	ErrFacultyHasStudyPrograms = errors.New("faculty still has study programs")
+)
+
 // FacultyService is a service for faculty operations
 type FacultyService struct {
-	repository *repositories.FacultyRepository
+	repository repositories.FacultyStore
 }
 
 // NewFacultyService creates a new faculty service
 func NewFacultyService() *FacultyService {
+	return NewFacultyServiceWithStores(repositories.NewFacultyRepository())
+}
+
+// NewFacultyServiceWithStores creates a new faculty service on top of the given stores
+func NewFacultyServiceWithStores(repository repositories.FacultyStore) *FacultyService {
 	return &FacultyService{
-		repository: repositories.NewFacultyRepository(),
+		repository: repository,
 	}
 }
 
@@ -51,7 +68,7 @@ func (s *FacultyService) CreateFaculty(faculty *models.Faculty) error {
 			return nil
 		}
 		
-		return errors.New("fakultas dengan kode ini sudah ada")
+		return ErrFacultyCodeExists
 	}
 
 	// Create faculty
@@ -66,7 +83,7 @@ func (s *FacultyService) UpdateFaculty(faculty *models.Faculty) error {
 		return err
 	}
 	if existingFaculty == nil {
-		return errors.New("fakultas tidak ditemukan")
+		return ErrFacultyNotFound
 	}
 
 	// If code is changed, check if new code already exists (including soft-deleted records)
@@ -76,7 +93,7 @@ func (s *FacultyService) UpdateFaculty(faculty *models.Faculty) error {
 			return err
 		}
 		if exists {
-			return errors.New("fakultas dengan kode ini sudah ada (termasuk yang sudah dihapus)")
+			return fmt.Errorf("%w, including deleted ones", ErrFacultyCodeExists)
 		}
 	}
 
@@ -89,9 +106,9 @@ func (s *FacultyService) GetFacultyByID(id uint) (*models.Faculty, error) {
 	return s.repository.FindByID(id)
 }
 
-// GetAllFaculties gets all faculties
-func (s *FacultyService) GetAllFaculties() ([]models.Faculty, error) {
-	return s.repository.FindAll()
+// ListFaculties returns one page of faculties
+func (s *FacultyService) ListFaculties(q models.ListQuery) (models.ListResult[models.Faculty], error) {
+	return s.repository.List(q)
 }
 
 // DeleteFaculty deletes a faculty
@@ -102,7 +119,7 @@ func (s *FacultyService) DeleteFaculty(id uint) error {
 		return err
 	}
 	if faculty == nil {
-		return errors.New("fakultas tidak ditemukan")
+		return ErrFacultyNotFound
 	}
 
 	// Check if there are any associated study programs
@@ -111,7 +128,7 @@ func (s *FacultyService) DeleteFaculty(id uint) error {
 		return err
 	}
 	if programCount > 0 {
-		return errors.New("tidak dapat menghapus fakultas yang memiliki program studi")
+		return ErrFacultyHasStudyPrograms
 	}
 
 	// Delete faculty
@@ -133,7 +150,7 @@ func (s *FacultyService) GetFacultyWithStats(id uint) (*FacultyWithStats, error)
 		return nil, err
 	}
 	if faculty == nil {
-		return nil, errors.New("fakultas tidak ditemukan")
+		return nil, ErrFacultyNotFound
 	}
 
 	// Count programs
@@ -150,24 +167,23 @@ func (s *FacultyService) GetFacultyWithStats(id uint) (*FacultyWithStats, error)
 	}, nil
 }
 
-// GetAllFacultiesWithStats gets all faculties with their statistics
-func (s *FacultyService) GetAllFacultiesWithStats() ([]FacultyWithStats, error) {
-	// Get all faculties
-	faculties, err := s.repository.FindAll()
+// ListFacultiesWithStats returns one page of faculties with their statistics
+func (s *FacultyService) ListFacultiesWithStats(q models.ListQuery) (models.ListResult[FacultyWithStats], error) {
+	page, err := s.repository.List(q)
 	if err != nil {
-		return nil, err
+		return models.ListResult[FacultyWithStats]{}, err
 	}
 
 	// Build response with stats
-	result := make([]FacultyWithStats, len(faculties))
-	for i, faculty := range faculties {
+	result := models.ListResult[FacultyWithStats]{Items: make([]FacultyWithStats, len(page.Items)), Page: page.Page}
+	for i, faculty := range page.Items {
 		// Count programs for each faculty
 		programCount, err := s.repository.CountStudyPrograms(faculty.ID)
 		if err != nil {
-			return nil, err
+			return models.ListResult[FacultyWithStats]{}, err
 		}
 
-		result[i] = FacultyWithStats{
+		result.Items[i] = FacultyWithStats{
 			Faculty:       faculty,
 			ProgramCount:  programCount,
 			LecturerCount: int64(faculty.LecturerCount),
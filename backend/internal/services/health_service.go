@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+)
+
+const (
+	// readinessCheckTimeout bounds each readiness check, so a hung dependency cannot hang the probe
+	readinessCheckTimeout = 3 * time.Second
+	// campusCheckInterval is how long a CIS reachability result is reused, probes must not load CIS
+	campusCheckInterval = 30 * time.Second
+)
+
+// HealthService answers the liveness and readiness probes
+type HealthService struct {
+	campusURL     string
+	campusEnabled bool
+	client        *http.Client
+	draining      atomic.Bool
+
+	campusMu      sync.Mutex
+	campusResult  models.HealthCheck
+	campusChecked time.Time
+}
+
+var (
+	healthService     *HealthService
+	healthServiceOnce sync.Once
+)
+
+// GetHealthService returns the shared health service
+// It is shared so the shutdown sequence can mark the server as draining for the readiness probe
+func GetHealthService() *HealthService {
+	healthServiceOnce.Do(func() {
+		healthService = &HealthService{
+			campusURL:     config.Get().Readiness.CampusURL,
+			campusEnabled: config.Get().Readiness.CheckCampus,
+			client:        &http.Client{Timeout: readinessCheckTimeout},
+		}
+	})
+	return healthService
+}
+
+// StartDraining makes the readiness probe fail while the server shuts down
+func (s *HealthService) StartDraining() {
+	s.draining.Store(true)
+}
+
+// Readiness checks the database, the schema version and CIS
+// CIS is reported but never makes the server unready, the app works without it apart from syncs and campus login
+func (s *HealthService) Readiness(ctx context.Context) models.ReadinessReport {
+	report := models.ReadinessReport{
+		Draining: s.draining.Load(),
+		Checks: map[string]models.HealthCheck{
+			"database":   s.checkDatabase(ctx),
+			"migrations": s.checkMigrations(),
+			"campus":     s.checkCampus(ctx),
+		},
+	}
+
+	report.Ready = !report.Draining
+	for _, check := range report.Checks {
+		if check.Required && check.Status != models.HealthStatusOK {
+			report.Ready = false
+		}
+	}
+	return report
+}
+
+// checkDatabase pings the database
+func (s *HealthService) checkDatabase(ctx context.Context) models.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := database.Ping(ctx)
+	return newHealthCheck(true, start, err)
+}
+
+// checkMigrations verifies that every migration known to this build has been applied
+func (s *HealthService) checkMigrations() models.HealthCheck {
+	start := time.Now()
+	err := database.CheckSchema()
+	check := newHealthCheck(true, start, err)
+	check.Latest = database.LatestVersion()
+	if version, versionErr := database.SchemaVersion(); versionErr == nil {
+		check.Version = version
+	}
+	return check
+}
+
+// checkCampus checks that CIS answers HTTP requests, any status code counts as reachable
+// The result is cached for campusCheckInterval
+func (s *HealthService) checkCampus(ctx context.Context) models.HealthCheck {
+	if !s.campusEnabled {
+		return models.HealthCheck{Status: models.HealthStatusDisabled}
+	}
+
+	s.campusMu.Lock()
+	defer s.campusMu.Unlock()
+	if !s.campusChecked.IsZero() && time.Since(s.campusChecked) < campusCheckInterval {
+		return s.campusResult
+	}
+
+	start := time.Now()
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, s.campusURL, nil)
+	if err == nil {
+		var response *http.Response
+		response, err = s.client.Do(request)
+		if err == nil {
+			response.Body.Close()
+		}
+	}
+
+	s.campusResult = newHealthCheck(false, start, err)
+	s.campusChecked = time.Now()
+	return s.campusResult
+}
+
+// newHealthCheck builds the result of a check that started at start
+func newHealthCheck(required bool, start time.Time, err error) models.HealthCheck {
+	check := models.HealthCheck{
+		Status:    models.HealthStatusOK,
+		Required:  required,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		check.Status = models.HealthStatusFailed
+		check.Error = err.Error()
+	}
+	return check
+}
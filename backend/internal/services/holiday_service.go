@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+// dateLayout is the format of calendar dates in requests, YYYY-MM-DD
+const dateLayout = "2006-01-02"
+
+var (
+	// ErrHolidayNotFound is returned for holidays that do not exist
+	ErrHolidayNotFound = errors.New("holiday not found")
+
+	// ErrInvalidHoliday is returned for holidays with invalid dates
+	ErrInvalidHoliday = errors.New("invalid holiday")
+)
+
+// HolidayService manages the days without classes, which calendar feeds leave out
+type HolidayService struct {
+	holidays repositories.HolidayStore
+}
+
+// NewHolidayService creates a new holiday service
+func NewHolidayService() *HolidayService {
+	return NewHolidayServiceWithStores(repositories.NewHolidayRepository())
+}
+
+// NewHolidayServiceWithStores creates a new holiday service on top of the given store
+func NewHolidayServiceWithStores(holidays repositories.HolidayStore) *HolidayService {
+	return &HolidayService{holidays: holidays}
+}
+
+// List returns one page of holidays, by start date unless sorted otherwise
+func (s *HolidayService) List(q models.ListQuery) (models.ListResult[models.Holiday], error) {
+	return s.holidays.List(q)
+}
+
+// Create adds a holiday after checking its dates
+func (s *HolidayService) Create(request models.HolidayRequest) (*models.Holiday, error) {
+	if request.EndDate == "" {
+		request.EndDate = request.StartDate
+	}
+	start, startErr := time.Parse(dateLayout, request.StartDate)
+	end, endErr := time.Parse(dateLayout, request.EndDate)
+	if startErr != nil || endErr != nil || end.Before(start) {
+		return nil, fmt.Errorf("%w: start_date and end_date must be YYYY-MM-DD with end_date not before start_date", ErrInvalidHoliday)
+	}
+
+	holiday := &models.Holiday{
+		Name:      strings.TrimSpace(request.Name),
+		StartDate: request.StartDate,
+		EndDate:   request.EndDate,
+	}
+	if err := s.holidays.Create(holiday); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+// Delete removes a holiday
+func (s *HolidayService) Delete(id uint) error {
+	holiday, err := s.holidays.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if holiday == nil {
+		return ErrHolidayNotFound
+	}
+	return s.holidays.Delete(id)
+}
+
+// holidayOn returns the holiday a date falls in, or nil
+func holidayOn(holidays []models.Holiday, date string) *models.Holiday {
+	for i := range holidays {
+		if holidays[i].StartDate <= date && date <= holidays[i].EndDate {
+			return &holidays[i]
+		}
+	}
+	return nil
+}
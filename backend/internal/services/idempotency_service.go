@@ -0,0 +1,115 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+const (
+	// idempotencyKeyTTL is how long a key returns the original response
+	idempotencyKeyTTL = 24 * time.Hour
+
+	// idempotencyClaimTimeout is how long a request may hold a key without completing it, after
+	// which the request is assumed lost, for example when the server stopped, and the key is freed
+	idempotencyClaimTimeout = time.Minute
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when a key is sent again with a different request body
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+
+	// ErrIdempotencyKeyInProgress is returned while the first request with a key is still being handled
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still being processed")
+)
+
+// IdempotencyService stores the responses to requests sent with an Idempotency-Key header, so
+// clients on flaky connections can retry a request without it running twice
+type IdempotencyService struct {
+	repository repositories.IdempotencyStore
+}
+
+// NewIdempotencyService creates a new idempotency service
+func NewIdempotencyService() *IdempotencyService {
+	return NewIdempotencyServiceWithStores(repositories.NewIdempotencyRepository())
+}
+
+// NewIdempotencyServiceWithStores creates a new idempotency service on top of the given stores
+func NewIdempotencyServiceWithStores(repository repositories.IdempotencyStore) *IdempotencyService {
+	return &IdempotencyService{
+		repository: repository,
+	}
+}
+
+// Begin claims a key of a user on a route for a request body
+// When the key was used before for the same body, it returns the stored record with replay set and
+// the caller answers with its response. Otherwise it returns a new record, which the caller must
+// Complete with the response or Release when the request failed.
+func (s *IdempotencyService) Begin(userID uint, route, key string, body []byte) (record *models.IdempotencyRecord, replay bool, err error) {
+	sum := sha256.Sum256(body)
+	requestHash := hex.EncodeToString(sum[:])
+	now := time.Now()
+
+	existing, err := s.repository.Find(userID, route, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil && s.expired(existing, now) {
+		if err := s.repository.Delete(existing.ID); err != nil {
+			return nil, false, err
+		}
+		existing = nil
+	}
+	if existing != nil {
+		if existing.RequestHash != requestHash {
+			return nil, false, ErrIdempotencyKeyReused
+		}
+		if !existing.Completed() {
+			return nil, false, ErrIdempotencyKeyInProgress
+		}
+		return existing, true, nil
+	}
+
+	// Expired keys are removed as new ones come in
+	if err := s.repository.DeleteCreatedBefore(now.Add(-idempotencyKeyTTL)); err != nil {
+		return nil, false, err
+	}
+
+	record = &models.IdempotencyRecord{
+		UserID:      userID,
+		Route:       route,
+		Key:         key,
+		RequestHash: requestHash,
+	}
+	created, err := s.repository.Create(record)
+	if err != nil {
+		return nil, false, err
+	}
+	if !created {
+		// Another request with the same key claimed it first
+		return nil, false, ErrIdempotencyKeyInProgress
+	}
+	return record, false, nil
+}
+
+// Complete stores the response to the request of a record, it is returned for later retries
+func (s *IdempotencyService) Complete(record *models.IdempotencyRecord, statusCode int, responseBody []byte) error {
+	return s.repository.Complete(record.ID, statusCode, string(responseBody))
+}
+
+// Release frees the key of a record whose request failed, so a retry runs the request again
+func (s *IdempotencyService) Release(record *models.IdempotencyRecord) error {
+	return s.repository.Delete(record.ID)
+}
+
+// expired reports whether a record no longer holds its key
+func (s *IdempotencyService) expired(record *models.IdempotencyRecord, now time.Time) bool {
+	if record.Completed() {
+		return now.Sub(record.CreatedAt) > idempotencyKeyTTL
+	}
+	return now.Sub(record.CreatedAt) > idempotencyClaimTimeout
+}
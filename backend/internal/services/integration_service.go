@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+// ErrNoActiveSession is returned when no attendance session is open in a room
+var ErrNoActiveSession = errors.New("no active attendance session in this room")
+
+// IntegrationService serves the read-only data exposed to kiosks and service integrations
+type IntegrationService struct {
+	attendanceRepo repositories.AttendanceStore
+	scheduleRepo   repositories.CourseScheduleStore
+}
+
+// NewIntegrationService creates a new integration service
+func NewIntegrationService() *IntegrationService {
+	return NewIntegrationServiceWithStores(
+		repositories.NewAttendanceRepository(),
+		repositories.NewCourseScheduleRepository(),
+	)
+}
+
+// NewIntegrationServiceWithStores creates a new integration service on top of the given stores
+func NewIntegrationServiceWithStores(attendanceRepo repositories.AttendanceStore, scheduleRepo repositories.CourseScheduleStore) *IntegrationService {
+	return &IntegrationService{
+		attendanceRepo: attendanceRepo,
+		scheduleRepo:   scheduleRepo,
+	}
+}
+
+// GetActiveSessionForRoom returns the most recent active attendance session held in a room
+func (s *IntegrationService) GetActiveSessionForRoom(roomID uint) (*models.AttendanceSession, error) {
+	sessions, err := s.attendanceRepo.ListActiveSessionsByRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, ErrNoActiveSession
+	}
+	return &sessions[0], nil
+}
+
+// GetAttendanceReport summarizes attendance per course schedule of an academic year
+// Only schedules whose student group belongs to an allowed study program are included,
+// an empty allowed list means all study programs
+func (s *IntegrationService) GetAttendanceReport(academicYearID uint, studyProgramID uint, allowedStudyPrograms []uint) ([]models.AttendanceReportRow, error) {
+	schedules, err := s.scheduleRepo.GetByAcademicYear(academicYearID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := models.APIKey{StudyProgramIDs: allowedStudyPrograms}
+	rows := []models.AttendanceReportRow{}
+	for _, schedule := range schedules {
+		programID := schedule.StudentGroup.DepartmentID
+		if studyProgramID != 0 && programID != studyProgramID {
+			continue
+		}
+		if !allowed.AllowsStudyProgram(programID) {
+			continue
+		}
+
+		stats, err := s.attendanceRepo.GetAttendanceStats(schedule.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, models.AttendanceReportRow{
+			CourseScheduleID: schedule.ID,
+			CourseCode:       schedule.Course.Code,
+			CourseName:       schedule.Course.Name,
+			StudentGroup:     schedule.StudentGroup.Name,
+			StudyProgramID:   programID,
+			AcademicYearID:   schedule.AcademicYearID,
+			Day:              schedule.Day,
+			StartTime:        schedule.StartTime,
+			EndTime:          schedule.EndTime,
+			Statistics:       *stats,
+		})
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+var (
+	// ErrLecturerAvailabilityNotFound is returned for availability windows that do not exist
+	ErrLecturerAvailabilityNotFound = errors.New("lecturer availability not found")
+
+	// ErrInvalidLecturerAvailability is returned for windows with an unknown day or invalid times
+	ErrInvalidLecturerAvailability = errors.New("invalid lecturer availability")
+)
+
+// LecturerAvailabilityService manages the weekly windows in which lecturers cannot or would rather not teach
+type LecturerAvailabilityService struct {
+	windows repositories.LecturerAvailabilityStore
+}
+
+// NewLecturerAvailabilityService creates a new lecturer availability service
+func NewLecturerAvailabilityService() *LecturerAvailabilityService {
+	return NewLecturerAvailabilityServiceWithStores(repositories.NewLecturerAvailabilityRepository())
+}
+
+// NewLecturerAvailabilityServiceWithStores creates a new lecturer availability service on top of the given store
+func NewLecturerAvailabilityServiceWithStores(windows repositories.LecturerAvailabilityStore) *LecturerAvailabilityService {
+	return &LecturerAvailabilityService{windows: windows}
+}
+
+// List returns the windows of a lecturer, or of every lecturer when lecturerID is 0
+func (s *LecturerAvailabilityService) List(lecturerID uint) ([]models.LecturerAvailability, error) {
+	return s.windows.FindByLecturer(lecturerID)
+}
+
+// Create adds a window after checking its day and times
+func (s *LecturerAvailabilityService) Create(request models.LecturerAvailabilityRequest) (*models.LecturerAvailability, error) {
+	day := strings.TrimSpace(request.Day)
+	if dayOrder(strings.ToLower(day)) == len(weekDays) {
+		return nil, fmt.Errorf("%w: unknown day %q", ErrInvalidLecturerAvailability, request.Day)
+	}
+	start, startOK := clockMinutes(request.StartTime)
+	end, endOK := clockMinutes(request.EndTime)
+	if !startOK || !endOK || end <= start {
+		return nil, fmt.Errorf("%w: start_time and end_time must be HH:MM with end_time after start_time", ErrInvalidLecturerAvailability)
+	}
+
+	window := &models.LecturerAvailability{
+		UserID:    request.LecturerID,
+		Day:       day,
+		StartTime: formatClock(start),
+		EndTime:   formatClock(end),
+		Kind:      request.Kind,
+		Note:      request.Note,
+	}
+	if err := s.windows.Create(window); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// Delete removes a window
+func (s *LecturerAvailabilityService) Delete(id uint) error {
+	window, err := s.windows.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if window == nil {
+		return ErrLecturerAvailabilityNotFound
+	}
+	return s.windows.Delete(id)
+}
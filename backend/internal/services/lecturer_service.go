@@ -5,39 +5,54 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/delpresence/backend/internal/config"
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/repositories"
 )
 
-const (
-	CampusLecturersURL = "https://cis.del.ac.id/api/library-api/dosen"
-)
-
 // LecturerService handles lecturer operations
 type LecturerService struct {
-	repository   *repositories.LecturerRepository
-	campusAuth   *CampusAuthService
-	studyProgramRepository *repositories.StudyProgramRepository
+	repository             repositories.LecturerStore
+	studyProgramRepository repositories.StudyProgramStore
+	campusAuth             *CampusAuthService
 }
 
 // NewLecturerService creates a new LecturerService
 func NewLecturerService() *LecturerService {
+	return NewLecturerServiceWithStores(
+		repositories.NewLecturerRepository(),
+		repositories.NewStudyProgramRepository(),
+		NewCampusAuthService(),
+	)
+}
+
+// NewLecturerServiceWithStores creates a new lecturer service on top of the given stores
+func NewLecturerServiceWithStores(
+	repository repositories.LecturerStore,
+	studyProgramRepository repositories.StudyProgramStore,
+	campusAuth *CampusAuthService,
+) *LecturerService {
 	return &LecturerService{
-		repository: repositories.NewLecturerRepository(),
-		campusAuth: NewCampusAuthService(),
-		studyProgramRepository: repositories.NewStudyProgramRepository(),
+		repository:             repository,
+		studyProgramRepository: studyProgramRepository,
+		campusAuth:             campusAuth,
 	}
 }
 
-// GetAllLecturers returns all lecturers from the database
-func (s *LecturerService) GetAllLecturers() ([]models.Lecturer, error) {
-	return s.repository.FindAll()
+// ListLecturers returns one page of lecturers with their study program
+func (s *LecturerService) ListLecturers(q models.ListQuery) (models.ListResult[models.Lecturer], error) {
+	return s.repository.List(q)
+}
+
+// GetActiveLecturers returns the lecturers that have not been deactivated by the sync
+func (s *LecturerService) GetActiveLecturers() ([]models.Lecturer, error) {
+	return s.repository.FindActive()
 }
 
 // GetLecturerByID returns a lecturer by ID
@@ -47,10 +62,23 @@ func (s *LecturerService) GetLecturerByID(id uint) (*models.Lecturer, error) {
 
 // SyncLecturers fetches lecturers from the campus API and syncs them to the database
 func (s *LecturerService) SyncLecturers() (int, error) {
+	lecturers, err := s.FetchCampusLecturers()
+	if err != nil {
+		return 0, err
+	}
+	result, err := s.ApplyLecturers(lecturers)
+	if err != nil {
+		return 0, err
+	}
+	return result.Inserted + result.Updated, nil
+}
+
+// FetchCampusLecturers fetches lecturers from the campus API and converts them without saving
+func (s *LecturerService) FetchCampusLecturers() ([]models.Lecturer, error) {
 	// Get auth token from campus auth service
 	token, err := s.campusAuth.GetToken()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get authentication token: %w", err)
+		return nil, fmt.Errorf("failed to get authentication token: %w", err)
 	}
 
 	// Fetch lecturers from campus API
@@ -60,14 +88,14 @@ func (s *LecturerService) SyncLecturers() (int, error) {
 		if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "403") {
 			token, errRefresh := s.campusAuth.RefreshToken()
 			if errRefresh != nil {
-				return 0, fmt.Errorf("failed to refresh authentication token: %w", errRefresh)
+				return nil, fmt.Errorf("failed to refresh authentication token: %w", errRefresh)
 			}
 			campusLecturers, err = s.fetchLecturersFromCampus(token)
 			if err != nil {
-				return 0, err
+				return nil, err
 			}
 		} else {
-			return 0, err
+			return nil, err
 		}
 	}
 
@@ -95,12 +123,12 @@ func (s *LecturerService) SyncLecturers() (int, error) {
 			case string:
 				intVal, err := strconv.Atoi(v)
 				if err != nil {
-					log.Printf("Warning: could not convert string '%s' to int, using 0", v)
+					slog.Warn("Could not convert campus value to int, using 0", "value", v)
 					return 0
 				}
 				return intVal
 			default:
-				log.Printf("Warning: unexpected type for integer field: %T, using 0", val)
+				slog.Warn("Unexpected type of campus integer field, using 0", "type", fmt.Sprintf("%T", val))
 				return 0
 			}
 		}
@@ -136,6 +164,7 @@ func (s *LecturerService) SyncLecturers() (int, error) {
 			NIDN:             cl.NIDN,
 			UserID:           convertToInt(cl.UserID),
 			LastSync:         time.Now(),
+			LifecycleStatus:  models.LifecycleActive,
 		}
 		
 		// If we have a valid StudyProgramID, try to look up the associated StudyProgram
@@ -169,21 +198,51 @@ func (s *LecturerService) SyncLecturers() (int, error) {
 		lecturers = append(lecturers, lecturer)
 	}
 
-	// Save to database
-	err = s.repository.UpsertMany(lecturers)
+	return lecturers, nil
+}
+
+// ApplyLecturers saves lecturers fetched from the campus API
+// Lecturers that CIS no longer returns are deactivated
+func (s *LecturerService) ApplyLecturers(lecturers []models.Lecturer) (*models.SyncResult, error) {
+	result := &models.SyncResult{Received: len(lecturers)}
+	if err := s.repository.UpsertMany(lecturers, result); err != nil {
+		return nil, err
+	}
+
+	syncedIDs := make(map[int]bool, len(lecturers))
+	for _, lecturer := range lecturers {
+		syncedIDs[lecturer.LecturerID] = true
+	}
+
+	current, err := s.repository.FindActive()
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	var ids []uint
+	for _, lecturer := range current {
+		if !syncedIDs[lecturer.LecturerID] {
+			ids = append(ids, lecturer.ID)
+		}
+	}
+	if len(ids) > 0 {
+		slog.Info("Deactivating lecturers no longer returned by CIS", "count", len(ids))
+		if err := s.repository.Deactivate(ids, "no longer returned by CIS", time.Now()); err != nil {
+			return nil, err
+		}
+		result.Deactivated = len(ids)
 	}
 
-	return len(lecturers), nil
+	return result, nil
 }
 
 // fetchLecturersFromCampus fetches lecturers from the campus API
 func (s *LecturerService) fetchLecturersFromCampus(token string) ([]models.CampusLecturer, error) {
-	log.Printf("Fetching lecturers from campus API: %s", CampusLecturersURL)
+	url := config.Get().Campus.LecturersURL
+	slog.Info("Fetching lecturers from campus API", "url", url)
 	
 	// Create request to campus API
-	req, err := http.NewRequest("GET", CampusLecturersURL, nil)
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -193,10 +252,9 @@ func (s *LecturerService) fetchLecturersFromCampus(token string) ([]models.Campu
 
 	// Send request
 	client := &http.Client{Timeout: 15 * time.Second}
-	log.Printf("Sending request to campus API with token")
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Printf("Network error when fetching lecturers: %v", err)
+		slog.Warn("Network error when fetching lecturers", "error", err)
 		return nil, fmt.Errorf("network error when fetching lecturers: %w", err)
 	}
 	defer resp.Body.Close()
@@ -204,48 +262,47 @@ func (s *LecturerService) fetchLecturersFromCampus(token string) ([]models.Campu
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		log.Printf("Failed to fetch lecturers from campus API with status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
+		slog.Warn("Failed to fetch lecturers from campus API", "status", resp.StatusCode, "response", string(bodyBytes))
 		return nil, fmt.Errorf("failed to fetch lecturers from campus API with status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
 	}
 
 	// Read the response body
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("Error reading response body: %v", err)
+		slog.Warn("Error reading campus API response", "error", err)
 		return nil, fmt.Errorf("error reading response body: %w", err)
 	}
 
-	log.Printf("Received response from campus API, length: %d bytes", len(bodyBytes))
+	slog.Debug("Received response from campus API", "bytes", len(bodyBytes))
 	
 	// For debugging, log a small portion of the response
 	previewLen := 200
 	if len(bodyBytes) < previewLen {
 		previewLen = len(bodyBytes)
 	}
-	log.Printf("Response preview: %s", string(bodyBytes[:previewLen]))
+	slog.Debug("Campus API response preview", "response", string(bodyBytes[:previewLen]))
 
 	// Parse response
 	var campusResp models.CampusLecturerResponse
 	err = json.Unmarshal(bodyBytes, &campusResp)
 	if err != nil {
-		log.Printf("Failed to parse campus API response: %v, raw response length: %d", err, len(bodyBytes))
-		log.Printf("First 500 characters of response: %s", string(bodyBytes[:min(500, len(bodyBytes))]))
+		slog.Warn("Failed to parse campus API response", "bytes", len(bodyBytes), "response", string(bodyBytes[:min(500, len(bodyBytes))]), "error", err)
 		return nil, fmt.Errorf("failed to parse campus API response: %w, raw response: %s", err, string(bodyBytes))
 	}
 
 	// Check if result is OK
 	if campusResp.Result != "Ok" {
-		log.Printf("Campus API returned an error: %s", campusResp.Result)
+		slog.Warn("Campus API returned an error", "result", campusResp.Result)
 		return nil, fmt.Errorf("campus API returned an error: %s", campusResp.Result)
 	}
 
 	// Check if we have lecturers
 	if len(campusResp.Data.Lecturers) == 0 {
-		log.Printf("No lecturers found in campus API response")
+		slog.Warn("No lecturers found in campus API response")
 		return nil, errors.New("no lecturers found in campus API response")
 	}
 
-	log.Printf("Successfully fetched %d lecturers from campus API", len(campusResp.Data.Lecturers))
+	slog.Info("Fetched lecturers from campus API", "count", len(campusResp.Data.Lecturers))
 	return campusResp.Data.Lecturers, nil
 }
 
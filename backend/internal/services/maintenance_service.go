@@ -0,0 +1,140 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+// integritySampleSize is the number of affected IDs reported per integrity check
+const integritySampleSize = 10
+
+// MaintenanceService provides the operational tasks run by administrators outside the API
+type MaintenanceService struct {
+	attendanceRepo repositories.AttendanceStore
+	scheduleRepo   repositories.CourseScheduleStore
+	groupRepo      repositories.StudentGroupStore
+	integrityRepo  repositories.IntegrityStore
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService() *MaintenanceService {
+	return NewMaintenanceServiceWithStores(
+		repositories.NewAttendanceRepository(),
+		repositories.NewCourseScheduleRepository(),
+		repositories.NewStudentGroupRepository(),
+		repositories.NewIntegrityRepository(),
+	)
+}
+
+// NewMaintenanceServiceWithStores creates a new maintenance service on top of the given stores
+func NewMaintenanceServiceWithStores(
+	attendanceRepo repositories.AttendanceStore,
+	scheduleRepo repositories.CourseScheduleStore,
+	groupRepo repositories.StudentGroupStore,
+	integrityRepo repositories.IntegrityStore,
+) *MaintenanceService {
+	return &MaintenanceService{
+		attendanceRepo: attendanceRepo,
+		scheduleRepo:   scheduleRepo,
+		groupRepo:      groupRepo,
+		integrityRepo:  integrityRepo,
+	}
+}
+
+// FindStaleSessions returns active attendance sessions that started more than olderThan ago
+func (s *MaintenanceService) FindStaleSessions(olderThan time.Duration) ([]models.StaleSession, error) {
+	now := GetIndonesiaTime()
+	sessions, err := s.attendanceRepo.ListActiveSessionsStartedBefore(now.Add(-olderThan))
+	if err != nil {
+		return nil, err
+	}
+
+	stale := make([]models.StaleSession, 0, len(sessions))
+	for _, session := range sessions {
+		stale = append(stale, models.StaleSession{
+			ID:               session.ID,
+			CourseScheduleID: session.CourseScheduleID,
+			LecturerID:       session.LecturerID,
+			StartTime:        session.StartTime,
+			OpenHours:        math.Round(now.Sub(session.StartTime).Hours()*10) / 10,
+		})
+	}
+	return stale, nil
+}
+
+// CloseStaleSessions closes active attendance sessions that started more than olderThan ago
+// With dryRun the sessions are only returned
+func (s *MaintenanceService) CloseStaleSessions(olderThan time.Duration, dryRun bool) ([]models.StaleSession, error) {
+	stale, err := s.FindStaleSessions(olderThan)
+	if err != nil || dryRun || len(stale) == 0 {
+		return stale, err
+	}
+
+	ids := make([]uint, 0, len(stale))
+	for _, session := range stale {
+		ids = append(ids, session.ID)
+	}
+
+	if err := s.attendanceRepo.CloseSessions(ids, GetIndonesiaTime()); err != nil {
+		return nil, err
+	}
+	return stale, nil
+}
+
+// RecomputeEnrolled compares the cached enrolled count of every course schedule with the size
+// of its student group and corrects the ones that differ. With dryRun nothing is saved.
+func (s *MaintenanceService) RecomputeEnrolled(dryRun bool) ([]models.EnrollmentFix, error) {
+	counts, err := s.groupRepo.CountMembersByGroup()
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.scheduleRepo.GetEnrolledCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	fixes := []models.EnrollmentFix{}
+	for _, schedule := range schedules {
+		actual := counts[schedule.StudentGroupID]
+		if schedule.Enrolled == actual {
+			continue
+		}
+		fixes = append(fixes, models.EnrollmentFix{
+			CourseScheduleID: schedule.ID,
+			StudentGroupID:   schedule.StudentGroupID,
+			Stored:           schedule.Enrolled,
+			Actual:           actual,
+		})
+	}
+	if dryRun || len(fixes) == 0 {
+		return fixes, nil
+	}
+
+	enrolled := make(map[uint]int, len(fixes))
+	for _, fix := range fixes {
+		enrolled[fix.CourseScheduleID] = fix.Actual
+	}
+	if err := s.scheduleRepo.UpdateEnrolledCounts(enrolled); err != nil {
+		return nil, err
+	}
+	return fixes, nil
+}
+
+// CheckIntegrity runs the data integrity checks and returns the ones that found problems
+func (s *MaintenanceService) CheckIntegrity() ([]models.IntegrityIssue, error) {
+	issues, err := s.integrityRepo.FindViolations(GetIndonesiaTime().Add(-24 * time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range issues {
+		if len(issues[i].SampleIDs) > integritySampleSize {
+			issues[i].SampleIDs = issues[i].SampleIDs[:integritySampleSize]
+		}
+	}
+	return issues, nil
+}
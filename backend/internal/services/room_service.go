@@ -2,23 +2,46 @@ package services
 
 import (
 	"errors"
-	"fmt"
+	"log/slog"
 
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/repositories"
 )
 
+var (
+	// ErrRoomNotFound is returned for rooms that do not exist
+	ErrRoomNotFound = errors.New("room not found")
+
+	// ErrRoomCodeExists is returned when another room has the code
+	ErrRoomCodeExists = errors.New("room code is already in use")
+)
+
 // RoomService is a service for room operations
 type RoomService struct {
-	repository       *repositories.RoomRepository
-	buildingRepository *repositories.BuildingRepository
+	repository         repositories.RoomStore
+	buildingRepository repositories.BuildingStore
+	scheduleRepo       repositories.CourseScheduleStore
 }
 
 // NewRoomService creates a new room service
 func NewRoomService() *RoomService {
+	return NewRoomServiceWithStores(
+		repositories.NewRoomRepository(),
+		repositories.NewBuildingRepository(),
+		repositories.NewCourseScheduleRepository(),
+	)
+}
+
+// NewRoomServiceWithStores creates a new room service on top of the given stores
+func NewRoomServiceWithStores(
+	repository repositories.RoomStore,
+	buildingRepository repositories.BuildingStore,
+	scheduleRepo repositories.CourseScheduleStore,
+) *RoomService {
 	return &RoomService{
-		repository:       repositories.NewRoomRepository(),
-		buildingRepository: repositories.NewBuildingRepository(),
+		repository:         repository,
+		buildingRepository: buildingRepository,
+		scheduleRepo:       scheduleRepo,
 	}
 }
 
@@ -53,7 +76,7 @@ func (s *RoomService) CreateRoom(room *models.Room) error {
 			return s.repository.Update(restoredRoom)
 		}
 		
-		return errors.New("kode ruangan sudah digunakan")
+		return ErrRoomCodeExists
 	}
 
 	// Check if building exists
@@ -62,7 +85,7 @@ func (s *RoomService) CreateRoom(room *models.Room) error {
 		return err
 	}
 	if building == nil {
-		return errors.New("gedung tidak ditemukan")
+		return ErrBuildingNotFound
 	}
 
 	// Create room
@@ -77,7 +100,7 @@ func (s *RoomService) UpdateRoom(room *models.Room) error {
 		return err
 	}
 	if existingRoom == nil {
-		return errors.New("ruangan tidak ditemukan")
+		return ErrRoomNotFound
 	}
 
 	// If code is changed, check if new code already exists
@@ -87,7 +110,7 @@ func (s *RoomService) UpdateRoom(room *models.Room) error {
 			return err
 		}
 		if exists {
-			return errors.New("kode ruangan sudah digunakan")
+			return ErrRoomCodeExists
 		}
 	}
 
@@ -97,7 +120,7 @@ func (s *RoomService) UpdateRoom(room *models.Room) error {
 		return err
 	}
 	if building == nil {
-		return errors.New("gedung tidak ditemukan")
+		return ErrBuildingNotFound
 	}
 
 	// Check if capacity has changed
@@ -110,12 +133,11 @@ func (s *RoomService) UpdateRoom(room *models.Room) error {
 	
 	// If capacity changed, update all course schedules that use this room
 	if capacityChanged {
-		scheduleRepo := repositories.NewCourseScheduleRepository()
-		if err := scheduleRepo.UpdateSchedulesForRoom(room.ID, room.Capacity); err != nil {
+		if err := s.scheduleRepo.UpdateSchedulesForRoom(room.ID, room.Capacity); err != nil {
 			// Log error but don't fail the operation
 			// This is to prevent updates to rooms from failing due to schedule update issues
 			// The schedules will eventually be updated when they're accessed
-			fmt.Printf("Error updating course schedules for room ID %d: %v\n", room.ID, err)
+			slog.Error("Error updating course schedules of room", "room_id", room.ID, "error", err)
 		}
 	}
 	
@@ -127,9 +149,9 @@ func (s *RoomService) GetRoomByID(id uint) (*models.Room, error) {
 	return s.repository.FindByID(id)
 }
 
-// GetAllRooms gets all rooms
-func (s *RoomService) GetAllRooms() ([]models.Room, error) {
-	return s.repository.FindAll()
+// ListRooms returns one page of rooms
+func (s *RoomService) ListRooms(q models.ListQuery) (models.ListResult[models.Room], error) {
+	return s.repository.List(q)
 }
 
 // GetRoomsByBuildingID gets all rooms by building ID
@@ -140,7 +162,7 @@ func (s *RoomService) GetRoomsByBuildingID(buildingID uint) ([]models.Room, erro
 		return nil, err
 	}
 	if building == nil {
-		return nil, errors.New("gedung tidak ditemukan")
+		return nil, ErrBuildingNotFound
 	}
 
 	return s.repository.FindByBuildingID(buildingID)
@@ -154,7 +176,7 @@ func (s *RoomService) DeleteRoom(id uint) error {
 		return err
 	}
 	if room == nil {
-		return errors.New("ruangan tidak ditemukan")
+		return ErrRoomNotFound
 	}
 
 	// Delete room (soft delete)
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+var (
+	// ErrScheduleConflictNotFound is returned when reviewing two schedules that do not conflict
+	ErrScheduleConflictNotFound = errors.New("schedule conflict not found")
+
+	// ErrInvalidScheduleSlot is returned for slots whose times are not HH:MM or that end before they start
+	ErrInvalidScheduleSlot = errors.New("start and end time must be HH:MM and the end must be after the start")
+)
+
+// ScheduleConflictService finds the schedules of an academic year that use the same room, lecturer
+// or student group at overlapping times, and keeps the status admins give to each conflict
+type ScheduleConflictService struct {
+	schedules     repositories.CourseScheduleStore
+	academicYears repositories.AcademicYearStore
+	lecturers     repositories.LecturerStore
+	reviews       repositories.ScheduleConflictReviewStore
+}
+
+// NewScheduleConflictService creates a new schedule conflict service
+func NewScheduleConflictService() *ScheduleConflictService {
+	return NewScheduleConflictServiceWithStores(
+		repositories.NewCourseScheduleRepository(),
+		repositories.NewAcademicYearRepository(),
+		repositories.NewLecturerRepository(),
+		repositories.NewScheduleConflictReviewRepository(),
+	)
+}
+
+// NewScheduleConflictServiceWithStores creates a new schedule conflict service on top of the given stores
+func NewScheduleConflictServiceWithStores(
+	schedules repositories.CourseScheduleStore,
+	academicYears repositories.AcademicYearStore,
+	lecturers repositories.LecturerStore,
+	reviews repositories.ScheduleConflictReviewStore,
+) *ScheduleConflictService {
+	return &ScheduleConflictService{
+		schedules:     schedules,
+		academicYears: academicYears,
+		lecturers:     lecturers,
+		reviews:       reviews,
+	}
+}
+
+// scheduleSlot is a schedule with its day and times in a comparable form
+type scheduleSlot struct {
+	schedule models.CourseSchedule
+	day      string // Lower case, the campus data mixes "Senin" and "senin"
+	start    int    // Minutes since midnight
+	end      int
+}
+
+// conflictResources are the resources a schedule holds, in the order conflicts are reported
+var conflictResources = []struct {
+	conflictType models.ScheduleConflictType
+	id           func(models.CourseSchedule) uint
+}{
+	{models.ScheduleConflictRoom, func(schedule models.CourseSchedule) uint { return schedule.RoomID }},
+	{models.ScheduleConflictLecturer, func(schedule models.CourseSchedule) uint { return schedule.UserID }},
+	{models.ScheduleConflictStudentGroup, func(schedule models.CourseSchedule) uint { return schedule.StudentGroupID }},
+}
+
+// Report lists every conflict between the schedules of an academic year with its status
+func (s *ScheduleConflictService) Report(ctx context.Context, academicYearID uint) (*models.ScheduleConflictReport, error) {
+	academicYear, err := s.academicYears.FindByID(academicYearID)
+	if err != nil {
+		return nil, err
+	}
+	if academicYear == nil {
+		return nil, ErrAcademicYearNotFound
+	}
+
+	schedules, err := s.schedules.WithContext(ctx).GetByAcademicYear(academicYearID)
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := s.reviewed(academicYearID, s.findConflicts(toScheduleSlots(schedules)))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ScheduleConflictReport{
+		AcademicYearID:   academicYear.ID,
+		AcademicYearName: academicYear.Name,
+		ScheduleCount:    len(schedules),
+		Conflicts:        conflicts,
+	}
+	for _, conflict := range conflicts {
+		if conflict.Status == models.ScheduleConflictAcknowledged {
+			report.AcknowledgedCount++
+		} else {
+			report.BlockingCount++
+		}
+	}
+	return report, nil
+}
+
+// SetStatus marks the conflict between two schedules blocking or acknowledged
+// The schedules must currently conflict on the resource, the status is kept while they overlap on the same slot
+func (s *ScheduleConflictService) SetStatus(ctx context.Context, request models.ScheduleConflictStatusRequest, reviewedBy string) (*models.ScheduleConflict, error) {
+	if len(request.ScheduleIDs) != 2 {
+		return nil, ErrScheduleConflictNotFound
+	}
+	report, err := s.Report(ctx, request.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+
+	first, second := orderedPair(request.ScheduleIDs[0], request.ScheduleIDs[1])
+	for _, conflict := range report.Conflicts {
+		if conflict.Type != request.Type || conflict.ScheduleIDs[0] != first || conflict.ScheduleIDs[1] != second {
+			continue
+		}
+
+		review := &models.ScheduleConflictReview{
+			AcademicYearID:  request.AcademicYearID,
+			Type:            request.Type,
+			ScheduleID:      first,
+			OtherScheduleID: second,
+			Slot:            conflictSlot(conflict),
+			Status:          request.Status,
+			Note:            request.Note,
+			ReviewedBy:      reviewedBy,
+		}
+		if err := s.reviews.Save(review); err != nil {
+			return nil, err
+		}
+
+		reviewedAt := time.Now()
+		conflict.Status = request.Status
+		conflict.Note = request.Note
+		conflict.ReviewedBy = reviewedBy
+		conflict.ReviewedAt = &reviewedAt
+		return &conflict, nil
+	}
+	return nil, ErrScheduleConflictNotFound
+}
+
+// Check lists the conflicts a proposed slot would have with the other schedules of its academic year
+func (s *ScheduleConflictService) Check(ctx context.Context, request models.ScheduleConflictCheckRequest) (*models.ScheduleConflictCheck, error) {
+	start, startOK := clockMinutes(request.StartTime)
+	end, endOK := clockMinutes(request.EndTime)
+	if !startOK || !endOK || end <= start {
+		return nil, ErrInvalidScheduleSlot
+	}
+
+	schedules, err := s.schedules.WithContext(ctx).GetByAcademicYear(request.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal := scheduleSlot{
+		schedule: models.CourseSchedule{
+			RoomID:         request.RoomID,
+			UserID:         request.LecturerID,
+			StudentGroupID: request.StudentGroupID,
+			AcademicYearID: request.AcademicYearID,
+			Day:            request.Day,
+			StartTime:      request.StartTime,
+			EndTime:        request.EndTime,
+		},
+		day:   strings.ToLower(strings.TrimSpace(request.Day)),
+		start: start,
+		end:   end,
+	}
+	if request.ScheduleID != nil {
+		proposal.schedule.ID = *request.ScheduleID
+	}
+
+	// Only the pairs with the proposal are of interest, so it is compared with each schedule alone
+	lecturerNames := make(map[uint]string)
+	found := []models.ScheduleConflict{}
+	for _, slot := range toScheduleSlots(schedules) {
+		if request.ScheduleID != nil && slot.schedule.ID == *request.ScheduleID {
+			continue
+		}
+		if slot.day != proposal.day || slot.start >= proposal.end || proposal.start >= slot.end {
+			continue
+		}
+		for _, resource := range conflictResources {
+			id := resource.id(proposal.schedule)
+			if id == 0 || resource.id(slot.schedule) != id {
+				continue
+			}
+			conflict := newScheduleConflict(resource.conflictType, id, proposal, slot)
+			conflict.ResourceName = s.resourceName(resource.conflictType, slot.schedule, lecturerNames)
+			found = append(found, conflict)
+		}
+	}
+	conflicts, err := s.reviewed(request.AcademicYearID, found)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &models.ScheduleConflictCheck{Conflicts: conflicts}
+	for _, conflict := range conflicts {
+		if conflict.Status == models.ScheduleConflictBlocking {
+			check.HasBlockingConflict = true
+		}
+	}
+	return check, nil
+}
+
+// findConflicts returns every pair of slots that overlap on a resource, per resource, day and start time
+func (s *ScheduleConflictService) findConflicts(slots []scheduleSlot) []models.ScheduleConflict {
+	conflicts := []models.ScheduleConflict{}
+	lecturerNames := make(map[uint]string)
+
+	for _, resource := range conflictResources {
+		// Group the slots by resource and day, so only slots that can overlap are compared
+		type groupKey struct {
+			id  uint
+			day string
+		}
+		groups := make(map[groupKey][]scheduleSlot)
+		var keys []groupKey
+		for _, slot := range slots {
+			key := groupKey{resource.id(slot.schedule), slot.day}
+			if key.id == 0 {
+				continue
+			}
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], slot)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].id != keys[j].id {
+				return keys[i].id < keys[j].id
+			}
+			return dayOrder(keys[i].day) < dayOrder(keys[j].day)
+		})
+
+		for _, key := range keys {
+			group := groups[key]
+			sort.SliceStable(group, func(i, j int) bool { return group[i].start < group[j].start })
+			for i := range group {
+				// Sorted by start, so the slots after the first one starting at or after its end never overlap it
+				for j := i + 1; j < len(group) && group[j].start < group[i].end; j++ {
+					conflict := newScheduleConflict(resource.conflictType, key.id, group[i], group[j])
+					conflict.ResourceName = s.resourceName(resource.conflictType, group[i].schedule, lecturerNames)
+					conflicts = append(conflicts, conflict)
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// reviewed sets the status of conflicts from their reviews, conflicts without a review that still applies are blocking
+func (s *ScheduleConflictService) reviewed(academicYearID uint, conflicts []models.ScheduleConflict) ([]models.ScheduleConflict, error) {
+	reviews, err := s.reviews.FindByAcademicYear(academicYearID)
+	if err != nil {
+		return nil, err
+	}
+
+	type pairKey struct {
+		conflictType models.ScheduleConflictType
+		first        uint
+		second       uint
+	}
+	byPair := make(map[pairKey]models.ScheduleConflictReview, len(reviews))
+	for _, review := range reviews {
+		byPair[pairKey{review.Type, review.ScheduleID, review.OtherScheduleID}] = review
+	}
+
+	for i := range conflicts {
+		conflict := &conflicts[i]
+		conflict.Status = models.ScheduleConflictBlocking
+		if len(conflict.ScheduleIDs) != 2 {
+			continue
+		}
+		review, ok := byPair[pairKey{conflict.Type, conflict.ScheduleIDs[0], conflict.ScheduleIDs[1]}]
+		if !ok || review.Slot != conflictSlot(*conflict) {
+			continue
+		}
+		reviewedAt := review.UpdatedAt
+		conflict.Status = review.Status
+		conflict.Note = review.Note
+		conflict.ReviewedBy = review.ReviewedBy
+		conflict.ReviewedAt = &reviewedAt
+	}
+	return conflicts, nil
+}
+
+// resourceName returns the name of the room, lecturer or student group of a schedule
+// Lecturer names are looked up once per lecturer and kept in names
+func (s *ScheduleConflictService) resourceName(conflictType models.ScheduleConflictType, schedule models.CourseSchedule, names map[uint]string) string {
+	switch conflictType {
+	case models.ScheduleConflictRoom:
+		return schedule.Room.Name
+	case models.ScheduleConflictStudentGroup:
+		return schedule.StudentGroup.Name
+	}
+
+	if name, ok := names[schedule.UserID]; ok {
+		return name
+	}
+	name := schedule.Lecturer.Username
+	if schedule.Lecturer.ExternalUserID != nil {
+		lecturer, err := s.lecturers.GetByUserID(*schedule.Lecturer.ExternalUserID)
+		if err == nil && lecturer.FullName != "" {
+			name = lecturer.FullName
+		}
+	}
+	names[schedule.UserID] = name
+	return name
+}
+
+// newScheduleConflict describes the overlap of two slots on a resource
+// Slots without an ID, a proposed new schedule, are left out of ScheduleIDs
+func newScheduleConflict(conflictType models.ScheduleConflictType, resourceID uint, a, b scheduleSlot) models.ScheduleConflict {
+	if b.schedule.ID < a.schedule.ID {
+		a, b = b, a
+	}
+	start, end := max(a.start, b.start), min(a.end, b.end)
+
+	conflict := models.ScheduleConflict{
+		Type:           conflictType,
+		ResourceID:     resourceID,
+		Day:            a.schedule.Day,
+		Schedules:      []models.ScheduleConflictSchedule{conflictSchedule(a.schedule), conflictSchedule(b.schedule)},
+		OverlapStart:   formatClock(start),
+		OverlapEnd:     formatClock(end),
+		OverlapMinutes: end - start,
+	}
+	for _, slot := range []scheduleSlot{a, b} {
+		if slot.schedule.ID != 0 {
+			conflict.ScheduleIDs = append(conflict.ScheduleIDs, slot.schedule.ID)
+		}
+	}
+	return conflict
+}
+
+// conflictSchedule describes one schedule of a conflict
+func conflictSchedule(schedule models.CourseSchedule) models.ScheduleConflictSchedule {
+	return models.ScheduleConflictSchedule{
+		ID:         schedule.ID,
+		CourseCode: schedule.Course.Code,
+		CourseName: schedule.Course.Name,
+		StartTime:  schedule.StartTime,
+		EndTime:    schedule.EndTime,
+	}
+}
+
+// toScheduleSlots converts schedules to slots, schedules with unreadable times are skipped
+func toScheduleSlots(schedules []models.CourseSchedule) []scheduleSlot {
+	slots := make([]scheduleSlot, 0, len(schedules))
+	for _, schedule := range schedules {
+		start, startOK := clockMinutes(schedule.StartTime)
+		end, endOK := clockMinutes(schedule.EndTime)
+		if !startOK || !endOK || end <= start {
+			continue
+		}
+		slots = append(slots, scheduleSlot{
+			schedule: schedule,
+			day:      strings.ToLower(strings.TrimSpace(schedule.Day)),
+			start:    start,
+			end:      end,
+		})
+	}
+	return slots
+}
+
+// conflictSlot identifies the overlap of a conflict, a review applies only while it is unchanged
+func conflictSlot(conflict models.ScheduleConflict) string {
+	return fmt.Sprintf("%s %s-%s", strings.ToLower(conflict.Day), conflict.OverlapStart, conflict.OverlapEnd)
+}
+
+// orderedPair returns two schedule IDs with the lower one first
+func orderedPair(a, b uint) (uint, uint) {
+	if b < a {
+		return b, a
+	}
+	return a, b
+}
+
+// clockMinutes parses an HH:MM or HH:MM:SS time of day into minutes since midnight
+func clockMinutes(value string) (int, bool) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, false
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
+// formatClock formats minutes since midnight as HH:MM
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// weekDays are the lower case Indonesian day names in week order
+var weekDays = []string{"senin", "selasa", "rabu", "kamis", "jumat", "sabtu", "minggu"}
+
+// dayOrder returns the position of a lower case day name in weekDays, len(weekDays) for unknown days
+func dayOrder(day string) int {
+	for i, name := range weekDays {
+		if day == name {
+			return i
+		}
+	}
+	return len(weekDays)
+}
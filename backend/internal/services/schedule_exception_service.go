@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrScheduleExceptionNotFound is returned for schedule exceptions that do not exist
+	ErrScheduleExceptionNotFound = errors.New("schedule exception not found")
+
+	// ErrInvalidScheduleException is returned for exceptions on a date the schedule has no class, or with an invalid replacement
+	ErrInvalidScheduleException = errors.New("invalid schedule exception")
+
+	// ErrScheduleExceptionConflict is returned when the room, lecturer or student group is busy during a replacement session
+	ErrScheduleExceptionConflict = errors.New("replacement session conflicts with another class")
+)
+
+// ScheduleExceptionService cancels single classes of a schedule or moves them to a one-off replacement session
+type ScheduleExceptionService struct {
+	exceptions repositories.ScheduleExceptionStore
+	schedules  repositories.CourseScheduleStore
+	rooms      repositories.RoomStore
+	holidays   repositories.HolidayStore
+}
+
+// NewScheduleExceptionService creates a new schedule exception service
+func NewScheduleExceptionService() *ScheduleExceptionService {
+	return NewScheduleExceptionServiceWithStores(
+		repositories.NewScheduleExceptionRepository(),
+		repositories.NewCourseScheduleRepository(),
+		repositories.NewRoomRepository(),
+		repositories.NewHolidayRepository(),
+	)
+}
+
+// NewScheduleExceptionServiceWithStores creates a new schedule exception service on top of the given stores
+func NewScheduleExceptionServiceWithStores(
+	exceptions repositories.ScheduleExceptionStore,
+	schedules repositories.CourseScheduleStore,
+	rooms repositories.RoomStore,
+	holidays repositories.HolidayStore,
+) *ScheduleExceptionService {
+	return &ScheduleExceptionService{
+		exceptions: exceptions,
+		schedules:  schedules,
+		rooms:      rooms,
+		holidays:   holidays,
+	}
+}
+
+// List returns the exceptions of a schedule by date
+func (s *ScheduleExceptionService) List(scheduleID uint) ([]models.ScheduleException, error) {
+	if _, err := s.schedules.GetByID(scheduleID); err != nil {
+		return nil, err
+	}
+	return s.exceptions.FindBySchedules([]uint{scheduleID})
+}
+
+// Save cancels or moves the class of a schedule on a date, replacing an earlier exception for that date
+// A replacement session must not fall on a holiday or clash with another class of its room, lecturer
+// or student group on that date.
+func (s *ScheduleExceptionService) Save(ctx context.Context, scheduleID uint, request models.ScheduleExceptionRequest, createdBy string) (*models.ScheduleException, error) {
+	schedule, err := s.schedules.WithContext(ctx).GetByID(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	weekday, ok := scheduleWeekday(schedule.Day)
+	date, dateErr := time.Parse(dateLayout, request.Date)
+	if !ok || dateErr != nil || date.Weekday() != weekday || !inAcademicYear(schedule.AcademicYear, date) {
+		return nil, fmt.Errorf("%w: date must be a YYYY-MM-DD %s in the academic year of the schedule", ErrInvalidScheduleException, schedule.Day)
+	}
+
+	exception := &models.ScheduleException{
+		CourseScheduleID: schedule.ID,
+		Date:             request.Date,
+		Kind:             request.Kind,
+		Note:             request.Note,
+		CreatedBy:        createdBy,
+	}
+	if request.Kind == models.ScheduleExceptionCancelled {
+		if err := s.exceptions.Save(exception); err != nil {
+			return nil, err
+		}
+		return exception, nil
+	}
+
+	newDate, err := time.Parse(dateLayout, request.NewDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: new_date must be YYYY-MM-DD for rescheduled classes", ErrInvalidScheduleException)
+	}
+	if request.NewStartTime == "" {
+		request.NewStartTime = schedule.StartTime
+	}
+	if request.NewEndTime == "" {
+		request.NewEndTime = schedule.EndTime
+	}
+	start, startOK := clockMinutes(request.NewStartTime)
+	end, endOK := clockMinutes(request.NewEndTime)
+	if !startOK || !endOK || end <= start {
+		return nil, fmt.Errorf("%w: new_start_time and new_end_time must be HH:MM with new_end_time after new_start_time", ErrInvalidScheduleException)
+	}
+	roomID := schedule.RoomID
+	if request.NewRoomID != nil {
+		roomID = *request.NewRoomID
+	}
+	room, err := s.rooms.FindByID(roomID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRoomNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.holidays.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	if holiday := holidayOn(holidays, request.NewDate); holiday != nil {
+		return nil, fmt.Errorf("%w: new_date is a holiday (%s)", ErrInvalidScheduleException, holiday.Name)
+	}
+
+	exception.NewDate = request.NewDate
+	exception.NewStartTime = formatClock(start)
+	exception.NewEndTime = formatClock(end)
+	exception.NewRoomID = &room.ID
+
+	replacement := schedule
+	replacement.RoomID = room.ID
+	if err := s.checkReplacement(ctx, replacement, exception, newDate, start, end); err != nil {
+		return nil, err
+	}
+
+	if err := s.exceptions.Save(exception); err != nil {
+		return nil, err
+	}
+	return exception, nil
+}
+
+// checkReplacement looks for classes of the room, lecturer or student group of a replacement session
+// on its date: regular classes of the academic year that are not cancelled or moved that day, and
+// other replacement sessions
+func (s *ScheduleExceptionService) checkReplacement(ctx context.Context, replacement models.CourseSchedule, exception *models.ScheduleException, newDate time.Time, start, end int) error {
+	schedules, err := s.schedules.WithContext(ctx).GetByAcademicYear(replacement.AcademicYearID)
+	if err != nil {
+		return err
+	}
+	onDate, err := s.exceptions.FindOnDate(exception.NewDate)
+	if err != nil {
+		return err
+	}
+
+	// Regular classes that do not take place on the date
+	away := make(map[uint]bool)
+	for _, other := range onDate {
+		if other.Date == exception.NewDate {
+			away[other.CourseScheduleID] = true
+		}
+	}
+	if exception.Date == exception.NewDate {
+		away[exception.CourseScheduleID] = true
+	}
+
+	byID := make(map[uint]models.CourseSchedule, len(schedules))
+	var busy []models.CourseSchedule
+	for _, schedule := range schedules {
+		byID[schedule.ID] = schedule
+		if weekday, ok := scheduleWeekday(schedule.Day); ok && weekday == newDate.Weekday() && !away[schedule.ID] {
+			busy = append(busy, schedule)
+		}
+	}
+	slots := toScheduleSlots(busy)
+
+	// Replacement sessions of other classes on the date
+	for _, other := range onDate {
+		if other.Kind != models.ScheduleExceptionRescheduled || other.NewDate != exception.NewDate ||
+			(other.CourseScheduleID == exception.CourseScheduleID && other.Date == exception.Date) {
+			continue
+		}
+		schedule, ok := byID[other.CourseScheduleID]
+		if !ok {
+			continue
+		}
+		otherStart, _ := clockMinutes(other.NewStartTime)
+		otherEnd, _ := clockMinutes(other.NewEndTime)
+		if other.NewRoomID != nil {
+			schedule.RoomID = *other.NewRoomID
+		}
+		slots = append(slots, scheduleSlot{schedule: schedule, start: otherStart, end: otherEnd})
+	}
+
+	// All slots are on the same date, so the weekday is the day of the replacement
+	day := weekDays[(int(newDate.Weekday())+6)%7]
+	for i := range slots {
+		slots[i].day = day
+	}
+	occupancy := newScheduleOccupancy(slots)
+	for _, resource := range conflictResources {
+		if occupancy.busy(resource.conflictType, resource.id(replacement), day, start, end) {
+			return fmt.Errorf("%w: the %s is busy on %s %s-%s", ErrScheduleExceptionConflict,
+				strings.ReplaceAll(string(resource.conflictType), "_", " "), exception.NewDate, formatClock(start), formatClock(end))
+		}
+	}
+	return nil
+}
+
+// Delete removes an exception, the class then takes place as scheduled again
+func (s *ScheduleExceptionService) Delete(id uint) error {
+	exception, err := s.exceptions.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if exception == nil {
+		return ErrScheduleExceptionNotFound
+	}
+	return s.exceptions.Delete(id)
+}
+
+// scheduleWeekday returns the weekday of an Indonesian day name
+func scheduleWeekday(day string) (time.Weekday, bool) {
+	order := dayOrder(strings.ToLower(strings.TrimSpace(day)))
+	if order == len(weekDays) {
+		return 0, false
+	}
+	// weekDays starts on Senin, time.Weekday on Sunday
+	return time.Weekday((order + 1) % 7), true
+}
+
+// inAcademicYear reports whether a date lies between the start and end date of an academic year
+func inAcademicYear(academicYear models.AcademicYear, date time.Time) bool {
+	first, last := academicYearDates(academicYear)
+	key := date.Format(dateLayout)
+	return first <= key && key <= last
+}
+
+// academicYearDates returns the first and last date of an academic year as YYYY-MM-DD, in campus time
+func academicYearDates(academicYear models.AcademicYear) (string, string) {
+	location := getIndonesiaLocation()
+	return academicYear.StartDate.In(location).Format(dateLayout), academicYear.EndDate.In(location).Format(dateLayout)
+}
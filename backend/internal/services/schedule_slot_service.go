@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+const (
+	// slotSearchStep is the interval between the start times tried by the free slot search
+	slotSearchStep = 30
+
+	// minutesPerCredit is the length of a class per credit (SKS) of its course
+	minutesPerCredit = 50
+
+	defaultSlotSearchLimit = 20
+	maxSlotSearchLimit     = 100
+)
+
+var (
+	// ErrCourseNotFound is returned for courses that do not exist
+	ErrCourseNotFound = errors.New("course not found")
+
+	// ErrInvalidSlotSearch is returned for free slot searches with invalid constraints
+	ErrInvalidSlotSearch = errors.New("invalid free slot search")
+)
+
+// defaultSlotSearchDays are the days searched when a search names none
+var defaultSlotSearchDays = []string{"Senin", "Selasa", "Rabu", "Kamis", "Jumat"}
+
+// ScheduleSlotService finds slots and rooms for a new schedule where the lecturer, the student group
+// and the room are all free, from the same occupancy of the academic year as the conflict report
+type ScheduleSlotService struct {
+	schedules     repositories.CourseScheduleStore
+	academicYears repositories.AcademicYearStore
+	courses       repositories.CourseStore
+	studentGroups repositories.StudentGroupStore
+	rooms         repositories.RoomStore
+	availability  repositories.LecturerAvailabilityStore
+}
+
+// NewScheduleSlotService creates a new schedule slot service
+func NewScheduleSlotService() *ScheduleSlotService {
+	return NewScheduleSlotServiceWithStores(
+		repositories.NewCourseScheduleRepository(),
+		repositories.NewAcademicYearRepository(),
+		repositories.NewCourseRepository(),
+		repositories.NewStudentGroupRepository(),
+		repositories.NewRoomRepository(),
+		repositories.NewLecturerAvailabilityRepository(),
+	)
+}
+
+// NewScheduleSlotServiceWithStores creates a new schedule slot service on top of the given stores
+func NewScheduleSlotServiceWithStores(
+	schedules repositories.CourseScheduleStore,
+	academicYears repositories.AcademicYearStore,
+	courses repositories.CourseStore,
+	studentGroups repositories.StudentGroupStore,
+	rooms repositories.RoomStore,
+	availability repositories.LecturerAvailabilityStore,
+) *ScheduleSlotService {
+	return &ScheduleSlotService{
+		schedules:     schedules,
+		academicYears: academicYears,
+		courses:       courses,
+		studentGroups: studentGroups,
+		rooms:         rooms,
+		availability:  availability,
+	}
+}
+
+// scheduleOccupancy indexes the slots of an academic year by resource and day
+type scheduleOccupancy map[occupancyKey][]scheduleSlot
+
+type occupancyKey struct {
+	conflictType models.ScheduleConflictType
+	id           uint
+	day          string
+}
+
+// newScheduleOccupancy indexes slots by the room, lecturer and student group they hold
+func newScheduleOccupancy(slots []scheduleSlot) scheduleOccupancy {
+	occupancy := make(scheduleOccupancy)
+	for _, slot := range slots {
+		for _, resource := range conflictResources {
+			key := occupancyKey{resource.conflictType, resource.id(slot.schedule), slot.day}
+			occupancy[key] = append(occupancy[key], slot)
+		}
+	}
+	return occupancy
+}
+
+// busy reports whether a resource holds a slot overlapping start to end on a day
+func (o scheduleOccupancy) busy(conflictType models.ScheduleConflictType, id uint, day string, start, end int) bool {
+	for _, slot := range o[occupancyKey{conflictType, id, day}] {
+		if slot.start < end && start < slot.end {
+			return true
+		}
+	}
+	return false
+}
+
+// lecturerWindows indexes the availability windows of one kind as lecturer slots
+func lecturerWindows(windows []models.LecturerAvailability, kind models.LecturerAvailabilityKind) scheduleOccupancy {
+	occupancy := make(scheduleOccupancy)
+	for _, window := range windows {
+		start, startOK := clockMinutes(window.StartTime)
+		end, endOK := clockMinutes(window.EndTime)
+		if window.Kind != kind || !startOK || !endOK || end <= start {
+			continue
+		}
+		day := strings.ToLower(strings.TrimSpace(window.Day))
+		key := occupancyKey{models.ScheduleConflictLecturer, window.UserID, day}
+		occupancy[key] = append(occupancy[key], scheduleSlot{
+			schedule: models.CourseSchedule{UserID: window.UserID, Day: window.Day, StartTime: window.StartTime, EndTime: window.EndTime},
+			day:      day,
+			start:    start,
+			end:      end,
+		})
+	}
+	return occupancy
+}
+
+// count returns the number of slots a resource holds on a day
+func (o scheduleOccupancy) count(conflictType models.ScheduleConflictType, id uint, day string) int {
+	return len(o[occupancyKey{conflictType, id, day}])
+}
+
+// FindFreeSlots returns the slot and room combinations of an academic year where the lecturer, the
+// student group and a large enough room are free for the whole duration, outside the windows the
+// lecturer is unavailable. Options are ranked by the fewest classes the group and then the lecturer
+// already have that day, spreading the week, then by the room closest to the needed capacity, then
+// by day and start time.
+func (s *ScheduleSlotService) FindFreeSlots(ctx context.Context, request models.ScheduleSlotSearchRequest) (*models.ScheduleSlotSearchResult, error) {
+	academicYear, err := s.academicYears.FindByID(request.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+	if academicYear == nil {
+		return nil, ErrAcademicYearNotFound
+	}
+	course, err := s.courses.FindByID(request.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+	group, err := s.studentGroups.GetByID(request.StudentGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := request.DurationMinutes
+	if duration == 0 {
+		duration = course.Credits * minutesPerCredit
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("%w: duration_minutes must be positive", ErrInvalidSlotSearch)
+	}
+	minCapacity := request.MinCapacity
+	if minCapacity == 0 {
+		minCapacity = group.StudentCount
+	}
+	if minCapacity < 0 {
+		return nil, fmt.Errorf("%w: min_capacity must not be negative", ErrInvalidSlotSearch)
+	}
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultSlotSearchLimit
+	}
+	if limit < 0 || limit > maxSlotSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidSlotSearch, maxSlotSearchLimit)
+	}
+
+	earliest, latest, err := parseSlotWindow(request.EarliestStart, request.LatestEnd)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSlotSearch, err)
+	}
+	days, err := parseSlotDays(request.Days)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSlotSearch, err)
+	}
+
+	rooms, err := s.rooms.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	rooms = fittingRooms(rooms, minCapacity, request.BuildingID)
+
+	schedules, err := s.schedules.WithContext(ctx).GetByAcademicYear(request.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+	occupancy := newScheduleOccupancy(toScheduleSlots(schedules))
+	windows, err := s.availability.FindByLecturer(request.LecturerID)
+	if err != nil {
+		return nil, err
+	}
+	unavailable := lecturerWindows(windows, models.LecturerUnavailable)
+
+	options := []models.ScheduleSlotOption{}
+	for _, day := range days {
+		key := strings.ToLower(strings.TrimSpace(day))
+		groupClasses := occupancy.count(models.ScheduleConflictStudentGroup, request.StudentGroupID, key)
+		lecturerClasses := occupancy.count(models.ScheduleConflictLecturer, request.LecturerID, key)
+
+		for start := earliest; start+duration <= latest; start += slotSearchStep {
+			end := start + duration
+			if occupancy.busy(models.ScheduleConflictLecturer, request.LecturerID, key, start, end) ||
+				unavailable.busy(models.ScheduleConflictLecturer, request.LecturerID, key, start, end) ||
+				occupancy.busy(models.ScheduleConflictStudentGroup, request.StudentGroupID, key, start, end) {
+				continue
+			}
+			for _, room := range rooms {
+				if occupancy.busy(models.ScheduleConflictRoom, room.ID, key, start, end) {
+					continue
+				}
+				options = append(options, models.ScheduleSlotOption{
+					Day:                    day,
+					StartTime:              formatClock(start),
+					EndTime:                formatClock(end),
+					RoomID:                 room.ID,
+					RoomName:               room.Name,
+					BuildingID:             room.BuildingID,
+					BuildingName:           room.Building.Name,
+					RoomCapacity:           room.Capacity,
+					SpareSeats:             room.Capacity - minCapacity,
+					GroupClassesThatDay:    groupClasses,
+					LecturerClassesThatDay: lecturerClasses,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		a, b := options[i], options[j]
+		if a.GroupClassesThatDay != b.GroupClassesThatDay {
+			return a.GroupClassesThatDay < b.GroupClassesThatDay
+		}
+		if a.LecturerClassesThatDay != b.LecturerClassesThatDay {
+			return a.LecturerClassesThatDay < b.LecturerClassesThatDay
+		}
+		if a.SpareSeats != b.SpareSeats {
+			return a.SpareSeats < b.SpareSeats
+		}
+		if dayA, dayB := dayOrder(strings.ToLower(a.Day)), dayOrder(strings.ToLower(b.Day)); dayA != dayB {
+			return dayA < dayB
+		}
+		return a.StartTime < b.StartTime
+	})
+
+	result := &models.ScheduleSlotSearchResult{
+		DurationMinutes: duration,
+		MinCapacity:     minCapacity,
+		TotalFound:      len(options),
+		Options:         options,
+	}
+	if len(result.Options) > limit {
+		result.Options = result.Options[:limit]
+	}
+	for i := range result.Options {
+		result.Options[i].Rank = i + 1
+	}
+	return result, nil
+}
+
+// parseSlotWindow parses the daily time window of a search, defaulting to 07:00 to 18:00
+func parseSlotWindow(earliestStart, latestEnd string) (int, int, error) {
+	if earliestStart == "" {
+		earliestStart = "07:00"
+	}
+	if latestEnd == "" {
+		latestEnd = "18:00"
+	}
+	earliest, earliestOK := clockMinutes(earliestStart)
+	latest, latestOK := clockMinutes(latestEnd)
+	if !earliestOK || !latestOK || latest <= earliest {
+		return 0, 0, errors.New("earliest_start and latest_end must be HH:MM with latest_end after earliest_start")
+	}
+	return earliest, latest, nil
+}
+
+// parseSlotDays checks the day names of a search and drops repeated ones, defaulting to Senin to Jumat
+func parseSlotDays(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		requested = defaultSlotSearchDays
+	}
+	var days []string
+	seen := make(map[string]bool, len(requested))
+	for _, day := range requested {
+		key := strings.ToLower(strings.TrimSpace(day))
+		if dayOrder(key) == len(weekDays) {
+			return nil, fmt.Errorf("unknown day %q", day)
+		}
+		if !seen[key] {
+			seen[key] = true
+			days = append(days, strings.TrimSpace(day))
+		}
+	}
+	return days, nil
+}
+
+// fittingRooms returns the rooms with at least minCapacity seats, of a building when buildingID is set
+func fittingRooms(rooms []models.Room, minCapacity int, buildingID uint) []models.Room {
+	fitting := make([]models.Room, 0, len(rooms))
+	for _, room := range rooms {
+		if room.Capacity < minCapacity || (buildingID != 0 && room.BuildingID != buildingID) {
+			continue
+		}
+		fitting = append(fitting, room)
+	}
+	return fitting
+}
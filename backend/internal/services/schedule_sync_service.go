@@ -2,20 +2,29 @@ package services
 
 import (
 	"fmt"
+	"log/slog"
 	"github.com/delpresence/backend/internal/repositories"
 )
 
 // ScheduleSyncService provides methods to synchronize related data in schedules
 type ScheduleSyncService struct {
-	scheduleRepo  *repositories.CourseScheduleRepository
-	lecturerRepo  *repositories.LecturerRepository
+	scheduleRepo repositories.CourseScheduleStore
+	lecturerRepo repositories.LecturerStore
 }
 
 // NewScheduleSyncService creates a new instance of ScheduleSyncService
 func NewScheduleSyncService() *ScheduleSyncService {
+	return NewScheduleSyncServiceWithStores(
+		repositories.NewCourseScheduleRepository(),
+		repositories.NewLecturerRepository(),
+	)
+}
+
+// NewScheduleSyncServiceWithStores creates a new schedule sync service on top of the given stores
+func NewScheduleSyncServiceWithStores(scheduleRepo repositories.CourseScheduleStore, lecturerRepo repositories.LecturerStore) *ScheduleSyncService {
 	return &ScheduleSyncService{
-		scheduleRepo:  repositories.NewCourseScheduleRepository(),
-		lecturerRepo:  repositories.NewLecturerRepository(),
+		scheduleRepo: scheduleRepo,
+		lecturerRepo: lecturerRepo,
 	}
 }
 
@@ -28,17 +37,14 @@ func (s *ScheduleSyncService) SyncLecturerAssignmentWithSchedules(
 	// Find the lecturer record directly based on user_id
 	lecturer, err := s.lecturerRepo.GetByUserID(lecturerUserID)
 	if err != nil {
-		fmt.Printf("Error finding lecturer by user_id %d: %v\n", lecturerUserID, err)
 		return fmt.Errorf("failed to find lecturer by user_id: %w", err)
 	}
 	
 	if lecturer.ID == 0 {
-		fmt.Printf("Lecturer not found for user_id %d\n", lecturerUserID)
 		return fmt.Errorf("lecturer not found for user_id %d", lecturerUserID)
 	}
 
-	fmt.Printf("Syncing course schedules: courseID=%d, academicYearID=%d, updating lecturer user_id=%d\n", 
-		courseID, academicYearID, lecturerUserID)
+	slog.Info("Syncing lecturer of course schedules", "course_id", courseID, "academic_year_id", academicYearID, "lecturer_id", lecturerUserID)
 
 	// Update all schedules for this course in this academic year with the lecturer user_id
 	// This assumes the lecturer_id column in course_schedules maps to the user_id field in lecturers
@@ -47,7 +53,6 @@ func (s *ScheduleSyncService) SyncLecturerAssignmentWithSchedules(
 		academicYearID,
 		uint(lecturerUserID), // Convert to uint for the schedule repository method
 	); err != nil {
-		fmt.Printf("Error updating course schedules: %v\n", err)
 		return fmt.Errorf("failed to update schedules: %w", err)
 	}
 
@@ -6,37 +6,47 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/delpresence/backend/internal/config"
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/repositories"
 )
 
-const (
-	CampusStudentsURL = "https://cis.del.ac.id/api/library-api/mahasiswa?status=aktif"
-)
-
 // StudentService provides functionality for managing students
 type StudentService struct {
-	repository *repositories.StudentRepository
+	repository repositories.StudentStore
 	campusAuth *CampusAuthService
 }
 
 // NewStudentService creates a new student service
 func NewStudentService() *StudentService {
+	return NewStudentServiceWithStores(
+		repositories.NewStudentRepository(),
+		NewCampusAuthService(),
+	)
+}
+
+// NewStudentServiceWithStores creates a new student service on top of the given stores
+func NewStudentServiceWithStores(repository repositories.StudentStore, campusAuth *CampusAuthService) *StudentService {
 	return &StudentService{
-		repository: repositories.NewStudentRepository(),
-		campusAuth: NewCampusAuthService(),
+		repository: repository,
+		campusAuth: campusAuth,
 	}
 }
 
-// GetAllStudents returns all students from the database
-func (s *StudentService) GetAllStudents() ([]models.Student, error) {
-	return s.repository.FindAll()
+// ListStudents returns one page of students
+func (s *StudentService) ListStudents(q models.ListQuery) (models.ListResult[models.Student], error) {
+	return s.repository.List(q)
+}
+
+// GetActiveStudents returns the students that have not been deactivated by the sync
+func (s *StudentService) GetActiveStudents() ([]models.Student, error) {
+	return s.repository.FindActive()
 }
 
 // GetStudentByID returns a student by ID
@@ -51,10 +61,23 @@ func (s *StudentService) GetStudentByUserID(userID int) (*models.Student, error)
 
 // SyncStudents fetches students from the campus API and syncs them to the database
 func (s *StudentService) SyncStudents() (int, error) {
+	students, err := s.FetchCampusStudents()
+	if err != nil {
+		return 0, err
+	}
+	result, err := s.ApplyStudents(students)
+	if err != nil {
+		return 0, err
+	}
+	return result.Inserted + result.Updated, nil
+}
+
+// FetchCampusStudents fetches students from the campus API and converts them without saving
+func (s *StudentService) FetchCampusStudents() ([]models.Student, error) {
 	// Get auth token from campus auth service
 	token, err := s.campusAuth.GetToken()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get authentication token: %w", err)
+		return nil, fmt.Errorf("failed to get authentication token: %w", err)
 	}
 
 	// Fetch students from campus API
@@ -64,14 +87,14 @@ func (s *StudentService) SyncStudents() (int, error) {
 		if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "403") {
 			token, errRefresh := s.campusAuth.RefreshToken()
 			if errRefresh != nil {
-				return 0, fmt.Errorf("failed to refresh authentication token: %w", errRefresh)
+				return nil, fmt.Errorf("failed to refresh authentication token: %w", errRefresh)
 			}
 			campusStudents, err = s.fetchStudentsFromCampus(token)
 			if err != nil {
-				return 0, err
+				return nil, err
 			}
 		} else {
-			return 0, err
+			return nil, err
 		}
 	}
 
@@ -79,38 +102,86 @@ func (s *StudentService) SyncStudents() (int, error) {
 	students := make([]models.Student, 0, len(campusStudents))
 	for _, cs := range campusStudents {
 		student := models.Student{
-			DimID:          cs.DimID,
-			UserID:         cs.UserID,
-			UserName:       cs.UserName,
-			NIM:            cs.NIM,
-			FullName:       cs.Nama,
-			Email:          cs.Email,
-			StudyProgramID: cs.ProdiID,
-			StudyProgram:   cs.ProdiName,
-			Faculty:        cs.Fakultas,
-			YearEnrolled:   cs.Angkatan,
-			Status:         cs.Status,
-			Dormitory:      cs.Asrama,
-			LastSync:       time.Now(),
+			DimID:           cs.DimID,
+			UserID:          cs.UserID,
+			UserName:        cs.UserName,
+			NIM:             cs.NIM,
+			FullName:        cs.Nama,
+			Email:           cs.Email,
+			StudyProgramID:  cs.ProdiID,
+			StudyProgram:    cs.ProdiName,
+			Faculty:         cs.Fakultas,
+			YearEnrolled:    cs.Angkatan,
+			Status:          cs.Status,
+			Dormitory:       cs.Asrama,
+			LastSync:        time.Now(),
+			LifecycleStatus: models.LifecycleActive,
 		}
 		students = append(students, student)
 	}
 
-	// Save to database
-	err = s.repository.UpsertMany(students)
+	return students, nil
+}
+
+// ApplyStudents saves students fetched from the campus API
+// Students that CIS no longer returns as active are deactivated and removed from their groups
+func (s *StudentService) ApplyStudents(students []models.Student) (*models.SyncResult, error) {
+	result := &models.SyncResult{Received: len(students)}
+	active := make([]models.Student, 0, len(students))
+	for _, student := range students {
+		if student.Status == "" || strings.EqualFold(student.Status, "aktif") {
+			active = append(active, student)
+		}
+	}
+
+	if err := s.repository.UpsertMany(active, result); err != nil {
+		return nil, err
+	}
+
+	deactivated, err := s.deactivateMissingStudents(active)
+	if err != nil {
+		return nil, err
+	}
+	result.Deactivated = deactivated
+	return result, nil
+}
+
+// deactivateMissingStudents deactivates active students that are not in the synced list
+func (s *StudentService) deactivateMissingStudents(synced []models.Student) (int, error) {
+	syncedDimIDs := make(map[int]bool, len(synced))
+	for _, student := range synced {
+		syncedDimIDs[student.DimID] = true
+	}
+
+	current, err := s.repository.FindActive()
 	if err != nil {
 		return 0, err
 	}
 
-	return len(students), nil
+	var ids []uint
+	for _, student := range current {
+		if !syncedDimIDs[student.DimID] {
+			ids = append(ids, student.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	slog.Info("Deactivating students no longer active in CIS", "count", len(ids))
+	if err := s.repository.Deactivate(ids, "no longer returned as active by CIS", time.Now()); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
 }
 
 // fetchStudentsFromCampus fetches students from the campus API
 func (s *StudentService) fetchStudentsFromCampus(token string) ([]models.CampusStudent, error) {
-	log.Printf("Fetching students from campus API: %s", CampusStudentsURL)
+	url := config.Get().Campus.StudentsURL
+	slog.Info("Fetching students from campus API", "url", url)
 
 	// Create request to campus API
-	req, err := http.NewRequest("GET", CampusStudentsURL, nil)
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -120,7 +191,6 @@ func (s *StudentService) fetchStudentsFromCampus(token string) ([]models.CampusS
 
 	// Send request with increased timeout (2 minutes)
 	client := &http.Client{Timeout: 120 * time.Second}
-	log.Printf("Sending request to campus API with token (timeout: 2 minutes)")
 
 	// Execute request with context for better error handling
 	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
@@ -129,7 +199,7 @@ func (s *StudentService) fetchStudentsFromCampus(token string) ([]models.CampusS
 
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Printf("Network error when fetching students: %v", err)
+		slog.Warn("Network error when fetching students", "error", err)
 
 		// Check for specific timeout errors
 		if os.IsTimeout(err) || strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "deadline exceeded") {
@@ -143,47 +213,46 @@ func (s *StudentService) fetchStudentsFromCampus(token string) ([]models.CampusS
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		log.Printf("Failed to fetch students from campus API with status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
+		slog.Warn("Failed to fetch students from campus API", "status", resp.StatusCode, "response", string(bodyBytes))
 		return nil, fmt.Errorf("failed to fetch students from campus API with status code: %d, response: %s", resp.StatusCode, string(bodyBytes))
 	}
 
 	// Read the response body
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("Error reading response body: %v", err)
+		slog.Warn("Error reading campus API response", "error", err)
 		return nil, fmt.Errorf("error reading response body: %w", err)
 	}
 
-	log.Printf("Received response from campus API, length: %d bytes", len(bodyBytes))
+	slog.Debug("Received response from campus API", "bytes", len(bodyBytes))
 
 	// For debugging, log a small portion of the response
 	previewLen := 200
 	if len(bodyBytes) < previewLen {
 		previewLen = len(bodyBytes)
 	}
-	log.Printf("Response preview: %s", string(bodyBytes[:previewLen]))
+	slog.Debug("Campus API response preview", "response", string(bodyBytes[:previewLen]))
 
 	// Parse response
 	var campusResp models.CampusStudentResponse
 	err = json.Unmarshal(bodyBytes, &campusResp)
 	if err != nil {
-		log.Printf("Failed to parse campus API response: %v, raw response length: %d", err, len(bodyBytes))
-		log.Printf("First 500 characters of response: %s", string(bodyBytes[:min(500, len(bodyBytes))]))
+		slog.Warn("Failed to parse campus API response", "bytes", len(bodyBytes), "response", string(bodyBytes[:min(500, len(bodyBytes))]), "error", err)
 		return nil, fmt.Errorf("failed to parse campus API response: %w, raw response: %s", err, string(bodyBytes))
 	}
 
 	// Check if result is OK
 	if campusResp.Result != "Ok" {
-		log.Printf("Campus API returned an error: %s", campusResp.Result)
+		slog.Warn("Campus API returned an error", "result", campusResp.Result)
 		return nil, fmt.Errorf("campus API returned an error: %s", campusResp.Result)
 	}
 
 	// Check if we have students
 	if len(campusResp.Data.Students) == 0 {
-		log.Printf("No students found in campus API response")
+		slog.Warn("No students found in campus API response")
 		return nil, errors.New("no students found in campus API response")
 	}
 
-	log.Printf("Successfully fetched %d students from campus API", len(campusResp.Data.Students))
+	slog.Info("Fetched students from campus API", "count", len(campusResp.Data.Students))
 	return campusResp.Data.Students, nil
 }
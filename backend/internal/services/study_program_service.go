@@ -2,22 +2,39 @@ package services
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/delpresence/backend/internal/models"
 	"github.com/delpresence/backend/internal/repositories"
 )
 
+var (
+	// ErrStudyProgramNotFound is returned for study programs that do not exist
+	ErrStudyProgramNotFound = errors.New("study program not found")
+
+	// ErrStudyProgramCodeExists is returned when another study program, possibly a deleted one, has the code
+	ErrStudyProgramCodeExists = errors.New("a study program with this code already exists")
+)
+
 // StudyProgramService is a service for study program operations
 type StudyProgramService struct {
-	repository      *repositories.StudyProgramRepository
-	facultyRepository *repositories.FacultyRepository
+	repository        repositories.StudyProgramStore
+	facultyRepository repositories.FacultyStore
 }
 
 // NewStudyProgramService creates a new study program service
 func NewStudyProgramService() *StudyProgramService {
+	return NewStudyProgramServiceWithStores(
+		repositories.NewStudyProgramRepository(),
+		repositories.NewFacultyRepository(),
+	)
+}
+
+// NewStudyProgramServiceWithStores creates a new study program service on top of the given stores
+func NewStudyProgramServiceWithStores(repository repositories.StudyProgramStore, facultyRepository repositories.FacultyStore) *StudyProgramService {
 	return &StudyProgramService{
-		repository:      repositories.NewStudyProgramRepository(),
-		facultyRepository: repositories.NewFacultyRepository(),
+		repository:        repository,
+		facultyRepository: facultyRepository,
 	}
 }
 
@@ -56,7 +73,7 @@ func (s *StudyProgramService) CreateStudyProgram(program *models.StudyProgram) e
 			return nil
 		}
 		
-		return errors.New("program studi dengan kode ini sudah ada")
+		return ErrStudyProgramCodeExists
 	}
 
 	// Check if faculty exists
@@ -65,7 +82,7 @@ func (s *StudyProgramService) CreateStudyProgram(program *models.StudyProgram) e
 		return err
 	}
 	if faculty == nil {
-		return errors.New("fakultas tidak ditemukan")
+		return ErrFacultyNotFound
 	}
 
 	// Create study program
@@ -80,7 +97,7 @@ func (s *StudyProgramService) UpdateStudyProgram(program *models.StudyProgram) e
 		return err
 	}
 	if existingProgram == nil {
-		return errors.New("program studi tidak ditemukan")
+		return ErrStudyProgramNotFound
 	}
 
 	// If code is changed, check if new code already exists (including soft-deleted records)
@@ -90,7 +107,7 @@ func (s *StudyProgramService) UpdateStudyProgram(program *models.StudyProgram) e
 			return err
 		}
 		if exists {
-			return errors.New("program studi dengan kode ini sudah ada (termasuk yang sudah dihapus)")
+			return fmt.Errorf("%w, including deleted ones", ErrStudyProgramCodeExists)
 		}
 	}
 
@@ -100,7 +117,7 @@ func (s *StudyProgramService) UpdateStudyProgram(program *models.StudyProgram) e
 		return err
 	}
 	if faculty == nil {
-		return errors.New("fakultas tidak ditemukan")
+		return ErrFacultyNotFound
 	}
 
 	// Update study program
@@ -112,9 +129,9 @@ func (s *StudyProgramService) GetStudyProgramByID(id uint) (*models.StudyProgram
 	return s.repository.FindByID(id)
 }
 
-// GetAllStudyPrograms gets all study programs
-func (s *StudyProgramService) GetAllStudyPrograms() ([]models.StudyProgram, error) {
-	return s.repository.FindAll()
+// ListStudyPrograms returns one page of study programs
+func (s *StudyProgramService) ListStudyPrograms(q models.ListQuery) (models.ListResult[models.StudyProgram], error) {
+	return s.repository.List(q)
 }
 
 // GetStudyProgramsByFacultyID gets all study programs by faculty ID
@@ -125,7 +142,7 @@ func (s *StudyProgramService) GetStudyProgramsByFacultyID(facultyID uint) ([]mod
 		return nil, err
 	}
 	if faculty == nil {
-		return nil, errors.New("fakultas tidak ditemukan")
+		return nil, ErrFacultyNotFound
 	}
 
 	return s.repository.FindByFacultyID(facultyID)
@@ -139,7 +156,7 @@ func (s *StudyProgramService) DeleteStudyProgram(id uint) error {
 		return err
 	}
 	if program == nil {
-		return errors.New("program studi tidak ditemukan")
+		return ErrStudyProgramNotFound
 	}
 
 	// Delete study program
@@ -161,7 +178,7 @@ func (s *StudyProgramService) GetStudyProgramWithStats(id uint) (*StudyProgramWi
 		return nil, err
 	}
 	if program == nil {
-		return nil, errors.New("program studi tidak ditemukan")
+		return nil, ErrStudyProgramNotFound
 	}
 
 	// Build response
@@ -172,18 +189,17 @@ func (s *StudyProgramService) GetStudyProgramWithStats(id uint) (*StudyProgramWi
 	}, nil
 }
 
-// GetAllStudyProgramsWithStats gets all study programs with statistics
-func (s *StudyProgramService) GetAllStudyProgramsWithStats() ([]StudyProgramWithStats, error) {
-	// Get all study programs
-	programs, err := s.repository.FindAll()
+// ListStudyProgramsWithStats returns one page of study programs with statistics
+func (s *StudyProgramService) ListStudyProgramsWithStats(q models.ListQuery) (models.ListResult[StudyProgramWithStats], error) {
+	page, err := s.repository.List(q)
 	if err != nil {
-		return nil, err
+		return models.ListResult[StudyProgramWithStats]{}, err
 	}
 
 	// Build response with stats
-	result := make([]StudyProgramWithStats, len(programs))
-	for i, program := range programs {
-		result[i] = StudyProgramWithStats{
+	result := models.ListResult[StudyProgramWithStats]{Items: make([]StudyProgramWithStats, len(page.Items)), Page: page.Page}
+	for i, program := range page.Items {
+		result.Items[i] = StudyProgramWithStats{
 			StudyProgram:  program,
 			LecturerCount: int64(program.LecturerCount),
 			StudentCount:  int64(program.StudentCount),
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/delpresence/backend/internal/models"
+)
+
+// syncField is one compared field of a synced record
+type syncField struct {
+	name  string
+	value string
+}
+
+// syncRecord is the entity-independent form of a synced record used to compute diffs
+type syncRecord struct {
+	key    string
+	label  string
+	fields []syncField
+}
+
+// syncBatch holds the records fetched from the campus API until they are applied
+type syncBatch struct {
+	records []syncRecord
+	apply   func() (*models.SyncResult, error)
+	encode  func() ([]byte, error) // Stores the campus records of a preview, see syncSource.decode
+}
+
+// syncSource fetches the campus records of an entity and loads the matching database records
+type syncSource struct {
+	fetch  func() (*syncBatch, error)
+	decode func(data []byte) (*syncBatch, error) // Restores a batch stored with syncBatch.encode
+	local  func() ([]syncRecord, error)
+}
+
+// syncLimits configures when a sync diff is considered suspicious
+type syncLimits struct {
+	maxMissingPercent float64 // Block applying when more records would disappear
+	massChangePercent float64 // Warn when more records would change
+}
+
+// newSyncSources returns the sync sources of all entities
+func newSyncSources() map[string]syncSource {
+	return map[string]syncSource{
+		models.SyncEntityStudents: campusSource(
+			func() ([]models.Student, error) { return NewStudentService().FetchCampusStudents() },
+			func() ([]models.Student, error) { return NewStudentService().GetActiveStudents() },
+			func(students []models.Student) (*models.SyncResult, error) { return NewStudentService().ApplyStudents(students) },
+			studentSyncRecord,
+		),
+		models.SyncEntityLecturers: campusSource(
+			func() ([]models.Lecturer, error) { return NewLecturerService().FetchCampusLecturers() },
+			func() ([]models.Lecturer, error) { return NewLecturerService().GetActiveLecturers() },
+			func(lecturers []models.Lecturer) (*models.SyncResult, error) { return NewLecturerService().ApplyLecturers(lecturers) },
+			lecturerSyncRecord,
+		),
+		models.SyncEntityEmployees: campusSource(
+			func() ([]models.Employee, error) { return NewEmployeeService().FetchCampusEmployees() },
+			func() ([]models.Employee, error) { return NewEmployeeService().GetActiveEmployees() },
+			func(employees []models.Employee) (*models.SyncResult, error) { return NewEmployeeService().ApplyEmployees(employees) },
+			employeeSyncRecord,
+		),
+	}
+}
+
+// campusSource returns the sync source of an entity whose campus records are of type T
+// fetch loads them from the campus API, active the active ones from the database, and apply saves them
+func campusSource[T any](fetch, active func() ([]T, error), apply func([]T) (*models.SyncResult, error), record func(T) syncRecord) syncSource {
+	toRecords := func(items []T) []syncRecord {
+		records := make([]syncRecord, 0, len(items))
+		for _, item := range items {
+			records = append(records, record(item))
+		}
+		return records
+	}
+	batch := func(items []T) *syncBatch {
+		return &syncBatch{
+			records: toRecords(items),
+			apply:   func() (*models.SyncResult, error) { return apply(items) },
+			encode:  func() ([]byte, error) { return json.Marshal(items) },
+		}
+	}
+
+	return syncSource{
+		fetch: func() (*syncBatch, error) {
+			items, err := fetch()
+			if err != nil {
+				return nil, err
+			}
+			return batch(items), nil
+		},
+		decode: func(data []byte) (*syncBatch, error) {
+			var items []T
+			if err := json.Unmarshal(data, &items); err != nil {
+				return nil, err
+			}
+			return batch(items), nil
+		},
+		local: func() ([]syncRecord, error) {
+			items, err := active()
+			if err != nil {
+				return nil, err
+			}
+			return toRecords(items), nil
+		},
+	}
+}
+
+// studentSyncRecord converts a student, matched by DimID like StudentRepository.UpsertMany
+func studentSyncRecord(s models.Student) syncRecord {
+	return syncRecord{
+		key:   strconv.Itoa(s.DimID),
+		label: s.NIM + " " + s.FullName,
+		fields: []syncField{
+			{"user_id", strconv.Itoa(s.UserID)},
+			{"user_name", s.UserName},
+			{"nim", s.NIM},
+			{"full_name", s.FullName},
+			{"email", s.Email},
+			{"study_program_id", strconv.Itoa(s.StudyProgramID)},
+			{"study_program", s.StudyProgram},
+			{"faculty", s.Faculty},
+			{"year_enrolled", strconv.Itoa(s.YearEnrolled)},
+			{"status", s.Status},
+			{"dormitory", s.Dormitory},
+		},
+	}
+}
+
+// lecturerSyncRecord converts a lecturer, matched by LecturerID like LecturerRepository.Upsert
+func lecturerSyncRecord(l models.Lecturer) syncRecord {
+	return syncRecord{
+		key:   strconv.Itoa(l.LecturerID),
+		label: l.FullName,
+		fields: []syncField{
+			{"employee_id", strconv.Itoa(l.EmployeeID)},
+			{"user_id", strconv.Itoa(l.UserID)},
+			{"nip", l.NIP},
+			{"full_name", l.FullName},
+			{"email", l.Email},
+			{"study_program_id", strconv.FormatUint(uint64(l.StudyProgramID), 10)},
+			{"study_program_name", l.StudyProgramName},
+			{"academic_rank", l.AcademicRank},
+			{"academic_rank_desc", l.AcademicRankDesc},
+			{"education_level", l.EducationLevel},
+			{"nidn", l.NIDN},
+		},
+	}
+}
+
+// employeeSyncRecord converts an employee, matched by EmployeeID like EmployeeRepository.UpsertMany
+func employeeSyncRecord(e models.Employee) syncRecord {
+	return syncRecord{
+		key:   strconv.Itoa(e.EmployeeID),
+		label: e.FullName,
+		fields: []syncField{
+			{"user_id", strconv.Itoa(e.UserID)},
+			{"nip", e.NIP},
+			{"full_name", e.FullName},
+			{"email", e.Email},
+			{"position", e.Position},
+			{"employment_type", e.EmploymentType},
+		},
+	}
+}
+
+// computeSyncDiff compares campus records with database records and flags suspicious changes
+func computeSyncDiff(entity string, campus, local []syncRecord, limits syncLimits) models.SyncDiff {
+	diff := models.SyncDiff{
+		Entity:      entity,
+		CampusCount: len(campus),
+		LocalCount:  len(local),
+		New:         []models.SyncRecordDiff{},
+		Changed:     []models.SyncRecordDiff{},
+		Missing:     []models.SyncRecordDiff{},
+		Warnings:    []string{},
+	}
+
+	localByKey := make(map[string]syncRecord, len(local))
+	for _, record := range local {
+		localByKey[record.key] = record
+	}
+
+	seen := make(map[string]bool, len(campus))
+	duplicates := 0
+	for _, record := range campus {
+		if seen[record.key] {
+			duplicates++
+			continue
+		}
+		seen[record.key] = true
+
+		existing, ok := localByKey[record.key]
+		if !ok {
+			diff.New = append(diff.New, models.SyncRecordDiff{Key: record.key, Label: record.label})
+			continue
+		}
+
+		changes := compareSyncFields(existing.fields, record.fields)
+		if len(changes) == 0 {
+			diff.UnchangedCount++
+			continue
+		}
+		diff.Changed = append(diff.Changed, models.SyncRecordDiff{Key: record.key, Label: record.label, Changes: changes})
+	}
+
+	for _, record := range local {
+		if !seen[record.key] {
+			diff.Missing = append(diff.Missing, models.SyncRecordDiff{Key: record.key, Label: record.label})
+		}
+	}
+
+	if diff.LocalCount > 0 {
+		diff.MissingPercent = roundPercent(len(diff.Missing), diff.LocalCount)
+		diff.ChangedPercent = roundPercent(len(diff.Changed), diff.LocalCount)
+	}
+
+	if diff.CampusCount == 0 && diff.LocalCount > 0 {
+		diff.Warnings = append(diff.Warnings, "campus API returned no records")
+	}
+	if duplicates > 0 {
+		diff.Warnings = append(diff.Warnings, fmt.Sprintf("campus API returned %d duplicate records", duplicates))
+	}
+	if diff.MissingPercent > limits.maxMissingPercent {
+		diff.Blocked = true
+		diff.Warnings = append(diff.Warnings, fmt.Sprintf("%d records (%.1f%%) are no longer returned by the campus API, more than the allowed %.1f%%",
+			len(diff.Missing), diff.MissingPercent, limits.maxMissingPercent))
+	}
+	if diff.ChangedPercent > limits.massChangePercent {
+		diff.Warnings = append(diff.Warnings, fmt.Sprintf("%d records (%.1f%%) would change, more than %.1f%%",
+			len(diff.Changed), diff.ChangedPercent, limits.massChangePercent))
+	}
+
+	return diff
+}
+
+// compareSyncFields returns the fields whose values differ
+func compareSyncFields(old, new []syncField) []models.SyncFieldChange {
+	oldValues := make(map[string]string, len(old))
+	for _, field := range old {
+		oldValues[field.name] = field.value
+	}
+
+	var changes []models.SyncFieldChange
+	for _, field := range new {
+		if oldValue := oldValues[field.name]; oldValue != field.value {
+			changes = append(changes, models.SyncFieldChange{Field: field.name, Old: oldValue, New: field.value})
+		}
+	}
+	return changes
+}
+
+// roundPercent returns part/total as a percentage rounded to one decimal
+func roundPercent(part, total int) float64 {
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+)
+
+// Previews are stored with the fetched campus records, so exactly the reviewed data is applied,
+// through whichever instance the apply request reaches
+
+// Preview fetches an entity from the campus API and computes the diff against the database
+// without changing anything. The preview can be applied with ApplyPreview until it expires.
+func (s *SyncScheduler) Preview(entity, createdBy string) (*models.SyncPreview, error) {
+	job, ok := s.jobs[entity]
+	if !ok {
+		return nil, ErrUnknownSyncEntity
+	}
+
+	batch, err := job.source.fetch()
+	if err != nil {
+		return nil, err
+	}
+	diff, err := s.diff(job, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	preview := models.SyncPreview{
+		ID:        hex.EncodeToString(idBytes),
+		Entity:    entity,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.previewTTL),
+		Diff:      *diff,
+	}
+	record, err := previewRecord(preview, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.previews.DeleteExpired(now); err != nil {
+		slog.Error("Error deleting expired sync previews", "error", err)
+	}
+	if err := s.previews.Create(record); err != nil {
+		return nil, fmt.Errorf("failed to store sync preview: %w", err)
+	}
+
+	slog.Info("Sync preview created", "entity", entity, "preview_id", preview.ID, "created_by", createdBy,
+		"new", len(diff.New), "changed", len(diff.Changed), "missing", len(diff.Missing))
+
+	return &preview, nil
+}
+
+// GetPreview returns a sync preview that has not expired yet
+func (s *SyncScheduler) GetPreview(id string) (*models.SyncPreview, error) {
+	record, err := s.findPreview(id)
+	if err != nil {
+		return nil, err
+	}
+	return toSyncPreview(record)
+}
+
+// ApplyPreview saves the records fetched by a preview
+// The diff is recomputed against the current database, and the safety threshold
+// blocks the apply unless force is set
+func (s *SyncScheduler) ApplyPreview(id string, force bool, actor *SyncActor) (*models.SyncRun, error) {
+	record, err := s.findPreview(id)
+	if err != nil {
+		return nil, err
+	}
+	job, ok := s.jobs[record.Entity]
+	if !ok {
+		return nil, ErrUnknownSyncEntity
+	}
+
+	run, err := s.execute(job, models.SyncTriggerManual, actor, func(run *models.SyncRun) (*models.SyncResult, error) {
+		// Read again under the sync lock, a concurrent apply of the preview has deleted it
+		record, err := s.findPreview(id)
+		if err != nil {
+			return nil, err
+		}
+		batch, err := job.source.decode([]byte(record.Records))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sync preview records: %w", err)
+		}
+
+		run.RecordCount = len(batch.records)
+		diff, err := s.checkBatch(job, batch, force)
+		if err != nil {
+			return nil, err
+		}
+		if diff.Blocked {
+			slog.Warn("Sync preview applied despite safety threshold", "entity", job.entity, "preview_id", id, "applied_by", actor.Username,
+				"missing", len(diff.Missing), "missing_percent", diff.MissingPercent)
+		}
+		result, err := batch.apply()
+		if err != nil {
+			return result, err
+		}
+
+		if err := s.previews.Delete(id); err != nil {
+			slog.Error("Error deleting applied sync preview", "preview_id", id, "error", err)
+		}
+		return result, nil
+	})
+	return run, err
+}
+
+// diff compares a fetched batch with the current database records of the job's entity
+func (s *SyncScheduler) diff(job *syncJob, batch *syncBatch) (*models.SyncDiff, error) {
+	local, err := job.source.local()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s from database: %w", job.entity, err)
+	}
+	diff := computeSyncDiff(job.entity, batch.records, local, s.limits)
+	return &diff, nil
+}
+
+// checkBatch computes the diff of a batch and returns ErrSyncBlocked when it exceeds the safety threshold
+func (s *SyncScheduler) checkBatch(job *syncJob, batch *syncBatch, force bool) (*models.SyncDiff, error) {
+	diff, err := s.diff(job, batch)
+	if err != nil {
+		return nil, err
+	}
+	if diff.Blocked && !force {
+		return diff, fmt.Errorf("%w: %d of %d %s (%.1f%%) are no longer returned by the campus API, review a preview and apply it with force",
+			ErrSyncBlocked, len(diff.Missing), diff.LocalCount, job.entity, diff.MissingPercent)
+	}
+	return diff, nil
+}
+
+// findPreview returns a stored preview that has not expired yet
+func (s *SyncScheduler) findPreview(id string) (*models.SyncPreviewRecord, error) {
+	record, err := s.previews.FindActive(id, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync preview: %w", err)
+	}
+	if record == nil {
+		return nil, ErrSyncPreviewNotFound
+	}
+	return record, nil
+}
+
+// previewRecord returns the stored form of a preview and the campus records of its batch
+func previewRecord(preview models.SyncPreview, batch *syncBatch) (*models.SyncPreviewRecord, error) {
+	diff, err := json.Marshal(preview.Diff)
+	if err != nil {
+		return nil, err
+	}
+	records, err := batch.encode()
+	if err != nil {
+		return nil, err
+	}
+	return &models.SyncPreviewRecord{
+		ID:        preview.ID,
+		Entity:    preview.Entity,
+		CreatedBy: preview.CreatedBy,
+		CreatedAt: preview.CreatedAt,
+		ExpiresAt: preview.ExpiresAt,
+		Diff:      string(diff),
+		Records:   string(records),
+	}, nil
+}
+
+// toSyncPreview returns the preview of a stored preview
+func toSyncPreview(record *models.SyncPreviewRecord) (*models.SyncPreview, error) {
+	preview := &models.SyncPreview{
+		ID:        record.ID,
+		Entity:    record.Entity,
+		CreatedBy: record.CreatedBy,
+		CreatedAt: record.CreatedAt,
+		ExpiresAt: record.ExpiresAt,
+	}
+	if err := json.Unmarshal([]byte(record.Diff), &preview.Diff); err != nil {
+		return nil, fmt.Errorf("failed to read sync preview diff: %w", err)
+	}
+	return preview, nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/database"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+func TestSyncPreviewAppliedThroughAnotherInstance(t *testing.T) {
+	t.Setenv("DB_DRIVER", database.DriverSQLite)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "previews.db"))
+	if _, err := config.Load(); err != nil {
+		t.Logf("config: %v", err)
+	}
+	database.Initialize()
+	t.Cleanup(database.Close)
+	if err := database.MigrateUp(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	// Each instance has its own scheduler on the shared database, the campus returns one new student
+	var applied []models.Student
+	newInstance := func() *SyncScheduler {
+		source := campusSource(
+			func() ([]models.Student, error) {
+				return []models.Student{{DimID: 1, UserID: 2001, NIM: "11S001", FullName: "Student"}}, nil
+			},
+			func() ([]models.Student, error) { return nil, nil },
+			func(students []models.Student) (*models.SyncResult, error) {
+				applied = append(applied, students...)
+				return &models.SyncResult{Received: len(students), Inserted: len(students)}, nil
+			},
+			studentSyncRecord,
+		)
+		return &SyncScheduler{
+			runs:       repositories.NewSyncRunRepository(),
+			previews:   repositories.NewSyncPreviewRepository(),
+			jobs:       map[string]*syncJob{models.SyncEntityStudents: {entity: models.SyncEntityStudents, source: source}},
+			previewTTL: time.Hour,
+		}
+	}
+
+	preview, err := newInstance().Preview(models.SyncEntityStudents, "admin")
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+
+	other := newInstance()
+	stored, err := other.GetPreview(preview.ID)
+	if err != nil {
+		t.Fatalf("get preview: %v", err)
+	}
+	if len(stored.Diff.New) != 1 || stored.Diff.New[0].Key != "1" {
+		t.Errorf("got new records %+v, want the student with DimID 1", stored.Diff.New)
+	}
+
+	run, err := other.ApplyPreview(preview.ID, false, &SyncActor{Username: "admin"})
+	if err != nil {
+		t.Fatalf("apply preview: %v", err)
+	}
+	if run.Status != models.SyncStatusSuccess || len(applied) != 1 || applied[0].NIM != "11S001" {
+		t.Errorf("got run %s applying %+v, want the previewed student applied", run.Status, applied)
+	}
+
+	if _, err := newInstance().ApplyPreview(preview.ID, false, &SyncActor{Username: "admin"}); !errors.Is(err, ErrSyncPreviewNotFound) {
+		t.Errorf("second apply: got %v, want %v", err, ErrSyncPreviewNotFound)
+	}
+}
@@ -0,0 +1,429 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/delpresence/backend/internal/config"
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+	"github.com/delpresence/backend/internal/utils"
+)
+
+var (
+	// ErrSyncInProgress is returned when a sync of the same entity is already running
+	ErrSyncInProgress = errors.New("a sync of this entity is already running")
+
+	// ErrUnknownSyncEntity is returned for entities that cannot be synchronized
+	ErrUnknownSyncEntity = errors.New("unknown sync entity")
+
+	// ErrSyncBlocked is returned when a sync would remove more records than the safety threshold allows
+	ErrSyncBlocked = errors.New("sync blocked by safety threshold")
+
+	// ErrSyncPreviewNotFound is returned for unknown or expired sync previews
+	ErrSyncPreviewNotFound = errors.New("sync preview not found or expired")
+)
+
+// SyncActor identifies the user who started a manual sync
+// UserID is zero for syncs started from the command line
+type SyncActor struct {
+	UserID   uint
+	Username string
+}
+
+// syncJob holds the schedule and state of one entity
+type syncJob struct {
+	entity   string
+	schedule utils.Schedule
+	source   syncSource
+
+	mu       sync.Mutex
+	running  bool
+	nextRun  *time.Time
+	failures int
+}
+
+// SyncScheduler runs campus syncs on a schedule and records every run
+// Manual syncs go through the scheduler too, so runs of the same entity never overlap,
+// not even across server instances sharing the database
+type SyncScheduler struct {
+	runs         repositories.SyncRunStore
+	previews     repositories.SyncPreviewStore
+	jobs         map[string]*syncJob
+	enabled      bool
+	jitter       time.Duration
+	alertAfter   int
+	staleAfter   time.Duration
+	limits       syncLimits
+	previewTTL   time.Duration
+	startOnce    sync.Once
+	loops        sync.WaitGroup
+	randomSource *rand.Rand
+	randomMu     sync.Mutex
+}
+
+var (
+	syncScheduler     *SyncScheduler
+	syncSchedulerOnce sync.Once
+)
+
+// GetSyncScheduler returns the shared sync scheduler
+func GetSyncScheduler() *SyncScheduler {
+	syncSchedulerOnce.Do(func() {
+		syncScheduler = newSyncScheduler()
+	})
+	return syncScheduler
+}
+
+// newSyncScheduler creates the scheduler from the sync configuration
+// SYNC_<ENTITY>_SCHEDULE takes a cron expression, @hourly/@daily/@weekly, "@every <duration>" or "off"
+func newSyncScheduler() *SyncScheduler {
+	cfg := config.Get().Sync
+	s := &SyncScheduler{
+		runs:       repositories.NewSyncRunRepository(),
+		previews:   repositories.NewSyncPreviewRepository(),
+		jobs:       make(map[string]*syncJob),
+		enabled:    cfg.Enabled,
+		jitter:     cfg.Jitter,
+		alertAfter: cfg.FailureAlertAfter,
+		staleAfter: cfg.StaleAfter,
+		limits: syncLimits{
+			maxMissingPercent: float64(cfg.MaxMissingPercent),
+			massChangePercent: float64(cfg.MassChangePercent),
+		},
+		previewTTL:   cfg.PreviewTTL,
+		randomSource: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	sources := newSyncSources()
+	for _, entity := range models.SyncEntities {
+		job := &syncJob{entity: entity, source: sources[entity]}
+
+		envKey := "SYNC_" + strings.ToUpper(entity) + "_SCHEDULE"
+		spec := cfg.Schedule(entity)
+		if !strings.EqualFold(spec, "off") {
+			schedule, err := utils.ParseSchedule(spec)
+			if err != nil {
+				slog.Warn("Invalid sync schedule, scheduled sync disabled", "setting", envKey, "entity", entity, "error", err)
+			} else {
+				job.schedule = schedule
+			}
+		}
+
+		s.jobs[entity] = job
+	}
+
+	return s
+}
+
+// Start launches the scheduled syncs in the background until the context is canceled
+func (s *SyncScheduler) Start(ctx context.Context) {
+	s.startOnce.Do(func() {
+		// Runs left over from a previous process can never finish
+		for _, entity := range models.SyncEntities {
+			s.markInterrupted(entity)
+		}
+
+		if !s.enabled {
+			slog.Info("Scheduled campus sync is disabled")
+			return
+		}
+
+		for _, entity := range models.SyncEntities {
+			job := s.jobs[entity]
+			if job.schedule == nil {
+				slog.Info("Scheduled sync is disabled", "entity", entity)
+				continue
+			}
+			slog.Info("Scheduled sync", "entity", entity, "schedule", job.schedule)
+			s.loops.Add(1)
+			go func(job *syncJob) {
+				defer s.loops.Done()
+				s.loop(ctx, job)
+			}(job)
+		}
+	})
+}
+
+// markInterrupted fails the running runs of an entity unless another instance is syncing it
+func (s *SyncScheduler) markInterrupted(entity string) {
+	unlock, locked, err := s.runs.TryLock(entity)
+	if err != nil {
+		slog.Error("Error taking sync lock", "entity", entity, "error", err)
+		return
+	}
+	if !locked {
+		return
+	}
+	defer unlock()
+	if err := s.runs.MarkInterrupted(entity); err != nil {
+		slog.Error("Error marking interrupted sync runs", "entity", entity, "error", err)
+	}
+}
+
+// Wait blocks until the scheduled sync loops have returned after their context was canceled,
+// including a sync they are running, or until ctx is done
+func (s *SyncScheduler) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.loops.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loop waits for the next activation of a job and runs it, until the context is canceled
+func (s *SyncScheduler) loop(ctx context.Context, job *syncJob) {
+	for {
+		next := job.schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Warn("Sync schedule never fires, stopping", "entity", job.entity, "schedule", job.schedule)
+			return
+		}
+
+		job.mu.Lock()
+		job.nextRun = &next
+		job.mu.Unlock()
+
+		// Jitter spreads the requests of several instances and entities over time
+		timer := time.NewTimer(time.Until(next) + s.randomJitter())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := s.Run(job.entity, models.SyncTriggerScheduled, nil); err != nil && !errors.Is(err, ErrSyncInProgress) {
+			slog.Error("Scheduled sync failed", "entity", job.entity, "error", err)
+		}
+	}
+}
+
+// randomJitter returns a random delay up to the configured jitter
+func (s *SyncScheduler) randomJitter() time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+	s.randomMu.Lock()
+	defer s.randomMu.Unlock()
+	return time.Duration(s.randomSource.Int63n(int64(s.jitter)))
+}
+
+// Run synchronizes an entity now and records the run
+// The sync is refused with ErrSyncBlocked when too many records would disappear
+// It returns ErrSyncInProgress without starting a run if the entity is already being synced
+// The actor is nil for scheduled runs
+func (s *SyncScheduler) Run(entity, trigger string, actor *SyncActor) (*models.SyncRun, error) {
+	job, ok := s.jobs[entity]
+	if !ok {
+		return nil, ErrUnknownSyncEntity
+	}
+
+	return s.execute(job, trigger, actor, func(run *models.SyncRun) (*models.SyncResult, error) {
+		batch, err := job.source.fetch()
+		if err != nil {
+			return nil, err
+		}
+		run.RecordCount = len(batch.records)
+		if _, err := s.checkBatch(job, batch, false); err != nil {
+			return nil, err
+		}
+		return batch.apply()
+	})
+}
+
+// execute runs a sync function under the job lock and records the run with its counts and record errors
+func (s *SyncScheduler) execute(job *syncJob, trigger string, actor *SyncActor, syncFn func(run *models.SyncRun) (*models.SyncResult, error)) (*models.SyncRun, error) {
+	entity := job.entity
+
+	job.mu.Lock()
+	if job.running {
+		job.mu.Unlock()
+		return nil, ErrSyncInProgress
+	}
+	job.running = true
+	job.mu.Unlock()
+
+	defer func() {
+		job.mu.Lock()
+		job.running = false
+		job.mu.Unlock()
+	}()
+
+	// The job guards this process, the lock the instances sharing the database
+	unlock, locked, err := s.runs.TryLock(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take the %s sync lock: %w", entity, err)
+	}
+	if !locked {
+		return nil, ErrSyncInProgress
+	}
+	defer unlock()
+
+	run := &models.SyncRun{
+		Entity:    entity,
+		Trigger:   trigger,
+		Status:    models.SyncStatusRunning,
+		StartedAt: time.Now(),
+	}
+	if actor != nil {
+		if actor.UserID != 0 {
+			run.ActorUserID = &actor.UserID
+		}
+		run.ActorUsername = actor.Username
+	}
+	if err := s.runs.Create(run); err != nil {
+		slog.Error("Error recording sync run", "entity", entity, "error", err)
+	}
+
+	slog.Info("Starting sync", "entity", entity, "trigger", trigger)
+	result, syncErr := syncFn(run)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	if result != nil {
+		run.RecordCount = result.Received
+		run.InsertedCount = result.Inserted
+		run.UpdatedCount = result.Updated
+		run.DeactivatedCount = result.Deactivated
+		run.FailedCount = len(result.Errors)
+		run.Errors = result.Errors
+	}
+	switch {
+	case syncErr != nil:
+		run.Status = models.SyncStatusFailed
+		run.Error = syncErr.Error()
+	case run.FailedCount > 0:
+		run.Status = models.SyncStatusPartial
+		run.Error = fmt.Sprintf("%d records could not be synced", run.FailedCount)
+	default:
+		run.Status = models.SyncStatusSuccess
+	}
+	if run.ID != 0 {
+		if err := s.runs.Finish(run); err != nil {
+			slog.Error("Error recording sync run", "entity", entity, "error", err)
+		}
+	}
+
+	s.reportResult(job, run)
+	return run, syncErr
+}
+
+// reportResult logs the outcome of a run and raises an alert after repeated failures
+func (s *SyncScheduler) reportResult(job *syncJob, run *models.SyncRun) {
+	job.mu.Lock()
+	if run.Status != models.SyncStatusFailed {
+		job.failures = 0
+	} else {
+		job.failures++
+	}
+	failures := job.failures
+	job.mu.Unlock()
+
+	duration := time.Duration(run.DurationMs) * time.Millisecond
+	if run.Status != models.SyncStatusFailed {
+		slog.Info("Sync finished", "entity", job.entity, "duration_ms", duration.Milliseconds(), "received", run.RecordCount,
+			"inserted", run.InsertedCount, "updated", run.UpdatedCount, "deactivated", run.DeactivatedCount, "failed", run.FailedCount)
+		return
+	}
+
+	slog.Error("Sync failed", "entity", job.entity, "duration_ms", duration.Milliseconds(), "error", run.Error)
+	if s.alertAfter > 0 && failures >= s.alertAfter {
+		slog.Error("ALERT: sync keeps failing, campus data may be stale", "entity", job.entity, "consecutive_failures", failures)
+	}
+}
+
+// Status returns the schedule and latest run of every entity
+func (s *SyncScheduler) Status() ([]models.SyncScheduleStatus, error) {
+	statuses := make([]models.SyncScheduleStatus, 0, len(models.SyncEntities))
+	for _, entity := range models.SyncEntities {
+		job := s.jobs[entity]
+
+		lastRun, err := s.runs.FindLatest(entity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest %s sync run: %w", entity, err)
+		}
+
+		lastSuccess, err := s.runs.FindLastSuccess(entity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get last successful %s sync run: %w", entity, err)
+		}
+
+		status := models.SyncScheduleStatus{
+			Entity:  entity,
+			LastRun: lastRun,
+		}
+		if lastSuccess != nil {
+			status.LastSuccessAt = lastSuccess.FinishedAt
+		}
+		if s.enabled && job.schedule != nil {
+			status.Schedule = job.schedule.String()
+		}
+
+		job.mu.Lock()
+		status.Running = job.running
+		status.ConsecutiveFailures = job.failures
+		if job.nextRun != nil {
+			next := *job.nextRun
+			status.NextRunAt = &next
+		}
+		job.mu.Unlock()
+
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Runs returns the most recent sync runs, optionally filtered by entity and status
+func (s *SyncScheduler) Runs(entity, status string, limit int) ([]models.SyncRun, error) {
+	return s.runs.FindRecent(entity, status, limit)
+}
+
+// ListRuns returns one page of sync runs, latest first unless sorted otherwise
+func (s *SyncScheduler) ListRuns(q models.ListQuery) (models.ListResult[models.SyncRun], error) {
+	return s.runs.List(q)
+}
+
+// GetRun returns a sync run with its record errors, or nil if it does not exist
+func (s *SyncScheduler) GetRun(id uint) (*models.SyncRun, error) {
+	return s.runs.FindByID(id)
+}
+
+// Freshness returns when every entity was last synced successfully and whether its data is stale
+// SYNC_STALE_AFTER_HOURS sets how old the data may get before it is considered stale
+func (s *SyncScheduler) Freshness() ([]models.SyncFreshness, error) {
+	now := time.Now()
+	freshness := make([]models.SyncFreshness, 0, len(models.SyncEntities))
+	for _, entity := range models.SyncEntities {
+		lastSuccess, err := s.runs.FindLastSuccess(entity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get last successful %s sync run: %w", entity, err)
+		}
+
+		entry := models.SyncFreshness{Entity: entity, Stale: true}
+		if lastSuccess != nil && lastSuccess.FinishedAt != nil {
+			age := now.Sub(*lastSuccess.FinishedAt)
+			ageHours := math.Round(age.Hours()*10) / 10
+			entry.LastSuccessAt = lastSuccess.FinishedAt
+			entry.AgeHours = &ageHours
+			entry.Stale = age > s.staleAfter
+		}
+		freshness = append(freshness, entry)
+	}
+	return freshness, nil
+}
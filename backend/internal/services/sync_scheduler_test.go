@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+// lockedSyncRunStore is a SyncRunStore whose entities are all being synced by another instance
+type lockedSyncRunStore struct {
+	repositories.SyncRunStore
+	created int
+}
+
+// TryLock reports the lock as held elsewhere
+func (s *lockedSyncRunStore) TryLock(entity string) (func(), bool, error) {
+	return nil, false, nil
+}
+
+// Create counts the runs started despite the lock
+func (s *lockedSyncRunStore) Create(run *models.SyncRun) error {
+	s.created++
+	return nil
+}
+
+func TestSyncRunRefusedWhileAnotherInstanceSyncs(t *testing.T) {
+	runs := &lockedSyncRunStore{}
+	fetched := false
+	s := &SyncScheduler{
+		runs: runs,
+		jobs: map[string]*syncJob{
+			models.SyncEntityStudents: {entity: models.SyncEntityStudents, source: syncSource{
+				fetch: func() (*syncBatch, error) {
+					fetched = true
+					return &syncBatch{}, nil
+				},
+			}},
+		},
+	}
+
+	_, err := s.Run(models.SyncEntityStudents, models.SyncTriggerManual, nil)
+	if !errors.Is(err, ErrSyncInProgress) {
+		t.Fatalf("got %v, want %v", err, ErrSyncInProgress)
+	}
+	if fetched || runs.created != 0 {
+		t.Errorf("sync fetched %t and recorded %d runs, want neither while locked", fetched, runs.created)
+	}
+	if s.jobs[models.SyncEntityStudents].running {
+		t.Error("job still marked running after the refused sync")
+	}
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/delpresence/backend/internal/models"
+	"github.com/delpresence/backend/internal/repositories"
+)
+
+var (
+	// ErrTimetableDraftNotFound is returned for timetable drafts that do not exist
+	ErrTimetableDraftNotFound = errors.New("timetable draft not found")
+
+	// ErrTimetableDraftCommitted is returned when changing a draft that was already committed
+	ErrTimetableDraftCommitted = errors.New("timetable draft is already committed")
+
+	// ErrTimetableDraftStale is returned when committing a draft that conflicts with schedules added since it was generated
+	ErrTimetableDraftStale = errors.New("timetable draft conflicts with the current schedules")
+
+	// ErrInvalidTimetableRequest is returned for timetable requests with invalid constraints
+	ErrInvalidTimetableRequest = errors.New("invalid timetable request")
+)
+
+// TimetableService generates timetable drafts for the lecturer assignments of an academic year and
+// turns a reviewed draft into course schedules
+type TimetableService struct {
+	drafts        repositories.TimetableDraftStore
+	schedules     repositories.CourseScheduleStore
+	academicYears repositories.AcademicYearStore
+	assignments   repositories.LecturerAssignmentStore
+	studentGroups repositories.StudentGroupStore
+	rooms         repositories.RoomStore
+	availability  repositories.LecturerAvailabilityStore
+}
+
+// NewTimetableService creates a new timetable service
+func NewTimetableService() *TimetableService {
+	return NewTimetableServiceWithStores(
+		repositories.NewTimetableDraftRepository(),
+		repositories.NewCourseScheduleRepository(),
+		repositories.NewAcademicYearRepository(),
+		repositories.NewLecturerAssignmentRepository(),
+		repositories.NewStudentGroupRepository(),
+		repositories.NewRoomRepository(),
+		repositories.NewLecturerAvailabilityRepository(),
+	)
+}
+
+// NewTimetableServiceWithStores creates a new timetable service on top of the given stores
+func NewTimetableServiceWithStores(
+	drafts repositories.TimetableDraftStore,
+	schedules repositories.CourseScheduleStore,
+	academicYears repositories.AcademicYearStore,
+	assignments repositories.LecturerAssignmentStore,
+	studentGroups repositories.StudentGroupStore,
+	rooms repositories.RoomStore,
+	availability repositories.LecturerAvailabilityStore,
+) *TimetableService {
+	return &TimetableService{
+		drafts:        drafts,
+		schedules:     schedules,
+		academicYears: academicYears,
+		assignments:   assignments,
+		studentGroups: studentGroups,
+		rooms:         rooms,
+		availability:  availability,
+	}
+}
+
+// Generate solves the timetable of the lecturer assignments of an academic year and stores it as a draft
+// Course and student group pairs that already have a schedule in the academic year are left out, their
+// schedules are kept and the draft is placed around them.
+func (s *TimetableService) Generate(ctx context.Context, request models.TimetableSolveRequest, createdBy string) (*models.TimetableDraft, error) {
+	academicYear, err := s.academicYears.FindByID(request.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+	if academicYear == nil {
+		return nil, ErrAcademicYearNotFound
+	}
+	earliest, latest, err := parseSlotWindow(request.EarliestStart, request.LatestEnd)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTimetableRequest, err)
+	}
+	days, err := parseSlotDays(request.Days)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTimetableRequest, err)
+	}
+
+	groups, err := s.studentGroups.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	groupsByID := make(map[uint]models.StudentGroup, len(groups))
+	groupsByDepartment := make(map[uint][]models.StudentGroup)
+	for _, group := range groups {
+		groupsByID[group.ID] = group
+		groupsByDepartment[group.DepartmentID] = append(groupsByDepartment[group.DepartmentID], group)
+	}
+	courseGroups := make(map[uint][]models.StudentGroup, len(request.CourseGroups))
+	for _, entry := range request.CourseGroups {
+		for _, groupID := range entry.StudentGroupIDs {
+			group, ok := groupsByID[groupID]
+			if !ok {
+				return nil, fmt.Errorf("%w: student group %d does not exist", ErrInvalidTimetableRequest, groupID)
+			}
+			courseGroups[entry.CourseID] = append(courseGroups[entry.CourseID], group)
+		}
+	}
+
+	assignments, err := s.assignments.GetAll(request.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(assignments, func(i, j int) bool { return assignments[i].CourseID < assignments[j].CourseID })
+
+	rooms, err := s.rooms.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.schedules.WithContext(ctx).GetByAcademicYear(request.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+	scheduled := make(map[[2]uint]bool, len(schedules))
+	for _, schedule := range schedules {
+		scheduled[[2]uint{schedule.CourseID, schedule.StudentGroupID}] = true
+	}
+	windows, err := s.availability.FindByLecturer(0)
+	if err != nil {
+		return nil, err
+	}
+
+	solver := newTimetableSolver(days, earliest, latest, rooms, toScheduleSlots(schedules), windows)
+	var meetings []timetableMeeting
+	var unplaced []models.TimetableUnplaced
+	seenCourses := make(map[uint]bool)
+	for _, assignment := range assignments {
+		// A course is taught by one lecturer, as in the schedule service
+		if seenCourses[assignment.CourseID] {
+			continue
+		}
+		seenCourses[assignment.CourseID] = true
+
+		groups, ok := courseGroups[assignment.CourseID]
+		if !ok {
+			groups = groupsByDepartment[assignment.Course.DepartmentID]
+		}
+		for _, group := range groups {
+			if scheduled[[2]uint{assignment.CourseID, group.ID}] {
+				continue
+			}
+			reason := ""
+			switch {
+			case assignment.UserID <= 0:
+				reason = "the course has no lecturer assigned"
+			case assignment.Course.Credits <= 0:
+				reason = "the course has no credits"
+			}
+			if reason != "" {
+				unplaced = append(unplaced, models.TimetableUnplaced{
+					CourseID:       assignment.CourseID,
+					StudentGroupID: group.ID,
+					LecturerID:     uint(max(assignment.UserID, 0)),
+					Reason:         reason,
+				})
+				continue
+			}
+			meetings = append(meetings, timetableMeetings(assignment.CourseID, group.ID, uint(assignment.UserID), assignment.Course.Credits, group.StudentCount)...)
+		}
+	}
+	solver.solve(meetings)
+
+	draft := &models.TimetableDraft{
+		AcademicYearID: request.AcademicYearID,
+		Status:         models.TimetableDraftOpen,
+		Unplaced:       append(unplaced, solver.unplaced...),
+		CreatedBy:      createdBy,
+	}
+	if draft.Unplaced == nil {
+		draft.Unplaced = []models.TimetableUnplaced{}
+	}
+	draft.GapMinutes, draft.AvoidedMinutes, draft.BuildingChanges = solver.totals()
+	draft.Score = draft.GapMinutes*timetableGapWeight +
+		draft.AvoidedMinutes*timetableAvoidWeight +
+		draft.BuildingChanges*timetableBuildingChangeWeight
+
+	for i, placement := range solver.placed {
+		meeting := meetings[i]
+		draft.Entries = append(draft.Entries, models.TimetableDraftEntry{
+			CourseID:       meeting.courseID,
+			StudentGroupID: meeting.groupID,
+			LecturerID:     meeting.lecturerID,
+			RoomID:         placement.room.id,
+			Day:            placement.day,
+			StartTime:      formatClock(placement.start),
+			EndTime:        formatClock(placement.end),
+		})
+	}
+	sort.Slice(draft.Entries, func(i, j int) bool {
+		a, b := draft.Entries[i], draft.Entries[j]
+		if dayA, dayB := dayOrder(strings.ToLower(a.Day)), dayOrder(strings.ToLower(b.Day)); dayA != dayB {
+			return dayA < dayB
+		}
+		if a.StartTime != b.StartTime {
+			return a.StartTime < b.StartTime
+		}
+		return a.RoomID < b.RoomID
+	})
+
+	if err := s.drafts.Create(draft); err != nil {
+		return nil, err
+	}
+	return s.Get(draft.ID)
+}
+
+// List returns the drafts of an academic year without their entries, newest first
+func (s *TimetableService) List(academicYearID uint) ([]models.TimetableDraft, error) {
+	return s.drafts.FindByAcademicYear(academicYearID)
+}
+
+// Get returns a draft with its entries
+func (s *TimetableService) Get(id uint) (*models.TimetableDraft, error) {
+	draft, err := s.drafts.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if draft == nil {
+		return nil, ErrTimetableDraftNotFound
+	}
+	return draft, nil
+}
+
+// Delete discards a draft that was not committed
+func (s *TimetableService) Delete(id uint) error {
+	draft, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if draft.Status == models.TimetableDraftCommitted {
+		return ErrTimetableDraftCommitted
+	}
+	return s.drafts.Delete(id)
+}
+
+// Commit creates a course schedule for every entry of a draft in one transaction
+// The entries are checked again against the schedules of the academic year inside the transaction,
+// a draft that conflicts with schedules added since it was generated is refused and has to be generated again.
+func (s *TimetableService) Commit(ctx context.Context, id uint, committedBy string) (*models.TimetableDraft, error) {
+	draft, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Status == models.TimetableDraftCommitted {
+		return nil, ErrTimetableDraftCommitted
+	}
+
+	members, err := s.studentGroups.CountMembersByGroup()
+	if err != nil {
+		return nil, err
+	}
+	schedules := make([]models.CourseSchedule, len(draft.Entries))
+	for i, entry := range draft.Entries {
+		schedules[i] = models.CourseSchedule{
+			CourseID:       entry.CourseID,
+			RoomID:         entry.RoomID,
+			Day:            entry.Day,
+			StartTime:      entry.StartTime,
+			EndTime:        entry.EndTime,
+			UserID:         entry.LecturerID,
+			StudentGroupID: entry.StudentGroupID,
+			AcademicYearID: draft.AcademicYearID,
+			Capacity:       entry.Room.Capacity,
+			Enrolled:       members[entry.StudentGroupID],
+		}
+	}
+
+	committed, err := s.drafts.Commit(draft, schedules, committedBy, time.Now(), func(current []models.CourseSchedule) error {
+		return draftFits(draft, current)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !committed {
+		return nil, ErrTimetableDraftCommitted
+	}
+	return s.Get(id)
+}
+
+// draftFits returns ErrTimetableDraftStale if an entry of a draft overlaps one of the current schedules
+func draftFits(draft *models.TimetableDraft, current []models.CourseSchedule) error {
+	occupancy := newScheduleOccupancy(toScheduleSlots(current))
+	for _, entry := range draft.Entries {
+		start, _ := clockMinutes(entry.StartTime)
+		end, _ := clockMinutes(entry.EndTime)
+		day := strings.ToLower(entry.Day)
+		if occupancy.busy(models.ScheduleConflictRoom, entry.RoomID, day, start, end) ||
+			occupancy.busy(models.ScheduleConflictLecturer, entry.LecturerID, day, start, end) ||
+			occupancy.busy(models.ScheduleConflictStudentGroup, entry.StudentGroupID, day, start, end) {
+			return fmt.Errorf("%w: %s %s-%s", ErrTimetableDraftStale, entry.Day, entry.StartTime, entry.EndTime)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/delpresence/backend/internal/models"
+)
+
+// Weights of the soft preferences of the timetable solver, in penalty points
+const (
+	timetableGapWeight            = 1  // Per minute a student group or lecturer waits between classes
+	timetableAvoidWeight          = 3  // Per minute in a window the lecturer would rather avoid
+	timetableBuildingChangeWeight = 30 // Per change of building between consecutive classes of a student group
+)
+
+const (
+	// maxCreditsPerMeeting splits courses with more credits into several weekly meetings on different days
+	maxCreditsPerMeeting = 3
+
+	// timetableImprovementPasses is how often the solver tries to move each placed class to a better slot
+	timetableImprovementPasses = 3
+)
+
+// timetableMeeting is one weekly class the solver has to place
+type timetableMeeting struct {
+	courseID   uint
+	groupID    uint
+	lecturerID uint
+	minutes    int
+	groupSize  int
+}
+
+// timetablePlacement is where a meeting is held
+type timetablePlacement struct {
+	day    string // As given in the request
+	dayKey string // Lower case
+	start  int
+	end    int
+	room   timetableRoom
+}
+
+type timetableRoom struct {
+	id         uint
+	buildingID uint
+	capacity   int
+}
+
+// timetableInterval is a class of a student group or lecturer on a day, for the soft preferences
+type timetableInterval struct {
+	start      int
+	end        int
+	buildingID uint
+}
+
+// timetableSolver places meetings greedily, the most constrained first, on the cheapest slot and room
+// that breaks no hard constraint, then tries to move each one to a cheaper slot
+//
+// Hard constraints: the room, lecturer and student group are free, the room seats the group, the
+// lecturer is not unavailable, the class lies in the daily window and the meetings of a course and
+// group are on different days. Soft preferences: fewer gaps for groups and lecturers, fewer windows
+// lecturers would rather avoid, and fewer building changes between consecutive classes of a group.
+type timetableSolver struct {
+	days        []string
+	earliest    int
+	latest      int
+	rooms       []timetableRoom // By capacity, smallest first
+	roomsByID   map[uint]timetableRoom
+	existing    scheduleOccupancy // Stored schedules of the academic year
+	unavailable scheduleOccupancy
+	avoid       scheduleOccupancy
+
+	meetings  []timetableMeeting
+	placed    map[int]timetablePlacement // By meeting index
+	byKey     map[occupancyKey][]int     // Placed meeting indexes by resource and day
+	pairDays  map[[2]uint]map[string]int // Meetings of a course and group per day
+	unplaced  []models.TimetableUnplaced
+	lastError map[int]string
+}
+
+func newTimetableSolver(days []string, earliest, latest int, rooms []models.Room, existing []scheduleSlot, windows []models.LecturerAvailability) *timetableSolver {
+	solver := &timetableSolver{
+		days:        days,
+		earliest:    earliest,
+		latest:      latest,
+		roomsByID:   make(map[uint]timetableRoom, len(rooms)),
+		existing:    newScheduleOccupancy(existing),
+		unavailable: lecturerWindows(windows, models.LecturerUnavailable),
+		avoid:       lecturerWindows(windows, models.LecturerAvoid),
+		placed:      make(map[int]timetablePlacement),
+		byKey:       make(map[occupancyKey][]int),
+		pairDays:    make(map[[2]uint]map[string]int),
+		lastError:   make(map[int]string),
+	}
+	for _, room := range rooms {
+		r := timetableRoom{id: room.ID, buildingID: room.BuildingID, capacity: room.Capacity}
+		solver.rooms = append(solver.rooms, r)
+		solver.roomsByID[room.ID] = r
+	}
+	sort.SliceStable(solver.rooms, func(i, j int) bool {
+		if solver.rooms[i].capacity != solver.rooms[j].capacity {
+			return solver.rooms[i].capacity < solver.rooms[j].capacity
+		}
+		return solver.rooms[i].id < solver.rooms[j].id
+	})
+	return solver
+}
+
+// timetableMeetings splits a course into weekly meetings of at most maxCreditsPerMeeting credits
+func timetableMeetings(courseID, groupID, lecturerID uint, credits, groupSize int) []timetableMeeting {
+	count := (credits + maxCreditsPerMeeting - 1) / maxCreditsPerMeeting
+	meetings := make([]timetableMeeting, 0, count)
+	for i := 0; i < count; i++ {
+		meetingCredits := credits / count
+		if i < credits%count {
+			meetingCredits++
+		}
+		meetings = append(meetings, timetableMeeting{
+			courseID:   courseID,
+			groupID:    groupID,
+			lecturerID: lecturerID,
+			minutes:    meetingCredits * minutesPerCredit,
+			groupSize:  groupSize,
+		})
+	}
+	return meetings
+}
+
+// solve places the meetings, the ones that cannot be placed end up in unplaced
+func (t *timetableSolver) solve(meetings []timetableMeeting) {
+	t.meetings = meetings
+
+	// The meetings with the fewest options are placed first, while they still have some
+	options := make([]int, len(meetings))
+	order := make([]int, len(meetings))
+	for i := range meetings {
+		options[i] = t.countOptions(i)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ma, mb := meetings[order[a]], meetings[order[b]]
+		if options[order[a]] != options[order[b]] {
+			return options[order[a]] < options[order[b]]
+		}
+		if ma.minutes != mb.minutes {
+			return ma.minutes > mb.minutes
+		}
+		return ma.groupSize > mb.groupSize
+	})
+
+	for _, i := range order {
+		if placement, _, ok := t.best(i); ok {
+			t.place(i, placement)
+		}
+	}
+
+	for pass := 0; pass < timetableImprovementPasses; pass++ {
+		improved := false
+		for _, i := range order {
+			current, ok := t.placed[i]
+			if !ok {
+				// Moves of other meetings may have freed a slot
+				if placement, _, ok := t.best(i); ok {
+					t.place(i, placement)
+					improved = true
+				}
+				continue
+			}
+			t.unplace(i)
+			currentCost := t.cost(i, current)
+			placement, cost, _ := t.best(i)
+			if cost < currentCost {
+				t.place(i, placement)
+				improved = true
+			} else {
+				t.place(i, current)
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	for _, i := range order {
+		if _, ok := t.placed[i]; ok {
+			continue
+		}
+		meeting := meetings[i]
+		t.unplaced = append(t.unplaced, models.TimetableUnplaced{
+			CourseID:       meeting.courseID,
+			StudentGroupID: meeting.groupID,
+			LecturerID:     meeting.lecturerID,
+			Minutes:        meeting.minutes,
+			Reason:         t.unplacedReason(meeting),
+		})
+	}
+}
+
+// candidates calls fn with every placement of a meeting that breaks no hard constraint
+func (t *timetableSolver) candidates(i int, fn func(timetablePlacement)) {
+	meeting := t.meetings[i]
+	pair := [2]uint{meeting.courseID, meeting.groupID}
+	for _, day := range t.days {
+		dayKey := strings.ToLower(day)
+		if t.pairDays[pair][dayKey] > 0 {
+			continue
+		}
+		for start := t.earliest; start+meeting.minutes <= t.latest; start += slotSearchStep {
+			end := start + meeting.minutes
+			if t.busy(models.ScheduleConflictLecturer, meeting.lecturerID, dayKey, start, end) ||
+				t.unavailable.busy(models.ScheduleConflictLecturer, meeting.lecturerID, dayKey, start, end) ||
+				t.busy(models.ScheduleConflictStudentGroup, meeting.groupID, dayKey, start, end) {
+				continue
+			}
+			for _, room := range t.rooms {
+				if room.capacity < meeting.groupSize || t.busy(models.ScheduleConflictRoom, room.id, dayKey, start, end) {
+					continue
+				}
+				fn(timetablePlacement{day: day, dayKey: dayKey, start: start, end: end, room: room})
+			}
+		}
+	}
+}
+
+// countOptions returns the number of placements of a meeting before anything is placed
+func (t *timetableSolver) countOptions(i int) int {
+	count := 0
+	t.candidates(i, func(timetablePlacement) { count++ })
+	return count
+}
+
+// best returns the cheapest placement of a meeting, ties go to the smallest fitting room, then day and time
+func (t *timetableSolver) best(i int) (timetablePlacement, int, bool) {
+	var best timetablePlacement
+	bestCost, found := 0, false
+	t.candidates(i, func(placement timetablePlacement) {
+		cost := t.cost(i, placement)
+		if !found || cost < bestCost {
+			best, bestCost, found = placement, cost, true
+		}
+	})
+	return best, bestCost, found
+}
+
+// busy reports whether a resource has a stored schedule or a placed meeting overlapping start to end
+func (t *timetableSolver) busy(conflictType models.ScheduleConflictType, id uint, day string, start, end int) bool {
+	if t.existing.busy(conflictType, id, day, start, end) {
+		return true
+	}
+	for _, j := range t.byKey[occupancyKey{conflictType, id, day}] {
+		placement := t.placed[j]
+		if placement.start < end && start < placement.end {
+			return true
+		}
+	}
+	return false
+}
+
+// cost returns the penalty a placement adds to the soft preferences, with the meeting not placed
+func (t *timetableSolver) cost(i int, placement timetablePlacement) int {
+	meeting := t.meetings[i]
+	added := timetableInterval{start: placement.start, end: placement.end, buildingID: placement.room.buildingID}
+
+	groupBefore := t.intervals(models.ScheduleConflictStudentGroup, meeting.groupID, placement.dayKey)
+	groupAfter := append(append([]timetableInterval{}, groupBefore...), added)
+	lecturerBefore := t.intervals(models.ScheduleConflictLecturer, meeting.lecturerID, placement.dayKey)
+	lecturerAfter := append(append([]timetableInterval{}, lecturerBefore...), added)
+
+	gaps := timetableGaps(groupAfter) - timetableGaps(groupBefore) + timetableGaps(lecturerAfter) - timetableGaps(lecturerBefore)
+	changes := timetableBuildingChanges(groupAfter) - timetableBuildingChanges(groupBefore)
+	avoided := t.avoidedMinutes(meeting.lecturerID, placement.dayKey, placement.start, placement.end)
+
+	return gaps*timetableGapWeight + avoided*timetableAvoidWeight + changes*timetableBuildingChangeWeight
+}
+
+// intervals returns the classes of a student group or lecturer on a day, stored and placed
+func (t *timetableSolver) intervals(conflictType models.ScheduleConflictType, id uint, day string) []timetableInterval {
+	var intervals []timetableInterval
+	for _, slot := range t.existing[occupancyKey{conflictType, id, day}] {
+		intervals = append(intervals, timetableInterval{
+			start:      slot.start,
+			end:        slot.end,
+			buildingID: t.roomsByID[slot.schedule.RoomID].buildingID,
+		})
+	}
+	for _, j := range t.byKey[occupancyKey{conflictType, id, day}] {
+		placement := t.placed[j]
+		intervals = append(intervals, timetableInterval{start: placement.start, end: placement.end, buildingID: placement.room.buildingID})
+	}
+	return intervals
+}
+
+// avoidedMinutes returns the minutes of start to end in windows the lecturer would rather avoid
+func (t *timetableSolver) avoidedMinutes(lecturerID uint, day string, start, end int) int {
+	minutes := 0
+	for _, window := range t.avoid[occupancyKey{models.ScheduleConflictLecturer, lecturerID, day}] {
+		if overlap := min(end, window.end) - max(start, window.start); overlap > 0 {
+			minutes += overlap
+		}
+	}
+	return minutes
+}
+
+func (t *timetableSolver) place(i int, placement timetablePlacement) {
+	meeting := t.meetings[i]
+	t.placed[i] = placement
+	for _, key := range t.meetingKeys(meeting, placement) {
+		t.byKey[key] = append(t.byKey[key], i)
+	}
+	pair := [2]uint{meeting.courseID, meeting.groupID}
+	if t.pairDays[pair] == nil {
+		t.pairDays[pair] = make(map[string]int)
+	}
+	t.pairDays[pair][placement.dayKey]++
+}
+
+func (t *timetableSolver) unplace(i int) {
+	meeting, placement := t.meetings[i], t.placed[i]
+	delete(t.placed, i)
+	for _, key := range t.meetingKeys(meeting, placement) {
+		indexes := t.byKey[key]
+		for n, j := range indexes {
+			if j == i {
+				t.byKey[key] = append(indexes[:n:n], indexes[n+1:]...)
+				break
+			}
+		}
+	}
+	t.pairDays[[2]uint{meeting.courseID, meeting.groupID}][placement.dayKey]--
+}
+
+// meetingKeys returns the room, lecturer and student group a placed meeting holds
+func (t *timetableSolver) meetingKeys(meeting timetableMeeting, placement timetablePlacement) []occupancyKey {
+	return []occupancyKey{
+		{models.ScheduleConflictRoom, placement.room.id, placement.dayKey},
+		{models.ScheduleConflictLecturer, meeting.lecturerID, placement.dayKey},
+		{models.ScheduleConflictStudentGroup, meeting.groupID, placement.dayKey},
+	}
+}
+
+// unplacedReason explains why a meeting has no placement
+func (t *timetableSolver) unplacedReason(meeting timetableMeeting) string {
+	if len(t.rooms) == 0 || t.rooms[len(t.rooms)-1].capacity < meeting.groupSize {
+		return fmt.Sprintf("no room seats the %d students of the group", meeting.groupSize)
+	}
+	if meeting.minutes > t.latest-t.earliest {
+		return fmt.Sprintf("the %d minute class does not fit in the daily window", meeting.minutes)
+	}
+	return "no slot where the lecturer, the student group and a large enough room are all free"
+}
+
+// totals returns the soft preference measures of the placed meetings: the gaps of the student
+// groups and lecturers that have one, including their stored schedules, the minutes in windows
+// lecturers would rather avoid, and the building changes of the student groups
+func (t *timetableSolver) totals() (gapMinutes, avoidedMinutes, buildingChanges int) {
+	seen := make(map[occupancyKey]bool)
+	for i, placement := range t.placed {
+		meeting := t.meetings[i]
+		avoidedMinutes += t.avoidedMinutes(meeting.lecturerID, placement.dayKey, placement.start, placement.end)
+
+		for _, key := range t.meetingKeys(meeting, placement)[1:] {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			intervals := t.intervals(key.conflictType, key.id, key.day)
+			gapMinutes += timetableGaps(intervals)
+			if key.conflictType == models.ScheduleConflictStudentGroup {
+				buildingChanges += timetableBuildingChanges(intervals)
+			}
+		}
+	}
+	return gapMinutes, avoidedMinutes, buildingChanges
+}
+
+// timetableGaps returns the idle minutes between the classes of a day
+func timetableGaps(intervals []timetableInterval) int {
+	sorted := sortedIntervals(intervals)
+	gaps := 0
+	for n := 1; n < len(sorted); n++ {
+		if gap := sorted[n].start - sorted[n-1].end; gap > 0 {
+			gaps += gap
+		}
+	}
+	return gaps
+}
+
+// timetableBuildingChanges returns how often consecutive classes of a day are in different buildings
+func timetableBuildingChanges(intervals []timetableInterval) int {
+	sorted := sortedIntervals(intervals)
+	changes := 0
+	for n := 1; n < len(sorted); n++ {
+		if sorted[n].buildingID != 0 && sorted[n-1].buildingID != 0 && sorted[n].buildingID != sorted[n-1].buildingID {
+			changes++
+		}
+	}
+	return changes
+}
+
+func sortedIntervals(intervals []timetableInterval) []timetableInterval {
+	sorted := append([]timetableInterval{}, intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start < sorted[j].start })
+	return sorted
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ICalEvent is one VEVENT of an iCalendar feed
+type ICalEvent struct {
+	UID          string // Stable across fetches, so calendar apps update the event instead of duplicating it
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Location     string
+	Description  string
+	Cancelled    bool
+	Sequence     int
+	LastModified time.Time
+}
+
+// ICalendar is an iCalendar (RFC 5545) document with a list of events
+type ICalendar struct {
+	Name            string
+	RefreshInterval time.Duration // How often calendar apps should fetch the feed again, 0 to leave it to them
+	Events          []ICalEvent
+}
+
+// icalTimeFormat is a UTC date-time, which needs no VTIMEZONE component
+const icalTimeFormat = "20060102T150405Z"
+
+// Encode writes the calendar with CRLF line endings and long lines folded at 75 octets
+func (c ICalendar) Encode(w io.Writer, stamp time.Time) error {
+	out := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeICalLine(out, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//DelPresence//Timetable//ID")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escapeICalText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		duration := "PT" + strconv.Itoa(int(c.RefreshInterval.Minutes())) + "M"
+		line("REFRESH-INTERVAL;VALUE=DURATION", duration)
+		line("X-PUBLISHED-TTL", duration)
+	}
+
+	for _, event := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escapeICalText(event.UID))
+		line("DTSTAMP", stamp.UTC().Format(icalTimeFormat))
+		line("DTSTART", event.Start.UTC().Format(icalTimeFormat))
+		line("DTEND", event.End.UTC().Format(icalTimeFormat))
+		line("SUMMARY", escapeICalText(event.Summary))
+		if event.Location != "" {
+			line("LOCATION", escapeICalText(event.Location))
+		}
+		if event.Description != "" {
+			line("DESCRIPTION", escapeICalText(event.Description))
+		}
+		if event.Cancelled {
+			line("STATUS", "CANCELLED")
+		} else {
+			line("STATUS", "CONFIRMED")
+		}
+		line("SEQUENCE", strconv.Itoa(event.Sequence))
+		if !event.LastModified.IsZero() {
+			line("LAST-MODIFIED", event.LastModified.UTC().Format(icalTimeFormat))
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return out.Flush()
+}
+
+// escapeICalText escapes a TEXT value
+func escapeICalText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(value)
+}
+
+// writeICalLine writes a content line, folding it into lines of at most 75 octets without splitting characters
+func writeICalLine(w *bufio.Writer, content string) {
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.WriteString(content[:cut])
+		w.WriteString("\r\n ")
+		content = content[cut:]
+		limit = 74 // Continuation lines start with a space
+	}
+	w.WriteString(content)
+	w.WriteString("\r\n")
+}